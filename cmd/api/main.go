@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
@@ -11,9 +12,13 @@ import (
 
 	"github.com/joho/godotenv"
 
+	"tricking-api/internal/cache"
+	"tricking-api/internal/changelog"
 	"tricking-api/internal/config"
 	"tricking-api/internal/database"
 	"tricking-api/internal/handlers"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/middleware"
 	"tricking-api/internal/repository"
 	"tricking-api/internal/routes"
 	"tricking-api/internal/services"
@@ -31,6 +36,19 @@ func main() {
 		// log.Fatalf prints the error and exits the program with status code 1
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+
+	// Validate the embedded changelog up front - a malformed file should never ship
+	apiChangelog, err := changelog.Load()
+	if err != nil {
+		log.Fatalf("Failed to load changelog: %v", err)
+	}
+
+	// Same for the error message catalogs - every error code needs an English message
+	if err := messages.Load(); err != nil {
+		log.Fatalf("Failed to load error messages: %v", err)
+	}
+	messages.SetVersion(apiChangelog.CurrentVersion())
+
 	// STEP 2: Initialize Database Connection Pool
 	dbPool, err := database.NewPool(context.Background(), cfg.DatabaseURL)
 	if err != nil {
@@ -44,35 +62,126 @@ func main() {
 	trickRepo := repository.NewTrickRepository(dbPool)
 	videoRepo := repository.NewVideoRepository(dbPool)
 	categoryRepo := repository.NewCategoryRepository(dbPool)
+	flipRepo := repository.NewFlipRepository(dbPool)
+	translationRepo := repository.NewTranslationRepository(dbPool)
 	userRepo := repository.NewUserRepository(dbPool)
-	//comboRepo := repository.NewComboRepository(dbPool)
+	catalogRepo := repository.NewCatalogRepository(dbPool)
+	comboRepo := repository.NewComboRepository(dbPool)
+	stanceRepo := repository.NewStanceRepository(dbPool)
+	schemaRepo := repository.NewSchemaRepository(dbPool)
+	mistakeRepo := repository.NewMistakeRepository(dbPool)
+	prereqRepo := repository.NewPrerequisiteRepository(dbPool)
+	aliasRepo := repository.NewAliasRepository(dbPool)
+	tagRepo := repository.NewTagRepository(dbPool)
+	apiKeyRepo := repository.NewAPIKeyRepository(dbPool)
+
+	// Verify required indexes before serving - missing ones mean table scans, not errors,
+	// so we only warn (and flag /health/ready) unless strict mode is on in production
+	selfCheck := services.NewSelfCheckService(schemaRepo, database.RequiredIndexes)
+	if report := selfCheck.Run(context.Background()); report.Degraded && cfg.StrictSchemaCheck && cfg.IsProduction() {
+		log.Fatalf("Refusing to start: schema self-check failed and STRICT_SCHEMA_CHECK=true (see warnings above)")
+	}
+
+	// Fuzzy search needs pg_trgm - without it search falls back to substring/full-text matching
+	searchSimilarity := cfg.SearchSimilarityThreshold
+	if installed, err := schemaRepo.HasExtension(context.Background(), "pg_trgm"); err != nil {
+		log.Printf("Warning: could not check for pg_trgm, fuzzy search disabled: %v", err)
+		searchSimilarity = 0
+	} else if !installed {
+		log.Printf("Warning: pg_trgm is not installed, fuzzy search disabled - enable it with: CREATE EXTENSION pg_trgm;")
+		searchSimilarity = 0
+	}
 
 	// Create services (business logic layer)
 	// Services receive repositories as dependencies
-	trickService := services.NewTrickService(trickRepo, videoRepo)
-	comboService := services.NewComboService(trickRepo)
-	categoryService := services.NewCategoryService(categoryRepo)
-	userService := services.NewUserService(userRepo)
+	viewCounter := services.NewViewCounter(trickRepo)
+	// Shared by the trick service (reads) and every service that writes trick/video data
+	dictionaryCache := services.NewDictionaryCache(cache.NewMemory(), cfg.DictionaryCacheTTL)
+	// Named difficulty ranges, validated at config load (DIFFICULTY_BANDS)
+	bands := services.NewDifficultyBands(cfg.DifficultyBands)
+	trickService := services.NewTrickService(trickRepo, videoRepo, mistakeRepo, prereqRepo, aliasRepo, tagRepo, translationRepo, categoryRepo, viewCounter, dictionaryCache, cfg.NewTrickDays, bands, searchSimilarity, cfg.TrickPurgeAfter, cfg.TrickGraphMaxNodes, cfg.CategorySuggestions)
+	comboService := services.NewComboService(trickRepo, stanceRepo, bands)
+	categoryService := services.NewCategoryService(categoryRepo, trickRepo)
+	flipService := services.NewFlipService(flipRepo)
+	stanceService := services.NewStanceService(stanceRepo)
+	userService := services.NewUserService(userRepo, comboRepo, cfg.ComboLimits)
+	// Plain http URLs are only accepted outside production
+	adminService := services.NewAdminService(trickRepo, videoRepo, catalogRepo, aliasRepo, translationRepo, categoryRepo, !cfg.IsProduction(), cfg.CategorySuggestions, cfg.TrickPurgeAfter, dictionaryCache)
+	moderationService := services.NewModerationService(mistakeRepo, prereqRepo, tagRepo, dictionaryCache)
+	catalogConsistency := services.NewCatalogConsistency(trickRepo, dictionaryCache)
+	publicLinkService := services.NewPublicLinkService(trickRepo, videoRepo, cfg.PublicLinkSecret)
+	// Key lookups are cached briefly (see services.APIKeyService); tiers come from RATE_LIMIT_API_KEY_*
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, cache.NewMemory(), cfg.APIKeyRateLimits)
+	trickPurger := services.NewTrickPurger(trickRepo)
+	weightDecayer := services.NewTrickWeightDecayer(trickRepo, cfg.WeightDecay)
+	// The client timeout caps background checks; forced checks use a shorter request deadline
+	// Describes this instance - logged once below and served at /admin/runtime
+	runtimeInfo := services.NewRuntimeInfoService(cfg, apiChangelog.CurrentVersion(), database.Describe(dbPool), dictionaryCache)
+	videoAvailability := services.NewVideoAvailabilityService(videoRepo,
+		services.NewHTTPAvailabilityChecker(&http.Client{Timeout: 15 * time.Second}), dictionaryCache)
 	// Create handlers (HTTP layer)
 	// Handlers receive services as dependencies
-	trickHandler := handlers.NewTrickHandler(trickService)
-	comboHandler := handlers.NewComboHandler(comboService)
-	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	trickHandler := handlers.NewTrickHandler(trickService, stanceService, bands)
+	comboHandler := handlers.NewComboHandler(comboService, bands)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, bands)
+	flipHandler := handlers.NewFlipHandler(flipService)
+	stanceHandler := handlers.NewStanceHandler(stanceService)
 	userHandler := handlers.NewUserHandler(userService)
+	changelogHandler := handlers.NewChangelogHandler(apiChangelog)
+	adminHandler := handlers.NewAdminHandler(adminService, videoAvailability, selfCheck, catalogConsistency, runtimeInfo)
+	// Flipped by SIGTERM (below) or POST /admin/drain - fails /health/ready
+	drain := services.NewDrainService()
+	healthHandler := handlers.NewHealthHandler(selfCheck, drain)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+	publicLinkHandler := handlers.NewPublicLinkHandler(publicLinkService)
+	metaHandler := handlers.NewMetaHandler(bands)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	apiKeys := middleware.NewAPIKeys(apiKeyService, cfg.APIKeyRateLimits)
+
+	// One structured line so logs show exactly which build and config started
+	if startup, err := json.Marshal(runtimeInfo.Info()); err == nil {
+		log.Printf("Startup: %s", startup)
+	}
 
 	// STEP 4: Setup Router and Routes
-	router := routes.NewRouter(cfg, trickHandler, comboHandler, categoryHandler, userHandler)
+	router := routes.NewRouter(cfg, trickHandler, comboHandler, categoryHandler, stanceHandler, flipHandler, userHandler, changelogHandler, adminHandler, healthHandler, moderationHandler, publicLinkHandler, metaHandler, apiKeyHandler, apiKeys, apiChangelog.CurrentVersion())
 
 	// STEP 5: Create HTTP Server
 	srv := &http.Server{
-		Addr:    ":" + cfg.Port, // e.g., ":8080"
-		Handler: router,         // Our Gin router handles all requests
+		Addr: ":" + cfg.Port, // e.g., ":8080"
+		// Our Gin router handles all requests ("/tricks/" is served as "/tricks")
+		Handler: middleware.StripTrailingSlash(router),
 		// Timeouts prevent slow clients from holding connections indefinitely
+		// (WriteTimeout must outlast the longest per-route timeout, so the 504 gets out)
 		ReadTimeout:  15 * time.Second, // Max time to read request
-		WriteTimeout: 15 * time.Second, // Max time to write response
+		WriteTimeout: 65 * time.Second, // Max time to write response
 		IdleTimeout:  60 * time.Second, // Max time for keep-alive connections
 	}
 
+	// Flush trick views to the database in the background
+	// Cancelling viewCtx on shutdown triggers one final flush
+	viewCtx, stopViews := context.WithCancel(context.Background())
+	viewsDone := make(chan struct{})
+	go func() {
+		viewCounter.Run(viewCtx, 30*time.Second)
+		close(viewsDone)
+	}()
+
+	// Periodic maintenance jobs - they just stop on shutdown, nothing to flush
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+
+	// Permanently remove deleted tricks once their restore window has passed
+	go trickPurger.Run(jobsCtx, time.Hour)
+
+	// Re-check external video links so dead ones stop being featured
+	go videoAvailability.Run(jobsCtx, time.Hour)
+
+	// Report tricks whose stance/flip references point at nothing (clearing them is manual)
+	go catalogConsistency.Run(jobsCtx, 24*time.Hour)
+
+	// Suggest stale, video-less tricks less often (staleness is measured in days, so hourly is plenty)
+	go weightDecayer.Run(jobsCtx, time.Hour)
+
 	go func() {
 		log.Printf("Server starting on port %s", cfg.Port)
 		// ListenAndServe blocks until the server stops
@@ -86,16 +195,16 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	// SIGINT = Ctrl+C, SIGTERM = kill command or container orchestrator
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit // Block until we receive a signal
-
-	log.Println("Shutting down server...")
+	sig := <-quit // Block until we receive a signal
 
-	// Create a deadline for shutdown - give requests 30 seconds to complete
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	// Attempt graceful shutdown
-	if err := srv.Shutdown(ctx); err != nil {
+	// Fail readiness, wait out the drain delay, then stop the server (in-flight
+	// requests get 30 seconds) and the jobs, flushing the last batch of views
+	err = drain.Shutdown(srv, sig.String(), cfg.ShutdownDrainDelay, 30*time.Second, quit, func() {
+		stopJobs()
+		stopViews()
+		<-viewsDone
+	})
+	if err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
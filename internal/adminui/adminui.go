@@ -0,0 +1,36 @@
+// =============================================================================
+// FILE: internal/adminui/adminui.go
+// PURPOSE: Serves a tiny catalog-browsing page for local development
+// =============================================================================
+//
+// The page lives in index.html next to this file and is embedded at build time.
+// It is plain HTML + fetch() calls against the public JSON endpoints (same
+// origin, so no CORS needed) - there is nothing to build or install.
+//
+// DEV ONLY: routes.NewRouter only registers this outside production.
+// =============================================================================
+
+package adminui
+
+import (
+	"embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed index.html
+var files embed.FS
+
+// Handler serves the admin UI page
+func Handler() gin.HandlerFunc {
+	page, err := files.ReadFile("index.html")
+	if err != nil {
+		// Can't happen - the file is embedded at compile time
+		panic(err)
+	}
+
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
@@ -0,0 +1,102 @@
+// =============================================================================
+// FILE: internal/cache/cache.go
+// PURPOSE: Small key/value cache with per-entry TTL and prefix invalidation
+// =============================================================================
+//
+// Services talk to the Cache interface, so the in-memory store can be swapped
+// for a shared one (e.g. Redis) without touching them. Keys are namespaced
+// strings like "dictionary:backflip:...", which is what makes DeletePrefix
+// useful: invalidating one trick drops only that trick's entries.
+// =============================================================================
+
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is the contract every cache backend implements
+type Cache interface {
+	// Get returns the value for key, or false if it is missing or expired
+	Get(key string) (any, bool)
+
+	// Set stores value under key for ttl
+	Set(key string, value any, ttl time.Duration)
+
+	// DeletePrefix removes every key starting with prefix and returns how many it removed
+	DeletePrefix(prefix string) int
+
+	// Len returns how many entries are stored (expired ones may not be swept yet)
+	Len() int
+}
+
+// entry is one cached value and when it stops being valid
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// Memory is an in-process Cache
+// Expired entries are dropped when they are next read or when a prefix is deleted.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// NewMemory creates an empty in-memory cache
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]entry)}
+}
+
+// Get returns the value for key, or false if it is missing or expired
+func (m *Memory) Get(key string) (any, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key for ttl
+func (m *Memory) Set(key string, value any, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = entry{value: value, expiresAt: time.Now().Add(ttl)}
+}
+
+// DeletePrefix removes every key starting with prefix
+// Also sweeps expired entries it passes, so the map doesn't grow without bound
+func (m *Memory) DeletePrefix(prefix string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for key, e := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.entries, key)
+			removed++
+		} else if now.After(e.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	return removed
+}
+
+// Len returns how many entries are stored, including expired ones not yet swept
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
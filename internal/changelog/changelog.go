@@ -0,0 +1,107 @@
+// =============================================================================
+// FILE: internal/changelog/changelog.go
+// PURPOSE: Loads the API changelog that is compiled into the binary
+// =============================================================================
+//
+// The changelog lives in changelog.json next to this file and is embedded at
+// build time, so every deploy serves exactly the changelog it was built with.
+// Newest release goes FIRST in the file.
+// =============================================================================
+
+package changelog
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+//go:embed changelog.json
+var rawChangelog []byte
+
+// Entry types allowed in the changelog
+const (
+	EntryAdded   = "added"
+	EntryChanged = "changed"
+	EntryFixed   = "fixed"
+)
+
+// Entry is a single line item in a release
+type Entry struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// Release is one deployed API version and what changed in it
+type Release struct {
+	Version string  `json:"version"`
+	Date    string  `json:"date"` // YYYY-MM-DD
+	Entries []Entry `json:"entries"`
+}
+
+// Changelog is the parsed, validated changelog
+type Changelog struct {
+	Releases []Release `json:"releases"`
+
+	// ETag is a hash of the embedded file - it only changes when the file does
+	ETag string `json:"-"`
+}
+
+// Load parses and validates the embedded changelog
+// Called at startup so a malformed file stops the server from booting
+func Load() (*Changelog, error) {
+	return parse(rawChangelog)
+}
+
+// CurrentVersion returns the version of the newest release
+func (c *Changelog) CurrentVersion() string {
+	return c.Releases[0].Version
+}
+
+// parse decodes and validates changelog JSON
+func parse(data []byte) (*Changelog, error) {
+	var cl Changelog
+	if err := json.Unmarshal(data, &cl); err != nil {
+		return nil, fmt.Errorf("failed to parse changelog: %w", err)
+	}
+
+	if len(cl.Releases) == 0 {
+		return nil, fmt.Errorf("changelog has no releases")
+	}
+
+	seen := make(map[string]bool, len(cl.Releases))
+	for i, release := range cl.Releases {
+		if release.Version == "" {
+			return nil, fmt.Errorf("changelog release %d is missing a version", i)
+		}
+		if seen[release.Version] {
+			return nil, fmt.Errorf("changelog release %s is listed more than once", release.Version)
+		}
+		seen[release.Version] = true
+
+		if _, err := time.Parse("2006-01-02", release.Date); err != nil {
+			return nil, fmt.Errorf("changelog release %s has invalid date %q", release.Version, release.Date)
+		}
+		if len(release.Entries) == 0 {
+			return nil, fmt.Errorf("changelog release %s has no entries", release.Version)
+		}
+
+		for j, entry := range release.Entries {
+			switch entry.Type {
+			case EntryAdded, EntryChanged, EntryFixed:
+			default:
+				return nil, fmt.Errorf("changelog release %s entry %d has invalid type %q", release.Version, j, entry.Type)
+			}
+			if entry.Description == "" {
+				return nil, fmt.Errorf("changelog release %s entry %d is missing a description", release.Version, j)
+			}
+		}
+	}
+
+	// Quoted strong ETag built from the file contents
+	cl.ETag = fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+
+	return &cl, nil
+}
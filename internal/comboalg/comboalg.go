@@ -0,0 +1,406 @@
+// =============================================================================
+// FILE: internal/comboalg/comboalg.go
+// PURPOSE: Pure combo selection and scoring algorithms, shared by the API and the BFF preview
+// =============================================================================
+//
+// Everything here is a function of the candidate tricks, an Options value and
+// a rand.Source - no repositories, no context, no clock. Seed the source the
+// same way and you get the same combo, which is what lets the BFF preview a
+// selection offline and have the server confirm it.
+//
+// services.ComboService fetches candidates and stances, then calls Select.
+// ScoreCombo grades a combo the user put together (POST /combos/estimate).
+// FormatNotation (notation.go) writes a combo out as text.
+// =============================================================================
+
+package comboalg
+
+import (
+	"math"
+	"math/rand"
+
+	"tricking-api/internal/models"
+)
+
+// Strategy names a selection algorithm
+type Strategy string
+
+const (
+	// StrategyWeighted draws tricks at random, biased by SelectionWeight
+	StrategyWeighted Strategy = "weighted"
+
+	// StrategyFlow prefers tricks whose takeoff stance matches the previous landing
+	StrategyFlow Strategy = "flow"
+
+	// StrategyBalanced is weighted with the balance_legs rule (see MaxSameLegLandings)
+	StrategyBalanced Strategy = "balanced"
+)
+
+// MaxSameLegLandings is how many tricks in a row may land on the same single leg
+// under StrategyBalanced
+const MaxSameLegLandings = 2
+
+// Options configures a selection
+type Options struct {
+	// Count is how many tricks to select - fewer come back if there aren't enough candidates
+	Count int
+
+	// Strategy defaults to StrategyWeighted when empty
+	Strategy Strategy
+
+	// Legs maps a landing stance ID to its leg (models.LegLeft etc.)
+	// Only StrategyBalanced reads it; unknown stances count as both legs.
+	Legs map[int]string
+}
+
+// Selection is the outcome of Select
+type Selection struct {
+	Tricks []models.Trick
+
+	// RelaxedPositions are 1-indexed positions where StrategyBalanced had to
+	// break the leg rule because no other candidate was left
+	RelaxedPositions []int
+}
+
+// Select picks opts.Count distinct tricks from candidates
+// candidates is not modified. Each trick is picked at most once.
+func Select(candidates []models.Trick, opts Options, src rand.Source) Selection {
+	rng := rand.New(src)
+
+	switch opts.Strategy {
+	case StrategyFlow:
+		return Selection{Tricks: selectWithFlow(rng, candidates, opts.Count)}
+	case StrategyBalanced:
+		tricks, relaxed := selectBalanced(rng, candidates, opts.Count, opts.Legs)
+		return Selection{Tricks: tricks, RelaxedPositions: relaxed}
+	default:
+		return Selection{Tricks: selectWeighted(rng, candidates, opts.Count)}
+	}
+}
+
+// Pick draws a single trick, weighted like Select
+// candidates must not be empty.
+func Pick(candidates []models.Trick, src rand.Source) models.Trick {
+	return pickWeighted(rand.New(src), candidates)
+}
+
+// selectionWeightScale turns fractional effective weights into integer shares
+// (a decayed trick with weight 1 and modifier 0.5 gets 50 vs. an undecayed trick's 100)
+const selectionWeightScale = 100
+
+// SelectionWeight is a trick's share of the random draw: its curated weight
+// (at least 1, so no trick is impossible to select) times its decay modifier
+func SelectionWeight(t models.Trick) int64 {
+	weight := float64(t.Weight)
+	if weight < 1 {
+		weight = 1
+	}
+
+	// Tricks loaded without weight_modifier have the zero value - treat as undecayed
+	modifier := t.WeightModifier
+	if modifier <= 0 {
+		modifier = 1
+	}
+
+	share := int64(math.Round(weight * modifier * selectionWeightScale))
+	if share < 1 {
+		share = 1
+	}
+	return share
+}
+
+// =============================================================================
+// ALGORITHMS
+// =============================================================================
+
+// selectWeighted selects n tricks using weighted random selection
+// Tricks with higher weight are more likely to be selected
+func selectWeighted(rng *rand.Rand, candidates []models.Trick, count int) []models.Trick {
+	// Make a copy to avoid modifying the original slice
+	available := make([]models.Trick, len(candidates))
+	copy(available, candidates)
+
+	selected := make([]models.Trick, 0, max(count, 0))
+
+	for i := 0; i < count && len(available) > 0; i++ {
+		// Calculate total weight
+		totalWeight := int64(0)
+		for _, trick := range available {
+			totalWeight += SelectionWeight(trick)
+		}
+
+		// Pick random point in weight space
+		target := rng.Int63n(totalWeight)
+
+		// Find the trick at that point
+		cumulative := int64(0)
+		selectedIdx := 0
+		for idx, trick := range available {
+			cumulative += SelectionWeight(trick)
+			if cumulative > target {
+				selectedIdx = idx
+				break
+			}
+		}
+
+		// Add to selected and remove from available
+		selected = append(selected, available[selectedIdx])
+		// Remove by swapping with last element and shrinking slice
+		available[selectedIdx] = available[len(available)-1]
+		available = available[:len(available)-1]
+	}
+
+	return selected
+}
+
+// selectBalanced is selectWeighted with the balance_legs constraint:
+// no more than MaxSameLegLandings consecutive tricks land on the same single leg.
+// Tricks landing on both legs (or with unknown stance) reset the streak.
+// If every remaining trick would break the rule, the rule is relaxed for that
+// position and the 1-indexed position is returned so the client can flag it.
+func selectBalanced(rng *rand.Rand, candidates []models.Trick, count int, legs map[int]string) ([]models.Trick, []int) {
+	available := make([]models.Trick, len(candidates))
+	copy(available, candidates)
+
+	selected := make([]models.Trick, 0, max(count, 0))
+	var relaxed []int
+
+	for i := 0; i < count && len(available) > 0; i++ {
+		pool := available
+		if blocked := blockedLeg(selected, legs); blocked != "" {
+			allowed := make([]models.Trick, 0, len(available))
+			for _, trick := range available {
+				if landingLeg(trick, legs) != blocked {
+					allowed = append(allowed, trick)
+				}
+			}
+			if len(allowed) > 0 {
+				pool = allowed
+			} else {
+				// Nothing else fits - keep the combo full rather than failing
+				relaxed = append(relaxed, i+1)
+			}
+		}
+
+		next := pickWeighted(rng, pool)
+		selected = append(selected, next)
+		available = removeTrick(available, next.ID)
+	}
+
+	return selected, relaxed
+}
+
+// selectWithFlow considers stance compatibility for smoother combos
+// This is more complex but creates more realistic combos
+func selectWithFlow(rng *rand.Rand, candidates []models.Trick, count int) []models.Trick {
+	if len(candidates) == 0 || count <= 0 {
+		return []models.Trick{}
+	}
+
+	selected := make([]models.Trick, 0, count)
+	available := make([]models.Trick, len(candidates))
+	copy(available, candidates)
+
+	// Pick first trick randomly (weighted)
+	first := pickWeighted(rng, available)
+	selected = append(selected, first)
+	available = removeTrick(available, first.ID)
+
+	// For subsequent tricks, prefer those where takeoff_stance matches previous landing_stance
+	for i := 1; i < count && len(available) > 0; i++ {
+		lastTrick := selected[i-1]
+
+		// Find tricks with compatible stances
+		compatible := filterCompatibleTricks(available, lastTrick.LandingStanceID)
+
+		var nextTrick models.Trick
+		if len(compatible) > 0 {
+			// Pick from compatible tricks
+			nextTrick = pickWeighted(rng, compatible)
+		} else {
+			// Fallback to any trick if no compatible ones
+			nextTrick = pickWeighted(rng, available)
+		}
+
+		selected = append(selected, nextTrick)
+		available = removeTrick(available, nextTrick.ID)
+	}
+
+	return selected
+}
+
+// =============================================================================
+// HELPERS
+// =============================================================================
+
+// pickWeighted picks a single trick using weighted random selection
+func pickWeighted(rng *rand.Rand, tricks []models.Trick) models.Trick {
+	if len(tricks) == 1 {
+		return tricks[0]
+	}
+
+	totalWeight := int64(0)
+	for _, t := range tricks {
+		totalWeight += SelectionWeight(t)
+	}
+
+	target := rng.Int63n(totalWeight)
+	cumulative := int64(0)
+
+	for _, t := range tricks {
+		cumulative += SelectionWeight(t)
+		if cumulative > target {
+			return t
+		}
+	}
+
+	return tricks[len(tricks)-1] // Fallback
+}
+
+// blockedLeg returns the leg the next trick must not land on, or "" if any is fine
+// A leg is blocked once the last MaxSameLegLandings tricks all landed on it
+func blockedLeg(selected []models.Trick, legs map[int]string) string {
+	if len(selected) < MaxSameLegLandings {
+		return ""
+	}
+
+	leg := landingLeg(selected[len(selected)-1], legs)
+	if leg != models.LegLeft && leg != models.LegRight {
+		return ""
+	}
+	for _, trick := range selected[len(selected)-MaxSameLegLandings:] {
+		if landingLeg(trick, legs) != leg {
+			return ""
+		}
+	}
+	return leg
+}
+
+// landingLeg returns the leg a trick lands on, treating unknown stances as both legs
+func landingLeg(trick models.Trick, legs map[int]string) string {
+	if trick.LandingStanceID == nil {
+		return models.LegBoth
+	}
+	if leg, ok := legs[*trick.LandingStanceID]; ok {
+		return leg
+	}
+	return models.LegBoth
+}
+
+// filterCompatibleTricks returns tricks where takeoff matches the given landing stance
+func filterCompatibleTricks(tricks []models.Trick, landingStanceID *int) []models.Trick {
+	if landingStanceID == nil {
+		return tricks // No landing stance = any trick works
+	}
+
+	compatible := make([]models.Trick, 0)
+	for _, t := range tricks {
+		// Trick is compatible if it has no takeoff requirement OR matches
+		if t.TakeoffStanceID == nil || *t.TakeoffStanceID == *landingStanceID {
+			compatible = append(compatible, t)
+		}
+	}
+	return compatible
+}
+
+// removeTrick returns tricks without the trick with the given ID
+// It builds a new slice: the caller's candidates share the backing array
+func removeTrick(tricks []models.Trick, id string) []models.Trick {
+	remaining := make([]models.Trick, 0, len(tricks))
+	for _, t := range tricks {
+		if t.ID != id {
+			remaining = append(remaining, t)
+		}
+	}
+	return remaining
+}
+
+// =============================================================================
+// SCORING
+// =============================================================================
+
+// Transition compatibilities between two consecutive tricks
+const (
+	TransitionMatch    = "match"    // The next trick takes off from the stance the previous one landed in
+	TransitionMismatch = "mismatch" // The stances differ - the combo needs a reset step there
+	TransitionUnknown  = "unknown"  // One of the two stances isn't recorded
+)
+
+// gradeSteps are the combo grades, best first
+var gradeSteps = []string{"S", "A", "B", "C", "D"}
+
+// gradeMinAverage is the minimum average difficulty for each grade but the last
+var gradeMinAverage = []float64{8, 6, 4, 2}
+
+// Transition is the stance check between two consecutive tricks of a combo
+type Transition struct {
+	From          string
+	To            string
+	Compatibility string
+}
+
+// Score is the outcome of ScoreCombo
+type Score struct {
+	// TotalDifficulty sums the tricks' difficulties (tricks without one add nothing)
+	TotalDifficulty int64
+
+	// Grade is "S" (best) to "D"
+	Grade string
+
+	// Transitions has one entry per consecutive pair, in combo order
+	Transitions []Transition
+}
+
+// ScoreCombo grades tricks performed in the given order
+// The grade starts from the average difficulty of the tricks that have one
+// (8+ S, 6+ A, 4+ B, 2+ C, else D) and drops one step per stance mismatch, so
+// reordering a combo changes its grade only through its transitions.
+func ScoreCombo(tricks []models.Trick) Score {
+	score := Score{Transitions: make([]Transition, 0, max(len(tricks)-1, 0))}
+
+	rated := 0
+	for _, trick := range tricks {
+		if trick.Difficulty != nil {
+			score.TotalDifficulty += *trick.Difficulty
+			rated++
+		}
+	}
+
+	step := len(gradeMinAverage)
+	if rated > 0 {
+		average := float64(score.TotalDifficulty) / float64(rated)
+		for i, minAverage := range gradeMinAverage {
+			if average >= minAverage {
+				step = i
+				break
+			}
+		}
+	}
+
+	for i := 1; i < len(tricks); i++ {
+		compatibility := transitionCompatibility(tricks[i-1], tricks[i])
+		if compatibility == TransitionMismatch {
+			step++
+		}
+		score.Transitions = append(score.Transitions, Transition{
+			From:          tricks[i-1].ID,
+			To:            tricks[i].ID,
+			Compatibility: compatibility,
+		})
+	}
+
+	score.Grade = gradeSteps[min(step, len(gradeSteps)-1)]
+	return score
+}
+
+// transitionCompatibility compares one trick's landing stance with the next one's takeoff
+func transitionCompatibility(from, to models.Trick) string {
+	switch {
+	case from.LandingStanceID == nil || to.TakeoffStanceID == nil:
+		return TransitionUnknown
+	case *from.LandingStanceID == *to.TakeoffStanceID:
+		return TransitionMatch
+	default:
+		return TransitionMismatch
+	}
+}
@@ -0,0 +1,433 @@
+package comboalg
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+var strategies = []Strategy{StrategyWeighted, StrategyFlow, StrategyBalanced}
+
+// ids returns the IDs of tricks, in order
+func ids(tricks []models.Trick) []string {
+	out := make([]string, len(tricks))
+	for i, trick := range tricks {
+		out[i] = trick.ID
+	}
+	return out
+}
+
+// without returns candidates minus the tricks in picked
+func without(candidates, picked []models.Trick) []models.Trick {
+	gone := map[string]bool{}
+	for _, trick := range picked {
+		gone[trick.ID] = true
+	}
+	var rest []models.Trick
+	for _, trick := range candidates {
+		if !gone[trick.ID] {
+			rest = append(rest, trick)
+		}
+	}
+	return rest
+}
+
+func TestSelectIsDeterministic(t *testing.T) {
+	for _, strategy := range strategies {
+		t.Run(string(strategy), func(t *testing.T) {
+			opts := Options{Count: 6, Strategy: strategy, Legs: fixtures.CatalogLegs()}
+			for seed := int64(0); seed < 20; seed++ {
+				a := Select(fixtures.CatalogTricks(), opts, rand.NewSource(seed))
+				b := Select(fixtures.CatalogTricks(), opts, rand.NewSource(seed))
+				if !reflect.DeepEqual(a, b) {
+					t.Fatalf("seed %d: %v != %v", seed, ids(a.Tricks), ids(b.Tricks))
+				}
+			}
+		})
+	}
+}
+
+func TestSelectCountAndDistinct(t *testing.T) {
+	catalogSize := len(fixtures.CatalogTricks())
+
+	tests := []struct {
+		name      string
+		count     int
+		candidate int // how many catalog tricks are offered
+		want      int
+	}{
+		{name: "some", count: 5, candidate: catalogSize, want: 5},
+		{name: "all", count: catalogSize, candidate: catalogSize, want: catalogSize},
+		{name: "more than offered", count: 10, candidate: 4, want: 4},
+		{name: "one", count: 1, candidate: catalogSize, want: 1},
+		{name: "zero", count: 0, candidate: catalogSize, want: 0},
+		{name: "negative", count: -3, candidate: catalogSize, want: 0},
+		{name: "no candidates", count: 3, candidate: 0, want: 0},
+	}
+
+	for _, strategy := range strategies {
+		for _, tt := range tests {
+			t.Run(string(strategy)+"/"+tt.name, func(t *testing.T) {
+				candidates := fixtures.CatalogTricks()[:tt.candidate]
+				before := ids(candidates)
+
+				selection := Select(candidates, Options{Count: tt.count, Strategy: strategy, Legs: fixtures.CatalogLegs()}, rand.NewSource(7))
+
+				if len(selection.Tricks) != tt.want {
+					t.Fatalf("selected %d tricks, want %d", len(selection.Tricks), tt.want)
+				}
+				seen := map[string]bool{}
+				for _, trick := range selection.Tricks {
+					if seen[trick.ID] {
+						t.Errorf("trick %s selected twice", trick.ID)
+					}
+					seen[trick.ID] = true
+				}
+				if !reflect.DeepEqual(ids(candidates), before) {
+					t.Errorf("Select modified its candidates")
+				}
+			})
+		}
+	}
+}
+
+func TestSelectionWeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		weight   int16
+		modifier float64
+		want     int64
+	}{
+		{name: "undecayed", weight: 5, modifier: 1, want: 500},
+		{name: "decayed", weight: 4, modifier: 0.5, want: 200},
+		{name: "zero weight counts as one", weight: 0, modifier: 1, want: 100},
+		{name: "negative weight counts as one", weight: -3, modifier: 1, want: 100},
+		{name: "missing modifier is undecayed", weight: 2, modifier: 0, want: 200},
+		{name: "tiny share is never zero", weight: 1, modifier: 0.001, want: 1},
+		{name: "rounds", weight: 1, modifier: 0.333, want: 33},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trick := models.Trick{Weight: tt.weight, WeightModifier: tt.modifier}
+			if got := SelectionWeight(trick); got != tt.want {
+				t.Errorf("SelectionWeight() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectWeightedFollowsWeights(t *testing.T) {
+	heavy := fixtures.Trick().WithSlug("heavy").WithWeight(9, 1).Build()
+	light := fixtures.Trick().WithSlug("light").WithWeight(1, 1).Build()
+	decayed := fixtures.Trick().WithSlug("decayed").WithWeight(9, 1.0/9).Build()
+
+	tests := []struct {
+		name      string
+		a, b      models.Trick
+		wantShare float64 // expected share of draws won by a
+	}{
+		{name: "heavy vs light", a: heavy, b: light, wantShare: 0.9},
+		{name: "decay evens the odds", a: decayed, b: light, wantShare: 0.5},
+	}
+
+	const draws = 20000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := rand.NewSource(42)
+			wins := 0
+			for i := 0; i < draws; i++ {
+				if Select([]models.Trick{tt.a, tt.b}, Options{Count: 1}, src).Tricks[0].ID == tt.a.ID {
+					wins++
+				}
+			}
+			if share := float64(wins) / draws; share < tt.wantShare-0.02 || share > tt.wantShare+0.02 {
+				t.Errorf("%s won %.3f of draws, want about %.2f", tt.a.ID, share, tt.wantShare)
+			}
+		})
+	}
+}
+
+func TestSelectWithFlowPrefersMatchingStances(t *testing.T) {
+	catalog := fixtures.CatalogTricks()
+
+	for seed := int64(0); seed < 200; seed++ {
+		selection := Select(catalog, Options{Count: 8, Strategy: StrategyFlow}, rand.NewSource(seed))
+
+		for i := 1; i < len(selection.Tricks); i++ {
+			prev, next := selection.Tricks[i-1], selection.Tricks[i]
+			if transitionCompatibility(prev, next) != TransitionMismatch {
+				continue
+			}
+			// A mismatch is only allowed when nothing left would have matched
+			if compatible := filterCompatibleTricks(without(catalog, selection.Tricks[:i]), prev.LandingStanceID); len(compatible) > 0 {
+				t.Fatalf("seed %d position %d: %s -> %s mismatches although %s fit",
+					seed, i+1, prev.ID, next.ID, compatible[0].ID)
+			}
+		}
+	}
+}
+
+func TestSelectBalancedLegRule(t *testing.T) {
+	legs := fixtures.CatalogLegs()
+
+	// Every catalog trick landing on the left leg, plus one landing on both feet
+	var lefts []models.Trick
+	for _, trick := range fixtures.CatalogTricks() {
+		if landingLeg(trick, legs) == models.LegLeft {
+			lefts = append(lefts, trick)
+		}
+	}
+	twoFeet := fixtures.CatalogTrick("backflip")
+
+	tests := []struct {
+		name        string
+		candidates  []models.Trick
+		count       int
+		wantRelaxed []int // nil = must never relax
+	}{
+		{name: "full catalog never relaxes", candidates: fixtures.CatalogTricks(), count: 10},
+		{name: "left only relaxes from the third trick", candidates: lefts[:5], count: 5, wantRelaxed: []int{3, 4, 5}},
+		{name: "one two-feet trick breaks the streak", candidates: append(append([]models.Trick{}, lefts[:4]...), twoFeet), count: 5, wantRelaxed: []int{4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 50; seed++ {
+				selection := Select(tt.candidates, Options{Count: tt.count, Strategy: StrategyBalanced, Legs: legs}, rand.NewSource(seed))
+
+				relaxed := map[int]bool{}
+				for _, pos := range selection.RelaxedPositions {
+					relaxed[pos] = true
+				}
+				for i := MaxSameLegLandings; i < len(selection.Tricks); i++ {
+					if blocked := blockedLeg(selection.Tricks[:i], legs); blocked != "" &&
+						landingLeg(selection.Tricks[i], legs) == blocked && !relaxed[i+1] {
+						t.Fatalf("seed %d: position %d breaks the leg rule without being reported", seed, i+1)
+					}
+				}
+
+				if tt.wantRelaxed == nil && len(selection.RelaxedPositions) > 0 {
+					t.Fatalf("seed %d: relaxed %v, want none", seed, selection.RelaxedPositions)
+				}
+				// With a single two-feet trick, where it lands decides the relaxed positions
+				if tt.wantRelaxed != nil && tt.name != "one two-feet trick breaks the streak" &&
+					!reflect.DeepEqual(selection.RelaxedPositions, tt.wantRelaxed) {
+					t.Fatalf("seed %d: relaxed %v, want %v", seed, selection.RelaxedPositions, tt.wantRelaxed)
+				}
+			}
+		})
+	}
+}
+
+func TestPick(t *testing.T) {
+	only := fixtures.CatalogTrick("cork")
+	if got := Pick([]models.Trick{only}, rand.NewSource(1)); got.ID != only.ID {
+		t.Errorf("Pick() = %s, want %s", got.ID, only.ID)
+	}
+
+	catalog := fixtures.CatalogTricks()
+	a := Pick(catalog, rand.NewSource(99))
+	b := Pick(catalog, rand.NewSource(99))
+	if a.ID != b.ID {
+		t.Errorf("Pick() with the same seed gave %s and %s", a.ID, b.ID)
+	}
+}
+
+func TestScoreCombo(t *testing.T) {
+	trick := func(id string, difficulty int64, takeoff, landing int) models.Trick {
+		builder := fixtures.Trick().WithSlug(id).WithStances(takeoff, landing)
+		if difficulty > 0 {
+			builder = builder.WithDifficulty(difficulty)
+		}
+		return builder.Build()
+	}
+	unrated := fixtures.Trick().WithSlug("unrated").Build()
+
+	tests := []struct {
+		name            string
+		tricks          []models.Trick
+		wantTotal       int64
+		wantGrade       string
+		wantTransitions []string
+	}{
+		{name: "empty", tricks: nil, wantGrade: "D", wantTransitions: []string{}},
+		{name: "single S", tricks: []models.Trick{trick("a", 9, 1, 1)}, wantTotal: 9, wantGrade: "S", wantTransitions: []string{}},
+		{
+			name:      "matching chain keeps grade",
+			tricks:    []models.Trick{trick("a", 6, 1, 2), trick("b", 6, 2, 3), trick("c", 6, 3, 1)},
+			wantTotal: 18, wantGrade: "A", wantTransitions: []string{TransitionMatch, TransitionMatch},
+		},
+		{
+			name:      "each mismatch drops a step",
+			tricks:    []models.Trick{trick("a", 6, 1, 2), trick("b", 6, 1, 3), trick("c", 6, 1, 1)},
+			wantTotal: 18, wantGrade: "C", wantTransitions: []string{TransitionMismatch, TransitionMismatch},
+		},
+		{
+			name:      "grade never drops below D",
+			tricks:    []models.Trick{trick("a", 2, 1, 2), trick("b", 2, 1, 3), trick("c", 2, 1, 1), trick("d", 2, 3, 1)},
+			wantTotal: 8, wantGrade: "D", wantTransitions: []string{TransitionMismatch, TransitionMismatch, TransitionMismatch},
+		},
+		{
+			name:      "unknown stance doesn't cost a step",
+			tricks:    []models.Trick{trick("a", 4, 1, 2), unrated},
+			wantTotal: 4, wantGrade: "B", wantTransitions: []string{TransitionUnknown},
+		},
+		{name: "no rated tricks is D", tricks: []models.Trick{unrated}, wantGrade: "D", wantTransitions: []string{}},
+		{name: "grade boundary", tricks: []models.Trick{trick("a", 8, 1, 1), trick("b", 7, 1, 1)}, wantTotal: 15, wantGrade: "A", wantTransitions: []string{TransitionMatch}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := ScoreCombo(tt.tricks)
+			if score.TotalDifficulty != tt.wantTotal {
+				t.Errorf("TotalDifficulty = %d, want %d", score.TotalDifficulty, tt.wantTotal)
+			}
+			if score.Grade != tt.wantGrade {
+				t.Errorf("Grade = %q, want %q", score.Grade, tt.wantGrade)
+			}
+			got := make([]string, len(score.Transitions))
+			for i, transition := range score.Transitions {
+				got[i] = transition.Compatibility
+				if transition.From != tt.tricks[i].ID || transition.To != tt.tricks[i+1].ID {
+					t.Errorf("transition %d = %s -> %s", i, transition.From, transition.To)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantTransitions) {
+				t.Errorf("transitions = %v, want %v", got, tt.wantTransitions)
+			}
+		})
+	}
+}
+
+func TestFormatNotation(t *testing.T) {
+	ten := make([]string, 10)
+	for i := range ten {
+		ten[i] = fmt.Sprintf("T%d", i+1)
+	}
+	unicode := []string{"Mortal atrás", "Bütterfly Twist", "旋子", "Cork 🔥"}
+
+	tests := []struct {
+		name   string
+		tricks []string
+		style  NotationStyle
+		want   string
+	}{
+		{name: "arrows", tricks: []string{"Cork", "Full"}, style: NotationArrows, want: "Cork > Full"},
+		{name: "dashes", tricks: []string{"Cork", "Full"}, style: NotationDashes, want: "Cork - Full"},
+		{name: "numbered", tricks: []string{"Cork", "Full"}, style: NotationNumbered, want: "1. Cork\n2. Full"},
+		{name: "numbered aligns from ten", tricks: ten[:10], style: NotationNumbered, want: " 1. T1\n 2. T2\n 3. T3\n 4. T4\n 5. T5\n 6. T6\n 7. T7\n 8. T8\n 9. T9\n10. T10"},
+		{name: "unknown style is arrows", tricks: []string{"Cork", "Full"}, style: "zigzag", want: "Cork > Full"},
+		{name: "single trick", tricks: []string{"Cork"}, style: NotationDashes, want: "Cork"},
+		{name: "empty", tricks: nil, style: NotationNumbered, want: ""},
+		{name: "unicode arrows", tricks: unicode, style: NotationArrows, want: "Mortal atrás > Bütterfly Twist > 旋子 > Cork 🔥"},
+		{name: "unicode dashes", tricks: unicode, style: NotationDashes, want: "Mortal atrás - Bütterfly Twist - 旋子 - Cork 🔥"},
+		{
+			name: "unicode numbered", tricks: unicode, style: NotationNumbered,
+			want: "1. Mortal atrás\n2. Bütterfly Twist\n3. 旋子\n4. Cork 🔥",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatNotation(tt.tricks, tt.style); got != tt.want {
+				t.Errorf("FormatNotation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatNotationLongNumberedCombos(t *testing.T) {
+	long := strings.Repeat("Double Cork Switch Kick ", 8) + "Mortal atrás" // Far past any terminal width
+
+	tests := []struct {
+		count     int
+		wantFirst string
+		wantLast  string
+	}{
+		{count: 9, wantFirst: "1. Trick 1", wantLast: "9. " + long},
+		{count: 12, wantFirst: " 1. Trick 1", wantLast: "12. " + long},
+		{count: 100, wantFirst: "  1. Trick 1", wantLast: "100. " + long},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.count), func(t *testing.T) {
+			tricks := make([]string, tt.count)
+			for i := range tricks {
+				tricks[i] = fmt.Sprintf("Trick %d", i+1)
+			}
+			tricks[tt.count-1] = long
+
+			lines := strings.Split(FormatNotation(tricks, NotationNumbered), "\n")
+			// One line per trick: long names aren't wrapped
+			if len(lines) != tt.count {
+				t.Fatalf("%d lines for %d tricks", len(lines), tt.count)
+			}
+			if lines[0] != tt.wantFirst || lines[len(lines)-1] != tt.wantLast {
+				t.Errorf("first / last line = %q / %q, want %q / %q", lines[0], lines[len(lines)-1], tt.wantFirst, tt.wantLast)
+			}
+
+			// Numbers are right-aligned, so every name starts in the same column
+			column := strings.Index(lines[0], "Trick")
+			for i, line := range lines {
+				if line[column:] != tricks[i] {
+					t.Errorf("line %d = %q, want the name %q from column %d", i+1, line, tricks[i], column)
+				}
+			}
+		})
+	}
+}
+
+// benchmarkCatalog repeats the fixture catalog n times with unique IDs
+func benchmarkCatalog(n int) []models.Trick {
+	base := fixtures.CatalogTricks()
+	tricks := make([]models.Trick, 0, n*len(base))
+	for i := 0; i < n; i++ {
+		for _, trick := range base {
+			trick.ID = fmt.Sprintf("%s-%d", trick.ID, i)
+			tricks = append(tricks, trick)
+		}
+	}
+	return tricks
+}
+
+func BenchmarkSelect(b *testing.B) {
+	for _, size := range []int{1, 30} {
+		catalog := benchmarkCatalog(size)
+		for _, strategy := range strategies {
+			b.Run(fmt.Sprintf("%s/%d_tricks", strategy, len(catalog)), func(b *testing.B) {
+				opts := Options{Count: 8, Strategy: strategy, Legs: fixtures.CatalogLegs()}
+				src := rand.NewSource(1)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					Select(catalog, opts, src)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkPick(b *testing.B) {
+	catalog := benchmarkCatalog(30)
+	src := rand.NewSource(1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Pick(catalog, src)
+	}
+}
+
+func BenchmarkScoreCombo(b *testing.B) {
+	combo := fixtures.CatalogTricks()[:8]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ScoreCombo(combo)
+	}
+}
@@ -0,0 +1,43 @@
+package comboalg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NotationStyle names a way of writing a combo out as text
+type NotationStyle string
+
+const (
+	// NotationArrows writes "Cork > Full" - the default
+	NotationArrows NotationStyle = "arrows"
+	// NotationDashes writes "Cork - Full"
+	NotationDashes NotationStyle = "dashes"
+	// NotationNumbered writes one trick per line: "1. Cork", "2. Full"
+	NotationNumbered NotationStyle = "numbered"
+)
+
+// NotationStyles lists every style, default first (for validation and error bodies)
+var NotationStyles = []string{string(NotationArrows), string(NotationDashes), string(NotationNumbered)}
+
+// FormatNotation writes a combo's trick names in the given style
+// Every combo shown as text goes through here, so the styles can't drift apart.
+// Numbered combos put each trick on its own line (never wrapped mid-name),
+// numbers right-aligned so names line up from the tenth trick on. An unknown
+// style is written as arrows - callers validate against NotationStyles first.
+func FormatNotation(tricks []string, style NotationStyle) string {
+	switch style {
+	case NotationDashes:
+		return strings.Join(tricks, " - ")
+	case NotationNumbered:
+		width := len(strconv.Itoa(len(tricks)))
+		lines := make([]string, len(tricks))
+		for i, name := range tricks {
+			lines[i] = fmt.Sprintf("%*d. %s", width, i+1, name)
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return strings.Join(tricks, " > ")
+	}
+}
@@ -1,8 +1,14 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all application configuration
@@ -15,8 +21,136 @@ type Config struct {
 	Environment string
 
 	InternalAPIKey string
+
+	// AllowedServices are the internal callers allowed to identify themselves
+	// via the service-name header (e.g. "bff", "bff-admin", "notification-service")
+	AllowedServices []string
+
+	// AdminServices are the services trusted to act for admin users
+	AdminServices []string
+
+	// PublicRateLimit applies to the unauthenticated catalog routes
+	PublicRateLimit RateLimitConfig
+
+	// InternalRateLimit applies to the API-key protected routes the BFF calls
+	InternalRateLimit RateLimitConfig
+
+	// APIKeyRateLimits applies per external API key, by the key's rate tier
+	// (APIKeyTierStandard, APIKeyTierPartner) - instead of the shared buckets
+	APIKeyRateLimits map[string]RateLimitConfig
+
+	// TrickPurgeAfter is how long a deleted trick can be restored before it is purged
+	TrickPurgeAfter time.Duration
+
+	// WeightDecay controls how stale tricks are suggested less often in generated combos
+	WeightDecay WeightDecayConfig
+
+	// DictionaryCacheTTL is how long an assembled trick dictionary is cached (0 disables)
+	DictionaryCacheTTL time.Duration
+
+	// NewTrickDays is how many days a trick is flagged is_new after it is added
+	NewTrickDays int
+
+	// StrictSchemaCheck refuses to start in production when required indexes are missing
+	StrictSchemaCheck bool
+
+	// ComboLimits caps how many combos a user can save
+	ComboLimits ComboLimitConfig
+
+	// PublicLinkSecret signs public trick links (empty disables them)
+	PublicLinkSecret string
+
+	// PublicLinkRateLimit applies per client IP to the keyless /public routes
+	PublicLinkRateLimit RateLimitConfig
+
+	// CategorySuggestions lets the bulk trick import and trick creation suggest
+	// (and, when sure, set) the category of tricks submitted without one
+	CategorySuggestions bool
+
+	// DifficultyBands name difficulty ranges ("advanced" = 7-8), lowest first
+	// Validated at startup: no overlaps and no gaps between neighbours
+	DifficultyBands []DifficultyBand
+
+	// TrickImportMaxBytes caps the upload of POST /tricks/import (CSV or JSON array)
+	TrickImportMaxBytes int64
+
+	// TrickGraphMaxNodes caps the tricks GET /tricks/graph returns - past it the
+	// request must narrow the graph by category or difficulty
+	TrickGraphMaxNodes int
+
+	// ShutdownDrainDelay is how long /health/ready fails after SIGTERM before the
+	// server stops accepting connections - long enough for the load balancer to notice
+	ShutdownDrainDelay time.Duration
+
+	// SearchSimilarityThreshold is the lowest pg_trgm word similarity (0-1] at
+	// which a search finds a trick despite a typo ("gainner" -> "Gainer").
+	// Only applies when the pg_trgm extension is installed.
+	SearchSimilarityThreshold float64
+}
+
+// DifficultyBand names an inclusive difficulty range
+type DifficultyBand struct {
+	Name string
+	Min  int64
+	Max  int64
+}
+
+// defaultDifficultyBands covers the 1-10 difficulty scale
+// Override with DIFFICULTY_BANDS="beginner=1-3,intermediate=4-6,advanced=7-8,elite=9-10"
+var defaultDifficultyBands = []DifficultyBand{
+	{Name: "beginner", Min: 1, Max: 3},
+	{Name: "intermediate", Min: 4, Max: 6},
+	{Name: "advanced", Min: 7, Max: 8},
+	{Name: "elite", Min: 9, Max: 10},
+}
+
+// ComboLimitConfig is the saved-combo cap, per user role
+type ComboLimitConfig struct {
+	Default int            // For users whose role has no override
+	ByRole  map[string]int // Per-role overrides - 0 means unlimited
+}
+
+// For returns the cap for a role (0 = unlimited)
+func (l ComboLimitConfig) For(role string) int {
+	if limit, ok := l.ByRole[role]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// Rate limit modes
+const (
+	// RateLimitHard rejects with 429 as soon as the bucket is empty
+	RateLimitHard = "hard"
+
+	// RateLimitQueue delays requests up to MaxWait for a token before rejecting
+	RateLimitQueue = "queue"
+)
+
+// External API key rate tiers (api_keys.rate_tier)
+const (
+	APIKeyTierStandard = "standard"
+	APIKeyTierPartner  = "partner"
+)
+
+// RateLimitConfig configures the token bucket for one route group
+type RateLimitConfig struct {
+	Mode              string  // RateLimitHard or RateLimitQueue
+	RequestsPerSecond float64 // Sustained rate
+	Burst             int     // Requests allowed at once before limiting kicks in
+	MaxWait           time.Duration
+}
+
+// WeightDecayConfig is the staleness rule for the trick weight decay job
+// A trick older than After with no video and no view in the last After gets Modifier
+type WeightDecayConfig struct {
+	After    time.Duration
+	Modifier float64 // (0, 1] - multiplies the trick's weight during combo generation
 }
 
+// minPublicLinkSecretLength keeps the HMAC key at least as long as its 256-bit output
+const minPublicLinkSecretLength = 32
+
 // Load reads configuration from environment variables
 func Load() (*Config, error) {
 	// Database URL is required
@@ -39,11 +173,150 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	publicLimit, err := getRateLimit("RATE_LIMIT_PUBLIC", RateLimitConfig{
+		Mode: RateLimitHard, RequestsPerSecond: 20, Burst: 40,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The BFF fronts real users, so brief spikes queue instead of erroring
+	internalLimit, err := getRateLimit("RATE_LIMIT_INTERNAL", RateLimitConfig{
+		Mode: RateLimitQueue, RequestsPerSecond: 100, Burst: 200, MaxWait: 2 * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	purgeDays, err := strconv.Atoi(getEnv("TRICK_PURGE_AFTER_DAYS", "30"))
+	if err != nil || purgeDays < 1 {
+		return nil, fmt.Errorf("TRICK_PURGE_AFTER_DAYS must be a positive integer")
+	}
+
+	decayDays, err := strconv.Atoi(getEnv("WEIGHT_DECAY_AFTER_DAYS", "365"))
+	if err != nil || decayDays < 1 {
+		return nil, fmt.Errorf("WEIGHT_DECAY_AFTER_DAYS must be a positive integer")
+	}
+
+	decayModifier, err := strconv.ParseFloat(getEnv("WEIGHT_DECAY_MODIFIER", "0.5"), 64)
+	if err != nil || decayModifier <= 0 || decayModifier > 1 {
+		return nil, fmt.Errorf("WEIGHT_DECAY_MODIFIER must be greater than 0 and at most 1")
+	}
+
+	dictionaryTTL, err := strconv.Atoi(getEnv("DICTIONARY_CACHE_TTL_SECONDS", "300"))
+	if err != nil || dictionaryTTL < 0 {
+		return nil, fmt.Errorf("DICTIONARY_CACHE_TTL_SECONDS must be a non-negative integer")
+	}
+
+	newTrickDays, err := strconv.Atoi(getEnv("NEW_TRICK_WINDOW_DAYS", "7"))
+	if err != nil || newTrickDays < 1 || newTrickDays > 90 {
+		return nil, fmt.Errorf("NEW_TRICK_WINDOW_DAYS must be an integer between 1 and 90")
+	}
+
+	strictSchema, err := strconv.ParseBool(getEnv("STRICT_SCHEMA_CHECK", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("STRICT_SCHEMA_CHECK must be true or false")
+	}
+
+	categorySuggestions, err := strconv.ParseBool(getEnv("CATEGORY_SUGGESTIONS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("CATEGORY_SUGGESTIONS must be true or false")
+	}
+
+	difficultyBands, err := getDifficultyBands(defaultDifficultyBands)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0.3 is pg_trgm's own default - low enough for one-letter typos in short names
+	searchSimilarity, err := strconv.ParseFloat(getEnv("SEARCH_SIMILARITY_THRESHOLD", "0.3"), 64)
+	if err != nil || searchSimilarity <= 0 || searchSimilarity > 1 {
+		return nil, fmt.Errorf("SEARCH_SIMILARITY_THRESHOLD must be greater than 0 and at most 1")
+	}
+
+	// 1 MiB is a few thousand rows - plenty to seed a catalog
+	importMaxBytes, err := strconv.ParseInt(getEnv("TRICK_IMPORT_MAX_BYTES", "1048576"), 10, 64)
+	if err != nil || importMaxBytes < 1 {
+		return nil, fmt.Errorf("TRICK_IMPORT_MAX_BYTES must be a positive integer")
+	}
+
+	// Beyond a few hundred nodes a rendered graph stops being readable anyway
+	graphMaxNodes, err := strconv.Atoi(getEnv("TRICK_GRAPH_MAX_NODES", "300"))
+	if err != nil || graphMaxNodes < 1 {
+		return nil, fmt.Errorf("TRICK_GRAPH_MAX_NODES must be a positive integer")
+	}
+
+	// Kubernetes keeps routing to a terminating pod for a few seconds; the delay
+	// plus the 30s shutdown should fit in the pod's termination grace period
+	drainSeconds, err := strconv.Atoi(getEnv("SHUTDOWN_DRAIN_DELAY_SECONDS", "5"))
+	if err != nil || drainSeconds < 0 || drainSeconds > 60 {
+		return nil, fmt.Errorf("SHUTDOWN_DRAIN_DELAY_SECONDS must be an integer between 0 and 60")
+	}
+
+	// Anyone holding a link can read through it, so keep the per-IP budget small
+	publicLinkLimit, err := getRateLimit("RATE_LIMIT_PUBLIC_LINK", RateLimitConfig{
+		Mode: RateLimitHard, RequestsPerSecond: 1, Burst: 10,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Each external API key gets its own bucket, sized by its tier
+	apiKeyLimits := make(map[string]RateLimitConfig, 2)
+	for tier, defaults := range map[string]RateLimitConfig{
+		APIKeyTierStandard: {Mode: RateLimitHard, RequestsPerSecond: 5, Burst: 20},
+		APIKeyTierPartner:  {Mode: RateLimitHard, RequestsPerSecond: 25, Burst: 100},
+	} {
+		limit, err := getRateLimit("RATE_LIMIT_API_KEY_"+strings.ToUpper(tier), defaults)
+		if err != nil {
+			return nil, err
+		}
+		apiKeyLimits[tier] = limit
+	}
+
+	// Optional - without it public links can't be minted or read
+	publicLinkSecret := getEnv("PUBLIC_LINK_SECRET", "")
+	if publicLinkSecret != "" && len(publicLinkSecret) < minPublicLinkSecretLength {
+		return nil, fmt.Errorf("PUBLIC_LINK_SECRET must be at least %d characters", minPublicLinkSecretLength)
+	}
+
+	// Free tier gets 50; admins and pro users are unlimited unless overridden
+	comboLimits, err := getComboLimits(ComboLimitConfig{
+		Default: 50,
+		ByRole:  map[string]int{"admin": 0, "pro": 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		DatabaseURL:    dbURL,
-		Port:           getEnv("PORT", "8080"), // Default to 8080 if not set
-		Environment:    env,
-		InternalAPIKey: internalKey,
+		DatabaseURL:       dbURL,
+		Port:              getEnv("PORT", "8080"), // Default to 8080 if not set
+		Environment:       env,
+		InternalAPIKey:    internalKey,
+		AllowedServices:   getEnvList("ALLOWED_SERVICES", []string{"bff", "bff-admin", "notification-service"}),
+		AdminServices:     getEnvList("ADMIN_SERVICES", []string{"bff-admin"}),
+		PublicRateLimit:   publicLimit,
+		InternalRateLimit: internalLimit,
+		APIKeyRateLimits:  apiKeyLimits,
+		TrickPurgeAfter:   time.Duration(purgeDays) * 24 * time.Hour,
+		WeightDecay: WeightDecayConfig{
+			After:    time.Duration(decayDays) * 24 * time.Hour,
+			Modifier: decayModifier,
+		},
+		DictionaryCacheTTL:  time.Duration(dictionaryTTL) * time.Second,
+		NewTrickDays:        newTrickDays,
+		StrictSchemaCheck:   strictSchema,
+		ComboLimits:         comboLimits,
+		PublicLinkSecret:    publicLinkSecret,
+		PublicLinkRateLimit: publicLinkLimit,
+		CategorySuggestions: categorySuggestions,
+		DifficultyBands:     difficultyBands,
+		TrickImportMaxBytes: importMaxBytes,
+		TrickGraphMaxNodes:  graphMaxNodes,
+		ShutdownDrainDelay:  time.Duration(drainSeconds) * time.Second,
+
+		SearchSimilarityThreshold: searchSimilarity,
 	}, nil
 }
 
@@ -57,6 +330,25 @@ func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
 
+// EnabledFeatures lists the optional features this config switches on, sorted
+// Names only - never the settings behind them (PublicLinkSecret is a key).
+func (c *Config) EnabledFeatures() []string {
+	features := []string{}
+	if c.CategorySuggestions {
+		features = append(features, "category_suggestions")
+	}
+	if c.DictionaryCacheTTL > 0 {
+		features = append(features, "dictionary_cache")
+	}
+	if c.PublicLinkSecret != "" {
+		features = append(features, "public_links")
+	}
+	if c.StrictSchemaCheck {
+		features = append(features, "strict_schema_check")
+	}
+	return features
+}
+
 // getEnv is a helper that returns a default if the env var is not set
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -65,6 +357,134 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList reads a comma-separated env var, returning a default if it's not set
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getRateLimit reads PREFIX_MODE, PREFIX_RPS, PREFIX_BURST and PREFIX_MAX_WAIT
+// Any variable that isn't set keeps its default
+func getRateLimit(prefix string, defaults RateLimitConfig) (RateLimitConfig, error) {
+	cfg := defaults
+	var err error
+
+	cfg.Mode = getEnv(prefix+"_MODE", defaults.Mode)
+	if cfg.Mode != RateLimitHard && cfg.Mode != RateLimitQueue {
+		return cfg, fmt.Errorf("%s_MODE must be %q or %q", prefix, RateLimitHard, RateLimitQueue)
+	}
+
+	if value := os.Getenv(prefix + "_RPS"); value != "" {
+		if cfg.RequestsPerSecond, err = strconv.ParseFloat(value, 64); err != nil || cfg.RequestsPerSecond <= 0 {
+			return cfg, fmt.Errorf("%s_RPS must be a positive number", prefix)
+		}
+	}
+
+	if value := os.Getenv(prefix + "_BURST"); value != "" {
+		if cfg.Burst, err = strconv.Atoi(value); err != nil || cfg.Burst < 1 {
+			return cfg, fmt.Errorf("%s_BURST must be a positive integer", prefix)
+		}
+	}
+
+	// time.ParseDuration accepts values like "500ms" or "2s"
+	if value := os.Getenv(prefix + "_MAX_WAIT"); value != "" {
+		if cfg.MaxWait, err = time.ParseDuration(value); err != nil || cfg.MaxWait < 0 {
+			return cfg, fmt.Errorf("%s_MAX_WAIT must be a duration like 2s", prefix)
+		}
+	}
+
+	return cfg, nil
+}
+
+// getComboLimits reads COMBO_LIMIT (the default cap) and COMBO_LIMIT_ROLES
+// ("moderator=200,pro=0") - role overrides are merged over the defaults
+func getComboLimits(defaults ComboLimitConfig) (ComboLimitConfig, error) {
+	limits := ComboLimitConfig{Default: defaults.Default, ByRole: make(map[string]int)}
+	for role, limit := range defaults.ByRole {
+		limits.ByRole[role] = limit
+	}
+
+	if value := os.Getenv("COMBO_LIMIT"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return limits, fmt.Errorf("COMBO_LIMIT must be a non-negative integer (0 = unlimited)")
+		}
+		limits.Default = limit
+	}
+
+	for _, item := range getEnvList("COMBO_LIMIT_ROLES", nil) {
+		role, value, ok := strings.Cut(item, "=")
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(role) == "" || err != nil || limit < 0 {
+			return limits, fmt.Errorf("COMBO_LIMIT_ROLES must look like role=limit,role=limit (got %q)", item)
+		}
+		limits.ByRole[strings.TrimSpace(role)] = limit
+	}
+
+	return limits, nil
+}
+
+// getDifficultyBands reads DIFFICULTY_BANDS ("beginner=1-3,intermediate=4-6")
+// The list replaces the defaults as a whole. Bands are returned lowest first,
+// and must tile their range: overlapping or gapped bands are an error.
+func getDifficultyBands(defaults []DifficultyBand) ([]DifficultyBand, error) {
+	bands := defaults
+	if items := getEnvList("DIFFICULTY_BANDS", nil); len(items) > 0 {
+		bands = make([]DifficultyBand, 0, len(items))
+		for _, item := range items {
+			name, bounds, _ := strings.Cut(item, "=")
+			low, high, ok := strings.Cut(bounds, "-")
+			min, minErr := strconv.ParseInt(strings.TrimSpace(low), 10, 64)
+			max, maxErr := strconv.ParseInt(strings.TrimSpace(high), 10, 64)
+			if !ok || minErr != nil || maxErr != nil {
+				return nil, fmt.Errorf("DIFFICULTY_BANDS must look like name=min-max,name=min-max (got %q)", item)
+			}
+			bands = append(bands, DifficultyBand{Name: strings.ToLower(strings.TrimSpace(name)), Min: min, Max: max})
+		}
+	}
+
+	sorted := slices.Clone(bands)
+	slices.SortFunc(sorted, func(a, b DifficultyBand) int { return cmp.Compare(a.Min, b.Min) })
+
+	seen := make(map[string]bool, len(sorted))
+	for i, band := range sorted {
+		switch {
+		case !bandNamePattern.MatchString(band.Name):
+			return nil, fmt.Errorf("DIFFICULTY_BANDS: band name %q must be lowercase letters, digits, - or _", band.Name)
+		case seen[band.Name]:
+			return nil, fmt.Errorf("DIFFICULTY_BANDS: band %q is defined twice", band.Name)
+		case band.Min > band.Max:
+			return nil, fmt.Errorf("DIFFICULTY_BANDS: band %q has min %d above max %d", band.Name, band.Min, band.Max)
+		}
+		seen[band.Name] = true
+
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		if band.Min <= prev.Max {
+			return nil, fmt.Errorf("DIFFICULTY_BANDS: bands %q and %q overlap", prev.Name, band.Name)
+		}
+		if band.Min > prev.Max+1 {
+			return nil, fmt.Errorf("DIFFICULTY_BANDS: gap between bands %q and %q (%d-%d)", prev.Name, band.Name, prev.Max+1, band.Min-1)
+		}
+	}
+	return sorted, nil
+}
+
+// bandNamePattern is what a difficulty band name can look like (it's used as ?band=)
+var bandNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
 // getEnvRequired returns an error if the env var is not set
 func getEnvRequired(key string) (string, error) {
 	value := os.Getenv(key)
@@ -5,6 +5,9 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/redact"
 )
 
 // creates and configures a new PostgreSQL connection pool
@@ -25,3 +28,15 @@ func NewPool(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
+
+// Describe reports where a pool connects and its limits, with credentials redacted
+func Describe(pool *pgxpool.Pool) models.DatabaseInfo {
+	cfg := pool.Config()
+	return models.DatabaseInfo{
+		URL:             redact.ConnString(cfg.ConnString()),
+		MaxConns:        cfg.MaxConns,
+		MinConns:        cfg.MinConns,
+		MaxConnLifetime: cfg.MaxConnLifetime.String(),
+		MaxConnIdleTime: cfg.MaxConnIdleTime.String(),
+	}
+}
@@ -0,0 +1,178 @@
+package database
+
+// =============================================================================
+// REQUIRED INDEXES
+// =============================================================================
+// Queries in the repository layer assume these indexes exist. Without them they
+// still work - they just table-scan, which is how this list came about.
+//
+// KEEP IN SYNC: the schema for each table is documented in the header of its
+// repository file. When a change there adds an index that a query relies on,
+// add it here too, in the same commit.
+//
+// The startup self-check (services.SelfCheckService) looks every entry up by
+// name in pg_indexes and reports the Definition of anything missing.
+
+// RequiredIndex is one index the API needs to perform well
+type RequiredIndex struct {
+	Schema string // "public" for tables created without a schema prefix
+	Table  string
+	Name   string
+	// Definition is the DDL that creates the index, logged when it is missing
+	Definition string
+}
+
+// RequiredIndexes is every index checked at startup and by /admin/self-check
+var RequiredIndexes = []RequiredIndex{
+	{
+		Schema:     "trick_data",
+		Table:      "tricks",
+		Name:       "tricks_slug_key",
+		Definition: "ALTER TABLE trick_data.tricks ADD CONSTRAINT tricks_slug_key UNIQUE (slug);",
+	},
+	{
+		// Case-insensitive slug lookups (TrickRepository.GetByID)
+		Schema:     "trick_data",
+		Table:      "tricks",
+		Name:       "tricks_lower_slug",
+		Definition: "CREATE UNIQUE INDEX tricks_lower_slug ON trick_data.tricks (lower(slug));",
+	},
+	{
+		// One trick per name, any case - backs the name checks of every trick write
+		// Rejected submissions give their name up; an index created before that
+		// (without the WHERE) has to be dropped and created again
+		Schema:     "trick_data",
+		Table:      "tricks",
+		Name:       "tricks_lower_name",
+		Definition: "CREATE UNIQUE INDEX tricks_lower_name ON trick_data.tricks (lower(name)) WHERE status <> 'rejected';",
+	},
+	{
+		Schema:     "trick_data",
+		Table:      "tricks",
+		Name:       "tricks_live_name_slug",
+		Definition: "CREATE INDEX tricks_live_name_slug ON trick_data.tricks (name, slug) WHERE deleted_at IS NULL;",
+	},
+	{
+		Schema:     "trick_data",
+		Table:      "tricks",
+		Name:       "tricks_search_fts",
+		Definition: "CREATE INDEX tricks_search_fts ON trick_data.tricks USING GIN (to_tsvector('english', name || ' ' || COALESCE(description, '') || ' ' || COALESCE(execution_notes, '')));",
+	},
+	{
+		Schema:     "trick_data",
+		Table:      "tricks",
+		Name:       "tricks_name_trgm",
+		Definition: "CREATE INDEX tricks_name_trgm ON trick_data.tricks USING GIN (name gin_trgm_ops);",
+	},
+	{
+		// Featured-video lookups per trick (GetTrickDictionary)
+		Schema:     "trick_data",
+		Table:      "trick_videos",
+		Name:       "trick_videos_trick_id_featured",
+		Definition: "CREATE INDEX trick_videos_trick_id_featured ON trick_data.trick_videos (trick_id) WHERE is_featured;",
+	},
+	{
+		// One row per (trick, URL) - duplicate checks of video creation and import rely on it
+		Schema:     "trick_data",
+		Table:      "trick_videos",
+		Name:       "trick_videos_trick_id_url",
+		Definition: "CREATE UNIQUE INDEX trick_videos_trick_id_url ON trick_data.trick_videos (trick_id, video_url);",
+	},
+	{
+		// Any-video checks per trick (GET /tricks?include=video_flags)
+		Schema:     "trick_data",
+		Table:      "trick_videos",
+		Name:       "trick_videos_trick_id",
+		Definition: "CREATE INDEX trick_videos_trick_id ON trick_data.trick_videos (trick_id);",
+	},
+	{
+		// Tricks unlocked by a trick (GET /tricks/:id/unlocks) - the primary key covers the other direction
+		Schema:     "trick_data",
+		Table:      "trick_prerequisites",
+		Name:       "trick_prerequisites_prerequisite_id",
+		Definition: "CREATE INDEX trick_prerequisites_prerequisite_id ON trick_data.trick_prerequisites (prerequisite_id);",
+	},
+	{
+		// Sitemap slugs, index-only (TrickRepository.FindSlugs)
+		Schema:     "trick_data",
+		Table:      "tricks",
+		Name:       "tricks_live_slugs",
+		Definition: "CREATE INDEX tricks_live_slugs ON trick_data.tricks (slug) INCLUDE (created_at, updated_at) WHERE deleted_at IS NULL AND status = 'approved';",
+	},
+	{
+		// Recently added tricks (GET /tricks/new, stats "added last 30 days")
+		Schema:     "trick_data",
+		Table:      "tricks",
+		Name:       "tricks_live_created_at",
+		Definition: "CREATE INDEX tricks_live_created_at ON trick_data.tricks (created_at DESC) WHERE deleted_at IS NULL;",
+	},
+	{
+		// Tricks carrying a tag (GET /tricks?tag=, tag usage counts) - the primary key covers trick -> tags
+		Schema:     "trick_data",
+		Table:      "trick_tags",
+		Name:       "trick_tags_tag_id",
+		Definition: "CREATE INDEX trick_tags_tag_id ON trick_data.trick_tags (tag_id);",
+	},
+	{
+		// Views per day in a window (GET /tricks/popular) and retention pruning
+		Schema:     "trick_data",
+		Table:      "trick_view_days",
+		Name:       "trick_view_days_day",
+		Definition: "CREATE INDEX trick_view_days_day ON trick_data.trick_view_days (day);",
+	},
+	{
+		// Alias slug -> trick (trick lookups that miss on the slug); unique, so it also enforces that
+		Schema:     "trick_data",
+		Table:      "trick_aliases",
+		Name:       "trick_aliases_slug",
+		Definition: "CREATE UNIQUE INDEX trick_aliases_slug ON trick_data.trick_aliases (slug);",
+	},
+	{
+		// Aliases of a trick (trick detail and every search candidate)
+		Schema:     "trick_data",
+		Table:      "trick_aliases",
+		Name:       "trick_aliases_trick_id",
+		Definition: "CREATE INDEX trick_aliases_trick_id ON trick_data.trick_aliases (trick_id);",
+	},
+	{
+		// Common mistakes of a trick, in display order (every dictionary)
+		Schema:     "trick_data",
+		Table:      "trick_mistakes",
+		Name:       "trick_mistakes_trick_id_position",
+		Definition: "CREATE INDEX trick_mistakes_trick_id_position ON trick_data.trick_mistakes (trick_id, position);",
+	},
+	{
+		// combo_id leads the primary key, so this also serves "all tricks of a combo"
+		Schema:     "public",
+		Table:      "combo_tricks",
+		Name:       "combo_tricks_pkey",
+		Definition: "ALTER TABLE combo_tricks ADD PRIMARY KEY (combo_id, trick_id, position);",
+	},
+	{
+		Schema:     "public",
+		Table:      "combos",
+		Name:       "combos_user_id_idx",
+		Definition: "CREATE INDEX combos_user_id_idx ON combos (user_id);",
+	},
+	{
+		// External API keys are looked up by hash on every request that sends one
+		Schema:     "public",
+		Table:      "api_keys",
+		Name:       "api_keys_key_hash_key",
+		Definition: "ALTER TABLE api_keys ADD CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash);",
+	},
+	{
+		// One translation per (trick, locale) - trick details look them up by both
+		Schema:     "trick_data",
+		Table:      "trick_translations",
+		Name:       "trick_translations_pkey",
+		Definition: "ALTER TABLE trick_data.trick_translations ADD PRIMARY KEY (trick_id, locale);",
+	},
+	{
+		// The training calendar reads one user's attempts over a date range
+		Schema:     "public",
+		Table:      "combo_attempts",
+		Name:       "combo_attempts_user_id_attempted_at",
+		Definition: "CREATE INDEX combo_attempts_user_id_attempted_at ON combo_attempts (user_id, attempted_at);",
+	},
+}
@@ -0,0 +1,865 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+)
+
+// videoCheckTimeout bounds a forced availability check so the admin isn't left waiting
+const videoCheckTimeout = 10 * time.Second
+
+// importTimeout bounds a bulk import - the same budget as other admin routes, but
+// set here because middleware.Timeout buffers responses and imports stream theirs
+const importTimeout = 60 * time.Second
+
+// ndjsonContentType is the media type of bulk imports and their results
+const ndjsonContentType = "application/x-ndjson"
+
+// AdminHandler handles HTTP requests for admin maintenance endpoints
+type AdminHandler struct {
+	adminService      services.AdminServiceInterface
+	videoAvailability services.VideoAvailabilityServiceInterface
+	selfCheck         services.SelfCheckServiceInterface
+	consistency       services.CatalogConsistencyInterface
+	runtimeInfo       services.RuntimeInfoServiceInterface
+}
+
+// NewAdminHandler creates a new AdminHandler instance
+func NewAdminHandler(
+	adminService services.AdminServiceInterface,
+	videoAvailability services.VideoAvailabilityServiceInterface,
+	selfCheck services.SelfCheckServiceInterface,
+	consistency services.CatalogConsistencyInterface,
+	runtimeInfo services.RuntimeInfoServiceInterface,
+) *AdminHandler {
+	return &AdminHandler{
+		adminService:      adminService,
+		videoAvailability: videoAvailability,
+		selfCheck:         selfCheck,
+		consistency:       consistency,
+		runtimeInfo:       runtimeInfo,
+	}
+}
+
+// SelfCheck re-runs the schema self-check and returns the fresh report
+// Also updates the degraded flag on /health/ready, so run it after adding an index
+func (h *AdminHandler) SelfCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, h.selfCheck.Run(c.Request.Context()))
+}
+
+// CheckConsistency reports trick references (stances, flip) that point at nothing
+// GET only reports. POST with ?fix=null_out also clears them, recording each
+// cleared trick in the revision log under the acting admin.
+func (h *AdminHandler) CheckConsistency(c *gin.Context) {
+	fix := ""
+	if c.Request.Method == http.MethodPost {
+		fix = c.Query("fix")
+	}
+
+	report, err := h.consistency.Check(c.Request.Context(), fix, actingUserID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownFixMode) {
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeUnknownFixMode, gin.H{
+				"allowed": models.ConsistencyFixNullOut,
+			})
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ResanitizeCatalog re-applies sanitization rules to existing tricks and videos
+// Safe to run more than once - already-clean rows are not rewritten
+func (h *AdminHandler) ResanitizeCatalog(c *gin.Context) {
+	report, err := h.adminService.ResanitizeCatalog(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetCatalogDiff returns what changed in the catalog between two timestamps
+// Query params: ?from=...&to=... (RFC 3339 or YYYY-MM-DD, to defaults to now)
+// Add &format=csv for a spreadsheet-friendly download
+// Videos are listed when created or edited; a purged video is covered by its
+// trick's "deleted" row, since videos are only removed along with their trick
+func (h *AdminHandler) GetCatalogDiff(c *gin.Context) {
+	from, err := parseTimeParam(c.Query("from"))
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidFrom)
+		return
+	}
+
+	to := time.Now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		if to, err = parseTimeParam(raw); err != nil {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidTo)
+			return
+		}
+	}
+
+	diff, err := h.adminService.GetCatalogDiff(c.Request.Context(), from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDiffWindow) {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidDiffWindow)
+			return
+		}
+
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		writeCatalogDiffCSV(c, diff)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// GetAttributions lists every trick and video that credits an external source
+func (h *AdminHandler) GetAttributions(c *gin.Context) {
+	sources, err := h.adminService.GetAttributions(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sources": sources,
+		"count":   len(sources),
+	})
+}
+
+// GetDifficultyCalibration lists tricks where curated and community difficulty disagree
+// Query params: ?min_votes=10&min_delta=2&limit=50&offset=0
+func (h *AdminHandler) GetDifficultyCalibration(c *gin.Context) {
+	minVotes, err := strconv.Atoi(c.DefaultQuery("min_votes", "10"))
+	if err != nil || minVotes < 1 {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidMinVotes)
+		return
+	}
+
+	minDelta, err := strconv.ParseFloat(c.DefaultQuery("min_delta", "2"), 64)
+	if err != nil || minDelta < 0 {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidMinDelta)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidLimit, gin.H{"min": 1, "max": 200})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidOffset)
+		return
+	}
+
+	page, err := h.adminService.GetDifficultyCalibration(c.Request.Context(), minVotes, minDelta, limit, offset)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// AdoptCommunityDifficulty copies the rounded community average into the curated difficulty
+func (h *AdminHandler) AdoptCommunityDifficulty(c *gin.Context) {
+	change, err := h.adminService.AdoptCommunityDifficulty(c.Request.Context(), slugParam(c, "slug"), actingUserID(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTrickNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+		case errors.Is(err, services.ErrNoDifficultyVotes):
+			messages.Respond(c, http.StatusUnprocessableEntity, messages.CodeNoDifficultyVotes)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+// DeleteTrick soft-deletes a trick - it can be restored until its purge date
+func (h *AdminHandler) DeleteTrick(c *gin.Context) {
+	deletion, err := h.adminService.DeleteTrick(c.Request.Context(), slugParam(c, "id"), actingUserID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrTrickNotFound) {
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, deletion)
+}
+
+// SetTrickWeight sets a trick's curated weight, which drives how often combo generation picks it
+// Body: {"weight": 1-1000}. Responds with the weight and whether it changed.
+func (h *AdminHandler) SetTrickWeight(c *gin.Context) {
+	var req models.TrickWeightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWeightError(c, services.ErrInvalidTrickWeight)
+		return
+	}
+
+	result, err := h.adminService.SetTrickWeight(c.Request.Context(), slugParam(c, "id"), req.Weight, actingUserID(c))
+	if err != nil {
+		respondWeightError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SetTrickWeights sets the curated weights of many tricks in one transaction
+// Body: {"weights": {"cork": 400, "btwist": 250}}. All or nothing - an unknown
+// slug is a 404 listing every unknown slug, and no weight changes.
+func (h *AdminHandler) SetTrickWeights(c *gin.Context) {
+	var req models.TrickWeightsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.adminService.SetTrickWeights(c.Request.Context(), req.Weights, actingUserID(c))
+	if err != nil {
+		respondWeightError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondWeightError maps weight change errors to HTTP responses
+func respondWeightError(c *gin.Context, err error) {
+	var unknownErr *services.UnknownTricksError
+	switch {
+	case errors.As(err, &unknownErr):
+		messages.RespondWith(c, http.StatusNotFound, messages.CodeUnknownTrickSlugs, gin.H{"slugs": unknownErr.Slugs})
+	case errors.Is(err, services.ErrTrickNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+	case errors.Is(err, services.ErrInvalidTrickWeight):
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidTrickWeight, gin.H{
+			"min": services.MinTrickWeight,
+			"max": services.MaxTrickWeight,
+		})
+	case errors.Is(err, services.ErrTooManyWeights):
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeTooManySlugs, gin.H{"max": services.MaxBulkWeights})
+	default:
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+	}
+}
+
+// RestoreTrick undoes a delete, as long as the trick hasn't reached its purge date
+func (h *AdminHandler) RestoreTrick(c *gin.Context) {
+	err := h.adminService.RestoreTrick(c.Request.Context(), slugParam(c, "id"), actingUserID(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTrickNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+		case errors.Is(err, services.ErrTrickNotDeleted):
+			messages.Respond(c, http.StatusConflict, messages.CodeTrickNotDeleted)
+		case errors.Is(err, services.ErrPurgeWindowClosed):
+			// 410 Gone - the trick is about to be (or has been) removed for good
+			messages.Respond(c, http.StatusGone, messages.CodePurgeWindowClosed)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetTrickHistory lists who changed a trick and how, newest first
+// Query params: ?limit= (1-200, default 50) and ?offset=. Each entry's diff
+// holds the old and new value of every field the write changed.
+func (h *AdminHandler) GetTrickHistory(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidLimit, gin.H{"min": 1, "max": 200})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidOffset)
+		return
+	}
+
+	page, err := h.adminService.GetTrickHistory(c.Request.Context(), slugParam(c, "id"), limit, offset)
+	if err != nil {
+		if errors.Is(err, services.ErrTrickNotFound) {
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// MergeTrick folds a duplicate into the trick in the URL, then soft-deletes the duplicate
+// Body: models.TrickMergeRequest - {"duplicate_slug": "540"}. Responds with how
+// many combo positions, videos and aliases moved; 422 for a merge into itself.
+func (h *AdminHandler) MergeTrick(c *gin.Context) {
+	var req models.TrickMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	merged, err := h.adminService.MergeTrick(c.Request.Context(), slugParam(c, "slug"), req.DuplicateSlug, actingUserID(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMergeIntoSelf):
+			messages.Respond(c, http.StatusUnprocessableEntity, messages.CodeMergeIntoSelf)
+		case errors.Is(err, services.ErrTrickNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, merged)
+}
+
+// ListPendingTricks returns the tricks waiting for review, oldest first
+// Query params: ?limit= (1-200, default 50) and ?offset=.
+func (h *AdminHandler) ListPendingTricks(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidLimit, gin.H{"min": 1, "max": 200})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidOffset)
+		return
+	}
+
+	page, err := h.adminService.ListPendingTricks(c.Request.Context(), limit, offset)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ApproveTrick adds a pending trick to the catalog (409 if it was already reviewed)
+func (h *AdminHandler) ApproveTrick(c *gin.Context) {
+	trick, err := h.adminService.ApproveTrick(c.Request.Context(), slugParam(c, "slug"), actingUserID(c))
+	if err != nil {
+		respondTrickReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trick)
+}
+
+// RejectTrick turns a pending trick down (409 if it was already reviewed)
+// Body (optional): models.TrickRejectRequest - {"reason": "..."}, shown to the submitter.
+func (h *AdminHandler) RejectTrick(c *gin.Context) {
+	var req models.TrickRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	trick, err := h.adminService.RejectTrick(c.Request.Context(), slugParam(c, "slug"), req.Reason, actingUserID(c))
+	if err != nil {
+		respondTrickReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trick)
+}
+
+// respondTrickReviewError maps an approve/reject error to its response
+func respondTrickReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTrickNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+	case errors.Is(err, services.ErrTrickNotPending):
+		messages.Respond(c, http.StatusConflict, messages.CodeTrickNotPending)
+	case errors.Is(err, services.ErrInvalidRejectionReason):
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRejectionReason, gin.H{
+			"max": services.MaxRejectionReasonLength,
+		})
+	default:
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+	}
+}
+
+// GetPendingPurge lists deleted tricks and when each will be purged
+func (h *AdminHandler) GetPendingPurge(c *gin.Context) {
+	pending, err := h.adminService.GetPendingPurge(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": pending,
+		"count":  len(pending),
+	})
+}
+
+// CreateVideo adds a video to a trick
+// Body: models.VideoCreateRequest. The acting admin is recorded as the uploader.
+func (h *AdminHandler) CreateVideo(c *gin.Context) {
+	uploadedBy := actingUserID(c)
+	if uploadedBy == nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	var req models.VideoCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	video, err := h.adminService.CreateVideo(c.Request.Context(), slugParam(c, "slug"), req, *uploadedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTrickNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+		case errors.Is(err, services.ErrDuplicateVideo):
+			messages.Respond(c, http.StatusConflict, messages.CodeDuplicateVideo)
+		case errors.Is(err, services.ErrInvalidVideoURL), errors.Is(err, services.ErrInvalidVideoLabel):
+			code, params := videoRowCode(err)
+			messages.RespondWith(c, http.StatusBadRequest, code, params)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, video)
+}
+
+// ImportVideos adds many videos to tricks at once, matched by trick slug
+// Body: models.VideoImportRequest - {"rows": [{"trick_slug", "video_url", "performer_name",
+// "label", "is_featured"}, ...]}. Responds 200 with a result per row (created, skipped
+// as a duplicate URL, or invalid with a code) - bad rows don't fail the import.
+// If a chunk fails to write, the summary still covers the rows saved before it.
+func (h *AdminHandler) ImportVideos(c *gin.Context) {
+	uploadedBy := actingUserID(c)
+	if uploadedBy == nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	var req models.VideoImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	locale := messages.Locale(c.GetHeader("Accept-Language"))
+	results := make([]models.VideoImportResult, 0, len(req.Rows))
+	emit := func(result models.VideoImportResult, rowErr error) {
+		if rowErr != nil {
+			code, params := videoRowCode(rowErr)
+			result.Code = code
+			result.Error = messages.Message(locale, code, params)
+		}
+		results = append(results, result)
+	}
+
+	summary, err := h.adminService.ImportVideos(c.Request.Context(), req.Rows, *uploadedBy, emit)
+	summary.Results = results
+	if err != nil {
+		messages.RespondWith(c, http.StatusInternalServerError, messages.CodeImportFailed, gin.H{
+			"summary": summary,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// videoRowCode maps a video validation error to its message code and placeholders
+func videoRowCode(err error) (string, gin.H) {
+	switch {
+	case errors.Is(err, services.ErrTrickNotFound):
+		return messages.CodeTrickNotFound, nil
+	case errors.Is(err, services.ErrDuplicateVideo):
+		return messages.CodeDuplicateVideo, nil
+	case errors.Is(err, services.ErrInvalidVideoLabel):
+		return messages.CodeInvalidVideoLabel, gin.H{"max": services.MaxVideoLabelLength}
+	case errors.Is(err, services.ErrInvalidPerformerName):
+		return messages.CodeInvalidPerformerName, nil
+	case errors.Is(err, services.ErrThumbnailRequired):
+		return messages.CodeThumbnailRequired, nil
+	default:
+		return messages.CodeInvalidVideoURL, nil
+	}
+}
+
+// AddAlias gives a trick an alternate name - searchable, and usable in place of its slug
+// Body: models.AliasRequest - {"alias": "Side Somi"}. Returns the stored alias and its slug.
+func (h *AdminHandler) AddAlias(c *gin.Context) {
+	var req models.AliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	alias, err := h.adminService.AddAlias(c.Request.Context(), slugParam(c, "slug"), req.Alias)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTrickNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+		case errors.Is(err, services.ErrInvalidAlias):
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidAlias, gin.H{
+				"max": services.MaxAliasLength,
+			})
+		case errors.Is(err, services.ErrAliasTaken):
+			messages.Respond(c, http.StatusConflict, messages.CodeAliasTaken)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, alias)
+}
+
+// RemoveAlias deletes one of a trick's aliases, addressed by the alias slug
+func (h *AdminHandler) RemoveAlias(c *gin.Context) {
+	err := h.adminService.RemoveAlias(c.Request.Context(), slugParam(c, "slug"), slugParam(c, "alias"))
+	if err != nil {
+		if errors.Is(err, services.ErrAliasNotFound) {
+			messages.Respond(c, http.StatusNotFound, messages.CodeAliasNotFound)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFormerNames lists the names a trick was renamed from, most recent first
+func (h *AdminHandler) GetFormerNames(c *gin.Context) {
+	names, err := h.adminService.GetFormerNames(c.Request.Context(), slugParam(c, "slug"))
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"former_names": names,
+		"count":        len(names),
+	})
+}
+
+// UpdateFormerName corrects the spelling of a former name, addressed by its alias slug
+// Body: models.AliasRequest - {"alias": "Side Somi"}. Returns the stored name and its new slug.
+func (h *AdminHandler) UpdateFormerName(c *gin.Context) {
+	var req models.AliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	name, err := h.adminService.UpdateFormerName(c.Request.Context(), slugParam(c, "slug"), slugParam(c, "alias"), req.Alias)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAliasNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeAliasNotFound)
+		case errors.Is(err, services.ErrInvalidAlias):
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidAlias, gin.H{
+				"max": services.MaxAliasLength,
+			})
+		case errors.Is(err, services.ErrAliasTaken):
+			messages.Respond(c, http.StatusConflict, messages.CodeAliasTaken)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, name)
+}
+
+// RemoveFormerName prunes one of a trick's former names, addressed by its alias slug
+func (h *AdminHandler) RemoveFormerName(c *gin.Context) {
+	err := h.adminService.RemoveFormerName(c.Request.Context(), slugParam(c, "slug"), slugParam(c, "alias"))
+	if err != nil {
+		if errors.Is(err, services.ErrAliasNotFound) {
+			messages.Respond(c, http.StatusNotFound, messages.CodeAliasNotFound)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetTranslations lists a trick's translations
+func (h *AdminHandler) GetTranslations(c *gin.Context) {
+	translations, err := h.adminService.GetTranslations(c.Request.Context(), slugParam(c, "slug"))
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"translations": translations,
+		"count":        len(translations),
+	})
+}
+
+// SaveTranslation creates or replaces a trick's translation into :locale
+// Body: models.TrickTranslationRequest - fields left out stay English on trick details.
+func (h *AdminHandler) SaveTranslation(c *gin.Context) {
+	var req models.TrickTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	translation, err := h.adminService.SaveTranslation(c.Request.Context(), slugParam(c, "slug"), c.Param("locale"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTrickNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+		case errors.Is(err, services.ErrInvalidLocale):
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidLocale, gin.H{"default": services.BaseLocale})
+		case errors.Is(err, services.ErrInvalidTranslationName):
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidTranslationName, gin.H{
+				"max": services.MaxTranslationNameLength,
+			})
+		case errors.Is(err, services.ErrEmptyTranslation):
+			messages.Respond(c, http.StatusBadRequest, messages.CodeEmptyTranslation)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+// DeleteTranslation removes a trick's translation into :locale
+func (h *AdminHandler) DeleteTranslation(c *gin.Context) {
+	err := h.adminService.DeleteTranslation(c.Request.Context(), slugParam(c, "slug"), c.Param("locale"))
+	if err != nil {
+		if errors.Is(err, services.ErrTranslationNotFound) {
+			messages.Respond(c, http.StatusNotFound, messages.CodeTranslationNotFound)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ImportTricks bulk-creates or overwrites tricks from an NDJSON upload
+// The body is read as a stream and the response streams back one result line
+// per input line, then a summary line: {"done": true, "created": ...}.
+// The status is always 200 once streaming starts - a failure part-way shows up
+// as a summary with done=false and a code.
+func (h *AdminHandler) ImportTricks(c *gin.Context) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != ndjsonContentType {
+		messages.Respond(c, http.StatusUnsupportedMediaType, messages.CodeUnsupportedImportFormat)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), importTimeout)
+	defer cancel()
+
+	locale := messages.Locale(c.GetHeader("Accept-Language"))
+	c.Header("Content-Type", ndjsonContentType)
+	c.Header("Content-Language", locale)
+	c.Status(http.StatusOK)
+
+	// No explicit Flush per line: the response writer sends each full buffer,
+	// and a client that stops reading blocks Encode - which pauses the import
+	encoder := json.NewEncoder(c.Writer)
+	emit := func(result models.TrickImportResult, lineErr error) error {
+		if lineErr != nil {
+			code, params := importLineCode(lineErr)
+			result.Code = code
+			result.Error = messages.Message(locale, code, params)
+		}
+		return encoder.Encode(result)
+	}
+
+	summary, err := h.adminService.ImportTricks(ctx, c.Request.Body, actingUserID(c), emit)
+	if err != nil {
+		code := messages.CodeImportFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			code = messages.CodeRequestTimeout
+		}
+		summary.Code = code
+		summary.Error = messages.Message(locale, code, nil)
+	}
+	encoder.Encode(summary)
+}
+
+// importLineCode maps a per-line import error to its message code and placeholders
+func importLineCode(err error) (string, gin.H) {
+	switch {
+	case errors.Is(err, services.ErrImportLineTooLong):
+		return messages.CodeImportLineTooLong, gin.H{"max": services.MaxImportLineBytes}
+	case errors.Is(err, services.ErrInvalidImportSlug):
+		return messages.CodeInvalidImportSlug, nil
+	case errors.Is(err, services.ErrInvalidImportName):
+		return messages.CodeInvalidImportName, gin.H{"max": services.MaxImportNameLength}
+	case errors.Is(err, services.ErrInvalidImportDifficulty):
+		return messages.CodeInvalidImportDifficulty, gin.H{"min": services.MinImportDifficulty, "max": services.MaxImportDifficulty}
+	case errors.Is(err, services.ErrInvalidImportWeight):
+		return messages.CodeInvalidImportWeight, gin.H{"min": services.MinImportWeight, "max": services.MaxImportWeight}
+	case errors.Is(err, services.ErrUnknownImportCategory):
+		return messages.CodeUnknownImportCategory, nil
+	case errors.Is(err, services.ErrImportedTrickDeleted):
+		return messages.CodeImportedTrickDeleted, nil
+	case errors.Is(err, services.ErrTrickNameTaken):
+		return messages.CodeTrickNameTaken, nil
+	case errors.Is(err, services.ErrDuplicateTrick):
+		return messages.CodeTrickSlugTaken, nil
+	default:
+		return messages.CodeInvalidImportLine, nil
+	}
+}
+
+// GetStats returns catalog counts, including how many tricks are currently decayed
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	stats, err := h.adminService.GetStats(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetRuntime describes this instance: build, config, pool limits and live process numbers
+// Same fields as the startup log line, plus uptime - credentials are already redacted
+func (h *AdminHandler) GetRuntime(c *gin.Context) {
+	c.JSON(http.StatusOK, h.runtimeInfo.Status())
+}
+
+// CheckVideoAvailability re-checks one video's external link right now
+// Waits at most videoCheckTimeout for the external host to answer
+func (h *AdminHandler) CheckVideoAvailability(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidVideoID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), videoCheckTimeout)
+	defer cancel()
+
+	result, err := h.videoAvailability.CheckVideo(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrVideoNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeVideoNotFound)
+		case errors.Is(err, context.DeadlineExceeded):
+			messages.Respond(c, http.StatusGatewayTimeout, messages.CodeVideoCheckTimeout)
+		case errors.Is(err, services.ErrAvailabilityCheckFailed):
+			messages.Respond(c, http.StatusBadGateway, messages.CodeVideoCheckFailed)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// actingUserID returns the BFF-supplied user ID for audit records (nil if absent or invalid)
+func actingUserID(c *gin.Context) *uuid.UUID {
+	raw, _ := c.Get("user_id")
+	s, _ := raw.(string)
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// writeCatalogDiffCSV streams the diff as CSV, one change per row
+func writeCatalogDiffCSV(c *gin.Context, diff *models.CatalogDiffResponse) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="catalog-diff.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"entity", "entity_id", "name", "change", "changed_fields", "changed_at"})
+	for _, change := range diff.Changes {
+		w.Write([]string{
+			change.Entity,
+			change.EntityID,
+			change.Name,
+			change.Change,
+			strings.Join(change.ChangedFields, ";"),
+			change.ChangedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+}
+
+// parseTimeParam accepts a full RFC 3339 timestamp or a plain date (midnight UTC)
+func parseTimeParam(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fakes"
+)
+
+func TestImportTricksWeightRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		weight     string
+		locale     string
+		wantStatus string
+		wantWeight int16  // Written weight, for an imported line
+		wantError  string // Message, for a rejected line
+	}{
+		{name: "minimum", weight: "1", wantStatus: models.ImportCreated, wantWeight: 1},
+		{name: "maximum", weight: "32767", wantStatus: models.ImportCreated, wantWeight: 32767},
+		{
+			name: "zero", weight: "0", wantStatus: models.ImportInvalid,
+			wantError: "Invalid weight - must be a whole number between 1 and 32767",
+		},
+		{
+			name: "over the column", weight: "32768", wantStatus: models.ImportInvalid,
+			wantError: "Invalid weight - must be a whole number between 1 and 32767",
+		},
+		{
+			name: "fraction", weight: "1.5", wantStatus: models.ImportInvalid,
+			wantError: "Invalid weight - must be a whole number between 1 and 32767",
+		},
+		{
+			name: "spanish", weight: "40000", locale: "es", wantStatus: models.ImportInvalid,
+			wantError: "Peso inválido - debe ser un número entero entre 1 y 32767",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakes.ImportTrickRepo{Weights: map[string]int16{}}
+			service := services.NewAdminService(repo, nil, nil, nil, nil, &fakes.CategoryRepo{}, false, false, time.Hour, nil)
+			router := gin.New()
+			router.POST("/admin/tricks/import", NewAdminHandler(service, nil, nil, nil, nil).ImportTricks)
+
+			body := `{"slug":"heavy-trick","name":"Heavy Trick","weight":` + tt.weight + "}\n"
+			req := httptest.NewRequest(http.MethodPost, "/admin/tricks/import", strings.NewReader(body))
+			req.Header.Set("Content-Type", ndjsonContentType)
+			req.Header.Set("Accept-Language", tt.locale)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+			}
+			scanner := bufio.NewScanner(w.Body)
+			if !scanner.Scan() {
+				t.Fatal("no result line")
+			}
+			var result models.TrickImportResult
+			if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+				t.Fatalf("decode result line: %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Fatalf("status = %q, want %q: %+v", result.Status, tt.wantStatus, result)
+			}
+			if tt.wantError == "" {
+				if got := repo.Weights["heavy-trick"]; got != tt.wantWeight {
+					t.Errorf("written weight = %d, want %d", got, tt.wantWeight)
+				}
+				return
+			}
+			if result.Code != messages.CodeInvalidImportWeight || result.Error != tt.wantError {
+				t.Errorf("result = %s/%q, want %s/%q", result.Code, result.Error, messages.CodeInvalidImportWeight, tt.wantError)
+			}
+			if len(repo.Weights) != 0 {
+				t.Errorf("rejected line was written: %v", repo.Weights)
+			}
+		})
+	}
+}
+
+func TestImportTricksDuplicates(t *testing.T) {
+	repo := &fakes.ImportTrickRepo{Weights: map[string]int16{}, Taken: map[string]bool{"webster": true, "side-somi": true}}
+	service := services.NewAdminService(repo, nil, nil, nil, nil, &fakes.CategoryRepo{}, false, false, time.Hour, nil)
+	router := gin.New()
+	router.POST("/admin/tricks/import", NewAdminHandler(service, nil, nil, nil, nil).ImportTricks)
+
+	lines := []struct {
+		line     string
+		wantCode string // "" for imported
+	}{
+		{line: `{"slug":"cork","name":"Cork"}`},
+		// Earlier in the same batch, in another case
+		{line: `{"slug":"cork-2","name":"CORK"}`, wantCode: messages.CodeTrickNameTaken},
+		// Another trick's name, an alias's slug
+		{line: `{"slug":"webster-2","name":"Webster"}`, wantCode: messages.CodeTrickNameTaken},
+		{line: `{"slug":"side-somi","name":"Side Somi"}`, wantCode: messages.CodeTrickSlugTaken},
+		{line: `{"slug":"raiz","name":"Raiz"}`},
+	}
+	var body strings.Builder
+	for _, line := range lines {
+		body.WriteString(line.line + "\n")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/tricks/import", strings.NewReader(body.String()))
+	req.Header.Set("Content-Type", ndjsonContentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+
+	results := map[int]models.TrickImportResult{}
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var result models.TrickImportResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("decode result line: %v", err)
+		}
+		results[result.Line] = result
+	}
+
+	for i, line := range lines {
+		result, ok := results[i+1]
+		switch {
+		case !ok:
+			t.Errorf("line %d: no result", i+1)
+		case line.wantCode == "" && result.Status != models.ImportCreated:
+			t.Errorf("line %d = %+v, want created", i+1, result)
+		case line.wantCode != "" && (result.Status != models.ImportInvalid || result.Code != line.wantCode):
+			t.Errorf("line %d = %s/%s, want %s/%s", i+1, result.Status, result.Code, models.ImportInvalid, line.wantCode)
+		}
+	}
+	if len(repo.Weights) != 2 {
+		t.Errorf("wrote %v, want cork and raiz only", repo.Weights)
+	}
+}
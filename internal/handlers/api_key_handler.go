@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+)
+
+// APIKeyHandler handles issuing and revoking external API keys (admin)
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyServiceInterface
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler instance
+func NewAPIKeyHandler(apiKeyService services.APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// IssueKey creates a key for a third party and returns it - the only time the key is shown
+// Body: models.APIKeyCreateRequest - {"owner": "...", "scopes": ["catalog:read"], "rate_tier": "standard"}
+func (h *APIKeyHandler) IssueKey(c *gin.Context) {
+	var req models.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	issued, err := h.apiKeyService.IssueKey(c.Request.Context(), req, actingUserID(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidAPIKeyOwner):
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidAPIKeyOwner, gin.H{
+				"max": services.MaxAPIKeyOwnerLength,
+			})
+		case errors.Is(err, services.ErrInvalidAPIKeyScope):
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidAPIKeyScope, gin.H{
+				"allowed": strings.Join(services.APIKeyScopes, ", "),
+			})
+		case errors.Is(err, services.ErrInvalidAPIKeyTier):
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidAPIKeyTier, gin.H{
+				"allowed": strings.Join([]string{config.APIKeyTierStandard, config.APIKeyTierPartner}, ", "),
+			})
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAPIKeyFailed)
+		}
+		return
+	}
+
+	// The key can't be recovered later - make sure nothing caches the response
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, issued)
+}
+
+// ListKeys returns every key (prefix, owner, scopes, tier, revoked_at) - never the secrets
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAPIKeyFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  keys,
+		"count": len(keys),
+	})
+}
+
+// RevokeKey stops a key from working; revoking it again is a no-op
+// Returns the key with its revoked_at.
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidAPIKeyID)
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeKey(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			messages.Respond(c, http.StatusNotFound, messages.CodeAPIKeyNotFound)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAPIKeyFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
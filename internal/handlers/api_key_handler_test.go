@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/cache"
+	"tricking-api/internal/config"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/middleware"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+)
+
+// fakeAPIKeyTable is the api_keys table, rows by hash
+type fakeAPIKeyTable struct {
+	repository.APIKeyRepositoryInterface
+
+	rows map[string]*models.APIKey
+}
+
+func (r *fakeAPIKeyTable) Insert(ctx context.Context, key *models.APIKey, hash []byte) error {
+	key.ID = int64(len(r.rows) + 1)
+	row := *key
+	r.rows[hex.EncodeToString(hash)] = &row
+	return nil
+}
+
+func (r *fakeAPIKeyTable) FindByHash(ctx context.Context, hash []byte) (*models.APIKey, error) {
+	row, ok := r.rows[hex.EncodeToString(hash)]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	key := *row
+	return &key, nil
+}
+
+func (r *fakeAPIKeyTable) FindAll(ctx context.Context) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	for _, row := range r.rows {
+		keys = append(keys, *row)
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyTable) Revoke(ctx context.Context, id int64) (*models.APIKey, error) {
+	for _, row := range r.rows {
+		if row.ID == id {
+			now := time.Now()
+			row.RevokedAt = &now
+			key := *row
+			return &key, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	tiers := map[string]config.RateLimitConfig{config.APIKeyTierStandard: {RequestsPerSecond: 10, Burst: 10}}
+	table := &fakeAPIKeyTable{rows: map[string]*models.APIKey{}}
+	service := services.NewAPIKeyService(table, cache.NewMemory(), tiers)
+	handler := NewAPIKeyHandler(service)
+
+	router := gin.New()
+	router.POST("/admin/api-keys", handler.IssueKey)
+	router.GET("/admin/api-keys", handler.ListKeys)
+	router.DELETE("/admin/api-keys/:id", handler.RevokeKey)
+	router.GET("/tricks", middleware.NewAPIKeys(service, tiers).Accept(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	send := func(method, path, authorization, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Issue: the key is in this response and nowhere else
+	w := send(http.MethodPost, "/admin/api-keys", "", `{"owner": "Stats Site"}`)
+	if w.Code != http.StatusCreated || w.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("issue = %d, Cache-Control %q: %s", w.Code, w.Header().Get("Cache-Control"), w.Body.String())
+	}
+	var issued models.IssuedAPIKey
+	if err := json.Unmarshal(w.Body.Bytes(), &issued); err != nil || issued.Key == "" {
+		t.Fatalf("issue body = %s (%v)", w.Body.String(), err)
+	}
+	if list := send(http.MethodGet, "/admin/api-keys", "", ""); strings.Contains(list.Body.String(), issued.Key) ||
+		!strings.Contains(list.Body.String(), issued.Prefix) {
+		t.Errorf("key list shows the key or misses its prefix: %s", list.Body.String())
+	}
+
+	// Authenticate
+	if w := send(http.MethodGet, "/tricks", "ApiKey "+issued.Key, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("keyed request = %d: %s", w.Code, w.Body.String())
+	}
+
+	// Revoke: the next request is refused, though the key was cached
+	id := strconv.FormatInt(issued.ID, 10)
+	if w := send(http.MethodDelete, "/admin/api-keys/"+id, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("revoke = %d: %s", w.Code, w.Body.String())
+	}
+	w = send(http.MethodGet, "/tricks", "ApiKey "+issued.Key, "")
+	var body struct {
+		Code string `json:"code"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusUnauthorized || body.Code != messages.CodeInvalidAPIKey {
+		t.Errorf("revoked key = %d %s, want 401 %s", w.Code, body.Code, messages.CodeInvalidAPIKey)
+	}
+
+	// Bad admin input
+	for _, tt := range []struct {
+		method, path, body string
+		wantStatus         int
+		wantCode           string
+	}{
+		{http.MethodPost, "/admin/api-keys", `{"owner": "Stats Site", "rate_tier": "gold"}`, http.StatusBadRequest, messages.CodeInvalidAPIKeyTier},
+		{http.MethodPost, "/admin/api-keys", `{"owner": "Stats Site", "scopes": ["catalog:write"]}`, http.StatusBadRequest, messages.CodeInvalidAPIKeyScope},
+		{http.MethodPost, "/admin/api-keys", `{"owner": " "}`, http.StatusBadRequest, messages.CodeInvalidAPIKeyOwner},
+		{http.MethodDelete, "/admin/api-keys/abc", "", http.StatusBadRequest, messages.CodeInvalidAPIKeyID},
+		{http.MethodDelete, "/admin/api-keys/99", "", http.StatusNotFound, messages.CodeAPIKeyNotFound},
+	} {
+		w := send(tt.method, tt.path, "", tt.body)
+		var body struct {
+			Code string `json:"code"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if w.Code != tt.wantStatus || body.Code != tt.wantCode {
+			t.Errorf("%s %s %s = %d %s, want %d %s", tt.method, tt.path, tt.body, w.Code, body.Code, tt.wantStatus, tt.wantCode)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeDeltaTrickRepo is a catalog with a clock: each change happens at a time,
+// and FindChangesSince returns what happened at or after since, like the
+// REPEATABLE READ query does
+type fakeDeltaTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	now       time.Time
+	tricks    map[string]time.Time // slug -> last change
+	deleted   map[string]time.Time // slug -> deleted at
+	reference time.Time            // last category/stance change
+	err       error
+}
+
+func (r *fakeDeltaTrickRepo) FindChangesSince(ctx context.Context, since time.Time, withTricks, withReferenceData bool) (*repository.TrickChanges, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	changes := &repository.TrickChanges{ServerTime: r.now}
+	for slug, at := range r.tricks {
+		if withTricks && !at.Before(since) {
+			changes.Tricks = append(changes.Tricks, fixtures.CatalogTrick(slug))
+		}
+	}
+	for slug, at := range r.deleted {
+		if !at.Before(since) {
+			changes.DeletedSlugs = append(changes.DeletedSlugs, slug)
+		}
+	}
+	if withReferenceData && !r.reference.Before(since) {
+		changes.Categories, changes.Stances = fixtures.CatalogCategories(), fixtures.CatalogStances()
+	}
+	return changes, nil
+}
+
+// deltaRouter serves GET /catalog/snapshot/delta with a real TrickService over repo
+func deltaRouter(repo *fakeDeltaTrickRepo) *gin.Engine {
+	service := services.NewTrickService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, 7,
+		services.NewDifficultyBands(nil), 0, 30*24*time.Hour, 0, false)
+	router := gin.New()
+	router.GET("/catalog/snapshot/delta", NewTrickHandler(service, nil, nil).GetCatalogDelta)
+	return router
+}
+
+// getDelta calls the delta endpoint, returning the response and its decoded body (nil for none)
+func getDelta(t *testing.T, router *gin.Engine, sinceVersion string) (*httptest.ResponseRecorder, *models.CatalogDeltaResponse) {
+	t.Helper()
+
+	path := "/catalog/snapshot/delta"
+	if sinceVersion != "" {
+		path += "?since_version=" + url.QueryEscape(sinceVersion)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+
+	var delta models.CatalogDeltaResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &delta); err != nil {
+		t.Fatalf("decode delta: %v", err)
+	}
+	if got := w.Header().Get("ETag"); got != `"`+delta.Version+`"` {
+		t.Errorf("ETag = %s, want the version %q", got, delta.Version)
+	}
+	return w, &delta
+}
+
+// slugsOf lists a delta's trick slugs
+func slugsOf(delta *models.CatalogDeltaResponse) []string {
+	slugs := []string{}
+	for _, trick := range delta.Tricks {
+		slugs = append(slugs, trick.ID)
+	}
+	return slugs
+}
+
+func TestCatalogDeltaSync(t *testing.T) {
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	repo := &fakeDeltaTrickRepo{
+		now:       start,
+		tricks:    map[string]time.Time{"cork": start.Add(-time.Hour)},
+		deleted:   map[string]time.Time{},
+		reference: start.Add(-time.Hour),
+	}
+	router := deltaRouter(repo)
+
+	// 1. Full snapshot: everything, flagged full
+	w, full := getDelta(t, router, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("full snapshot status = %d: %s", w.Code, w.Body.String())
+	}
+	if !full.Full || !reflect.DeepEqual(slugsOf(full), []string{"cork"}) || len(full.Categories) == 0 || len(full.Stances) == 0 {
+		t.Errorf("full snapshot = %+v", full)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", w.Header().Get("Cache-Control"))
+	}
+
+	// 2. Nothing happened since: 304, with the new version in the ETag
+	repo.now = start.Add(time.Minute)
+	w, _ = getDelta(t, router, full.Version)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("unchanged status = %d, body %q; want 304 and no body", w.Code, w.Body.String())
+	}
+	unchangedETag := w.Header().Get("ETag")
+	if unchangedETag == "" || unchangedETag == `"`+full.Version+`"` {
+		t.Errorf("304 ETag = %q, want a version newer than %q", unchangedETag, full.Version)
+	}
+
+	// 3. A trick changes and another is deleted: only those come back
+	repo.tricks["aerial"] = start.Add(2 * time.Minute)
+	repo.deleted["webster"] = start.Add(2 * time.Minute)
+	repo.now = start.Add(3 * time.Minute)
+	w, delta := getDelta(t, router, full.Version)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delta status = %d: %s", w.Code, w.Body.String())
+	}
+	if delta.Full || !reflect.DeepEqual(slugsOf(delta), []string{"aerial"}) ||
+		!reflect.DeepEqual(delta.DeletedSlugs, []string{"webster"}) || len(delta.Categories) != 0 || len(delta.Stances) != 0 {
+		t.Errorf("delta = %+v, want aerial changed and webster deleted only", delta)
+	}
+
+	// 4. The next delta starts from the version just handed out
+	repo.now = start.Add(4 * time.Minute)
+	if w, _ := getDelta(t, router, delta.Version); w.Code != http.StatusNotModified {
+		t.Errorf("after catching up status = %d, want 304: %s", w.Code, w.Body.String())
+	}
+
+	// 5. Stances changing is a change too
+	repo.reference = start.Add(5 * time.Minute)
+	repo.now = start.Add(6 * time.Minute)
+	if w, next := getDelta(t, router, delta.Version); w.Code != http.StatusOK || len(next.Stances) == 0 || len(next.Tricks) != 0 {
+		t.Errorf("reference data change status = %d, delta = %+v", w.Code, next)
+	}
+}
+
+func TestCatalogDeltaFullRequired(t *testing.T) {
+	version := func(format int, at time.Time) string {
+		data := fmt.Sprintf(`{"v":%d,"at":%d}`, format, at.Unix())
+		return base64.RawURLEncoding.EncodeToString([]byte(data))
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		sinceVersion string
+		repoErr      error
+		wantStatus   int
+		wantCode     string
+	}{
+		{name: "garbage", sinceVersion: "not-a-version!", wantStatus: http.StatusConflict, wantCode: messages.CodeFullSnapshotRequired},
+		{name: "not json", sinceVersion: base64.RawURLEncoding.EncodeToString([]byte("v1")), wantStatus: http.StatusConflict, wantCode: messages.CodeFullSnapshotRequired},
+		{name: "other format", sinceVersion: version(2, now.Add(-time.Hour)), wantStatus: http.StatusConflict, wantCode: messages.CodeFullSnapshotRequired},
+		{name: "older than tombstones", sinceVersion: version(1, now.Add(-31*24*time.Hour)), wantStatus: http.StatusConflict, wantCode: messages.CodeFullSnapshotRequired},
+		{name: "from the future", sinceVersion: version(1, now.Add(time.Hour)), wantStatus: http.StatusConflict, wantCode: messages.CodeFullSnapshotRequired},
+		{name: "within retention", sinceVersion: version(1, now.Add(-29*24*time.Hour)), wantStatus: http.StatusNotModified},
+		{name: "repository error", sinceVersion: version(1, now.Add(-time.Hour)), repoErr: errors.New("db down"), wantStatus: http.StatusInternalServerError, wantCode: messages.CodeCatalogDeltaFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := deltaRouter(&fakeDeltaTrickRepo{now: now, err: tt.repoErr})
+
+			w, _ := getDelta(t, router, tt.sinceVersion)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+
+			var body struct {
+				Code         string `json:"code"`
+				FullRequired bool   `json:"full_required"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode error body: %v", err)
+			}
+			wantFull := tt.wantStatus == http.StatusConflict
+			if body.Code != tt.wantCode || body.FullRequired != wantFull {
+				t.Errorf("body = %+v, want code %s, full_required %v", body, tt.wantCode, wantFull)
+			}
+		})
+	}
+}
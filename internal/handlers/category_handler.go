@@ -1,30 +1,34 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"tricking-api/internal/messages"
 	"tricking-api/internal/services"
 )
 
 // CategoryHandler handles HTTP requests for category endpoints
 type CategoryHandler struct {
 	categoryService services.CategoryServiceInterface
+
+	// bands translates ?band= into a difficulty range
+	bands *services.DifficultyBands
 }
 
 // NewCategoryHandler creates a new CategoryHandler instance
-func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHandler {
-	return &CategoryHandler{categoryService: categoryService}
+func NewCategoryHandler(categoryService *services.CategoryService, bands *services.DifficultyBands) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService, bands: bands}
 }
 
 // ListCategories returns all trick categories
 func (h *CategoryHandler) ListCategories(c *gin.Context) {
 	categories, err := h.categoryService.GetAllCategories(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve categories",
-		})
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeCategoriesFailed)
 		return
 	}
 
@@ -33,3 +37,37 @@ func (h *CategoryHandler) ListCategories(c *gin.Context) {
 		"count":      len(categories),
 	})
 }
+
+// ListCategoryTricks returns the tricks in one category
+// Optional ?min_difficulty= / ?max_difficulty= / ?band= work as on GET /tricks
+// (tricks without a difficulty are left out when either is set)
+func (h *CategoryHandler) ListCategoryTricks(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || categoryID < 1 {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidCategoryID)
+		return
+	}
+
+	minDifficulty, maxDifficulty, ok := difficultyRangeQuery(c, h.bands)
+	if !ok {
+		return
+	}
+
+	tricks, err := h.categoryService.GetTricksForCategory(c.Request.Context(), categoryID, minDifficulty, maxDifficulty)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCategoryNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeCategoryNotFound)
+		case errors.Is(err, services.ErrInvalidDifficultyRange):
+			messages.Respond(c, http.StatusBadRequest, messages.CodeDifficultyRange)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeTricksFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": tricks,
+		"count":  len(tricks),
+	})
+}
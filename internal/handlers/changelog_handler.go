@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/changelog"
+)
+
+// ChangelogHandler handles HTTP requests for the changelog endpoint
+type ChangelogHandler struct {
+	changelog *changelog.Changelog
+}
+
+// NewChangelogHandler creates a new ChangelogHandler instance
+func NewChangelogHandler(cl *changelog.Changelog) *ChangelogHandler {
+	return &ChangelogHandler{changelog: cl}
+}
+
+// GetChangelog returns the API changelog, newest release first
+func (h *ChangelogHandler) GetChangelog(c *gin.Context) {
+	// The changelog is compiled into the binary, so the ETag only changes on deploy
+	if c.GetHeader("If-None-Match") == h.changelog.ETag {
+		c.Header("ETag", h.changelog.ETag)
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("ETag", h.changelog.ETag)
+
+	c.JSON(http.StatusOK, gin.H{
+		"meta": gin.H{
+			"version": h.changelog.CurrentVersion(),
+		},
+		"releases": h.changelog.Releases,
+	})
+}
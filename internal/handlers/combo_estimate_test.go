@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeEstimateTrickRepo serves the batched lookup only - any other call
+// (including every write) panics on the nil embedded interface
+type fakeEstimateTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	tricks map[string]models.Trick // By slug or legacy ID
+	err    error
+
+	calls [][]string
+}
+
+func (r *fakeEstimateTrickRepo) GetByIdentifiers(ctx context.Context, ids []string) (map[string]models.Trick, error) {
+	r.calls = append(r.calls, append([]string(nil), ids...))
+	if r.err != nil {
+		return nil, r.err
+	}
+	found := make(map[string]models.Trick)
+	for _, id := range ids {
+		if trick, ok := r.tricks[id]; ok {
+			found[id] = trick
+		}
+	}
+	return found, nil
+}
+
+func estimateTricks() map[string]models.Trick {
+	btwist := fixtures.Trick().WithSlug("btwist").WithDifficulty(6).
+		WithStances(fixtures.StanceComplete, fixtures.StanceHyper).Build()
+	cork := fixtures.Trick().WithSlug("cork").WithDifficulty(8).
+		WithStances(fixtures.StanceHyper, fixtures.StanceHyper).Build()
+	gainer := fixtures.Trick().WithSlug("gainer").WithDifficulty(4).
+		WithStances(fixtures.StanceComplete, fixtures.StanceComplete).Build()
+	backflip := fixtures.Trick().WithSlug("backflip").WithDifficulty(2).Build() // No stances recorded
+
+	return map[string]models.Trick{
+		"btwist": btwist, "cork": cork, "gainer": gainer, "backflip": backflip,
+		"42": backflip, // Legacy numeric ID
+	}
+}
+
+func TestEstimateCombo(t *testing.T) {
+	transition := func(from, to, compatibility string) models.ComboTransition {
+		return models.ComboTransition{From: from, To: to, Compatibility: compatibility}
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		repoErr    error
+		wantStatus int
+		wantLookup []string // IDs passed to the one batched lookup, nil for none
+		want       models.ComboEstimateResponse
+		wantCode   string
+	}{
+		{
+			name:       "matching stances",
+			body:       `{"tricks": ["btwist", "cork"]}`,
+			wantStatus: http.StatusOK,
+			wantLookup: []string{"btwist", "cork"},
+			want: models.ComboEstimateResponse{
+				TotalDifficulty: 14, Grade: "A",
+				Transitions: []models.ComboTransition{transition("btwist", "cork", "match")},
+			},
+		},
+		{
+			name:       "stance mismatch drops a grade",
+			body:       `{"tricks": ["cork", "gainer"]}`,
+			wantStatus: http.StatusOK,
+			wantLookup: []string{"cork", "gainer"},
+			want: models.ComboEstimateResponse{
+				TotalDifficulty: 12, Grade: "B",
+				Transitions: []models.ComboTransition{transition("cork", "gainer", "mismatch")},
+			},
+		},
+		{
+			name:       "unknown tricks are counted and skipped",
+			body:       `{"tricks": ["btwist", "no-such-trick", "cork"]}`,
+			wantStatus: http.StatusOK,
+			wantLookup: []string{"btwist", "no-such-trick", "cork"},
+			want: models.ComboEstimateResponse{
+				TotalDifficulty: 14, Grade: "A", UnknownCount: 1,
+				Transitions: []models.ComboTransition{transition("btwist", "cork", "match")},
+			},
+		},
+		{
+			name:       "legacy ID and unnormalized slug",
+			body:       `{"tricks": ["42", " CORK "]}`,
+			wantStatus: http.StatusOK,
+			wantLookup: []string{"42", "cork"},
+			want: models.ComboEstimateResponse{
+				TotalDifficulty: 10, Grade: "B",
+				Transitions: []models.ComboTransition{transition("backflip", "cork", "unknown")},
+			},
+		},
+		{
+			name:       "nothing known",
+			body:       `{"tricks": ["nope", "also-nope"]}`,
+			wantStatus: http.StatusOK,
+			wantLookup: []string{"nope", "also-nope"},
+			want: models.ComboEstimateResponse{
+				Grade: "D", UnknownCount: 2, Transitions: []models.ComboTransition{},
+			},
+		},
+		{
+			name:       "empty list",
+			body:       `{"tricks": []}`,
+			wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidRequest,
+		},
+		{
+			name:       "more than ten tricks",
+			body:       `{"tricks": ["a","b","c","d","e","f","g","h","i","j","k"]}`,
+			wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidRequest,
+		},
+		{
+			name:       "blank trick",
+			body:       `{"tricks": ["cork", ""]}`,
+			wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidRequest,
+		},
+		{
+			name:       "malformed JSON",
+			body:       `{"tricks": `,
+			wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidRequest,
+		},
+		{
+			name:       "repository error",
+			body:       `{"tricks": ["cork"]}`,
+			repoErr:    errors.New("db down"),
+			wantStatus: http.StatusInternalServerError,
+			wantLookup: []string{"cork"},
+			wantCode:   messages.CodeEstimateFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeEstimateTrickRepo{tricks: estimateTricks(), err: tt.repoErr}
+			handler := NewComboHandler(services.NewComboService(repo, nil, nil), nil)
+			router := gin.New()
+			router.POST("/combos/estimate", handler.EstimateCombo)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/combos/estimate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			// Always a single query, and none at all for a rejected body
+			switch {
+			case tt.wantLookup == nil && len(repo.calls) != 0:
+				t.Errorf("lookups = %v, want none", repo.calls)
+			case tt.wantLookup != nil && (len(repo.calls) != 1 || !reflect.DeepEqual(repo.calls[0], tt.wantLookup)):
+				t.Errorf("lookups = %v, want one for %v", repo.calls, tt.wantLookup)
+			}
+
+			if tt.wantCode != "" {
+				var body struct {
+					Code string `json:"code"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != tt.wantCode {
+					t.Errorf("code = %q (%v), want %q", body.Code, err, tt.wantCode)
+				}
+				return
+			}
+
+			var got models.ComboEstimateResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("estimate = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
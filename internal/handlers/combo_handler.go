@@ -3,59 +3,98 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"tricking-api/internal/comboalg"
+	"tricking-api/internal/handlers/params"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/metrics"
 	"tricking-api/internal/models"
 	"tricking-api/internal/services"
 )
 
+// generationFailures counts failed combo generations by reason
+// Reasons come from services.GenerationError (insufficient_candidates, invalid_size, ...)
+var generationFailures = metrics.NewCounterVec(
+	"generation_failures_total",
+	"Combo generation requests that failed, labelled by reason",
+	"reason",
+)
+
 // ComboHandler handles HTTP requests for combo endpoints
 type ComboHandler struct {
 	comboService services.ComboServiceInterface
+
+	// bands translates ?band= into a difficulty range (GET /tricks/random)
+	bands *services.DifficultyBands
 }
 
 // NewComboHandler creates a new ComboHandler instance
-func NewComboHandler(comboService services.ComboServiceInterface) *ComboHandler {
-	return &ComboHandler{comboService: comboService}
+func NewComboHandler(comboService services.ComboServiceInterface, bands *services.DifficultyBands) *ComboHandler {
+	return &ComboHandler{comboService: comboService, bands: bands}
 }
 
+// Caps on the repeated ID params of combo generation
+const (
+	maxCategoryIDs     = 50
+	maxTrickIDs        = 10 // A combo has at most 10 tricks
+	maxExcludeTrickIDs = 100
+	maxRandomExcludes  = 20 // "the last few tricks" - not a second catalog filter
+)
+
 // GenerateComboWithFilters creates a new random combo based on filters
+// ?notation_style=arrows|dashes|numbered picks how the response's notation is written.
 func (h *ComboHandler) GenerateComboWithFilters(c *gin.Context) {
 	var req models.ComboGenerateRequest
 
 	// ShouldBindQuery also performs validation based on `binding` struct tags
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request parameters",
+		generationFailures.Inc(services.ReasonInvalidSize)
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
 			// Include validation details in development, hide in production
 			"details": err.Error(),
 		})
 		return
 	}
+
+	// The repeated ID lists aren't bound above - params caps and de-duplicates them
+	var err error
+	if req.ExcludeCategoryIDs, err = params.IntList(c, "category_ids", maxCategoryIDs); err != nil {
+		params.Respond(c, err)
+		return
+	}
+	if req.TrickIDs, err = params.IntList(c, "trick_ids", maxTrickIDs); err != nil {
+		params.Respond(c, err)
+		return
+	}
+	if req.ExcludeTrickIDs, err = params.IntList(c, "exclude_trick_ids", maxExcludeTrickIDs); err != nil {
+		params.Respond(c, err)
+		return
+	}
+	if req.NotationStyle, err = params.OneOf(c, "notation_style", comboalg.NotationStyles, string(comboalg.NotationArrows)); err != nil {
+		params.Respond(c, err)
+		return
+	}
 	// Generate the combo
 	combo, err := h.comboService.GenerateComboWithFilters(c.Request.Context(), req)
 	if err != nil {
+		generationFailures.Inc(services.GenerationFailureReason(err))
+
 		// Check for specific errors
 		if errors.Is(err, services.ErrInsufficientTricks) {
 			// 422 Unprocessable Entity - request is valid but can't be fulfilled
-			c.JSON(http.StatusUnprocessableEntity, gin.H{
-				"error": err.Error(),
-			})
+			messages.Respond(c, http.StatusUnprocessableEntity, messages.CodeInsufficientTricks)
 			return
 		}
 
 		if errors.Is(err, services.ErrInvalidComboSize) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidComboSize)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to generate combo",
-		})
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeGenerationFailed)
 		return
 	}
 
@@ -63,37 +102,105 @@ func (h *ComboHandler) GenerateComboWithFilters(c *gin.Context) {
 }
 
 // GenerateSimpleCombo creates a new random combo based only on size
+// It takes ?notation_style= like GenerateComboWithFilters.
 func (h *ComboHandler) GenerateSimpleCombo(c *gin.Context) {
-	//sizeStr := c.Query("size") // Returns empty string if not present
-	sizeStr := c.DefaultQuery("size", "3") // Returns "3" if not present
+	// ?size= defaults to 3 when not present
+	size, err := params.BoundedInt(c, "size", 3, 10, 3)
+	if err != nil {
+		generationFailures.Inc(services.ReasonInvalidSize)
+		params.Respond(c, err)
+		return
+	}
 
-	size, err := strconv.Atoi(sizeStr)
-	if err != nil || size < 3 || size > 10 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size"})
+	notationStyle, err := params.OneOf(c, "notation_style", comboalg.NotationStyles, string(comboalg.NotationArrows))
+	if err != nil {
+		params.Respond(c, err)
 		return
 	}
 
-	combo, err := h.comboService.GenerateSimpleCombo(c.Request.Context(), size)
+	combo, err := h.comboService.GenerateSimpleCombo(c.Request.Context(), size, notationStyle)
 	if err != nil {
+		generationFailures.Inc(services.GenerationFailureReason(err))
+
 		if errors.Is(err, services.ErrInsufficientTricks) {
-			c.JSON(http.StatusUnprocessableEntity, gin.H{
-				"error": err.Error(),
-			})
+			messages.Respond(c, http.StatusUnprocessableEntity, messages.CodeInsufficientTricks)
 			return
 		}
 
 		if errors.Is(err, services.ErrInvalidComboSize) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidComboSize)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeGenerationFailed)
 		return
 	}
 
 	c.JSON(http.StatusOK, combo)
 }
+
+// EstimateCombo scores a combo the user is putting together - nothing is generated or saved
+// Body: models.ComboEstimateRequest - {"tricks": ["btwist", "cork", "42"]}, 1-10 slugs or legacy IDs.
+// Unknown tricks are skipped and counted in unknown_count rather than failing the request.
+func (h *ComboHandler) EstimateCombo(c *gin.Context) {
+	var req models.ComboEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Slugs are lowercase everywhere else in the URL space too
+	for i, id := range req.Tricks {
+		req.Tricks[i] = strings.ToLower(strings.TrimSpace(id))
+	}
+
+	estimate, err := h.comboService.EstimateCombo(c.Request.Context(), req.Tricks)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeEstimateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// GetRandomTrick returns one weighted-random trick for warm-up drills
+// Accepts the GET /tricks filters plus ?exclude=slug1,slug2 to skip recent picks.
+// 422 when nothing is left after filtering - the request is valid, just unsatisfiable.
+func (h *ComboHandler) GetRandomTrick(c *gin.Context) {
+	var filter models.RandomTrickFilter
+	var ok bool
+	if filter.MinDifficulty, filter.MaxDifficulty, ok = difficultyRangeQuery(c, h.bands); !ok {
+		return
+	}
+	if filter.TakeoffStanceID, ok = stanceQuery(c, "takeoff_stance_id"); !ok {
+		return
+	}
+	if filter.LandingStanceID, ok = stanceQuery(c, "landing_stance_id"); !ok {
+		return
+	}
+
+	var err error
+	if filter.Exclude, err = params.StringList(c, "exclude", maxRandomExcludes); err != nil {
+		params.Respond(c, err)
+		return
+	}
+
+	trick, err := h.comboService.PickRandomTrick(c.Request.Context(), filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidDifficultyRange):
+			messages.Respond(c, http.StatusBadRequest, messages.CodeDifficultyRange)
+		case errors.Is(err, services.ErrNoMatchingTrick):
+			messages.Respond(c, http.StatusUnprocessableEntity, messages.CodeNoMatchingTrick)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeRandomTrickFailed)
+		}
+		return
+	}
+
+	// Every request should draw again - never let a cache pin one trick
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, trick)
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/comboalg"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/metrics"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeComboService fails every generation with err
+type fakeComboService struct {
+	services.ComboServiceInterface
+
+	err error
+}
+
+func (s *fakeComboService) GenerateComboWithFilters(ctx context.Context, req models.ComboGenerateRequest) (*models.GeneratedComboResponse, error) {
+	return nil, s.err
+}
+
+func (s *fakeComboService) GenerateSimpleCombo(ctx context.Context, size int, notationStyle string) (*models.GeneratedComboResponse, error) {
+	return nil, s.err
+}
+
+// counterValue reads one series from the metrics text output (0 if absent)
+func counterValue(t *testing.T, series string) uint64 {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := metrics.WriteText(&buf); err != nil {
+		t.Fatalf("metrics.WriteText() error = %v", err)
+	}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), series+" "); ok {
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				t.Fatalf("bad value for %s: %q", series, value)
+			}
+			return n
+		}
+	}
+	return 0
+}
+
+func TestGenerationFailureMetrics(t *testing.T) {
+	// Wrapped exactly the way ComboService wraps them
+	serviceErr := func(reason string, err error) error {
+		return &services.GenerationError{Reason: reason, Err: err}
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+		wantReason string
+	}{
+		{
+			name:       "filtered insufficient candidates",
+			path:       "/combos/generate?size=5",
+			err:        serviceErr(services.ReasonInsufficientCandidates, services.ErrInsufficientTricks),
+			wantStatus: http.StatusUnprocessableEntity, wantReason: services.ReasonInsufficientCandidates,
+		},
+		{
+			name:       "filtered repo error",
+			path:       "/combos/generate?size=5",
+			err:        serviceErr(services.ReasonRepoError, errors.New("db down")),
+			wantStatus: http.StatusInternalServerError, wantReason: services.ReasonRepoError,
+		},
+		{
+			name:       "filtered unwrapped error counts as repo error",
+			path:       "/combos/generate?size=5",
+			err:        errors.New("unexpected"),
+			wantStatus: http.StatusInternalServerError, wantReason: services.ReasonRepoError,
+		},
+		{
+			name:       "filtered invalid size from binding",
+			path:       "/combos/generate?size=99",
+			wantStatus: http.StatusBadRequest, wantReason: services.ReasonInvalidSize,
+		},
+		{
+			name:       "filtered invalid size from service",
+			path:       "/combos/generate?size=2",
+			err:        serviceErr(services.ReasonInvalidSize, services.ErrInvalidComboSize),
+			wantStatus: http.StatusBadRequest, wantReason: services.ReasonInvalidSize,
+		},
+		{
+			name:       "simple insufficient candidates",
+			path:       "/combos/generate/simple/5?size=5",
+			err:        serviceErr(services.ReasonInsufficientCandidates, services.ErrInsufficientTricks),
+			wantStatus: http.StatusUnprocessableEntity, wantReason: services.ReasonInsufficientCandidates,
+		},
+		{
+			name:       "simple invalid size",
+			path:       "/combos/generate/simple/11?size=11",
+			wantStatus: http.StatusBadRequest, wantReason: services.ReasonInvalidSize,
+		},
+		{
+			name:       "simple repo error",
+			path:       "/combos/generate/simple/3",
+			err:        serviceErr(services.ReasonRepoError, errors.New("db down")),
+			wantStatus: http.StatusInternalServerError, wantReason: services.ReasonRepoError,
+		},
+	}
+
+	reasons := []string{
+		services.ReasonInsufficientCandidates, services.ReasonQuotaUnsatisfiable, services.ReasonBudgetUnsatisfiable,
+		services.ReasonInvalidSize, services.ReasonRepoError,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewComboHandler(&fakeComboService{err: tt.err}, nil)
+			router := gin.New()
+			router.GET("/combos/generate", handler.GenerateComboWithFilters)
+			router.GET("/combos/generate/simple/:size", handler.GenerateSimpleCombo)
+
+			before := map[string]uint64{}
+			for _, reason := range reasons {
+				before[reason] = counterValue(t, `generation_failures_total{reason="`+reason+`"}`)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			for _, reason := range reasons {
+				want := before[reason]
+				if reason == tt.wantReason {
+					want++
+				}
+				if got := counterValue(t, `generation_failures_total{reason="`+reason+`"}`); got != want {
+					t.Errorf("generation_failures_total{reason=%q} = %d, want %d", reason, got, want)
+				}
+			}
+		})
+	}
+}
+
+// fakeNotationTrickRepo serves a few tricks with non-ASCII names as candidates
+type fakeNotationTrickRepo struct {
+	repository.TrickRepositoryInterface
+}
+
+func (r *fakeNotationTrickRepo) notationTricks() []models.Trick {
+	tricks := fixtures.CatalogTricks()[:5]
+	for i, name := range []string{"Mortal atrás", "Bütterfly Twist", "旋子", "Cork 🔥", "Gainer"} {
+		tricks[i].Name = name
+	}
+	return tricks
+}
+
+func (r *fakeNotationTrickRepo) FindAll(ctx context.Context) ([]models.Trick, error) {
+	return r.notationTricks(), nil
+}
+
+func (r *fakeNotationTrickRepo) FindByFilters(ctx context.Context, filters repository.TrickFilters) ([]models.Trick, error) {
+	return r.notationTricks(), nil
+}
+
+func TestGenerateComboNotationStyle(t *testing.T) {
+	// joinNames writes the response's tricks the way each style should
+	joinNames := map[string]func(names []string) string{
+		"arrows": func(names []string) string { return strings.Join(names, " > ") },
+		"dashes": func(names []string) string { return strings.Join(names, " - ") },
+		"numbered": func(names []string) string {
+			lines := make([]string, len(names))
+			for i, name := range names {
+				lines[i] = strconv.Itoa(i+1) + ". " + name
+			}
+			return strings.Join(lines, "\n")
+		},
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantStyle string // "" for a 400
+		wantValue string
+	}{
+		{name: "simple default", path: "/combos/generate/simple/3", wantStyle: "arrows"},
+		{name: "simple numbered", path: "/combos/generate/simple/4?size=4&notation_style=numbered", wantStyle: "numbered"},
+		{name: "filtered dashes", path: "/combos/generate?size=3&notation_style=dashes", wantStyle: "dashes"},
+		{name: "filtered arrows", path: "/combos/generate?size=5&notation_style=arrows", wantStyle: "arrows"},
+		{name: "simple unknown", path: "/combos/generate/simple/3?notation_style=slashes", wantValue: "slashes"},
+		{name: "filtered unknown", path: "/combos/generate?size=3&notation_style=zigzag", wantValue: "zigzag"},
+		{name: "styles are case sensitive", path: "/combos/generate?size=3&notation_style=Numbered", wantValue: "Numbered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewComboHandler(services.NewComboService(&fakeNotationTrickRepo{}, nil, nil), nil)
+			router := gin.New()
+			router.GET("/combos/generate", handler.GenerateComboWithFilters)
+			router.GET("/combos/generate/simple/:size", handler.GenerateSimpleCombo)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if tt.wantStyle == "" {
+				var body struct {
+					Code    string   `json:"code"`
+					Field   string   `json:"field"`
+					Value   string   `json:"value"`
+					Allowed []string `json:"allowed"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("decode error body: %v", err)
+				}
+				if w.Code != http.StatusBadRequest || body.Code != messages.CodeUnknownField || body.Field != "notation_style" ||
+					body.Value != tt.wantValue || !reflect.DeepEqual(body.Allowed, comboalg.NotationStyles) {
+					t.Errorf("got %d %+v, want 400 unknown_field listing %v", w.Code, body, comboalg.NotationStyles)
+				}
+				return
+			}
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+			}
+			var combo models.GeneratedComboResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &combo); err != nil {
+				t.Fatalf("decode combo: %v", err)
+			}
+			names := make([]string, len(combo.Tricks))
+			for i, trick := range combo.Tricks {
+				names[i] = trick.Name
+			}
+			if want := joinNames[tt.wantStyle](names); combo.Notation != want {
+				t.Errorf("notation = %q, want %q", combo.Notation, want)
+			}
+			if combo.AppliedConstraints.NotationStyle != tt.wantStyle {
+				t.Errorf("applied notation_style = %q, want %q", combo.AppliedConstraints.NotationStyle, tt.wantStyle)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// comboStoreTrick is a row of trick_data.tricks as combo_tricks sees it
+type comboStoreTrick struct {
+	id         int
+	slug       string
+	name       string
+	difficulty int64
+}
+
+// comboStore is the combos and combo_tricks tables: tricks are stored by
+// integer ID, and every read joins back to the slug like the SQL does
+type comboStore struct {
+	tricks []comboStoreTrick
+	combos []models.Combo
+	rows   map[int64][]repository.ComboTrick
+}
+
+func newComboStore() *comboStore {
+	return &comboStore{
+		tricks: []comboStoreTrick{
+			{id: 1, slug: "cork", name: "Cork", difficulty: 6},
+			{id: 2, slug: "gainer", name: "Gainer", difficulty: 3},
+			{id: 3, slug: "butterfly-twist", name: "Butterfly Twist", difficulty: 5},
+		},
+		rows: map[int64][]repository.ComboTrick{},
+	}
+}
+
+// joined is a combo's tricks as the read queries return them
+func (s *comboStore) joined(comboID int64) []models.ComboTrickResponse {
+	var tricks []models.ComboTrickResponse
+	for i, row := range s.rows[comboID] {
+		for _, trick := range s.tricks {
+			if trick.id == row.TrickID {
+				tricks = append(tricks, models.ComboTrickResponse{
+					ID: trick.slug, TrickID: int64(trick.id), Slug: trick.slug, Name: trick.name,
+					Difficulty: fixtures.Ptr(trick.difficulty), Position: i + 1, Note: row.Note,
+				})
+			}
+		}
+	}
+	return tricks
+}
+
+func (s *comboStore) summary(combo models.Combo) models.ComboSummary {
+	summary := models.ComboSummary{Combo: combo}
+	for _, trick := range s.joined(combo.ID) {
+		summary.TrickCount++
+		summary.TotalDifficulty += *trick.Difficulty
+	}
+	return summary
+}
+
+// fakeStoreComboRepo is the combo repository over a comboStore
+type fakeStoreComboRepo struct {
+	repository.ComboRepositoryInterface
+
+	store *comboStore
+}
+
+func (r *fakeStoreComboRepo) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	return len(r.store.combos), nil
+}
+
+// ResolveTrickIDs matches a slug first, then a legacy numeric ID
+func (r *fakeStoreComboRepo) ResolveTrickIDs(ctx context.Context, ids []string) (map[string]int, error) {
+	resolved := make(map[string]int)
+	for _, ident := range ids {
+		for _, trick := range r.store.tricks {
+			if trick.slug == ident {
+				resolved[ident] = trick.id
+			}
+		}
+		if _, ok := resolved[ident]; ok {
+			continue
+		}
+		for _, trick := range r.store.tricks {
+			if strconv.Itoa(trick.id) == ident {
+				resolved[ident] = trick.id
+			}
+		}
+	}
+	return resolved, nil
+}
+
+func (r *fakeStoreComboRepo) Create(ctx context.Context, userID uuid.UUID, name string, notes *string, tricks []repository.ComboTrick) (*models.Combo, error) {
+	combo := models.Combo{ID: int64(len(r.store.combos) + 1), UserID: userID, Name: name, Notes: notes, CreatedAt: fixtures.Epoch}
+	r.store.combos = append(r.store.combos, combo)
+	r.store.rows[combo.ID] = tricks
+	return &combo, nil
+}
+
+func (r *fakeStoreComboRepo) GetTricksForCombo(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error) {
+	return r.store.joined(comboID), nil
+}
+
+func (r *fakeStoreComboRepo) GetByIDs(ctx context.Context, ids []int64) ([]models.ComboSummary, error) {
+	var summaries []models.ComboSummary
+	for _, combo := range r.store.combos {
+		for _, id := range ids {
+			if combo.ID == id {
+				summaries = append(summaries, r.store.summary(combo))
+			}
+		}
+	}
+	return summaries, nil
+}
+
+func (r *fakeStoreComboRepo) GetTricksForCombos(ctx context.Context, comboIDs []int64) (map[int64][]models.ComboTrickResponse, error) {
+	tricks := make(map[int64][]models.ComboTrickResponse, len(comboIDs))
+	for _, id := range comboIDs {
+		tricks[id] = r.store.joined(id)
+	}
+	return tricks, nil
+}
+
+// fakeStoreUserRepo is the user repository's combo reads over a comboStore
+type fakeStoreUserRepo struct {
+	repository.UserRepositoryInterface
+
+	store *comboStore
+}
+
+func (r *fakeStoreUserRepo) GetComboSummariesByUserID(ctx context.Context, userID uuid.UUID) ([]models.ComboSummary, error) {
+	var summaries []models.ComboSummary
+	for _, combo := range r.store.combos {
+		if combo.UserID == userID {
+			summaries = append(summaries, r.store.summary(combo))
+		}
+	}
+	return summaries, nil
+}
+
+func (r *fakeStoreUserRepo) GetComboTricks(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error) {
+	return r.store.joined(comboID), nil
+}
+
+func TestComboSlugRoundTrip(t *testing.T) {
+	userID := uuid.New()
+	store := newComboStore()
+	handler := NewUserHandler(services.NewUserService(&fakeStoreUserRepo{store: store}, &fakeStoreComboRepo{store: store},
+		config.ComboLimitConfig{Default: 5}))
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+	})
+	router.GET("/users/:userId/combos", handler.GetUserCombos)
+	router.POST("/users/:userId/combos", handler.CreateCombo)
+	router.POST("/combos/batch-get", handler.BatchGetCombos)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	combosPath := "/users/" + userID.String() + "/combos"
+
+	// Saved by slug, plus a numeric ID from an older response
+	w := send(http.MethodPost, combosPath,
+		`{"name": "Flow", "tricks": [{"trick_id": "cork", "note": "high"}, {"trick_id": "2"}, {"trick_id": "butterfly-twist"}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", w.Code, w.Body.String())
+	}
+	var created models.ComboResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	want := []models.ComboTrickResponse{
+		{ID: "cork", TrickID: 1, Slug: "cork", Name: "Cork", Difficulty: fixtures.Ptr[int64](6), Position: 1, Note: fixtures.Ptr("high")},
+		{ID: "gainer", TrickID: 2, Slug: "gainer", Name: "Gainer", Difficulty: fixtures.Ptr[int64](3), Position: 2},
+		{ID: "butterfly-twist", TrickID: 3, Slug: "butterfly-twist", Name: "Butterfly Twist", Difficulty: fixtures.Ptr[int64](5), Position: 3},
+	}
+	if !reflect.DeepEqual(created.Tricks, want) {
+		t.Errorf("create tricks = %+v, want %+v", created.Tricks, want)
+	}
+	if created.TrickCount != 3 || created.TotalDifficulty != 14 {
+		t.Errorf("create totals = %d tricks, difficulty %d; want 3 and 14", created.TrickCount, created.TotalDifficulty)
+	}
+
+	// combo_tricks holds the integer keys, resolved before the write
+	var stored []int
+	for _, row := range store.rows[created.ID] {
+		stored = append(stored, row.TrickID)
+	}
+	if !reflect.DeepEqual(stored, []int{1, 2, 3}) {
+		t.Errorf("stored trick IDs = %v, want [1 2 3]", stored)
+	}
+
+	// List
+	w = send(http.MethodGet, combosPath, "")
+	var list struct {
+		Combos []models.ComboResponse `json:"combos"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("list = %d (%v): %s", w.Code, err, w.Body.String())
+	}
+	if len(list.Combos) != 1 || list.Combos[0].ID != created.ID || !reflect.DeepEqual(list.Combos[0].Tricks, want) {
+		t.Errorf("list = %+v, want the saved combo with %+v", list.Combos, want)
+	}
+
+	// Detail
+	w = send(http.MethodPost, "/combos/batch-get", `{"ids": [`+strconv.FormatInt(created.ID, 10)+`]}`)
+	var detail models.ComboBatchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("batch-get = %d (%v): %s", w.Code, err, w.Body.String())
+	}
+	if len(detail.Combos) != 1 || !reflect.DeepEqual(detail.Combos[0].Tricks, want) {
+		t.Errorf("batch-get = %+v, want the saved combo with %+v", detail.Combos, want)
+	}
+
+	// An unknown slug is refused before anything is written
+	w = send(http.MethodPost, combosPath, `{"name": "Typo", "tricks": [{"trick_id": "cork"}, {"trick_id": "corck"}]}`)
+	var body struct {
+		Code string `json:"code"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusUnprocessableEntity || body.Code != messages.CodeUnknownComboTrick {
+		t.Errorf("unknown slug = %d %s, want 422 %s", w.Code, body.Code, messages.CodeUnknownComboTrick)
+	}
+	if len(store.combos) != 1 {
+		t.Errorf("%d combos stored, want only the first", len(store.combos))
+	}
+}
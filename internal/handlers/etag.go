@@ -0,0 +1,80 @@
+package handlers
+
+// =============================================================================
+// ETAGS
+// =============================================================================
+// Trick ETags are weak and derived from a last-modified Unix timestamp:
+// the same timestamp means the same data, but not necessarily the same bytes
+// (?fields=, gzip, key order). Clients send them back in If-None-Match.
+//
+// Some CDN/proxy layers only speak Last-Modified, so routes can also send
+// the same timestamp as an HTTP date and honor If-Modified-Since.
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// weakETag builds the ETag for data last modified at the given Unix time
+func weakETag(lastModified int64) string {
+	return fmt.Sprintf(`W/"%d"`, lastModified)
+}
+
+// listETag is weakETag for catalog lists, which also flag new tricks
+// Which tricks count as new moves on daily without any trick changing, so the
+// "new" cutoff is part of the tag too.
+func listETag(lastModified int64, newSince time.Time) string {
+	return fmt.Sprintf(`W/"%d-%d"`, lastModified, newSince.Unix())
+}
+
+// etagMatches reports whether an If-None-Match header matches etag
+// Uses weak comparison (W/ is ignored) and accepts lists and "*", per RFC 9110.
+func etagMatches(ifNoneMatch, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified sets the ETag header and, if the client already has this
+// version, writes a bodiless 304. Returns true when the response is done.
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// notModifiedSince is notModified plus Last-Modified / If-Modified-Since
+// If-None-Match wins when both are sent (RFC 9110 13.2.2) - If-Modified-Since
+// is only consulted when the client sent no ETag. HTTP dates have one-second
+// precision, which matches our Unix-second timestamps.
+func notModifiedSince(c *gin.Context, lastModified int64) bool {
+	if lastModified > 0 {
+		c.Header("Last-Modified", time.Unix(lastModified, 0).UTC().Format(http.TimeFormat))
+	}
+	if notModified(c, weakETag(lastModified)) {
+		return true
+	}
+	if c.GetHeader("If-None-Match") != "" || lastModified <= 0 {
+		return false
+	}
+
+	// Unparseable dates are ignored, as the RFC requires
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || lastModified > since.Unix() {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
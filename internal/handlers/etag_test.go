@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+)
+
+const etagLastModified int64 = 1700000000
+
+// etagNewSince is the fake service's "new" cutoff, part of every list ETag
+var etagNewSince = time.Unix(1699000000, 0)
+
+// fakeETagTrickService serves one catalog timestamp and counts the queries behind it
+type fakeETagTrickService struct {
+	services.TrickServiceInterface
+
+	lastModifiedCalls int
+	listCalls         int
+	detailCalls       int
+	views             int
+}
+
+func (s *fakeETagTrickService) GetLastModified(ctx context.Context) (int64, error) {
+	s.lastModifiedCalls++
+	return etagLastModified, nil
+}
+
+func (s *fakeETagTrickService) GetLastModifiedByID(ctx context.Context, id string) (int64, error) {
+	panic("the detail route must take its timestamp from GetSimpleTrickById")
+}
+
+func (s *fakeETagTrickService) NewTricksSince(days int) time.Time {
+	return etagNewSince
+}
+
+func (s *fakeETagTrickService) ListTricks(ctx context.Context, cursor string, limit int, includes []string, withCounts bool) (*models.TrickPage, error) {
+	s.listCalls++
+	return &models.TrickPage{Tricks: []models.TrickSimpleResponse{{ID: "backflip", Name: "Backflip"}}}, nil
+}
+
+func (s *fakeETagTrickService) GetSimpleTrickById(ctx context.Context, id string, locale string) (*models.TrickDetailResponse, int64, error) {
+	s.detailCalls++
+	if id != "backflip" {
+		return nil, 0, services.ErrTrickNotFound
+	}
+	return &models.TrickDetailResponse{ID: id, Name: "Backflip"}, etagLastModified, nil
+}
+
+func (s *fakeETagTrickService) RecordView(id string) {
+	s.views++
+}
+
+func newETagRouter(service services.TrickServiceInterface) *gin.Engine {
+	handler := NewTrickHandler(service, nil, nil)
+	router := gin.New()
+	router.GET("/tricks", handler.ListTricks)
+	router.GET("/tricks/:id", handler.GetSimpleTrickById)
+	return router
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		etag        string
+		want        bool
+	}{
+		{name: "exact", ifNoneMatch: `W/"1700"`, etag: `W/"1700"`, want: true},
+		{name: "strong form of a weak tag", ifNoneMatch: `"1700"`, etag: `W/"1700"`, want: true},
+		{name: "different", ifNoneMatch: `W/"1699"`, etag: `W/"1700"`, want: false},
+		{name: "list", ifNoneMatch: `W/"1", W/"1700" ,W/"2"`, etag: `W/"1700"`, want: true},
+		{name: "star", ifNoneMatch: `*`, etag: `W/"1700"`, want: true},
+		{name: "unquoted", ifNoneMatch: `1700`, etag: `W/"1700"`, want: false},
+		{name: "list tag needs its cutoff", ifNoneMatch: `W/"1700"`, etag: `W/"1700-1600"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.ifNoneMatch, tt.etag); got != tt.want {
+				t.Errorf("etagMatches(%q, %q) = %v, want %v", tt.ifNoneMatch, tt.etag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListTricksETag(t *testing.T) {
+	etag := listETag(etagLastModified, etagNewSince)
+
+	tests := []struct {
+		name          string
+		path          string
+		ifNoneMatch   string
+		wantStatus    int
+		wantETag      string
+		wantListQuery bool
+	}{
+		{name: "first request", path: "/tricks", wantStatus: http.StatusOK, wantETag: etag, wantListQuery: true},
+		{name: "current copy", path: "/tricks", ifNoneMatch: etag, wantStatus: http.StatusNotModified, wantETag: etag},
+		{name: "stale copy", path: "/tricks", ifNoneMatch: listETag(etagLastModified-1, etagNewSince), wantStatus: http.StatusOK, wantETag: etag, wantListQuery: true},
+		{name: "stale new-trick cutoff", path: "/tricks", ifNoneMatch: listETag(etagLastModified, etagNewSince.AddDate(0, 0, -1)), wantStatus: http.StatusOK, wantETag: etag, wantListQuery: true},
+		{name: "star", path: "/tricks", ifNoneMatch: "*", wantStatus: http.StatusNotModified, wantETag: etag},
+		{name: "same tag for another page", path: "/tricks?limit=10", ifNoneMatch: etag, wantStatus: http.StatusNotModified, wantETag: etag},
+		{name: "video counts go without a tag", path: "/tricks?with_counts=true", ifNoneMatch: etag, wantStatus: http.StatusOK, wantListQuery: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeETagTrickService{}
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			newETagRouter(service).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+			if tt.wantStatus == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("304 has a body: %q", w.Body.String())
+			}
+			if ran := service.listCalls > 0; ran != tt.wantListQuery {
+				t.Errorf("list query ran = %v, want %v", ran, tt.wantListQuery)
+			}
+		})
+	}
+}
+
+func TestGetTrickETag(t *testing.T) {
+	etag := weakETag(etagLastModified)
+	lastModified := time.Unix(etagLastModified, 0).UTC()
+
+	tests := []struct {
+		name       string
+		path       string
+		headers    map[string]string
+		wantStatus int
+		wantETag   string
+		wantView   bool
+	}{
+		{name: "first request", path: "/tricks/backflip", wantStatus: http.StatusOK, wantETag: etag, wantView: true},
+		{name: "current copy", path: "/tricks/backflip", headers: map[string]string{"If-None-Match": etag}, wantStatus: http.StatusNotModified, wantETag: etag},
+		{name: "strong form of the tag", path: "/tricks/backflip", headers: map[string]string{"If-None-Match": `"1700000000"`}, wantStatus: http.StatusNotModified, wantETag: etag},
+		{name: "stale copy", path: "/tricks/backflip", headers: map[string]string{"If-None-Match": weakETag(etagLastModified - 60)}, wantStatus: http.StatusOK, wantETag: etag, wantView: true},
+		{
+			name: "fresh If-Modified-Since", path: "/tricks/backflip",
+			headers:    map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			wantStatus: http.StatusNotModified, wantETag: etag,
+		},
+		{
+			name: "If-None-Match wins over If-Modified-Since", path: "/tricks/backflip",
+			headers: map[string]string{
+				"If-None-Match":     weakETag(etagLastModified - 60),
+				"If-Modified-Since": lastModified.Add(time.Hour).Format(http.TimeFormat),
+			},
+			wantStatus: http.StatusOK, wantETag: etag, wantView: true,
+		},
+		{name: "unknown trick has no tag", path: "/tricks/nope", headers: map[string]string{"If-None-Match": "*"}, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeETagTrickService{}
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			newETagRouter(service).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+			if tt.wantETag != "" {
+				if got := w.Header().Get("Last-Modified"); got != lastModified.Format(http.TimeFormat) {
+					t.Errorf("Last-Modified = %q, want %q", got, lastModified.Format(http.TimeFormat))
+				}
+			}
+			if tt.wantStatus == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("304 has a body: %q", w.Body.String())
+			}
+			// One query serves both the 304 and the 200 path
+			if service.detailCalls != 1 || service.lastModifiedCalls != 0 {
+				t.Errorf("queries: %d detail, %d last-modified, want 1 and 0", service.detailCalls, service.lastModifiedCalls)
+			}
+			if viewed := service.views > 0; viewed != tt.wantView {
+				t.Errorf("view recorded = %v, want %v", viewed, tt.wantView)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+// =============================================================================
+// SPARSE FIELDSETS (?fields=id,name,difficulty)
+// =============================================================================
+// List views don't need description/execution_notes, which are most of a
+// trick's bytes. With ?fields= the handler projects each response object down
+// to the requested JSON fields. Without it nothing changes.
+//
+// The allowed names are read from the response type's json tags, so adding a
+// field to a DTO makes it selectable without touching this file.
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/handlers/params"
+)
+
+// fieldSet is the set of JSON fields requested with ?fields=
+// nil means the param was absent - respond with whole objects
+type fieldSet map[string]bool
+
+// parseFields reads ?fields= for responses shaped like sample
+// Returns ok=false after writing a 400 if a name isn't a field of sample.
+func parseFields(c *gin.Context, sample any) (fieldSet, bool) {
+	fields, err := params.Fields(c, "fields", jsonFieldNames(reflect.TypeOf(sample)))
+	if err != nil {
+		params.Respond(c, err)
+		return nil, false
+	}
+	return fields, true
+}
+
+// jsonFieldNames lists the JSON keys a struct type marshals to
+// Embedded structs contribute their own fields, like encoding/json does.
+func jsonFieldNames(t reflect.Type) []string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			names = append(names, jsonFieldNames(field.Type)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// project returns v reduced to the requested fields (v itself when fs is nil)
+// Fields that v omits (omitempty) stay omitted.
+func (fs fieldSet) project(v any) any {
+	if fs == nil {
+		return v
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &object); err != nil {
+		return v
+	}
+
+	for key := range object {
+		if !fs[key] {
+			delete(object, key)
+		}
+	}
+	return object
+}
+
+// projectAll applies project to every item of a list
+func projectAll[T any](fs fieldSet, items []T) any {
+	if fs == nil {
+		return items
+	}
+
+	projected := make([]any, 0, len(items))
+	for _, item := range items {
+		projected = append(projected, fs.project(item))
+	}
+	return projected
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/services"
+)
+
+// FlipHandler handles HTTP requests for flip endpoints
+type FlipHandler struct {
+	flipService services.FlipServiceInterface
+}
+
+// NewFlipHandler creates a new FlipHandler instance
+func NewFlipHandler(flipService *services.FlipService) *FlipHandler {
+	return &FlipHandler{flipService: flipService}
+}
+
+// ListFlips returns every flip family - resolves a trick's flip_id
+// Like stances this is reference data, so clients may cache the list for a while.
+func (h *FlipHandler) ListFlips(c *gin.Context) {
+	flips, err := h.flipService.GetAllFlips(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeFlipsFailed)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, gin.H{
+		"flips": flips,
+		"count": len(flips),
+	})
+}
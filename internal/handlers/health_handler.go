@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/services"
+)
+
+// HealthHandler handles HTTP requests for health probes
+type HealthHandler struct {
+	selfCheck services.SelfCheckServiceInterface
+	drain     services.DrainServiceInterface
+}
+
+// NewHealthHandler creates a new HealthHandler instance
+func NewHealthHandler(selfCheck services.SelfCheckServiceInterface, drain services.DrainServiceInterface) *HealthHandler {
+	return &HealthHandler{selfCheck: selfCheck, drain: drain}
+}
+
+// Ready reports whether the API is ready for traffic, plus the schema self-check result
+// degraded=true means required indexes are missing - slow, but still serving.
+// A draining instance is a 503 so the load balancer stops routing to it.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if drain := h.drain.Status(); drain.Draining {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":         "draining",
+			"draining_since": drain.Since,
+			"reason":         drain.Reason,
+		})
+		return
+	}
+
+	report := h.selfCheck.LastReport()
+	if report == nil {
+		// main runs the check before the server starts, so this is only a safety net
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "starting",
+		})
+		return
+	}
+
+	status := "ready"
+	if report.Degraded {
+		status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":          status,
+		"degraded":        report.Degraded,
+		"missing_indexes": report.MissingIndexes,
+		"checked_at":      report.CheckedAt,
+	})
+}
+
+// Drain fails readiness from now on, as SIGTERM does, without stopping the server
+// For debugging load balancer behaviour - only a restart undoes it. Responds
+// 202 with the drain status (the first drain's start when already draining).
+func (h *HealthHandler) Drain(c *gin.Context) {
+	c.JSON(http.StatusAccepted, h.drain.Drain("admin"))
+}
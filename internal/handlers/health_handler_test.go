@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeSelfCheck has a passing report from startup
+type fakeSelfCheck struct {
+	services.SelfCheckServiceInterface
+}
+
+func (f *fakeSelfCheck) LastReport() *models.SelfCheckReport {
+	return &models.SelfCheckReport{CheckedAt: fixtures.Epoch}
+}
+
+func TestAdminDrain(t *testing.T) {
+	drain := services.NewDrainService()
+	handler := NewHealthHandler(&fakeSelfCheck{}, drain)
+
+	router := gin.New()
+	router.GET("/health/ready", handler.Ready)
+	router.POST("/admin/drain", handler.Drain)
+	router.GET("/tricks", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+	type readiness struct {
+		Status        string `json:"status"`
+		DrainingSince string `json:"draining_since"`
+		Reason        string `json:"reason"`
+	}
+	ready := func() (int, readiness) {
+		w := send(http.MethodGet, "/health/ready")
+		var body readiness
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	if status, body := ready(); status != http.StatusOK || body.Status != "ready" {
+		t.Fatalf("ready before draining = %d %q, want 200 ready", status, body.Status)
+	}
+
+	w := send(http.MethodPost, "/admin/drain")
+	var first models.DrainStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &first); err != nil || w.Code != http.StatusAccepted {
+		t.Fatalf("drain = %d (%v): %s", w.Code, err, w.Body.String())
+	}
+	if !first.Draining || first.Since == nil || first.Reason != "admin" {
+		t.Errorf("drain status = %+v, want draining since now, by admin", first)
+	}
+
+	// Readiness fails; everything else is still served
+	status, body := ready()
+	if status != http.StatusServiceUnavailable || body.Status != "draining" || body.Reason != "admin" || body.DrainingSince == "" {
+		t.Errorf("ready while draining = %d %+v, want 503 draining", status, body)
+	}
+	if w := send(http.MethodGet, "/tricks"); w.Code != http.StatusNoContent {
+		t.Errorf("request while draining = %d, want it served", w.Code)
+	}
+
+	// Draining again is a no-op that reports the first drain
+	w = send(http.MethodPost, "/admin/drain")
+	var again models.DrainStatus
+	_ = json.Unmarshal(w.Body.Bytes(), &again)
+	if w.Code != http.StatusAccepted || !again.Since.Equal(*first.Since) {
+		t.Errorf("second drain = %d %+v, want 202 with the first start %v", w.Code, again, first.Since)
+	}
+}
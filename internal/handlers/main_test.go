@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	if err := messages.Load(); err != nil {
+		log.Fatalf("failed to load messages: %v", err)
+	}
+	os.Exit(m.Run())
+}
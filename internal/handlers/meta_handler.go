@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/services"
+)
+
+// MetaHandler serves the definitions clients need to interpret other responses
+type MetaHandler struct {
+	bands *services.DifficultyBands
+}
+
+// NewMetaHandler creates a new MetaHandler instance
+func NewMetaHandler(bands *services.DifficultyBands) *MetaHandler {
+	return &MetaHandler{bands: bands}
+}
+
+// ListDifficultyBands returns the difficulty bands, lowest first
+// The names are what ?band= accepts and what "band" on trick details holds.
+func (h *MetaHandler) ListDifficultyBands(c *gin.Context) {
+	bands := h.bands.All()
+
+	// Bands come from config, so they only change on restart
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, gin.H{
+		"bands": bands,
+		"count": len(bands),
+	})
+}
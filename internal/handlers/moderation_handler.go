@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+)
+
+// ModerationHandler handles moderator-only content curation endpoints
+type ModerationHandler struct {
+	moderationService services.ModerationServiceInterface
+}
+
+// NewModerationHandler creates a new ModerationHandler instance
+func NewModerationHandler(moderationService services.ModerationServiceInterface) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// CreateMistake adds a common mistake to the end of a trick's list
+// Body: models.MistakeRequest
+func (h *ModerationHandler) CreateMistake(c *gin.Context) {
+	var req models.MistakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	mistake, err := h.moderationService.CreateMistake(c.Request.Context(), slugParam(c, "slug"), req)
+	if err != nil {
+		respondMistakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mistake)
+}
+
+// UpdateMistake replaces a mistake's text and severity
+// Body: models.MistakeRequest
+func (h *ModerationHandler) UpdateMistake(c *gin.Context) {
+	id, ok := mistakeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.MistakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	mistake, err := h.moderationService.UpdateMistake(c.Request.Context(), slugParam(c, "slug"), id, req)
+	if err != nil {
+		respondMistakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mistake)
+}
+
+// DeleteMistake removes a mistake from a trick
+func (h *ModerationHandler) DeleteMistake(c *gin.Context) {
+	id, ok := mistakeIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.moderationService.DeleteMistake(c.Request.Context(), slugParam(c, "slug"), id); err != nil {
+		respondMistakeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderMistakes sets the display order of a trick's mistakes
+// Body: models.MistakeOrderRequest - every mistake ID of the trick, in the new order
+func (h *ModerationHandler) ReorderMistakes(c *gin.Context) {
+	var req models.MistakeOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	mistakes, err := h.moderationService.ReorderMistakes(c.Request.Context(), slugParam(c, "slug"), req.MistakeIDs)
+	if err != nil {
+		respondMistakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mistakes": mistakes,
+		"count":    len(mistakes),
+	})
+}
+
+// mistakeIDParam parses :mistakeId, writing a 400 if it isn't a positive integer
+func mistakeIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("mistakeId"), 10, 64)
+	if err != nil || id < 1 {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidMistakeID)
+		return 0, false
+	}
+	return id, true
+}
+
+// AddPrerequisite records a trick to learn before this one
+// Body: models.PrerequisiteRequest. 201 when added, 200 if it was already there,
+// 422 if it would make a trick its own (transitive) prerequisite.
+func (h *ModerationHandler) AddPrerequisite(c *gin.Context) {
+	var req models.PrerequisiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	trickSlug := slugParam(c, "slug")
+	created, err := h.moderationService.AddPrerequisite(c.Request.Context(), trickSlug, strings.ToLower(req.PrerequisiteID))
+	if err != nil {
+		respondPrerequisiteError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"trick_id":        trickSlug,
+		"prerequisite_id": strings.ToLower(req.PrerequisiteID),
+	})
+}
+
+// RemovePrerequisite deletes one of a trick's prerequisites
+func (h *ModerationHandler) RemovePrerequisite(c *gin.Context) {
+	err := h.moderationService.RemovePrerequisite(c.Request.Context(), slugParam(c, "slug"), slugParam(c, "prerequisite"))
+	if err != nil {
+		respondPrerequisiteError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateTag adds a tag without putting it on a trick
+// Body: models.TagRequest. The name is normalized (lowercase, single spaces);
+// 201 when created, 200 if it already existed.
+func (h *ModerationHandler) CreateTag(c *gin.Context) {
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	name, created, err := h.moderationService.CreateTag(c.Request.Context(), req.Name)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"name": name})
+}
+
+// DeleteTag removes a tag from every trick and deletes it
+func (h *ModerationHandler) DeleteTag(c *gin.Context) {
+	if err := h.moderationService.DeleteTag(c.Request.Context(), c.Param("tag")); err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// TagTrick puts a tag on a trick, creating the tag if needed
+// Body: models.TagRequest. 201 when added, 200 if the trick already had it.
+func (h *ModerationHandler) TagTrick(c *gin.Context) {
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	trickSlug := slugParam(c, "slug")
+	name, created, err := h.moderationService.TagTrick(c.Request.Context(), trickSlug, req.Name)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"trick_id": trickSlug,
+		"tag":      name,
+	})
+}
+
+// UntagTrick takes a tag off a trick (the tag itself stays)
+func (h *ModerationHandler) UntagTrick(c *gin.Context) {
+	if err := h.moderationService.UntagTrick(c.Request.Context(), slugParam(c, "slug"), c.Param("tag")); err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondTagError maps tag service errors to HTTP responses
+func respondTagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTag):
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidTag, gin.H{"max": services.MaxTagLength})
+	case errors.Is(err, services.ErrTrickNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+	case errors.Is(err, services.ErrTagNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodeTagNotFound)
+	default:
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeModerationFailed)
+	}
+}
+
+// respondPrerequisiteError maps prerequisite service errors to HTTP responses
+func respondPrerequisiteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTrickNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+	case errors.Is(err, services.ErrPrerequisiteNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodePrerequisiteNotFound)
+	case errors.Is(err, services.ErrUnknownPrerequisite):
+		messages.Respond(c, http.StatusUnprocessableEntity, messages.CodeUnknownPrerequisite)
+	case errors.Is(err, services.ErrPrerequisiteCycle):
+		messages.Respond(c, http.StatusUnprocessableEntity, messages.CodePrerequisiteCycle)
+	default:
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeModerationFailed)
+	}
+}
+
+// respondMistakeError maps ModerationService errors to responses
+func respondMistakeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMistakeText):
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidMistakeText)
+	case errors.Is(err, services.ErrInvalidMistakeSeverity):
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidMistakeSeverity)
+	case errors.Is(err, services.ErrMistakeOrderMismatch):
+		messages.Respond(c, http.StatusBadRequest, messages.CodeMistakeOrderMismatch)
+	case errors.Is(err, services.ErrTrickNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+	case errors.Is(err, services.ErrMistakeNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodeMistakeNotFound)
+	default:
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeModerationFailed)
+	}
+}
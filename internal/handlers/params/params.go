@@ -0,0 +1,213 @@
+// =============================================================================
+// FILE: internal/handlers/params/params.go
+// PURPOSE: Typed parsing of query parameters with consistent errors
+// =============================================================================
+//
+// Handlers used to parse query params by hand (strconv + their own checks), so
+// the same mistake got a different error depending on the endpoint. These
+// helpers return typed values or a *FieldError, and Respond turns a FieldError
+// into the standard error body:
+//
+//	limit, err := params.BoundedInt(c, "limit", 1, 100, 50)
+//	if err != nil {
+//		params.Respond(c, err)
+//		return
+//	}
+//
+// An absent or empty parameter is never an error - it gets the default (or nil).
+// =============================================================================
+
+package params
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"tricking-api/internal/messages"
+)
+
+// FieldError describes one invalid query parameter
+type FieldError struct {
+	Field string
+	Code  string // messages.Code* value
+
+	// Params fill the message placeholders (min, max, ...) and are added to the body
+	Params gin.H
+}
+
+// Error implements the error interface
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid query parameter %q (%s)", e.Field, e.Code)
+}
+
+// fieldCodes keeps the codes clients already handle for well-known params
+// Everything else gets the generic code for its type
+var fieldCodes = map[string]string{
+	"limit": messages.CodeInvalidLimit,
+	"size":  messages.CodeInvalidSize,
+}
+
+// newFieldError builds a FieldError, using the field's established code if it has one
+func newFieldError(field, code string, params gin.H) *FieldError {
+	if known, ok := fieldCodes[field]; ok {
+		code = known
+	}
+	params["field"] = field
+	return &FieldError{Field: field, Code: code, Params: params}
+}
+
+// BoundedInt parses an integer in [min, max], returning def when the param is absent
+func BoundedInt(c *gin.Context, name string, min, max, def int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || value > max {
+		return 0, newFieldError(name, messages.CodeInvalidIntParam, gin.H{"min": min, "max": max})
+	}
+	return value, nil
+}
+
+// IntList parses a repeated integer param (?ids=1&ids=2) into at most max values
+// Empty values are skipped and duplicates are dropped, keeping first-seen order.
+// Returns an empty (non-nil) slice when the param is absent.
+func IntList(c *gin.Context, name string, max int) ([]int, error) {
+	raws := c.QueryArray(name)
+	values := make([]int, 0, len(raws))
+	seen := make(map[int]bool, len(raws))
+
+	for _, raw := range raws {
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, newFieldError(name, messages.CodeInvalidIntList, gin.H{})
+		}
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+
+	// Checked after de-duplication, so repeating a value never pushes a request over the cap
+	if len(values) > max {
+		return nil, newFieldError(name, messages.CodeTooManyValues, gin.H{"max": max})
+	}
+	return values, nil
+}
+
+// StringList parses a comma-separated param (?exclude=a,b) into at most max values
+// Values are trimmed, empty ones skipped and duplicates dropped, keeping first-seen order.
+// Returns an empty (non-nil) slice when the param is absent.
+func StringList(c *gin.Context, name string, max int) ([]string, error) {
+	values := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, value := range strings.Split(c.Query(name), ",") {
+		if value = strings.TrimSpace(value); value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+
+	if len(values) > max {
+		return nil, newFieldError(name, messages.CodeTooManyValues, gin.H{"max": max})
+	}
+	return values, nil
+}
+
+// Fields parses a comma-separated list of field names, each of which must be in allowed
+// Returns nil (not an empty set) when the param is absent, so callers can tell
+// "no selection" from a selection.
+func Fields(c *gin.Context, name string, allowed []string) (map[string]bool, error) {
+	if c.Query(name) == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]bool, len(allowed))
+	for _, field := range allowed {
+		known[field] = true
+	}
+
+	// Every valid name is distinct, so len(allowed) is the natural cap
+	values, err := StringList(c, name, len(allowed))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]bool, len(values))
+	for _, value := range values {
+		if !known[value] {
+			return nil, newFieldError(name, messages.CodeUnknownField, gin.H{"value": value})
+		}
+		fields[value] = true
+	}
+	return fields, nil
+}
+
+// OneOf parses a param that must be one of allowed, returning def when it is absent
+// The error lists the allowed values, so clients can correct themselves.
+func OneOf(c *gin.Context, name string, allowed []string, def string) (string, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+
+	for _, value := range allowed {
+		if raw == value {
+			return raw, nil
+		}
+	}
+	return "", newFieldError(name, messages.CodeUnknownField, gin.H{"value": raw, "allowed": allowed})
+}
+
+// UUID parses an optional UUID param, returning nil when it is absent
+func UUID(c *gin.Context, name string) (*uuid.UUID, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, newFieldError(name, messages.CodeInvalidUUIDParam, gin.H{})
+	}
+	return &id, nil
+}
+
+// Date parses an optional calendar date (YYYY-MM-DD, midnight UTC), returning nil when it is absent
+func Date(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, newFieldError(name, messages.CodeInvalidDateParam, gin.H{})
+	}
+	return &date, nil
+}
+
+// Respond writes err as a 400 in the standard error format
+// Errors that aren't FieldErrors become a generic invalid_request
+func Respond(c *gin.Context, err error) {
+	fieldErr, ok := err.(*FieldError)
+	if !ok {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{"details": err.Error()})
+		return
+	}
+	messages.RespondWith(c, http.StatusBadRequest, fieldErr.Code, fieldErr.Params)
+}
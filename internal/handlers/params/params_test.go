@@ -0,0 +1,329 @@
+package params
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"tricking-api/internal/messages"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// newContext builds a gin context for a request with the given raw query
+func newContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	return c
+}
+
+// fieldCode returns err's code, or "" when err isn't a FieldError
+func fieldCode(err error) string {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return fieldErr.Code
+	}
+	return ""
+}
+
+func TestBoundedInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    string
+		query    string
+		want     int
+		wantCode string
+	}{
+		{name: "absent uses default", param: "offset", query: "", want: 5},
+		{name: "empty uses default", param: "offset", query: "offset=", want: 5},
+		{name: "in range", param: "offset", query: "offset=7", want: 7},
+		{name: "at min", param: "offset", query: "offset=0", want: 0},
+		{name: "at max", param: "offset", query: "offset=10", want: 10},
+		{name: "below min", param: "offset", query: "offset=-1", wantCode: messages.CodeInvalidIntParam},
+		{name: "above max", param: "offset", query: "offset=11", wantCode: messages.CodeInvalidIntParam},
+		{name: "malformed", param: "offset", query: "offset=ten", wantCode: messages.CodeInvalidIntParam},
+		{name: "float", param: "offset", query: "offset=1.5", wantCode: messages.CodeInvalidIntParam},
+		{name: "overflow", param: "offset", query: "offset=99999999999999999999", wantCode: messages.CodeInvalidIntParam},
+		{name: "limit keeps its code", param: "limit", query: "limit=11", wantCode: messages.CodeInvalidLimit},
+		{name: "size keeps its code", param: "size", query: "size=abc", wantCode: messages.CodeInvalidSize},
+		{name: "first of duplicates wins", param: "offset", query: "offset=2&offset=3", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BoundedInt(newContext(tt.query), tt.param, 0, 10, 5)
+			if tt.wantCode != "" {
+				if code := fieldCode(err); code != tt.wantCode {
+					t.Errorf("BoundedInt() error = %v, want code %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BoundedInt() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BoundedInt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntList(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		want     []int
+		wantCode string
+	}{
+		{name: "absent", query: "", want: []int{}},
+		{name: "empty values skipped", query: "ids=&ids=", want: []int{}},
+		{name: "single", query: "ids=3", want: []int{3}},
+		{name: "keeps order", query: "ids=3&ids=1&ids=2", want: []int{3, 1, 2}},
+		{name: "duplicates dropped", query: "ids=3&ids=1&ids=3&ids=1", want: []int{3, 1}},
+		{name: "duplicates don't count against the cap", query: "ids=1&ids=2&ids=3&ids=1&ids=2", want: []int{1, 2, 3}},
+		{name: "at cap", query: "ids=1&ids=2&ids=3", want: []int{1, 2, 3}},
+		{name: "over cap", query: "ids=1&ids=2&ids=3&ids=4", wantCode: messages.CodeTooManyValues},
+		{name: "malformed", query: "ids=1&ids=two", wantCode: messages.CodeInvalidIntList},
+		{name: "comma list is malformed", query: "ids=1,2", wantCode: messages.CodeInvalidIntList},
+		{name: "negative allowed", query: "ids=-1", want: []int{-1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IntList(newContext(tt.query), "ids", 3)
+			if tt.wantCode != "" {
+				if code := fieldCode(err); code != tt.wantCode {
+					t.Errorf("IntList() error = %v, want code %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("IntList() error = %v", err)
+			}
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IntList() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringList(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		want     []string
+		wantCode string
+	}{
+		{name: "absent", query: "", want: []string{}},
+		{name: "trims and skips empty", query: "tags=%20a%20,,b,%20", want: []string{"a", "b"}},
+		{name: "duplicates dropped", query: "tags=a,b,a", want: []string{"a", "b"}},
+		{name: "at cap", query: "tags=a,b", want: []string{"a", "b"}},
+		{name: "over cap", query: "tags=a,b,c", wantCode: messages.CodeTooManyValues},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StringList(newContext(tt.query), "tags", 2)
+			if tt.wantCode != "" {
+				if code := fieldCode(err); code != tt.wantCode {
+					t.Errorf("StringList() error = %v, want code %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StringList() error = %v", err)
+			}
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StringList() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldsAndOneOf(t *testing.T) {
+	allowed := []string{"id", "name", "difficulty"}
+
+	fieldTests := []struct {
+		name     string
+		query    string
+		want     map[string]bool
+		wantCode string
+	}{
+		{name: "absent is nil", query: "", want: nil},
+		{name: "selection", query: "fields=id,name", want: map[string]bool{"id": true, "name": true}},
+		{name: "unknown field", query: "fields=id,secret", wantCode: messages.CodeUnknownField},
+	}
+	for _, tt := range fieldTests {
+		t.Run("Fields "+tt.name, func(t *testing.T) {
+			got, err := Fields(newContext(tt.query), "fields", allowed)
+			if tt.wantCode != "" {
+				if code := fieldCode(err); code != tt.wantCode {
+					t.Errorf("Fields() error = %v, want code %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fields() = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	oneOfTests := []struct {
+		name     string
+		query    string
+		want     string
+		wantCode string
+	}{
+		{name: "absent uses default", query: "", want: "name"},
+		{name: "allowed", query: "sort=difficulty", want: "difficulty"},
+		{name: "case sensitive", query: "sort=Name", wantCode: messages.CodeUnknownField},
+		{name: "unknown", query: "sort=random", wantCode: messages.CodeUnknownField},
+	}
+	for _, tt := range oneOfTests {
+		t.Run("OneOf "+tt.name, func(t *testing.T) {
+			got, err := OneOf(newContext(tt.query), "sort", allowed, "name")
+			if tt.wantCode != "" {
+				if code := fieldCode(err); code != tt.wantCode {
+					t.Errorf("OneOf() error = %v, want code %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("OneOf() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUUIDAndDate(t *testing.T) {
+	id := uuid.MustParse("0b6d1b9e-7c1a-4c5e-9f3a-2d8e4b6a1c00")
+
+	uuidTests := []struct {
+		name     string
+		query    string
+		want     *uuid.UUID
+		wantCode string
+	}{
+		{name: "absent is nil", query: ""},
+		{name: "valid", query: "user=" + id.String(), want: &id},
+		{name: "uppercase", query: "user=0B6D1B9E-7C1A-4C5E-9F3A-2D8E4B6A1C00", want: &id},
+		{name: "malformed", query: "user=not-a-uuid", wantCode: messages.CodeInvalidUUIDParam},
+		{name: "truncated", query: "user=0b6d1b9e-7c1a", wantCode: messages.CodeInvalidUUIDParam},
+	}
+	for _, tt := range uuidTests {
+		t.Run("UUID "+tt.name, func(t *testing.T) {
+			got, err := UUID(newContext(tt.query), "user")
+			if tt.wantCode != "" {
+				if code := fieldCode(err); code != tt.wantCode {
+					t.Errorf("UUID() error = %v, want code %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UUID() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("UUID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	dateTests := []struct {
+		name     string
+		query    string
+		want     string
+		wantCode string
+	}{
+		{name: "absent is nil", query: ""},
+		{name: "valid", query: "from=2026-02-28", want: "2026-02-28"},
+		{name: "not a real day", query: "from=2026-02-30", wantCode: messages.CodeInvalidDateParam},
+		{name: "timestamp", query: "from=2026-02-28T10:00:00Z", wantCode: messages.CodeInvalidDateParam},
+	}
+	for _, tt := range dateTests {
+		t.Run("Date "+tt.name, func(t *testing.T) {
+			got, err := Date(newContext(tt.query), "from")
+			if tt.wantCode != "" {
+				if code := fieldCode(err); code != tt.wantCode {
+					t.Errorf("Date() error = %v, want code %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Date() error = %v", err)
+			}
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("Date() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Format("2006-01-02") != tt.want {
+				t.Errorf("Date() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	if err := messages.Load(); err != nil {
+		t.Fatalf("messages.Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantFields map[string]any
+	}{
+		{
+			name:       "field error",
+			err:        newFieldError("offset", messages.CodeInvalidIntParam, gin.H{"min": 0, "max": 10}),
+			wantCode:   messages.CodeInvalidIntParam,
+			wantFields: map[string]any{"field": "offset", "min": float64(0), "max": float64(10)},
+		},
+		{
+			name:       "other error",
+			err:        errors.New("boom"),
+			wantCode:   messages.CodeInvalidRequest,
+			wantFields: map[string]any{"details": "boom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			Respond(c, tt.err)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", w.Code)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if body["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %q", body["code"], tt.wantCode)
+			}
+			if msg, _ := body["error"].(string); msg == "" || msg == tt.wantCode {
+				t.Errorf("error = %q, want a message", msg)
+			}
+			for key, want := range tt.wantFields {
+				if body[key] != want {
+					t.Errorf("%s = %v, want %v", key, body[key], want)
+				}
+			}
+		})
+	}
+}
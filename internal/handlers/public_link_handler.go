@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+)
+
+// publicLinkMaxAge caps how long clients and CDNs may cache a public preview
+const publicLinkMaxAge = 5 * time.Minute
+
+// PublicLinkHandler handles signed public trick links - minting (admin) and reading (keyless)
+type PublicLinkHandler struct {
+	publicLinkService services.PublicLinkServiceInterface
+}
+
+// NewPublicLinkHandler creates a new PublicLinkHandler instance
+func NewPublicLinkHandler(publicLinkService services.PublicLinkServiceInterface) *PublicLinkHandler {
+	return &PublicLinkHandler{publicLinkService: publicLinkService}
+}
+
+// CreateLink mints a signed, expiring link to one trick's public preview
+// Body (optional): models.PublicTrickLinkRequest - {"ttl_seconds": 86400}, default one week
+func (h *PublicLinkHandler) CreateLink(c *gin.Context) {
+	var req models.PublicTrickLinkRequest
+	// No body at all just means "use the default lifetime"
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ttl := services.DefaultPublicLinkTTL
+	if req.TTLSeconds != nil {
+		// Range-checked as seconds: a huge value would overflow the Duration into range
+		seconds := *req.TTLSeconds
+		if seconds < int(services.MinPublicLinkTTL.Seconds()) || seconds > int(services.MaxPublicLinkTTL.Seconds()) {
+			respondInvalidLinkTTL(c)
+			return
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+
+	link, err := h.publicLinkService.CreateLink(c.Request.Context(), slugParam(c, "slug"), ttl)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidLinkTTL):
+			respondInvalidLinkTTL(c)
+		case errors.Is(err, services.ErrTrickNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+		case errors.Is(err, services.ErrPublicLinksDisabled):
+			messages.Respond(c, http.StatusServiceUnavailable, messages.CodePublicLinksDisabled)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeAdminActionFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// respondInvalidLinkTTL is the 400 for a ttl_seconds outside the allowed range
+func respondInvalidLinkTTL(c *gin.Context) {
+	messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidLinkTTL, gin.H{
+		"min": int(services.MinPublicLinkTTL.Seconds()),
+		"max": int(services.MaxPublicLinkTTL.Seconds()),
+	})
+}
+
+// GetPublicTrick serves a trick's reduced preview through a signed link
+// Query params: ?exp= and ?sig= exactly as minted. No API key - the signature is the credential.
+// Bad or tampered signatures and expired links are a 401.
+func (h *PublicLinkHandler) GetPublicTrick(c *gin.Context) {
+	slug := slugParam(c, "slug")
+	if !trickIDPattern.MatchString(slug) {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidTrickID)
+		return
+	}
+
+	preview, expiresAt, err := h.publicLinkService.GetPublicTrick(c.Request.Context(), slug, c.Query("sig"), c.Query("exp"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidLinkSignature):
+			messages.Respond(c, http.StatusUnauthorized, messages.CodeInvalidLinkSignature)
+		case errors.Is(err, services.ErrLinkExpired):
+			messages.Respond(c, http.StatusUnauthorized, messages.CodeLinkExpired)
+		case errors.Is(err, services.ErrTrickNotFound):
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodePublicLinkFailed)
+		}
+		return
+	}
+
+	// A cached copy must not keep serving after the link expires
+	maxAge := min(time.Until(expiresAt), publicLinkMaxAge)
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+	c.JSON(http.StatusOK, preview)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeLinkTrickRepo knows every trick
+type fakeLinkTrickRepo struct {
+	repository.TrickRepositoryInterface
+}
+
+func (r *fakeLinkTrickRepo) GetByID(ctx context.Context, slug string) (*models.Trick, error) {
+	trick := fixtures.Trick().WithSlug(slug).Build()
+	return &trick, nil
+}
+
+func TestCreateLinkTTL(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantTTL    time.Duration // From now to expires_at, for a 201
+	}{
+		{name: "default", body: ``, wantStatus: http.StatusCreated, wantTTL: services.DefaultPublicLinkTTL},
+		{name: "one hour", body: `{"ttl_seconds": 3600}`, wantStatus: http.StatusCreated, wantTTL: time.Hour},
+		{name: "shortest", body: `{"ttl_seconds": 60}`, wantStatus: http.StatusCreated, wantTTL: services.MinPublicLinkTTL},
+		{name: "longest", body: `{"ttl_seconds": 7776000}`, wantStatus: http.StatusCreated, wantTTL: services.MaxPublicLinkTTL},
+		{name: "too short", body: `{"ttl_seconds": 59}`, wantStatus: http.StatusBadRequest},
+		{name: "too long", body: `{"ttl_seconds": 7776001}`, wantStatus: http.StatusBadRequest},
+		{name: "negative", body: `{"ttl_seconds": -3600}`, wantStatus: http.StatusBadRequest},
+		// 2^64 ns is ~18446744073.7 s: as a Duration this wraps around to just under an hour
+		{name: "overflows into range", body: `{"ttl_seconds": 18446747673}`, wantStatus: http.StatusBadRequest},
+		{name: "largest int", body: `{"ttl_seconds": 9223372036854775807}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewPublicLinkService(&fakeLinkTrickRepo{}, nil, "test-secret")
+			router := gin.New()
+			router.POST("/admin/tricks/:slug/public-link", NewPublicLinkHandler(service).CreateLink)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/tricks/cork/public-link", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				var body struct {
+					Code string `json:"code"`
+					Max  int    `json:"max"`
+				}
+				_ = json.Unmarshal(w.Body.Bytes(), &body)
+				if body.Code != messages.CodeInvalidLinkTTL || body.Max != int(services.MaxPublicLinkTTL.Seconds()) {
+					t.Errorf("body = %s, want %s with the allowed range", w.Body.String(), messages.CodeInvalidLinkTTL)
+				}
+				return
+			}
+
+			var link models.PublicTrickLink
+			if err := json.Unmarshal(w.Body.Bytes(), &link); err != nil {
+				t.Fatalf("decode link: %v", err)
+			}
+			if ttl := time.Until(link.ExpiresAt); ttl > tt.wantTTL || ttl < tt.wantTTL-time.Minute {
+				t.Errorf("link expires in %v, want %v", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/services"
+)
+
+// StanceHandler handles HTTP requests for stance endpoints
+type StanceHandler struct {
+	stanceService services.StanceServiceInterface
+}
+
+// NewStanceHandler creates a new StanceHandler instance
+func NewStanceHandler(stanceService *services.StanceService) *StanceHandler {
+	return &StanceHandler{stanceService: stanceService}
+}
+
+// ListStances returns every stance - resolves takeoff_stance_id / landing_stance_id
+// Stances are reference data, so clients may cache the list for a while.
+func (h *StanceHandler) ListStances(c *gin.Context) {
+	stances, err := h.stanceService.GetAllStances(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeStancesFailed)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, gin.H{
+		"stances": stances,
+		"count":   len(stances),
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+// =============================================================================
+// STREAMED RESPONSES
+// =============================================================================
+// Catalog-sized responses (the export, the full snapshot) are written as rows
+// come out of the database instead of being built in memory first. The status
+// line goes out with the first bytes, so a failure after that can't become an
+// error response: the body is ended cleanly and the X-Stream-Status trailer
+// says "incomplete" (the failure is logged). Clients should check the trailer
+// before trusting a streamed body.
+//
+// Streaming routes must stay out of the Timeout middleware - it buffers the
+// whole response. Handlers set their own deadline instead.
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamStatusTrailer is sent after a streamed body: "complete" or "incomplete"
+const streamStatusTrailer = "X-Stream-Status"
+
+// declareStreamStatus announces the trailer - call it before the first write
+func declareStreamStatus(c *gin.Context) {
+	c.Header("Trailer", streamStatusTrailer)
+}
+
+// finishStream sets the trailer for how the stream ended, logging a failure
+func finishStream(c *gin.Context, what string, err error) {
+	if err != nil {
+		log.Printf("Warning: %s ended early: %v", what, err)
+		c.Writer.Header().Set(streamStatusTrailer, "incomplete")
+		return
+	}
+	c.Writer.Header().Set(streamStatusTrailer, "complete")
+}
+
+// jsonArrayStream writes a JSON array one element at a time
+// Only the element being encoded is held in memory.
+type jsonArrayStream struct {
+	w       io.Writer
+	encoder *json.Encoder
+	count   int
+}
+
+func newJSONArrayStream(w io.Writer) *jsonArrayStream {
+	return &jsonArrayStream{w: w, encoder: json.NewEncoder(w)}
+}
+
+// Open writes the opening bracket
+func (s *jsonArrayStream) Open() error {
+	_, err := io.WriteString(s.w, "[")
+	return err
+}
+
+// Write appends one element (Encode's trailing newline is just whitespace)
+func (s *jsonArrayStream) Write(v any) error {
+	if s.count > 0 {
+		if _, err := io.WriteString(s.w, ","); err != nil {
+			return err
+		}
+	}
+	s.count++
+	return s.encoder.Encode(v)
+}
+
+// Close writes the closing bracket - also after a failed Write, so the body
+// still parses (the trailer tells the client it's cut short)
+func (s *jsonArrayStream) Close() error {
+	_, err := io.WriteString(s.w, "]")
+	return err
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeSnapshotTrickRepo is a catalog read the two ways the repository offers:
+// collected into a slice (FindChangesSince, like CollectRows) or handed over a
+// row at a time (ForEachTrick). Each row is a fresh copy, as a scan would be.
+type fakeSnapshotTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	tricks    []models.Trick
+	failAfter int   // ForEachTrick fails after this many rows (0 = never)
+	err       error // FindChangesSince fails
+}
+
+func (r *fakeSnapshotTrickRepo) FindChangesSince(ctx context.Context, since time.Time, withTricks, withReferenceData bool) (*repository.TrickChanges, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	changes := &repository.TrickChanges{ServerTime: fixtures.Epoch, DeletedSlugs: []string{"webster"}}
+	if withTricks {
+		for _, trick := range r.tricks {
+			changes.Tricks = append(changes.Tricks, trick)
+		}
+	}
+	if withReferenceData {
+		changes.Categories, changes.Stances = fixtures.CatalogCategories(), fixtures.CatalogStances()
+	}
+	return changes, nil
+}
+
+func (r *fakeSnapshotTrickRepo) ForEachTrick(ctx context.Context, fn func(*models.Trick) error) error {
+	for i := range r.tricks {
+		if r.failAfter > 0 && i == r.failAfter {
+			return errors.New("connection reset")
+		}
+		trick := r.tricks[i]
+		if err := fn(&trick); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotCatalog repeats the fixture catalog up to n tricks, with unique slugs
+func snapshotCatalog(n int) []models.Trick {
+	base := fixtures.CatalogTricks()
+	tricks := make([]models.Trick, n)
+	for i := range tricks {
+		trick := base[i%len(base)]
+		trick.Slug = fmt.Sprintf("%s-%d", trick.Slug, i/len(base))
+		trick.ID = trick.Slug
+		tricks[i] = trick
+	}
+	return tricks
+}
+
+// snapshotRouter serves the streamed snapshot and the buffered full delta over repo
+func snapshotRouter(repo *fakeSnapshotTrickRepo) *gin.Engine {
+	service := services.NewTrickService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, 7,
+		services.NewDifficultyBands(nil), 0, 30*24*time.Hour, 0, false)
+	handler := NewTrickHandler(service, nil, nil)
+	router := gin.New()
+	router.GET("/catalog/snapshot", handler.GetCatalogSnapshot)
+	router.GET("/catalog/snapshot/delta", handler.GetCatalogDelta)
+	return router
+}
+
+func TestCatalogSnapshotStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		repo        *fakeSnapshotTrickRepo
+		wantStatus  int
+		wantTricks  int
+		wantTrailer string
+	}{
+		{name: "complete", repo: &fakeSnapshotTrickRepo{tricks: snapshotCatalog(45)}, wantStatus: http.StatusOK, wantTricks: 45, wantTrailer: "complete"},
+		{name: "empty catalog", repo: &fakeSnapshotTrickRepo{}, wantStatus: http.StatusOK, wantTricks: 0, wantTrailer: "complete"},
+		{
+			name: "fails mid-stream", repo: &fakeSnapshotTrickRepo{tricks: snapshotCatalog(45), failAfter: 3},
+			wantStatus: http.StatusOK, wantTricks: 3, wantTrailer: "incomplete",
+		},
+		{name: "fails before the first byte", repo: &fakeSnapshotTrickRepo{err: errors.New("db down")}, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := snapshotRouter(tt.repo)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/catalog/snapshot", nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				var body struct {
+					Code string `json:"code"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != messages.CodeCatalogDeltaFailed {
+					t.Errorf("error body = %s, want code %s", w.Body.String(), messages.CodeCatalogDeltaFailed)
+				}
+				return
+			}
+
+			// Even cut short, the body is whole JSON - the trailer says whether to trust it
+			var streamed models.CatalogDeltaResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &streamed); err != nil {
+				t.Fatalf("streamed body isn't JSON: %v\n%s", err, w.Body.String())
+			}
+			if got := w.Result().Trailer.Get(streamStatusTrailer); got != tt.wantTrailer {
+				t.Errorf("%s trailer = %q, want %q", streamStatusTrailer, got, tt.wantTrailer)
+			}
+			if len(streamed.Tricks) != tt.wantTricks {
+				t.Errorf("streamed %d tricks, want %d", len(streamed.Tricks), tt.wantTricks)
+			}
+			if tt.wantTrailer != "complete" {
+				return
+			}
+
+			// A complete stream is the full delta, ETag included
+			buffered := httptest.NewRecorder()
+			router.ServeHTTP(buffered, httptest.NewRequest(http.MethodGet, "/catalog/snapshot/delta", nil))
+			var delta models.CatalogDeltaResponse
+			if err := json.Unmarshal(buffered.Body.Bytes(), &delta); err != nil {
+				t.Fatalf("decode delta: %v", err)
+			}
+			streamedJSON, _ := json.Marshal(streamed)
+			deltaJSON, _ := json.Marshal(delta)
+			if string(streamedJSON) != string(deltaJSON) {
+				t.Errorf("streamed snapshot differs from the full delta:\n%s\n%s", streamedJSON, deltaJSON)
+			}
+			if w.Header().Get("ETag") != buffered.Header().Get("ETag") {
+				t.Errorf("ETag = %s, want the delta's %s", w.Header().Get("ETag"), buffered.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+// discardResponseWriter throws the body away, so a benchmark measures the
+// handler and not a recorder holding every byte
+type discardResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardResponseWriter) WriteHeader(status int)      { w.status = status }
+
+// WriteString is there because net/http's writer has it too (no []byte copy)
+func (w *discardResponseWriter) WriteString(s string) (int, error) { return len(s), nil }
+
+// BenchmarkCatalogSnapshot compares the full snapshot built in memory (the
+// delta without since_version) with the streamed one, on a 10k-trick catalog
+func BenchmarkCatalogSnapshot(b *testing.B) {
+	router := snapshotRouter(&fakeSnapshotTrickRepo{tricks: snapshotCatalog(10_000)})
+
+	for _, bench := range []struct {
+		name string
+		path string
+	}{
+		{name: "buffered", path: "/catalog/snapshot/delta"},
+		{name: "streamed", path: "/catalog/snapshot"},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			req := httptest.NewRequest(http.MethodGet, bench.path, nil)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				w := &discardResponseWriter{header: http.Header{}}
+				router.ServeHTTP(w, req)
+				if w.status != http.StatusOK {
+					b.Fatalf("status = %d", w.status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+	"tricking-api/internal/viewer"
+)
+
+// fakeDuplicateTrickRepo holds "backflip" and fails writes the way the repository does
+// nameTaken / slugTaken answer the up-front checks; writeErr is what the write
+// itself returns once a duplicate got past them (uniqueViolation's result)
+type fakeDuplicateTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	nameTaken bool
+	slugTaken bool
+	writeErr  error
+}
+
+func (r *fakeDuplicateTrickRepo) ExistsByName(ctx context.Context, name, exceptSlug string) (bool, error) {
+	return r.nameTaken, nil
+}
+
+func (r *fakeDuplicateTrickRepo) ExistsBySlug(ctx context.Context, slug, exceptSlug string) (bool, error) {
+	return r.slugTaken, nil
+}
+
+func (r *fakeDuplicateTrickRepo) GetByID(ctx context.Context, slug string) (*models.Trick, error) {
+	trick := fixtures.Trick().WithSlug("backflip").WithName("Backflip").WithDifficulty(4).Build()
+	return &trick, nil
+}
+
+func (r *fakeDuplicateTrickRepo) Insert(ctx context.Context, trick *models.Trick, status string, changedBy *uuid.UUID) error {
+	return r.writeErr
+}
+
+func (r *fakeDuplicateTrickRepo) Update(ctx context.Context, slug string, update repository.TrickUpdate, changedBy *uuid.UUID) (*models.Trick, error) {
+	if r.writeErr != nil {
+		return nil, r.writeErr
+	}
+	return &update.Trick, nil
+}
+
+func (r *fakeDuplicateTrickRepo) Patch(ctx context.Context, slug string, patch repository.TrickPatch, changedBy *uuid.UUID) (*models.Trick, error) {
+	if r.writeErr != nil {
+		return nil, r.writeErr
+	}
+	return &patch.Trick, nil
+}
+
+// trickWriteRouter serves the trick write routes as an admin, over a real TrickService
+func trickWriteRouter(repo repository.TrickRepositoryInterface) *gin.Engine {
+	service := services.NewTrickService(repo, nil, nil, nil, &fakeNoAliasRepo{}, nil, nil, nil, nil, nil, 7,
+		services.NewDifficultyBands(nil), 0, 0, 0, false)
+	handler := NewTrickHandler(service, nil, nil)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uuid.NewString())
+		c.Request = c.Request.WithContext(viewer.WithRole(c.Request.Context(), "admin"))
+	})
+	router.POST("/tricks", handler.CreateTrick)
+	router.PUT("/tricks/:id", handler.UpdateTrick)
+	router.PATCH("/tricks/:id", handler.PatchTrick)
+	return router
+}
+
+// sendTrickWrite sends a JSON write, returning the status and error code
+func sendTrickWrite(router *gin.Engine, method, path, body string) (int, string) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Code string `json:"code"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w.Code, response.Code
+}
+
+func TestDuplicateTrickConflict(t *testing.T) {
+	const (
+		create = `{"name": "Cork", "difficulty": 5}`
+		rename = `{"name": "Cork"}`
+	)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		repo     *fakeDuplicateTrickRepo
+		wantCode string
+	}{
+		// Caught by the checks
+		{name: "create, name taken", method: http.MethodPost, path: "/tricks", body: create, repo: &fakeDuplicateTrickRepo{nameTaken: true}, wantCode: messages.CodeTrickNameTaken},
+		{name: "put, name taken", method: http.MethodPut, path: "/tricks/backflip", body: create, repo: &fakeDuplicateTrickRepo{nameTaken: true}, wantCode: messages.CodeTrickNameTaken},
+		{name: "patch, name taken", method: http.MethodPatch, path: "/tricks/backflip", body: rename, repo: &fakeDuplicateTrickRepo{nameTaken: true}, wantCode: messages.CodeTrickNameTaken},
+
+		// Raced past the checks: the unique index catches it, with the same 409
+		{name: "create, name index", method: http.MethodPost, path: "/tricks", body: create, repo: &fakeDuplicateTrickRepo{writeErr: repository.ErrNameTaken}, wantCode: messages.CodeTrickNameTaken},
+		{name: "create, slug index", method: http.MethodPost, path: "/tricks", body: create, repo: &fakeDuplicateTrickRepo{writeErr: repository.ErrSlugTaken}, wantCode: messages.CodeTrickSlugTaken},
+		{
+			name: "create, wrapped violation", method: http.MethodPost, path: "/tricks", body: create,
+			repo:     &fakeDuplicateTrickRepo{writeErr: fmt.Errorf("failed to insert trick: %w", repository.ErrNameTaken)},
+			wantCode: messages.CodeTrickNameTaken,
+		},
+		{name: "put, name index", method: http.MethodPut, path: "/tricks/backflip", body: create, repo: &fakeDuplicateTrickRepo{writeErr: repository.ErrNameTaken}, wantCode: messages.CodeTrickNameTaken},
+		{name: "put, slug index", method: http.MethodPut, path: "/tricks/backflip", body: create, repo: &fakeDuplicateTrickRepo{writeErr: repository.ErrSlugTaken}, wantCode: messages.CodeTrickSlugTaken},
+		{name: "patch, name index", method: http.MethodPatch, path: "/tricks/backflip", body: rename, repo: &fakeDuplicateTrickRepo{writeErr: repository.ErrNameTaken}, wantCode: messages.CodeTrickNameTaken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := sendTrickWrite(trickWriteRouter(tt.repo), tt.method, tt.path, tt.body)
+			if status != http.StatusConflict || code != tt.wantCode {
+				t.Errorf("got %d %s, want 409 %s", status, code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// fakeRacingTrickRepo lets every create through the name check before any of
+// them inserts - the race the unique index is there for. The first insert of
+// a name wins; the rest hit the index.
+type fakeRacingTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	checked *sync.WaitGroup // Done once per check, waited on before inserting
+
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+func (r *fakeRacingTrickRepo) ExistsByName(ctx context.Context, name, exceptSlug string) (bool, error) {
+	r.mu.Lock()
+	taken := r.names[strings.ToLower(name)]
+	r.mu.Unlock()
+
+	r.checked.Done()
+	return taken, nil
+}
+
+func (r *fakeRacingTrickRepo) Insert(ctx context.Context, trick *models.Trick, status string, changedBy *uuid.UUID) error {
+	r.checked.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[strings.ToLower(trick.Name)] {
+		return repository.ErrNameTaken // uniqueViolation's answer to tricks_lower_name
+	}
+	r.names[strings.ToLower(trick.Name)] = true
+	return nil
+}
+
+func TestConcurrentCreateSameName(t *testing.T) {
+	const creates = 5
+	repo := &fakeRacingTrickRepo{checked: &sync.WaitGroup{}, names: map[string]bool{}}
+	repo.checked.Add(creates)
+	router := trickWriteRouter(repo)
+
+	statuses := make([]int, creates)
+	codes := make([]string, creates)
+	var wg sync.WaitGroup
+	for i := range creates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Same name, different case: still the same trick
+			name := []string{"Cork", "cork", "CORK", "Cork", "cOrK"}[i]
+			statuses[i], codes[i] = sendTrickWrite(router, http.MethodPost, "/tricks", `{"name": "`+name+`", "difficulty": 5}`)
+		}()
+	}
+	wg.Wait()
+
+	created, conflicts := 0, 0
+	for i := range creates {
+		switch {
+		case statuses[i] == http.StatusCreated:
+			created++
+		case statuses[i] == http.StatusConflict && codes[i] == messages.CodeTrickNameTaken:
+			conflicts++
+		default:
+			t.Errorf("create %d = %d %s, want 201 or 409 %s", i, statuses[i], codes[i], messages.CodeTrickNameTaken)
+		}
+	}
+	if created != 1 || conflicts != creates-1 {
+		t.Errorf("%d created, %d conflicts; want 1 and %d", created, conflicts, creates-1)
+	}
+}
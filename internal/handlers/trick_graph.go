@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/handlers/params"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+)
+
+// graphFormats are the ?format= values of GET /tricks/graph
+var graphFormats = []string{"json", "dot"}
+
+// GetTrickGraph returns tricks and the relations between them, for visualization
+// Query params: ?relations=prerequisite (comma-separated, default all),
+// ?format=json|dot, and the GET /tricks filters. A graph of more than
+// TRICK_GRAPH_MAX_NODES tricks is a 413 - narrow it by category or difficulty.
+func (h *TrickHandler) GetTrickGraph(c *gin.Context) {
+	selected, err := params.Fields(c, "relations", services.TrickRelations)
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+	format, err := params.OneOf(c, "format", graphFormats, "json")
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+	filter, ok := listFilterQuery(c, h.bands)
+	if !ok {
+		return
+	}
+
+	relations := []string{}
+	for _, relation := range services.TrickRelations {
+		if selected[relation] {
+			relations = append(relations, relation)
+		}
+	}
+
+	graph, err := h.trickService.GetTrickGraph(c.Request.Context(), filter, relations)
+	if err != nil {
+		var tooLarge *services.TrickGraphTooLargeError
+		switch {
+		case errors.As(err, &tooLarge):
+			messages.RespondWith(c, http.StatusRequestEntityTooLarge, messages.CodeTrickGraphTooLarge, gin.H{
+				"max":     tooLarge.Max,
+				"filters": []string{"category_ids", "band", "min_difficulty", "max_difficulty"},
+			})
+		case errors.Is(err, services.ErrInvalidDifficultyRange):
+			messages.Respond(c, http.StatusBadRequest, messages.CodeDifficultyRange)
+		default:
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickGraphFailed)
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	if format == "dot" {
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(trickGraphDOT(graph)))
+		return
+	}
+	c.JSON(http.StatusOK, graph)
+}
+
+// trickGraphDOT renders the graph in Graphviz DOT ("dot -Tsvg graph.dot")
+// Nodes are labelled with name and difficulty; edges with their relation.
+func trickGraphDOT(graph *models.TrickGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph tricks {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for _, node := range graph.Nodes {
+		label := node.Name
+		if node.Difficulty != nil {
+			label = fmt.Sprintf("%s (%d)", node.Name, *node.Difficulty)
+		}
+		fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(node.Slug), dotQuote(label))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(edge.Relation))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote makes s a DOT quoted string - only backslashes and quotes need escaping
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -30,12 +30,26 @@
 package handlers
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"mime"
 	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
+	"tricking-api/internal/handlers/params"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
 	"tricking-api/internal/services"
 )
 
@@ -43,11 +57,17 @@ import (
 type TrickHandler struct {
 	// Depend on interface, not concrete type (enables testing with mocks)
 	trickService services.TrickServiceInterface
+
+	// stanceService resolves stance IDs for ?expand=stances
+	stanceService services.StanceServiceInterface
+
+	// bands translates ?band= into a difficulty range
+	bands *services.DifficultyBands
 }
 
 // NewTrickHandler creates a new TrickHandler instance
-func NewTrickHandler(trickService services.TrickServiceInterface) *TrickHandler {
-	return &TrickHandler{trickService: trickService}
+func NewTrickHandler(trickService services.TrickServiceInterface, stanceService services.StanceServiceInterface, bands *services.DifficultyBands) *TrickHandler {
+	return &TrickHandler{trickService: trickService, stanceService: stanceService, bands: bands}
 }
 
 // GetSimpleTricksList returns a simple list of all tricks
@@ -55,31 +75,23 @@ func (h *TrickHandler) GetSimpleTricksList(c *gin.Context) {
 	// Step 1: Get last modified timestamp from database (fast query)
 	lastModified, err := h.trickService.GetLastModified(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve tricks",
-		})
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTricksFailed)
 		return
 	}
 
-	// Step 2: Generate ETag from timestamp
+	// Step 2: Generate ETag from timestamp (and the is_new cutoff)
 	// Using timestamp-based ETag means we don't need to fetch/marshal data
-	etag := fmt.Sprintf(`"%d"`, lastModified)
-
 	// Step 3: Check If-None-Match header BEFORE fetching data
 	// This is the key performance improvement - avoid expensive operations
-	if c.GetHeader("If-None-Match") == etag {
-		// Data hasn't changed, return 304 Not Modified
-		c.Header("ETag", etag)
-		c.Status(http.StatusNotModified)
+	if notModified(c, listETag(lastModified, h.trickService.NewTricksSince(0))) {
+		// Data hasn't changed, 304 Not Modified already sent
 		return
 	}
 
 	// Step 4: Only fetch data if ETag doesn't match (data has changed)
 	tricks, err := h.trickService.GetSimpleTricksList(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve tricks",
-		})
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTricksFailed)
 		return
 	}
 
@@ -94,121 +106,1408 @@ func (h *TrickHandler) GetSimpleTricksList(c *gin.Context) {
 	// max-age=3600: cache for 1 hour (3600 seconds)
 	// stale-while-revalidate=86400: can serve stale content for 1 day while revalidating
 	c.Header("Cache-Control", "public, max-age=3600, stale-while-revalidate=86400")
-	c.Header("ETag", etag)
 
 	// Return successful response
 	c.JSON(http.StatusOK, responseData)
 }
 
-// GetSimpleTrickById returns basic trick details
-func (h *TrickHandler) GetSimpleTrickById(c *gin.Context) {
-	// Parse ID from URL parameter
-	id := c.Param("id")
+// ListTricks returns the trick catalog one page at a time
+// Query params: ?limit=50 (1-100) and ?cursor= (next_cursor from the previous page)
+// With any of ?min_difficulty=, ?max_difficulty=, ?takeoff_stance_id=, ?landing_stance_id=
+// or ?tag= (repeatable, all required) it instead returns the whole filtered list (see listFilteredTricks).
+// With ?slugs=a,b,c it returns exactly those tricks (see getTricksBySlugs).
+// Every mode accepts ?fields=id,name,... to trim each trick (unknown names are a 400).
+// The paginated mode also accepts ?include=featured_video (alias: videos) for gallery grids
+// and ?include=video_flags for has_video / has_featured_video on every trick.
+// ?with_counts=true adds video_count to every trick (0 when it has none).
+// Responses carry a weak ETag from the catalog's last change and honor If-None-Match.
+func (h *TrickHandler) ListTricks(c *gin.Context) {
+	if c.Query("slugs") != "" {
+		h.getTricksBySlugs(c)
+		return
+	}
 
-	// Step 1: Get last modified timestamp for this specific trick
-	lastModified, err := h.trickService.GetLastModifiedByID(c.Request.Context(), id)
+	for _, param := range trickListFilterParams {
+		if c.Query(param) != "" {
+			h.listFilteredTricks(c)
+			return
+		}
+	}
+
+	limit, err := params.BoundedInt(c, "limit", 1, 100, 50)
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+	fields, ok := parseFields(c, models.TrickSimpleResponse{})
+	if !ok {
+		return
+	}
+	includes, err := services.ParseTrickListIncludes(c.Query("include"))
+	if err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeUnknownInclude, gin.H{"details": err.Error()})
+		return
+	}
+	withCounts := c.Query("with_counts") == "true"
+	// Adding a video doesn't touch the trick rows, so the catalog timestamp
+	// can't vouch for featured videos or video counts - those pages go without an ETag
+	if len(includes) == 0 && !withCounts && h.listNotModified(c) {
+		return
+	}
+
+	page, err := h.trickService.ListTricks(c.Request.Context(), c.Query("cursor"), limit, includes, withCounts)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidCursor)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTricksFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks":      projectAll(fields, page.Tricks),
+		"count":       len(page.Tricks),
+		"next_cursor": page.NextCursor,
+	})
+}
+
+// listNotModified sets the catalog-wide ETag on a GET /tricks response and
+// answers 304 if the client's copy is current. The timestamp query is a single
+// aggregate, so a 304 never runs the list query itself.
+// If the timestamp can't be read the response is simply served without an ETag.
+func (h *TrickHandler) listNotModified(c *gin.Context) bool {
+	return h.listNotModifiedSince(c, h.trickService.NewTricksSince(0))
+}
+
+// listNotModifiedSince is listNotModified for a list whose "new" cutoff is newSince
+func (h *TrickHandler) listNotModifiedSince(c *gin.Context, newSince time.Time) bool {
+	lastModified, err := h.trickService.GetLastModified(c.Request.Context())
+	if err != nil {
+		return false
+	}
+	return notModified(c, listETag(lastModified, newSince))
+}
+
+// getTricksBySlugs returns full details for a comma-separated list of slugs
+// Order follows the request; unknown slugs come back in "missing".
+// More than services.MaxBatchSlugs slugs is a 400.
+func (h *TrickHandler) getTricksBySlugs(c *gin.Context) {
+	slugs := make([]string, 0)
+	for _, slug := range strings.Split(c.Query("slugs"), ",") {
+		// Tolerate "a, b" and trailing commas
+		if slug = strings.TrimSpace(slug); slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+	fields, ok := parseFields(c, models.TrickDetailResponse{})
+	if !ok {
+		return
+	}
+	if h.listNotModified(c) {
+		return
+	}
+
+	result, err := h.trickService.GetTricksBySlugs(c.Request.Context(), slugs)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManySlugs) {
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeTooManySlugs, gin.H{"max": services.MaxBatchSlugs})
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTricksFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks":  projectAll(fields, result.Tricks),
+		"count":   result.Count,
+		"missing": result.Missing,
+	})
+}
+
+// trickListFilterParams are the query params that switch GET /tricks to listFilteredTricks
+var trickListFilterParams = []string{"min_difficulty", "max_difficulty", "band", "takeoff_stance_id", "landing_stance_id", "tag", "category_ids"}
+
+// maxTagFilters caps how many tags one GET /tricks may require
+const maxTagFilters = 10
+
+// maxCategoryFilters caps how many categories one GET /tricks may accept
+const maxCategoryFilters = 50
+
+// listFilteredTricks returns every trick matching the query filters (ANDed together)
+// Difficulty bounds are inclusive and optional, but min_difficulty > max_difficulty is a 400.
+// Tricks with no difficulty (NULL) are EXCLUDED whenever either bound is given -
+// an unrated trick can't be said to fall inside any range.
+// A stance ID that matches nothing returns 200 with an empty list.
+func (h *TrickHandler) listFilteredTricks(c *gin.Context) {
+	filter, ok := listFilterQuery(c, h.bands)
+	if !ok {
+		return
+	}
+	fields, ok := parseFields(c, models.Trick{})
+	if !ok {
+		return
+	}
+	if h.listNotModified(c) {
+		return
+	}
+
+	tricks, err := h.trickService.GetTricksList(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDifficultyRange) {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeDifficultyRange)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTricksFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": projectAll(fields, tricks),
+		"count":  len(tricks),
+	})
+}
+
+// listFilterQuery parses the trickListFilterParams shared by GET /tricks, GET /tricks/export
+// and GET /tricks/difficulty-histogram
+// Returns ok=false after writing a 400 if any of them is malformed
+func listFilterQuery(c *gin.Context, bands *services.DifficultyBands) (models.TrickListFilter, bool) {
+	var filter models.TrickListFilter
+	var ok bool
+	if filter.MinDifficulty, filter.MaxDifficulty, ok = difficultyRangeQuery(c, bands); !ok {
+		return filter, false
+	}
+	if filter.TakeoffStanceID, ok = stanceQuery(c, "takeoff_stance_id"); !ok {
+		return filter, false
+	}
+	if filter.LandingStanceID, ok = stanceQuery(c, "landing_stance_id"); !ok {
+		return filter, false
+	}
+	if filter.Tags, ok = tagQuery(c, "tag"); !ok {
+		return filter, false
+	}
+	categoryIDs, err := params.IntList(c, "category_ids", maxCategoryFilters)
+	if err != nil {
+		params.Respond(c, err)
+		return filter, false
+	}
+	filter.CategoryIDs = categoryIDs
+	return filter, true
+}
+
+// difficultyRangeQuery parses ?min_difficulty=, ?max_difficulty= and ?band=
+// A band stands for its configured range (see GET /meta/difficulty-bands).
+// Given together with explicit bounds, the tighter bound on each side wins -
+// min > max is left for the service to reject. Returns ok=false after writing
+// a 400 for a malformed bound or an unknown band.
+func difficultyRangeQuery(c *gin.Context, bands *services.DifficultyBands) (min, max *int64, ok bool) {
+	if min, ok = difficultyQuery(c, "min_difficulty"); !ok {
+		return nil, nil, false
+	}
+	if max, ok = difficultyQuery(c, "max_difficulty"); !ok {
+		return nil, nil, false
+	}
+
+	name := strings.ToLower(strings.TrimSpace(c.Query("band")))
+	if name == "" {
+		return min, max, true
+	}
+	band, found := bands.Find(name)
+	if !found {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeUnknownDifficultyBand, gin.H{"bands": bands.Names()})
+		return nil, nil, false
+	}
+	if min == nil || *min < band.MinDifficulty {
+		min = &band.MinDifficulty
+	}
+	if max == nil || *max > band.MaxDifficulty {
+		max = &band.MaxDifficulty
+	}
+	return min, max, true
+}
+
+// difficultyQuery parses an optional integer difficulty bound from the query string
+// Returns ok=false after writing a 400 if the value isn't an integer
+func difficultyQuery(c *gin.Context, param string) (*int64, bool) {
+	raw := c.Query(param)
+	if raw == "" {
+		return nil, true
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidDifficulty, gin.H{"param": param})
+		return nil, false
+	}
+	return &value, true
+}
+
+// tagQuery parses ?tag= into normalized, distinct tag names
+// Both ?tag=a&tag=b and ?tag=a,b work. Returns ok=false after writing a 400
+// for an invalid name or more than maxTagFilters tags.
+func tagQuery(c *gin.Context, param string) ([]string, bool) {
+	tags := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, raw := range c.QueryArray(param) {
+		for _, value := range strings.Split(raw, ",") {
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+			name, err := services.NormalizeTag(value)
+			if err != nil {
+				messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidTag, gin.H{"max": services.MaxTagLength})
+				return nil, false
+			}
+			if !seen[name] {
+				seen[name] = true
+				tags = append(tags, name)
+			}
+		}
+	}
+
+	if len(tags) > maxTagFilters {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeTooManyValues, gin.H{"field": param, "max": maxTagFilters})
+		return nil, false
+	}
+	return tags, true
+}
+
+// stanceQuery parses an optional stance ID from the query string
+// Returns ok=false after writing a 400 if the value isn't a positive integer
+func stanceQuery(c *gin.Context, param string) (*int, bool) {
+	raw := c.Query(param)
+	if raw == "" {
+		return nil, true
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidStanceID, gin.H{"param": param})
+		return nil, false
+	}
+	return &value, true
+}
+
+// GetTrickSlugs returns every live trick's slug and update time
+// Meant for nightly sitemap generation - supports If-Modified-Since
+func (h *TrickHandler) GetTrickSlugs(c *gin.Context) {
+	lastModified, err := h.trickService.GetLastModified(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickSlugsFailed)
+		return
+	}
+
+	// HTTP dates have second precision, same as our timestamp
+	modifiedAt := time.Unix(lastModified, 0).UTC()
+	if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !modifiedAt.After(since) {
+		c.Header("Last-Modified", modifiedAt.Format(http.TimeFormat))
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	slugs, err := h.trickService.GetTrickSlugs(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickSlugsFailed)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("Last-Modified", modifiedAt.Format(http.TimeFormat))
+
+	c.JSON(http.StatusOK, gin.H{
+		"slugs": slugs,
+		"count": len(slugs),
+	})
+}
+
+// ListTags returns every tag with how many live tricks carry it
+func (h *TrickHandler) ListTags(c *gin.Context) {
+	tags, err := h.trickService.GetTags(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTagsFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags":  tags,
+		"count": len(tags),
+	})
+}
+
+// GetNewTricks returns recently added tricks, newest first (the home screen's "new this week")
+// Query params: ?days= (1-90, default: the server's is_new window) and ?limit= (1-50, default 10).
+// "Within days" counts from midnight UTC, the same cutoff as is_new on the lists.
+func (h *TrickHandler) GetNewTricks(c *gin.Context) {
+	days, err := params.BoundedInt(c, "days", 1, services.MaxNewTrickDays, 0)
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+	limit, err := params.BoundedInt(c, "limit", 1, 50, 10)
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+
+	since := h.trickService.NewTricksSince(days)
+	if h.listNotModifiedSince(c, since) {
+		return
+	}
+
+	tricks, err := h.trickService.GetNewTricks(c.Request.Context(), since, limit)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTricksFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": tricks,
+		"count":  len(tricks),
+		"since":  since,
+	})
+}
+
+// GetPopularTricks returns the most viewed tricks in a recent window (the home screen's "popular this week")
+// Query params: ?window= (1d-30d, default 7d) and ?limit= (1-50, default 10).
+// Counts lag by up to one view-counter flush, so a short shared cache is fine.
+func (h *TrickHandler) GetPopularTricks(c *gin.Context) {
+	days := 7
+	if raw := c.Query("window"); raw != "" {
+		var err error
+		if days, err = services.ParsePopularWindow(raw); err != nil {
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidPopularWindow, gin.H{"max": services.MaxPopularWindowDays})
+			return
+		}
+	}
+	limit, err := params.BoundedInt(c, "limit", 1, 50, 10)
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+
+	tricks, since, err := h.trickService.GetPopularTricks(c.Request.Context(), days, limit)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodePopularTricksFailed)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": tricks,
+		"count":  len(tricks),
+		"since":  since,
+	})
+}
+
+// Trick export formats (?format=)
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFormatJSON   = "json"
+)
+
+// exportFormats are the accepted ?format= values, the default first
+var exportFormats = []string{exportFormatCSV, exportFormatNDJSON, exportFormatJSON}
+
+// exportTimeout bounds a catalog export - streaming routes have no Timeout middleware
+const exportTimeout = 60 * time.Second
+
+// exportCSVHeader is the CSV header row, in TrickExportRow order
+var exportCSVHeader = []string{
+	"name", "slug", "difficulty", "rotation", "takeoff_stance", "landing_stance",
+	"creator_name", "attribution", "license",
+}
+
+// ExportTricks downloads the catalog as CSV (the default), NDJSON or a JSON array
+// Query params: ?format=csv|ndjson|json plus the same filters as GET /tricks
+// (?min_difficulty=, ?max_difficulty=, ?takeoff_stance_id=, ?landing_stance_id=, ?tag=).
+// Rows are written as the database returns them - nothing is buffered. Once the first
+// row is out the status is 200, so a failure after that ends the download early
+// (see stream.go): the JSON array is still closed, and X-Stream-Status says so.
+func (h *TrickHandler) ExportTricks(c *gin.Context) {
+	format := c.DefaultQuery("format", exportFormatCSV)
+	if !slices.Contains(exportFormats, format) {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidExportFormat, gin.H{
+			"formats": exportFormats,
+		})
+		return
+	}
+	filter, ok := listFilterQuery(c, h.bands)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), exportTimeout)
+	defer cancel()
+
+	csvWriter := csv.NewWriter(c.Writer)
+	encoder := json.NewEncoder(c.Writer)
+	array := newJSONArrayStream(c.Writer)
+	started := false
+
+	// start sends the headers (and the CSV header row / opening bracket) just before
+	// the first row, so an error before any row can still be a normal JSON error response
+	start := func() error {
+		started = true
+		filename := fmt.Sprintf("tricks-%s.%s", time.Now().UTC().Format(time.DateOnly), format)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		declareStreamStatus(c)
+		switch format {
+		case exportFormatNDJSON:
+			c.Header("Content-Type", ndjsonContentType)
+			c.Status(http.StatusOK)
+			return nil
+		case exportFormatJSON:
+			c.Header("Content-Type", "application/json; charset=utf-8")
+			c.Status(http.StatusOK)
+			return array.Open()
+		}
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		return csvWriter.Write(exportCSVHeader)
+	}
+
+	err := h.trickService.ExportTricks(ctx, filter, func(row models.TrickExportRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		switch format {
+		case exportFormatNDJSON:
+			return encoder.Encode(row)
+		case exportFormatJSON:
+			return array.Write(row)
+		}
+		return csvWriter.Write(exportCSVRecord(row))
+	})
+	if err == nil && !started {
+		err = start() // Nothing matched - still a valid (empty) file
+	}
+	if started {
+		switch format {
+		case exportFormatCSV:
+			csvWriter.Flush()
+			if err == nil {
+				err = csvWriter.Error()
+			}
+		case exportFormatJSON:
+			if closeErr := array.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		finishStream(c, "trick export", err)
+		return
+	}
+
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDifficultyRange) {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeDifficultyRange)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTricksFailed)
+	}
+}
+
+// exportCSVRecord formats a row for CSV - NULLs become empty cells
+// Text cells are guarded against formula injection: spreadsheets run a cell
+// starting with =, +, - or @ as a formula, so those get a leading apostrophe.
+func exportCSVRecord(row models.TrickExportRow) []string {
+	return []string{
+		csvText(row.Name),
+		row.Slug,
+		csvOptionalInt(row.Difficulty),
+		csvOptionalInt(row.Rotation),
+		csvOptionalText(row.TakeoffStance),
+		csvOptionalText(row.LandingStance),
+		csvOptionalText(row.CreatorName),
+		csvOptionalText(row.Attribution),
+		csvOptionalText(row.License),
+	}
+}
+
+// csvText neutralizes a text cell a spreadsheet would read as a formula
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// csvOptionalText is csvText for a nullable column
+func csvOptionalText(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return csvText(*s)
+}
+
+// csvOptionalInt formats a nullable integer column
+func csvOptionalInt[T int | int64](n *T) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(*n), 10)
+}
+
+// GetTrickStats returns catalog-wide counts for the app's stats widget
+// The service caches the result for a few minutes, and so may clients.
+func (h *TrickHandler) GetTrickStats(c *gin.Context) {
+	stats, err := h.trickService.GetTrickStats(c.Request.Context())
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickStatsFailed)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetDifficultyHistogram returns how many tricks there are at each difficulty
+// Takes the same filters as GET /tricks, so a filter UI can grey out empty
+// ranges. Unrated tricks are the difficulty: null bucket (absent once a
+// difficulty bound is set). Difficulties no trick has are left out.
+func (h *TrickHandler) GetDifficultyHistogram(c *gin.Context) {
+	filter, ok := listFilterQuery(c, h.bands)
+	if !ok {
+		return
+	}
+
+	buckets, err := h.trickService.GetDifficultyHistogram(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDifficultyRange) {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeDifficultyRange)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeDifficultyHistogramFailed)
+		return
+	}
+
+	total := 0
+	for _, bucket := range buckets {
+		total += bucket.Count
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"histogram": buckets,
+		"total":     total,
+	})
+}
+
+// GetPrerequisites returns the tricks to learn before this one (direct prerequisites)
+func (h *TrickHandler) GetPrerequisites(c *gin.Context) {
+	h.respondTrickGraph(c, h.trickService.GetPrerequisites)
+}
+
+// GetUnlocks returns the tricks that list this one as a direct prerequisite
+func (h *TrickHandler) GetUnlocks(c *gin.Context) {
+	h.respondTrickGraph(c, h.trickService.GetUnlocks)
+}
+
+// respondTrickGraph serves one direction of the prerequisite graph for the :id trick
+func (h *TrickHandler) respondTrickGraph(c *gin.Context, load func(ctx context.Context, id string) ([]models.TrickSimpleResponse, error)) {
+	id, ok := trickIDParam(c)
+	if !ok {
+		return
+	}
+
+	tricks, err := load(c.Request.Context(), id)
 	if err != nil {
-		// Check for specific error types to return appropriate status codes
 		if errors.Is(err, services.ErrTrickNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Trick not found",
-			})
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
 			return
 		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodePrerequisitesFailed)
+		return
+	}
 
-		// For other errors, continue without caching
-		// (could also return error here, but we choose to be resilient)
-	} else {
-		// Step 2: Generate ETag from timestamp
-		etag := fmt.Sprintf(`"%d"`, lastModified)
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": tricks,
+		"count":  len(tricks),
+	})
+}
+
+// GetTrickChanges returns the tricks changed since ?since= (Unix seconds) for offline sync
+// Without since the client gets the whole catalog - that's a first sync.
+func (h *TrickHandler) GetTrickChanges(c *gin.Context) {
+	var since int64
+	if raw := c.Query("since"); raw != "" {
+		var err error
+		since, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || since < 0 {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidSince)
+			return
+		}
+	}
+
+	changes, err := h.trickService.GetTrickChanges(c.Request.Context(), since)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickChangesFailed)
+		return
+	}
+
+	// Each response embeds its own server_time - caching one would replay it
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, changes)
+}
+
+// GetCatalogDelta returns the tricks, categories and stances changed since
+// ?since_version= (the version of the client's last snapshot or delta)
+// Without since_version the client gets the full catalog. A version that's
+// unknown or older than the tombstones go back is a 409 with full_required:
+// the client should drop its copy and fetch the full catalog again.
+// Nothing changed is a 304. The new version is in the ETag either way, so a
+// client that hears nothing for weeks still moves its version forward.
+func (h *TrickHandler) GetCatalogDelta(c *gin.Context) {
+	sinceVersion := c.Query("since_version")
+
+	delta, err := h.trickService.GetCatalogDelta(c.Request.Context(), sinceVersion)
+	if err != nil {
+		if errors.Is(err, services.ErrFullSnapshotRequired) {
+			messages.RespondWith(c, http.StatusConflict, messages.CodeFullSnapshotRequired, gin.H{"full_required": true})
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeCatalogDeltaFailed)
+		return
+	}
+
+	// Each response carries its own version - caching one would replay it
+	c.Header("Cache-Control", "no-store")
+	c.Header("ETag", `"`+delta.Version+`"`)
+	if sinceVersion != "" && delta.Empty() {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, delta)
+}
+
+// snapshotHead is a full snapshot without its tricks - the outer Tricks field
+// hides the response's own, and being nil it's left out
+type snapshotHead struct {
+	*models.CatalogDeltaResponse
+	Tricks []models.TrickDetailResponse `json:"tricks,omitempty"`
+}
+
+// GetCatalogSnapshot streams the full catalog: what GetCatalogDelta returns
+// without since_version, written one trick at a time
+// The body is the same apart from field order (tricks come last), and so is
+// the ETag. Nothing is buffered, so the route has its own deadline instead of
+// the Timeout middleware; a failure mid-stream is handled as in stream.go.
+func (h *TrickHandler) GetCatalogSnapshot(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), exportTimeout)
+	defer cancel()
+
+	array := newJSONArrayStream(c.Writer)
+	started := false
+
+	start := func(head *models.CatalogDeltaResponse) error {
+		data, err := json.Marshal(snapshotHead{CatalogDeltaResponse: head})
+		if err != nil {
+			return err
+		}
+		started = true
+		c.Header("Cache-Control", "no-store")
+		c.Header("ETag", `"`+head.Version+`"`)
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		declareStreamStatus(c)
+		c.Status(http.StatusOK)
+
+		// Reopen the object to append the tricks array
+		data = append(data[:len(data)-1], `,"tricks":`...)
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+		return array.Open()
+	}
+
+	err := h.trickService.StreamCatalogSnapshot(ctx, start, func(trick models.TrickDetailResponse) error {
+		return array.Write(trick)
+	})
+	if !started {
+		log.Printf("Warning: catalog snapshot failed: %v", err)
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeCatalogDeltaFailed)
+		return
+	}
 
-		// Step 3: Check If-None-Match header BEFORE fetching full data
-		if c.GetHeader("If-None-Match") == etag {
-			c.Header("ETag", etag)
-			c.Status(http.StatusNotModified)
+	if closeErr := array.Close(); err == nil {
+		err = closeErr
+	}
+	if _, closeErr := c.Writer.WriteString("}"); err == nil {
+		err = closeErr
+	}
+	finishStream(c, "catalog snapshot", err)
+}
+
+// SearchTricks returns tricks matching a search query, best matches first
+// Query params: ?q=backfull (2-100 characters) &limit=20 (capped at 100)
+// Typos are tolerated when pg_trgm is installed: each result then carries a
+// score (0-1), and a top result with match_type "fuzzy" is the client's cue
+// to show "did you mean <name>?" rather than present it as an exact hit.
+func (h *TrickHandler) SearchTricks(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if len(query) > 100 {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeSearchQueryTooLong, gin.H{"max": 100})
+		return
+	}
+
+	limit, err := params.BoundedInt(c, "limit", 1, services.MaxSearchLimit, 20)
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+
+	results, err := h.trickService.SearchTricks(c.Request.Context(), query, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrSearchQueryTooShort) {
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeSearchQueryTooShort, gin.H{"min": services.MinSearchQueryLength})
 			return
 		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeSearchFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": results,
+		"count":  len(results),
+	})
+}
+
+// AutocompleteTricks returns up to 10 tricks whose name starts with the prefix
+// Query params: ?prefix=cork (1-100 characters). No matches is an empty list, not a 404.
+func (h *TrickHandler) AutocompleteTricks(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("prefix"))
+	if prefix == "" {
+		messages.Respond(c, http.StatusBadRequest, messages.CodePrefixRequired)
+		return
+	}
+	if len(prefix) > 100 {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeSearchQueryTooLong, gin.H{"max": 100})
+		return
+	}
+
+	tricks, err := h.trickService.AutocompleteTricks(c.Request.Context(), prefix)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeAutocompleteFailed)
+		return
+	}
+
+	// Suggestions are fine slightly stale, and type-ahead repeats prefixes a lot
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": tricks,
+		"count":  len(tricks),
+	})
+}
+
+// slugParam reads a trick slug from the path, lowercased
+// Slugs are stored lowercase, so /tricks/Backflip finds "backflip" instead of 404ing
+func slugParam(c *gin.Context, name string) string {
+	return strings.ToLower(c.Param(name))
+}
+
+// redirectToLowercaseSlug answers a trick read whose :id had uppercase letters
+// with a 301 to the same URL, slug lowercased - /tricks/Backflip -> /tricks/backflip -
+// so shared links converge on one cacheable URL. Returns true when it redirected.
+// Call it once the trick is found: a missing slug should 404, not redirect first.
+func redirectToLowercaseSlug(c *gin.Context, slug string) bool {
+	raw := c.Param("id")
+	if raw == slug {
+		return false
+	}
+
+	target := *c.Request.URL
+	target.RawPath = ""
+	segments := strings.Split(target.Path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] == raw {
+			segments[i] = slug
+			break
+		}
+	}
+	target.Path = strings.Join(segments, "/")
+
+	c.Redirect(http.StatusMovedPermanently, target.RequestURI())
+	return true
+}
+
+// trickIDPattern is what a trick ID can look like: a slug, or a legacy numeric ID
+var trickIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,99}$`)
+
+// trickIDParam is slugParam for the :id of the trick read routes
+// Malformed IDs get a 400 here instead of a pointless lookup and a 404.
+func trickIDParam(c *gin.Context) (string, bool) {
+	id := slugParam(c, "id")
+	if !trickIDPattern.MatchString(id) {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidTrickID)
+		return "", false
+	}
+	return id, true
+}
+
+// GetSimpleTrickById returns basic trick details
+func (h *TrickHandler) GetSimpleTrickById(c *gin.Context) {
+	// Parse ID from URL parameter (slugs are stored lowercase)
+	id, ok := trickIDParam(c)
+	if !ok {
+		return
+	}
 
-		// Set ETag for response
-		c.Header("ETag", etag)
+	// ?fields=id,name,... trims the response - validated before any lookup
+	fields, ok := parseFields(c, models.TrickDetailResponse{})
+	if !ok {
+		return
+	}
+	expand, ok := parseExpand(c)
+	if !ok {
+		return
+	}
+	locale, ok := contentLocale(c)
+	if !ok {
+		return
 	}
 
-	// Step 4: Fetch trick data (only if cache miss or ETag check failed)
-	trick, err := h.trickService.GetSimpleTrickById(c.Request.Context(), id)
+	// Step 1: Fetch the trick - its last-modified time comes from the same row,
+	// so one query serves both the 304 and the 200 path
+	trick, lastModified, err := h.trickService.GetSimpleTrickById(c.Request.Context(), id, locale)
 	if err != nil {
 		if errors.Is(err, services.ErrTrickNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Trick not found",
-			})
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve trick",
-		})
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickFailed)
+		return
+	}
+	if redirectToLowercaseSlug(c, id) {
 		return
 	}
 
-	// Step 5: Set cache headers
+	// Step 2: ETag + Last-Modified from the timestamp - 304 if the client
+	// already has this version (by If-None-Match or If-Modified-Since)
+	if notModifiedSince(c, lastModified) {
+		return
+	}
+
+	// Count the view, like the dictionary does (304s aren't counted on either,
+	// nor are HEAD freshness checks - nobody sees the trick)
+	if c.Request.Method != http.MethodHead {
+		h.trickService.RecordView(trick.ID)
+	}
+
+	if !expand[expandFlip] {
+		trick.FlipName = nil
+	}
+	if expand[expandStances] {
+		if err := h.stanceService.ExpandStances(c.Request.Context(), trick); err != nil {
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeStancesFailed)
+			return
+		}
+	}
+
+	// Step 3: Set cache headers
 	// Individual tricks change less frequently than lists, so longer cache
 	c.Header("Cache-Control", "public, max-age=86400, stale-while-revalidate=604800")
+	setContentLanguage(c, trick.Locale)
 
 	// Return response
+	c.JSON(http.StatusOK, fields.project(trick))
+}
+
+// GetDailyTrick returns the trick of the day (see services.DailyTrickSlug) with full details
+// ?date=YYYY-MM-DD picks another day's trick - otherwise today's, in UTC.
+// Responses are cached for up to an hour - today's never past midnight UTC, when it changes.
+func (h *TrickHandler) GetDailyTrick(c *gin.Context) {
+	date, err := params.Date(c, "date")
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+	locale, ok := contentLocale(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	day := now
+	if date != nil {
+		day = *date
+	}
+
+	trick, err := h.trickService.GetDailyTrick(c.Request.Context(), day, locale)
+	if err != nil {
+		if errors.Is(err, services.ErrTrickNotFound) {
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+			return
+		}
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickFailed)
+		return
+	}
+
+	// Any day's pick can change with the catalog, so an hour like the dictionary
+	maxAge := 3600
+	if date == nil {
+		nextMidnight := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+		maxAge = min(maxAge, int(nextMidnight.Sub(now).Seconds())+1)
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	setContentLanguage(c, trick.Locale)
+
 	c.JSON(http.StatusOK, trick)
 }
 
+// The ?expand= values trick details accept
+const (
+	expandStances = "stances"
+	expandFlip    = "flip"
+)
+
+// parseExpand reads ?expand= (comma-separated) into the set of values asked for
+// Returns ok=false after writing a 400 for any other value.
+func parseExpand(c *gin.Context) (map[string]bool, bool) {
+	expand, err := params.Fields(c, "expand", []string{expandStances, expandFlip})
+	if err != nil {
+		params.Respond(c, err)
+		return nil, false
+	}
+	return expand, true
+}
+
+// contentLocale is the language trick texts are wanted in: ?locale= when given,
+// otherwise the first two-letter language of Accept-Language ("" if there is none).
+// Only that one locale is tried - without a translation into it, texts stay English.
+// Returns ok=false after writing a 400 for a malformed ?locale=.
+func contentLocale(c *gin.Context) (string, bool) {
+	if locale := c.Query("locale"); locale != "" {
+		if !services.ValidContentLocale(locale) {
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidLocale, gin.H{"default": services.BaseLocale})
+			return "", false
+		}
+		return locale, true
+	}
+
+	// The response now depends on the header - caches must key on it
+	c.Header("Vary", "Accept-Language")
+	for _, lang := range messages.Languages(c.GetHeader("Accept-Language")) {
+		if services.ValidContentLocale(lang) {
+			return lang, true
+		}
+	}
+	return "", true
+}
+
+// setContentLanguage labels a trick response with the language of its texts
+// applied is the translation used ("" when the texts are the trick's own).
+func setContentLanguage(c *gin.Context, applied string) {
+	if applied == "" {
+		applied = services.BaseLocale
+	}
+	c.Header("Content-Language", applied)
+}
+
 // GetFullDetailsTrickById returns full trick details with videos
+// Response: models.TrickDictionaryResponse (its doc comment shows the JSON shape)
 func (h *TrickHandler) GetFullDetailsTrickById(c *gin.Context) {
-	// Parse ID from URL parameter
-	id := c.Param("id")
+	// Parse ID from URL parameter (slugs are stored lowercase)
+	id, ok := trickIDParam(c)
+	if !ok {
+		return
+	}
 
 	// Step 1: Get last modified timestamp for this trick
 	lastModified, err := h.trickService.GetLastModifiedByID(c.Request.Context(), id)
 	if err != nil {
 		// Check for specific error types
 		if errors.Is(err, services.ErrTrickNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Trick not found",
-			})
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
 			return
 		}
 
 		// For other errors, continue without caching
-	} else {
-		// Step 2: Generate ETag from timestamp
-		etag := fmt.Sprintf(`"%d"`, lastModified)
-
-		// Step 3: Check If-None-Match header BEFORE fetching data
-		if c.GetHeader("If-None-Match") == etag {
-			c.Header("ETag", etag)
-			c.Status(http.StatusNotModified)
-			return
-		}
+	} else if redirectToLowercaseSlug(c, id) {
+		return
+	} else if notModified(c, weakETag(lastModified)) {
+		// Steps 2-3: ETag from the timestamp, checked BEFORE assembling the dictionary
+		return
+	}
 
-		// Set ETag for response
-		c.Header("ETag", etag)
+	// Step 4: Fetch full trick details - ?include=featured_video,performers (default: everything)
+	includes, err := services.ParseDictionaryIncludes(c.Query("include"))
+	if err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeUnknownInclude, gin.H{"details": err.Error()})
+		return
 	}
 
-	// Step 4: Fetch full trick details with videos
-	trick, err := h.trickService.GetFullDetailsTrickById(c.Request.Context(), id)
+	locale, ok := contentLocale(c)
+	if !ok {
+		return
+	}
+	trick, err := h.trickService.GetTrickDictionary(c.Request.Context(), id, includes, locale)
 	if err != nil {
 		if errors.Is(err, services.ErrTrickNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Trick not found",
-			})
+			messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve trick details",
-		})
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickFailed)
 		return
 	}
 
+	// Count the view - the dictionary page is what users actually open
+	// (by the returned slug - id may be a legacy numeric ID)
+	h.trickService.RecordView(trick.ID)
+
 	// Step 5: Set cache headers
 	// Full details with videos - moderate cache duration
 	c.Header("Cache-Control", "public, max-age=3600, stale-while-revalidate=86400")
+	setContentLanguage(c, trick.Locale)
 
 	// Return response
 	c.JSON(http.StatusOK, trick)
 }
+
+// BatchGetDictionaries returns the dictionaries of several tricks in one call (combo detail pages)
+// Body: models.TrickDictionaryBatchRequest - {"slugs": ["cork", "raiz"]} (1-20 slugs).
+// ?include= and ?locale= work as on GET /tricks/:id/dictionary and apply to every trick.
+// Results follow the request order; a slug that can't be served carries its
+// code and error inline, so unknown slugs never fail the batch. Views aren't
+// counted - the cards are previews, the dictionary page is what counts.
+func (h *TrickHandler) BatchGetDictionaries(c *gin.Context) {
+	var req models.TrickDictionaryBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	includes, err := services.ParseDictionaryIncludes(c.Query("include"))
+	if err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeUnknownInclude, gin.H{"details": err.Error()})
+		return
+	}
+	locale, ok := contentLocale(c)
+	if !ok {
+		return
+	}
+
+	// Malformed slugs are answered here, like trickIDParam does for a single trick
+	response := models.TrickDictionaryBatchResponse{Results: make([]models.TrickDictionaryBatchItem, len(req.Slugs))}
+	lookups := make([]string, 0, len(req.Slugs))
+	for i, slug := range req.Slugs {
+		response.Results[i].Slug = slug
+		if id := strings.ToLower(strings.TrimSpace(slug)); trickIDPattern.MatchString(id) {
+			lookups = append(lookups, id)
+		}
+	}
+
+	errorLocale := messages.Locale(c.GetHeader("Accept-Language"))
+	results := h.trickService.GetTrickDictionaries(c.Request.Context(), lookups, includes, locale)
+	for i := range response.Results {
+		item := &response.Results[i]
+		id := strings.ToLower(strings.TrimSpace(item.Slug))
+		if !trickIDPattern.MatchString(id) {
+			item.Code = messages.CodeInvalidTrickID
+			item.Error = messages.Message(errorLocale, item.Code, nil)
+			continue
+		}
+
+		result := results[0]
+		results = results[1:]
+		switch {
+		case result.Err == nil:
+			item.Dictionary = result.Dictionary
+			response.Found++
+		case errors.Is(result.Err, services.ErrTrickNotFound):
+			item.Code = messages.CodeTrickNotFound
+			item.Error = messages.Message(errorLocale, item.Code, nil)
+		default:
+			log.Printf("Warning: batch dictionary for %q failed: %v", id, result.Err)
+			item.Code = messages.CodeTrickFailed
+			item.Error = messages.Message(errorLocale, item.Code, nil)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateTrick adds a trick to the catalog
+// Body: models.TrickCreateRequest - {"name": "Cork", "difficulty": 4, ...}.
+// The slug comes from the name ("cork", or "cork-2" if that's taken). Responds
+// 201 with the trick's details and its URL in Location; 409 if the name is
+// already a trick name or alias. The acting user is recorded as the creator.
+// A non-admin's trick is a 202 with "status": "pending" and no Location - it
+// isn't readable until an admin approves it.
+func (h *TrickHandler) CreateTrick(c *gin.Context) {
+	createdBy := actingUserID(c)
+	if createdBy == nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	var req models.TrickCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	trick, err := h.trickService.CreateTrick(c.Request.Context(), req, createdBy)
+	if err != nil {
+		h.respondTrickWriteError(c, err, req.Name)
+		return
+	}
+
+	if trick.Status == models.TrickPending {
+		c.JSON(http.StatusAccepted, trick)
+		return
+	}
+	c.Header("Location", "/api/v1/tricks/"+trick.ID)
+	c.JSON(http.StatusCreated, trick)
+}
+
+// GetTrickSubmissions lists the tricks a user created and where each is in review
+// Query params: ?limit=20 (1-100). Rejected tricks include the reviewer's reason.
+func (h *TrickHandler) GetTrickSubmissions(c *gin.Context) {
+	requestedUserID := c.Param("userId")
+
+	parsedRequestedID, err := uuid.Parse(requestedUserID)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	if !canAccessUser(c, requestedUserID) {
+		messages.Respond(c, http.StatusForbidden, messages.CodeForbiddenUser)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidLimit, gin.H{"min": 1, "max": 100})
+		return
+	}
+
+	tricks, err := h.trickService.GetTrickSubmissions(c.Request.Context(), parsedRequestedID, limit)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickSubmissionsFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": tricks,
+		"count":  len(tricks),
+	})
+}
+
+// ImportTrickBatch creates many tricks from one upload, all or nothing
+// The upload is a CSV (multipart "file" field, or a text/csv body) with a
+// header row, or a JSON array of POST /tricks bodies. ?dry_run=true validates
+// (slugs and name clashes included) without writing. Responds 201 when the
+// tricks were created, 200 for a clean dry run and 422 when any row is
+// invalid - each with a result per row. Uploads over the configured size are 413.
+func (h *TrickHandler) ImportTrickBatch(c *gin.Context) {
+	createdBy := actingUserID(c)
+	if createdBy == nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	dryRun, err := params.OneOf(c, "dry_run", []string{"false", "true"}, "false")
+	if err != nil {
+		params.Respond(c, err)
+		return
+	}
+
+	rows, err := parseTrickBatch(c)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			messages.RespondWith(c, http.StatusRequestEntityTooLarge, messages.CodeRequestTooLarge, gin.H{"max": tooLarge.Limit})
+		case errors.Is(err, errUnsupportedBatchFormat):
+			messages.Respond(c, http.StatusUnsupportedMediaType, messages.CodeUnsupportedImportFormat)
+		default:
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidTrickBatch, gin.H{"details": err.Error()})
+		}
+		return
+	}
+
+	locale := messages.Locale(c.GetHeader("Accept-Language"))
+	results := make([]models.TrickBatchResult, 0, len(rows))
+	emit := func(result models.TrickBatchResult, rowErr error) {
+		if rowErr != nil {
+			code, params := trickBatchRowCode(rowErr)
+			result.Code = code
+			result.Error = messages.Message(locale, code, params)
+		}
+		results = append(results, result)
+	}
+
+	summary, err := h.trickService.CreateTrickBatch(c.Request.Context(), rows, createdBy, dryRun == "true", emit)
+	if err != nil {
+		log.Printf("Warning: trick batch import failed: %v", err)
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeImportFailed)
+		return
+	}
+	summary.Results = results
+
+	switch {
+	case summary.Invalid > 0:
+		c.JSON(http.StatusUnprocessableEntity, summary)
+	case summary.Written:
+		c.JSON(http.StatusCreated, summary)
+	default:
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+// errUnsupportedBatchFormat is an upload that is neither CSV nor JSON
+var errUnsupportedBatchFormat = errors.New("unsupported upload format")
+
+// parseTrickBatch reads the rows of a batch upload, picking the parser by content type
+// A multipart file is JSON if its name ends in .json, CSV otherwise.
+func parseTrickBatch(c *gin.Context) ([]services.TrickBatchRow, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		return services.ParseTrickBatchJSON(c.Request.Body)
+	case "text/csv":
+		return services.ParseTrickBatchCSV(c.Request.Body)
+	case "multipart/form-data":
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if strings.HasSuffix(strings.ToLower(header.Filename), ".json") {
+			return services.ParseTrickBatchJSON(file)
+		}
+		return services.ParseTrickBatchCSV(file)
+	default:
+		return nil, errUnsupportedBatchFormat
+	}
+}
+
+// trickBatchRowCode maps a batch row error to its message code and placeholders
+func trickBatchRowCode(err error) (string, gin.H) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTrickName):
+		return messages.CodeInvalidTrickName, gin.H{"max": services.MaxImportNameLength}
+	case errors.Is(err, services.ErrInvalidImportDifficulty):
+		return messages.CodeInvalidImportDifficulty, gin.H{"min": services.MinImportDifficulty, "max": services.MaxImportDifficulty}
+	case errors.Is(err, services.ErrInvalidTrickRotation):
+		return messages.CodeInvalidTrickRotation, gin.H{"min": services.MinTrickRotation, "max": services.MaxTrickRotation}
+	case errors.Is(err, services.ErrUnknownTrickReference):
+		return messages.CodeUnknownTrickReference, nil
+	case errors.Is(err, services.ErrTrickNameTaken):
+		return messages.CodeTrickNameTaken, nil
+	case errors.Is(err, services.ErrDuplicateTrick):
+		return messages.CodeTrickSlugTaken, nil
+	default:
+		return messages.CodeInvalidTrickBatchRow, nil
+	}
+}
+
+// UpdateTrick replaces a trick's name, texts, difficulty, stances, flip and rotation
+// Body: models.TrickUpdateRequest - the create body, plus "regenerate_slug": true
+// to move the trick to a slug derived from its new name (the old one keeps
+// resolving). Responds 200 with the trick's details and its new ETag; 404 for
+// an unknown trick, 409 if the name belongs to another trick or an alias.
+func (h *TrickHandler) UpdateTrick(c *gin.Context) {
+	id, ok := trickIDParam(c)
+	if !ok {
+		return
+	}
+	changedBy := actingUserID(c)
+	if changedBy == nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	var req models.TrickUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	trick, lastModified, err := h.trickService.UpdateTrick(c.Request.Context(), id, req, changedBy)
+	if err != nil {
+		h.respondTrickWriteError(c, err, id)
+		return
+	}
+
+	c.Header("ETag", weakETag(lastModified))
+	c.Header("Last-Modified", time.Unix(lastModified, 0).UTC().Format(http.TimeFormat))
+	c.JSON(http.StatusOK, trick)
+}
+
+// PatchTrick changes only the fields in the body, leaving the rest as they are
+// Body: models.TrickPatchRequest - any subset of the create body's keys. A key
+// sent as null clears that field (name and difficulty can't be cleared); a key
+// left out is untouched. An empty object is a 400. Responds like UpdateTrick.
+func (h *TrickHandler) PatchTrick(c *gin.Context) {
+	id, ok := trickIDParam(c)
+	if !ok {
+		return
+	}
+	changedBy := actingUserID(c)
+	if changedBy == nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	var req models.TrickPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	trick, lastModified, err := h.trickService.PatchTrick(c.Request.Context(), id, req, changedBy)
+	if err != nil {
+		h.respondTrickWriteError(c, err, id)
+		return
+	}
+
+	c.Header("ETag", weakETag(lastModified))
+	c.Header("Last-Modified", time.Unix(lastModified, 0).UTC().Format(http.TimeFormat))
+	c.JSON(http.StatusOK, trick)
+}
+
+// respondTrickWriteError writes the error response for a failed create, update or patch
+func (h *TrickHandler) respondTrickWriteError(c *gin.Context, err error, trick string) {
+	switch {
+	case errors.Is(err, services.ErrTrickNotFound):
+		messages.Respond(c, http.StatusNotFound, messages.CodeTrickNotFound)
+	case errors.Is(err, services.ErrInvalidTrickName):
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidTrickName, gin.H{
+			"max": services.MaxImportNameLength,
+		})
+	case errors.Is(err, services.ErrUnknownTrickReference):
+		messages.Respond(c, http.StatusBadRequest, messages.CodeUnknownTrickReference)
+	case errors.Is(err, services.ErrTrickNameTaken):
+		messages.Respond(c, http.StatusConflict, messages.CodeTrickNameTaken)
+	case errors.Is(err, services.ErrDuplicateTrick):
+		messages.Respond(c, http.StatusConflict, messages.CodeTrickSlugTaken)
+	case errors.Is(err, services.ErrEmptyTrickPatch):
+		messages.Respond(c, http.StatusBadRequest, messages.CodeEmptyTrickPatch)
+	default:
+		log.Printf("Warning: saving trick %q failed: %v", trick, err)
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrickSaveFailed)
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakePatchTrickRepo holds one trick and writes only a patch's Columns, the way
+// the UPDATE's SET list does: a nil value there is a NULL
+type fakePatchTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	trick   models.Trick
+	patches []repository.TrickPatch
+}
+
+func (r *fakePatchTrickRepo) GetByID(ctx context.Context, slug string) (*models.Trick, error) {
+	if slug != r.trick.Slug {
+		return nil, repository.ErrNotFound
+	}
+	trick := r.trick
+	return &trick, nil
+}
+
+func (r *fakePatchTrickRepo) ExistsByName(ctx context.Context, name, exceptSlug string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakePatchTrickRepo) Patch(ctx context.Context, slug string, patch repository.TrickPatch, changedBy *uuid.UUID) (*models.Trick, error) {
+	r.patches = append(r.patches, patch)
+	for _, column := range patch.Columns {
+		switch column {
+		case "name":
+			r.trick.Name = patch.Name
+		case "difficulty":
+			r.trick.Difficulty = patch.Difficulty
+		case "description":
+			r.trick.Description = patch.Description
+		case "execution_notes":
+			r.trick.ExecutionNotes = patch.ExecutionNotes
+		case "takeoff_stance_id":
+			r.trick.TakeoffStanceID = patch.TakeoffStanceID
+		case "landing_stance_id":
+			r.trick.LandingStanceID = patch.LandingStanceID
+		case "flip_id":
+			r.trick.FlipID = patch.FlipID
+		case "rotation":
+			r.trick.Rotation = patch.Rotation
+		case "attribution":
+			r.trick.Attribution = patch.Attribution
+		case "license":
+			r.trick.License = patch.License
+		}
+	}
+	updatedAt := fixtures.Epoch.Add(time.Hour)
+	r.trick.UpdatedAt = &updatedAt
+	trick := r.trick
+	return &trick, nil
+}
+
+// fakeNoAliasRepo knows no aliases
+type fakeNoAliasRepo struct {
+	repository.AliasRepositoryInterface
+}
+
+func (r *fakeNoAliasRepo) ResolveSlug(ctx context.Context, aliasSlug string) (string, error) {
+	return "", repository.ErrNotFound
+}
+
+// patchRouter serves PATCH /tricks/:id as an admin, with a real TrickService over repo
+func patchRouter(repo *fakePatchTrickRepo) *gin.Engine {
+	service := services.NewTrickService(repo, nil, nil, nil, &fakeNoAliasRepo{}, nil, nil, nil, nil, nil, 7,
+		services.NewDifficultyBands(nil), 0, 0, 0, false)
+	router := gin.New()
+	router.PATCH("/tricks/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.NewString())
+	}, NewTrickHandler(service, nil, nil).PatchTrick)
+	return router
+}
+
+func TestPatchTrick(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantColumns []string
+		wantNull    []string // Columns cleared - left out of the response
+		check       func(t *testing.T, trick models.Trick)
+	}{
+		{
+			name:        "null clears a column",
+			body:        `{"description": null}`,
+			wantColumns: []string{"description"},
+			wantNull:    []string{"description"},
+			check: func(t *testing.T, trick models.Trick) {
+				if trick.Description != nil {
+					t.Errorf("description = %q, want NULL", *trick.Description)
+				}
+			},
+		},
+		{
+			name:        "omitted keys are left alone",
+			body:        `{"difficulty": 6}`,
+			wantColumns: []string{"difficulty"},
+			check: func(t *testing.T, trick models.Trick) {
+				if trick.Difficulty == nil || *trick.Difficulty != 6 {
+					t.Errorf("difficulty = %v, want 6", trick.Difficulty)
+				}
+				if deref(trick.Description) != "A backward flip" || deref(trick.ExecutionNotes) != "Jump up, not back" {
+					t.Errorf("untouched fields changed: %v / %v", deref(trick.Description), deref(trick.ExecutionNotes))
+				}
+			},
+		},
+		{
+			name:        "null and a value together",
+			body:        `{"execution_notes": null, "rotation": 720}`,
+			wantColumns: []string{"execution_notes", "rotation"},
+			wantNull:    []string{"execution_notes"},
+			check: func(t *testing.T, trick models.Trick) {
+				if trick.ExecutionNotes != nil || trick.Rotation == nil || *trick.Rotation != 720 {
+					t.Errorf("execution_notes = %v, rotation = %v; want NULL and 720", deref(trick.ExecutionNotes), trick.Rotation)
+				}
+				if deref(trick.Description) != "A backward flip" {
+					t.Errorf("description = %v, want it untouched", deref(trick.Description))
+				}
+			},
+		},
+		{
+			name:        "attribution and license",
+			body:        `{"attribution": " Tricking Bible ", "license": null}`,
+			wantColumns: []string{"attribution", "license"},
+			wantNull:    []string{"license"},
+			check: func(t *testing.T, trick models.Trick) {
+				if deref(trick.Attribution) != "Tricking Bible" || trick.License != nil {
+					t.Errorf("attribution = %v, license = %v; want Tricking Bible and NULL", deref(trick.Attribution), deref(trick.License))
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePatchTrickRepo{trick: fixtures.Trick().WithSlug("backflip").WithName("Backflip").
+				WithDifficulty(4).WithRotation(360).WithDescription("A backward flip", "Jump up, not back").
+				WithAttribution("Loopkicks", "CC BY 4.0").Build()}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, "/tricks/backflip", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			patchRouter(repo).ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+			}
+			if len(repo.patches) != 1 || !reflect.DeepEqual(repo.patches[0].Columns, tt.wantColumns) {
+				t.Fatalf("patched columns = %+v, want %v", repo.patches, tt.wantColumns)
+			}
+			if !reflect.DeepEqual(repo.patches[0].ChangedFields, tt.wantColumns) {
+				t.Errorf("changed fields = %v, want %v", repo.patches[0].ChangedFields, tt.wantColumns)
+			}
+			if w.Header().Get("ETag") == "" || w.Header().Get("Last-Modified") == "" {
+				t.Errorf("missing validators: ETag %q, Last-Modified %q", w.Header().Get("ETag"), w.Header().Get("Last-Modified"))
+			}
+			tt.check(t, repo.trick)
+
+			// The response is the stored trick: a cleared field is gone from it
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			for _, column := range tt.wantColumns {
+				if _, present := body[column]; present == slices.Contains(tt.wantNull, column) {
+					t.Errorf("response %s present = %v: %s", column, present, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPatchTrickRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "empty patch", id: "backflip", body: `{}`, wantStatus: http.StatusBadRequest, wantCode: messages.CodeEmptyTrickPatch},
+		{name: "only unknown keys", id: "backflip", body: `{"slug": "new"}`, wantStatus: http.StatusBadRequest, wantCode: messages.CodeEmptyTrickPatch},
+		{name: "null name", id: "backflip", body: `{"name": null}`, wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidRequest},
+		{name: "null difficulty", id: "backflip", body: `{"difficulty": null}`, wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidRequest},
+		{name: "difficulty out of range", id: "backflip", body: `{"difficulty": 11}`, wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidRequest},
+		{name: "wrong type", id: "backflip", body: `{"rotation": "720"}`, wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidRequest},
+		{name: "blank name", id: "backflip", body: `{"name": "  "}`, wantStatus: http.StatusBadRequest, wantCode: messages.CodeInvalidTrickName},
+		{name: "unknown trick", id: "no-such-trick", body: `{"difficulty": 5}`, wantStatus: http.StatusNotFound, wantCode: messages.CodeTrickNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePatchTrickRepo{trick: fixtures.Trick().WithSlug("backflip").WithName("Backflip").Build()}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, "/tricks/"+tt.id, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			patchRouter(repo).ServeHTTP(w, req)
+
+			var body struct {
+				Code string `json:"code"`
+			}
+			_ = json.Unmarshal(w.Body.Bytes(), &body)
+			if w.Code != tt.wantStatus || body.Code != tt.wantCode {
+				t.Errorf("got %d %s, want %d %s: %s", w.Code, body.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+			if len(repo.patches) != 0 {
+				t.Errorf("rejected patch reached the repository: %+v", repo.patches)
+			}
+		})
+	}
+}
+
+// deref is *s, or "<nil>" to tell NULL apart from empty
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
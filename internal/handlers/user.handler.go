@@ -6,11 +6,16 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
 	"tricking-api/internal/services"
 )
 
@@ -34,40 +39,39 @@ func (h *UserHandler) GetUserCombos(c *gin.Context) {
 
 	parsedRequestedID, err := uuid.Parse(requestedUserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid user ID format - must be a valid UUID",
-		})
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
 		return
 	}
 
 	// =========================================================================
 	// AUTHORIZATION CHECK
 	// =========================================================================
-	// Compare requested user vs authenticated user (from BFF header)
-	authenticatedUserID, exists := c.Get("user_id")
-
-	// If we have an authenticated user, verify they can access this resource
-	if exists && authenticatedUserID != "" {
-		// User can only view their own combos (unless admin)
-		if authenticatedUserID != requestedUserID {
-			userRole, _ := c.Get("user_role")
-			if userRole != "admin" {
-				c.JSON(http.StatusForbidden, gin.H{
-					"error": "You can only view your own combos",
-				})
-				return
-			}
-		}
+	if !canAccessUser(c, requestedUserID) {
+		messages.Respond(c, http.StatusForbidden, messages.CodeForbiddenUser)
+		return
 	}
 
 	// =========================================================================
 	// FETCH COMBOS
 	// =========================================================================
+	// ?summary=true returns totals only, skipping the per-combo trick lists
+	if c.Query("summary") == "true" {
+		summaries, err := h.userService.GetUserComboSummaries(c.Request.Context(), parsedRequestedID)
+		if err != nil {
+			messages.Respond(c, http.StatusInternalServerError, messages.CodeCombosFailed)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"combos": summaries,
+			"count":  len(summaries),
+		})
+		return
+	}
+
 	combos, err := h.userService.GetUserCombos(c.Request.Context(), parsedRequestedID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve combos",
-		})
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeCombosFailed)
 		return
 	}
 
@@ -76,3 +80,331 @@ func (h *UserHandler) GetUserCombos(c *gin.Context) {
 		"count":  len(combos),
 	})
 }
+
+// GetRecentTricks returns the tricks a user most recently used in their combos
+// Query params: ?limit=10 (1-50)
+func (h *UserHandler) GetRecentTricks(c *gin.Context) {
+	requestedUserID := c.Param("userId")
+
+	parsedRequestedID, err := uuid.Parse(requestedUserID)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	if !canAccessUser(c, requestedUserID) {
+		messages.Respond(c, http.StatusForbidden, messages.CodeForbiddenUser)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidLimit, gin.H{"min": 1, "max": 50})
+		return
+	}
+
+	tricks, err := h.userService.GetRecentTricks(c.Request.Context(), parsedRequestedID, limit)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeRecentTricksFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tricks": tricks,
+		"count":  len(tricks),
+	})
+}
+
+// GetTrainingCalendar returns a user's combo attempts per day, with streaks
+// Query params: ?from=YYYY-MM-DD&to=YYYY-MM-DD (both included, UTC; at most 366 days).
+// to defaults to today and from to a year before to.
+func (h *UserHandler) GetTrainingCalendar(c *gin.Context) {
+	requestedUserID := c.Param("userId")
+
+	parsedRequestedID, err := uuid.Parse(requestedUserID)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	if !canAccessUser(c, requestedUserID) {
+		messages.Respond(c, http.StatusForbidden, messages.CodeForbiddenUser)
+		return
+	}
+
+	to := time.Now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.DateOnly, raw); err != nil {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidTo)
+			return
+		}
+	}
+	from := to.AddDate(-1, 0, 1)
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse(time.DateOnly, raw); err != nil {
+			messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidFrom)
+			return
+		}
+	}
+
+	calendar, err := h.userService.GetTrainingCalendar(c.Request.Context(), parsedRequestedID, from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCalendarRange) {
+			messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidCalendarRange, gin.H{"max": services.MaxTrainingCalendarDays})
+			return
+		}
+
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeTrainingCalendarFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// CreateCombo saves a new combo for a user
+func (h *UserHandler) CreateCombo(c *gin.Context) {
+	requestedUserID := c.Param("userId")
+
+	parsedRequestedID, err := uuid.Parse(requestedUserID)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	if !canAccessUser(c, requestedUserID) {
+		messages.Respond(c, http.StatusForbidden, messages.CodeForbiddenUser)
+		return
+	}
+
+	var req models.ComboSaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	role, _ := c.Get("user_role")
+	roleName, _ := role.(string)
+
+	combo, err := h.userService.CreateCombo(c.Request.Context(), parsedRequestedID, roleName, req)
+	if err != nil {
+		respondComboSaveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, combo)
+}
+
+// ImportCombos saves up to 50 combos for a user in one request
+// Each combo is saved on its own: invalid ones are skipped, and once the
+// user's cap is reached the rest are reported as over_limit. Responds 201
+// when every combo was saved, 200 otherwise - with a result per combo either way.
+func (h *UserHandler) ImportCombos(c *gin.Context) {
+	requestedUserID := c.Param("userId")
+
+	parsedRequestedID, err := uuid.Parse(requestedUserID)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	if !canAccessUser(c, requestedUserID) {
+		messages.Respond(c, http.StatusForbidden, messages.CodeForbiddenUser)
+		return
+	}
+
+	var req models.ComboImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	role, _ := c.Get("user_role")
+	roleName, _ := role.(string)
+
+	locale := messages.Locale(c.GetHeader("Accept-Language"))
+	results := make([]models.ComboImportResult, 0, len(req.Combos))
+	emit := func(result models.ComboImportResult, rowErr error) {
+		if rowErr != nil {
+			_, code, params := comboSaveError(rowErr)
+			result.Code = code
+			result.Error = messages.Message(locale, code, params)
+		}
+		results = append(results, result)
+	}
+
+	summary, err := h.userService.ImportCombos(c.Request.Context(), parsedRequestedID, roleName, req.Combos, emit)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeComboSaveFailed)
+		return
+	}
+	summary.Results = results
+
+	if summary.Created == summary.Rows {
+		c.JSON(http.StatusCreated, summary)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// UpdateCombo replaces a saved combo's name, notes and tricks
+func (h *UserHandler) UpdateCombo(c *gin.Context) {
+	requestedUserID := c.Param("userId")
+
+	parsedRequestedID, err := uuid.Parse(requestedUserID)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	comboID, err := strconv.ParseInt(c.Param("comboId"), 10, 64)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidComboID)
+		return
+	}
+
+	if !canAccessUser(c, requestedUserID) {
+		messages.Respond(c, http.StatusForbidden, messages.CodeForbiddenUser)
+		return
+	}
+
+	var req models.ComboSaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	combo, err := h.userService.UpdateCombo(c.Request.Context(), parsedRequestedID, comboID, req)
+	if err != nil {
+		respondComboSaveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, combo)
+}
+
+// ReplaceComboTricks replaces a saved combo's trick list (reorder/add/remove)
+func (h *UserHandler) ReplaceComboTricks(c *gin.Context) {
+	requestedUserID := c.Param("userId")
+
+	parsedRequestedID, err := uuid.Parse(requestedUserID)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidUserID)
+		return
+	}
+
+	comboID, err := strconv.ParseInt(c.Param("comboId"), 10, 64)
+	if err != nil {
+		messages.Respond(c, http.StatusBadRequest, messages.CodeInvalidComboID)
+		return
+	}
+
+	if !canAccessUser(c, requestedUserID) {
+		messages.Respond(c, http.StatusForbidden, messages.CodeForbiddenUser)
+		return
+	}
+
+	var req models.ComboTricksReplaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	combo, err := h.userService.ReplaceComboTricks(c.Request.Context(), parsedRequestedID, comboID, req)
+	if err != nil {
+		respondComboSaveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, combo)
+}
+
+// BatchGetCombos returns several combos (from any users) in one call
+// Body: {"ids": [1, 2, 3]} (1-50 IDs). Combos the caller can't see come back in missing_ids.
+func (h *UserHandler) BatchGetCombos(c *gin.Context) {
+	var req models.ComboBatchGetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		messages.RespondWith(c, http.StatusBadRequest, messages.CodeInvalidRequest, gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userRole, _ := c.Get("user_role")
+	viewer := services.ComboViewer{
+		UserID:  actingUserID(c),
+		IsAdmin: userRole == "admin",
+	}
+
+	result, err := h.userService.BatchGetCombos(c.Request.Context(), req.IDs, viewer)
+	if err != nil {
+		messages.Respond(c, http.StatusInternalServerError, messages.CodeCombosFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondComboSaveError maps combo create/update errors to HTTP responses
+func respondComboSaveError(c *gin.Context, err error) {
+	status, code, params := comboSaveError(err)
+	messages.RespondWith(c, status, code, params)
+}
+
+// comboSaveError maps a combo save error to its status, message code and extra fields
+func comboSaveError(err error) (int, string, gin.H) {
+	var duplicateErr *services.DuplicateComboTricksError
+	if errors.As(err, &duplicateErr) {
+		return http.StatusUnprocessableEntity, messages.CodeDuplicateComboTricks, gin.H{
+			"duplicates": duplicateErr.Duplicates,
+		}
+	}
+
+	var limitErr *services.ComboLimitError
+	if errors.As(err, &limitErr) {
+		return http.StatusForbidden, messages.CodeComboLimitReached, gin.H{
+			"count": limitErr.Count,
+			"limit": limitErr.Limit,
+		}
+	}
+
+	switch {
+	case errors.Is(err, services.ErrComboNotFound):
+		return http.StatusNotFound, messages.CodeComboNotFound, nil
+	case errors.Is(err, services.ErrInvalidComboName):
+		return http.StatusBadRequest, messages.CodeInvalidComboName, nil
+	case errors.Is(err, services.ErrComboNoteTooLong):
+		return http.StatusBadRequest, messages.CodeComboNoteTooLong, nil
+	case errors.Is(err, services.ErrUnknownComboTrick):
+		// 422 - the body is well-formed but references a trick we don't have
+		return http.StatusUnprocessableEntity, messages.CodeUnknownComboTrick, nil
+	default:
+		return http.StatusInternalServerError, messages.CodeComboSaveFailed, nil
+	}
+}
+
+// canAccessUser reports whether the caller may read the requested user's data
+// Users can only access their own data, unless they are an admin
+func canAccessUser(c *gin.Context, requestedUserID string) bool {
+	// Compare requested user vs authenticated user (from BFF header)
+	authenticatedUserID, exists := c.Get("user_id")
+
+	// No authenticated user means the BFF is calling on its own behalf
+	if !exists || authenticatedUserID == "" {
+		return true
+	}
+
+	if authenticatedUserID == requestedUserID {
+		return true
+	}
+
+	userRole, _ := c.Get("user_role")
+	return userRole == "admin"
+}
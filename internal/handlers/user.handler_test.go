@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+)
+
+// fakeComboUserRepo serves combo summaries and per-combo tricks from memory
+type fakeComboUserRepo struct {
+	repository.UserRepositoryInterface
+
+	combos     []models.ComboSummary
+	tricks     map[int64][]models.ComboTrickResponse
+	trickLoads int
+}
+
+func (r *fakeComboUserRepo) GetComboSummariesByUserID(ctx context.Context, userID uuid.UUID) ([]models.ComboSummary, error) {
+	return r.combos, nil
+}
+
+func (r *fakeComboUserRepo) GetComboTricks(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error) {
+	r.trickLoads++
+	return r.tricks[comboID], nil
+}
+
+func TestGetUserCombosSummaryMode(t *testing.T) {
+	userID := uuid.New()
+	difficulty := int64(3)
+	newRepo := func() *fakeComboUserRepo {
+		return &fakeComboUserRepo{
+			combos: []models.ComboSummary{
+				{Combo: models.Combo{ID: 1, UserID: userID, Name: "Full", CreatedAt: time.Now()}, TrickCount: 1, TotalDifficulty: 3},
+				{Combo: models.Combo{ID: 2, UserID: userID, Name: "Empty", CreatedAt: time.Now()}},
+			},
+			tricks: map[int64][]models.ComboTrickResponse{
+				1: {{ID: "cork", Slug: "cork", Name: "Cork", Difficulty: &difficulty}},
+				// combo 2 has no tricks - the repository returns nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantTricks     bool
+		wantTrickCount []int // len(tricks) per combo when wantTricks
+		wantLoads      int
+	}{
+		{name: "full mode", query: "", wantTricks: true, wantTrickCount: []int{1, 0}, wantLoads: 2},
+		{name: "summary false", query: "?summary=false", wantTricks: true, wantTrickCount: []int{1, 0}, wantLoads: 2},
+		{name: "summary mode", query: "?summary=true", wantTricks: false, wantLoads: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo()
+			handler := NewUserHandler(services.NewUserService(repo, nil, config.ComboLimitConfig{}))
+
+			router := gin.New()
+			router.GET("/users/:userId/combos", handler.GetUserCombos)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/users/"+userID.String()+"/combos"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+			}
+
+			var body struct {
+				Combos []map[string]json.RawMessage `json:"combos"`
+				Count  int                          `json:"count"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if body.Count != 2 || len(body.Combos) != 2 {
+				t.Fatalf("got %d combos (count %d), want 2", len(body.Combos), body.Count)
+			}
+
+			for i, combo := range body.Combos {
+				for _, key := range []string{"id", "name", "trick_count", "total_difficulty", "created_at"} {
+					if _, ok := combo[key]; !ok {
+						t.Errorf("combo %d missing %q", i, key)
+					}
+				}
+
+				raw, ok := combo["tricks"]
+				if !tt.wantTricks {
+					if ok {
+						t.Errorf("combo %d has tricks %s in summary mode", i, raw)
+					}
+					continue
+				}
+				if !ok {
+					t.Fatalf("combo %d has no tricks key", i)
+				}
+				var tricks []json.RawMessage
+				if err := json.Unmarshal(raw, &tricks); err != nil || tricks == nil {
+					t.Fatalf("combo %d tricks = %s, want an array", i, raw)
+				}
+				if len(tricks) != tt.wantTrickCount[i] {
+					t.Errorf("combo %d has %d tricks, want %d", i, len(tricks), tt.wantTrickCount[i])
+				}
+			}
+
+			if repo.trickLoads != tt.wantLoads {
+				t.Errorf("loaded tricks %d times, want %d", repo.trickLoads, tt.wantLoads)
+			}
+		})
+	}
+}
+
+// fakeImportComboRepo knows two tricks and counts saved combos
+type fakeImportComboRepo struct {
+	repository.ComboRepositoryInterface
+
+	saved int
+}
+
+func (r *fakeImportComboRepo) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	return r.saved, nil
+}
+
+func (r *fakeImportComboRepo) ResolveTrickIDs(ctx context.Context, ids []string) (map[string]int, error) {
+	known := map[string]int{"cork": 1, "gainer": 2}
+	resolved := make(map[string]int)
+	for _, id := range ids {
+		if n, ok := known[id]; ok {
+			resolved[id] = n
+		}
+	}
+	return resolved, nil
+}
+
+func (r *fakeImportComboRepo) Create(ctx context.Context, userID uuid.UUID, name string, notes *string, tricks []repository.ComboTrick) (*models.Combo, error) {
+	r.saved++
+	return &models.Combo{ID: int64(r.saved), UserID: userID, Name: name}, nil
+}
+
+func (r *fakeImportComboRepo) GetTricksForCombo(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error) {
+	return nil, nil
+}
+
+func TestImportCombos(t *testing.T) {
+	userID := uuid.New()
+	combo := func(name string, tricks ...string) string {
+		items := make([]string, len(tricks))
+		for i, slug := range tricks {
+			items[i] = fmt.Sprintf(`{"trick_id":%q}`, slug)
+		}
+		return fmt.Sprintf(`{"name":%q,"tricks":[%s]}`, name, strings.Join(items, ","))
+	}
+	body := func(combos ...string) string {
+		return `{"combos":[` + strings.Join(combos, ",") + `]}`
+	}
+	tooMany := make([]string, 51)
+	for i := range tooMany {
+		tooMany[i] = combo("Cork", "cork")
+	}
+
+	tests := []struct {
+		name       string
+		saved      int
+		path       string
+		body       string
+		wantStatus int
+		wantRows   []string // status of each result
+		wantCodes  []string // code of each result
+	}{
+		{
+			name: "all saved", saved: 0, body: body(combo("A", "cork"), combo("B", "gainer")),
+			wantStatus: http.StatusCreated,
+			wantRows:   []string{models.ImportCreated, models.ImportCreated},
+			wantCodes:  []string{"", ""},
+		},
+		{
+			name: "cap hit mid-batch", saved: 4, body: body(combo("A", "cork"), combo("B", "nope"), combo("C", "gainer"), combo("D", "cork")),
+			wantStatus: http.StatusOK,
+			wantRows:   []string{models.ImportCreated, models.ImportInvalid, models.ImportOverLimit, models.ImportOverLimit},
+			wantCodes:  []string{"", messages.CodeUnknownComboTrick, messages.CodeComboLimitReached, messages.CodeComboLimitReached},
+		},
+		{name: "too many combos", body: body(tooMany...), wantStatus: http.StatusBadRequest},
+		{name: "combo without tricks", body: body(`{"name":"Empty","tricks":[]}`), wantStatus: http.StatusBadRequest},
+		{name: "bad user ID", path: "/users/nope/combos/import", body: body(combo("A", "cork")), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeImportComboRepo{saved: tt.saved}
+			handler := NewUserHandler(services.NewUserService(nil, repo, config.ComboLimitConfig{Default: 5}))
+
+			router := gin.New()
+			router.POST("/users/:userId/combos/import", handler.ImportCombos)
+
+			path := tt.path
+			if path == "" {
+				path = "/users/" + userID.String() + "/combos/import"
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantRows == nil {
+				return
+			}
+
+			var summary models.ComboImportSummary
+			if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if len(summary.Results) != len(tt.wantRows) {
+				t.Fatalf("got %d results, want %d", len(summary.Results), len(tt.wantRows))
+			}
+			for i, result := range summary.Results {
+				if result.Status != tt.wantRows[i] || result.Code != tt.wantCodes[i] {
+					t.Errorf("row %d = %s/%s, want %s/%s", i+1, result.Status, result.Code, tt.wantRows[i], tt.wantCodes[i])
+				}
+				// The cap's numbers are filled into the message
+				if result.Code == messages.CodeComboLimitReached && !strings.Contains(result.Error, "5 of 5") {
+					t.Errorf("row %d error = %q, want the count and limit", i+1, result.Error)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,483 @@
+// =============================================================================
+// FILE: internal/messages/messages.go
+// PURPOSE: User-facing error messages, keyed by stable error code, per locale
+// =============================================================================
+//
+// Every error response looks like:
+//
+//	{"error": "Trick not found", "code": "trick_not_found", "meta": {"version": "1.4.0"}}
+//
+// "code" never changes and is what clients should branch on. "error" is the
+// human-readable message, picked from the catalog for the caller's
+// Accept-Language and falling back to English.
+//
+// The catalogs live in catalog/<locale>.json and are embedded at build time.
+// To add a message: add a Code constant, register it in allCodes, and add it
+// to catalog/en.json (Load refuses to start without an English message).
+// =============================================================================
+
+package messages
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed catalog/*.json
+var catalogFiles embed.FS
+
+// DefaultLocale is used when the caller asks for nothing we have
+const DefaultLocale = "en"
+
+// Error codes - stable identifiers for machine use
+const (
+	// Auth & infrastructure
+	CodeInvalidAPIKey       = "invalid_api_key"
+	CodeAPIKeyNotAllowed    = "api_key_not_allowed"
+	CodeAPIKeyCheckFailed   = "api_key_check_failed"
+	CodeConflictingUserID   = "conflicting_user_id"
+	CodeConflictingUserRole = "conflicting_user_role"
+	CodeAdminRequired       = "admin_required"
+	CodeModeratorRequired   = "moderator_required"
+	CodeUnknownService      = "unknown_service"
+	CodeRateLimited         = "rate_limited"
+	CodeRequestTimeout      = "request_timeout"
+
+	// Request validation
+	CodeInvalidRequest        = "invalid_request"
+	CodeInvalidLimit          = "invalid_limit"
+	CodeInvalidOffset         = "invalid_offset"
+	CodeInvalidCursor         = "invalid_cursor"
+	CodeInvalidUserID         = "invalid_user_id"
+	CodeInvalidTrickID        = "invalid_trick_id"
+	CodeInvalidComboID        = "invalid_combo_id"
+	CodeInvalidVideoID        = "invalid_video_id"
+	CodeInvalidVideoURL       = "invalid_video_url"
+	CodeInvalidSize           = "invalid_size"
+	CodeSearchQueryTooShort   = "search_query_too_short"
+	CodeSearchQueryTooLong    = "search_query_too_long"
+	CodePrefixRequired        = "prefix_required"
+	CodeInvalidMinVotes       = "invalid_min_votes"
+	CodeInvalidMinDelta       = "invalid_min_delta"
+	CodeInvalidFrom           = "invalid_from"
+	CodeInvalidTo             = "invalid_to"
+	CodeInvalidDiffWindow     = "invalid_diff_window"
+	CodeInvalidDifficulty     = "invalid_difficulty"
+	CodeDifficultyRange       = "invalid_difficulty_range"
+	CodeInvalidStanceID       = "invalid_stance_id"
+	CodeInvalidCategoryID     = "invalid_category_id"
+	CodeTooManySlugs          = "too_many_slugs"
+	CodeInvalidIntParam       = "invalid_int_param"
+	CodeInvalidIntList        = "invalid_int_list"
+	CodeTooManyValues         = "too_many_values"
+	CodeInvalidUUIDParam      = "invalid_uuid_param"
+	CodeInvalidDateParam      = "invalid_date_param"
+	CodeUnknownDifficultyBand = "unknown_difficulty_band"
+	CodeUnknownInclude        = "unknown_include"
+	CodeInvalidSince          = "invalid_since"
+	CodeUnknownField          = "unknown_field"
+
+	// Tricks
+	CodeTrickNotFound      = "trick_not_found"
+	CodeTrickNotDeleted    = "trick_not_deleted"
+	CodePurgeWindowClosed  = "purge_window_closed"
+	CodeNoDifficultyVotes  = "no_difficulty_votes"
+	CodeTricksFailed       = "tricks_failed"
+	CodeTrickFailed        = "trick_failed"
+	CodeTrickSlugsFailed   = "trick_slugs_failed"
+	CodeTrickChangesFailed = "trick_changes_failed"
+	CodeTrickStatsFailed   = "trick_stats_failed"
+	CodeSearchFailed       = "search_failed"
+	CodeAutocompleteFailed = "autocomplete_failed"
+	CodeCategoriesFailed   = "categories_failed"
+	CodeCategoryNotFound   = "category_not_found"
+
+	// Combo generation
+	CodeInvalidComboSize   = "invalid_combo_size"
+	CodeInsufficientTricks = "insufficient_tricks"
+	CodeGenerationFailed   = "generation_failed"
+	CodeNoMatchingTrick    = "no_matching_trick"
+	CodeRandomTrickFailed  = "random_trick_failed"
+	CodeEstimateFailed     = "estimate_failed"
+
+	// Saved combos
+	CodeForbiddenUser        = "forbidden_user"
+	CodeComboNotFound        = "combo_not_found"
+	CodeInvalidComboName     = "invalid_combo_name"
+	CodeComboNoteTooLong     = "combo_note_too_long"
+	CodeUnknownComboTrick    = "unknown_combo_trick"
+	CodeDuplicateComboTricks = "duplicate_combo_tricks"
+	CodeComboLimitReached    = "combo_limit_reached"
+	CodeCombosFailed         = "combos_failed"
+	CodeComboSaveFailed      = "combo_save_failed"
+	CodeRecentTricksFailed   = "recent_tricks_failed"
+
+	// Admin
+	CodeVideoNotFound     = "video_not_found"
+	CodeVideoCheckTimeout = "video_check_timeout"
+	CodeVideoCheckFailed  = "video_check_failed"
+	CodeAdminActionFailed = "admin_action_failed"
+	CodeUnknownFixMode    = "unknown_fix_mode"
+
+	// Videos (also per-row codes of a bulk video import)
+	CodeDuplicateVideo       = "duplicate_video"
+	CodeInvalidVideoLabel    = "invalid_video_label"
+	CodeInvalidPerformerName = "invalid_performer_name"
+	CodeThumbnailRequired    = "thumbnail_required"
+
+	// Bulk import (per-line codes appear on NDJSON result lines)
+	CodeUnsupportedImportFormat = "unsupported_import_format"
+	CodeImportLineTooLong       = "import_line_too_long"
+	CodeInvalidImportLine       = "invalid_import_line"
+	CodeInvalidImportSlug       = "invalid_import_slug"
+	CodeInvalidImportName       = "invalid_import_name"
+	CodeInvalidImportDifficulty = "invalid_import_difficulty"
+	CodeInvalidImportWeight     = "invalid_import_weight"
+	CodeUnknownImportCategory   = "unknown_import_category"
+	CodeImportedTrickDeleted    = "imported_trick_deleted"
+	CodeImportFailed            = "import_failed"
+
+	// Public links
+	CodeInvalidLinkTTL       = "invalid_link_ttl"
+	CodeInvalidLinkSignature = "invalid_link_signature"
+	CodeLinkExpired          = "link_expired"
+	CodePublicLinksDisabled  = "public_links_disabled"
+	CodePublicLinkFailed     = "public_link_failed"
+
+	// External API keys (admin)
+	CodeInvalidAPIKeyOwner = "invalid_api_key_owner"
+	CodeInvalidAPIKeyScope = "invalid_api_key_scope"
+	CodeInvalidAPIKeyTier  = "invalid_api_key_tier"
+	CodeInvalidAPIKeyID    = "invalid_api_key_id"
+	CodeAPIKeyNotFound     = "api_key_not_found"
+	CodeAPIKeyFailed       = "api_key_failed"
+
+	// Moderation
+	CodeInvalidMistakeID       = "invalid_mistake_id"
+	CodeInvalidMistakeText     = "invalid_mistake_text"
+	CodeInvalidMistakeSeverity = "invalid_mistake_severity"
+	CodeMistakeNotFound        = "mistake_not_found"
+	CodeMistakeOrderMismatch   = "mistake_order_mismatch"
+	CodeModerationFailed       = "moderation_failed"
+
+	// Prerequisites
+	CodeUnknownPrerequisite  = "unknown_prerequisite"
+	CodePrerequisiteCycle    = "prerequisite_cycle"
+	CodePrerequisiteNotFound = "prerequisite_not_found"
+	CodePrerequisitesFailed  = "prerequisites_failed"
+
+	// Aliases
+	CodeInvalidAlias  = "invalid_alias"
+	CodeAliasTaken    = "alias_taken"
+	CodeAliasNotFound = "alias_not_found"
+
+	// Tags
+	CodeInvalidTag  = "invalid_tag"
+	CodeTagNotFound = "tag_not_found"
+	CodeTagsFailed  = "tags_failed"
+
+	// Popular tricks
+	CodeInvalidPopularWindow = "invalid_popular_window"
+	CodePopularTricksFailed  = "popular_tricks_failed"
+
+	// Export
+	CodeInvalidExportFormat = "invalid_export_format"
+
+	// Trick graph
+	CodeTrickGraphTooLarge = "trick_graph_too_large"
+	CodeTrickGraphFailed   = "trick_graph_failed"
+
+	// Trick merge
+	CodeMergeIntoSelf = "merge_into_self"
+
+	// Trick review
+	CodeTrickNotPending        = "trick_not_pending"
+	CodeInvalidRejectionReason = "invalid_rejection_reason"
+	CodeTrickSubmissionsFailed = "trick_submissions_failed"
+
+	// Stances
+	CodeStancesFailed = "stances_failed"
+
+	// Flips
+	CodeFlipsFailed = "flips_failed"
+
+	// Training calendar
+	CodeInvalidCalendarRange   = "invalid_calendar_range"
+	CodeTrainingCalendarFailed = "training_calendar_failed"
+
+	// Trick translations
+	CodeInvalidLocale          = "invalid_locale"
+	CodeInvalidTranslationName = "invalid_translation_name"
+	CodeEmptyTranslation       = "empty_translation"
+	CodeTranslationNotFound    = "translation_not_found"
+
+	// Difficulty histogram
+	CodeDifficultyHistogramFailed = "difficulty_histogram_failed"
+
+	// Trick writes
+	CodeInvalidTrickName      = "invalid_trick_name"
+	CodeUnknownTrickReference = "unknown_trick_reference"
+	CodeTrickNameTaken        = "trick_name_taken"
+	CodeTrickSlugTaken        = "trick_slug_taken"
+	CodeTrickSaveFailed       = "trick_save_failed"
+	CodeEmptyTrickPatch       = "empty_trick_patch"
+
+	// Catalog delta
+	CodeFullSnapshotRequired = "full_snapshot_required"
+	CodeCatalogDeltaFailed   = "catalog_delta_failed"
+
+	// Trick weights
+	CodeInvalidTrickWeight = "invalid_trick_weight"
+	CodeUnknownTrickSlugs  = "unknown_trick_slugs"
+
+	// Batch trick creation
+	CodeRequestTooLarge      = "request_too_large"
+	CodeInvalidTrickBatch    = "invalid_trick_batch"
+	CodeInvalidTrickBatchRow = "invalid_trick_batch_row"
+	CodeInvalidTrickRotation = "invalid_trick_rotation"
+)
+
+// allCodes is every code the API can return - each needs an English message
+var allCodes = []string{
+	CodeInvalidAPIKey, CodeAPIKeyNotAllowed, CodeAPIKeyCheckFailed, CodeConflictingUserID, CodeConflictingUserRole, CodeAdminRequired, CodeModeratorRequired,
+	CodeUnknownService, CodeRateLimited, CodeRequestTimeout,
+	CodeInvalidRequest, CodeInvalidLimit, CodeInvalidOffset, CodeInvalidCursor, CodeInvalidUserID, CodeInvalidTrickID,
+	CodeInvalidComboID, CodeInvalidVideoID, CodeInvalidVideoURL, CodeInvalidSize, CodeSearchQueryTooShort,
+	CodeSearchQueryTooLong, CodePrefixRequired, CodeInvalidMinVotes, CodeInvalidMinDelta,
+	CodeInvalidFrom, CodeInvalidTo, CodeInvalidDiffWindow, CodeInvalidDifficulty, CodeDifficultyRange,
+	CodeInvalidStanceID, CodeInvalidCategoryID, CodeTooManySlugs,
+	CodeInvalidIntParam, CodeInvalidIntList, CodeTooManyValues, CodeInvalidUUIDParam, CodeInvalidDateParam, CodeUnknownDifficultyBand,
+	CodeUnknownInclude, CodeInvalidSince, CodeUnknownField,
+	CodeTrickNotFound, CodeTrickNotDeleted, CodePurgeWindowClosed, CodeNoDifficultyVotes,
+	CodeTricksFailed, CodeTrickFailed, CodeTrickSlugsFailed, CodeTrickChangesFailed, CodeTrickStatsFailed, CodeSearchFailed,
+	CodeAutocompleteFailed, CodeCategoriesFailed, CodeCategoryNotFound,
+	CodeInvalidComboSize, CodeInsufficientTricks, CodeGenerationFailed,
+	CodeNoMatchingTrick, CodeRandomTrickFailed, CodeEstimateFailed,
+	CodeForbiddenUser, CodeComboNotFound, CodeInvalidComboName, CodeComboNoteTooLong,
+	CodeUnknownComboTrick, CodeDuplicateComboTricks, CodeComboLimitReached, CodeCombosFailed, CodeComboSaveFailed,
+	CodeRecentTricksFailed,
+	CodeVideoNotFound, CodeVideoCheckTimeout, CodeVideoCheckFailed, CodeAdminActionFailed, CodeUnknownFixMode,
+	CodeDuplicateVideo, CodeInvalidVideoLabel, CodeInvalidPerformerName, CodeThumbnailRequired,
+	CodeUnsupportedImportFormat, CodeImportLineTooLong, CodeInvalidImportLine, CodeInvalidImportSlug,
+	CodeInvalidImportName, CodeInvalidImportDifficulty, CodeInvalidImportWeight, CodeUnknownImportCategory, CodeImportedTrickDeleted, CodeImportFailed,
+	CodeInvalidLinkTTL, CodeInvalidLinkSignature, CodeLinkExpired, CodePublicLinksDisabled, CodePublicLinkFailed,
+	CodeInvalidAPIKeyOwner, CodeInvalidAPIKeyScope, CodeInvalidAPIKeyTier, CodeInvalidAPIKeyID, CodeAPIKeyNotFound, CodeAPIKeyFailed,
+	CodeInvalidMistakeID, CodeInvalidMistakeText, CodeInvalidMistakeSeverity, CodeMistakeNotFound,
+	CodeMistakeOrderMismatch, CodeModerationFailed,
+	CodeUnknownPrerequisite, CodePrerequisiteCycle, CodePrerequisiteNotFound, CodePrerequisitesFailed,
+	CodeInvalidAlias, CodeAliasTaken, CodeAliasNotFound,
+	CodeInvalidTag, CodeTagNotFound, CodeTagsFailed,
+	CodeInvalidPopularWindow, CodePopularTricksFailed,
+	CodeInvalidExportFormat,
+	CodeTrickGraphTooLarge, CodeTrickGraphFailed,
+	CodeMergeIntoSelf,
+	CodeTrickNotPending, CodeInvalidRejectionReason, CodeTrickSubmissionsFailed,
+	CodeStancesFailed,
+	CodeFlipsFailed,
+	CodeInvalidCalendarRange, CodeTrainingCalendarFailed,
+	CodeInvalidLocale, CodeInvalidTranslationName, CodeEmptyTranslation, CodeTranslationNotFound,
+	CodeDifficultyHistogramFailed,
+	CodeInvalidTrickName, CodeUnknownTrickReference, CodeTrickNameTaken, CodeTrickSlugTaken, CodeTrickSaveFailed, CodeEmptyTrickPatch,
+	CodeFullSnapshotRequired, CodeCatalogDeltaFailed,
+	CodeInvalidTrickWeight, CodeUnknownTrickSlugs,
+	CodeRequestTooLarge, CodeInvalidTrickBatch, CodeInvalidTrickBatchRow, CodeInvalidTrickRotation,
+}
+
+// catalogs maps locale -> code -> message template, filled in by Load
+var catalogs = map[string]map[string]string{}
+
+// apiVersion is echoed in every envelope's meta block, set by SetVersion
+var apiVersion string
+
+// SetVersion records the running API version (the newest changelog release)
+// Called once at startup, before the server starts handling requests
+func SetVersion(version string) {
+	apiVersion = version
+}
+
+// Load parses and validates the embedded catalogs
+// Called at startup so a missing English message stops the server from booting
+func Load() error {
+	files, err := catalogFiles.ReadDir("catalog")
+	if err != nil {
+		return fmt.Errorf("failed to read message catalogs: %w", err)
+	}
+
+	loaded := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		locale := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+
+		raw, err := catalogFiles.ReadFile("catalog/" + file.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read %s catalog: %w", locale, err)
+		}
+
+		var entries map[string]string
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return fmt.Errorf("failed to parse %s catalog: %w", locale, err)
+		}
+		loaded[locale] = entries
+	}
+
+	if err := validate(loaded); err != nil {
+		return err
+	}
+
+	catalogs = loaded
+	return nil
+}
+
+// validate checks every registered code has an English message
+// and no catalog contains codes we never return (usually a typo)
+func validate(loaded map[string]map[string]string) error {
+	english, ok := loaded[DefaultLocale]
+	if !ok {
+		return fmt.Errorf("message catalog %q is missing", DefaultLocale)
+	}
+
+	var missing []string
+	for _, code := range allCodes {
+		if strings.TrimSpace(english[code]) == "" {
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("english message catalog is missing codes: %s", strings.Join(missing, ", "))
+	}
+
+	known := make(map[string]bool, len(allCodes))
+	for _, code := range allCodes {
+		known[code] = true
+	}
+	for locale, entries := range loaded {
+		for code := range entries {
+			if !known[code] {
+				return fmt.Errorf("%s message catalog has unknown code %q", locale, code)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Message returns the message for code in locale, falling back to English,
+// then to the code itself. {name} placeholders are filled from params.
+func Message(locale, code string, params gin.H) string {
+	msg, ok := catalogs[locale][code]
+	if !ok {
+		if msg, ok = catalogs[DefaultLocale][code]; !ok {
+			msg = code
+		}
+	}
+
+	for name, value := range params {
+		msg = strings.ReplaceAll(msg, "{"+name+"}", fmt.Sprint(value))
+	}
+	return msg
+}
+
+// Locale picks the best catalog for an Accept-Language header
+// "es-MX,es;q=0.9,en;q=0.8" -> "es". Only the primary language tag is matched.
+func Locale(acceptLanguage string) string {
+	for _, lang := range Languages(acceptLanguage) {
+		if _, ok := catalogs[lang]; ok {
+			return lang
+		}
+	}
+	return DefaultLocale
+}
+
+// Languages lists the primary language tags of an Accept-Language header, most wanted first
+// "fr-CA,es;q=0.9,en;q=0.8" -> ["fr", "es", "en"]. Tags with q=0 are left out.
+// Unlike Locale, this isn't limited to the message catalogs (content may be
+// translated into languages the error messages aren't).
+func Languages(acceptLanguage string) []string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, qValue, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(qValue), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang != "" && q > 0 {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	// Stable sort keeps header order for equal weights
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	langs := make([]string, 0, len(candidates))
+	for _, cand := range candidates {
+		langs = append(langs, cand.lang)
+	}
+	return langs
+}
+
+// Respond writes an error response for code in the caller's language
+func Respond(c *gin.Context, status int, code string) {
+	RespondWith(c, status, code, nil)
+}
+
+// RespondWith is Respond plus extra fields (e.g. "details", "duplicates")
+// The extra fields also fill {name} placeholders in the message
+func RespondWith(c *gin.Context, status int, code string, fields gin.H) {
+	c.JSON(status, body(c, code, fields))
+}
+
+// Abort is Respond for middleware - it also stops the handler chain
+func Abort(c *gin.Context, status int, code string) {
+	AbortWith(c, status, code, nil)
+}
+
+// AbortWith is RespondWith for middleware
+func AbortWith(c *gin.Context, status int, code string, fields gin.H) {
+	c.AbortWithStatusJSON(status, body(c, code, fields))
+}
+
+// body builds the JSON error body and sets Content-Language
+func body(c *gin.Context, code string, fields gin.H) gin.H {
+	locale := Locale(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", locale)
+	return Envelope(locale, code, fields)
+}
+
+// envelopeKeys are the keys Envelope sets itself - fields can't replace them
+var envelopeKeys = map[string]bool{"error": true, "code": true, "meta": true}
+
+// Envelope is the error body for an already-chosen locale - for writers
+// that can't go through a gin.Context (e.g. the timeout middleware)
+// A field named like an envelope key still fills the message, but is left out of the body.
+func Envelope(locale, code string, fields gin.H) gin.H {
+	response := gin.H{
+		"error": Message(locale, code, fields),
+		"code":  code,
+	}
+	if apiVersion != "" {
+		response["meta"] = gin.H{"version": apiVersion}
+	}
+	for key, value := range fields {
+		if !envelopeKeys[key] {
+			response[key] = value
+		}
+	}
+	return response
+}
@@ -0,0 +1,275 @@
+package messages
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// declaredCodes parses messages.go and returns every Code* constant's value
+// Walking the source (rather than allCodes) catches a constant that was
+// declared but never registered.
+func declaredCodes(t *testing.T) map[string]string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "messages.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse messages.go: %v", err)
+	}
+
+	codes := map[string]string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				if !strings.HasPrefix(name.Name, "Code") || i >= len(value.Values) {
+					continue
+				}
+				lit, ok := value.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					t.Fatalf("%s is not a string literal", name.Name)
+				}
+				code, _ := strconv.Unquote(lit.Value)
+				codes[name.Name] = code
+			}
+		}
+	}
+	return codes
+}
+
+func TestEveryCodeHasEnglishMessage(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	declared := declaredCodes(t)
+	if len(declared) == 0 {
+		t.Fatal("found no Code constants")
+	}
+
+	registered := map[string]bool{}
+	for _, code := range allCodes {
+		if registered[code] {
+			t.Errorf("code %q is registered twice", code)
+		}
+		registered[code] = true
+	}
+
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := map[string]string{}
+	for _, name := range names {
+		code := declared[name]
+		t.Run(name, func(t *testing.T) {
+			if other, ok := values[code]; ok {
+				t.Errorf("%s and %s share the code %q", name, other, code)
+			}
+			values[code] = name
+
+			if !registered[code] {
+				t.Errorf("%s (%q) is not registered in allCodes", name, code)
+			}
+			if strings.TrimSpace(catalogs[DefaultLocale][code]) == "" {
+				t.Errorf("%s (%q) has no %s message", name, code, DefaultLocale)
+			}
+		})
+	}
+
+	if len(registered) != len(declared) {
+		t.Errorf("allCodes has %d codes, %d Code constants are declared", len(registered), len(declared))
+	}
+}
+
+// placeholder matches a {name} in a message template
+var placeholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+func TestTranslationsKeepPlaceholders(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for locale, entries := range catalogs {
+		if locale == DefaultLocale {
+			continue
+		}
+		for code, msg := range entries {
+			want := placeholder.FindAllString(catalogs[DefaultLocale][code], -1)
+			got := placeholder.FindAllString(msg, -1)
+			sort.Strings(want)
+			sort.Strings(got)
+			if len(want) == 0 && len(got) == 0 {
+				continue
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s %q placeholders = %v, English has %v", locale, code, got, want)
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	complete := map[string]string{}
+	for _, code := range allCodes {
+		complete[code] = "message"
+	}
+	withBlank := map[string]string{}
+	for code, msg := range complete {
+		withBlank[code] = msg
+	}
+	withBlank[CodeTrickNotFound] = "  "
+
+	tests := []struct {
+		name    string
+		loaded  map[string]map[string]string
+		wantErr string
+	}{
+		{name: "complete", loaded: map[string]map[string]string{"en": complete, "es": {CodeTrickNotFound: "x"}}},
+		{name: "no english", loaded: map[string]map[string]string{"es": complete}, wantErr: "missing"},
+		{name: "blank english message", loaded: map[string]map[string]string{"en": withBlank}, wantErr: CodeTrickNotFound},
+		{name: "unknown code", loaded: map[string]map[string]string{"en": complete, "es": {"trick_not_fuond": "x"}}, wantErr: "trick_not_fuond"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(tt.loaded)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("validate() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("validate() error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLocaleAndMessage(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLocale     string
+	}{
+		{name: "empty", acceptLanguage: "", wantLocale: "en"},
+		{name: "spanish region", acceptLanguage: "es-MX,es;q=0.9,en;q=0.8", wantLocale: "es"},
+		{name: "unsupported falls through", acceptLanguage: "fr-CA,es;q=0.9", wantLocale: "es"},
+		{name: "q ordering", acceptLanguage: "en;q=0.5,es;q=0.8", wantLocale: "es"},
+		{name: "q zero is refused", acceptLanguage: "es;q=0,en;q=0.1", wantLocale: "en"},
+		{name: "nothing supported", acceptLanguage: "de,fr", wantLocale: "en"},
+		{name: "case insensitive", acceptLanguage: "ES", wantLocale: "es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale := Locale(tt.acceptLanguage)
+			if locale != tt.wantLocale {
+				t.Errorf("Locale(%q) = %q, want %q", tt.acceptLanguage, locale, tt.wantLocale)
+			}
+			if msg := Message(locale, CodeTrickNotFound, nil); msg == "" || msg == CodeTrickNotFound {
+				t.Errorf("Message(%q, %q) = %q, want a real message", locale, CodeTrickNotFound, msg)
+			}
+		})
+	}
+
+	if got := Message("en", "not_a_code", nil); got != "not_a_code" {
+		t.Errorf("unknown code message = %q, want the code itself", got)
+	}
+	if got := Message("xx", CodeTrickNotFound, nil); got != catalogs[DefaultLocale][CodeTrickNotFound] {
+		t.Errorf("unknown locale message = %q, want the English one", got)
+	}
+}
+
+func TestEnvelopeMeta(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	defer SetVersion("")
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{name: "version set", version: "1.4.0", want: "1.4.0"},
+		{name: "version unset", version: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetVersion(tt.version)
+			envelope := Envelope(DefaultLocale, CodeTrickNotFound, nil)
+
+			meta, ok := envelope["meta"].(gin.H)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("meta = %v, want no meta block", meta)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("envelope %v has no meta block", envelope)
+			}
+			if meta["version"] != tt.want {
+				t.Errorf("meta.version = %v, want %q", meta["version"], tt.want)
+			}
+			if envelope["code"] != CodeTrickNotFound {
+				t.Errorf("code = %v, want %q", envelope["code"], CodeTrickNotFound)
+			}
+		})
+	}
+}
+
+func TestEnvelopeReservedFields(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	SetVersion("1.4.0")
+	defer SetVersion("")
+
+	tests := []struct {
+		name   string
+		fields gin.H
+	}{
+		{name: "code", fields: gin.H{"code": "something_else"}},
+		{name: "error", fields: gin.H{"error": "not the message"}},
+		{name: "meta", fields: gin.H{"meta": "not the meta block"}},
+		{name: "all of them", fields: gin.H{"code": "x", "error": "y", "meta": "z", "details": "kept"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope := Envelope(DefaultLocale, CodeTrickNotFound, tt.fields)
+
+			if envelope["code"] != CodeTrickNotFound {
+				t.Errorf("code = %v, want %q", envelope["code"], CodeTrickNotFound)
+			}
+			if want := Message(DefaultLocale, CodeTrickNotFound, tt.fields); envelope["error"] != want {
+				t.Errorf("error = %v, want %q", envelope["error"], want)
+			}
+			if meta, ok := envelope["meta"].(gin.H); !ok || meta["version"] != "1.4.0" {
+				t.Errorf("meta = %v, want version 1.4.0", envelope["meta"])
+			}
+			if details, ok := tt.fields["details"]; ok && envelope["details"] != details {
+				t.Errorf("details = %v, want %v", envelope["details"], details)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// =============================================================================
+// FILE: internal/metrics/metrics.go
+// PURPOSE: Minimal in-process counters exposed in Prometheus text format
+// =============================================================================
+//
+// We only need a handful of counters, so instead of pulling in the full
+// Prometheus client this package keeps labelled counters in memory and
+// renders them in the text exposition format on GET /metrics.
+//
+// USAGE:
+//   var requests = metrics.NewCounterVec("api_requests_total", "Requests served", "service")
+//   requests.Inc("bff")
+// =============================================================================
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// registry holds every counter created with NewCounterVec
+var registry = struct {
+	mu       sync.Mutex
+	counters []*CounterVec
+}{}
+
+// CounterVec is a monotonically increasing counter partitioned by label values
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]uint64 // key is label values joined by labelSeparator
+}
+
+// labelSeparator joins label values into a map key - it can't appear in label values we emit
+const labelSeparator = "\xff"
+
+// NewCounterVec creates and registers a counter with the given label names
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]uint64),
+	}
+
+	registry.mu.Lock()
+	registry.counters = append(registry.counters, c)
+	registry.mu.Unlock()
+
+	return c
+}
+
+// Inc adds one to the counter for the given label values
+// Values must be passed in the same order as the label names
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds n to the counter for the given label values
+func (c *CounterVec) Add(n uint64, labelValues ...string) {
+	if len(labelValues) != len(c.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", c.name, len(c.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, labelSeparator)
+	c.mu.Lock()
+	c.values[key] += n
+	c.mu.Unlock()
+}
+
+// WriteText renders every registered counter in Prometheus text format
+func WriteText(w io.Writer) error {
+	registry.mu.Lock()
+	counters := make([]*CounterVec, len(registry.counters))
+	copy(counters, registry.counters)
+	registry.mu.Unlock()
+
+	for _, c := range counters {
+		if err := c.writeText(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeText renders one counter, series sorted for stable output
+func (c *CounterVec) writeText(w io.Writer) error {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	values := make(map[string]uint64, len(c.values))
+	for key, v := range c.values {
+		values[key] = v
+	}
+	c.mu.Unlock()
+
+	sort.Strings(keys)
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s%s %d\n", c.name, c.formatLabels(key), values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatLabels turns a map key back into {name="value",...}
+func (c *CounterVec) formatLabels(key string) string {
+	if len(c.labelNames) == 0 {
+		return ""
+	}
+
+	labelValues := strings.Split(key, labelSeparator)
+	pairs := make([]string, len(c.labelNames))
+	for i, name := range c.labelNames {
+		pairs[i] = fmt.Sprintf("%s=%q", name, labelValues[i])
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
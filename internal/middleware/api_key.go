@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/ratelimit"
+)
+
+// ============================================================================
+// EXTERNAL API KEYS
+// ============================================================================
+
+// Context keys set for a request made with an external API key
+const (
+	APIKeyIDKey     = "api_key_id"
+	APIKeyOwnerKey  = "api_key_owner"
+	APIKeyScopesKey = "api_key_scopes"
+)
+
+// apiKeyScheme is the Authorization scheme of external keys ("Authorization: ApiKey <key>")
+const apiKeyScheme = "ApiKey"
+
+// APIKeyAuthenticator resolves an external API key (services.APIKeyService)
+// A nil key with a nil error means the key isn't valid (unknown or revoked).
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+// APIKeys lets third parties read the catalog with their own key
+//
+// HOW IT WORKS:
+//   - Requests without "Authorization: ApiKey ..." pass through untouched
+//   - A bad key is a 401; a good one may only GET/HEAD catalog routes (403 otherwise)
+//   - The key's ID, owner and scopes go on the context, and any user-id /
+//     user-role headers are dropped - external callers never act for a user
+//   - Each key gets its own token bucket, sized by its rate tier, in place of the
+//     shared public/internal buckets (RateLimit skips keyed requests)
+//
+// One APIKeys is shared by every route group that accepts keys, so a key has
+// one bucket however many groups it calls.
+type APIKeys struct {
+	auth     APIKeyAuthenticator
+	tiers    map[string]config.RateLimitConfig
+	limiters map[string]*ratelimit.KeyedLimiter
+}
+
+// NewAPIKeys creates the middleware's shared state - a keyed bucket per rate tier
+func NewAPIKeys(auth APIKeyAuthenticator, tiers map[string]config.RateLimitConfig) *APIKeys {
+	limiters := make(map[string]*ratelimit.KeyedLimiter, len(tiers))
+	for tier, cfg := range tiers {
+		limiters[tier] = ratelimit.NewKeyedLimiter(cfg.RequestsPerSecond, cfg.Burst)
+	}
+	return &APIKeys{auth: auth, tiers: tiers, limiters: limiters}
+}
+
+// Accept authenticates requests that send an external API key
+// routes limits which of the group's routes take one (by c.FullPath()) - none
+// means all of them. Must run before the group's RateLimit, and before
+// InternalAPIKey and ExtractUserContext where those apply.
+func (k *APIKeys) Accept(routes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, key, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !strings.EqualFold(scheme, apiKeyScheme) {
+			c.Next()
+			return
+		}
+
+		apiKey, err := k.auth.Authenticate(c.Request.Context(), strings.TrimSpace(key))
+		if err != nil {
+			log.Printf("Warning: API key lookup failed: %v", err)
+			messages.Abort(c, http.StatusServiceUnavailable, messages.CodeAPIKeyCheckFailed)
+			return
+		}
+		if apiKey == nil {
+			messages.Abort(c, http.StatusUnauthorized, messages.CodeInvalidAPIKey)
+			return
+		}
+
+		readOnly := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+		routeAllowed := len(routes) == 0 || slices.Contains(routes, c.FullPath())
+		if !readOnly || !routeAllowed || !apiKey.HasScope(models.APIKeyScopeCatalogRead) {
+			messages.Abort(c, http.StatusForbidden, messages.CodeAPIKeyNotAllowed)
+			return
+		}
+
+		c.Request.Header.Del("user-id")
+		c.Request.Header.Del("user-role")
+		c.Set(APIKeyIDKey, apiKey.ID)
+		c.Set(APIKeyOwnerKey, apiKey.Owner)
+		c.Set(APIKeyScopesKey, apiKey.Scopes)
+
+		// A tier dropped from the config since the key was issued falls back to standard
+		tier := apiKey.RateTier
+		if _, ok := k.limiters[tier]; !ok {
+			tier = config.APIKeyTierStandard
+		}
+		limitRequest(c, "api_key", k.tiers[tier], k.limiters[tier].For(strconv.FormatInt(apiKey.ID, 10)))
+	}
+}
+
+// hasAPIKey reports whether APIKeys.Accept authenticated the request
+func hasAPIKey(c *gin.Context) bool {
+	_, ok := c.Get(APIKeyIDKey)
+	return ok
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+)
+
+// fakeAuthenticator knows a fixed set of live keys
+type fakeAuthenticator struct {
+	keys map[string]*models.APIKey
+	err  error
+}
+
+func (a *fakeAuthenticator) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	return a.keys[key], nil
+}
+
+// apiKeyRouter serves a catalog route that takes keys, one that doesn't, and
+// an internal route behind InternalAPIKey - reporting what the handler saw
+func apiKeyRouter(auth APIKeyAuthenticator, tiers map[string]config.RateLimitConfig) *gin.Engine {
+	keys := NewAPIKeys(auth, tiers)
+	seen := func(c *gin.Context) {
+		id, _ := c.Get(APIKeyIDKey)
+		owner, _ := c.Get(APIKeyOwnerKey)
+		scopes, _ := c.Get(APIKeyScopesKey)
+		c.JSON(http.StatusOK, gin.H{
+			"id": id, "owner": owner, "scopes": scopes,
+			"user_id": c.GetHeader("user-id"), "user_role": c.GetHeader("user-role"),
+		})
+	}
+
+	router := gin.New()
+	catalog := router.Group("/tricks", keys.Accept("/tricks", "/tricks/:id"))
+	catalog.GET("", seen)
+	catalog.HEAD("", seen)
+	catalog.GET("/:id", seen)
+	catalog.POST("", seen)
+	catalog.GET("/:id/history", seen)
+	router.GET("/combos", keys.Accept(), InternalAPIKey("internal-secret"), seen)
+	return router
+}
+
+func TestAPIKeysAccept(t *testing.T) {
+	tiers := map[string]config.RateLimitConfig{config.APIKeyTierStandard: {RequestsPerSecond: 0.001, Burst: 100}}
+	auth := &fakeAuthenticator{keys: map[string]*models.APIKey{
+		"good":     {ID: 7, Owner: "Stats Site", Scopes: []string{models.APIKeyScopeCatalogRead}, RateTier: config.APIKeyTierStandard},
+		"unscoped": {ID: 8, Owner: "Old Key", Scopes: []string{}, RateTier: config.APIKeyTierStandard},
+	}}
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		auth          APIKeyAuthenticator
+		wantStatus    int
+		wantCode      string
+		wantKeyID     float64 // 0 = no key on the context
+	}{
+		{name: "no key", method: http.MethodGet, path: "/tricks", wantStatus: http.StatusOK},
+		{name: "other scheme passes through", method: http.MethodGet, path: "/tricks", authorization: "Bearer good", wantStatus: http.StatusOK},
+		{name: "good key", method: http.MethodGet, path: "/tricks", authorization: "ApiKey good", wantStatus: http.StatusOK, wantKeyID: 7},
+		{name: "scheme is case-insensitive", method: http.MethodGet, path: "/tricks/cork", authorization: "apikey  good ", wantStatus: http.StatusOK, wantKeyID: 7},
+		{name: "HEAD is read-only", method: http.MethodHead, path: "/tricks", authorization: "ApiKey good", wantStatus: http.StatusOK},
+		{name: "internal route accepts the key", method: http.MethodGet, path: "/combos", authorization: "ApiKey good", wantStatus: http.StatusOK, wantKeyID: 7},
+		{name: "unknown key", method: http.MethodGet, path: "/tricks", authorization: "ApiKey nope", wantStatus: http.StatusUnauthorized, wantCode: messages.CodeInvalidAPIKey},
+		{name: "empty key", method: http.MethodGet, path: "/tricks", authorization: "ApiKey", wantStatus: http.StatusUnauthorized, wantCode: messages.CodeInvalidAPIKey},
+		{
+			name: "lookup failed", method: http.MethodGet, path: "/tricks", authorization: "ApiKey good",
+			auth: &fakeAuthenticator{err: errors.New("db down")}, wantStatus: http.StatusServiceUnavailable, wantCode: messages.CodeAPIKeyCheckFailed,
+		},
+		{name: "write", method: http.MethodPost, path: "/tricks", authorization: "ApiKey good", wantStatus: http.StatusForbidden, wantCode: messages.CodeAPIKeyNotAllowed},
+		{name: "route not listed", method: http.MethodGet, path: "/tricks/cork/history", authorization: "ApiKey good", wantStatus: http.StatusForbidden, wantCode: messages.CodeAPIKeyNotAllowed},
+		{name: "no catalog scope", method: http.MethodGet, path: "/tricks", authorization: "ApiKey unscoped", wantStatus: http.StatusForbidden, wantCode: messages.CodeAPIKeyNotAllowed},
+		{name: "internal route without any key", method: http.MethodGet, path: "/combos", wantStatus: http.StatusUnauthorized, wantCode: messages.CodeInvalidAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authenticator APIKeyAuthenticator = auth
+			if tt.auth != nil {
+				authenticator = tt.auth
+			}
+			router := apiKeyRouter(authenticator, tiers)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			// Keyed callers never act for a user
+			req.Header.Set("user-id", "u1")
+			req.Header.Set("user-role", "admin")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.method == http.MethodHead {
+				return
+			}
+
+			var body struct {
+				Code     string   `json:"code"`
+				ID       float64  `json:"id"`
+				Owner    string   `json:"owner"`
+				Scopes   []string `json:"scopes"`
+				UserID   string   `json:"user_id"`
+				UserRole string   `json:"user_role"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if tt.wantCode != "" {
+				if body.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", body.Code, tt.wantCode)
+				}
+				return
+			}
+
+			if body.ID != tt.wantKeyID {
+				t.Errorf("key ID on the context = %v, want %v", body.ID, tt.wantKeyID)
+			}
+			if tt.wantKeyID == 0 {
+				if body.UserID != "u1" {
+					t.Errorf("user-id = %q, want it left alone without a key", body.UserID)
+				}
+				return
+			}
+			if body.Owner != "Stats Site" || !reflect.DeepEqual(body.Scopes, []string{models.APIKeyScopeCatalogRead}) {
+				t.Errorf("context owner / scopes = %q / %v", body.Owner, body.Scopes)
+			}
+			if body.UserID != "" || body.UserRole != "" {
+				t.Errorf("user headers = %q / %q, want them dropped", body.UserID, body.UserRole)
+			}
+			if w.Header().Get("X-RateLimit-Limit") != "100" {
+				t.Errorf("X-RateLimit-Limit = %q, want the tier's burst", w.Header().Get("X-RateLimit-Limit"))
+			}
+		})
+	}
+}
+
+func TestAPIKeysRateLimitPerKey(t *testing.T) {
+	tiers := map[string]config.RateLimitConfig{
+		config.APIKeyTierStandard: {RequestsPerSecond: 0.001, Burst: 2},
+		config.APIKeyTierPartner:  {RequestsPerSecond: 0.001, Burst: 5},
+	}
+	scopes := []string{models.APIKeyScopeCatalogRead}
+	router := apiKeyRouter(&fakeAuthenticator{keys: map[string]*models.APIKey{
+		"first":   {ID: 1, Scopes: scopes, RateTier: config.APIKeyTierStandard},
+		"second":  {ID: 2, Scopes: scopes, RateTier: config.APIKeyTierStandard},
+		"partner": {ID: 3, Scopes: scopes, RateTier: config.APIKeyTierPartner},
+		"retired": {ID: 4, Scopes: scopes, RateTier: "gold"}, // Tier since dropped from the config
+	}}, tiers)
+
+	get := func(key, path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "ApiKey "+key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		key   string
+		allow int // Requests before the 429
+	}{
+		{key: "first", allow: 2},
+		{key: "second", allow: 2}, // Its own bucket, untouched by first's
+		{key: "partner", allow: 5},
+		{key: "retired", allow: 2}, // Falls back to standard
+	}
+
+	for _, tt := range tests {
+		// One bucket per key, whichever of its routes it calls
+		paths := []string{"/tricks", "/tricks/cork", "/combos"}
+		for i := range tt.allow {
+			if status := get(tt.key, paths[i%len(paths)]); status != http.StatusOK {
+				t.Fatalf("%s request %d = %d, want 200", tt.key, i+1, status)
+			}
+		}
+		if status := get(tt.key, "/combos"); status != http.StatusTooManyRequests {
+			t.Errorf("%s request %d = %d, want 429", tt.key, tt.allow+1, status)
+		}
+	}
+}
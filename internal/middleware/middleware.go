@@ -1,21 +1,37 @@
 package middleware
 
 import (
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/metrics"
+	"tricking-api/internal/ratelimit"
+	"tricking-api/internal/viewer"
 )
 
 // InternalAPIKey validates that requests come from your BFF
-// This is a simple approach - the BFF sends a secret API key
+// This is a simple approach - the BFF sends a secret API key.
+// Requests already authenticated by an external API key (APIKeys) pass too.
 func InternalAPIKey(expectedKey string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if hasAPIKey(c) {
+			c.Next()
+			return
+		}
+
 		apiKey := c.GetHeader("internal-api-key")
 
 		if apiKey == "" || apiKey != expectedKey {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid or missing API key",
-			})
+			messages.Abort(c, http.StatusUnauthorized, messages.CodeInvalidAPIKey)
 			return
 		}
 
@@ -25,11 +41,39 @@ func InternalAPIKey(expectedKey string) gin.HandlerFunc {
 
 // ExtractUserContext pulls user info that the BFF passes in headers
 // The BFF already authenticated the user - we just need their ID
-func ExtractUserContext() gin.HandlerFunc {
+//
+// HARDENING:
+// - Several differing user-id values (e.g. a stale one added by a proxy) -> 400
+// - A user-id/user-role header that is present but empty is treated as absent
+// - user-role: admin from a service not on the admin allow-list is logged
+func ExtractUserContext(adminServices []string) gin.HandlerFunc {
+	adminAllowed := make(map[string]bool, len(adminServices))
+	for _, name := range adminServices {
+		adminAllowed[name] = true
+	}
+
 	return func(c *gin.Context) {
 		// BFF sends user info in headers after authenticating them
-		userID := c.GetHeader("user-id")
-		userRole := c.GetHeader("user-role")
+		userID, ok := singleHeaderValue(c, "user-id")
+		if !ok {
+			messages.Abort(c, http.StatusBadRequest, messages.CodeConflictingUserID)
+			return
+		}
+		userRole, ok := singleHeaderValue(c, "user-role")
+		if !ok {
+			messages.Abort(c, http.StatusBadRequest, messages.CodeConflictingUserRole)
+			return
+		}
+
+		if userRole == "admin" {
+			serviceName, _ := c.Get("service_name")
+			name, _ := serviceName.(string)
+			if !adminAllowed[name] {
+				requestID, _ := c.Get("request_id")
+				log.Printf("SECURITY: admin role asserted by non-admin service %q (request_id=%v, path=%s)",
+					name, requestID, c.Request.URL.Path)
+			}
+		}
 
 		// Store in context for handlers to use
 		if userID != "" {
@@ -37,8 +81,263 @@ func ExtractUserContext() gin.HandlerFunc {
 		}
 		if userRole != "" {
 			c.Set("user_role", userRole)
+			// Services read it from the request context (see the viewer package)
+			c.Request = c.Request.WithContext(viewer.WithRole(c.Request.Context(), userRole))
+		}
+
+		c.Next()
+	}
+}
+
+// singleHeaderValue returns the one non-empty value of a header
+// Empty values are ignored; ok is false if the non-empty values disagree
+func singleHeaderValue(c *gin.Context, key string) (value string, ok bool) {
+	for _, v := range c.Request.Header.Values(key) {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if value != "" && v != value {
+			return "", false
+		}
+		value = v
+	}
+	return value, true
+}
+
+// StripTrailingSlash serves "/tricks/" as "/tricks" instead of 404ing or redirecting
+// It wraps the whole router because gin matches routes before any gin middleware runs.
+// We rewrite rather than redirect: a 301 would go back through the BFF as a second round trip.
+func StripTrailingSlash(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.Path) > 1 && strings.HasSuffix(r.URL.Path, "/") {
+			r.URL.Path = strings.TrimRight(r.URL.Path, "/")
+			if r.URL.Path == "" {
+				r.URL.Path = "/"
+			}
+			// RawPath is only set for paths with escapes that don't round-trip (%2F);
+			// keep it in step so the two never disagree
+			if r.URL.RawPath != "" {
+				r.URL.RawPath = strings.TrimRight(r.URL.RawPath, "/")
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequestID tags every request with an ID for correlating logs
+// Reuses the caller's X-Request-ID if it sent one, otherwise generates a new one
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header("X-Request-ID", requestID)
+
+		c.Next()
+	}
+}
+
+// APIVersion stamps every response with the running API version
+// Clients compare this against the changelog to detect a newer server.
+// Success bodies are bare resources with no envelope to carry it, so the
+// header is the one place every response has it; error envelopes also
+// repeat it in their meta block (see messages.SetVersion).
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("X-API-Version", version)
+		c.Next()
+	}
+}
+
+// MaxBodySize rejects request bodies over limit bytes with 413
+// A declared Content-Length over the limit is refused up front; otherwise the
+// body is wrapped so reading past the limit fails with *http.MaxBytesError,
+// which the handler answers with the same 413.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			messages.AbortWith(c, http.StatusRequestEntityTooLarge, messages.CodeRequestTooLarge, gin.H{"max": limit})
+			return
 		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
 
+// Deprecated marks responses from a route that has a newer spelling
+// Sends the Deprecation header (RFC 9745: "@" + Unix time it was deprecated)
+// so clients can log and migrate
+func Deprecated(since time.Time) gin.HandlerFunc {
+	value := "@" + strconv.FormatInt(since.Unix(), 10)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", value)
 		c.Next()
 	}
 }
+
+// RequireAdmin rejects requests whose BFF-supplied role is not "admin"
+// Must run after ExtractUserContext
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, _ := c.Get("user_role"); role != "admin" {
+			messages.Abort(c, http.StatusForbidden, messages.CodeAdminRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireModerator rejects requests whose BFF-supplied role can't curate content
+// Admins can do everything moderators can. Must run after ExtractUserContext
+func RequireModerator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, _ := c.Get("user_role"); role != "moderator" && role != "admin" {
+			messages.Abort(c, http.StatusForbidden, messages.CodeModeratorRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestsByService counts requests per calling internal service
+// Unknown names are grouped under "unknown" so a bad caller can't explode the label set
+var requestsByService = metrics.NewCounterVec(
+	"api_requests_by_service_total",
+	"Requests received, labelled by the calling internal service",
+	"service",
+)
+
+// ServiceIdentity records which internal service made the request
+// The BFF API key says "this is one of ours"; the service-name header says which one.
+// Unknown or missing names are logged but allowed here - use RequireService to enforce
+func ServiceIdentity(allowedServices []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allowedServices))
+	for _, name := range allowedServices {
+		allowed[name] = true
+	}
+
+	return func(c *gin.Context) {
+		serviceName := c.GetHeader("service-name")
+
+		switch {
+		case serviceName == "":
+			requestsByService.Inc("none")
+		case allowed[serviceName]:
+			c.Set("service_name", serviceName)
+			requestsByService.Inc(serviceName)
+		default:
+			log.Printf("Warning: request from unknown service %q to %s", serviceName, c.Request.URL.Path)
+			c.Set("unknown_service_name", serviceName)
+			requestsByService.Inc("unknown")
+		}
+
+		c.Next()
+	}
+}
+
+// RequireService rejects requests that didn't come from an allow-listed service
+// Must run after ServiceIdentity. Used on admin routes so audit records know the caller
+func RequireService() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("service_name"); !ok {
+			messages.Abort(c, http.StatusForbidden, messages.CodeUnknownService)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Rate limit metrics, labelled by route group
+var (
+	rateLimitRejected = metrics.NewCounterVec(
+		"api_rate_limit_rejected_total",
+		"Requests rejected with 429 by the rate limiter",
+		"group",
+	)
+	rateLimitQueued = metrics.NewCounterVec(
+		"api_rate_limit_queued_total",
+		"Requests that had to wait for a rate limit token",
+		"group",
+	)
+	rateLimitQueuedTime = metrics.NewCounterVec(
+		"api_rate_limit_queued_milliseconds_total",
+		"Total time requests spent waiting for a rate limit token",
+		"group",
+	)
+)
+
+// RateLimit limits a route group with a shared token bucket
+//
+// MODES:
+// - hard:  429 as soon as the bucket is empty
+// - queue: wait up to cfg.MaxWait for a token, 429 only if the wait would be longer
+//
+// Every response from the group - allowed or not - carries the bucket's state,
+// so clients can pace themselves instead of waiting for a 429:
+// - X-RateLimit-Limit:     requests allowed back to back (the burst)
+// - X-RateLimit-Remaining: requests still allowed right now, after this one
+// - X-RateLimit-Reset:     seconds until the bucket is full again (0 = already full)
+//
+// The bucket refills continuously, so Remaining goes up before Reset reaches 0.
+//
+// Requests with an external API key are skipped - APIKeys limits them per key.
+func RateLimit(group string, cfg config.RateLimitConfig) gin.HandlerFunc {
+	limiter := ratelimit.NewLimiter(cfg.RequestsPerSecond, cfg.Burst)
+
+	return func(c *gin.Context) {
+		if hasAPIKey(c) {
+			c.Next()
+			return
+		}
+		limitRequest(c, group, cfg, limiter)
+	}
+}
+
+// RateLimitByIP is RateLimit with one bucket per client IP
+// For routes open to anyone (no API key), so one client can't use up everyone's budget.
+// c.ClientIP honours X-Forwarded-For only from the router's trusted proxies.
+// The X-RateLimit-* headers describe the caller's own bucket.
+func RateLimitByIP(group string, cfg config.RateLimitConfig) gin.HandlerFunc {
+	limiters := ratelimit.NewKeyedLimiter(cfg.RequestsPerSecond, cfg.Burst)
+
+	return func(c *gin.Context) {
+		limitRequest(c, group, cfg, limiters.For(c.ClientIP()))
+	}
+}
+
+// limitRequest takes a token from limiter (per cfg.Mode) or aborts with 429
+func limitRequest(c *gin.Context, group string, cfg config.RateLimitConfig, limiter *ratelimit.Limiter) {
+	var state ratelimit.State
+	allowed := false
+	if cfg.Mode == config.RateLimitQueue {
+		var waited time.Duration
+		waited, state, allowed = limiter.Wait(c.Request.Context(), cfg.MaxWait)
+		if allowed && waited > 0 {
+			rateLimitQueued.Inc(group)
+			rateLimitQueuedTime.Add(uint64(waited.Milliseconds()), group)
+		}
+	} else {
+		state, allowed = limiter.Allow()
+	}
+
+	// Set before the handler runs, so they survive on success too
+	c.Header("X-RateLimit-Limit", strconv.Itoa(state.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(state.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(state.Reset.Seconds()))))
+
+	if !allowed {
+		rateLimitRejected.Inc(group)
+		c.Header("Retry-After", "1")
+		messages.Abort(c, http.StatusTooManyRequests, messages.CodeRateLimited)
+		return
+	}
+
+	c.Next()
+}
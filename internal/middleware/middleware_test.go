@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+	"tricking-api/internal/viewer"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	if err := messages.Load(); err != nil {
+		log.Fatalf("failed to load messages: %v", err)
+	}
+	os.Exit(m.Run())
+}
+
+// captureLog redirects the standard logger for the duration of a test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestExtractUserContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    [][2]string // repeated keys are sent as separate header lines
+		service    string
+		wantStatus int
+		wantCode   string
+		wantUserID string
+		wantRole   string
+		wantWarn   bool
+	}{
+		{name: "no headers", wantStatus: http.StatusOK},
+		{
+			name:       "single user-id",
+			headers:    [][2]string{{"user-id", "u1"}},
+			wantStatus: http.StatusOK, wantUserID: "u1",
+		},
+		{
+			name:       "duplicate matching user-id",
+			headers:    [][2]string{{"user-id", "u1"}, {"user-id", "u1"}},
+			wantStatus: http.StatusOK, wantUserID: "u1",
+		},
+		{
+			name:       "conflicting user-id",
+			headers:    [][2]string{{"user-id", "u1"}, {"user-id", "u2"}},
+			wantStatus: http.StatusBadRequest, wantCode: messages.CodeConflictingUserID,
+		},
+		{
+			name:       "empty user-id is absent",
+			headers:    [][2]string{{"user-id", "  "}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "empty duplicate ignored",
+			headers:    [][2]string{{"user-id", ""}, {"user-id", "u1"}},
+			wantStatus: http.StatusOK, wantUserID: "u1",
+		},
+		{
+			name:       "conflicting user-role",
+			headers:    [][2]string{{"user-role", "user"}, {"user-role", "admin"}},
+			wantStatus: http.StatusBadRequest, wantCode: messages.CodeConflictingUserRole,
+		},
+		{
+			name:       "admin from admin service",
+			headers:    [][2]string{{"user-id", "u1"}, {"user-role", "admin"}},
+			service:    "admin-console",
+			wantStatus: http.StatusOK, wantUserID: "u1", wantRole: "admin",
+		},
+		{
+			name:       "admin from non-admin service is logged",
+			headers:    [][2]string{{"user-id", "u1"}, {"user-role", "admin"}},
+			service:    "web-bff",
+			wantStatus: http.StatusOK, wantUserID: "u1", wantRole: "admin", wantWarn: true,
+		},
+		{
+			name:       "admin with no service is logged",
+			headers:    [][2]string{{"user-role", "admin"}},
+			wantStatus: http.StatusOK, wantRole: "admin", wantWarn: true,
+		},
+		{
+			name:       "moderator from non-admin service is not logged",
+			headers:    [][2]string{{"user-role", "moderator"}},
+			service:    "web-bff",
+			wantStatus: http.StatusOK, wantRole: "moderator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLog(t)
+
+			var gotUserID, gotRole, gotViewerRole string
+			router := gin.New()
+			router.Use(RequestID(), ServiceIdentity([]string{"admin-console", "web-bff"}), ExtractUserContext([]string{"admin-console"}))
+			router.GET("/", func(c *gin.Context) {
+				gotUserID = c.GetString("user_id")
+				gotRole = c.GetString("user_role")
+				gotViewerRole = viewer.Role(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Request-ID", "req-123")
+			for _, h := range tt.headers {
+				req.Header.Add(h[0], h[1])
+			}
+			if tt.service != "" {
+				req.Header.Set("service-name", tt.service)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(w.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("body = %s, want code %q", w.Body.String(), tt.wantCode)
+			}
+			if gotUserID != tt.wantUserID {
+				t.Errorf("user_id = %q, want %q", gotUserID, tt.wantUserID)
+			}
+			if gotRole != tt.wantRole || gotViewerRole != tt.wantRole {
+				t.Errorf("user_role = %q, viewer role = %q, want %q", gotRole, gotViewerRole, tt.wantRole)
+			}
+
+			warned := strings.Contains(logs.String(), "SECURITY")
+			if warned != tt.wantWarn {
+				t.Errorf("security warning logged = %v, want %v (log: %q)", warned, tt.wantWarn, logs.String())
+			}
+			if warned && !strings.Contains(logs.String(), "request_id=req-123") {
+				t.Errorf("security warning %q is missing the request ID", logs.String())
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		middleware gin.HandlerFunc
+		role       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "admin allows admin", middleware: RequireAdmin(), role: "admin", wantStatus: http.StatusOK},
+		{name: "admin rejects moderator", middleware: RequireAdmin(), role: "moderator", wantStatus: http.StatusForbidden, wantCode: messages.CodeAdminRequired},
+		{name: "admin rejects user", middleware: RequireAdmin(), role: "user", wantStatus: http.StatusForbidden, wantCode: messages.CodeAdminRequired},
+		{name: "admin rejects no role", middleware: RequireAdmin(), wantStatus: http.StatusForbidden, wantCode: messages.CodeAdminRequired},
+		{name: "moderator allows admin", middleware: RequireModerator(), role: "admin", wantStatus: http.StatusOK},
+		{name: "moderator allows moderator", middleware: RequireModerator(), role: "moderator", wantStatus: http.StatusOK},
+		{name: "moderator rejects user", middleware: RequireModerator(), role: "user", wantStatus: http.StatusForbidden, wantCode: messages.CodeModeratorRequired},
+		{name: "moderator rejects no role", middleware: RequireModerator(), wantStatus: http.StatusForbidden, wantCode: messages.CodeModeratorRequired},
+		{name: "role is case sensitive", middleware: RequireAdmin(), role: "Admin", wantStatus: http.StatusForbidden, wantCode: messages.CodeAdminRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureLog(t)
+
+			reached := false
+			router := gin.New()
+			router.Use(ExtractUserContext(nil), tt.middleware)
+			router.GET("/", func(c *gin.Context) {
+				reached = true
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.role != "" {
+				req.Header.Set("user-role", tt.role)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if reached != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("handler reached = %v, want %v", reached, tt.wantStatus == http.StatusOK)
+			}
+			if tt.wantCode != "" && !strings.Contains(w.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("body = %s, want code %q", w.Body.String(), tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/config"
+)
+
+func TestRateLimitHeadersUnderConcurrency(t *testing.T) {
+	const (
+		burst    = 100
+		requests = 150
+	)
+	// Refills too slowly to add a token during the test
+	cfg := config.RateLimitConfig{Mode: config.RateLimitHard, RequestsPerSecond: 0.001, Burst: burst}
+
+	tests := []struct {
+		name       string
+		middleware gin.HandlerFunc
+	}{
+		{name: "shared bucket", middleware: RateLimit("test", cfg)},
+		{name: "per-IP bucket", middleware: RateLimitByIP("test_ip", cfg)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/limited", tt.middleware, func(c *gin.Context) {
+				c.String(http.StatusOK, "ok")
+			})
+
+			recorders := make([]*httptest.ResponseRecorder, requests)
+			var wg sync.WaitGroup
+			for i := range recorders {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+					req.RemoteAddr = "203.0.113.7:1234" // Same client for every request
+					recorders[i] = httptest.NewRecorder()
+					router.ServeHTTP(recorders[i], req)
+				}(i)
+			}
+			wg.Wait()
+
+			remainingSeen := make(map[int]bool)
+			allowed, rejected := 0, 0
+			for _, w := range recorders {
+				limit, errLimit := strconv.Atoi(w.Header().Get("X-RateLimit-Limit"))
+				remaining, errRemaining := strconv.Atoi(w.Header().Get("X-RateLimit-Remaining"))
+				reset, errReset := strconv.Atoi(w.Header().Get("X-RateLimit-Reset"))
+				if errLimit != nil || errRemaining != nil || errReset != nil {
+					t.Fatalf("%d response is missing X-RateLimit-* headers: %v", w.Code, w.Header())
+				}
+				if limit != burst {
+					t.Errorf("X-RateLimit-Limit = %d, want %d", limit, burst)
+				}
+				if reset <= 0 {
+					t.Errorf("X-RateLimit-Reset = %d with tokens taken, want > 0", reset)
+				}
+
+				switch w.Code {
+				case http.StatusOK:
+					allowed++
+					// Each allowed request saw its own balance - no two report the same
+					if remainingSeen[remaining] {
+						t.Errorf("X-RateLimit-Remaining %d reported twice", remaining)
+					}
+					remainingSeen[remaining] = true
+				case http.StatusTooManyRequests:
+					rejected++
+					if remaining != 0 {
+						t.Errorf("429 with X-RateLimit-Remaining = %d, want 0", remaining)
+					}
+					if w.Header().Get("Retry-After") == "" {
+						t.Error("429 without Retry-After")
+					}
+				default:
+					t.Errorf("unexpected status %d", w.Code)
+				}
+			}
+
+			if allowed != burst || rejected != requests-burst {
+				t.Errorf("allowed %d, rejected %d; want %d and %d", allowed, rejected, burst, requests-burst)
+			}
+			// Allowed requests counted the bucket down from burst-1 to 0
+			for remaining := 0; remaining < burst; remaining++ {
+				if !remainingSeen[remaining] {
+					t.Errorf("no allowed request reported X-RateLimit-Remaining = %d", remaining)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitByIPSeparatesClients(t *testing.T) {
+	cfg := config.RateLimitConfig{Mode: config.RateLimitHard, RequestsPerSecond: 0.001, Burst: 2}
+	router := gin.New()
+	router.GET("/limited", RateLimitByIP("test_ip_split", cfg), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	tests := []struct {
+		client        string
+		wantStatus    int
+		wantRemaining string
+	}{
+		{client: "198.51.100.1:1", wantStatus: http.StatusOK, wantRemaining: "1"},
+		{client: "198.51.100.1:2", wantStatus: http.StatusOK, wantRemaining: "0"},
+		{client: "198.51.100.1:3", wantStatus: http.StatusTooManyRequests, wantRemaining: "0"},
+		{client: "198.51.100.2:1", wantStatus: http.StatusOK, wantRemaining: "1"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = tt.client
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.wantStatus || w.Header().Get("X-RateLimit-Remaining") != tt.wantRemaining {
+			t.Errorf("%s: got %d with remaining %q, want %d with %q",
+				tt.client, w.Code, w.Header().Get("X-RateLimit-Remaining"), tt.wantStatus, tt.wantRemaining)
+		}
+	}
+}
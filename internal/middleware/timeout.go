@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+)
+
+// ============================================================================
+// REQUEST TIMEOUTS
+// ============================================================================
+
+// Timeout gives every request in a route group a deadline
+//
+// HOW IT WORKS:
+// - The request context gets the deadline, so DB queries see it and give up
+// - The rest of the chain runs in its own goroutine, writing into a buffer
+// - Handler finishes first: the buffered response is copied to the client
+// - Deadline hits first: the client gets a 504 at once, later writes are dropped
+//
+// The middleware still waits for the handler goroutine before returning -
+// gin recycles the *gin.Context once we return, so it must not be in use
+//
+// Nested Timeouts don't extend each other - the shortest deadline wins.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		// Pick the 504's language now - c belongs to the handler goroutine from here on
+		locale := messages.Locale(c.GetHeader("Accept-Language"))
+
+		dst := c.Writer
+		tw := &timeoutWriter{ResponseWriter: dst, ctx: ctx, header: dst.Header().Clone()}
+		c.Writer = tw
+
+		done := make(chan struct{})
+		var panicked any
+		go func() {
+			defer close(done)
+			defer tw.finish()
+			defer func() {
+				if p := recover(); p != nil {
+					panicked = p
+				}
+			}()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+
+		// A handler that saw the deadline and returned didn't make it either -
+		// whatever it wrote on the way out (usually an error) is dropped.
+		// Client went away (not the deadline) or the handler just made it:
+		// let the handler's own response through
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && tw.timeOut() {
+			writeTimeout(dst, locale)
+			<-done
+			c.Writer = dst
+			c.Abort()
+			if panicked != nil {
+				log.Printf("panic after request timeout: %v", panicked)
+			}
+			return
+		}
+		<-done
+
+		c.Writer = dst
+		// Re-panic on this goroutine so gin's Recovery middleware sees it
+		if panicked != nil {
+			panic(panicked)
+		}
+		tw.copyTo(dst)
+	}
+}
+
+// writeTimeout sends the 504 envelope straight to the client
+func writeTimeout(w gin.ResponseWriter, locale string) {
+	data, err := json.Marshal(messages.Envelope(locale, messages.CodeRequestTimeout, nil))
+	if err != nil {
+		data = []byte(`{"code":"` + messages.CodeRequestTimeout + `"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Language", locale)
+	w.WriteHeader(http.StatusGatewayTimeout)
+	w.Write(data)
+	w.Flush()
+}
+
+// timeoutWriter buffers the handler's response until Timeout decides
+// whether it gets sent. Only Header/Write/status are buffered - the
+// embedded writer is never written to from the handler goroutine.
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	ctx      context.Context // carries the deadline
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	written  bool
+	finished bool
+	timedOut bool
+}
+
+// finish records that the handler returned - a deadline after this is moot
+// Returning once the deadline has passed doesn't count: the handler most
+// likely returned because it saw the cancellation.
+func (w *timeoutWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.finished = w.ctx.Err() == nil
+}
+
+// timeOut marks the response as abandoned. It returns false if the handler
+// already returned in time (nothing to abandon - its response wins).
+func (w *timeoutWriter) timeOut() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.finished {
+		return false
+	}
+	w.timedOut = true
+	return true
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// Like gin: the status can change until the first byte is written
+	if w.timedOut || w.written || code <= 0 {
+		return
+	}
+	w.status = code
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.written = true
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written
+}
+
+// Flush is a no-op - nothing reaches the client until the handler is done
+func (w *timeoutWriter) Flush() {}
+
+// Hijack isn't possible on a buffered response
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("timeout middleware: hijack not supported")
+}
+
+func (w *timeoutWriter) Pusher() http.Pusher {
+	return nil
+}
+
+// copyTo sends the buffered response once the handler has returned in time
+func (w *timeoutWriter) copyTo(dst gin.ResponseWriter) {
+	header := dst.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	for key, values := range w.header {
+		header[key] = values
+	}
+
+	// The whole body is in hand, so say how long it is - net/http would
+	// otherwise chunk anything over its 2KB buffer. HEAD requests run the GET
+	// handler and net/http drops the body, leaving this header to match the GET's.
+	if w.body.Len() > 0 {
+		header.Set("Content-Length", strconv.Itoa(w.body.Len()))
+	}
+
+	if w.status != 0 {
+		dst.WriteHeader(w.status)
+	}
+	if w.body.Len() > 0 {
+		dst.Write(w.body.Bytes())
+	} else if w.written {
+		dst.WriteHeaderNow()
+	}
+}
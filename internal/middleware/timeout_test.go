@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/messages"
+)
+
+func TestTimeout(t *testing.T) {
+	const deadline = 20 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		handler      func(c *gin.Context) error // returned error is what the late write got
+		language     string
+		wantStatus   int
+		wantBody     string
+		wantCode     string
+		wantLanguage string
+		wantHeader   string // X-Handler header expected on the response
+		wantWriteErr error
+	}{
+		{
+			name: "fast handler",
+			handler: func(c *gin.Context) error {
+				c.Header("X-Handler", "yes")
+				c.String(http.StatusCreated, "made it")
+				return nil
+			},
+			wantStatus: http.StatusCreated, wantBody: "made it", wantHeader: "yes",
+		},
+		{
+			name: "handler observing cancellation",
+			handler: func(c *gin.Context) error {
+				<-c.Request.Context().Done()
+				// May still land in the buffer before the 504 goes out - either way it's dropped
+				c.Header("X-Handler", "yes")
+				c.String(http.StatusInternalServerError, "too late")
+				return nil
+			},
+			wantStatus: http.StatusGatewayTimeout, wantCode: messages.CodeRequestTimeout, wantLanguage: "en",
+		},
+		{
+			name: "handler ignoring cancellation",
+			handler: func(c *gin.Context) error {
+				time.Sleep(3 * deadline)
+				c.JSON(http.StatusOK, gin.H{"late": true})
+				_, err := c.Writer.Write([]byte("more"))
+				return err
+			},
+			wantStatus: http.StatusGatewayTimeout, wantCode: messages.CodeRequestTimeout, wantLanguage: "en",
+			wantWriteErr: http.ErrHandlerTimeout,
+		},
+		{
+			name: "envelope in the caller's language",
+			handler: func(c *gin.Context) error {
+				<-c.Request.Context().Done()
+				return nil
+			},
+			language:   "es-MX,es;q=0.9",
+			wantStatus: http.StatusGatewayTimeout, wantCode: messages.CodeRequestTimeout, wantLanguage: "es",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writeErr atomic.Value
+			finished := make(chan struct{})
+
+			router := gin.New()
+			router.GET("/slow", Timeout(deadline), func(c *gin.Context) {
+				defer close(finished)
+				if err := tt.handler(c); err != nil {
+					writeErr.Store(err)
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+			if tt.language != "" {
+				req.Header.Set("Accept-Language", tt.language)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Timeout waits for the handler before returning
+			select {
+			case <-finished:
+			default:
+				t.Fatal("Timeout returned while the handler was still running")
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("X-Handler"); got != tt.wantHeader {
+				t.Errorf("X-Handler = %q, want %q", got, tt.wantHeader)
+			}
+
+			if tt.wantCode == "" {
+				if w.Body.String() != tt.wantBody {
+					t.Errorf("body = %q, want %q", w.Body.String(), tt.wantBody)
+				}
+				if got := w.Header().Get("Content-Length"); got != strconv.Itoa(len(tt.wantBody)) {
+					t.Errorf("Content-Length = %q, want %d", got, len(tt.wantBody))
+				}
+			} else {
+				var body struct {
+					Code string `json:"code"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("504 body isn't a single envelope: %v: %q", err, w.Body.String())
+				}
+				if body.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", body.Code, tt.wantCode)
+				}
+				if got := w.Header().Get("Content-Language"); got != tt.wantLanguage {
+					t.Errorf("Content-Language = %q, want %q", got, tt.wantLanguage)
+				}
+			}
+
+			got, _ := writeErr.Load().(error)
+			if !errors.Is(got, tt.wantWriteErr) {
+				t.Errorf("late write error = %v, want %v", got, tt.wantWriteErr)
+			}
+		})
+	}
+}
+
+func TestTimeoutNestedShortestWins(t *testing.T) {
+	router := gin.New()
+	router.GET("/nested", Timeout(time.Hour), Timeout(10*time.Millisecond), func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+
+	w := httptest.NewRecorder()
+	start := time.Now()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nested", nil))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want %d: %q", w.Code, http.StatusGatewayTimeout, w.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %v, the inner deadline should have applied", elapsed)
+	}
+}
+
+func TestTimeoutPanicReachesRecovery(t *testing.T) {
+	router := gin.New()
+	router.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		c.String(http.StatusInternalServerError, "recovered: %v", recovered)
+	}))
+	router.GET("/panic", Timeout(time.Second), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError || w.Body.String() != "recovered: boom" {
+		t.Errorf("got %d %q, want 500 from the recovery middleware", w.Code, w.Body.String())
+	}
+}
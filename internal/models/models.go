@@ -1,6 +1,10 @@
 package models
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -61,6 +65,77 @@ type Trick struct {
 
 	// Weight is used for combo generation algorithm (affects selection probability)
 	Weight int16 `db:"weight" json:"weight"`
+
+	// WeightModifier scales Weight during combo generation (1.0 = no change)
+	// Set below 1.0 by the weight decay job for stale tricks; Weight itself stays curated
+	WeightModifier float64 `db:"weight_modifier" json:"-"`
+
+	// Attribution credits the external source of the description/notes (nullable)
+	Attribution *string `db:"attribution" json:"attribution,omitempty"`
+
+	// License is the license the content was contributed under, e.g. "CC BY 4.0" (nullable)
+	License *string `db:"license" json:"license,omitempty"`
+
+	// Aliases are alternate names ("Side Somi" for "Aerial") from trick_aliases
+	// Only loaded by single-trick reads and search - not a tricks column
+	Aliases []string `db:"-" json:"aliases,omitempty"`
+
+	// FormerNames are names the trick had before a rename, most recent first
+	// Loaded where Aliases are; those queries leave former names out of Aliases
+	FormerNames []string `db:"-" json:"former_names,omitempty"`
+
+	// Tags are free-form labels ("twisting", "inverted") from trick_tags
+	// Only loaded by single-trick reads, like Aliases
+	Tags []string `db:"-" json:"tags,omitempty"`
+
+	// FlipName is the name of the flip family FlipID points at (joined in)
+	// Only loaded by single-trick reads, like Aliases
+	FlipName *string `db:"-" json:"flip_name,omitempty"`
+
+	// Similarity is how closely a search query matched the name or an alias (0-1)
+	// Only set by fuzzy search - nil everywhere else
+	Similarity *float64 `db:"-" json:"-"`
+}
+
+// TrickTranslation is a trick's name and texts in another language (trick_translations)
+// Nil fields aren't translated - the trick's own value is used instead.
+type TrickTranslation struct {
+	Locale         string    `db:"locale" json:"locale"`
+	Name           *string   `db:"name" json:"name"`
+	Description    *string   `db:"description" json:"description"`
+	ExecutionNotes *string   `db:"execution_notes" json:"execution_notes"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Apply replaces the translated fields of a trick detail response
+func (t *TrickTranslation) Apply(detail *TrickDetailResponse) {
+	if t.Name != nil {
+		detail.Name = *t.Name
+	}
+	if t.Description != nil {
+		detail.Description = t.Description
+	}
+	if t.ExecutionNotes != nil {
+		detail.ExecutionNotes = t.ExecutionNotes
+	}
+	detail.Locale = t.Locale
+}
+
+// DifficultyBand names an inclusive difficulty range - GET /meta/difficulty-bands
+type DifficultyBand struct {
+	Name          string `json:"name"`
+	MinDifficulty int64  `json:"min_difficulty"`
+	MaxDifficulty int64  `json:"max_difficulty"`
+}
+
+// TrickAlias is an alternate name for a trick
+// Slug is the alias in slug form - requesting /tricks/<slug> resolves to the trick
+// FormerName marks a name the trick had before it was renamed.
+type TrickAlias struct {
+	TrickID    string `json:"trick_id"`
+	Alias      string `json:"alias"`
+	Slug       string `json:"slug"`
+	FormerName bool   `json:"former_name"`
 }
 
 // TrickVideo represents a row in the "trick_videos" table
@@ -92,8 +167,64 @@ type TrickVideo struct {
 
 	// CreatedAt is when this video was uploaded
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// Attribution credits the external source of the video (nullable)
+	Attribution *string `db:"attribution" json:"attribution,omitempty"`
+
+	// License is the license the video was contributed under (nullable)
+	License *string `db:"license" json:"license,omitempty"`
+
+	// Label is a short caption ("Slow motion", "Tutorial") shown with the video (nullable)
+	Label *string `db:"label" json:"label,omitempty"`
+
+	// Availability is whether the external link still plays (see VideoAvailable etc.)
+	Availability string `db:"availability" json:"availability"`
+
+	// LastCheckedAt is when Availability was last verified (nil = never checked)
+	LastCheckedAt *time.Time `db:"last_checked_at" json:"last_checked_at,omitempty"`
 }
 
+// Video availability states, set by the availability checker
+const (
+	VideoAvailabilityUnknown = "unknown"
+	VideoAvailable           = "available"
+	VideoUnavailable         = "unavailable"
+)
+
+// TrickMistake is one curated "common mistake" for a trick, written by moderators
+// Mistakes are shown in Position order (1 = first)
+type TrickMistake struct {
+	ID        int64     `db:"id" json:"id"`
+	TrickID   int       `db:"trick_id" json:"-"`
+	Text      string    `db:"text" json:"text"`
+	Severity  string    `db:"severity" json:"severity"`
+	Position  int       `db:"position" json:"-"`
+	CreatedAt time.Time `db:"created_at" json:"-"`
+	UpdatedAt time.Time `db:"updated_at" json:"-"`
+}
+
+// Mistake severities - the only values a moderator can set
+const (
+	MistakeSeverityMinor    = "minor"    // Costs style or height
+	MistakeSeverityMajor    = "major"    // Stops the trick from landing
+	MistakeSeverityCritical = "critical" // Risks injury
+)
+
+// Stance is a takeoff/landing position, with the leg a trick lands on
+type Stance struct {
+	ID          int     `db:"id" json:"id"`
+	Name        string  `db:"name" json:"name"`
+	Description *string `db:"description" json:"description"`
+	Leg         string  `db:"leg" json:"leg"` // LegLeft, LegRight or LegBoth
+}
+
+// Stance legs
+const (
+	LegLeft  = "left"
+	LegRight = "right"
+	LegBoth  = "both"
+)
+
 // Category represents a trick category (for filtering)
 type Category struct {
 	ID       int    `db:"id" json:"id"`
@@ -101,21 +232,50 @@ type Category struct {
 	ParentID *int   `db:"parent_id" json:"parent_id"`
 }
 
+// Flip is a flip/rotation family - a row of the table tricks.flip_id references
+// (trick_data.categories), with how many live tricks use it
+type Flip struct {
+	ID         int    `db:"id" json:"id"`
+	Name       string `db:"name" json:"name"`
+	ParentID   *int   `db:"parent_id" json:"parent_id"`
+	TrickCount int64  `db:"trick_count" json:"trick_count"`
+}
+
 // Combo represents a saved combo by a user
 // NEED to create this table if it doesn't exist
 type Combo struct {
 	ID        int64     `db:"id" json:"id"`
 	UserID    uuid.UUID `db:"user_id" json:"-"`
 	Name      string    `db:"name" json:"name"`
+	Notes     *string   `db:"notes" json:"notes,omitempty"` // Free-form notes on the whole combo
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// IsShared means the owner made the combo visible to other users
+	IsShared bool `db:"is_shared" json:"is_shared"`
+
+	// IsFeatured means an admin picked the combo for everyone to see
+	IsFeatured bool `db:"is_featured" json:"is_featured"`
+}
+
+// ComboSummary is a combo row with aggregates computed in SQL
+// Lets the combos list skip loading each combo's tricks
+type ComboSummary struct {
+	Combo
+
+	// TotalDifficulty sums the combo's trick difficulties (NULL difficulty counts as 0)
+	TotalDifficulty int64 `db:"total_difficulty" json:"total_difficulty"`
+
+	// TrickCount is how many tricks are in the combo
+	TrickCount int64 `db:"trick_count" json:"trick_count"`
 }
 
 // ComboTrick represents the many-to-many relationship between combos and tricks
 // This is a junction/join table
 type ComboTrick struct {
-	ComboID  int64 `db:"combo_id" json:"combo_id"`
-	TrickID  int   `db:"trick_id" json:"trick_id"`
-	Position int   `db:"position" json:"position"` // Order in the combo (1st, 2nd, 3rd trick)
+	ComboID  int64   `db:"combo_id" json:"combo_id"`
+	TrickID  int     `db:"trick_id" json:"trick_id"`
+	Position int     `db:"position" json:"position"`   // Order in the combo (1st, 2nd, 3rd trick)
+	Note     *string `db:"note" json:"note,omitempty"` // Note on this position
 }
 
 // =============================================================================
@@ -126,22 +286,211 @@ type ComboTrick struct {
 type TrickSimpleResponse struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
+
+	// FeaturedVideo is only loaded for GET /tricks?include=featured_video (gallery grids)
+	FeaturedVideo *VideoResponse `db:"-" json:"featured_video,omitempty"`
+
+	// HasVideo / HasFeaturedVideo are only set for GET /tricks?include=video_flags
+	// Videos known to be unavailable don't count.
+	HasVideo         *bool `db:"-" json:"has_video,omitempty"`
+	HasFeaturedVideo *bool `db:"-" json:"has_featured_video,omitempty"`
+
+	// VideoCount is only set for GET /tricks?with_counts=true - 0 for a trick without videos
+	// Counts the same videos as HasVideo (unavailable ones are left out).
+	VideoCount *int64 `db:"-" json:"video_count,omitempty"`
+
+	// IsNew is set on the catalog lists (GET /tricks, /tricks/simple): created within
+	// the server's "new" window, so every client highlights the same tricks
+	IsNew *bool `db:"-" json:"is_new,omitempty"`
+}
+
+// NewTrickResponse is one recently added trick (GET /tricks/new)
+type NewTrickResponse struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Difficulty *int64    `json:"difficulty,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PopularTrickResponse is one of the most viewed tricks in a window (GET /tricks/popular)
+type PopularTrickResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Difficulty *int64 `json:"difficulty,omitempty"`
+	Views      int64  `json:"views"`
+}
+
+// TrickListFilter holds the optional filters of GET /tricks
+// Every set field narrows the list further (they are ANDed together)
+type TrickListFilter struct {
+	MinDifficulty   *int64
+	MaxDifficulty   *int64
+	TakeoffStanceID *int
+	LandingStanceID *int
+	Tags            []string // Normalized and distinct - a trick needs every one
+	CategoryIDs     []int    // Flip categories (flip_id) - a trick needs any one
+}
+
+// Trick graph relation types (GET /tricks/graph?relations=)
+const (
+	TrickRelationPrerequisite = "prerequisite" // Edge from the trick to learn first to the trick it unlocks
+)
+
+// TrickGraphNode is one trick of GET /tricks/graph
+// Category is the trick's flip category (flip_id), null if it has none.
+type TrickGraphNode struct {
+	Slug       string  `db:"slug" json:"slug"`
+	Name       string  `db:"name" json:"name"`
+	Difficulty *int64  `db:"difficulty" json:"difficulty"`
+	CategoryID *int    `db:"category_id" json:"category_id"`
+	Category   *string `db:"category" json:"category"`
+}
+
+// TrickGraphEdge is one typed relation between two tricks of the graph
+type TrickGraphEdge struct {
+	From     string `db:"from_slug" json:"from"`
+	To       string `db:"to_slug" json:"to"`
+	Relation string `db:"relation" json:"relation"`
+}
+
+// TrickGraph is GET /tricks/graph: tricks and the relations between them
+// Only edges whose two ends are both nodes are included.
+type TrickGraph struct {
+	Relations []string         `json:"relations"`
+	Nodes     []TrickGraphNode `json:"nodes"`
+	Edges     []TrickGraphEdge `json:"edges"`
+}
+
+// DifficultyBucket is one bar of GET /tricks/difficulty-histogram
+// Difficulty is null for the bucket of unrated tricks.
+type DifficultyBucket struct {
+	Difficulty *int64 `json:"difficulty"`
+	Count      int    `json:"count"`
+}
+
+// TrickExportRow is one trick of the catalog export (GET /tricks/export)
+// The JSON tags are the NDJSON keys; the CSV header uses the same names.
+type TrickExportRow struct {
+	Name          string  `json:"name"`
+	Slug          string  `json:"slug"`
+	Difficulty    *int64  `json:"difficulty"`
+	Rotation      *int    `json:"rotation"`
+	TakeoffStance *string `json:"takeoff_stance"`
+	LandingStance *string `json:"landing_stance"`
+	CreatorName   *string `json:"creator_name"`
+	Attribution   *string `json:"attribution"`
+	License       *string `json:"license"`
+}
+
+// RandomTrickFilter holds the filters of GET /tricks/random
+// Exclude lists slugs the client just showed, so a drill doesn't repeat them
+type RandomTrickFilter struct {
+	TrickListFilter
+	Exclude []string
+}
+
+// TrickPage is one page of the cursor-paginated trick catalog
+// NextCursor is nil on the last page
+type TrickPage struct {
+	Tricks     []TrickSimpleResponse `json:"tricks"`
+	NextCursor *string               `json:"next_cursor"`
+}
+
+// TrickAutocompleteResponse is one type-ahead suggestion - kept tiny on purpose
+type TrickAutocompleteResponse struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// TrickSearchResult is one ranked hit from the trick search
+type TrickSearchResult struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Difficulty *int64 `json:"difficulty,omitempty"`
+	MatchType  string `json:"match_type"` // exact, prefix, alias, former_name, word, name, description, text, fuzzy
+	// Score is the trigram similarity of the query to the name or an alias (0-1),
+	// omitted when fuzzy search is off. A "fuzzy" match with a score well below
+	// 1 is the cue for a "did you mean ...?" hint.
+	Score *float64 `json:"score,omitempty"`
+}
+
+// TrickSlugResponse is a trick's slug and last change time (for sitemaps)
+type TrickSlugResponse struct {
+	Slug      string    `json:"slug"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TrickStatsResponse is the catalog summary behind GET /tricks/stats
+//
+//	{
+//	  "total_tricks": 212,
+//	  "by_flip": [{"flip_id": 1, "count": 80}, {"flip_id": null, "count": 12}],
+//	  "average_difficulty": 4.37,
+//	  "added_last_30_days": 6,
+//	  "generated_at": "2026-10-16T09:00:00Z"
+//	}
+//
+// by_flip is ordered by flip_id, with tricks that have no flip last (flip_id null).
+// average_difficulty skips tricks without a difficulty and is null if none has one.
+type TrickStatsResponse struct {
+	TotalTricks       int              `json:"total_tricks"`
+	ByFlip            []FlipTrickCount `json:"by_flip"`
+	AverageDifficulty *float64         `json:"average_difficulty"`
+	AddedLast30Days   int              `json:"added_last_30_days"`
+	GeneratedAt       time.Time        `json:"generated_at"`
+}
+
+// FlipTrickCount is how many live tricks share one flip_id
+type FlipTrickCount struct {
+	FlipID *int `json:"flip_id"`
+	Count  int  `json:"count"`
 }
 
 // TrickDetailResponse is the full trick data without videos
 // Used for the "simple" version of the trick detail endpoint
 type TrickDetailResponse struct {
-	ID              string     `json:"id"`
-	Name            string     `json:"name"`
-	Description     *string    `json:"description,omitempty"`
-	Difficulty      *int64     `json:"difficulty,omitempty"`
-	ExecutionNotes  *string    `json:"execution_notes,omitempty"`
-	CreatorName     *string    `json:"creator_name,omitempty"`
-	TakeoffStanceID *int       `json:"takeoff_stance_id,omitempty"`
-	LandingStanceID *int       `json:"landing_stance_id,omitempty"`
-	Rotation        *int       `json:"rotation,omitempty"`
-	CreatedAt       *time.Time `json:"created_at,omitempty"`
-	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Description     *string  `json:"description,omitempty"`
+	Difficulty      *int64   `json:"difficulty,omitempty"`
+	ExecutionNotes  *string  `json:"execution_notes,omitempty"`
+	CreatorName     *string  `json:"creator_name,omitempty"`
+	TakeoffStanceID *int     `json:"takeoff_stance_id,omitempty"`
+	LandingStanceID *int     `json:"landing_stance_id,omitempty"`
+	Rotation        *int     `json:"rotation,omitempty"`
+	Attribution     *string  `json:"attribution,omitempty"`
+	License         *string  `json:"license,omitempty"`
+	Aliases         []string `json:"aliases,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	// PreviouslyKnownAs lists names the trick was renamed from, most recent first
+	PreviouslyKnownAs []string   `json:"previously_known_as,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+
+	// Set only with ?expand=stances (unknown stance IDs stay unexpanded)
+	TakeoffStance *StanceResponse `json:"takeoff_stance,omitempty"`
+	LandingStance *StanceResponse `json:"landing_stance,omitempty"`
+
+	// Set only with ?expand=flip
+	FlipName *string `json:"flip_name,omitempty"`
+
+	// Band is the difficulty band the difficulty falls in (see GET /meta/difficulty-bands)
+	Band *string `json:"band,omitempty"`
+
+	// Status is only set on a trick just submitted for review ("pending") -
+	// everything else the catalog serves is approved
+	Status string `json:"status,omitempty"`
+
+	// SuggestedCategory is only set on a trick just created without flip_id,
+	// when category suggestions are on
+	SuggestedCategory *CategorySuggestion `json:"suggested_category,omitempty"`
+
+	// CreatedBy is only shown to staff (see ResponseView)
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+
+	// Locale is the translation applied to name/description/execution_notes
+	// (see TrickTranslation) - empty when they are the trick's own
+	Locale string `json:"locale,omitempty"`
 }
 
 // VideoResponse is the video data for API responses
@@ -151,12 +500,40 @@ type VideoResponse struct {
 	ThumbnailURL  string    `json:"thumbnail_url"`
 	PerformerName string    `json:"performer_name"`
 	IsFeatured    bool      `json:"is_featured"`
+	Attribution   *string   `json:"attribution,omitempty"`
+	License       *string   `json:"license,omitempty"`
+	Label         *string   `json:"label,omitempty"`
+	Availability  string    `json:"availability"` // available, unavailable or unknown
 	CreatedAt     time.Time `json:"created_at"`
+
+	// UploadedBy is only shown to staff (see ResponseView)
+	UploadedBy *uuid.UUID `json:"uploaded_by,omitempty"`
 }
 
-// TrickFullDetailsResponse is the "complicated" version with video
+// VideoAvailabilityResponse reports the result of a video availability check
+type VideoAvailabilityResponse struct {
+	ID            int64     `json:"id"`
+	Availability  string    `json:"availability"`
+	LastCheckedAt time.Time `json:"last_checked_at"`
+}
+
+// TrickDictionaryResponse is the "complicated" version with video
 // This is like a dictionary page for the trick with all available information
-type TrickFullDetailsResponse struct {
+//
+// JSON SHAPE (GET /api/v1/tricks/:id/dictionary):
+//
+//	{
+//	  "id": "cork", "name": "Cork", ...,          // every TrickDetailResponse field
+//	  "featured_video": { ...VideoResponse },     // include=featured_video
+//	  "videos": [ { ...VideoResponse } ],         // include=featured_video
+//	  "video_count": 3,                           // include=featured_video
+//	  "performers": [ { "name", "user_id", "video_count" } ], // include=performers
+//	  "performer_total": 12,                      // include=performers
+//	  "prerequisites": [ { "id", "name" } ],      // include=prerequisites
+//	  "common_mistakes": [ ... ],
+//	  "completeness": 83
+//	}
+type TrickDictionaryResponse struct {
 	// Embed TrickDetailResponse to include all its fields
 	// This is Go's composition pattern - avoids repeating fields
 	TrickDetailResponse
@@ -164,19 +541,281 @@ type TrickFullDetailsResponse struct {
 	// FeaturedVideo is the primary video (convenience field)
 	// Pointer allows null if no featured video exists
 	FeaturedVideo *VideoResponse `json:"featured_video,omitempty"`
+
+	// Videos is every playable video, featured first then newest
+	// Loaded with the featured video; both are omitted when it isn't included
+	Videos     []VideoResponse `json:"videos,omitempty"`
+	VideoCount *int            `json:"video_count,omitempty"`
+
+	// Performers is who has footage of this trick, most videos first (top 10)
+	// PerformerTotal counts all of them; both are omitted when not included
+	Performers     []TrickPerformer `json:"performers,omitempty"`
+	PerformerTotal *int             `json:"performer_total,omitempty"`
+
+	// Prerequisites are the tricks to learn first (direct only); omitted when not included
+	Prerequisites []TrickSimpleResponse `json:"prerequisites,omitempty"`
+
+	// CommonMistakes are the moderator-curated mistakes, in display order
+	CommonMistakes []TrickMistake `json:"common_mistakes"`
+
+	// Completeness is how much of the dictionary entry is filled in (0-100)
+	Completeness int `json:"completeness"`
+}
+
+// TrickDictionaryBatchRequest is the body of POST /tricks/batch-dictionary
+type TrickDictionaryBatchRequest struct {
+	Slugs []string `json:"slugs" binding:"required,min=1,max=20"`
+}
+
+// TrickDictionaryBatchItem is one requested slug of a batch dictionary fetch
+// Exactly one of Dictionary and Code/Error is set: a slug that can't be served
+// carries its error inline instead of failing the batch.
+type TrickDictionaryBatchItem struct {
+	Slug       string                   `json:"slug"` // As requested
+	Dictionary *TrickDictionaryResponse `json:"dictionary,omitempty"`
+	Code       string                   `json:"code,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+// TrickDictionaryBatchResponse holds one item per requested slug, in request order
+type TrickDictionaryBatchResponse struct {
+	Results []TrickDictionaryBatchItem `json:"results"`
+	Found   int                        `json:"found"` // Items with a dictionary
+}
+
+// PublicTrickLinkRequest is the body of POST /admin/tricks/:slug/public-link
+// TTLSeconds defaults to a week when omitted
+type PublicTrickLinkRequest struct {
+	TTLSeconds *int `json:"ttl_seconds"`
+}
+
+// PublicTrickLink is a signed, expiring link to one trick's public preview
+// URL is relative to the API root; Sig and Exp are its query parameters.
+type PublicTrickLink struct {
+	URL       string    `json:"url"`
+	Slug      string    `json:"slug"`
+	Sig       string    `json:"sig"`
+	Exp       int64     `json:"exp"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// APIKeyScopeCatalogRead lets an external API key read the catalog - the only scope so far
+const APIKeyScopeCatalogRead = "catalog:read"
+
+// APIKey is an external consumer's API key, as stored - the key itself is
+// never stored or shown again after it's issued, only its SHA-256 hash
+// Prefix is the key's first characters, to tell keys apart in listings.
+type APIKey struct {
+	ID        int64      `json:"id" db:"id"`
+	Prefix    string     `json:"prefix" db:"prefix"`
+	Owner     string     `json:"owner" db:"owner"`
+	Scopes    []string   `json:"scopes" db:"scopes"`
+	RateTier  string     `json:"rate_tier" db:"rate_tier"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// APIKeyCreateRequest is the body of POST /admin/api-keys
+// Scopes default to catalog:read, RateTier to "standard".
+type APIKeyCreateRequest struct {
+	Owner    string   `json:"owner" binding:"required,max=100"`
+	Scopes   []string `json:"scopes"`
+	RateTier string   `json:"rate_tier"`
+}
+
+// IssuedAPIKey is a new key with its secret - the only time the secret is shown
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// PublicTrickPreview is the reduced trick served through a public link
+// Only what a social media card needs - nothing else leaves without the API key.
+type PublicTrickPreview struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Description  *string `json:"description"`
+	Difficulty   *int64  `json:"difficulty"`
+	ThumbnailURL *string `json:"thumbnail_url"`
+}
+
+// TrickPerformer is one athlete with footage of a trick
+// Names are grouped case-insensitively; UserID is set when any of their videos is linked to an account
+type TrickPerformer struct {
+	Name       string     `json:"name"`
+	UserID     *uuid.UUID `json:"user_id,omitempty"`
+	VideoCount int        `json:"video_count"`
+}
+
+// ComboSummaryResponse represents a saved combo's totals without its tricks
+// Returned by the combos list in summary mode
+type ComboSummaryResponse struct {
+	ID              int64      `json:"id"`
+	OwnerID         *uuid.UUID `json:"owner_id,omitempty"` // Only set when combos from several users are mixed
+	Name            string     `json:"name"`
+	Notes           *string    `json:"notes,omitempty"`
+	TrickCount      int64      `json:"trick_count"`
+	TotalDifficulty int64      `json:"total_difficulty"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 // ComboResponse represents a saved combo with its tricks
 type ComboResponse struct {
-	ID        int64                 `json:"id"`
-	Name      string                `json:"name"`
-	Tricks    []TrickSimpleResponse `json:"tricks"` // Ordered list of tricks
-	CreatedAt time.Time             `json:"created_at"`
+	ComboSummaryResponse
+	Tricks []ComboTrickResponse `json:"tricks"` // Ordered list of tricks - always present, [] when empty
+}
+
+// TrickBatchResponse holds the tricks found by a slug batch lookup, in request order
+type TrickBatchResponse struct {
+	Tricks  []TrickDetailResponse `json:"tricks"`
+	Count   int                   `json:"count"`
+	Missing []string              `json:"missing"` // Requested slugs that don't exist (or are deleted)
+}
+
+// TrickChangesResponse is a delta sync: what changed in the catalog since a timestamp
+// Clients upsert Tricks, remove DeletedSlugs, and send ServerTime as the next "since".
+type TrickChangesResponse struct {
+	Tricks       []TrickDetailResponse `json:"tricks"`
+	Count        int                   `json:"count"`
+	DeletedSlugs []string              `json:"deleted_slugs"`
+	ServerTime   int64                 `json:"server_time"` // Unix seconds
+}
+
+// CatalogDeltaResponse is what changed in the catalog since a snapshot version
+// Clients upsert Tricks, Categories and Stances, remove DeletedSlugs, and send
+// Version as the next since_version. Full is set when no since_version was sent
+// (everything is included - replace the local copy rather than merge into it).
+type CatalogDeltaResponse struct {
+	Version      string                `json:"version"`
+	Full         bool                  `json:"full"`
+	Tricks       []TrickDetailResponse `json:"tricks"`
+	DeletedSlugs []string              `json:"deleted_slugs"`
+	Categories   []Category            `json:"categories"`
+	Stances      []Stance              `json:"stances"`
+}
+
+// Empty reports whether nothing changed
+func (d *CatalogDeltaResponse) Empty() bool {
+	return len(d.Tricks) == 0 && len(d.DeletedSlugs) == 0 && len(d.Categories) == 0 && len(d.Stances) == 0
+}
+
+// ComboBatchResponse holds the combos found by a batch get, in request order
+// IDs that don't exist or aren't visible to the caller are both reported as missing
+type ComboBatchResponse struct {
+	Combos     []ComboResponse `json:"combos"`
+	MissingIDs []int64         `json:"missing_ids"`
+}
+
+// ComboTrickResponse is one position in a saved combo
+// ID is the slug, like every other trick response; Slug repeats it and TrickID
+// is the integer key combo_tricks stores, so clients can match either.
+type ComboTrickResponse struct {
+	ID         string  `json:"id"`
+	TrickID    int64   `json:"trick_id"`
+	Slug       string  `json:"slug"`
+	Name       string  `json:"name"`
+	Difficulty *int64  `json:"difficulty,omitempty"`
+	Position   int     `json:"position"`
+	Note       *string `json:"note,omitempty"`
+
+	// Deleted marks a trick that was deleted after the combo was saved - it
+	// stays in place until it's purged, so the combo still reads as it was
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// RecentTrickResponse is a trick the user recently put in one of their combos
+type RecentTrickResponse struct {
+	ID         string    `json:"id"`
+	Slug       string    `json:"slug"`
+	Name       string    `json:"name"`
+	Difficulty *int64    `json:"difficulty,omitempty"`
+	LastUsedAt time.Time `json:"last_used_at"` // created_at of the newest combo using this trick
+}
+
+// AttemptDay is one UTC day of a user's combo attempts (days without attempts have no row)
+type AttemptDay struct {
+	Day      time.Time `db:"day"`
+	Attempts int64     `db:"attempts"`
+	Landed   int64     `db:"landed"`
+}
+
+// TrainingDay is one day of the training calendar - zeros when the user didn't train
+type TrainingDay struct {
+	Date     string `json:"date"` // YYYY-MM-DD (UTC)
+	Attempts int64  `json:"attempts"`
+	Landed   int64  `json:"landed"`
+}
+
+// TrainingCalendarResponse is GET /users/:userId/training-calendar
+// Days covers every date from From to To (inclusive), in order.
+// Streaks count consecutive days with at least one attempt, within the range:
+// the current streak ends on To (or the day before, if nothing was logged on To yet).
+type TrainingCalendarResponse struct {
+	From          string        `json:"from"`
+	To            string        `json:"to"`
+	Days          []TrainingDay `json:"days"`
+	TotalAttempts int64         `json:"total_attempts"`
+	TotalLanded   int64         `json:"total_landed"`
+	CurrentStreak int           `json:"current_streak"`
+	LongestStreak int           `json:"longest_streak"`
 }
 
 // GeneratedComboResponse represents a newly generated combo
 type GeneratedComboResponse struct {
 	Tricks []TrickSimpleResponse `json:"tricks"`
+
+	// Notation is the combo as text, in the applied notation_style
+	Notation string `json:"notation"`
+
+	// RelaxedPositions lists 1-indexed positions where balance_legs couldn't be honoured
+	RelaxedPositions []int `json:"relaxed_positions,omitempty"`
+
+	// AppliedConstraints is what the generation actually used, for clients to display
+	AppliedConstraints AppliedComboConstraints `json:"applied_constraints"`
+}
+
+// AppliedComboConstraints echoes the effective constraints of a combo generation
+// Built by services.NormalizeComboConstraints - the request after the server's
+// defaults and normalization, never the raw query string.
+type AppliedComboConstraints struct {
+	Size            int    `json:"size"`
+	MaxDifficulty   *int64 `json:"max_difficulty"`    // nil = no cap
+	CategoryIDs     []int  `json:"category_ids"`      // Only these categories (empty = all), sorted
+	ExcludeTrickIDs []int  `json:"exclude_trick_ids"` // Sorted
+	BalanceLegs     bool   `json:"balance_legs"`
+	Strategy        string `json:"strategy"`       // comboalg strategy: weighted or balanced
+	NotationStyle   string `json:"notation_style"` // comboalg notation style: arrows, dashes or numbered
+
+	// IgnoredParams lists request params that were accepted but had no effect
+	IgnoredParams []string `json:"ignored_params"`
+}
+
+// ComboEstimateRequest is the body of POST /combos/estimate - trick slugs (or legacy IDs) in combo order
+type ComboEstimateRequest struct {
+	Tricks []string `json:"tricks" binding:"required,min=1,max=10,dive,required,max=100"`
+}
+
+// ComboEstimateResponse scores a hypothetical combo without saving anything
+// Unknown (or deleted) tricks are left out - they count in UnknownCount only.
+type ComboEstimateResponse struct {
+	TotalDifficulty int64             `json:"total_difficulty"`
+	Grade           string            `json:"grade"`
+	Transitions     []ComboTransition `json:"transitions"`
+	UnknownCount    int               `json:"unknown_count"`
+}
+
+// ComboTransition is the stance check between two consecutive tricks (by slug)
+// Compatibility is "match", "mismatch" or "unknown" (a stance isn't recorded).
+type ComboTransition struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Compatibility string `json:"compatibility"`
 }
 
 // CategoryResponse is for the categories list endpoint
@@ -186,6 +825,310 @@ type CategoryResponse struct {
 	ParentID *int   `json:"parent_id"`
 }
 
+// FlipResponse is for the flips list endpoint (GET /flips)
+type FlipResponse struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	ParentID   *int   `json:"parent_id"`
+	TrickCount int64  `json:"trick_count"`
+}
+
+// StanceResponse is a stance for API responses (GET /stances, ?expand=stances)
+type StanceResponse struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+// RuntimeInfo identifies a running instance: build, environment and config
+// Logged once at startup and served by GET /admin/runtime. Must never carry
+// secrets - connection strings go through the redact package first.
+type RuntimeInfo struct {
+	Version     string       `json:"version"`
+	GitCommit   string       `json:"git_commit"`
+	GoVersion   string       `json:"go_version"`
+	Environment string       `json:"environment"`
+	Features    []string     `json:"features"`
+	Database    DatabaseInfo `json:"database"`
+	StartedAt   time.Time    `json:"started_at"`
+}
+
+// DatabaseInfo is the database target and pool limits, with credentials redacted
+type DatabaseInfo struct {
+	URL             string `json:"url"`
+	MaxConns        int32  `json:"max_conns"`
+	MinConns        int32  `json:"min_conns"`
+	MaxConnLifetime string `json:"max_conn_lifetime"`
+	MaxConnIdleTime string `json:"max_conn_idle_time"`
+}
+
+// RuntimeStatus is RuntimeInfo plus live process numbers (GET /admin/runtime)
+type RuntimeStatus struct {
+	RuntimeInfo
+	UptimeSeconds int64 `json:"uptime_seconds"`
+	GOMAXPROCS    int   `json:"gomaxprocs"`
+	Goroutines    int   `json:"goroutines"`
+
+	// CacheEntries is the size of the in-memory cache (assembled dictionaries)
+	CacheEntries int `json:"cache_entries"`
+}
+
+// SanitizeReport summarizes a re-sanitization pass over existing rows
+type SanitizeReport struct {
+	TricksScanned int `json:"tricks_scanned"`
+	TricksUpdated int `json:"tricks_updated"`
+	VideosScanned int `json:"videos_scanned"`
+	VideosUpdated int `json:"videos_updated"`
+
+	// InvalidVideoIDs are videos whose URLs can't be fixed automatically
+	// They are left untouched and need a human to correct or remove them
+	InvalidVideoIDs []int64 `json:"invalid_video_ids"`
+
+	// InvalidTrickIDs are tricks whose name cleans down to nothing
+	// They are left untouched rather than saved with an empty name
+	InvalidTrickIDs []string `json:"invalid_trick_ids"`
+}
+
+// CatalogChange is one row of the catalog diff report
+type CatalogChange struct {
+	Entity        string    `db:"entity" json:"entity"`                           // "trick", "video" or "category"
+	EntityID      string    `db:"entity_id" json:"entity_id"`                     // Slug for tricks, numeric ID otherwise
+	Name          string    `db:"name" json:"name"`                               // Trick name for videos
+	Change        string    `db:"change" json:"change"`                           // "created", "updated" or "deleted"
+	ChangedFields []string  `db:"changed_fields" json:"changed_fields,omitempty"` // Only for trick updates
+	ChangedAt     time.Time `db:"changed_at" json:"changed_at"`
+}
+
+// CatalogDiffResponse lists every catalog change between two timestamps
+type CatalogDiffResponse struct {
+	From    time.Time       `json:"from"`
+	To      time.Time       `json:"to"`
+	Changes []CatalogChange `json:"changes"`
+	Count   int             `json:"count"`
+}
+
+// AttributedContent is a trick or video that credits an external source
+type AttributedContent struct {
+	Attribution string  `db:"attribution" json:"-"`             // Grouping key - shown on the parent AttributionSource
+	ContentType string  `db:"content_type" json:"content_type"` // "trick" or "video"
+	ContentID   string  `db:"content_id" json:"content_id"`     // Slug for tricks, numeric ID for videos
+	Name        string  `db:"name" json:"name"`                 // Trick name (for videos, the trick they show)
+	License     *string `db:"license" json:"license,omitempty"`
+}
+
+// AttributionSource groups all content credited to one source
+type AttributionSource struct {
+	Attribution string              `json:"attribution"`
+	Items       []AttributedContent `json:"items"`
+}
+
+// DuplicateComboTrick reports a trick that appears more than once in a combo request
+type DuplicateComboTrick struct {
+	TrickID   string `json:"trick_id"`
+	Positions []int  `json:"positions"` // 1-indexed positions where it appears
+}
+
+// DifficultyCalibration compares a trick's curated difficulty with community votes
+type DifficultyCalibration struct {
+	ID                string  `db:"id" json:"id"`
+	Name              string  `db:"name" json:"name"`
+	CuratedDifficulty int64   `db:"curated_difficulty" json:"curated_difficulty"`
+	CommunityAverage  float64 `db:"community_average" json:"community_average"`
+	VoteCount         int64   `db:"vote_count" json:"vote_count"`
+	Delta             float64 `db:"delta" json:"delta"` // |curated - community_average|
+}
+
+// DifficultyCalibrationPage is one page of the calibration report
+type DifficultyCalibrationPage struct {
+	Tricks []DifficultyCalibration `json:"tricks"`
+	Total  int64                   `json:"total"` // Matching tricks across all pages
+	Limit  int                     `json:"limit"`
+	Offset int                     `json:"offset"`
+}
+
+// DifficultyChangeResponse reports a curated difficulty change
+type DifficultyChangeResponse struct {
+	ID                 string `json:"id"`
+	PreviousDifficulty *int64 `json:"previous_difficulty"`
+	Difficulty         int64  `json:"difficulty"`
+}
+
+// TrickWeightRequest is the body of PATCH /tricks/:id/weight
+// The range (services.MinTrickWeight-MaxTrickWeight) is checked by the service.
+type TrickWeightRequest struct {
+	Weight int `json:"weight" binding:"required"`
+}
+
+// TrickWeightResponse reports a trick's curated weight after a change
+// Changed is false when the trick already had that weight.
+type TrickWeightResponse struct {
+	ID      string `json:"id"`
+	Weight  int    `json:"weight"`
+	Changed bool   `json:"changed"`
+}
+
+// TrickWeightsRequest is the body of PATCH /admin/tricks/weights - slug -> weight
+type TrickWeightsRequest struct {
+	Weights map[string]int `json:"weights" binding:"required,min=1"`
+}
+
+// TrickWeightsResponse counts the tricks a bulk weight change wrote and the
+// ones that already had the requested weight
+type TrickWeightsResponse struct {
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// TrickDeletionResponse reports a soft delete and when the trick will be purged
+type TrickDeletionResponse struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// TrickMergeRequest is the body of POST /admin/tricks/:slug/merge
+type TrickMergeRequest struct {
+	DuplicateSlug string `json:"duplicate_slug" binding:"required,max=100"`
+}
+
+// TrickMergeResponse summarizes a merge: what moved from the duplicate to the kept trick
+// Videos whose URL the kept trick already had stay on the duplicate (VideosSkipped).
+type TrickMergeResponse struct {
+	ID               string    `json:"id"`           // Kept trick
+	DuplicateID      string    `json:"duplicate_id"` // Merged trick, now deleted
+	ComboTricksMoved int64     `json:"combo_tricks_moved"`
+	VideosMoved      int64     `json:"videos_moved"`
+	VideosSkipped    int64     `json:"videos_skipped"`
+	AliasesMoved     int64     `json:"aliases_moved"`
+	AliasAdded       bool      `json:"alias_added"` // The duplicate's name became an alias
+	DeletedAt        time.Time `json:"deleted_at"`
+	PurgeAt          time.Time `json:"purge_at"`
+}
+
+// Trick review statuses (tricks.status) - only approved tricks are in the catalog
+const (
+	TrickPending  = "pending"
+	TrickApproved = "approved"
+	TrickRejected = "rejected"
+)
+
+// TrickSubmission is a trick as its submitter and the reviewing admin see it
+// RejectionReason is only set on rejected tricks, ApprovedBy on approved ones.
+type TrickSubmission struct {
+	ID              string     `db:"id" json:"id"`
+	Name            string     `db:"name" json:"name"`
+	Description     *string    `db:"description" json:"description"`
+	Difficulty      *int64     `db:"difficulty" json:"difficulty"`
+	Status          string     `db:"status" json:"status"`
+	RejectionReason *string    `db:"rejection_reason" json:"rejection_reason"`
+	CreatedBy       *uuid.UUID `db:"created_by" json:"created_by"`
+	ApprovedBy      *uuid.UUID `db:"approved_by" json:"approved_by"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	ReviewedAt      *time.Time `db:"reviewed_at" json:"reviewed_at"`
+}
+
+// TrickSubmissionPage is one page of the review queue, oldest first
+type TrickSubmissionPage struct {
+	Tricks []TrickSubmission `json:"tricks"`
+	Total  int64             `json:"total"` // Pending tricks across all pages
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// TrickRejectRequest is the optional body of POST /admin/tricks/:slug/reject
+// The reason is shown to the submitter.
+type TrickRejectRequest struct {
+	Reason *string `json:"reason"`
+}
+
+// TrickAuditEntry is one recorded write to a trick (see GET /tricks/:id/history)
+// Diff holds only the fields the write changed.
+type TrickAuditEntry struct {
+	ID        int64                       `db:"id" json:"id"`
+	TrickSlug string                      `db:"trick_slug" json:"trick_slug"` // The slug after the write
+	ActorID   *uuid.UUID                  `db:"actor_id" json:"actor_id"`
+	Action    string                      `db:"action" json:"action"` // create, update, delete, restore, approve or reject
+	Diff      map[string]TrickAuditChange `db:"diff" json:"diff"`
+	CreatedAt time.Time                   `db:"created_at" json:"created_at"`
+}
+
+// TrickAuditChange is one field's value before and after a write (null when unset)
+type TrickAuditChange struct {
+	Old json.RawMessage `json:"old"`
+	New json.RawMessage `json:"new"`
+}
+
+// TrickHistoryPage is one page of a trick's audit history, newest first
+type TrickHistoryPage struct {
+	ID      string            `json:"id"`
+	Entries []TrickAuditEntry `json:"entries"`
+	Total   int64             `json:"total"` // Entries across all pages
+	Limit   int               `json:"limit"`
+	Offset  int               `json:"offset"`
+}
+
+// PendingPurge is a soft-deleted trick waiting to be permanently removed
+type PendingPurge struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// AdminStats is a snapshot of catalog health for the admin dashboard
+type AdminStats struct {
+	LiveTricks    int64 `json:"live_tricks"`
+	PendingPurge  int64 `json:"pending_purge"`
+	Videos        int64 `json:"videos"`
+	DeadVideos    int64 `json:"unavailable_videos"`
+	DecayedTricks int64 `json:"decayed_tricks"` // weight_modifier below 1.0
+}
+
+// MissingIndex is a required index the self-check couldn't find
+type MissingIndex struct {
+	Table      string `json:"table"`
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// SelfCheckReport is the result of the startup / on-demand schema self-check
+// Degraded means the API works but some queries will table-scan (or the check itself failed)
+type SelfCheckReport struct {
+	CheckedAt      time.Time      `json:"checked_at"`
+	Degraded       bool           `json:"degraded"`
+	MissingIndexes []MissingIndex `json:"missing_indexes"`
+	Error          string         `json:"error,omitempty"`
+}
+
+// DrainStatus is whether this instance has stopped taking new traffic (see /health/ready)
+type DrainStatus struct {
+	Draining bool       `json:"draining"`
+	Since    *time.Time `json:"since,omitempty"`
+	Reason   string     `json:"reason,omitempty"` // The signal ("terminated", "interrupt") or "admin"
+}
+
+// BrokenReference is a trick column pointing at a row that doesn't exist
+type BrokenReference struct {
+	TrickID      string `json:"trick_id"`
+	Field        string `json:"field"`
+	ReferencedID int    `json:"referenced_id"`
+}
+
+// Consistency fix modes
+const (
+	// ConsistencyFixNullOut clears dangling references (the trick keeps everything else)
+	ConsistencyFixNullOut = "null_out"
+)
+
+// ConsistencyReport is the result of a catalog reference check
+// BrokenReferences is what the check found; with a fix, Cleared counts how many were cleared.
+type ConsistencyReport struct {
+	CheckedAt        time.Time         `json:"checked_at"`
+	BrokenReferences []BrokenReference `json:"broken_references"`
+	Fix              string            `json:"fix,omitempty"`
+	Cleared          int               `json:"cleared"`
+}
+
 // =============================================================================
 // API REQUEST DTOs - These are what clients send to us
 // =============================================================================
@@ -204,15 +1147,375 @@ type ComboGenerateRequest struct {
 	// MaxDifficulty limits individual trick difficulty
 	MaxDifficulty *int64 `json:"max_difficulty" form:"max_difficulty" binding:"omitempty,min=1"`
 
+	// The ID lists below are parsed by handlers/params (capped, de-duplicated),
+	// not by query binding - hence form:"-"
+
 	// CategoryIDs filters tricks to specific categories
 	// In query string: ?category_ids=1&category_ids=2&category_ids=3
-	ExcludeCategoryIDs []int `json:"category_ids" form:"category_ids"`
+	ExcludeCategoryIDs []int `json:"category_ids" form:"-"`
 
 	// TrickIDs specifies exact tricks to include (for partial customization)
-	TrickIDs []int `json:"trick_ids" form:"trick_ids"`
+	TrickIDs []int `json:"trick_ids" form:"-"`
 
 	// ExcludeTrickIDs specifies tricks to never include
-	ExcludeTrickIDs []int `json:"exclude_trick_ids" form:"exclude_trick_ids"`
+	ExcludeTrickIDs []int `json:"exclude_trick_ids" form:"-"`
+
+	// BalanceLegs avoids more than two tricks in a row landing on the same single leg
+	BalanceLegs bool `json:"balance_legs" form:"balance_legs"`
+
+	// NotationStyle is how the response's notation is written (arrows when empty)
+	// Parsed by handlers/params so unknown styles get the allowed list back
+	NotationStyle string `json:"notation_style" form:"-"`
+}
+
+// ComboSaveRequest is the body for creating or replacing a saved combo
+type ComboSaveRequest struct {
+	Name  string  `json:"name" binding:"required"`
+	Notes *string `json:"notes"`
+
+	// Tricks in order - positions are assigned from array order
+	Tricks []ComboTrickInput `json:"tricks" binding:"required,min=1,max=20,dive"`
+
+	// AllowDuplicates must be set to repeat a trick in the combo on purpose
+	AllowDuplicates bool `json:"allow_duplicates"`
+}
+
+// ComboImportRequest is the body of POST /users/:userId/combos/import
+type ComboImportRequest struct {
+	Combos []ComboSaveRequest `json:"combos" binding:"required,min=1,max=50,dive"`
+}
+
+// ComboImportResult is the outcome of one combo of an import, numbered from 1
+// Status is ImportCreated, ImportInvalid or ImportOverLimit (Code/Error say why).
+type ComboImportResult struct {
+	Row    int            `json:"row"`
+	Status string         `json:"status"`
+	Combo  *ComboResponse `json:"combo,omitempty"`
+	Code   string         `json:"code,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// ComboImportSummary is the response of POST /users/:userId/combos/import
+// Combos are saved one at a time, so the ones saved before a failure stay saved.
+type ComboImportSummary struct {
+	Rows      int                 `json:"rows"`
+	Created   int                 `json:"created"`
+	Invalid   int                 `json:"invalid"`
+	OverLimit int                 `json:"over_limit"`
+	Results   []ComboImportResult `json:"results"`
+}
+
+// MistakeRequest is the body for creating or updating a common mistake
+type MistakeRequest struct {
+	Text     string `json:"text" binding:"required"`
+	Severity string `json:"severity" binding:"required"`
+}
+
+// TagResponse is one tag in GET /tags
+type TagResponse struct {
+	Name       string `json:"name"`
+	TrickCount int64  `json:"trick_count"` // Live tricks carrying the tag
+}
+
+// TagRequest is the body for creating a tag or tagging a trick
+type TagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AliasRequest is the body for adding an alias to a trick
+type AliasRequest struct {
+	Alias string `json:"alias" binding:"required"`
+}
+
+// TrickTranslationRequest is the body of PUT /admin/tricks/:slug/translations/:locale
+// Leave a field out (or null) to keep showing the trick's own value for it.
+type TrickTranslationRequest struct {
+	Name           *string `json:"name"`
+	Description    *string `json:"description"`
+	ExecutionNotes *string `json:"execution_notes"`
+}
+
+// PrerequisiteRequest is the body for adding a prerequisite to a trick
+type PrerequisiteRequest struct {
+	PrerequisiteID string `json:"prerequisite_id" binding:"required"`
+}
+
+// MistakeOrderRequest sets the display order of a trick's mistakes
+// It must list every one of the trick's mistake IDs exactly once
+type MistakeOrderRequest struct {
+	MistakeIDs []int64 `json:"mistake_ids" binding:"required"`
+}
+
+// TrickCreateRequest is the body of POST /tricks
+// The slug is derived from the name; stance and flip IDs must exist if given.
+type TrickCreateRequest struct {
+	Name           string  `json:"name" binding:"required"`
+	Difficulty     int64   `json:"difficulty" binding:"required,min=1,max=10"`
+	Description    *string `json:"description"`
+	ExecutionNotes *string `json:"execution_notes"`
+
+	TakeoffStanceID *int `json:"takeoff_stance_id" binding:"omitempty,min=1"`
+	LandingStanceID *int `json:"landing_stance_id" binding:"omitempty,min=1"`
+	FlipID          *int `json:"flip_id" binding:"omitempty,min=1"`
+
+	// Rotation in degrees - leave out for tricks that don't rotate
+	Rotation *int `json:"rotation" binding:"omitempty,min=0,max=1800"`
+
+	Attribution *string `json:"attribution"`
+	License     *string `json:"license"`
+}
+
+// TrickUpdateRequest is the body of PUT /tricks/:id - the trick's full new state
+// Optional fields left out are cleared. The slug stays as it is unless
+// RegenerateSlug is set, which derives a new one from the name like a create.
+type TrickUpdateRequest struct {
+	TrickCreateRequest
+	RegenerateSlug bool `json:"regenerate_slug"`
+}
+
+// PatchField is one field of a PATCH body
+// Set is false when the key was left out (leave the column alone); a JSON null
+// sets it with a nil Value (clear the column).
+type PatchField[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON implements json.Unmarshaler - it's only called for keys that are present
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Value = nil
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	f.Value = &value
+	return nil
+}
+
+// TrickPatchRequest is the body of PATCH /tricks/:id - only the keys sent change
+// null clears an optional field. Name and difficulty can't be cleared.
+type TrickPatchRequest struct {
+	Name           PatchField[string] `json:"name"`
+	Difficulty     PatchField[int64]  `json:"difficulty"`
+	Description    PatchField[string] `json:"description"`
+	ExecutionNotes PatchField[string] `json:"execution_notes"`
+
+	TakeoffStanceID PatchField[int] `json:"takeoff_stance_id"`
+	LandingStanceID PatchField[int] `json:"landing_stance_id"`
+	FlipID          PatchField[int] `json:"flip_id"`
+	Rotation        PatchField[int] `json:"rotation"`
+
+	Attribution PatchField[string] `json:"attribution"`
+	License     PatchField[string] `json:"license"`
+}
+
+// Empty reports whether the patch sets no field at all
+func (p *TrickPatchRequest) Empty() bool {
+	return !p.Name.Set && !p.Difficulty.Set && !p.Description.Set && !p.ExecutionNotes.Set &&
+		!p.TakeoffStanceID.Set && !p.LandingStanceID.Set && !p.FlipID.Set && !p.Rotation.Set &&
+		!p.Attribution.Set && !p.License.Set
+}
+
+// Validate applies TrickCreateRequest's binding rules to the fields that are set
+// (binding tags can't see inside a PatchField)
+func (p *TrickPatchRequest) Validate() error {
+	if p.Name.Set && p.Name.Value == nil {
+		return errors.New("name can't be null")
+	}
+	if p.Difficulty.Set && (p.Difficulty.Value == nil || *p.Difficulty.Value < 1 || *p.Difficulty.Value > 10) {
+		return errors.New("difficulty must be 1-10")
+	}
+	for _, id := range []struct {
+		name  string
+		field PatchField[int]
+	}{
+		{"takeoff_stance_id", p.TakeoffStanceID},
+		{"landing_stance_id", p.LandingStanceID},
+		{"flip_id", p.FlipID},
+	} {
+		if id.field.Value != nil && *id.field.Value < 1 {
+			return fmt.Errorf("%s must be at least 1", id.name)
+		}
+	}
+	if p.Rotation.Value != nil && (*p.Rotation.Value < 0 || *p.Rotation.Value > 1800) {
+		return errors.New("rotation must be 0-1800")
+	}
+	return nil
+}
+
+// VideoCreateRequest is the body for adding a video to a trick
+type VideoCreateRequest struct {
+	VideoURL        string     `json:"video_url" binding:"required"`
+	ThumbnailURL    string     `json:"thumbnail_url" binding:"required"`
+	PerformerName   string     `json:"performer_name" binding:"required"`
+	PerformerUserID *uuid.UUID `json:"performer_user_id"`
+
+	// IsFeatured makes this the trick's featured video (unfeaturing the current one)
+	IsFeatured bool `json:"is_featured"`
+
+	Attribution *string `json:"attribution"`
+	License     *string `json:"license"`
+	Label       *string `json:"label"`
+}
+
+// TrickImportRecord is one line of a bulk trick import (NDJSON)
+// Tricks are matched by slug: new slugs are created, existing ones overwritten
+type TrickImportRecord struct {
+	Slug            string  `json:"slug"`
+	Name            string  `json:"name"`
+	Description     *string `json:"description"`
+	Difficulty      *int64  `json:"difficulty"`
+	ExecutionNotes  *string `json:"execution_notes"`
+	TakeoffStanceID *int    `json:"takeoff_stance_id"`
+	LandingStanceID *int    `json:"landing_stance_id"`
+	Attribution     *string `json:"attribution"`
+	License         *string `json:"license"`
+
+	// Weight is kept as the raw JSON number so an out-of-range value is reported
+	// as such instead of failing the whole line (or truncating) - see parseImportLine.
+	// Left out, a new trick gets the column default and an existing one keeps its weight.
+	Weight *json.Number `json:"weight"`
+
+	// FlipID is the trick's category. Left out, an existing trick keeps its own
+	// (and a new one may get a suggested category - see TrickImportResult).
+	FlipID *int `json:"flip_id"`
+}
+
+// Import outcomes for a single line
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped" // The slug belongs to a deleted trick - restore it first
+	ImportInvalid = "invalid"
+	ImportValid   = "valid" // Batch creation only: passed, but not written (dry run, or another row failed)
+
+	ImportOverLimit = "over_limit" // Combo import only: not saved, the user's saved-combo cap was reached
+)
+
+// TrickImportResult is the response line for one input line
+// Code/Error are set for skipped and invalid lines
+type TrickImportResult struct {
+	Line   int    `json:"line"`
+	Slug   string `json:"slug,omitempty"`
+	Status string `json:"status"`
+	Code   string `json:"code,omitempty"`
+	Error  string `json:"error,omitempty"`
+
+	// SuggestedCategory is set for lines without flip_id when category suggestions are on
+	SuggestedCategory *CategorySuggestion `json:"suggested_category,omitempty"`
+}
+
+// TrickBatchResult is the outcome of one row of a batch creation, numbered from 1
+// Status is ImportCreated, ImportValid or ImportInvalid (Code/Error say why).
+// Slug is the slug the trick got - or, when nothing was written, would get.
+type TrickBatchResult struct {
+	Row    int    `json:"row"`
+	Slug   string `json:"slug,omitempty"`
+	Status string `json:"status"`
+	Code   string `json:"code,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// TrickBatchSummary is the response of POST /tricks/import
+// Written is true when the tricks were created - never for a dry run, and
+// never when any row is invalid (the batch is all or nothing).
+type TrickBatchSummary struct {
+	DryRun  bool               `json:"dry_run"`
+	Written bool               `json:"written"`
+	Rows    int                `json:"rows"`
+	Created int                `json:"created"`
+	Valid   int                `json:"valid"`
+	Invalid int                `json:"invalid"`
+	Results []TrickBatchResult `json:"results"`
+}
+
+// CategorySuggestion is a category picked for a trick from its name
+// Applied is true when it was confident enough to fill the trick's empty flip_id;
+// otherwise it is only a suggestion for the client to confirm.
+type CategorySuggestion struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+	Applied    bool    `json:"applied"`
+}
+
+// TrickImportSummary is the last response line of an import
+// Done is false when the import stopped early; Code/Error say why
+type TrickImportSummary struct {
+	Done    bool   `json:"done"`
+	Lines   int    `json:"lines"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+	Skipped int    `json:"skipped"`
+	Invalid int    `json:"invalid"`
+	Code    string `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// VideoImportRow is one video of a bulk video import (POST /admin/videos/import)
+// ThumbnailURL may be left out for YouTube links - it's derived from the video ID.
+type VideoImportRow struct {
+	TrickSlug     string  `json:"trick_slug"`
+	VideoURL      string  `json:"video_url"`
+	ThumbnailURL  string  `json:"thumbnail_url"`
+	PerformerName string  `json:"performer_name"`
+	Label         *string `json:"label"`
+	IsFeatured    bool    `json:"is_featured"`
+	Attribution   *string `json:"attribution"`
+	License       *string `json:"license"`
+}
+
+// VideoImportRequest is the body of a bulk video import
+// Rows are validated one by one (a bad row doesn't fail the import), so only the count is bound here.
+type VideoImportRequest struct {
+	Rows []VideoImportRow `json:"rows" binding:"required,min=1,max=1000"`
+}
+
+// VideoImportResult is the outcome of one row, numbered from 1
+// Status is ImportCreated, ImportSkipped (duplicate URL) or ImportInvalid;
+// Code/Error say why for the latter two. DemotedBy is set when a later featured
+// row for the same trick took the featured spot - this row was added unfeatured.
+type VideoImportResult struct {
+	Row        int    `json:"row"`
+	TrickSlug  string `json:"trick_slug"`
+	VideoURL   string `json:"video_url,omitempty"` // Normalized
+	Status     string `json:"status"`
+	VideoID    *int64 `json:"video_id,omitempty"`
+	IsFeatured bool   `json:"is_featured"`
+	DemotedBy  *int   `json:"demoted_by,omitempty"`
+	Code       string `json:"code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// VideoImportSummary is the response of a bulk video import
+type VideoImportSummary struct {
+	Rows    int                 `json:"rows"`
+	Created int                 `json:"created"`
+	Skipped int                 `json:"skipped"`
+	Invalid int                 `json:"invalid"`
+	Demoted int                 `json:"demoted"`
+	Results []VideoImportResult `json:"results"`
+}
+
+// ComboBatchGetRequest is the body for fetching several combos at once
+type ComboBatchGetRequest struct {
+	IDs []int64 `json:"ids" binding:"required,min=1,max=50"`
+}
+
+// ComboTricksReplaceRequest is the body for replacing only a combo's trick list
+type ComboTricksReplaceRequest struct {
+	Tricks          []ComboTrickInput `json:"tricks" binding:"required,min=1,max=20,dive"`
+	AllowDuplicates bool              `json:"allow_duplicates"`
+}
+
+// ComboTrickInput is one trick in a ComboSaveRequest
+type ComboTrickInput struct {
+	TrickID string  `json:"trick_id" binding:"required"` // Trick slug (or legacy numeric ID)
+	Note    *string `json:"note"`
 }
 
 // ComboGenerateSimpleRequest only requires size (no filters)
@@ -224,6 +1527,25 @@ type ComboGenerateSimpleRequest struct {
 // HELPER METHODS - Convert between models and DTOs
 // =============================================================================
 
+// ResponseView selects which restricted fields a converted response carries
+// Who created a trick or uploaded a video is for staff only - in PublicView
+// those fields stay nil and are left out of the JSON. Services pick the view
+// from the caller's role; anything cached and shared must use PublicView.
+type ResponseView int
+
+const (
+	PublicView ResponseView = iota // Regular users and anonymous callers
+	StaffView                      // Admins and moderators
+)
+
+// ViewForRole returns the view for a user-role header value
+func ViewForRole(role string) ResponseView {
+	if role == "admin" || role == "moderator" {
+		return StaffView
+	}
+	return PublicView
+}
+
 // ToSimpleResponse converts a Trick model to TrickSimpleResponse DTO
 // This is a method on Trick (receiver is t *Trick)
 func (t *Trick) ToSimpleResponse() TrickSimpleResponse {
@@ -234,32 +1556,71 @@ func (t *Trick) ToSimpleResponse() TrickSimpleResponse {
 }
 
 // ToDetailResponse converts a Trick model to TrickDetailResponse DTO
-func (t *Trick) ToDetailResponse() TrickDetailResponse {
-	return TrickDetailResponse{
-		ID:              t.ID,
-		Name:            t.Name,
-		Description:     t.Description,
-		Difficulty:      t.Difficulty,
-		ExecutionNotes:  t.ExecutionNotes,
-		CreatorName:     t.CreatorName,
-		TakeoffStanceID: t.TakeoffStanceID,
-		LandingStanceID: t.LandingStanceID,
-		Rotation:        t.Rotation,
-		CreatedAt:       t.CreatedAt,
-		UpdatedAt:       t.UpdatedAt,
+// created_by is only filled in for StaffView.
+func (t *Trick) ToDetailResponse(view ResponseView) TrickDetailResponse {
+	response := TrickDetailResponse{
+		ID:                t.ID,
+		Name:              t.Name,
+		Description:       t.Description,
+		Difficulty:        t.Difficulty,
+		ExecutionNotes:    t.ExecutionNotes,
+		CreatorName:       t.CreatorName,
+		TakeoffStanceID:   t.TakeoffStanceID,
+		LandingStanceID:   t.LandingStanceID,
+		Rotation:          t.Rotation,
+		Attribution:       t.Attribution,
+		License:           t.License,
+		Aliases:           t.Aliases,
+		Tags:              t.Tags,
+		PreviouslyKnownAs: t.FormerNames,
+		CreatedAt:         t.CreatedAt,
+		UpdatedAt:         t.UpdatedAt,
+	}
+	if view == StaffView {
+		response.CreatedBy = t.CreatedBy
 	}
+	return response
 }
 
 // ToResponse converts a TrickVideo model to VideoResponse DTO
-func (v *TrickVideo) ToResponse() VideoResponse {
-	return VideoResponse{
+// uploaded_by is only filled in for StaffView.
+func (v *TrickVideo) ToResponse(view ResponseView) VideoResponse {
+	response := VideoResponse{
 		ID:            v.ID,
 		VideoURL:      v.VideoURL,
 		ThumbnailURL:  v.ThumbnailURL,
 		PerformerName: v.PerformerName,
 		IsFeatured:    v.IsFeatured,
+		Attribution:   v.Attribution,
+		License:       v.License,
+		Label:         v.Label,
+		Availability:  v.Availability,
 		CreatedAt:     v.CreatedAt,
 	}
+	if view == StaffView {
+		uploadedBy := v.UploadedBy
+		response.UploadedBy = &uploadedBy
+	}
+	return response
+}
+
+// ToResponse converts a Stance model to StanceResponse DTO
+func (s *Stance) ToResponse() StanceResponse {
+	return StanceResponse{
+		ID:          s.ID,
+		Name:        s.Name,
+		Description: s.Description,
+	}
+}
+
+// ToResponse converts a Flip model to FlipResponse DTO
+func (f *Flip) ToResponse() FlipResponse {
+	return FlipResponse{
+		ID:         f.ID,
+		Name:       f.Name,
+		ParentID:   f.ParentID,
+		TrickCount: f.TrickCount,
+	}
 }
 
 // ToResponse converts a Category model to CategoryResponse DTO
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestViewForRole(t *testing.T) {
+	tests := []struct {
+		role string
+		want ResponseView
+	}{
+		{role: "admin", want: StaffView},
+		{role: "moderator", want: StaffView},
+		{role: "user", want: PublicView},
+		{role: "pro", want: PublicView},
+		{role: "Admin", want: PublicView}, // Roles are exact - the BFF sends them lowercase
+		{role: "", want: PublicView},
+	}
+
+	for _, tt := range tests {
+		if got := ViewForRole(tt.role); got != tt.want {
+			t.Errorf("ViewForRole(%q) = %v, want %v", tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestResponseViewRedaction(t *testing.T) {
+	creator := uuid.MustParse("00000000-0000-4000-8000-00000000c0de")
+	uploader := uuid.MustParse("00000000-0000-4000-8000-0000000f11e5")
+	trick := Trick{ID: "cork", Name: "Cork", CreatedBy: &creator}
+	video := TrickVideo{ID: 7, VideoURL: "https://videos.example.com/7.mp4", UploadedBy: uploader}
+
+	tests := []struct {
+		name      string
+		view      ResponseView
+		convert   func(view ResponseView) any
+		field     string
+		wantValue string // "" = the field must be absent
+	}{
+		{
+			name: "trick public", view: PublicView, field: "created_by",
+			convert: func(view ResponseView) any { return trick.ToDetailResponse(view) },
+		},
+		{
+			name: "trick staff", view: StaffView, field: "created_by", wantValue: creator.String(),
+			convert: func(view ResponseView) any { return trick.ToDetailResponse(view) },
+		},
+		{
+			name: "video public", view: PublicView, field: "uploaded_by",
+			convert: func(view ResponseView) any { return video.ToResponse(view) },
+		},
+		{
+			name: "video staff", view: StaffView, field: "uploaded_by", wantValue: uploader.String(),
+			convert: func(view ResponseView) any { return video.ToResponse(view) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.convert(tt.view))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var fields map[string]any
+			if err := json.Unmarshal(body, &fields); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			value, present := fields[tt.field]
+			switch {
+			case tt.wantValue == "" && present:
+				t.Errorf("%s = %v in %s, want it absent", tt.field, value, body)
+			case tt.wantValue != "" && value != tt.wantValue:
+				t.Errorf("%s = %v, want %s", tt.field, value, tt.wantValue)
+			}
+			// The raw model fields never leak under another name
+			if tt.wantValue == "" && (strings.Contains(string(body), creator.String()) || strings.Contains(string(body), uploader.String())) {
+				t.Errorf("public response carries a user ID: %s", body)
+			}
+		})
+	}
+}
+
+func TestTrickModelHidesCreator(t *testing.T) {
+	creator := uuid.MustParse("00000000-0000-4000-8000-00000000c0de")
+	body, err := json.Marshal(Trick{ID: "cork", CreatedBy: &creator})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), creator.String()) {
+		t.Errorf("models.Trick JSON carries created_by: %s", body)
+	}
+}
+
+func TestTrickPatchRequestNullVersusOmitted(t *testing.T) {
+	var req TrickPatchRequest
+	if err := json.Unmarshal([]byte(`{"description": null, "difficulty": 4}`), &req); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	// An explicit null is set, with no value: clear the column
+	if !req.Description.Set || req.Description.Value != nil {
+		t.Errorf("description = %+v, want set to null", req.Description)
+	}
+	if !req.Difficulty.Set || req.Difficulty.Value == nil || *req.Difficulty.Value != 4 {
+		t.Errorf("difficulty = %+v, want set to 4", req.Difficulty)
+	}
+	// An omitted key isn't set at all: leave the column alone
+	if req.ExecutionNotes.Set || req.Name.Set || req.Rotation.Set {
+		t.Errorf("omitted keys were set: %+v", req)
+	}
+	if req.Empty() {
+		t.Error("Empty() = true for a patch that sets two fields")
+	}
+
+	var empty TrickPatchRequest
+	if err := json.Unmarshal([]byte(`{}`), &empty); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !empty.Empty() {
+		t.Errorf("Empty() = false for {}: %+v", empty)
+	}
+}
+
+func TestTrickPatchRequestValidate(t *testing.T) {
+	tests := []struct {
+		body    string
+		wantErr string // "" for valid
+	}{
+		{body: `{"description": null, "execution_notes": null}`},
+		{body: `{"takeoff_stance_id": null, "flip_id": null, "rotation": null}`},
+		{body: `{"name": "Cork", "difficulty": 10, "rotation": 1800}`},
+		{body: `{"difficulty": 1, "rotation": 0, "landing_stance_id": 1}`},
+		{body: `{"name": null}`, wantErr: "name"},
+		{body: `{"difficulty": null}`, wantErr: "difficulty"},
+		{body: `{"difficulty": 0}`, wantErr: "difficulty"},
+		{body: `{"difficulty": 11}`, wantErr: "difficulty"},
+		{body: `{"takeoff_stance_id": 0}`, wantErr: "takeoff_stance_id"},
+		{body: `{"flip_id": -1}`, wantErr: "flip_id"},
+		{body: `{"rotation": -180}`, wantErr: "rotation"},
+		{body: `{"rotation": 1980}`, wantErr: "rotation"},
+	}
+
+	for _, tt := range tests {
+		var req TrickPatchRequest
+		if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+			t.Fatalf("decode %s: %v", tt.body, err)
+		}
+
+		err := req.Validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("Validate(%s) = %v, want nil", tt.body, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("Validate(%s) = %v, want an error about %s", tt.body, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// =============================================================================
+// FILE: internal/ratelimit/ratelimit.go
+// PURPOSE: Token bucket rate limiter with optional context-aware waiting
+// =============================================================================
+//
+// TOKEN BUCKET:
+// The bucket holds up to `burst` tokens and refills at `rate` tokens/second.
+// Each request takes one token. When the bucket is empty a request can either
+// be rejected right away (hard mode) or wait for the next token (queue mode).
+//
+// Waiting requests RESERVE their token up front (the balance may go negative),
+// so waiters are served in arrival order and never steal each other's tokens.
+// =============================================================================
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// State is a bucket's balance as of one request, for the X-RateLimit-* headers
+// It is read under the same lock that takes the request's token, so two
+// concurrent requests on one bucket never report the same Remaining.
+type State struct {
+	Limit     int           // Burst - the most requests allowed back to back
+	Remaining int           // Requests that would still be allowed right now
+	Reset     time.Duration // Until the bucket is full again
+}
+
+// Limiter is a token bucket shared by every request in a route group
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens added per second
+	burst  float64 // Maximum tokens the bucket can hold
+	tokens float64 // Current balance (negative = tokens already promised to waiters)
+	last   time.Time
+}
+
+// NewLimiter creates a new Limiter that starts full
+func NewLimiter(requestsPerSecond float64, burst int) *Limiter {
+	return &Limiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a token if one is available right now (hard mode)
+// The State is the bucket after this request, whether or not it was allowed.
+func (l *Limiter) Allow() (State, bool) {
+	wait, state, ok := l.reserve(0)
+	return state, ok && wait == 0
+}
+
+// Wait takes a token, blocking up to maxWait for one to become available (queue mode)
+// Returns how long the caller waited, the bucket's state as of the reservation,
+// and false if the wait would exceed maxWait or ctx ended first.
+// A false return never consumes a token.
+func (l *Limiter) Wait(ctx context.Context, maxWait time.Duration) (time.Duration, State, bool) {
+	wait, state, ok := l.reserve(maxWait)
+	if !ok {
+		return 0, state, false
+	}
+	if wait == 0 {
+		return 0, state, true
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return wait, state, true
+	case <-ctx.Done():
+		// Client went away - hand the reserved token back
+		l.cancel()
+		return 0, state, false
+	}
+}
+
+// reserve refills the bucket and takes one token if it will be available within maxWait
+// The returned State is computed under the lock, after the token is taken (or not).
+func (l *Limiter) reserve(maxWait time.Duration) (time.Duration, State, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// How long until this request's token exists
+	var wait time.Duration
+	if deficit := 1 - l.tokens; deficit > 0 {
+		wait = time.Duration(deficit / l.rate * float64(time.Second))
+	}
+	if wait > maxWait {
+		return 0, l.state(), false
+	}
+
+	l.tokens--
+	return wait, l.state(), true
+}
+
+// state snapshots the bucket (l.mu must be held)
+func (l *Limiter) state() State {
+	return State{
+		Limit:     int(l.burst),
+		Remaining: int(math.Max(0, math.Floor(l.tokens))),
+		Reset:     time.Duration((l.burst - l.tokens) / l.rate * float64(time.Second)),
+	}
+}
+
+// cancel returns a reserved token to the bucket
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+// idleAfter is how long a KeyedLimiter keeps a key nobody has used
+// Any bucket idle this long has refilled anyway, so dropping it changes nothing.
+const idleAfter = 10 * time.Minute
+
+// KeyedLimiter gives every key (e.g. a client IP) its own token bucket
+// Buckets are created on first use and swept once idle, so the map only
+// holds recently active keys.
+type KeyedLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     int
+	limiters  map[string]*Limiter
+	lastSweep time.Time
+}
+
+// NewKeyedLimiter creates a new KeyedLimiter - every key starts with a full bucket
+func NewKeyedLimiter(requestsPerSecond float64, burst int) *KeyedLimiter {
+	return &KeyedLimiter{
+		rate:      requestsPerSecond,
+		burst:     burst,
+		limiters:  make(map[string]*Limiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// For returns the bucket for key, creating it if needed
+func (k *KeyedLimiter) For(key string) *Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(k.lastSweep) > idleAfter {
+		k.sweep(now)
+	}
+
+	limiter, ok := k.limiters[key]
+	if !ok {
+		limiter = NewLimiter(k.rate, k.burst)
+		k.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// sweep drops buckets that haven't been touched for idleAfter (k.mu must be held)
+func (k *KeyedLimiter) sweep(now time.Time) {
+	for key, limiter := range k.limiters {
+		limiter.mu.Lock()
+		idle := now.Sub(limiter.last) > idleAfter
+		limiter.mu.Unlock()
+		if idle {
+			delete(k.limiters, key)
+		}
+	}
+	k.lastSweep = now
+}
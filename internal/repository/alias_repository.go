@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// TABLE STRUCTURE (need to create this):
+//
+// CREATE TABLE trick_data.trick_aliases (
+//     id         BIGSERIAL PRIMARY KEY,
+//     trick_id   INTEGER NOT NULL REFERENCES trick_data.tricks (id),
+//     alias      TEXT NOT NULL,
+//     slug       TEXT NOT NULL,
+//     former_name BOOLEAN NOT NULL DEFAULT FALSE,
+//     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+// );
+// CREATE UNIQUE INDEX trick_aliases_alias_lower ON trick_data.trick_aliases (lower(alias));
+// CREATE UNIQUE INDEX trick_aliases_slug ON trick_data.trick_aliases (slug);
+// CREATE INDEX trick_aliases_trick_id ON trick_data.trick_aliases (trick_id);
+//
+// An alias must not clash with any trick name or slug either - the unique
+// indexes can't see across tables, so Add checks that under a lock.
+//
+// former_name marks a name the trick had before a rename (recorded by
+// TrickRepository.UpsertImported). Former names resolve and search like any
+// alias, but are listed as "previously known as" rather than as aliases, and
+// may match their own trick's slug - "Backflip" renamed keeps slug backflip.
+// =============================================================================
+
+// ErrAliasTaken is returned when an alias matches an existing trick name, slug or alias
+var ErrAliasTaken = errors.New("alias is already a trick name or alias")
+
+// aliasLockKey serializes alias writes (pg_advisory_xact_lock)
+// Without it, an alias and a trick with the same name could pass the check together.
+const aliasLockKey = 7_221_002
+
+// AliasRepositoryInterface defines the contract for trick alias data operations
+type AliasRepositoryInterface interface {
+	ResolveSlug(ctx context.Context, aliasSlug string) (string, error)
+	Add(ctx context.Context, trickSlug, alias, aliasSlug string) (*models.TrickAlias, error)
+	Remove(ctx context.Context, trickSlug, aliasSlug string) error
+	FindFormerNames(ctx context.Context, trickSlug string) ([]models.TrickAlias, error)
+	UpdateFormerName(ctx context.Context, trickSlug, aliasSlug, alias, newAliasSlug string) (*models.TrickAlias, error)
+	RemoveFormerName(ctx context.Context, trickSlug, aliasSlug string) error
+}
+
+// AliasRepository implements AliasRepositoryInterface
+type AliasRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAliasRepository creates a new AliasRepository instance
+func NewAliasRepository(pool *pgxpool.Pool) *AliasRepository {
+	return &AliasRepository{pool: pool}
+}
+
+// ResolveSlug returns the slug of the live trick an alias slug belongs to
+// Returns ErrNotFound if no alias has that slug (or its trick is deleted)
+func (r *AliasRepository) ResolveSlug(ctx context.Context, aliasSlug string) (string, error) {
+	var slug string
+	err := r.pool.QueryRow(ctx, `
+		SELECT t.slug
+		FROM trick_data.trick_aliases a
+		JOIN trick_data.tricks t ON t.id = a.trick_id
+		WHERE a.slug = $1 AND t.deleted_at IS NULL AND t.status = 'approved'`,
+		aliasSlug,
+	).Scan(&slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to resolve alias %s: %w", aliasSlug, err)
+	}
+	return slug, nil
+}
+
+// Add gives a trick an alternate name
+// alias and aliasSlug must already be cleaned. Errors: ErrNotFound (trick),
+// ErrAliasTaken. Deleted tricks' names count - they come back on restore.
+// Bumps the trick's updated_at - aliases are part of its detail response.
+func (r *AliasRepository) Add(ctx context.Context, trickSlug, alias, aliasSlug string) (*models.TrickAlias, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, aliasLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock aliases: %w", err)
+	}
+
+	var trickID int
+	err = tx.QueryRow(ctx, touchTrickQuery, trickSlug).Scan(&trickID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick %s: %w", trickSlug, err)
+	}
+
+	var taken bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM trick_data.tricks
+			WHERE lower(name) = lower($1) OR slug = $2
+		) OR EXISTS (
+			SELECT 1 FROM trick_data.trick_aliases
+			WHERE lower(alias) = lower($1) OR slug = $2
+		)`,
+		alias, aliasSlug,
+	).Scan(&taken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check alias %s: %w", alias, err)
+	}
+	if taken {
+		return nil, ErrAliasTaken
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO trick_data.trick_aliases (trick_id, alias, slug) VALUES ($1, $2, $3)`,
+		trickID, alias, aliasSlug,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add alias %s to trick %s: %w", alias, trickSlug, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &models.TrickAlias{TrickID: trickSlug, Alias: alias, Slug: aliasSlug}, nil
+}
+
+// Remove deletes one of a trick's aliases, by alias slug
+// Returns ErrNotFound if the trick doesn't exist or doesn't have that alias
+// Former names are aliases too - this removes them as well.
+func (r *AliasRepository) Remove(ctx context.Context, trickSlug, aliasSlug string) error {
+	return r.remove(ctx, trickSlug, aliasSlug, false)
+}
+
+// RemoveFormerName deletes one of a trick's former names, by alias slug
+// Returns ErrNotFound if the trick doesn't exist or doesn't have that former name
+func (r *AliasRepository) RemoveFormerName(ctx context.Context, trickSlug, aliasSlug string) error {
+	return r.remove(ctx, trickSlug, aliasSlug, true)
+}
+
+// remove is Remove, limited to former names when formerOnly is set
+func (r *AliasRepository) remove(ctx context.Context, trickSlug, aliasSlug string, formerOnly bool) error {
+	tag, err := r.pool.Exec(ctx,
+		`WITH t AS (`+touchTrickQuery+`)
+		 DELETE FROM trick_data.trick_aliases a
+		 USING t
+		 WHERE a.trick_id = t.id AND a.slug = $2 AND (a.former_name OR NOT $3)`,
+		trickSlug, aliasSlug, formerOnly,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove alias %s from trick %s: %w", aliasSlug, trickSlug, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// FindFormerNames lists a live trick's former names, most recent first
+// An unknown trick has none - the result is simply empty.
+func (r *AliasRepository) FindFormerNames(ctx context.Context, trickSlug string) ([]models.TrickAlias, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT t.slug, a.alias, a.slug, a.former_name
+		FROM trick_data.trick_aliases a
+		JOIN trick_data.tricks t ON t.id = a.trick_id
+		WHERE t.slug = $1 AND t.deleted_at IS NULL AND a.former_name
+		ORDER BY a.created_at DESC, a.id DESC`,
+		trickSlug,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query former names of trick %s: %w", trickSlug, err)
+	}
+
+	names, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.TrickAlias])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect former names: %w", err)
+	}
+	return names, nil
+}
+
+// UpdateFormerName corrects the spelling of one of a trick's former names
+// alias and newAliasSlug must already be cleaned. Errors: ErrNotFound (trick or
+// former name), ErrAliasTaken - checked like Add, except that the trick's own
+// name, slug and the former name being edited don't count.
+func (r *AliasRepository) UpdateFormerName(ctx context.Context, trickSlug, aliasSlug, alias, newAliasSlug string) (*models.TrickAlias, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, aliasLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock aliases: %w", err)
+	}
+
+	var trickID int
+	err = tx.QueryRow(ctx, touchTrickQuery, trickSlug).Scan(&trickID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick %s: %w", trickSlug, err)
+	}
+
+	var taken bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM trick_data.tricks
+			WHERE id <> $3 AND (lower(name) = lower($1) OR slug = $2)
+		) OR EXISTS (
+			SELECT 1 FROM trick_data.trick_aliases
+			WHERE (lower(alias) = lower($1) OR slug = $2)
+				AND NOT (trick_id = $3 AND slug = $4)
+		)`,
+		alias, newAliasSlug, trickID, aliasSlug,
+	).Scan(&taken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check alias %s: %w", alias, err)
+	}
+	if taken {
+		return nil, ErrAliasTaken
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE trick_data.trick_aliases SET alias = $3, slug = $4
+		WHERE trick_id = $1 AND slug = $2 AND former_name`,
+		trickID, aliasSlug, alias, newAliasSlug,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update former name %s of trick %s: %w", aliasSlug, trickSlug, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &models.TrickAlias{TrickID: trickSlug, Alias: alias, Slug: newAliasSlug, FormerName: true}, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// TABLE STRUCTURE (need to create this):
+//
+// CREATE TABLE api_keys (
+//     id         BIGSERIAL PRIMARY KEY,
+//     key_hash   BYTEA NOT NULL,             -- SHA-256 of the key
+//     prefix     TEXT NOT NULL,              -- First characters, for listings
+//     owner      TEXT NOT NULL,
+//     scopes     TEXT[] NOT NULL,
+//     rate_tier  TEXT NOT NULL,
+//     created_by UUID,
+//     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//     revoked_at TIMESTAMPTZ,
+//     CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash)
+// );
+//
+// Keys are looked up by hash on every external request (cached in the
+// service), which the unique constraint's index serves. Revoked keys are kept
+// so listings still show who had access and when it ended.
+// =============================================================================
+
+// APIKeyRepositoryInterface defines the contract for external API key data operations
+type APIKeyRepositoryInterface interface {
+	Insert(ctx context.Context, key *models.APIKey, hash []byte) error
+	FindByHash(ctx context.Context, hash []byte) (*models.APIKey, error)
+	FindAll(ctx context.Context) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id int64) (*models.APIKey, error)
+}
+
+// APIKeyRepository implements APIKeyRepositoryInterface
+type APIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository instance
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{pool: pool}
+}
+
+// apiKeyColumns are the columns of models.APIKey, for RowToStructByName
+const apiKeyColumns = `id, prefix, owner, scopes, rate_tier, created_by, created_at, revoked_at`
+
+// Insert stores a new key under its hash; ID and CreatedAt are set from the new row
+func (r *APIKeyRepository) Insert(ctx context.Context, key *models.APIKey, hash []byte) error {
+	query := `
+		INSERT INTO api_keys (key_hash, prefix, owner, scopes, rate_tier, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, hash, key.Prefix, key.Owner, key.Scopes, key.RateTier, key.CreatedBy).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert API key: %w", err)
+	}
+	return nil
+}
+
+// FindByHash returns the key with the given hash, revoked or not
+// Returns ErrNotFound if no key has it.
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash []byte) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	return retryRead(ctx, r.pool, "api_key_by_hash", func(q querier) (*models.APIKey, error) {
+		rows, err := q.Query(ctx, query, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query API key: %w", err)
+		}
+		key, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[models.APIKey])
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to get API key: %w", err)
+		}
+		return key, nil
+	})
+}
+
+// FindAll returns every key, newest first (revoked ones included)
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+
+	keys, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.APIKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect API key rows: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke marks a key revoked and returns it
+// Revoking a revoked key keeps its original revoked_at. Returns ErrNotFound
+// if the key doesn't exist.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int64) (*models.APIKey, error) {
+	query := `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING ` + apiKeyColumns
+
+	rows, err := r.pool.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke API key %d: %w", id, err)
+	}
+	key, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[models.APIKey])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to revoke API key %d: %w", id, err)
+	}
+	return key, nil
+}
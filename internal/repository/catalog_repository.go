@@ -0,0 +1,179 @@
+// =============================================================================
+// TABLE STRUCTURE (catalog change tracking - need to create these):
+//
+// ALTER TABLE trick_data.tricks ADD COLUMN deleted_at TIMESTAMPTZ;
+//
+// CREATE TABLE trick_data.trick_revisions (
+//     id BIGSERIAL PRIMARY KEY,
+//     trick_id INTEGER NOT NULL REFERENCES trick_data.tricks(id),
+//     changed_fields TEXT[] NOT NULL,  -- Column names changed by this edit
+//     changed_by UUID,
+//     changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+// );
+// CREATE INDEX ON trick_data.trick_revisions (changed_at);
+//
+// ALTER TABLE trick_data.categories
+//     ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//     ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+// =============================================================================
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// CatalogRepositoryInterface defines the contract for catalog-wide reporting queries
+type CatalogRepositoryInterface interface {
+	FindChanges(ctx context.Context, from, to time.Time) ([]models.CatalogChange, error)
+	FindAttributions(ctx context.Context) ([]models.AttributedContent, error)
+	GetStats(ctx context.Context) (*models.AdminStats, error)
+}
+
+// CatalogRepository implements CatalogRepositoryInterface
+type CatalogRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCatalogRepository creates a new CatalogRepository instance
+func NewCatalogRepository(pool *pgxpool.Pool) *CatalogRepository {
+	return &CatalogRepository{pool: pool}
+}
+
+// FindChanges retrieves every catalog change in the window [from, to)
+// Results are ordered oldest change first
+//
+// Videos are reported when created and when edited (trick_videos.updated_at).
+// They are never deleted on their own - only when a soft-deleted trick is
+// purged - so a video's removal shows up as its trick's "deleted" row.
+func (r *CatalogRepository) FindChanges(ctx context.Context, from, to time.Time) ([]models.CatalogChange, error) {
+	// One UNION ALL query instead of seven round trips
+	// Every branch returns the same columns so they can be collected into one struct
+	query := `
+		SELECT 'trick' AS entity, slug AS entity_id, name, 'created' AS change,
+			NULL::TEXT[] AS changed_fields, created_at AS changed_at
+		FROM trick_data.tricks
+		WHERE created_at >= $1 AND created_at < $2
+
+		UNION ALL
+
+		-- One row per trick: every field touched by any revision in the window
+		SELECT 'trick', t.slug, t.name, 'updated',
+			ARRAY_AGG(DISTINCT f.field ORDER BY f.field), MAX(r.changed_at)
+		FROM trick_data.trick_revisions r
+		JOIN trick_data.tricks t ON t.id = r.trick_id
+		CROSS JOIN LATERAL UNNEST(r.changed_fields) AS f(field)
+		WHERE r.changed_at >= $1 AND r.changed_at < $2
+		GROUP BY t.slug, t.name
+
+		UNION ALL
+
+		SELECT 'trick', slug, name, 'deleted', NULL, deleted_at
+		FROM trick_data.tricks
+		WHERE deleted_at >= $1 AND deleted_at < $2
+
+		UNION ALL
+
+		SELECT 'video', v.id::TEXT, t.name, 'created', NULL, v.created_at
+		FROM trick_data.trick_videos v
+		JOIN trick_data.tricks t ON t.id = v.trick_id
+		WHERE v.created_at >= $1 AND v.created_at < $2
+
+		UNION ALL
+
+		SELECT 'video', v.id::TEXT, t.name, 'updated', NULL, v.updated_at
+		FROM trick_data.trick_videos v
+		JOIN trick_data.tricks t ON t.id = v.trick_id
+		WHERE v.updated_at >= $1 AND v.updated_at < $2
+
+		UNION ALL
+
+		SELECT 'category', id::TEXT, name, 'created', NULL, created_at
+		FROM trick_data.categories
+		WHERE created_at >= $1 AND created_at < $2
+
+		UNION ALL
+
+		SELECT 'category', id::TEXT, name, 'updated', NULL, updated_at
+		FROM trick_data.categories
+		WHERE updated_at >= $1 AND updated_at < $2 AND updated_at > created_at
+
+		ORDER BY changed_at ASC, entity ASC, entity_id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query catalog changes: %w", err)
+	}
+
+	changes, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.CatalogChange])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect catalog change rows: %w", err)
+	}
+
+	return changes, nil
+}
+
+// FindAttributions retrieves every trick and video with a non-null attribution
+// Ordered by attribution so callers can group consecutive rows
+func (r *CatalogRepository) FindAttributions(ctx context.Context) ([]models.AttributedContent, error) {
+	query := `
+		SELECT attribution, 'trick' AS content_type, slug AS content_id, name, license
+		FROM trick_data.tricks
+		WHERE attribution IS NOT NULL
+
+		UNION ALL
+
+		SELECT v.attribution, 'video', v.id::TEXT, t.name, v.license
+		FROM trick_data.trick_videos v
+		JOIN trick_data.tricks t ON t.id = v.trick_id
+		WHERE v.attribution IS NOT NULL
+
+		ORDER BY attribution ASC, content_type ASC, name ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attributions: %w", err)
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AttributedContent])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect attribution rows: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetStats counts tricks and videos for the admin dashboard in one round trip
+func (r *CatalogRepository) GetStats(ctx context.Context) (*models.AdminStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM trick_data.tricks WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM trick_data.tricks WHERE deleted_at IS NOT NULL),
+			(SELECT COUNT(*) FROM trick_data.trick_videos),
+			(SELECT COUNT(*) FROM trick_data.trick_videos WHERE availability = 'unavailable'),
+			(SELECT COUNT(*) FROM trick_data.tricks WHERE deleted_at IS NULL AND weight_modifier < 1.0)
+	`
+
+	var stats models.AdminStats
+	err := r.pool.QueryRow(ctx, query).Scan(
+		&stats.LiveTricks,
+		&stats.PendingPurge,
+		&stats.Videos,
+		&stats.DeadVideos,
+		&stats.DecayedTricks,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog stats: %w", err)
+	}
+
+	return &stats, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
@@ -13,6 +14,7 @@ import (
 // CategoryRepositoryInterface defines the contract for category data operations
 type CategoryRepositoryInterface interface {
 	FindAll(ctx context.Context) ([]models.Category, error)
+	GetByID(ctx context.Context, id int) (*models.Category, error)
 }
 
 // CategoryRepository implements CategoryRepositoryInterface
@@ -46,3 +48,24 @@ func (r *CategoryRepository) FindAll(ctx context.Context) ([]models.Category, er
 
 	return categories, nil
 }
+
+// GetByID retrieves a single category
+// Returns ErrNotFound if it doesn't exist
+func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	query := `
+		SELECT id, name, parent_id
+		FROM trick_data.categories
+		WHERE id = $1
+	`
+
+	var category models.Category
+	err := r.pool.QueryRow(ctx, query, id).Scan(&category.ID, &category.Name, &category.ParentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get category by ID %d: %w", id, err)
+	}
+
+	return &category, nil
+}
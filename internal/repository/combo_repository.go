@@ -5,21 +5,32 @@
 //     id BIGSERIAL PRIMARY KEY,
 //     user_id UUID NOT NULL,
 //     name TEXT NOT NULL,
-//     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+//     notes TEXT,                 -- Free-form notes on the whole combo
+//     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+//     is_shared BOOLEAN NOT NULL DEFAULT false,    -- Owner made it visible to others
+//     is_featured BOOLEAN NOT NULL DEFAULT false   -- Admin picked it for everyone
 // );
 //
 // CREATE TABLE combo_tricks (
 //     combo_id BIGINT REFERENCES combos(id) ON DELETE CASCADE,
 //     trick_id INTEGER REFERENCES tricks(id),
 //     position INTEGER NOT NULL,  -- Order in the combo
+//     note TEXT,                  -- Note on this position ("set the cork higher here")
 //     PRIMARY KEY (combo_id, trick_id, position)
 // );
+//
+// CREATE INDEX combos_user_id_idx ON combos (user_id);
+//
+// combo_tricks keeps the integer trick_id - the API speaks slugs, which the
+// service resolves with ResolveTrickIDs before a write. Rows saved before the
+// API switched to slugs need no migration: reads join back to the slug.
 // =============================================================================
 
 package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -30,11 +41,24 @@ import (
 	"tricking-api/internal/models"
 )
 
+// ComboTrick is one position of a combo write, its trick already resolved
+// (see ResolveTrickIDs). Position comes from the slice order.
+type ComboTrick struct {
+	TrickID int
+	Note    *string
+}
+
 // ComboRepositoryInterface defines the contract for combo data operations
 type ComboRepositoryInterface interface {
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.Combo, error)
-	GetTricksForCombo(ctx context.Context, comboID int64) ([]models.TrickSimpleResponse, error)
-	Create(ctx context.Context, userID uuid.UUID, name string, trickIDs []int) (*models.Combo, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	GetTricksForCombo(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]models.ComboSummary, error)
+	GetTricksForCombos(ctx context.Context, comboIDs []int64) (map[int64][]models.ComboTrickResponse, error)
+	Create(ctx context.Context, userID uuid.UUID, name string, notes *string, tricks []ComboTrick) (*models.Combo, error)
+	Update(ctx context.Context, userID uuid.UUID, comboID int64, name string, notes *string, tricks []ComboTrick) (*models.Combo, error)
+	ReplaceTricks(ctx context.Context, userID uuid.UUID, comboID int64, tricks []ComboTrick) (*models.Combo, error)
+	ResolveTrickIDs(ctx context.Context, ids []string) (map[string]int, error)
 }
 
 // ComboRepository implements ComboRepositoryInterface
@@ -50,7 +74,7 @@ func NewComboRepository(pool *pgxpool.Pool) *ComboRepository {
 // FindByUserID retrieves all combos for a specific user
 func (r *ComboRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.Combo, error) {
 	query := `
-		SELECT id, user_id, name, created_at
+		SELECT id, user_id, name, notes, created_at, is_shared, is_featured
 		FROM combos
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -70,9 +94,104 @@ func (r *ComboRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([
 	return combos, nil
 }
 
+// CountByUserID returns how many combos a user has saved
+// Served by the combos (user_id) index - cheap enough to run before every save
+func (r *ComboRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM combos WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count combos for user: %w", err)
+	}
+	return count, nil
+}
+
+// GetTricksForCombo retrieves a combo's tricks in order, with their position notes
+func (r *ComboRepository) GetTricksForCombo(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error) {
+	query := `
+		SELECT t.slug AS id, t.id AS trick_id, t.slug, t.name, t.difficulty, ct.position, ct.note,
+			t.deleted_at IS NOT NULL AS deleted
+		FROM combo_tricks ct
+		JOIN trick_data.tricks t ON t.id = ct.trick_id
+		WHERE ct.combo_id = $1
+		ORDER BY ct.position ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, comboID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query combo tricks: %w", err)
+	}
+
+	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.ComboTrickResponse])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect combo trick rows: %w", err)
+	}
+
+	return tricks, nil
+}
+
+// GetByIDs retrieves combos by ID with their aggregates, in no particular order
+// IDs that don't exist are simply absent from the result
+func (r *ComboRepository) GetByIDs(ctx context.Context, ids []int64) ([]models.ComboSummary, error) {
+	query := `
+		SELECT
+			c.id, c.user_id, c.name, c.notes, c.created_at, c.is_shared, c.is_featured,
+			COALESCE(SUM(COALESCE(t.difficulty, 0)), 0)::BIGINT AS total_difficulty,
+			COUNT(ct.trick_id) AS trick_count
+		FROM combos c
+		LEFT JOIN combo_tricks ct ON ct.combo_id = c.id
+		LEFT JOIN trick_data.tricks t ON t.id = ct.trick_id
+		WHERE c.id = ANY($1)
+		GROUP BY c.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query combos by ID: %w", err)
+	}
+
+	combos, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.ComboSummary])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect combo rows: %w", err)
+	}
+
+	return combos, nil
+}
+
+// GetTricksForCombos loads the tricks of several combos in ONE query
+// This is the batched version of GetTricksForCombo - use it whenever you'd
+// otherwise call GetTricksForCombo in a loop. Combos with no tricks are absent from the map.
+func (r *ComboRepository) GetTricksForCombos(ctx context.Context, comboIDs []int64) (map[int64][]models.ComboTrickResponse, error) {
+	query := `
+		SELECT ct.combo_id, t.id, t.slug, t.name, t.difficulty, ct.position, ct.note, t.deleted_at IS NOT NULL
+		FROM combo_tricks ct
+		JOIN trick_data.tricks t ON t.id = ct.trick_id
+		WHERE ct.combo_id = ANY($1)
+		ORDER BY ct.combo_id, ct.position ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, comboIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query combo tricks: %w", err)
+	}
+
+	tricksByCombo := make(map[int64][]models.ComboTrickResponse, len(comboIDs))
+	var comboID int64
+	var trick models.ComboTrickResponse
+	_, err = pgx.ForEachRow(rows, []any{&comboID, &trick.TrickID, &trick.Slug, &trick.Name, &trick.Difficulty, &trick.Position, &trick.Note, &trick.Deleted}, func() error {
+		trick.ID = trick.Slug
+		tricksByCombo[comboID] = append(tricksByCombo[comboID], trick)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect combo trick rows: %w", err)
+	}
+
+	return tricksByCombo, nil
+}
+
 // Create saves a new combo with its tricks
 // Uses a transaction to ensure atomic creation
-func (r *ComboRepository) Create(ctx context.Context, userID uuid.UUID, name string, trickIDs []int) (*models.Combo, error) {
+func (r *ComboRepository) Create(ctx context.Context, userID uuid.UUID, name string, notes *string, tricks []ComboTrick) (*models.Combo, error) {
 	// ==========================================================================
 	// TRANSACTION EXAMPLE
 	// ==========================================================================
@@ -92,22 +211,15 @@ func (r *ComboRepository) Create(ctx context.Context, userID uuid.UUID, name str
 	var comboID int64
 	var createdAt time.Time
 	err = tx.QueryRow(ctx,
-		`INSERT INTO combos (user_id, name) VALUES ($1, $2) RETURNING id, created_at`,
-		userID, name,
+		`INSERT INTO combos (user_id, name, notes) VALUES ($1, $2, $3) RETURNING id, created_at`,
+		userID, name, notes,
 	).Scan(&comboID, &createdAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert combo: %w", err)
 	}
 
-	// Insert each trick in the combo
-	for position, trickID := range trickIDs {
-		_, err = tx.Exec(ctx,
-			`INSERT INTO combo_tricks (combo_id, trick_id, position) VALUES ($1, $2, $3)`,
-			comboID, trickID, position+1, // Position is 1-indexed
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to insert combo trick: %w", err)
-		}
+	if err := insertComboTricks(ctx, tx, comboID, tricks); err != nil {
+		return nil, err
 	}
 
 	// Commit the transaction
@@ -119,6 +231,152 @@ func (r *ComboRepository) Create(ctx context.Context, userID uuid.UUID, name str
 		ID:        comboID,
 		UserID:    userID,
 		Name:      name,
+		Notes:     notes,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// Update replaces a combo's name, notes and full trick list
+// Returns ErrNotFound if the combo doesn't exist or belongs to another user
+func (r *ComboRepository) Update(ctx context.Context, userID uuid.UUID, comboID int64, name string, notes *string, tricks []ComboTrick) (*models.Combo, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Matching on user_id too means users can never edit someone else's combo
+	var createdAt time.Time
+	err = tx.QueryRow(ctx,
+		`UPDATE combos SET name = $1, notes = $2 WHERE id = $3 AND user_id = $4 RETURNING created_at`,
+		name, notes, comboID, userID,
+	).Scan(&createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to update combo %d: %w", comboID, err)
+	}
+
+	// Replace the trick list wholesale - simpler than diffing positions
+	if _, err := tx.Exec(ctx, `DELETE FROM combo_tricks WHERE combo_id = $1`, comboID); err != nil {
+		return nil, fmt.Errorf("failed to clear combo tricks: %w", err)
+	}
+
+	if err := insertComboTricks(ctx, tx, comboID, tricks); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &models.Combo{
+		ID:        comboID,
+		UserID:    userID,
+		Name:      name,
+		Notes:     notes,
 		CreatedAt: createdAt,
 	}, nil
 }
+
+// ReplaceTricks replaces a combo's trick list, leaving name and notes alone
+// Returns ErrNotFound if the combo doesn't exist or belongs to another user
+func (r *ComboRepository) ReplaceTricks(ctx context.Context, userID uuid.UUID, comboID int64, tricks []ComboTrick) (*models.Combo, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR UPDATE locks the combo so two concurrent edits can't interleave their rewrites
+	var combo models.Combo
+	err = tx.QueryRow(ctx,
+		`SELECT id, user_id, name, notes, created_at, is_shared, is_featured
+		 FROM combos WHERE id = $1 AND user_id = $2 FOR UPDATE`,
+		comboID, userID,
+	).Scan(&combo.ID, &combo.UserID, &combo.Name, &combo.Notes, &combo.CreatedAt, &combo.IsShared, &combo.IsFeatured)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get combo %d: %w", comboID, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM combo_tricks WHERE combo_id = $1`, comboID); err != nil {
+		return nil, fmt.Errorf("failed to clear combo tricks: %w", err)
+	}
+
+	if err := insertComboTricks(ctx, tx, comboID, tricks); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &combo, nil
+}
+
+// ResolveTrickIDs maps trick slugs (or legacy numeric IDs) to the integer keys
+// combo_tricks stores, in ONE query, keyed by the identifier as given. Same
+// precedence as TrickRepository.ResolveNumericID: a slug that is the number
+// wins over the primary key. Deleted tricks still resolve, so re-saving a
+// combo keeps a trick deleted since it was saved; pending tricks don't.
+// Unknown identifiers are absent from the map.
+func (r *ComboRepository) ResolveTrickIDs(ctx context.Context, ids []string) (map[string]int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT i.ident, t.id
+		FROM unnest($1::TEXT[]) AS i (ident)
+		JOIN LATERAL (
+			SELECT id
+			FROM trick_data.tricks
+			WHERE status = 'approved'
+				AND (slug = i.ident OR (i.ident ~ '^[0-9]{1,9}$' AND id = i.ident::INTEGER))
+			ORDER BY slug = i.ident DESC
+			LIMIT 1
+		) t ON TRUE`,
+		ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve combo tricks: %w", err)
+	}
+
+	idsByIdent := make(map[string]int, len(ids))
+	var ident string
+	var id int
+	_, err = pgx.ForEachRow(rows, []any{&ident, &id}, func() error {
+		idsByIdent[ident] = id
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve combo tricks: %w", err)
+	}
+	return idsByIdent, nil
+}
+
+// insertComboTricks inserts each trick at its array position (1-indexed)
+func insertComboTricks(ctx context.Context, tx pgx.Tx, comboID int64, tricks []ComboTrick) error {
+	// Positions come from array order, so they are always 1..n with no gaps
+	trickIDs := make([]int, len(tricks))
+	positions := make([]int, len(tricks))
+	notes := make([]*string, len(tricks))
+	for i, trick := range tricks {
+		trickIDs[i] = trick.TrickID
+		positions[i] = i + 1 // Position is 1-indexed
+		notes[i] = trick.Note
+	}
+
+	// UNNEST turns the parallel arrays back into rows - one INSERT for the whole list
+	_, err := tx.Exec(ctx,
+		`INSERT INTO combo_tricks (combo_id, trick_id, position, note)
+		 SELECT $1, t.trick_id, t.position, t.note
+		 FROM UNNEST($2::INTEGER[], $3::INTEGER[], $4::TEXT[]) AS t(trick_id, position, note)`,
+		comboID, trickIDs, positions, notes,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert combo tricks: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// Flips have no table of their own: tricks.flip_id references trick_data.categories,
+// so the flip/rotation families are the category rows. This repository reads them
+// the way trick details use them - with how many live tricks sit in each.
+
+// FlipRepositoryInterface defines the contract for flip data operations
+type FlipRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]models.Flip, error)
+}
+
+// FlipRepository implements FlipRepositoryInterface
+type FlipRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewFlipRepository creates a new FlipRepository instance
+func NewFlipRepository(pool *pgxpool.Pool) *FlipRepository {
+	return &FlipRepository{pool: pool}
+}
+
+// FindAll retrieves every flip family with its live-trick count
+// Families no trick uses yet are listed too (trick_count 0)
+func (r *FlipRepository) FindAll(ctx context.Context) ([]models.Flip, error) {
+	query := `
+		SELECT c.id, c.name, c.parent_id, COUNT(t.id) AS trick_count
+		FROM trick_data.categories c
+		LEFT JOIN trick_data.tricks t ON t.flip_id = c.id AND t.deleted_at IS NULL AND t.status = 'approved'
+		GROUP BY c.id, c.name, c.parent_id
+		ORDER BY c.name ASC
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query flips: %w", err)
+	}
+
+	flips, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Flip])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect flip rows: %w", err)
+	}
+
+	return flips, nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// TABLE STRUCTURE (need to create this):
+//
+// CREATE TABLE trick_data.trick_mistakes (
+//     id         BIGSERIAL PRIMARY KEY,
+//     trick_id   INTEGER NOT NULL REFERENCES trick_data.tricks (id) ON DELETE CASCADE,
+//     text       TEXT NOT NULL,
+//     severity   TEXT NOT NULL,               -- minor, major, critical (checked by the service)
+//     position   INTEGER NOT NULL,
+//     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+// );
+// CREATE INDEX trick_mistakes_trick_id_position ON trick_data.trick_mistakes (trick_id, position);
+// =============================================================================
+
+// ErrMistakeOrderMismatch is returned when a reorder doesn't list exactly the trick's mistakes
+var ErrMistakeOrderMismatch = errors.New("mistake order must list every mistake of the trick exactly once")
+
+// MistakeRepositoryInterface defines the contract for common-mistake data operations
+type MistakeRepositoryInterface interface {
+	FindByTrickSlug(ctx context.Context, trickSlug string) ([]models.TrickMistake, error)
+	Create(ctx context.Context, trickSlug string, mistake *models.TrickMistake) error
+	Update(ctx context.Context, trickSlug string, mistake *models.TrickMistake) error
+	Delete(ctx context.Context, trickSlug string, id int64) error
+	Reorder(ctx context.Context, trickSlug string, ids []int64) error
+}
+
+// touchTrickQuery bumps a live trick's updated_at and returns its ID
+// Every mistake write runs it: mistakes are part of the trick's dictionary,
+// whose ETag comes from updated_at. It also locks the trick row until commit.
+const touchTrickQuery = `UPDATE trick_data.tricks SET updated_at = NOW()
+	WHERE slug = $1 AND deleted_at IS NULL
+	RETURNING id`
+
+// MistakeRepository implements MistakeRepositoryInterface
+type MistakeRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewMistakeRepository creates a new MistakeRepository instance
+func NewMistakeRepository(pool *pgxpool.Pool) *MistakeRepository {
+	return &MistakeRepository{pool: pool}
+}
+
+// FindByTrickSlug returns a live trick's mistakes in display order
+// An unknown trick simply has no mistakes
+func (r *MistakeRepository) FindByTrickSlug(ctx context.Context, trickSlug string) ([]models.TrickMistake, error) {
+	query := `
+		SELECT m.id, m.trick_id, m.text, m.severity, m.position, m.created_at, m.updated_at
+		FROM trick_data.trick_mistakes m
+		JOIN trick_data.tricks t ON t.id = m.trick_id
+		WHERE t.slug = $1 AND t.deleted_at IS NULL AND t.status = 'approved'
+		ORDER BY m.position, m.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, trickSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mistakes for trick %s: %w", trickSlug, err)
+	}
+
+	mistakes, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickMistake])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect mistake rows: %w", err)
+	}
+	return mistakes, nil
+}
+
+// Create appends a mistake to a live trick's list, filling in ID, TrickID, Position and timestamps
+// Returns ErrNotFound if the trick doesn't exist or is deleted
+func (r *MistakeRepository) Create(ctx context.Context, trickSlug string, mistake *models.TrickMistake) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Touching the trick locks its row (so concurrent appends never share a position)
+	// and bumps updated_at, which the trick's ETags are derived from
+	err = tx.QueryRow(ctx, touchTrickQuery, trickSlug).Scan(&mistake.TrickID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to get trick %s: %w", trickSlug, err)
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO trick_data.trick_mistakes (trick_id, text, severity, position)
+		 SELECT $1, $2, $3, COALESCE(MAX(position), 0) + 1
+		 FROM trick_data.trick_mistakes WHERE trick_id = $1
+		 RETURNING id, position, created_at, updated_at`,
+		mistake.TrickID, mistake.Text, mistake.Severity,
+	).Scan(&mistake.ID, &mistake.Position, &mistake.CreatedAt, &mistake.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert mistake: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a mistake's text and severity, filling in the stored fields
+// Returns ErrNotFound unless the mistake belongs to the given live trick
+func (r *MistakeRepository) Update(ctx context.Context, trickSlug string, mistake *models.TrickMistake) error {
+	err := r.pool.QueryRow(ctx,
+		`WITH t AS (`+touchTrickQuery+`)
+		 UPDATE trick_data.trick_mistakes m
+		 SET text = $3, severity = $4, updated_at = NOW()
+		 FROM t
+		 WHERE m.id = $2 AND m.trick_id = t.id
+		 RETURNING m.trick_id, m.position, m.created_at, m.updated_at`,
+		trickSlug, mistake.ID, mistake.Text, mistake.Severity,
+	).Scan(&mistake.TrickID, &mistake.Position, &mistake.CreatedAt, &mistake.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to update mistake %d: %w", mistake.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a mistake from a live trick
+// Positions of the remaining mistakes keep their gaps - only their order matters
+func (r *MistakeRepository) Delete(ctx context.Context, trickSlug string, id int64) error {
+	tag, err := r.pool.Exec(ctx,
+		`WITH t AS (`+touchTrickQuery+`)
+		 DELETE FROM trick_data.trick_mistakes m
+		 USING t
+		 WHERE m.id = $2 AND m.trick_id = t.id`,
+		trickSlug, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete mistake %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Reorder sets positions 1..n in the order of ids
+// Returns ErrNotFound for an unknown trick, ErrMistakeOrderMismatch unless ids
+// is exactly the trick's set of mistakes
+func (r *MistakeRepository) Reorder(ctx context.Context, trickSlug string, ids []int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var trickID int
+	err = tx.QueryRow(ctx, touchTrickQuery, trickSlug).Scan(&trickID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to get trick %s: %w", trickSlug, err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id FROM trick_data.trick_mistakes WHERE trick_id = $1`, trickID)
+	if err != nil {
+		return fmt.Errorf("failed to query mistakes of trick %s: %w", trickSlug, err)
+	}
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return fmt.Errorf("failed to collect mistake IDs: %w", err)
+	}
+
+	// ids must be a permutation of existing - same length, no repeats, nothing foreign
+	if len(ids) != len(existing) {
+		return ErrMistakeOrderMismatch
+	}
+	remaining := make(map[int64]bool, len(existing))
+	for _, id := range existing {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return ErrMistakeOrderMismatch
+		}
+		delete(remaining, id)
+	}
+
+	// WITH ORDINALITY numbers the array elements 1..n in order
+	_, err = tx.Exec(ctx,
+		`UPDATE trick_data.trick_mistakes m
+		 SET position = o.position, updated_at = NOW()
+		 FROM unnest($2::BIGINT[]) WITH ORDINALITY AS o(id, position)
+		 WHERE m.id = o.id AND m.trick_id = $1`,
+		trickID, ids,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reorder mistakes of trick %s: %w", trickSlug, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// TABLE STRUCTURE (need to create this):
+//
+// CREATE TABLE trick_data.trick_prerequisites (
+//     trick_id        INTEGER NOT NULL REFERENCES trick_data.tricks (id),
+//     prerequisite_id INTEGER NOT NULL REFERENCES trick_data.tricks (id),
+//     created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//     PRIMARY KEY (trick_id, prerequisite_id),
+//     CHECK (trick_id <> prerequisite_id)
+// );
+// CREATE INDEX trick_prerequisites_prerequisite_id ON trick_data.trick_prerequisites (prerequisite_id);
+//
+// A row reads "learn prerequisite_id before trick_id". The graph must stay
+// acyclic - Add checks that, the database doesn't.
+// =============================================================================
+
+// ErrUnknownPrerequisite is returned when the prerequisite trick doesn't exist
+var ErrUnknownPrerequisite = errors.New("prerequisite trick not found")
+
+// ErrPrerequisiteCycle is returned when a link would make a trick its own (transitive) prerequisite
+var ErrPrerequisiteCycle = errors.New("prerequisite would create a cycle")
+
+// prerequisiteLockKey serializes prerequisite writes (pg_advisory_xact_lock)
+// Two concurrent inserts could each pass the cycle check and together close a loop.
+const prerequisiteLockKey = 7_221_001
+
+// PrerequisiteRepositoryInterface defines the contract for trick prerequisite data operations
+type PrerequisiteRepositoryInterface interface {
+	FindPrerequisites(ctx context.Context, trickSlug string) ([]models.TrickSimpleResponse, error)
+	FindUnlocks(ctx context.Context, trickSlug string) ([]models.TrickSimpleResponse, error)
+	FindGraphEdges(ctx context.Context, slugs []string) ([]models.TrickGraphEdge, error)
+	Add(ctx context.Context, trickSlug, prerequisiteSlug string) (bool, error)
+	Remove(ctx context.Context, trickSlug, prerequisiteSlug string) error
+}
+
+// PrerequisiteRepository implements PrerequisiteRepositoryInterface
+type PrerequisiteRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPrerequisiteRepository creates a new PrerequisiteRepository instance
+func NewPrerequisiteRepository(pool *pgxpool.Pool) *PrerequisiteRepository {
+	return &PrerequisiteRepository{pool: pool}
+}
+
+// FindPrerequisites returns the live tricks to learn before this one (direct only), by name
+// Returns ErrNotFound if the trick itself doesn't exist
+func (r *PrerequisiteRepository) FindPrerequisites(ctx context.Context, trickSlug string) ([]models.TrickSimpleResponse, error) {
+	return r.findLinked(ctx, trickSlug, "trick_id", "prerequisite_id")
+}
+
+// FindUnlocks returns the live tricks that list this one as a direct prerequisite, by name
+// Returns ErrNotFound if the trick itself doesn't exist
+func (r *PrerequisiteRepository) FindUnlocks(ctx context.Context, trickSlug string) ([]models.TrickSimpleResponse, error) {
+	return r.findLinked(ctx, trickSlug, "prerequisite_id", "trick_id")
+}
+
+// FindGraphEdges returns the prerequisite links between the given tricks, both ends in slugs
+// Each edge points from the prerequisite to the trick it unlocks.
+func (r *PrerequisiteRepository) FindGraphEdges(ctx context.Context, slugs []string) ([]models.TrickGraphEdge, error) {
+	query := `
+		SELECT p.slug AS from_slug, t.slug AS to_slug, $2 AS relation
+		FROM trick_data.trick_prerequisites tp
+		JOIN trick_data.tricks t ON t.id = tp.trick_id
+		JOIN trick_data.tricks p ON p.id = tp.prerequisite_id
+		WHERE t.slug = ANY($1) AND p.slug = ANY($1)
+		ORDER BY p.slug, t.slug
+	`
+
+	rows, err := r.pool.Query(ctx, query, slugs, models.TrickRelationPrerequisite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prerequisite edges: %w", err)
+	}
+
+	edges, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickGraphEdge])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect prerequisite edges: %w", err)
+	}
+	return edges, nil
+}
+
+// findLinked walks one edge of the graph from a trick: from is the trick's side, to the other side
+// The LEFT JOINs keep one all-NULL row for a live trick with no links, so
+// "no links" and "no such trick" can be told apart in one query.
+func (r *PrerequisiteRepository) findLinked(ctx context.Context, trickSlug, from, to string) ([]models.TrickSimpleResponse, error) {
+	query := fmt.Sprintf(`
+		SELECT l.slug, l.name
+		FROM trick_data.tricks t
+		LEFT JOIN trick_data.trick_prerequisites tp ON tp.%[1]s = t.id
+		LEFT JOIN trick_data.tricks l ON l.id = tp.%[2]s AND l.deleted_at IS NULL AND l.status = 'approved'
+		WHERE t.slug = $1 AND t.deleted_at IS NULL AND t.status = 'approved'
+		ORDER BY l.name ASC, l.slug ASC
+	`, from, to)
+
+	rows, err := r.pool.Query(ctx, query, trickSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prerequisites of trick %s: %w", trickSlug, err)
+	}
+	defer rows.Close()
+
+	found := false
+	tricks := make([]models.TrickSimpleResponse, 0)
+	for rows.Next() {
+		found = true
+		var slug, name *string
+		if err := rows.Scan(&slug, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan prerequisite row: %w", err)
+		}
+		// NULL: no links at all, or the linked trick is deleted
+		if slug == nil {
+			continue
+		}
+		tricks = append(tricks, models.TrickSimpleResponse{ID: *slug, Name: *name})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read prerequisite rows: %w", err)
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	return tricks, nil
+}
+
+// Add records that prerequisiteSlug comes before trickSlug
+// Returns false if the link already existed. Errors: ErrNotFound (trick),
+// ErrUnknownPrerequisite, ErrPrerequisiteCycle (including a trick listing itself).
+// Bumps the trick's updated_at - prerequisites are part of its dictionary.
+func (r *PrerequisiteRepository) Add(ctx context.Context, trickSlug, prerequisiteSlug string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, prerequisiteLockKey); err != nil {
+		return false, fmt.Errorf("failed to lock prerequisites: %w", err)
+	}
+
+	var trickID int
+	err = tx.QueryRow(ctx, touchTrickQuery, trickSlug).Scan(&trickID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrNotFound
+		}
+		return false, fmt.Errorf("failed to get trick %s: %w", trickSlug, err)
+	}
+
+	var prerequisiteID int
+	err = tx.QueryRow(ctx,
+		`SELECT id FROM trick_data.tricks WHERE slug = $1 AND deleted_at IS NULL`,
+		prerequisiteSlug,
+	).Scan(&prerequisiteID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrUnknownPrerequisite
+		}
+		return false, fmt.Errorf("failed to get trick %s: %w", prerequisiteSlug, err)
+	}
+	if prerequisiteID == trickID {
+		return false, ErrPrerequisiteCycle
+	}
+
+	// A cycle would close if the trick is already somewhere among the prerequisite's
+	// own prerequisites. Deleted tricks' links count too - they come back on restore.
+	// UNION (not UNION ALL) stops the walk even if old data already has a loop.
+	var cycle bool
+	err = tx.QueryRow(ctx, `
+		WITH RECURSIVE chain (id) AS (
+			SELECT prerequisite_id FROM trick_data.trick_prerequisites WHERE trick_id = $1
+			UNION
+			SELECT tp.prerequisite_id
+			FROM trick_data.trick_prerequisites tp
+			JOIN chain ON tp.trick_id = chain.id
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE id = $2)`,
+		prerequisiteID, trickID,
+	).Scan(&cycle)
+	if err != nil {
+		return false, fmt.Errorf("failed to check prerequisites of trick %s: %w", prerequisiteSlug, err)
+	}
+	if cycle {
+		return false, ErrPrerequisiteCycle
+	}
+
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO trick_data.trick_prerequisites (trick_id, prerequisite_id) VALUES ($1, $2)
+		 ON CONFLICT DO NOTHING`,
+		trickID, prerequisiteID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to add prerequisite %s to trick %s: %w", prerequisiteSlug, trickSlug, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Remove deletes one prerequisite link
+// Returns ErrNotFound if the trick doesn't exist or doesn't have that prerequisite
+func (r *PrerequisiteRepository) Remove(ctx context.Context, trickSlug, prerequisiteSlug string) error {
+	tag, err := r.pool.Exec(ctx,
+		`WITH t AS (`+touchTrickQuery+`)
+		 DELETE FROM trick_data.trick_prerequisites tp
+		 USING t, trick_data.tricks p
+		 WHERE tp.trick_id = t.id AND tp.prerequisite_id = p.id AND p.slug = $2`,
+		trickSlug, prerequisiteSlug,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove prerequisite %s from trick %s: %w", prerequisiteSlug, trickSlug, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
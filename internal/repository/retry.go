@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"tricking-api/internal/metrics"
+)
+
+// =============================================================================
+// READ RETRIES
+// =============================================================================
+// A database failover closes every pooled connection at once. The next query
+// on each of them fails with "conn closed" (or similar) although a fresh
+// connection would work straight away - without a retry, each one is a 500.
+//
+// retryRead gives a READ-ONLY query one more try when:
+// - the error is connection-class (IsConnError), not a query or data error
+// - no row had been read yet, so the caller can't have acted on half a result
+// - the request's context is still live
+//
+// Writes are never retried here: the first attempt may have committed before
+// the connection dropped. There is no idempotency-key machinery to make a
+// second attempt safe, so write paths keep surfacing the error.
+
+// readRetries counts read queries retried after a connection error, by query name
+var readRetries = metrics.NewCounterVec(
+	"db_read_retries_total",
+	"Read queries retried once after a connection error, labelled by query",
+	"query",
+)
+
+// retryDelay and retryJitter space out the retry: 5-25ms, so a burst of
+// failed reads doesn't hit the new primary in lockstep
+const (
+	retryDelay  = 5 * time.Millisecond
+	retryJitter = 20 * time.Millisecond
+)
+
+// querier is the read half of *pgxpool.Pool that retryRead hands to queries
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// IsConnError reports whether err means the connection failed, not the query
+// True for errors pgx itself calls safe to retry (nothing reached the server),
+// failed connects, dropped sockets and the server's shutdown / connection
+// SQLSTATEs (class 08, 57P01-57P03). Context cancellation and deadlines are
+// never connection errors - the caller gave up.
+func IsConnError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "57P01", "57P02", "57P03": // admin_shutdown, crash_shutdown, cannot_connect_now
+			return true
+		}
+		return len(pgErr.Code) == 5 && pgErr.Code[:2] == "08" // connection_exception
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connectErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryRead runs read, and once more if it fails with a connection error
+// before any row was read (see READ RETRIES above). read must only query
+// through q - that's how rows read are tracked - and must have no side effects.
+// name labels db_read_retries_total.
+func retryRead[T any](ctx context.Context, pool querier, name string, read func(q querier) (T, error)) (T, error) {
+	q := &trackingQuerier{querier: pool}
+	result, err := read(q)
+	if err == nil || q.rowsRead || !IsConnError(err) || ctx.Err() != nil {
+		return result, err
+	}
+
+	readRetries.Inc(name)
+	timer := time.NewTimer(retryDelay + rand.N(retryJitter))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return result, err
+	case <-timer.C:
+	}
+
+	return read(&trackingQuerier{querier: pool})
+}
+
+// trackingQuerier notes whether any row came back from its queries
+// QueryRow needs no tracking: its one row is only read by a Scan that succeeds.
+type trackingQuerier struct {
+	querier
+	rowsRead bool
+}
+
+func (q *trackingQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := q.querier.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &trackedRows{Rows: rows, read: &q.rowsRead}, nil
+}
+
+// trackedRows sets *read once Next yields a row
+type trackedRows struct {
+	pgx.Rows
+	read *bool
+}
+
+func (r *trackedRows) Next() bool {
+	if r.Rows.Next() {
+		*r.read = true
+		return true
+	}
+	return false
+}
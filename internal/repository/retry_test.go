@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"tricking-api/internal/metrics"
+)
+
+// fakeRows yields n rows, then fails with err (if set)
+type fakeRows struct {
+	pgx.Rows
+
+	n   int
+	err error
+}
+
+func (r *fakeRows) Next() bool {
+	if r.n == 0 {
+		return false
+	}
+	r.n--
+	return true
+}
+
+func (r *fakeRows) Err() error { return r.err }
+func (r *fakeRows) Close()     {}
+
+// fakeQuerier answers each Query with the next of its results
+type fakeQuerier struct {
+	querier
+
+	results []fakeQueryResult
+	calls   int
+}
+
+type fakeQueryResult struct {
+	rows int   // Rows returned before failing
+	err  error // Query error when rows is 0, else the error after the rows
+}
+
+func (q *fakeQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	result := q.results[q.calls]
+	q.calls++
+	if result.rows == 0 && result.err != nil {
+		return nil, result.err
+	}
+	return &fakeRows{n: result.rows, err: result.err}, nil
+}
+
+// countRows is a read that counts the rows of one query
+func countRows(ctx context.Context) func(q querier) (int, error) {
+	return func(q querier) (int, error) {
+		rows, err := q.Query(ctx, "SELECT 1")
+		if err != nil {
+			return 0, err
+		}
+		defer rows.Close()
+
+		n := 0
+		for rows.Next() {
+			n++
+		}
+		return n, rows.Err()
+	}
+}
+
+// retryCount reads db_read_retries_total for one query from the metrics output
+func retryCount(t *testing.T, query string) uint64 {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := metrics.WriteText(&buf); err != nil {
+		t.Fatalf("metrics.WriteText() error = %v", err)
+	}
+	series := `db_read_retries_total{query="` + query + `"} `
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), series); ok {
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				t.Fatalf("bad value for %s: %q", series, value)
+			}
+			return n
+		}
+	}
+	return 0
+}
+
+func TestRetryRead(t *testing.T) {
+	connClosed := &pgconn.PgError{Code: "08006", Message: "connection failure"}
+	adminShutdown := &pgconn.PgError{Code: "57P01", Message: "terminating connection due to administrator command"}
+	uniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "tricks_lower_name"}
+
+	tests := []struct {
+		name        string
+		results     []fakeQueryResult
+		cancelled   bool // Context cancelled before the read
+		wantRows    int
+		wantErr     error
+		wantCalls   int
+		wantRetries uint64
+	}{
+		{name: "success", results: []fakeQueryResult{{rows: 3}}, wantRows: 3, wantCalls: 1},
+		{name: "dropped connection", results: []fakeQueryResult{{err: io.ErrUnexpectedEOF}, {rows: 3}}, wantRows: 3, wantCalls: 2, wantRetries: 1},
+		{name: "connection SQLSTATE", results: []fakeQueryResult{{err: connClosed}, {rows: 2}}, wantRows: 2, wantCalls: 2, wantRetries: 1},
+		{name: "server shutting down", results: []fakeQueryResult{{err: fmt.Errorf("failed to query: %w", adminShutdown)}, {rows: 1}}, wantRows: 1, wantCalls: 2, wantRetries: 1},
+		{name: "retried only once", results: []fakeQueryResult{{err: connClosed}, {err: connClosed}}, wantErr: connClosed, wantCalls: 2, wantRetries: 1},
+		{name: "failed after a row", results: []fakeQueryResult{{rows: 1, err: connClosed}, {rows: 3}}, wantRows: 1, wantErr: connClosed, wantCalls: 1},
+		{name: "caller gave up", results: []fakeQueryResult{{err: context.Canceled}, {rows: 3}}, wantErr: context.Canceled, wantCalls: 1},
+		{name: "cancelled context", results: []fakeQueryResult{{err: connClosed}, {rows: 3}}, cancelled: true, wantErr: connClosed, wantCalls: 1},
+		{name: "query error", results: []fakeQueryResult{{err: uniqueViolation}, {rows: 3}}, wantErr: uniqueViolation, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+			pool := &fakeQuerier{results: tt.results}
+			query := "test_" + strings.ReplaceAll(tt.name, " ", "_")
+			before := retryCount(t, query)
+
+			rows, err := retryRead(ctx, pool, query, countRows(ctx))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("retryRead() error = %v, want %v", err, tt.wantErr)
+			}
+			if rows != tt.wantRows {
+				t.Errorf("read %d rows, want %d", rows, tt.wantRows)
+			}
+			if pool.calls != tt.wantCalls {
+				t.Errorf("%d queries, want %d", pool.calls, tt.wantCalls)
+			}
+			if got := retryCount(t, query) - before; got != tt.wantRetries {
+				t.Errorf("db_read_retries_total went up by %d, want %d", got, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestIsConnError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: true},
+		{name: "wrapped EOF", err: fmt.Errorf("failed to query: %w", io.EOF), want: true},
+		{name: "connection exception", err: &pgconn.PgError{Code: "08006"}, want: true},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, want: true},
+		{name: "cannot connect now", err: &pgconn.PgError{Code: "57P03"}, want: true},
+		{name: "query cancelled", err: &pgconn.PgError{Code: "57014"}, want: false},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "context cancelled", err: context.Canceled, want: false},
+		{name: "deadline", err: fmt.Errorf("failed to query: %w", context.DeadlineExceeded), want: false},
+		{name: "no rows", err: pgx.ErrNoRows, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConnError(tt.err); got != tt.want {
+				t.Errorf("IsConnError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
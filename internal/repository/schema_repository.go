@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaRepositoryInterface defines the contract for inspecting the live database schema
+type SchemaRepositoryInterface interface {
+	FindIndexNames(ctx context.Context, schemas []string) (map[string]bool, error)
+	HasExtension(ctx context.Context, name string) (bool, error)
+}
+
+// SchemaRepository implements SchemaRepositoryInterface
+type SchemaRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSchemaRepository creates a new SchemaRepository instance
+func NewSchemaRepository(pool *pgxpool.Pool) *SchemaRepository {
+	return &SchemaRepository{pool: pool}
+}
+
+// FindIndexNames returns every index in the given schemas, keyed "schema.index_name"
+// Index names are unique per schema, so the table isn't needed for the key
+func (r *SchemaRepository) FindIndexNames(ctx context.Context, schemas []string) (map[string]bool, error) {
+	query := `
+		SELECT schemaname || '.' || indexname
+		FROM pg_indexes
+		WHERE schemaname = ANY($1)
+	`
+
+	rows, err := r.pool.Query(ctx, query, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect index names: %w", err)
+	}
+
+	found := make(map[string]bool, len(names))
+	for _, name := range names {
+		found[name] = true
+	}
+	return found, nil
+}
+
+// HasExtension reports whether the named extension (e.g. "pg_trgm") is installed
+// in this database - installed, not merely available to CREATE EXTENSION
+func (r *SchemaRepository) HasExtension(ctx context.Context, name string) (bool, error) {
+	var installed bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = $1)`, name).Scan(&installed)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up extension %s: %w", name, err)
+	}
+	return installed, nil
+}
@@ -0,0 +1,107 @@
+// =============================================================================
+// TABLE STRUCTURE (stances - need to create / migrate these):
+//
+// CREATE TABLE IF NOT EXISTS trick_data.stances (
+//     id SERIAL PRIMARY KEY,
+//     name TEXT NOT NULL
+// );
+//
+// -- Which leg a trick lands on when it lands in this stance
+// ALTER TABLE trick_data.stances
+//     ADD COLUMN leg TEXT NOT NULL DEFAULT 'both'
+//     CHECK (leg IN ('left', 'right', 'both'));
+//
+// -- Shown by GET /stances ("Lands on the kicking leg...")
+// ALTER TABLE trick_data.stances ADD COLUMN description TEXT;
+//
+// -- Bump on every edit - the catalog delta (GET /catalog/snapshot/delta) sends
+// -- stances changed since the client's version
+// ALTER TABLE trick_data.stances
+//     ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+// =============================================================================
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// stanceCacheTTL is how long stance metadata is served from memory
+// Stances are reference data that almost never change
+const stanceCacheTTL = 10 * time.Minute
+
+// StanceRepositoryInterface defines the contract for stance data operations
+type StanceRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]models.Stance, error)
+	GetByID(ctx context.Context, id int) (*models.Stance, error)
+}
+
+// StanceRepository implements StanceRepositoryInterface
+// Results are cached in memory, so combo generation doesn't query stances every time
+type StanceRepository struct {
+	pool *pgxpool.Pool
+
+	mu       sync.Mutex
+	cached   []models.Stance
+	loadedAt time.Time
+}
+
+// NewStanceRepository creates a new StanceRepository instance
+func NewStanceRepository(pool *pgxpool.Pool) *StanceRepository {
+	return &StanceRepository{pool: pool}
+}
+
+// FindAll retrieves every stance, from cache when it is fresh
+// The returned slice is shared - callers must not modify it
+func (r *StanceRepository) FindAll(ctx context.Context) ([]models.Stance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached != nil && time.Since(r.loadedAt) < stanceCacheTTL {
+		return r.cached, nil
+	}
+
+	query := `
+		SELECT id, name, description, leg
+		FROM trick_data.stances
+		ORDER BY id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stances: %w", err)
+	}
+
+	stances, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Stance])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect stance rows: %w", err)
+	}
+
+	r.cached = stances
+	r.loadedAt = time.Now()
+	return stances, nil
+}
+
+// GetByID returns one stance, from the same cache as FindAll
+// Returns ErrNotFound if no stance has that ID
+func (r *StanceRepository) GetByID(ctx context.Context, id int) (*models.Stance, error) {
+	stances, err := r.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stances {
+		if stances[i].ID == id {
+			stance := stances[i]
+			return &stance, nil
+		}
+	}
+	return nil, ErrNotFound
+}
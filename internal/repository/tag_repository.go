@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// TABLE STRUCTURE (need to create this):
+//
+// CREATE TABLE trick_data.tags (
+//     id         SERIAL PRIMARY KEY,
+//     name       TEXT NOT NULL UNIQUE,
+//     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+// );
+//
+// CREATE TABLE trick_data.trick_tags (
+//     trick_id INTEGER NOT NULL REFERENCES trick_data.tricks (id),
+//     tag_id   INTEGER NOT NULL REFERENCES trick_data.tags (id) ON DELETE CASCADE,
+//     PRIMARY KEY (trick_id, tag_id)
+// );
+// CREATE INDEX trick_tags_tag_id ON trick_data.trick_tags (tag_id);
+//
+// Names are stored normalized (services.NormalizeTag), so UNIQUE (name) is
+// enough to dedupe "Twisting" and "twisting".
+// =============================================================================
+
+// TagRepositoryInterface defines the contract for trick tag data operations
+type TagRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]models.TagResponse, error)
+	Create(ctx context.Context, name string) (bool, error)
+	Delete(ctx context.Context, name string) ([]string, error)
+	AddToTrick(ctx context.Context, trickSlug, name string) (bool, error)
+	RemoveFromTrick(ctx context.Context, trickSlug, name string) error
+}
+
+// TagRepository implements TagRepositoryInterface
+type TagRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTagRepository creates a new TagRepository instance
+func NewTagRepository(pool *pgxpool.Pool) *TagRepository {
+	return &TagRepository{pool: pool}
+}
+
+// FindAll returns every tag with how many live tricks carry it, by name
+// Unused tags are included with a count of 0.
+func (r *TagRepository) FindAll(ctx context.Context) ([]models.TagResponse, error) {
+	query := `
+		SELECT g.name, COUNT(t.id) AS trick_count
+		FROM trick_data.tags g
+		LEFT JOIN trick_data.trick_tags tt ON tt.tag_id = g.id
+		LEFT JOIN trick_data.tricks t ON t.id = tt.trick_id AND t.deleted_at IS NULL AND t.status = 'approved'
+		GROUP BY g.name
+		ORDER BY g.name ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+
+	tags, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.TagResponse])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect tag rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Create adds a tag; returns false if it already existed
+// name must already be normalized
+func (r *TagRepository) Create(ctx context.Context, name string) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`INSERT INTO trick_data.tags (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`,
+		name,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create tag %s: %w", name, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Delete removes a tag from every trick and then the tag itself
+// Returns the slugs of the tricks that carried it (their updated_at is bumped),
+// or ErrNotFound if there is no such tag.
+func (r *TagRepository) Delete(ctx context.Context, name string) ([]string, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		UPDATE trick_data.tricks t SET updated_at = NOW()
+		FROM trick_data.trick_tags tt
+		JOIN trick_data.tags g ON g.id = tt.tag_id
+		WHERE tt.trick_id = t.id AND g.name = $1
+		RETURNING t.slug`,
+		name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to touch tricks tagged %s: %w", name, err)
+	}
+	slugs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect tagged trick slugs: %w", err)
+	}
+
+	// ON DELETE CASCADE clears trick_tags
+	tag, err := tx.Exec(ctx, `DELETE FROM trick_data.tags WHERE name = $1`, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete tag %s: %w", name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return slugs, nil
+}
+
+// AddToTrick tags a trick, creating the tag first if it's new
+// Returns false if the trick already had the tag, ErrNotFound if the trick doesn't exist.
+// Bumps the trick's updated_at - tags are part of its detail response.
+func (r *TagRepository) AddToTrick(ctx context.Context, trickSlug, name string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var trickID int
+	err = tx.QueryRow(ctx, touchTrickQuery, trickSlug).Scan(&trickID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrNotFound
+		}
+		return false, fmt.Errorf("failed to get trick %s: %w", trickSlug, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO trick_data.tags (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`,
+		name,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create tag %s: %w", name, err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO trick_data.trick_tags (trick_id, tag_id)
+		SELECT $1, id FROM trick_data.tags WHERE name = $2
+		ON CONFLICT DO NOTHING`,
+		trickID, name,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to tag trick %s with %s: %w", trickSlug, name, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RemoveFromTrick takes a tag off a trick (the tag itself stays)
+// Returns ErrNotFound if the trick doesn't exist or doesn't have that tag
+func (r *TagRepository) RemoveFromTrick(ctx context.Context, trickSlug, name string) error {
+	tag, err := r.pool.Exec(ctx,
+		`WITH t AS (`+touchTrickQuery+`)
+		 DELETE FROM trick_data.trick_tags tt
+		 USING t, trick_data.tags g
+		 WHERE tt.trick_id = t.id AND tt.tag_id = g.id AND g.name = $2`,
+		trickSlug, name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove tag %s from trick %s: %w", name, trickSlug, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
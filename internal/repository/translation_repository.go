@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// TABLE STRUCTURE (need to create this):
+//
+// CREATE TABLE trick_data.trick_translations (
+//     trick_id        INTEGER NOT NULL REFERENCES trick_data.tricks (id) ON DELETE CASCADE,
+//     locale          TEXT NOT NULL,   -- Primary language tag: "es", "fr"
+//     name            TEXT,            -- NULL = use the trick's own (English) value
+//     description     TEXT,
+//     execution_notes TEXT,
+//     updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//     PRIMARY KEY (trick_id, locale)
+// );
+//
+// A translation may be partial - any NULL field falls back to the base row.
+// =============================================================================
+
+// TranslationRepositoryInterface defines the contract for trick translation data operations
+type TranslationRepositoryInterface interface {
+	Find(ctx context.Context, trickSlug, locale string) (*models.TrickTranslation, error)
+	FindByTrickSlug(ctx context.Context, trickSlug string) ([]models.TrickTranslation, error)
+	Upsert(ctx context.Context, trickSlug string, translation *models.TrickTranslation) error
+	Delete(ctx context.Context, trickSlug, locale string) error
+}
+
+// TranslationRepository implements TranslationRepositoryInterface
+type TranslationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTranslationRepository creates a new TranslationRepository instance
+func NewTranslationRepository(pool *pgxpool.Pool) *TranslationRepository {
+	return &TranslationRepository{pool: pool}
+}
+
+// Find returns a live trick's translation into one locale
+// Returns ErrNotFound if the trick has none for that locale
+func (r *TranslationRepository) Find(ctx context.Context, trickSlug, locale string) (*models.TrickTranslation, error) {
+	var translation models.TrickTranslation
+	err := r.pool.QueryRow(ctx, `
+		SELECT tr.locale, tr.name, tr.description, tr.execution_notes, tr.updated_at
+		FROM trick_data.trick_translations tr
+		JOIN trick_data.tricks t ON t.id = tr.trick_id
+		WHERE t.slug = $1 AND t.deleted_at IS NULL AND tr.locale = $2`,
+		trickSlug, locale,
+	).Scan(&translation.Locale, &translation.Name, &translation.Description, &translation.ExecutionNotes, &translation.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get %s translation of trick %s: %w", locale, trickSlug, err)
+	}
+	return &translation, nil
+}
+
+// FindByTrickSlug returns every translation of a live trick, by locale
+// An unknown trick simply has no translations
+func (r *TranslationRepository) FindByTrickSlug(ctx context.Context, trickSlug string) ([]models.TrickTranslation, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT tr.locale, tr.name, tr.description, tr.execution_notes, tr.updated_at
+		FROM trick_data.trick_translations tr
+		JOIN trick_data.tricks t ON t.id = tr.trick_id
+		WHERE t.slug = $1 AND t.deleted_at IS NULL
+		ORDER BY tr.locale`,
+		trickSlug,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query translations of trick %s: %w", trickSlug, err)
+	}
+
+	translations, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickTranslation])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect translation rows: %w", err)
+	}
+	return translations, nil
+}
+
+// Upsert creates or replaces a trick's translation into translation.Locale
+// Fields left nil are stored as NULL (so they fall back to the base row), even
+// if an earlier version had them. Sets translation.UpdatedAt.
+// Returns ErrNotFound if the trick doesn't exist. Bumps the trick's updated_at -
+// translations are part of its detail responses.
+func (r *TranslationRepository) Upsert(ctx context.Context, trickSlug string, translation *models.TrickTranslation) error {
+	err := r.pool.QueryRow(ctx,
+		`WITH t AS (`+touchTrickQuery+`)
+		 INSERT INTO trick_data.trick_translations (trick_id, locale, name, description, execution_notes)
+		 SELECT t.id, $2, $3, $4, $5 FROM t
+		 ON CONFLICT (trick_id, locale) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			execution_notes = EXCLUDED.execution_notes,
+			updated_at = NOW()
+		 RETURNING updated_at`,
+		trickSlug, translation.Locale, translation.Name, translation.Description, translation.ExecutionNotes,
+	).Scan(&translation.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to save %s translation of trick %s: %w", translation.Locale, trickSlug, err)
+	}
+	return nil
+}
+
+// Delete removes a trick's translation into one locale
+// Returns ErrNotFound if there was none (or the trick doesn't exist)
+func (r *TranslationRepository) Delete(ctx context.Context, trickSlug, locale string) error {
+	tag, err := r.pool.Exec(ctx,
+		`WITH t AS (`+touchTrickQuery+`)
+		 DELETE FROM trick_data.trick_translations tr
+		 USING t
+		 WHERE tr.trick_id = t.id AND tr.locale = $2`,
+		trickSlug, locale,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s translation of trick %s: %w", locale, trickSlug, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
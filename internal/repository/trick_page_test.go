@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"tricking-api/internal/database"
+)
+
+// videoFlagsSchema is the part of trick_data that FindPage reads, nothing more
+const videoFlagsSchema = `
+	CREATE SCHEMA trick_data;
+	CREATE TABLE trick_data.tricks (
+		id SERIAL PRIMARY KEY,
+		slug TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'approved',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT '2024-01-01',
+		deleted_at TIMESTAMPTZ
+	);
+	CREATE TABLE trick_data.trick_videos (
+		id BIGSERIAL PRIMARY KEY,
+		trick_id INTEGER NOT NULL REFERENCES trick_data.tricks (id),
+		is_featured BOOLEAN NOT NULL DEFAULT FALSE,
+		availability TEXT NOT NULL DEFAULT 'unknown'
+	);
+`
+
+// videoFlagsFixture has one trick per combination of video states
+const videoFlagsFixture = `
+	INSERT INTO trick_data.tricks (slug, name, status) VALUES
+		('aerial', 'Aerial', 'approved'),
+		('backflip', 'Backflip', 'approved'),
+		('cork', 'Cork', 'approved'),
+		('gainer', 'Gainer', 'approved'),
+		('raiz', 'Raiz', 'approved'),
+		('webster', 'Webster', 'approved'),
+		('zz-pending', 'Zz Pending', 'pending');
+	INSERT INTO trick_data.trick_videos (trick_id, is_featured, availability)
+	SELECT t.id, v.is_featured, v.availability
+	FROM (VALUES
+		('backflip', FALSE, 'available'),
+		('cork', TRUE, 'available'),
+		('cork', FALSE, 'unavailable'),
+		('gainer', TRUE, 'unknown'),
+		('raiz', TRUE, 'unavailable'),
+		('raiz', FALSE, 'unavailable'),
+		('webster', TRUE, 'unavailable'),
+		('webster', FALSE, 'available'),
+		('zz-pending', TRUE, 'available')
+	) AS v (slug, is_featured, availability)
+	JOIN trick_data.tricks t ON t.slug = v.slug;
+`
+
+// newScratchRepo connects to TEST_DATABASE_URL - a scratch database the test
+// owns - and creates schema, then fixture. The test fails rather than touch an
+// existing trick_data schema, and drops the schema again when it's done.
+func newScratchRepo(t *testing.T, schema, fixture string) *TrickRepository {
+	t.Helper()
+
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := database.NewPool(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if _, err := pool.Exec(ctx, schema); err != nil {
+		t.Fatalf("create schema (the database must not have trick_data yet): %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := pool.Exec(context.Background(), `DROP SCHEMA trick_data CASCADE`); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+	if _, err := pool.Exec(ctx, fixture); err != nil {
+		t.Fatalf("insert fixture: %v", err)
+	}
+	return NewTrickRepository(pool)
+}
+
+func TestFindPageVideoFlags(t *testing.T) {
+	repo := newScratchRepo(t, videoFlagsSchema, videoFlagsFixture)
+
+	type flags struct {
+		hasVideo, hasFeatured bool
+		count                 int64
+	}
+	want := map[string]flags{
+		"aerial":   {false, false, 0}, // No videos
+		"backflip": {true, false, 1},  // Available, not featured
+		"cork":     {true, true, 1},   // Featured and available; the unavailable one doesn't count
+		"gainer":   {true, true, 1},   // Not checked yet - counts until the checker says otherwise
+		"raiz":     {false, false, 0}, // Every video unavailable
+		"webster":  {true, false, 1},  // Featured video unavailable, another one available
+	}
+
+	tricks, err := repo.FindPage(context.Background(), nil, 50, true, true, time.Now())
+	if err != nil {
+		t.Fatalf("FindPage() error = %v", err)
+	}
+	if len(tricks) != len(want) {
+		t.Fatalf("got %d tricks, want %d (pending tricks are not listed)", len(tricks), len(want))
+	}
+	for _, trick := range tricks {
+		w, ok := want[trick.ID]
+		if !ok {
+			t.Errorf("unexpected trick %s", trick.ID)
+			continue
+		}
+		if trick.HasVideo == nil || trick.HasFeaturedVideo == nil || trick.VideoCount == nil {
+			t.Fatalf("%s: flags not set: %+v", trick.ID, trick)
+		}
+		got := flags{*trick.HasVideo, *trick.HasFeaturedVideo, *trick.VideoCount}
+		if got != w {
+			t.Errorf("%s: has_video, has_featured_video, video_count = %v, want %v", trick.ID, got, w)
+		}
+	}
+
+	// Not asked for: no flags at all
+	tricks, err = repo.FindPage(context.Background(), nil, 50, false, false, time.Now())
+	if err != nil {
+		t.Fatalf("FindPage() error = %v", err)
+	}
+	for _, trick := range tricks {
+		if trick.HasVideo != nil || trick.HasFeaturedVideo != nil || trick.VideoCount != nil {
+			t.Errorf("%s: flags set without include=video_flags: %+v", trick.ID, trick)
+		}
+	}
+}
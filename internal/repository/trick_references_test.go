@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// trickWriteSchema is the part of trick_data that trick writes read and write,
+// with the indexes the name and slug checks rely on
+const trickWriteSchema = `
+	CREATE SCHEMA trick_data;
+	CREATE TABLE trick_data.stances (id SERIAL PRIMARY KEY);
+	CREATE TABLE trick_data.categories (id SERIAL PRIMARY KEY);
+	CREATE TABLE trick_data.tricks (
+		id SERIAL PRIMARY KEY,
+		slug TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		description TEXT,
+		difficulty BIGINT,
+		execution_notes TEXT,
+		takeoff_stance_id INTEGER,
+		landing_stance_id INTEGER,
+		flip_id INTEGER,
+		rotation INTEGER,
+		weight SMALLINT NOT NULL DEFAULT 100,
+		attribution TEXT,
+		license TEXT,
+		status TEXT NOT NULL DEFAULT 'approved',
+		created_by UUID,
+		approved_by UUID,
+		rejection_reason TEXT,
+		reviewed_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT '2024-01-01',
+		updated_at TIMESTAMPTZ,
+		deleted_at TIMESTAMPTZ
+	);
+	CREATE UNIQUE INDEX tricks_lower_slug ON trick_data.tricks (lower(slug));
+	CREATE UNIQUE INDEX tricks_lower_name ON trick_data.tricks (lower(name)) WHERE status <> 'rejected';
+	CREATE TABLE trick_data.trick_aliases (
+		id SERIAL PRIMARY KEY,
+		trick_id INTEGER NOT NULL REFERENCES trick_data.tricks (id),
+		alias TEXT NOT NULL,
+		slug TEXT NOT NULL UNIQUE,
+		former_name BOOLEAN NOT NULL DEFAULT FALSE
+	);
+	CREATE TABLE trick_data.trick_revisions (
+		id BIGSERIAL PRIMARY KEY,
+		trick_id INTEGER NOT NULL,
+		changed_fields TEXT[] NOT NULL,
+		changed_by UUID
+	);
+	CREATE TABLE trick_data.trick_audit (
+		id BIGSERIAL PRIMARY KEY,
+		trick_id INTEGER NOT NULL,
+		trick_slug TEXT NOT NULL,
+		actor_id UUID,
+		action TEXT NOT NULL,
+		diff JSONB NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+`
+
+// brokenReferencesFixture has stance 1 and category 1; 99 is dangling everywhere
+const brokenReferencesFixture = `
+	INSERT INTO trick_data.stances (id) VALUES (1);
+	INSERT INTO trick_data.categories (id) VALUES (1);
+	INSERT INTO trick_data.tricks (slug, name, takeoff_stance_id, landing_stance_id, flip_id, deleted_at) VALUES
+		('backflip', 'Backflip', 1, 99, 1, NULL),
+		('cork', 'Cork', 99, 1, 99, NULL),
+		('gainer', 'Gainer', 1, 1, 1, NULL),
+		('raiz', 'Raiz', 1, 1, 99, '2024-06-01');
+`
+
+func TestClearBrokenReferencesAudits(t *testing.T) {
+	repo := newScratchRepo(t, trickWriteSchema, brokenReferencesFixture)
+	ctx := context.Background()
+	admin := uuid.New()
+
+	cleared := map[string][]string{}
+	for _, ref := range TrickReferences {
+		slugs, err := repo.ClearBrokenReferences(ctx, ref.Field, 10, &admin)
+		if err != nil {
+			t.Fatalf("ClearBrokenReferences(%s) error = %v", ref.Field, err)
+		}
+		cleared[ref.Field] = slugs
+	}
+
+	want := map[string][]string{
+		"takeoff_stance_id": {"cork"},
+		"landing_stance_id": {"backflip"},
+		"flip_id":           {"cork"}, // raiz is deleted - left alone
+	}
+	for field, slugs := range want {
+		if !slices.Equal(cleared[field], slugs) {
+			t.Errorf("%s cleared %v, want %v", field, cleared[field], slugs)
+		}
+	}
+
+	// One audit entry per trick and field, by the admin, old value to null
+	rows, err := repo.pool.Query(ctx, `
+		SELECT trick_slug, actor_id, action, diff
+		FROM trick_data.trick_audit ORDER BY id`)
+	if err != nil {
+		t.Fatalf("read audit: %v", err)
+	}
+	defer rows.Close()
+
+	type entry struct {
+		slug, field string
+	}
+	var got []entry
+	for rows.Next() {
+		var (
+			slug, action string
+			actor        uuid.UUID
+			diff         map[string]struct{ Old, New json.RawMessage }
+		)
+		if err := rows.Scan(&slug, &actor, &action, &diff); err != nil {
+			t.Fatalf("scan audit: %v", err)
+		}
+		if actor != admin || action != TrickAuditUpdate {
+			t.Errorf("%s: actor %s, action %s, want %s, %s", slug, actor, action, admin, TrickAuditUpdate)
+		}
+		for field, change := range diff {
+			if string(change.Old) != "99" || string(change.New) != "null" {
+				t.Errorf("%s %s: %s -> %s, want 99 -> null", slug, field, change.Old, change.New)
+			}
+			got = append(got, entry{slug, field})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("read audit: %v", err)
+	}
+	wantEntries := []entry{{"cork", "takeoff_stance_id"}, {"backflip", "landing_stance_id"}, {"cork", "flip_id"}}
+	if !slices.Equal(got, wantEntries) {
+		t.Errorf("audited %v, want %v", got, wantEntries)
+	}
+}
@@ -1,11 +1,18 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"tricking-api/internal/models"
@@ -20,6 +27,30 @@ import (
 // ErrNotFound indicates the requested resource doesn't exist
 var ErrNotFound = errors.New("resource not found")
 
+// ErrNotDeleted indicates a restore was attempted on a trick that isn't deleted
+var ErrNotDeleted = errors.New("trick is not deleted")
+
+// ErrPurgeWindowClosed indicates a deleted trick is past its purge date and can't be restored
+var ErrPurgeWindowClosed = errors.New("trick is past its purge date")
+
+// ErrNoVotes indicates a trick has no community difficulty votes
+var ErrNoVotes = errors.New("trick has no community difficulty votes")
+
+// ErrNameTaken indicates a new trick name matches an existing trick name or alias
+// Rejected submissions don't count (see Review). The check runs under the alias
+// lock; behind it, a unique index on lower(name) turns a write that got past it
+// anyway into ErrNameTaken too:
+//
+//	CREATE UNIQUE INDEX tricks_lower_name ON trick_data.tricks (lower(name))
+//	    WHERE status <> 'rejected';
+var ErrNameTaken = errors.New("trick name is already a trick name or alias")
+
+// ErrSlugTaken indicates a trick slug that's already another trick's or an alias's slug
+var ErrSlugTaken = errors.New("trick slug is already a trick or alias slug")
+
+// ErrUnknownReference indicates a trick write names a stance or category that doesn't exist
+var ErrUnknownReference = errors.New("trick references a stance or category that doesn't exist")
+
 // =============================================================================
 // INTERFACE DEFINITION
 // =============================================================================
@@ -30,23 +61,86 @@ var ErrNotFound = errors.New("resource not found")
 // For repositories, "Interface" suffix is common for clarity
 type TrickRepositoryInterface interface {
 	GetByID(ctx context.Context, id string) (*models.Trick, error)
+	ResolveNumericID(ctx context.Context, id string, legacyID int64) (string, error)
 	GetByIDWithTimestamp(ctx context.Context, id string) (*models.Trick, error)
+	GetBySlugs(ctx context.Context, slugs []string) ([]models.Trick, error)
+	GetByIdentifiers(ctx context.Context, ids []string) (map[string]models.Trick, error)
 	FindAll(ctx context.Context) ([]models.Trick, error)
-	FindSimpleList(ctx context.Context) ([]models.TrickSimpleResponse, error)
+	ExistsByName(ctx context.Context, name, exceptSlug string) (bool, error)
+	ExistsBySlug(ctx context.Context, slug, exceptSlug string) (bool, error)
+	ForEachTrick(ctx context.Context, fn func(*models.Trick) error) error
+	FindSimpleList(ctx context.Context, newSince time.Time) ([]models.TrickSimpleResponse, error)
+	FindPage(ctx context.Context, after *TrickPageKey, limit int, withVideoFlags, withVideoCounts bool, newSince time.Time) ([]models.TrickSimpleResponse, error)
+	FindCreatedSince(ctx context.Context, since time.Time, limit int) ([]models.NewTrickResponse, error)
+	FindSlugs(ctx context.Context) ([]models.TrickSlugResponse, error)
+	GetStats(ctx context.Context, addedSince time.Time) (*models.TrickStatsResponse, error)
+	DifficultyHistogram(ctx context.Context, filters TrickFilters) ([]models.DifficultyBucket, error)
+	FindGraphNodes(ctx context.Context, filters TrickFilters, limit int) ([]models.TrickGraphNode, error)
+	Search(ctx context.Context, query string, limit int, minSimilarity float64) ([]models.Trick, error)
+	FindByNamePrefix(ctx context.Context, prefix string, limit int) ([]models.TrickAutocompleteResponse, error)
+	FindDifficultyCalibration(ctx context.Context, minVotes int, minDelta float64, limit, offset int) ([]models.DifficultyCalibration, int64, error)
+	AdoptCommunityDifficulty(ctx context.Context, id string, changedBy *uuid.UUID) (*models.DifficultyChangeResponse, error)
 	FindByFilters(ctx context.Context, filters TrickFilters) ([]models.Trick, error)
+	StreamExport(ctx context.Context, filters TrickFilters, fn func(models.TrickExportRow) error) error
 	GetLastModified(ctx context.Context) (int64, error)
 	GetLastModifiedByID(ctx context.Context, id string) (int64, error)
+	FindChangesSince(ctx context.Context, since time.Time, withTricks, withReferenceData bool) (*TrickChanges, error)
+	IncrementViewCounts(ctx context.Context, counts map[string]int64) error
+	FindPopular(ctx context.Context, since time.Time, limit int) ([]models.PopularTrickResponse, error)
+	FindTextFields(ctx context.Context) ([]models.Trick, error)
+	UpdateTextFields(ctx context.Context, trick *models.Trick) error
+	SoftDelete(ctx context.Context, id string, purgeAfter time.Duration, changedBy *uuid.UUID) (*models.TrickDeletionResponse, error)
+	Merge(ctx context.Context, keepSlug string, merge TrickMerge, purgeAfter time.Duration, changedBy *uuid.UUID) (*models.TrickMergeResponse, error)
+	Restore(ctx context.Context, id string, changedBy *uuid.UUID) error
+	FindPendingPurge(ctx context.Context) ([]models.PendingPurge, error)
+	PurgeExpired(ctx context.Context, batchSize int) (int, error)
+	ApplyWeightDecay(ctx context.Context, staleBefore time.Time, modifier float64) (int64, error)
+	SetWeights(ctx context.Context, weights map[string]int16, changedBy *uuid.UUID) (int, []string, error)
+	UpsertImported(ctx context.Context, tricks []ImportedTrick, changedBy *uuid.UUID) ([]ImportOutcome, error)
+	Insert(ctx context.Context, trick *models.Trick, status string, changedBy *uuid.UUID) error
+	InsertBatch(ctx context.Context, tricks []*models.Trick, changedBy *uuid.UUID, commit bool) ([]error, error)
+	Update(ctx context.Context, slug string, update TrickUpdate, changedBy *uuid.UUID) (*models.Trick, error)
+	Patch(ctx context.Context, slug string, patch TrickPatch, changedBy *uuid.UUID) (*models.Trick, error)
+	FindBrokenReferences(ctx context.Context) ([]models.BrokenReference, error)
+	ClearBrokenReferences(ctx context.Context, field string, batchSize int, changedBy *uuid.UUID) ([]string, error)
+	FindHistory(ctx context.Context, slug string, limit, offset int) ([]models.TrickAuditEntry, int64, error)
+	FindPending(ctx context.Context, limit, offset int) ([]models.TrickSubmission, int64, error)
+	FindSubmittedBy(ctx context.Context, userID uuid.UUID, limit int) ([]models.TrickSubmission, error)
+	Review(ctx context.Context, slug, status string, reason *string, reviewedBy *uuid.UUID) (*models.TrickSubmission, error)
 }
 
 // TrickFilters holds optional filters for querying tricks
 type TrickFilters struct {
 	MinDifficulty   *int64
 	MaxDifficulty   *int64
+	TakeoffStanceID *int
+	LandingStanceID *int
 	CategoryIDs     []int
 	ExcludeTrickIDs []int
+	ExcludeSlugs    []string
+	Tags            []string // Distinct tag names - a trick must carry all of them
 	Limit           *int
 }
 
+// TrickPageKey is the (name, slug) position of the last trick on a page
+// The next page starts strictly after it
+type TrickPageKey struct {
+	Name string
+	Slug string
+}
+
+// TrickChanges is everything that changed in the catalog from a point in time
+// ServerTime is the database clock when the changes were read. Categories and
+// Stances are only read when asked for (the catalog delta wants them), and
+// Tricks can be left out (the streamed snapshot reads them with ForEachTrick).
+type TrickChanges struct {
+	Tricks       []models.Trick
+	DeletedSlugs []string
+	Categories   []models.Category
+	Stances      []models.Stance
+	ServerTime   time.Time
+}
+
 // =============================================================================
 // REPOSITORY IMPLEMENTATION
 // =============================================================================
@@ -66,17 +160,38 @@ func NewTrickRepository(pool *pgxpool.Pool) *TrickRepository {
 
 // GetByID retrieves a single trick by its ID
 // Returns ErrNotFound if the trick doesn't exist
+// The slug is matched case-insensitively, so a pasted "Backflip" still finds the
+// trick even when a caller didn't lowercase it. Index:
+//
+//	CREATE UNIQUE INDEX tricks_lower_slug ON trick_data.tricks (lower(slug));
 func (r *TrickRepository) GetByID(ctx context.Context, id string) (*models.Trick, error) {
 	// SQL query to fetch a single trick
 	// $1 is a placeholder for the first parameter (prevents SQL injection)
 	// NEVER use fmt.Sprintf to build queries with user input!
 	query := `
 		SELECT 
-			slug as id, name, description, difficulty, execution_notes,
-			created_by, creator_name, created_at, updated_at,
-			takeoff_stance_id, landing_stance_id, flip_id, rotation, weight
+			tricks.slug as id, tricks.name, tricks.description, tricks.difficulty, tricks.execution_notes,
+			tricks.created_by, tricks.creator_name, tricks.created_at, tricks.updated_at,
+			tricks.takeoff_stance_id, tricks.landing_stance_id, tricks.flip_id, tricks.rotation, tricks.weight,
+			tricks.attribution, tricks.license,
+			ARRAY(
+				SELECT a.alias FROM trick_data.trick_aliases a
+				WHERE a.trick_id = tricks.id AND NOT a.former_name ORDER BY lower(a.alias)
+			) AS aliases,
+			ARRAY(
+				SELECT a.alias FROM trick_data.trick_aliases a
+				WHERE a.trick_id = tricks.id AND a.former_name ORDER BY a.created_at DESC, a.id DESC
+			) AS former_names,
+			ARRAY(
+				SELECT g.name FROM trick_data.trick_tags tt
+				JOIN trick_data.tags g ON g.id = tt.tag_id
+				WHERE tt.trick_id = tricks.id ORDER BY g.name
+			) AS tags,
+			flips.name AS flip_name
 		FROM trick_data.tricks
-		WHERE slug = $1
+		-- flip_id references categories; joined here so ?expand=flip costs no second query
+		LEFT JOIN trick_data.categories flips ON flips.id = tricks.flip_id
+		WHERE lower(tricks.slug) = lower($1) AND tricks.deleted_at IS NULL AND tricks.status = 'approved'
 	`
 
 	// Create an empty Trick to scan results into
@@ -84,22 +199,31 @@ func (r *TrickRepository) GetByID(ctx context.Context, id string) (*models.Trick
 
 	// QueryRow is used when expecting exactly one row
 	// Scan maps columns to struct fields in ORDER - must match SELECT order!
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&trick.ID, // actually "slug" in DB, mapped to ID field
-		&trick.Name,
-		&trick.Description,
-		&trick.Difficulty,
-		&trick.ExecutionNotes,
-		&trick.CreatedBy, // Can be NULL, so we use *uuid.UUID
-		&trick.CreatorName,
-		&trick.CreatedAt,
-		&trick.UpdatedAt,
-		&trick.TakeoffStanceID, // Can be NULL, so we use *int
-		&trick.LandingStanceID,
-		&trick.FlipID,
-		&trick.Rotation,
-		&trick.Weight,
-	)
+	// retryRead tries again once if a failover dropped the connection (see retry.go)
+	_, err := retryRead(ctx, r.pool, "trick_by_id", func(q querier) (struct{}, error) {
+		return struct{}{}, q.QueryRow(ctx, query, id).Scan(
+			&trick.ID, // actually "slug" in DB, mapped to ID field
+			&trick.Name,
+			&trick.Description,
+			&trick.Difficulty,
+			&trick.ExecutionNotes,
+			&trick.CreatedBy, // Can be NULL, so we use *uuid.UUID
+			&trick.CreatorName,
+			&trick.CreatedAt,
+			&trick.UpdatedAt,
+			&trick.TakeoffStanceID, // Can be NULL, so we use *int
+			&trick.LandingStanceID,
+			&trick.FlipID,
+			&trick.Rotation,
+			&trick.Weight,
+			&trick.Attribution,
+			&trick.License,
+			&trick.Aliases,
+			&trick.FormerNames,
+			&trick.Tags,
+			&trick.FlipName,
+		)
+	})
 	if err != nil {
 		// Check if it's a "no rows" error
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -109,22 +233,148 @@ func (r *TrickRepository) GetByID(ctx context.Context, id string) (*models.Trick
 		// Wrap other errors with context
 		return nil, fmt.Errorf("failed to get trick by ID %s: %w", id, err)
 	}
+	trick.Slug = trick.ID
 
 	return &trick, nil
 }
 
-// FindAll retrieves all tricks from the database
-func (r *TrickRepository) FindAll(ctx context.Context) ([]models.Trick, error) {
+// GetByIdentifiers is the batch form of GetBySlugs plus ResolveNumericID: one query
+// for a list of slugs and/or legacy numeric IDs, keyed by the identifier as given.
+// Only the fields combo scoring needs are loaded (slug, name, difficulty, stances).
+// Unknown identifiers are absent from the map.
+func (r *TrickRepository) GetByIdentifiers(ctx context.Context, ids []string) (map[string]models.Trick, error) {
+	// Same precedence as ResolveNumericID: a slug that is the number wins over the primary key
+	query := `
+		SELECT i.ident, t.slug, t.name, t.difficulty, t.takeoff_stance_id, t.landing_stance_id
+		FROM unnest($1::TEXT[]) AS i (ident)
+		JOIN LATERAL (
+			SELECT slug, name, difficulty, takeoff_stance_id, landing_stance_id
+			FROM trick_data.tricks
+			WHERE deleted_at IS NULL AND status = 'approved'
+				AND (slug = i.ident OR (i.ident ~ '^[0-9]{1,18}$' AND id = i.ident::BIGINT))
+			ORDER BY slug = i.ident DESC
+			LIMIT 1
+		) t ON TRUE
+	`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tricks by identifiers: %w", err)
+	}
+	defer rows.Close()
+
+	tricks := make(map[string]models.Trick, len(ids))
+	for rows.Next() {
+		var ident string
+		var trick models.Trick
+		err := rows.Scan(&ident, &trick.ID, &trick.Name, &trick.Difficulty, &trick.TakeoffStanceID, &trick.LandingStanceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trick: %w", err)
+		}
+		trick.Slug = trick.ID
+		tricks[ident] = trick
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trick rows: %w", err)
+	}
+
+	return tricks, nil
+}
+
+// ResolveNumericID returns the slug for a purely numeric trick identifier
+// Old clients still send the integer primary key ("/tricks/42"). A trick whose
+// slug really is that number wins over the primary key match.
+// Returns ErrNotFound if neither matches a live trick.
+func (r *TrickRepository) ResolveNumericID(ctx context.Context, id string, legacyID int64) (string, error) {
+	query := `
+		SELECT slug
+		FROM trick_data.tricks
+		WHERE (slug = $1 OR id = $2) AND deleted_at IS NULL AND status = 'approved'
+		ORDER BY slug = $1 DESC
+		LIMIT 1
+	`
+
+	var slug string
+	err := r.pool.QueryRow(ctx, query, id, legacyID).Scan(&slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to resolve trick ID %s: %w", id, err)
+	}
+
+	return slug, nil
+}
+
+// GetBySlugs retrieves the live tricks with the given slugs, in no particular order
+// Unknown slugs are simply absent from the result
+func (r *TrickRepository) GetBySlugs(ctx context.Context, slugs []string) ([]models.Trick, error) {
 	query := `
 		SELECT 
 			slug as id, name, description, difficulty, execution_notes,
-			created_by, creator_name, created_at,
-			takeoff_stance_id, landing_stance_id, flip_id, rotation, weight
+			created_by, creator_name, created_at, updated_at,
+			takeoff_stance_id, landing_stance_id, flip_id, rotation, weight,
+			attribution, license
 		FROM trick_data.tricks
-		ORDER BY name ASC
+		WHERE slug = ANY($1) AND deleted_at IS NULL AND status = 'approved'
 	`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.pool.Query(ctx, query, slugs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tricks by slugs: %w", err)
+	}
+	defer rows.Close()
+
+	tricks := make([]models.Trick, 0, len(slugs))
+	for rows.Next() {
+		var trick models.Trick
+		err := rows.Scan(
+			&trick.ID,
+			&trick.Name,
+			&trick.Description,
+			&trick.Difficulty,
+			&trick.ExecutionNotes,
+			&trick.CreatedBy,
+			&trick.CreatorName,
+			&trick.CreatedAt,
+			&trick.UpdatedAt,
+			&trick.TakeoffStanceID,
+			&trick.LandingStanceID,
+			&trick.FlipID,
+			&trick.Rotation,
+			&trick.Weight,
+			&trick.Attribution,
+			&trick.License,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trick: %w", err)
+		}
+		trick.Slug = trick.ID
+		tricks = append(tricks, trick)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tricks: %w", err)
+	}
+
+	return tricks, nil
+}
+
+// findAllTricksQuery selects every live trick, by name (FindAll, ForEachTrick)
+// RowToStructByName needs a column for every models.Trick field - keep the SELECT in sync
+const findAllTricksQuery = `
+	SELECT 
+		slug as id, slug, name, description, difficulty, execution_notes,
+		created_by, creator_name, created_at, updated_at,
+		takeoff_stance_id, landing_stance_id, flip_id, rotation, weight,
+		weight_modifier, attribution, license
+	FROM trick_data.tricks
+	WHERE deleted_at IS NULL AND status = 'approved'
+	ORDER BY name ASC
+`
+
+// FindAll retrieves all tricks from the database
+func (r *TrickRepository) FindAll(ctx context.Context) ([]models.Trick, error) {
+	rows, err := r.pool.Query(ctx, findAllTricksQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tricks: %w", err)
 	}
@@ -139,32 +389,417 @@ func (r *TrickRepository) FindAll(ctx context.Context) ([]models.Trick, error) {
 	return tricks, nil
 }
 
+// ForEachTrick calls fn for every live trick, by name, as rows arrive
+// FindAll without the slice: each row is scanned and handed to fn, so memory
+// stays flat however big the catalog gets. An error from fn stops the query.
+// Like StreamExport, the connection is held until the last row is read.
+func (r *TrickRepository) ForEachTrick(ctx context.Context, fn func(*models.Trick) error) error {
+	rows, err := r.pool.Query(ctx, findAllTricksQuery)
+	if err != nil {
+		return fmt.Errorf("failed to query tricks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		trick, err := pgx.RowToStructByName[models.Trick](rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan trick row: %w", err)
+		}
+		if err := fn(&trick); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate tricks: %w", err)
+	}
+	return nil
+}
+
 // FindSimpleList retrieves a minimal list of tricks for dropdown menus
-// This is more efficient than FindAll when you only need ID and name
-func (r *TrickRepository) FindSimpleList(ctx context.Context) ([]models.TrickSimpleResponse, error) {
+// This is more efficient than FindAll when you only need ID and name.
+// Tricks created at or after newSince are flagged IsNew.
+func (r *TrickRepository) FindSimpleList(ctx context.Context, newSince time.Time) ([]models.TrickSimpleResponse, error) {
 	// Only select the columns we need - more efficient!
 	query := `
-		SELECT slug as id, name
+		SELECT slug as id, name, COALESCE(created_at >= $1, FALSE) AS is_new
 		FROM trick_data.tricks
+		WHERE deleted_at IS NULL AND status = 'approved'
 		ORDER BY name ASC
 	`
 
+	return retryRead(ctx, r.pool, "trick_simple_list", func(q querier) ([]models.TrickSimpleResponse, error) {
+		rows, err := q.Query(ctx, query, newSince)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query tricks simple list: %w", err)
+		}
+
+		// Scanned by hand - IsNew is db:"-" (see FindPage)
+		tricks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.TrickSimpleResponse, error) {
+			var trick models.TrickSimpleResponse
+			err := row.Scan(&trick.ID, &trick.Name, &trick.IsNew)
+			return trick, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect trick simple rows: %w", err)
+		}
+
+		return tricks, nil
+	})
+}
+
+// FindPage retrieves one page of live tricks ordered by name, then slug
+// Keyset pagination: instead of OFFSET we continue from the last row seen,
+// so tricks added between requests can't shift rows across page boundaries.
+// Pass after = nil for the first page. Index:
+//
+//	CREATE INDEX tricks_live_name_slug ON trick_data.tricks (name, slug) WHERE deleted_at IS NULL;
+//
+// withVideoFlags also fills HasVideo / HasFeaturedVideo in the same query
+// (two EXISTS per row, served by trick_videos_trick_id and trick_videos_trick_id_featured).
+// Only availability filters videos: trick_videos has no moderation status, since
+// videos are only added by admins and are live from the start. Unchecked
+// ("unknown") videos count; videos known to be unavailable don't.
+// withVideoCounts fills VideoCount the same way, from a LEFT JOIN LATERAL count:
+// still one query, and a trick without videos gets 0 rather than no row.
+// Tricks created at or after newSince are flagged IsNew.
+func (r *TrickRepository) FindPage(ctx context.Context, after *TrickPageKey, limit int, withVideoFlags, withVideoCounts bool, newSince time.Time) ([]models.TrickSimpleResponse, error) {
+	// slug is unique, so (name, slug) is a total order with no ties
+	// The flags and count come back NULL when not asked for - Postgres skips the
+	// EXISTS and the lateral count entirely
+	query := `
+		SELECT t.slug AS id, t.name,
+			CASE WHEN $5::BOOLEAN THEN EXISTS (
+				SELECT 1 FROM trick_data.trick_videos v
+				WHERE v.trick_id = t.id AND v.availability <> $6
+			) END AS has_video,
+			CASE WHEN $5::BOOLEAN THEN EXISTS (
+				SELECT 1 FROM trick_data.trick_videos v
+				WHERE v.trick_id = t.id AND v.is_featured AND v.availability <> $6
+			) END AS has_featured_video,
+			COALESCE(t.created_at >= $7, FALSE) AS is_new,
+			vc.video_count
+		FROM trick_data.tricks t
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS video_count
+			FROM trick_data.trick_videos v
+			WHERE $8::BOOLEAN AND v.trick_id = t.id AND v.availability <> $6
+		) vc ON $8::BOOLEAN
+		WHERE t.deleted_at IS NULL AND t.status = 'approved'
+			AND ($1::BOOLEAN OR (t.name, t.slug) > ($2, $3))
+		ORDER BY t.name ASC, t.slug ASC
+		LIMIT $4
+	`
+
+	var afterName, afterSlug string
+	if after != nil {
+		afterName, afterSlug = after.Name, after.Slug
+	}
+
+	return retryRead(ctx, r.pool, "trick_page", func(q querier) ([]models.TrickSimpleResponse, error) {
+		rows, err := q.Query(ctx, query, after == nil, afterName, afterSlug, limit, withVideoFlags, models.VideoUnavailable, newSince, withVideoCounts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query trick page: %w", err)
+		}
+
+		// Scanned by hand - the flag fields are db:"-" so the other
+		// TrickSimpleResponse queries can keep using RowToStructByPos
+		tricks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.TrickSimpleResponse, error) {
+			var trick models.TrickSimpleResponse
+			err := row.Scan(&trick.ID, &trick.Name, &trick.HasVideo, &trick.HasFeaturedVideo, &trick.IsNew, &trick.VideoCount)
+			return trick, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect trick page rows: %w", err)
+		}
+
+		return tricks, nil
+	})
+}
+
+// FindCreatedSince retrieves up to limit live tricks created at or after since, newest first
+// Every live trick is published - there is no unreviewed submission state to filter out. Index:
+//
+//	CREATE INDEX tricks_live_created_at ON trick_data.tricks (created_at DESC) WHERE deleted_at IS NULL;
+func (r *TrickRepository) FindCreatedSince(ctx context.Context, since time.Time, limit int) ([]models.NewTrickResponse, error) {
+	query := `
+		SELECT slug AS id, name, difficulty, created_at
+		FROM trick_data.tricks
+		WHERE deleted_at IS NULL AND status = 'approved' AND created_at >= $1
+		ORDER BY created_at DESC, slug ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query new tricks: %w", err)
+	}
+
+	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.NewTrickResponse])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect new trick rows: %w", err)
+	}
+
+	return tricks, nil
+}
+
+// DifficultyHistogram counts the live tricks matching filters at each difficulty
+// One GROUP BY; only difficulties that occur get a bucket, in ascending order,
+// with unrated tricks (NULL) last. A difficulty bound in filters drops that
+// bucket, as it does the tricks (see trickFilterConditions).
+func (r *TrickRepository) DifficultyHistogram(ctx context.Context, filters TrickFilters) ([]models.DifficultyBucket, error) {
+	conditions, args := trickFilterConditions(filters)
+	query := `
+		SELECT difficulty, COUNT(*)
+		FROM trick_data.tricks
+		WHERE deleted_at IS NULL AND status = 'approved'` + conditions + `
+		GROUP BY difficulty
+		ORDER BY difficulty NULLS LAST
+	`
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query difficulty histogram: %w", err)
+	}
+
+	buckets, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.DifficultyBucket])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect difficulty histogram rows: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// FindGraphNodes returns up to limit live tricks matching filters, by name, with their category
+// Callers pass one more than they accept to tell "exactly limit" from "too many".
+func (r *TrickRepository) FindGraphNodes(ctx context.Context, filters TrickFilters, limit int) ([]models.TrickGraphNode, error) {
+	conditions, args := trickFilterConditions(filters)
+	// The filter conditions name tricks columns unqualified, so they run before the join
+	query := fmt.Sprintf(`
+		SELECT t.slug, t.name, t.difficulty, t.flip_id AS category_id, c.name AS category
+		FROM (
+			SELECT slug, name, difficulty, flip_id
+			FROM trick_data.tricks
+			WHERE deleted_at IS NULL AND status = 'approved'%s
+			ORDER BY name, slug
+			LIMIT $%d
+		) t
+		LEFT JOIN trick_data.categories c ON c.id = t.flip_id
+		ORDER BY t.name, t.slug
+	`, conditions, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trick graph nodes: %w", err)
+	}
+
+	nodes, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickGraphNode])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect trick graph nodes: %w", err)
+	}
+	return nodes, nil
+}
+
+// GetStats aggregates the live catalog in one query
+// ROLLUP adds a grand-total row (GROUPING(flip_id) = 1) after the per-flip rows,
+// so totals and the per-flip breakdown come from the same scan. Tricks created
+// at or after addedSince count towards AddedLast30Days.
+func (r *TrickRepository) GetStats(ctx context.Context, addedSince time.Time) (*models.TrickStatsResponse, error) {
+	query := `
+		SELECT
+			GROUPING(flip_id) = 1 AS is_total,
+			flip_id,
+			COUNT(*),
+			ROUND(AVG(difficulty), 2)::FLOAT8,
+			COUNT(*) FILTER (WHERE created_at >= $1)
+		FROM trick_data.tricks
+		WHERE deleted_at IS NULL AND status = 'approved'
+		GROUP BY ROLLUP (flip_id)
+		ORDER BY is_total, flip_id NULLS LAST
+	`
+
+	rows, err := r.pool.Query(ctx, query, addedSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trick stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.TrickStatsResponse{ByFlip: make([]models.FlipTrickCount, 0)}
+	for rows.Next() {
+		var (
+			isTotal    bool
+			flipID     *int
+			count      int
+			average    *float64
+			addedCount int
+		)
+		if err := rows.Scan(&isTotal, &flipID, &count, &average, &addedCount); err != nil {
+			return nil, fmt.Errorf("failed to scan trick stats row: %w", err)
+		}
+
+		if !isTotal {
+			stats.ByFlip = append(stats.ByFlip, models.FlipTrickCount{FlipID: flipID, Count: count})
+			continue
+		}
+		stats.TotalTricks = count
+		stats.AverageDifficulty = average
+		stats.AddedLast30Days = addedCount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trick stats rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// FindSlugs retrieves the slug and last change time of every live trick
+// Used for sitemap generation, so it only touches two columns.
+// Index for an index-only scan:
+//
+//	CREATE INDEX tricks_live_slugs ON trick_data.tricks (slug)
+//	    INCLUDE (created_at, updated_at) WHERE deleted_at IS NULL AND status = 'approved';
+func (r *TrickRepository) FindSlugs(ctx context.Context) ([]models.TrickSlugResponse, error) {
+	// Soft-deleted tricks are excluded - their dictionary pages no longer exist
+	query := `
+		SELECT slug, COALESCE(updated_at, created_at)
+		FROM trick_data.tricks
+		WHERE deleted_at IS NULL AND status = 'approved'
+		ORDER BY slug ASC
+	`
+
 	rows, err := r.pool.Query(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query tricks simple list: %w", err)
+		return nil, fmt.Errorf("failed to query trick slugs: %w", err)
+	}
+
+	slugs, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.TrickSlugResponse])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect trick slug rows: %w", err)
+	}
+
+	return slugs, nil
+}
+
+// Search retrieves live tricks matching query in name, aliases, description or execution notes
+// (former names count as aliases here, and come back in FormerNames)
+// A trick matches on either a substring (ILIKE) or Postgres full-text search, so
+// "backfull" finds "Backfull" and "flips" finds "flip". This is only the candidate
+// set - ranking happens in the service layer, so every candidate carries its aliases.
+// Name matches are fetched first, then alias matches, so a large text match set
+// can't crowd them out. Index for the full-text half:
+//
+//	CREATE INDEX tricks_search_fts ON trick_data.tricks USING GIN (to_tsvector('english',
+//	    name || ' ' || COALESCE(description, '') || ' ' || COALESCE(execution_notes, '')));
+//
+// With minSimilarity > 0 a trick also matches when the query is within typo
+// distance of its name or an alias: pg_trgm's word_similarity - how closely the
+// query matches the best-matching stretch of the name - is at least minSimilarity,
+// so "gainner" finds "Side Gainer". Every candidate's Similarity is set (0-1).
+// Pass 0 when pg_trgm isn't installed: the query then never calls it and
+// Similarity stays nil. The threshold is a parameter rather than the
+// pg_trgm.word_similarity_threshold setting, so it can't use tricks_name_trgm -
+// fine for a catalog of a few hundred tricks.
+func (r *TrickRepository) Search(ctx context.Context, query string, limit int, minSimilarity float64) ([]models.Trick, error) {
+	// $1 is the LIKE-escaped term, $3 the raw term for plainto_tsquery and
+	// word_similarity, $4 the similarity threshold
+	similarity, fuzzyMatch := `NULL::float8`, ``
+	args := []any{escapeLike(query), limit, query}
+	if minSimilarity > 0 {
+		similarity = `GREATEST(
+				word_similarity($3, name),
+				(SELECT max(word_similarity($3, a.alias)) FROM trick_data.trick_aliases a WHERE a.trick_id = tricks.id)
+			)::float8`
+		fuzzyMatch = `OR similarity >= $4`
+		args = append(args, minSimilarity)
+	}
+
+	sql := `
+		SELECT slug, name, description, execution_notes, difficulty, weight, aliases, former_names, similarity
+		FROM (
+			SELECT slug, name, description, execution_notes, difficulty, weight,
+				ARRAY(
+					SELECT a.alias FROM trick_data.trick_aliases a
+					WHERE a.trick_id = tricks.id AND NOT a.former_name ORDER BY lower(a.alias)
+				) AS aliases,
+				ARRAY(
+					SELECT a.alias FROM trick_data.trick_aliases a
+					WHERE a.trick_id = tricks.id AND a.former_name ORDER BY a.created_at DESC, a.id DESC
+				) AS former_names,
+				name ILIKE '%' || $1 || '%' AS name_match,
+				EXISTS (
+					SELECT 1 FROM trick_data.trick_aliases a
+					WHERE a.trick_id = tricks.id AND a.alias ILIKE '%' || $1 || '%'
+				) AS alias_match,
+				description ILIKE '%' || $1 || '%' OR execution_notes ILIKE '%' || $1 || '%' AS text_match,
+				ts_rank(to_tsvector('english',
+					name || ' ' || COALESCE(description, '') || ' ' || COALESCE(execution_notes, '')),
+					plainto_tsquery('english', $3)) AS text_rank,
+				to_tsvector('english',
+					name || ' ' || COALESCE(description, '') || ' ' || COALESCE(execution_notes, ''))
+					@@ plainto_tsquery('english', $3) AS fts_match,
+				` + similarity + ` AS similarity
+			FROM trick_data.tricks
+			WHERE deleted_at IS NULL AND status = 'approved'
+		) candidates
+		WHERE name_match OR alias_match OR text_match OR fts_match ` + fuzzyMatch + `
+		ORDER BY name_match DESC, alias_match DESC, similarity DESC NULLS LAST,
+			text_rank DESC, weight DESC, name ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tricks: %w", err)
+	}
+	defer rows.Close()
+
+	tricks := make([]models.Trick, 0)
+	for rows.Next() {
+		var trick models.Trick
+		err := rows.Scan(&trick.ID, &trick.Name, &trick.Description, &trick.ExecutionNotes, &trick.Difficulty, &trick.Weight, &trick.Aliases, &trick.FormerNames, &trick.Similarity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		tricks = append(tricks, trick)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search results: %w", err)
+	}
+
+	return tricks, nil
+}
+
+// FindByNamePrefix retrieves live tricks whose name starts with prefix (case-insensitive)
+// Backs type-ahead, so it only touches slug and name. Index (pg_trgm makes ILIKE indexable):
+//
+//	CREATE EXTENSION IF NOT EXISTS pg_trgm;
+//	CREATE INDEX tricks_name_trgm ON trick_data.tricks USING GIN (name gin_trgm_ops);
+func (r *TrickRepository) FindByNamePrefix(ctx context.Context, prefix string, limit int) ([]models.TrickAutocompleteResponse, error) {
+	query := `
+		SELECT slug, name
+		FROM trick_data.tricks
+		WHERE deleted_at IS NULL AND status = 'approved' AND name ILIKE $1 || '%'
+		ORDER BY weight DESC, name ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, escapeLike(prefix), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tricks by name prefix: %w", err)
 	}
 
-	// pgx.CollectRows with RowToStructByPos for simple DTOs without db tags
-	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.TrickSimpleResponse])
+	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.TrickAutocompleteResponse])
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect trick simple rows: %w", err)
+		return nil, fmt.Errorf("failed to collect autocomplete rows: %w", err)
 	}
 
 	return tricks, nil
 }
 
 // FindByFilters retrieves tricks matching the given filters
-// This is used by the combo generation algorithm
+// This is used by the combo generation algorithm and the difficulty-filtered trick list
+// Note: "difficulty >= $n" is never true for NULL, so tricks without a difficulty
+// drop out as soon as either bound is set.
 func (r *TrickRepository) FindByFilters(ctx context.Context, filters TrickFilters) ([]models.Trick, error) {
 	// ==========================================================================
 	// DYNAMIC QUERY BUILDING
@@ -175,16 +810,89 @@ func (r *TrickRepository) FindByFilters(ctx context.Context, filters TrickFilter
 	// Base query
 	query := `
 		SELECT 
-			slug as id, name, description, difficulty, execution_notes,
+			slug as id, slug, name, description, difficulty, execution_notes,
 			created_by, creator_name, created_at, updated_at,
-			takeoff_stance_id, landing_stance_id, flip_id, rotation, weight
+			takeoff_stance_id, landing_stance_id, flip_id, rotation, weight,
+			weight_modifier, attribution, license
 		FROM trick_data.tricks
-		WHERE 1=1
+		WHERE deleted_at IS NULL AND status = 'approved'
+	`
+	// Starting with a WHERE clause (soft-deleted tricks are never candidates)
+	// means every filter condition can start with "AND"
+
+	conditions, args := trickFilterConditions(filters)
+	query += conditions
+	argPosition := len(args) + 1 // Tracks which $N we're on
+
+	// Add ordering - we order by effective weight for combo generation
+	// Higher weight = more likely to be selected (decayed tricks sink)
+	query += " ORDER BY weight * weight_modifier DESC, RANDOM()"
+
+	// Add limit if specified
+	if filters.Limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", argPosition)
+		args = append(args, *filters.Limit)
+	}
+
+	// Execute the query (once more if a failover dropped the connection - see retry.go)
+	return retryRead(ctx, r.pool, "trick_filters", func(q querier) ([]models.Trick, error) {
+		rows, err := q.Query(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query tricks with filters: %w", err)
+		}
+
+		// pgx.CollectRows handles iteration, scanning, and closing rows automatically
+		tricks, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Trick])
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect filtered trick rows: %w", err)
+		}
+
+		return tricks, nil
+	})
+}
+
+// StreamExport calls fn for every live trick matching filters, by name, as rows arrive
+// Nothing is collected: each row is scanned into the same struct and handed to fn,
+// so memory stays flat however big the catalog gets. An error from fn stops the
+// query. The connection is held until the last row is read - fn should write
+// straight to the client, not do more queries.
+func (r *TrickRepository) StreamExport(ctx context.Context, filters TrickFilters, fn func(models.TrickExportRow) error) error {
+	conditions, args := trickFilterConditions(filters)
+	query := `
+		SELECT t.name, t.slug, t.difficulty, t.rotation,
+			ts.name, ls.name, t.creator_name, t.attribution, t.license
+		FROM (
+			SELECT * FROM trick_data.tricks WHERE deleted_at IS NULL AND status = 'approved'` + conditions + `
+		) t
+		LEFT JOIN trick_data.stances ts ON ts.id = t.takeoff_stance_id
+		LEFT JOIN trick_data.stances ls ON ls.id = t.landing_stance_id
+		ORDER BY t.name ASC, t.slug ASC
 	`
-	// "WHERE 1=1" is a trick that makes it easier to append AND clauses
-	// because every condition can start with "AND"
 
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query trick export: %w", err)
+	}
+
+	var row models.TrickExportRow
+	_, err = pgx.ForEachRow(rows, []any{
+		&row.Name, &row.Slug, &row.Difficulty, &row.Rotation,
+		&row.TakeoffStance, &row.LandingStance, &row.CreatorName, &row.Attribution, &row.License,
+	}, func() error {
+		return fn(row)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream trick export: %w", err)
+	}
+	return nil
+}
+
+// trickFilterConditions builds the " AND ..." conditions for filters, numbered from $1
+// Columns are unqualified (trick_data.tricks), so callers with joins filter in a subquery.
+// Limit is not included - it has to come after ORDER BY.
+func trickFilterConditions(filters TrickFilters) (string, []interface{}) {
 	// args holds the parameter values in order ($1, $2, etc.)
+	query := ""
 	args := make([]interface{}, 0)
 	argPosition := 1 // Tracks which $N we're on
 
@@ -201,6 +909,19 @@ func (r *TrickRepository) FindByFilters(ctx context.Context, filters TrickFilter
 		argPosition++
 	}
 
+	// Add stance filters if provided - both set means takeoff AND landing must match
+	if filters.TakeoffStanceID != nil {
+		query += fmt.Sprintf(" AND takeoff_stance_id = $%d", argPosition)
+		args = append(args, *filters.TakeoffStanceID)
+		argPosition++
+	}
+
+	if filters.LandingStanceID != nil {
+		query += fmt.Sprintf(" AND landing_stance_id = $%d", argPosition)
+		args = append(args, *filters.LandingStanceID)
+		argPosition++
+	}
+
 	// Add category filter if provided
 	// This assumes you have a category_id column or a junction table
 	// Adjust based on your actual schema
@@ -217,29 +938,26 @@ func (r *TrickRepository) FindByFilters(ctx context.Context, filters TrickFilter
 		argPosition++
 	}
 
-	// Add ordering - we order by weight for combo generation
-	// Higher weight = more likely to be selected
-	query += " ORDER BY weight DESC, RANDOM()"
-
-	// Add limit if specified
-	if filters.Limit != nil {
-		query += fmt.Sprintf(" LIMIT $%d", argPosition)
-		args = append(args, *filters.Limit)
-	}
-
-	// Execute the query
-	rows, err := r.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query tricks with filters: %w", err)
+	if len(filters.ExcludeSlugs) > 0 {
+		query += fmt.Sprintf(" AND slug != ALL($%d)", argPosition)
+		args = append(args, filters.ExcludeSlugs)
+		argPosition++
 	}
 
-	// pgx.CollectRows handles iteration, scanning, and closing rows automatically
-	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Trick])
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect filtered trick rows: %w", err)
+	// Tags are ANDed: the trick must match as many distinct tags as were asked for
+	if len(filters.Tags) > 0 {
+		query += fmt.Sprintf(` AND id IN (
+			SELECT tt.trick_id
+			FROM trick_data.trick_tags tt
+			JOIN trick_data.tags g ON g.id = tt.tag_id
+			WHERE g.name = ANY($%d)
+			GROUP BY tt.trick_id
+			HAVING COUNT(*) = $%d)`, argPosition, argPosition+1)
+		args = append(args, filters.Tags, len(filters.Tags))
+		argPosition += 2
 	}
 
-	return tricks, nil
+	return query, args
 }
 
 // GetByIDWithTimestamp retrieves a single trick with updated_at timestamp
@@ -249,9 +967,10 @@ func (r *TrickRepository) GetByIDWithTimestamp(ctx context.Context, id string) (
 		SELECT
 			slug as id, name, description, difficulty, execution_notes,
 			created_by, creator_name, created_at, updated_at,
-			takeoff_stance_id, landing_stance_id, flip_id, rotation, weight
+			takeoff_stance_id, landing_stance_id, flip_id, rotation, weight,
+			attribution, license
 		FROM trick_data.tricks
-		WHERE slug = $1
+		WHERE slug = $1 AND deleted_at IS NULL AND status = 'approved'
 	`
 
 	var trick models.Trick
@@ -270,6 +989,8 @@ func (r *TrickRepository) GetByIDWithTimestamp(ctx context.Context, id string) (
 		&trick.FlipID,
 		&trick.Rotation,
 		&trick.Weight,
+		&trick.Attribution,
+		&trick.License,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -277,29 +998,122 @@ func (r *TrickRepository) GetByIDWithTimestamp(ctx context.Context, id string) (
 		}
 		return nil, fmt.Errorf("failed to get trick with timestamp by ID %s: %w", id, err)
 	}
+	trick.Slug = trick.ID
 
 	return &trick, nil
 }
 
 // GetLastModified returns the latest modification timestamp across all tricks
+// Soft deletes count as modifications (GREATEST ignores NULLs)
 // Used for ETag generation on list endpoints
 // Returns Unix timestamp (seconds since epoch)
 func (r *TrickRepository) GetLastModified(ctx context.Context) (int64, error) {
 	query := `
 		SELECT COALESCE(
-			EXTRACT(EPOCH FROM MAX(GREATEST(created_at, COALESCE(updated_at, created_at))))::BIGINT,
+			EXTRACT(EPOCH FROM MAX(GREATEST(created_at, updated_at, deleted_at)))::BIGINT,
 			0
 		)
 		FROM trick_data.tricks
 	`
 
-	var timestamp int64
-	err := r.pool.QueryRow(ctx, query).Scan(&timestamp)
+	return retryRead(ctx, r.pool, "trick_last_modified", func(q querier) (int64, error) {
+		var timestamp int64
+		if err := q.QueryRow(ctx, query).Scan(&timestamp); err != nil {
+			return 0, fmt.Errorf("failed to get last modified timestamp: %w", err)
+		}
+		return timestamp, nil
+	})
+}
+
+// FindChangesSince returns the live tricks created or updated at or after since,
+// and the slugs of tricks soft-deleted at or after it.
+// Both reads share one REPEATABLE READ snapshot, and ServerTime is that
+// transaction's NOW(), so nothing falls between this sync and the next one.
+// Tricks purged for good are gone from the table - a client whose since is
+// older than the purge window won't hear about them.
+// withReferenceData also reads the categories and stances changed since then,
+// in the same snapshot (categories and stances are never deleted). Without
+// withTricks the changed tricks aren't read - only the deleted slugs are.
+func (r *TrickRepository) FindChangesSince(ctx context.Context, since time.Time, withTricks, withReferenceData bool) (*TrickChanges, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	changes := &TrickChanges{}
+	if err := tx.QueryRow(ctx, `SELECT NOW()`).Scan(&changes.ServerTime); err != nil {
+		return nil, fmt.Errorf("failed to read server time: %w", err)
+	}
+
+	if withTricks {
+		rows, err := tx.Query(ctx, `
+			SELECT 
+				slug as id, slug, name, description, difficulty, execution_notes,
+				created_by, creator_name, created_at, updated_at,
+				takeoff_stance_id, landing_stance_id, flip_id, rotation, weight,
+				weight_modifier, attribution, license
+			FROM trick_data.tricks
+			WHERE deleted_at IS NULL AND status = 'approved'
+			  AND GREATEST(created_at, COALESCE(updated_at, created_at)) >= $1
+			ORDER BY slug
+		`, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query changed tricks: %w", err)
+		}
+		changes.Tricks, err = pgx.CollectRows(rows, pgx.RowToStructByName[models.Trick])
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect changed trick rows: %w", err)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT slug
+		FROM trick_data.tricks
+		WHERE deleted_at >= $1
+		ORDER BY slug
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted tricks: %w", err)
+	}
+	changes.DeletedSlugs, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect deleted trick slugs: %w", err)
+	}
+
+	if !withReferenceData {
+		return changes, nil
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT id, name, parent_id
+		FROM trick_data.categories
+		WHERE GREATEST(created_at, updated_at) >= $1
+		ORDER BY id
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changed categories: %w", err)
+	}
+	changes.Categories, err = pgx.CollectRows(rows, pgx.RowToStructByName[models.Category])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect changed category rows: %w", err)
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT id, name, description, leg
+		FROM trick_data.stances
+		WHERE updated_at >= $1
+		ORDER BY id
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changed stances: %w", err)
+	}
+	changes.Stances, err = pgx.CollectRows(rows, pgx.RowToStructByName[models.Stance])
 	if err != nil {
-		return 0, fmt.Errorf("failed to get last modified timestamp: %w", err)
+		return nil, fmt.Errorf("failed to collect changed stance rows: %w", err)
 	}
 
-	return timestamp, nil
+	return changes, nil
 }
 
 // GetLastModifiedByID returns the modification timestamp for a specific trick
@@ -309,17 +1123,2040 @@ func (r *TrickRepository) GetLastModifiedByID(ctx context.Context, id string) (i
 	query := `
 		SELECT EXTRACT(EPOCH FROM GREATEST(created_at, COALESCE(updated_at, created_at)))::BIGINT
 		FROM trick_data.tricks
-		WHERE slug = $1
+		WHERE slug = $1 AND deleted_at IS NULL AND status = 'approved'
 	`
 
-	var timestamp int64
-	err := r.pool.QueryRow(ctx, query, id).Scan(&timestamp)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, ErrNotFound
+	return retryRead(ctx, r.pool, "trick_last_modified_by_id", func(q querier) (int64, error) {
+		var timestamp int64
+		if err := q.QueryRow(ctx, query, id).Scan(&timestamp); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, ErrNotFound
+			}
+			return 0, fmt.Errorf("failed to get last modified timestamp for trick %s: %w", id, err)
 		}
-		return 0, fmt.Errorf("failed to get last modified timestamp for trick %s: %w", id, err)
+		return timestamp, nil
+	})
+}
+
+// ViewDayRetentionDays is how many days of daily view buckets are kept
+// It bounds the ?window= of GET /tricks/popular.
+const ViewDayRetentionDays = 30
+
+// IncrementViewCounts adds pending view counts to each trick's view_count
+// Also stamps last_viewed_at, which the weight decay job uses to spot stale tricks.
+// Requires: ALTER TABLE trick_data.tricks ADD COLUMN view_count BIGINT NOT NULL DEFAULT 0,
+// ADD COLUMN last_viewed_at TIMESTAMPTZ;
+//
+// view_count is all-time, so "popular this week" needs views per time window too.
+// DESIGN: rolling daily counters, not one row per view:
+//
+//	CREATE TABLE trick_data.trick_view_days (
+//	    trick_id INTEGER NOT NULL REFERENCES trick_data.tricks (id),
+//	    day      DATE    NOT NULL,  -- UTC
+//	    views    BIGINT  NOT NULL,
+//	    PRIMARY KEY (trick_id, day)
+//	);
+//	CREATE INDEX trick_view_days_day ON trick_data.trick_view_days (day);
+//
+// A trick_views(trick_id, viewed_at) log would grow with traffic; daily buckets
+// grow with tricks x days and are upserted with the same relative increment.
+// The price is day granularity: a 7-day window is today plus the 6 days before.
+// Buckets older than ViewDayRetentionDays are deleted in the same flush.
+//
+// Each UPDATE is a RELATIVE increment - we never read the count and write it back,
+// so concurrent flushes from multiple API instances can't overwrite each other.
+// All updates are sent as one pgx.Batch inside a transaction: either every
+// increment lands or none do, which lets the caller safely retry on error.
+func (r *TrickRepository) IncrementViewCounts(ctx context.Context, counts map[string]int64) error {
+	if len(counts) == 0 {
+		return nil
 	}
 
-	return timestamp, nil
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for id, n := range counts {
+		batch.Queue(
+			`UPDATE trick_data.tricks SET view_count = view_count + $1, last_viewed_at = NOW() WHERE slug = $2`,
+			n, id,
+		)
+		batch.Queue(`
+			INSERT INTO trick_data.trick_view_days (trick_id, day, views)
+			SELECT id, (NOW() AT TIME ZONE 'UTC')::date, $1
+			FROM trick_data.tricks WHERE slug = $2
+			ON CONFLICT (trick_id, day) DO UPDATE SET views = trick_data.trick_view_days.views + EXCLUDED.views`,
+			n, id,
+		)
+	}
+	batch.Queue(
+		`DELETE FROM trick_data.trick_view_days WHERE day < (NOW() AT TIME ZONE 'UTC')::date - $1::int`,
+		ViewDayRetentionDays,
+	)
+
+	// Close reads every queued result and returns the first error
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to increment trick view counts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FindPopular retrieves up to limit live tricks with the most views on or after since's day
+// Reads the daily buckets described on IncrementViewCounts; views not yet flushed
+// (up to one flush interval) aren't counted. Ties go to the name, then the slug.
+func (r *TrickRepository) FindPopular(ctx context.Context, since time.Time, limit int) ([]models.PopularTrickResponse, error) {
+	query := `
+		SELECT t.slug AS id, t.name, t.difficulty, SUM(v.views)::BIGINT AS views
+		FROM trick_data.trick_view_days v
+		JOIN trick_data.tricks t ON t.id = v.trick_id
+		WHERE v.day >= $1::date AND t.deleted_at IS NULL AND t.status = 'approved'
+		GROUP BY t.id
+		ORDER BY views DESC, t.name ASC, t.slug ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, since.UTC().Format(time.DateOnly), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query popular tricks: %w", err)
+	}
+
+	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.PopularTrickResponse])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect popular trick rows: %w", err)
+	}
+
+	return tricks, nil
+}
+
+// FindTextFields retrieves every trick with only its free-text columns populated
+// Used by the admin re-sanitization pass
+func (r *TrickRepository) FindTextFields(ctx context.Context) ([]models.Trick, error) {
+	query := `
+		SELECT slug, name, description, execution_notes
+		FROM trick_data.tricks
+		ORDER BY slug ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trick text fields: %w", err)
+	}
+	defer rows.Close()
+
+	tricks := make([]models.Trick, 0)
+	for rows.Next() {
+		var trick models.Trick
+		if err := rows.Scan(&trick.ID, &trick.Name, &trick.Description, &trick.ExecutionNotes); err != nil {
+			return nil, fmt.Errorf("failed to scan trick text fields: %w", err)
+		}
+		tricks = append(tricks, trick)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trick text fields: %w", err)
+	}
+
+	return tricks, nil
+}
+
+// UpdateTextFields overwrites a trick's free-text columns
+func (r *TrickRepository) UpdateTextFields(ctx context.Context, trick *models.Trick) error {
+	query := `
+		UPDATE trick_data.tricks
+		SET name = $1, description = $2, execution_notes = $3, updated_at = NOW()
+		WHERE slug = $4
+	`
+
+	tag, err := r.pool.Exec(ctx, query, trick.Name, trick.Description, trick.ExecutionNotes, trick.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update text fields for trick %s: %w", trick.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// escapeLike escapes LIKE/ILIKE wildcards so user input matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// FindDifficultyCalibration finds tricks whose curated difficulty disagrees with community votes
+// Requires:
+//
+//	CREATE TABLE trick_data.trick_difficulty_votes (
+//	    trick_id INTEGER NOT NULL REFERENCES trick_data.tricks(id),
+//	    user_id UUID NOT NULL,
+//	    difficulty SMALLINT NOT NULL,
+//	    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//	    PRIMARY KEY (trick_id, user_id)
+//	);
+//
+// Only tricks with at least minVotes votes and |curated - average| > minDelta are returned,
+// biggest disagreement first. The second return value is the total across all pages.
+func (r *TrickRepository) FindDifficultyCalibration(ctx context.Context, minVotes int, minDelta float64, limit, offset int) ([]models.DifficultyCalibration, int64, error) {
+	// COUNT(*) OVER () gives the total match count alongside each page row
+	query := `
+		WITH votes AS (
+			SELECT trick_id, AVG(difficulty)::FLOAT8 AS community_average, COUNT(*) AS vote_count
+			FROM trick_data.trick_difficulty_votes
+			GROUP BY trick_id
+			HAVING COUNT(*) >= $1
+		)
+		SELECT
+			t.slug AS id, t.name, t.difficulty AS curated_difficulty,
+			v.community_average, v.vote_count,
+			ABS(t.difficulty - v.community_average) AS delta,
+			COUNT(*) OVER () AS total
+		FROM trick_data.tricks t
+		JOIN votes v ON v.trick_id = t.id
+		WHERE t.deleted_at IS NULL AND t.status = 'approved'
+			AND t.difficulty IS NOT NULL
+			AND ABS(t.difficulty - v.community_average) > $2
+		ORDER BY delta DESC, t.slug ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.pool.Query(ctx, query, minVotes, minDelta, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query difficulty calibration: %w", err)
+	}
+	defer rows.Close()
+
+	var total int64
+	tricks := make([]models.DifficultyCalibration, 0)
+	for rows.Next() {
+		var cal models.DifficultyCalibration
+		err := rows.Scan(&cal.ID, &cal.Name, &cal.CuratedDifficulty,
+			&cal.CommunityAverage, &cal.VoteCount, &cal.Delta, &total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan difficulty calibration: %w", err)
+		}
+		tricks = append(tricks, cal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate difficulty calibration: %w", err)
+	}
+
+	return tricks, total, nil
+}
+
+// AdoptCommunityDifficulty copies the rounded community average into the curated difficulty
+// The change and a trick_revisions audit row are written in one transaction
+func (r *TrickRepository) AdoptCommunityDifficulty(ctx context.Context, id string, changedBy *uuid.UUID) (*models.DifficultyChangeResponse, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR UPDATE on the trick row so concurrent edits can't slip in between read and write
+	var trickID int
+	var previous *int64
+	err = tx.QueryRow(ctx,
+		`SELECT id, difficulty FROM trick_data.tricks WHERE slug = $1 AND deleted_at IS NULL FOR UPDATE`,
+		id,
+	).Scan(&trickID, &previous)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick %s: %w", id, err)
+	}
+
+	// ROUND(AVG) is NULL when there are no votes
+	var community *int64
+	err = tx.QueryRow(ctx,
+		`SELECT ROUND(AVG(difficulty))::BIGINT FROM trick_data.trick_difficulty_votes WHERE trick_id = $1`,
+		trickID,
+	).Scan(&community)
+	if err != nil {
+		return nil, fmt.Errorf("failed to average votes for trick %s: %w", id, err)
+	}
+	if community == nil {
+		return nil, ErrNoVotes
+	}
+
+	before, err := trickAuditStates(ctx, tx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE trick_data.tricks SET difficulty = $1, updated_at = NOW() WHERE id = $2`,
+		*community, trickID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update difficulty for trick %s: %w", id, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by) VALUES ($1, $2, $3)`,
+		trickID, []string{"difficulty"}, changedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record difficulty revision for trick %s: %w", id, err)
+	}
+
+	after, err := trickAuditStates(ctx, tx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditUpdate, before, after, changedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &models.DifficultyChangeResponse{
+		ID:                 id,
+		PreviousDifficulty: previous,
+		Difficulty:         *community,
+	}, nil
+}
+
+// =============================================================================
+// SOFT DELETE & PURGE
+// =============================================================================
+// Deleting a trick is two-phase:
+//   1. SoftDelete sets deleted_at and schedules purge_at - the trick disappears
+//      from every public read but can still be restored.
+//   2. PurgeExpired permanently removes tricks whose purge_at has passed,
+//      together with every row that references them.
+//
+// Requires: ALTER TABLE trick_data.tricks ADD COLUMN purge_at TIMESTAMPTZ;
+//           CREATE INDEX ON trick_data.tricks (purge_at) WHERE purge_at IS NOT NULL;
+
+// SoftDelete marks a live trick deleted and schedules its purge purgeAfter from now
+// Returns ErrNotFound if the trick doesn't exist or is already deleted
+func (r *TrickRepository) SoftDelete(ctx context.Context, id string, purgeAfter time.Duration, changedBy *uuid.UUID) (*models.TrickDeletionResponse, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := trickAuditStates(ctx, tx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	var trickID int
+	deletion := models.TrickDeletionResponse{ID: id}
+	err = tx.QueryRow(ctx,
+		`UPDATE trick_data.tricks
+		 SET deleted_at = NOW(), purge_at = NOW() + $2 * INTERVAL '1 second'
+		 WHERE slug = $1 AND deleted_at IS NULL
+		 RETURNING id, deleted_at, purge_at`,
+		id, purgeAfter.Seconds(),
+	).Scan(&trickID, &deletion.DeletedAt, &deletion.PurgeAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to delete trick %s: %w", id, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by) VALUES ($1, $2, $3)`,
+		trickID, []string{"deleted_at"}, changedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record deletion revision for trick %s: %w", id, err)
+	}
+
+	after, err := trickAuditStates(ctx, tx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditDelete, before, after, changedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &deletion, nil
+}
+
+// Restore brings a soft-deleted trick back, as long as its purge date hasn't passed
+// Returns ErrNotFound, ErrNotDeleted or ErrPurgeWindowClosed
+func (r *TrickRepository) Restore(ctx context.Context, id string, changedBy *uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR UPDATE so the purge job can't remove the trick while we restore it
+	var trickID int
+	var deletedAt *time.Time
+	var purgeOpen bool
+	err = tx.QueryRow(ctx,
+		`SELECT id, deleted_at, COALESCE(purge_at > NOW(), false)
+		 FROM trick_data.tricks WHERE slug = $1 FOR UPDATE`,
+		id,
+	).Scan(&trickID, &deletedAt, &purgeOpen)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to get trick %s: %w", id, err)
+	}
+	if deletedAt == nil {
+		return ErrNotDeleted
+	}
+	if !purgeOpen {
+		return ErrPurgeWindowClosed
+	}
+
+	before, err := trickAuditStates(ctx, tx, []string{id})
+	if err != nil {
+		return err
+	}
+
+	// Bumping updated_at invalidates list and detail ETags
+	_, err = tx.Exec(ctx,
+		`UPDATE trick_data.tricks SET deleted_at = NULL, purge_at = NULL, updated_at = NOW() WHERE id = $1`,
+		trickID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore trick %s: %w", id, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by) VALUES ($1, $2, $3)`,
+		trickID, []string{"deleted_at"}, changedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record restore revision for trick %s: %w", id, err)
+	}
+
+	after, err := trickAuditStates(ctx, tx, []string{id})
+	if err != nil {
+		return err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditRestore, before, after, changedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// FindPendingPurge lists every soft-deleted trick with its purge date, soonest first
+func (r *TrickRepository) FindPendingPurge(ctx context.Context) ([]models.PendingPurge, error) {
+	query := `
+		SELECT slug AS id, name, deleted_at, purge_at
+		FROM trick_data.tricks
+		WHERE deleted_at IS NOT NULL AND purge_at IS NOT NULL
+		ORDER BY purge_at ASC, slug ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending purges: %w", err)
+	}
+
+	pending, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.PendingPurge])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect pending purge rows: %w", err)
+	}
+
+	return pending, nil
+}
+
+// PurgeExpired permanently deletes up to batchSize tricks past their purge date
+// Dependent rows (videos, combo positions, revisions, votes, prerequisites, aliases, tags) go in the same transaction,
+// so a failed batch leaves nothing half-deleted. Returns how many tricks were purged;
+// callers loop until it returns less than batchSize. Running it again is a no-op.
+func (r *TrickRepository) PurgeExpired(ctx context.Context, batchSize int) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// SKIP LOCKED lets two instances run the job at once without blocking each other,
+	// and skips tricks that are being restored right now
+	rows, err := tx.Query(ctx,
+		`SELECT id FROM trick_data.tricks
+		 WHERE deleted_at IS NOT NULL AND purge_at <= NOW()
+		 ORDER BY purge_at ASC
+		 LIMIT $1
+		 FOR UPDATE SKIP LOCKED`,
+		batchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select tricks to purge: %w", err)
+	}
+	trickIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect tricks to purge: %w", err)
+	}
+	if len(trickIDs) == 0 {
+		return 0, nil
+	}
+
+	// Children first, so the foreign keys on tricks(id) are satisfied
+	dependents := []string{
+		`DELETE FROM trick_data.trick_videos WHERE trick_id = ANY($1)`,
+		`DELETE FROM combo_tricks WHERE trick_id = ANY($1)`,
+		`DELETE FROM trick_data.trick_revisions WHERE trick_id = ANY($1)`,
+		`DELETE FROM trick_data.trick_difficulty_votes WHERE trick_id = ANY($1)`,
+		`DELETE FROM trick_data.trick_prerequisites WHERE trick_id = ANY($1) OR prerequisite_id = ANY($1)`,
+		`DELETE FROM trick_data.trick_aliases WHERE trick_id = ANY($1)`,
+		`DELETE FROM trick_data.trick_tags WHERE trick_id = ANY($1)`,
+		`DELETE FROM trick_data.trick_view_days WHERE trick_id = ANY($1)`,
+		`DELETE FROM trick_data.trick_translations WHERE trick_id = ANY($1)`,
+		`DELETE FROM trick_data.tricks WHERE id = ANY($1)`,
+	}
+	for _, stmt := range dependents {
+		if _, err := tx.Exec(ctx, stmt, trickIDs); err != nil {
+			return 0, fmt.Errorf("failed to purge tricks: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(trickIDs), nil
+}
+
+// =============================================================================
+// WEIGHT DECAY
+// =============================================================================
+// weight_modifier scales a trick's curated weight during combo generation.
+// Only ApplyWeightDecay and VideoRepository.Create write it; weight is never touched.
+//
+// Requires: ALTER TABLE trick_data.tricks
+//               ADD COLUMN weight_modifier DOUBLE PRECISION NOT NULL DEFAULT 1.0;
+
+// ApplyWeightDecay sets weight_modifier on every live trick from the staleness rule:
+// created before staleBefore, no videos, and not viewed since staleBefore -> modifier,
+// anything else -> 1.0 (so a trick that picks up views recovers on the next run).
+// Only rows whose modifier actually changes are written. Returns how many changed.
+func (r *TrickRepository) ApplyWeightDecay(ctx context.Context, staleBefore time.Time, modifier float64) (int64, error) {
+	query := `
+		UPDATE trick_data.tricks t
+		SET weight_modifier = target.modifier
+		FROM (
+			SELECT
+				s.id,
+				CASE
+					WHEN s.created_at < $1
+						AND (s.last_viewed_at IS NULL OR s.last_viewed_at < $1)
+						AND NOT EXISTS (SELECT 1 FROM trick_data.trick_videos v WHERE v.trick_id = s.id)
+					THEN $2::DOUBLE PRECISION
+					ELSE 1.0
+				END AS modifier
+			FROM trick_data.tricks s
+			WHERE s.deleted_at IS NULL
+		) target
+		WHERE t.id = target.id AND t.weight_modifier <> target.modifier
+	`
+
+	tag, err := r.pool.Exec(ctx, query, staleBefore, modifier)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply weight decay: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// SetWeights sets the curated weight of live tricks by slug, all in one transaction
+// Returns how many tricks actually changed (a revision is written for each).
+// If any slug isn't a live trick, nothing is written and those slugs are returned.
+func (r *TrickRepository) SetWeights(ctx context.Context, weights map[string]int16, changedBy *uuid.UUID) (int, []string, error) {
+	slugs := make([]string, 0, len(weights))
+	values := make([]int16, 0, len(weights))
+	for slug, weight := range weights {
+		slugs = append(slugs, slug)
+		values = append(values, weight)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := trickAuditStates(ctx, tx, slugs)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Unchanged weights are matched (so they don't count as missing) but not written
+	// updated_at moves to a later second, as in Update - weight is part of the detail ETag
+	rows, err := tx.Query(ctx, `
+		WITH targets AS (
+			SELECT t.id, t.slug, w.weight
+			FROM unnest($1::TEXT[], $2::SMALLINT[]) AS w(slug, weight)
+			JOIN trick_data.tricks t ON t.slug = w.slug AND t.deleted_at IS NULL
+		), updated AS (
+			UPDATE trick_data.tricks t SET
+				weight = targets.weight,
+				updated_at = GREATEST(NOW(),
+					date_trunc('second', GREATEST(t.created_at, t.updated_at)) + INTERVAL '1 second')
+			FROM targets
+			WHERE t.id = targets.id AND t.weight <> targets.weight
+			RETURNING t.id
+		), revisions AS (
+			INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by)
+			SELECT id, ARRAY['weight'], $3 FROM updated
+		)
+		SELECT targets.slug, updated.id IS NOT NULL
+		FROM targets
+		LEFT JOIN updated ON updated.id = targets.id`,
+		slugs, values, changedBy,
+	)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to set trick weights: %w", err)
+	}
+
+	found := make(map[string]bool, len(weights))
+	changed := 0
+	var (
+		slug    string
+		written bool
+	)
+	_, err = pgx.ForEachRow(rows, []any{&slug, &written}, func() error {
+		found[slug] = true
+		if written {
+			changed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to collect trick weight rows: %w", err)
+	}
+
+	missing := []string{}
+	for _, slug := range slugs {
+		if !found[slug] {
+			missing = append(missing, slug)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return 0, missing, nil
+	}
+
+	after, err := trickAuditStates(ctx, tx, slugs)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditUpdate, before, after, changedBy); err != nil {
+		return 0, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return changed, nil, nil
+}
+
+// importedFields are the columns every bulk import line writes (recorded as the revision's changed_fields)
+// flip_id and weight are added for the lines that set them.
+var importedFields = []string{
+	"name", "description", "difficulty", "execution_notes",
+	"takeoff_stance_id", "landing_stance_id", "attribution", "license",
+}
+
+// ImportedTrick is one trick of an import batch
+// SuggestedFlip marks a FlipID picked by the category suggester rather than given
+// in the import: it only fills an empty flip_id, it never replaces one.
+// FormerName is set when the line renames an existing trick: its current name
+// (and that name's alias slug), kept as a former-name alias.
+type ImportedTrick struct {
+	models.Trick
+	SuggestedFlip  bool
+	FormerName     string
+	FormerNameSlug string
+}
+
+// ImportOutcome is what happened to one imported trick
+// FlipID is the trick's category after the write (nil for skipped tricks).
+type ImportOutcome struct {
+	Status string // models.ImportCreated, ImportUpdated or ImportSkipped
+	FlipID *int
+}
+
+// UpsertImported creates or overwrites a batch of imported tricks, matched by slug
+// Returns one outcome per trick, in input order: models.ImportCreated,
+// ImportUpdated, or ImportSkipped for a slug that belongs to a deleted trick
+// (an import must not silently bring it back).
+//
+// flip_id is only overwritten by a category the import gave explicitly; without
+// one an existing trick keeps its own, and a suggested one only fills a NULL.
+// Weight is only written when set (non-zero): a new trick otherwise gets the
+// column default and an existing one keeps its curated weight. It's a separate
+// UPDATE queued right after the trick's upsert, since the INSERT can't fall back
+// to the default for a NULL parameter.
+//
+// A rename (FormerName set) records the old name as a former-name alias in the
+// same statement - only if the trick still has that name, and skipped when the
+// name is taken by another trick or alias. Renaming a trick back to one of its
+// former names drops that former name. The alias lock (see alias_repository.go)
+// is held for the whole batch, since new trick names must not race alias writes.
+//
+// The batch is one transaction - either every row lands or none do. Each
+// written row also gets a trick_revisions entry, like any other catalog edit.
+// ON CONFLICT (slug) relies on the tricks_slug_key unique constraint.
+func (r *TrickRepository) UpsertImported(ctx context.Context, tricks []ImportedTrick, changedBy *uuid.UUID) ([]ImportOutcome, error) {
+	if len(tricks) == 0 {
+		return nil, nil
+	}
+
+	// xmax = 0 only for a freshly inserted row; the conflict WHERE leaves deleted tricks alone
+	// Every CTE reads the same snapshot, so "former" and "restored" see the name before the update
+	query := `
+		WITH former AS (
+			INSERT INTO trick_data.trick_aliases (trick_id, alias, slug, former_name)
+			SELECT t.id, $14, $15, TRUE
+			FROM trick_data.tricks t
+			WHERE $14 <> '' AND t.slug = $1 AND t.deleted_at IS NULL AND t.name = $14
+				AND NOT EXISTS (
+					SELECT 1 FROM trick_data.tricks o
+					WHERE o.id <> t.id AND ((lower(o.name) = lower($14) AND o.status <> 'rejected') OR o.slug = $15)
+				)
+			ON CONFLICT DO NOTHING
+		), restored AS (
+			DELETE FROM trick_data.trick_aliases a
+			USING trick_data.tricks t
+			WHERE t.slug = $1 AND t.deleted_at IS NULL AND a.trick_id = t.id
+				AND a.former_name AND lower(a.alias) = lower($2)
+		), upserted AS (
+			INSERT INTO trick_data.tricks
+				(slug, name, description, difficulty, execution_notes,
+				 takeoff_stance_id, landing_stance_id, attribution, license, created_by, flip_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $12)
+			ON CONFLICT (slug) DO UPDATE SET
+				name = EXCLUDED.name,
+				description = EXCLUDED.description,
+				difficulty = EXCLUDED.difficulty,
+				execution_notes = EXCLUDED.execution_notes,
+				takeoff_stance_id = EXCLUDED.takeoff_stance_id,
+				landing_stance_id = EXCLUDED.landing_stance_id,
+				attribution = EXCLUDED.attribution,
+				license = EXCLUDED.license,
+				flip_id = CASE WHEN $13 THEN COALESCE(trick_data.tricks.flip_id, EXCLUDED.flip_id)
+					ELSE COALESCE(EXCLUDED.flip_id, trick_data.tricks.flip_id) END,
+				updated_at = NOW()
+			WHERE trick_data.tricks.deleted_at IS NULL
+			RETURNING id, xmax = 0 AS inserted, flip_id
+		), revision AS (
+			INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by)
+			SELECT id, $11, $10 FROM upserted
+		)
+		SELECT inserted, flip_id FROM upserted
+	`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, aliasLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock aliases: %w", err)
+	}
+
+	slugs := make([]string, len(tricks))
+	for i, trick := range tricks {
+		slugs[i] = trick.Slug
+	}
+	before, err := trickAuditStates(ctx, tx, slugs)
+	if err != nil {
+		return nil, err
+	}
+
+	weightQuery := `
+		UPDATE trick_data.tricks SET weight = $2
+		WHERE slug = $1 AND deleted_at IS NULL
+	`
+
+	batch := &pgx.Batch{}
+	for _, trick := range tricks {
+		fields := slices.Clip(importedFields)
+		if trick.FlipID != nil {
+			fields = append(fields, "flip_id")
+		}
+		if trick.Weight != 0 {
+			fields = append(fields, "weight")
+		}
+		batch.Queue(query,
+			trick.Slug, trick.Name, trick.Description, trick.Difficulty, trick.ExecutionNotes,
+			trick.TakeoffStanceID, trick.LandingStanceID, trick.Attribution, trick.License,
+			changedBy, fields, trick.FlipID, trick.SuggestedFlip,
+			trick.FormerName, trick.FormerNameSlug,
+		)
+		if trick.Weight != 0 {
+			batch.Queue(weightQuery, trick.Slug, trick.Weight)
+		}
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	outcomes := make([]ImportOutcome, len(tricks))
+	for i, trick := range tricks {
+		var inserted bool
+		err := results.QueryRow().Scan(&inserted, &outcomes[i].FlipID)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			outcomes[i].Status = models.ImportSkipped
+		case err != nil:
+			results.Close()
+			return nil, fmt.Errorf("failed to import trick %s: %w", trick.Slug, uniqueViolation(err))
+		case inserted:
+			outcomes[i].Status = models.ImportCreated
+		default:
+			outcomes[i].Status = models.ImportUpdated
+		}
+
+		// A skipped (deleted) trick's weight update matches no row
+		if trick.Weight != 0 {
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return nil, fmt.Errorf("failed to set weight of trick %s: %w", trick.Slug, err)
+			}
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("failed to import tricks: %w", err)
+	}
+
+	// New tricks are missing from before, so they're recorded as creates
+	after, err := trickAuditStates(ctx, tx, slugs)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditUpdate, before, after, changedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return outcomes, nil
+}
+
+// Insert creates a trick with the given review status (models.TrickPending or
+// TrickApproved), slugged trick.Slug or - when that's taken - trick.Slug-2, -3, ...
+// Trick slugs (deleted tricks' too, they come back on restore) and alias slugs both
+// count as taken. On success trick.ID/Slug, Weight and the timestamps are set
+// from the new row. Errors: ErrNameTaken (case-insensitive, against trick names
+// and aliases, like AliasRepository.Add), ErrUnknownReference.
+//
+// The alias lock (see alias_repository.go) is held for the check and the insert,
+// which also writes the trick's first trick_revisions and trick_audit entries.
+func (r *TrickRepository) Insert(ctx context.Context, trick *models.Trick, status string, changedBy *uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, aliasLockKey); err != nil {
+		return fmt.Errorf("failed to lock aliases: %w", err)
+	}
+
+	if err := insertTrick(ctx, tx, trick, status, changedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// InsertBatch inserts tricks one after another in one transaction, as Insert
+// would each (same slugs, checks and revisions - earlier tricks of the batch
+// count when checking later ones). Returns one error per trick: nil,
+// ErrNameTaken or ErrUnknownReference. The batch is committed only when commit
+// is set and no trick failed - otherwise nothing is written, but the tricks'
+// slugs are still set to what they would have been (for a dry run).
+func (r *TrickRepository) InsertBatch(ctx context.Context, tricks []*models.Trick, changedBy *uuid.UUID, commit bool) ([]error, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, aliasLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock aliases: %w", err)
+	}
+
+	errs := make([]error, len(tricks))
+	failed := false
+	for i, trick := range tricks {
+		err := insertTrick(ctx, tx, trick, models.TrickApproved, changedBy)
+		switch {
+		case errors.Is(err, ErrNameTaken), errors.Is(err, ErrUnknownReference):
+			errs[i] = err
+			failed = true
+		case err != nil:
+			return nil, err
+		}
+	}
+
+	if commit && !failed {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+	return errs, nil
+}
+
+// insertTrick is Insert inside tx - the caller holds the alias lock
+func insertTrick(ctx context.Context, tx pgx.Tx, trick *models.Trick, status string, changedBy *uuid.UUID) error {
+	if err := checkReferences(ctx, tx, trick); err != nil {
+		return err
+	}
+
+	var taken bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM trick_data.tricks WHERE lower(name) = lower($1) AND status <> 'rejected'
+		) OR EXISTS (
+			SELECT 1 FROM trick_data.trick_aliases WHERE lower(alias) = lower($1)
+		)`,
+		trick.Name,
+	).Scan(&taken)
+	if err != nil {
+		return fmt.Errorf("failed to check trick name %s: %w", trick.Name, err)
+	}
+	if taken {
+		return ErrNameTaken
+	}
+
+	slug, err := freeSlug(ctx, tx, trick.Slug)
+	if err != nil {
+		return err
+	}
+
+	fields := []string{"name", "difficulty"}
+	for _, field := range []struct {
+		name string
+		set  bool
+	}{
+		{"description", trick.Description != nil},
+		{"execution_notes", trick.ExecutionNotes != nil},
+		{"takeoff_stance_id", trick.TakeoffStanceID != nil},
+		{"landing_stance_id", trick.LandingStanceID != nil},
+		{"flip_id", trick.FlipID != nil},
+		{"rotation", trick.Rotation != nil},
+		{"attribution", trick.Attribution != nil},
+		{"license", trick.License != nil},
+	} {
+		if field.set {
+			fields = append(fields, field.name)
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		WITH inserted AS (
+			INSERT INTO trick_data.tricks
+				(slug, name, description, difficulty, execution_notes,
+				 takeoff_stance_id, landing_stance_id, flip_id, rotation, created_by, status,
+				 attribution, license)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $12, $13, $14)
+			RETURNING id, slug, weight, created_at, updated_at
+		), revision AS (
+			INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by)
+			SELECT id, $11, $10 FROM inserted
+		)
+		SELECT slug, weight, created_at, updated_at FROM inserted`,
+		slug, trick.Name, trick.Description, trick.Difficulty, trick.ExecutionNotes,
+		trick.TakeoffStanceID, trick.LandingStanceID, trick.FlipID, trick.Rotation, changedBy,
+		fields, status, trick.Attribution, trick.License,
+	).Scan(&trick.Slug, &trick.Weight, &trick.CreatedAt, &trick.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert trick %s: %w", slug, uniqueViolation(err))
+	}
+	trick.ID = trick.Slug
+
+	after, err := trickAuditStates(ctx, tx, []string{trick.Slug})
+	if err != nil {
+		return err
+	}
+	return recordTrickAudit(ctx, tx, TrickAuditCreate, nil, after, changedBy)
+}
+
+// TrickUpdate is a trick's full new state for Update
+// ChangedFields are recorded on the revision ("slug" is added when it moves).
+// FormerName is set when the update renames the trick: its current name, kept
+// as a former-name alias like UpsertImported does. SlugBase, when set, is the
+// slug to move the trick to (suffixed like Insert if taken) - "" keeps the slug.
+type TrickUpdate struct {
+	models.Trick
+	ChangedFields  []string
+	FormerName     string
+	FormerNameSlug string
+	SlugBase       string
+}
+
+// Update overwrites a live trick's name, texts, difficulty, stances, flip, rotation
+// and attribution/license
+// Returns the trick as GetByID reads it after the write. Errors: ErrNotFound,
+// ErrNameTaken (another trick's name, or any alias other than the trick's own
+// former names - renaming back to one drops it), ErrUnknownReference.
+//
+// When the slug moves, the former-name alias takes the old slug, so links to
+// it keep resolving. Checks and writes run under the alias lock, like Insert.
+//
+// updated_at always moves to a later second than the trick's last change:
+// ETags are whole Unix seconds, and a second write within the same second
+// would otherwise keep the ETag a client cached after the first.
+func (r *TrickRepository) Update(ctx context.Context, slug string, update TrickUpdate, changedBy *uuid.UUID) (*models.Trick, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, aliasLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock aliases: %w", err)
+	}
+
+	var (
+		trickID     int
+		currentName string
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT id, name FROM trick_data.tricks
+		WHERE slug = $1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		slug,
+	).Scan(&trickID, &currentName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick %s: %w", slug, err)
+	}
+
+	before, err := trickAuditStates(ctx, tx, []string{slug})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReferences(ctx, tx, &update.Trick); err != nil {
+		return nil, err
+	}
+	if err := claimName(ctx, tx, trickID, update.Name); err != nil {
+		return nil, err
+	}
+
+	newSlug := slug
+	if update.SlugBase != "" && update.SlugBase != slug {
+		if newSlug, err = freeSlug(ctx, tx, update.SlugBase); err != nil {
+			return nil, err
+		}
+	}
+	fields := slices.Clip(update.ChangedFields)
+	if newSlug != slug {
+		fields = append(fields, "slug")
+	}
+
+	// Kept only if the trick still has that name
+	formerSlug := update.FormerNameSlug
+	if newSlug != slug {
+		formerSlug = slug
+	}
+	if update.FormerName != "" && update.FormerName == currentName {
+		if err := keepFormerName(ctx, tx, trickID, update.FormerName, formerSlug); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		WITH updated AS (
+			UPDATE trick_data.tricks SET
+				slug = $2,
+				name = $3,
+				description = $4,
+				difficulty = $5,
+				execution_notes = $6,
+				takeoff_stance_id = $7,
+				landing_stance_id = $8,
+				flip_id = $9,
+				rotation = $10,
+				attribution = $11,
+				license = $12,
+				updated_at = GREATEST(NOW(),
+					date_trunc('second', GREATEST(created_at, updated_at)) + INTERVAL '1 second')
+			WHERE id = $1
+			RETURNING id
+		)
+		INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by)
+		SELECT id, $13, $14 FROM updated`,
+		trickID, newSlug, update.Name, update.Description, update.Difficulty, update.ExecutionNotes,
+		update.TakeoffStanceID, update.LandingStanceID, update.FlipID, update.Rotation,
+		update.Attribution, update.License, fields, changedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update trick %s: %w", slug, uniqueViolation(err))
+	}
+
+	after, err := trickAuditStates(ctx, tx, []string{newSlug})
+	if err != nil {
+		return nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditUpdate, before, after, changedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetByID(ctx, newSlug)
+}
+
+// TrickPatch is a partial update for Patch: only Columns are written, with
+// their values taken from Trick. Columns are tricks columns (name, difficulty,
+// description, execution_notes, takeoff_stance_id, landing_stance_id, flip_id,
+// rotation, attribution, license). The other fields are as in TrickUpdate - a patch never moves the slug.
+type TrickPatch struct {
+	models.Trick
+	Columns        []string
+	ChangedFields  []string
+	FormerName     string
+	FormerNameSlug string
+}
+
+// Patch writes some of a live trick's columns, leaving the rest as they are
+// A nil value in Trick clears its column. Errors and locking are as in Update;
+// stance and flip IDs are checked only when patched.
+func (r *TrickRepository) Patch(ctx context.Context, slug string, patch TrickPatch, changedBy *uuid.UUID) (*models.Trick, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, aliasLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock aliases: %w", err)
+	}
+
+	var (
+		trickID     int
+		currentName string
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT id, name FROM trick_data.tricks
+		WHERE slug = $1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		slug,
+	).Scan(&trickID, &currentName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick %s: %w", slug, err)
+	}
+
+	before, err := trickAuditStates(ctx, tx, []string{slug})
+	if err != nil {
+		return nil, err
+	}
+
+	// args holds the parameter values in order ($1 is the trick's id)
+	set := ""
+	args := []interface{}{trickID}
+	argPosition := 2 // Tracks which $N we're on
+	references := models.Trick{}
+	for _, column := range patch.Columns {
+		var value interface{}
+		switch column {
+		case "name":
+			value = patch.Name
+			if err := claimName(ctx, tx, trickID, patch.Name); err != nil {
+				return nil, err
+			}
+		case "difficulty":
+			value = patch.Difficulty
+		case "description":
+			value = patch.Description
+		case "execution_notes":
+			value = patch.ExecutionNotes
+		case "takeoff_stance_id":
+			value, references.TakeoffStanceID = patch.TakeoffStanceID, patch.TakeoffStanceID
+		case "landing_stance_id":
+			value, references.LandingStanceID = patch.LandingStanceID, patch.LandingStanceID
+		case "flip_id":
+			value, references.FlipID = patch.FlipID, patch.FlipID
+		case "rotation":
+			value = patch.Rotation
+		case "attribution":
+			value = patch.Attribution
+		case "license":
+			value = patch.License
+		default:
+			return nil, fmt.Errorf("column %q can't be patched", column)
+		}
+		set += fmt.Sprintf("%s = $%d, ", column, argPosition)
+		args = append(args, value)
+		argPosition++
+	}
+	if err := checkReferences(ctx, tx, &references); err != nil {
+		return nil, err
+	}
+
+	if patch.FormerName != "" && patch.FormerName == currentName {
+		if err := keepFormerName(ctx, tx, trickID, patch.FormerName, patch.FormerNameSlug); err != nil {
+			return nil, err
+		}
+	}
+
+	// updated_at moves to a later second, as in Update
+	query := fmt.Sprintf(`
+		WITH updated AS (
+			UPDATE trick_data.tricks SET
+				%supdated_at = GREATEST(NOW(),
+					date_trunc('second', GREATEST(created_at, updated_at)) + INTERVAL '1 second')
+			WHERE id = $1
+			RETURNING id
+		)
+		INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by)
+		SELECT id, $%d, $%d FROM updated`,
+		set, argPosition, argPosition+1,
+	)
+	args = append(args, patch.ChangedFields, changedBy)
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to patch trick %s: %w", slug, uniqueViolation(err))
+	}
+
+	after, err := trickAuditStates(ctx, tx, []string{slug})
+	if err != nil {
+		return nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditUpdate, before, after, changedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetByID(ctx, slug)
+}
+
+// ExistsByName reports whether name (any case) is taken for the trick slugged exceptSlug
+// Taken like claimName: another trick's name (deleted tricks' too, rejected ones not) or an alias,
+// other than the trick's own former names. An empty exceptSlug is a new trick.
+func (r *TrickRepository) ExistsByName(ctx context.Context, name, exceptSlug string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM trick_data.tricks
+			WHERE lower(name) = lower($1) AND slug <> $2 AND status <> 'rejected'
+		) OR EXISTS (
+			SELECT 1 FROM trick_data.trick_aliases a
+			WHERE lower(a.alias) = lower($1)
+				AND NOT (a.former_name AND a.trick_id IN (SELECT id FROM trick_data.tricks WHERE slug = $2))
+		)
+	`
+
+	var taken bool
+	if err := r.pool.QueryRow(ctx, query, name, exceptSlug).Scan(&taken); err != nil {
+		return false, fmt.Errorf("failed to check trick name %s: %w", name, err)
+	}
+	return taken, nil
+}
+
+// ExistsBySlug reports whether slug (any case) is taken for the trick slugged exceptSlug
+// Taken means another trick's slug (deleted tricks' too, they come back on
+// restore) or an alias slug - either way, GET /tricks/:id would be ambiguous.
+func (r *TrickRepository) ExistsBySlug(ctx context.Context, slug, exceptSlug string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM trick_data.tricks WHERE lower(slug) = lower($1) AND slug <> $2
+		) OR EXISTS (
+			SELECT 1 FROM trick_data.trick_aliases WHERE slug = lower($1)
+		)
+	`
+
+	var taken bool
+	if err := r.pool.QueryRow(ctx, query, slug, exceptSlug).Scan(&taken); err != nil {
+		return false, fmt.Errorf("failed to check trick slug %s: %w", slug, err)
+	}
+	return taken, nil
+}
+
+// uniqueViolation turns a unique violation on a trick's name or slug into
+// ErrNameTaken / ErrSlugTaken - a write that raced past the checks above
+// Any other error comes back as is.
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" { // unique_violation
+		return err
+	}
+	switch pgErr.ConstraintName {
+	case "tricks_lower_name":
+		return ErrNameTaken
+	case "tricks_slug_key", "tricks_lower_slug", "trick_aliases_slug":
+		return ErrSlugTaken
+	}
+	return err
+}
+
+// claimName checks that name is free for the trick (ErrNameTaken if not)
+// Free means no other trick has it (rejected submissions aside) and no alias
+// does, except the trick's own former names - renaming back to one drops it, since it's the name again.
+// The caller holds the alias lock.
+func claimName(ctx context.Context, tx pgx.Tx, trickID int, name string) error {
+	var taken bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM trick_data.tricks
+			WHERE id <> $2 AND lower(name) = lower($1) AND status <> 'rejected'
+		) OR EXISTS (
+			SELECT 1 FROM trick_data.trick_aliases
+			WHERE lower(alias) = lower($1) AND NOT (trick_id = $2 AND former_name)
+		)`,
+		name, trickID,
+	).Scan(&taken)
+	if err != nil {
+		return fmt.Errorf("failed to check trick name %s: %w", name, err)
+	}
+	if taken {
+		return ErrNameTaken
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM trick_data.trick_aliases
+		WHERE trick_id = $1 AND former_name AND lower(alias) = lower($2)`,
+		trickID, name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to drop former name %s: %w", name, err)
+	}
+	return nil
+}
+
+// keepFormerName adds a renamed trick's old name as a former-name alias
+// Kept only if nothing else claims the name or slug (as in UpsertImported).
+func keepFormerName(ctx context.Context, tx pgx.Tx, trickID int, name, slug string) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO trick_data.trick_aliases (trick_id, alias, slug, former_name)
+		SELECT $1, $2, $3, TRUE
+		WHERE NOT EXISTS (
+			SELECT 1 FROM trick_data.tricks o
+			WHERE o.id <> $1 AND ((lower(o.name) = lower($2) AND o.status <> 'rejected') OR o.slug = $3)
+		)
+		ON CONFLICT DO NOTHING`,
+		trickID, name, slug,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to keep former name %s: %w", name, err)
+	}
+	return nil
+}
+
+// freeSlug returns base if no trick or alias has it, else the first free base-N (N >= 2)
+func freeSlug(ctx context.Context, tx pgx.Tx, base string) (string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT slug FROM trick_data.tricks WHERE slug = $1 OR slug LIKE $2
+		UNION
+		SELECT slug FROM trick_data.trick_aliases WHERE slug = $1 OR slug LIKE $2`,
+		base, escapeLike(base)+"-%",
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to check slug %s: %w", base, err)
+	}
+	slugs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", fmt.Errorf("failed to collect slugs: %w", err)
+	}
+
+	taken := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		taken[slug] = true
+	}
+	slug := base
+	for n := 2; taken[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return slug, nil
+}
+
+// checkReferences returns ErrUnknownReference if a stance or flip ID the trick sets doesn't exist
+// Table names come from TrickReferences, never from input.
+func checkReferences(ctx context.Context, tx pgx.Tx, trick *models.Trick) error {
+	values := map[string]*int{
+		"takeoff_stance_id": trick.TakeoffStanceID,
+		"landing_stance_id": trick.LandingStanceID,
+		"flip_id":           trick.FlipID,
+	}
+	for _, ref := range TrickReferences {
+		id := values[ref.Field]
+		if id == nil {
+			continue
+		}
+		var exists bool
+		query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1)`, ref.Table)
+		if err := tx.QueryRow(ctx, query, *id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check %s %d: %w", ref.Field, *id, err)
+		}
+		if !exists {
+			return ErrUnknownReference
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// REFERENCE CONSISTENCY
+// =============================================================================
+// Not every reference from tricks has a foreign key (older rows predate them),
+// so these queries find and clear IDs that point at nothing.
+
+// TrickReference is one trick column that holds another table's ID
+type TrickReference struct {
+	Field string // Column on trick_data.tricks
+	Table string // Table whose id it refers to
+}
+
+// TrickReferences is every reference the consistency check verifies
+// Field and Table are formatted into SQL - they must only ever come from this list.
+// A new reference column (e.g. a mirror_trick_id -> trick_data.tricks) only needs an entry here.
+var TrickReferences = []TrickReference{
+	{Field: "takeoff_stance_id", Table: "trick_data.stances"},
+	{Field: "landing_stance_id", Table: "trick_data.stances"},
+	{Field: "flip_id", Table: "trick_data.categories"},
+}
+
+// FindBrokenReferences lists every live trick reference that doesn't resolve
+// One anti-join per reference, all in one query; ordered by trick, then field.
+func (r *TrickRepository) FindBrokenReferences(ctx context.Context) ([]models.BrokenReference, error) {
+	checks := make([]string, len(TrickReferences))
+	for i, ref := range TrickReferences {
+		checks[i] = fmt.Sprintf(`
+			SELECT t.slug, '%[1]s', t.%[1]s
+			FROM trick_data.tricks t
+			WHERE t.deleted_at IS NULL AND t.%[1]s IS NOT NULL
+				AND NOT EXISTS (SELECT 1 FROM %[2]s r WHERE r.id = t.%[1]s)`,
+			ref.Field, ref.Table)
+	}
+	query := strings.Join(checks, "\n\t\t\tUNION ALL") + "\n\t\tORDER BY 1, 2"
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query broken trick references: %w", err)
+	}
+
+	broken, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.BrokenReference])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect broken trick references: %w", err)
+	}
+
+	return broken, nil
+}
+
+// ClearBrokenReferences sets up to batchSize dangling values of one field to NULL
+// Each cleared trick gets a trick_revisions and a trick_audit entry in the same
+// transaction. Returns the slugs of the tricks it changed; callers loop until
+// fewer than batchSize come back.
+// field must be one of TrickReferences - anything else is an error, not SQL.
+func (r *TrickRepository) ClearBrokenReferences(ctx context.Context, field string, batchSize int, changedBy *uuid.UUID) ([]string, error) {
+	var ref *TrickReference
+	for i := range TrickReferences {
+		if TrickReferences[i].Field == field {
+			ref = &TrickReferences[i]
+		}
+	}
+	if ref == nil {
+		return nil, fmt.Errorf("unknown trick reference %q", field)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// SKIP LOCKED: rows someone is editing right now are left for the next batch or run
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT t.slug
+		FROM trick_data.tricks t
+		WHERE t.deleted_at IS NULL AND t.%[1]s IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM %[2]s r WHERE r.id = t.%[1]s)
+		ORDER BY t.id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, ref.Field, ref.Table),
+		batchSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find broken %s references: %w", field, err)
+	}
+	slugs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect broken %s references: %w", field, err)
+	}
+	if len(slugs) == 0 {
+		return slugs, nil
+	}
+
+	before, err := trickAuditStates(ctx, tx, slugs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The rows are locked since the SELECT, so they still dangle
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		WITH cleared AS (
+			UPDATE trick_data.tricks
+			SET %s = NULL, updated_at = NOW()
+			WHERE slug = ANY($1)
+			RETURNING id
+		)
+		INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by)
+		SELECT id, $2, $3 FROM cleared`, ref.Field),
+		slugs, []string{ref.Field}, changedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear broken %s references: %w", field, err)
+	}
+
+	after, err := trickAuditStates(ctx, tx, slugs)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditUpdate, before, after, changedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return slugs, nil
+}
+
+// =============================================================================
+// AUDIT HISTORY
+// =============================================================================
+// Who changed what on a trick, with the old and new values - trick_revisions
+// only knows which columns changed. Written in the same transaction as the
+// edit it describes:
+//
+//	CREATE TABLE trick_data.trick_audit (
+//	    id         BIGSERIAL PRIMARY KEY,
+//	    trick_id   INTEGER NOT NULL,  -- No FK: history outlives a purge
+//	    trick_slug TEXT NOT NULL,     -- The slug after the write
+//	    actor_id   UUID,
+//	    action     TEXT NOT NULL,     -- create, update, delete, restore, approve or reject
+//	    diff       JSONB NOT NULL,    -- {"difficulty": {"old": 5, "new": 6}, ...}
+//	    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+//	);
+//	CREATE INDEX trick_audit_trick_id ON trick_data.trick_audit (trick_id, id DESC);
+//
+// Each write path reads the tricks' audited columns before and after the write
+// (trickAuditStates) and records the difference (recordTrickAudit). A write that
+// changes none of them - an import line repeating the current values - leaves
+// no entry. Clearing broken references (POST /admin/consistency?fix=null_out)
+// is audited like any edit; only the background weight decay isn't - its
+// revisions say what it touched.
+
+// Trick audit actions
+const (
+	TrickAuditCreate  = "create"
+	TrickAuditUpdate  = "update"
+	TrickAuditDelete  = "delete"
+	TrickAuditRestore = "restore"
+	TrickAuditApprove = "approve"
+	TrickAuditReject  = "reject"
+)
+
+// trickAuditColumns are the columns whose changes are audited, as one jsonb object
+const trickAuditColumns = `jsonb_build_object(
+	'slug', slug, 'name', name, 'description', description, 'difficulty', difficulty,
+	'execution_notes', execution_notes, 'takeoff_stance_id', takeoff_stance_id,
+	'landing_stance_id', landing_stance_id, 'flip_id', flip_id, 'rotation', rotation,
+	'weight', weight, 'attribution', attribution, 'license', license, 'deleted_at', deleted_at,
+	'status', status, 'approved_by', approved_by, 'rejection_reason', rejection_reason)`
+
+// trickAuditState is a trick's audited columns at one point of a transaction
+type trickAuditState struct {
+	Slug   string
+	Fields map[string]json.RawMessage
+}
+
+// trickAuditStates reads the audited columns of the tricks slugged slugs, keyed by trick ID
+// The rows are locked, so nothing else changes them before the transaction ends.
+func trickAuditStates(ctx context.Context, tx pgx.Tx, slugs []string) (map[int]trickAuditState, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id, slug, `+trickAuditColumns+` FROM trick_data.tricks WHERE slug = ANY($1) FOR UPDATE`,
+		slugs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trick audit state: %w", err)
+	}
+
+	states := make(map[int]trickAuditState, len(slugs))
+	var (
+		id    int
+		state trickAuditState
+	)
+	_, err = pgx.ForEachRow(rows, []any{&id, &state.Slug, &state.Fields}, func() error {
+		states[id] = state
+		state = trickAuditState{}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect trick audit state: %w", err)
+	}
+	return states, nil
+}
+
+// recordTrickAudit writes an audit entry for each trick whose audited columns
+// differ between before and after (trickAuditStates around the write)
+// A trick missing from before was created by the write, whatever action says.
+func recordTrickAudit(ctx context.Context, tx pgx.Tx, action string, before, after map[int]trickAuditState, actorID *uuid.UUID) error {
+	var (
+		ids     []int
+		slugs   []string
+		actions []string
+		diffs   []string
+	)
+	for id, next := range after {
+		previous, existed := before[id]
+		diff := trickAuditDiff(previous.Fields, next.Fields)
+		if len(diff) == 0 {
+			continue
+		}
+		encoded, err := json.Marshal(diff)
+		if err != nil {
+			return fmt.Errorf("failed to encode trick audit diff: %w", err)
+		}
+
+		ids = append(ids, id)
+		slugs = append(slugs, next.Slug)
+		diffs = append(diffs, string(encoded))
+		if existed {
+			actions = append(actions, action)
+		} else {
+			actions = append(actions, TrickAuditCreate)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO trick_data.trick_audit (trick_id, trick_slug, actor_id, action, diff)
+		SELECT id, slug, $5, action, diff::JSONB
+		FROM unnest($1::INTEGER[], $2::TEXT[], $3::TEXT[], $4::TEXT[]) AS a(id, slug, action, diff)`,
+		ids, slugs, actions, diffs, actorID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record trick audit: %w", err)
+	}
+	return nil
+}
+
+// trickAuditDiff pairs the old and new value of every field that differs
+// A field missing on one side (a created trick has no old values) is null there.
+// jsonb prints equal values identically, so comparing the text is enough.
+func trickAuditDiff(before, after map[string]json.RawMessage) map[string]models.TrickAuditChange {
+	null := json.RawMessage("null")
+	diff := map[string]models.TrickAuditChange{}
+	for field, next := range after {
+		previous, ok := before[field]
+		if !ok {
+			previous = null
+		}
+		if !bytes.Equal(previous, next) {
+			diff[field] = models.TrickAuditChange{Old: previous, New: next}
+		}
+	}
+	return diff
+}
+
+// FindHistory returns a page of the audit entries of the trick slugged slug, newest
+// first, and how many there are in all. Deleted tricks have history too.
+// Returns ErrNotFound if no trick has that slug.
+func (r *TrickRepository) FindHistory(ctx context.Context, slug string, limit, offset int) ([]models.TrickAuditEntry, int64, error) {
+	var trickID int
+	err := r.pool.QueryRow(ctx, `SELECT id FROM trick_data.tricks WHERE slug = $1`, slug).Scan(&trickID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, 0, ErrNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to get trick %s: %w", slug, err)
+	}
+
+	var total int64
+	err = r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM trick_data.trick_audit WHERE trick_id = $1`,
+		trickID,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count history of trick %s: %w", slug, err)
+	}
+
+	// Newest first by id - created_at is the transaction's start, so it can tie
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, trick_slug, actor_id, action, diff, created_at
+		FROM trick_data.trick_audit
+		WHERE trick_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`,
+		trickID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query history of trick %s: %w", slug, err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickAuditEntry])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collect history of trick %s: %w", slug, err)
+	}
+	return entries, total, nil
+}
+
+// =============================================================================
+// SUBMISSION REVIEW
+// =============================================================================
+// Tricks submitted by regular users wait for an admin before they join the
+// catalog. Every public read filters on status = 'approved':
+//
+//	ALTER TABLE trick_data.tricks
+//	    ADD COLUMN status TEXT NOT NULL DEFAULT 'approved'
+//	        CHECK (status IN ('pending', 'approved', 'rejected')),
+//	    ADD COLUMN approved_by UUID,
+//	    ADD COLUMN rejection_reason TEXT,
+//	    ADD COLUMN reviewed_at TIMESTAMPTZ;
+//	CREATE INDEX tricks_review_queue ON trick_data.tricks (created_at) WHERE status = 'pending';
+//
+// A pending trick holds its name and slug, so the same trick can't be
+// submitted twice while it waits. A rejected one gives both up: name checks
+// skip rejected tricks (and tricks_lower_name leaves them out), and Review
+// moves a rejected trick to a slug no trick can have ("cork--rejected-42" -
+// generated and imported slugs never contain "--").
+
+// ErrNotPending indicates a review of a trick that isn't waiting for one
+var ErrNotPending = errors.New("trick is not pending review")
+
+// trickSubmissionColumns are the columns of models.TrickSubmission
+const trickSubmissionColumns = `slug AS id, name, description, difficulty, status, rejection_reason,
+	created_by, approved_by, created_at, reviewed_at`
+
+// FindPending returns a page of the tricks waiting for review, oldest first, and how many there are
+func (r *TrickRepository) FindPending(ctx context.Context, limit, offset int) ([]models.TrickSubmission, int64, error) {
+	var total int64
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM trick_data.tricks WHERE status = 'pending' AND deleted_at IS NULL`,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending tricks: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+trickSubmissionColumns+`
+		FROM trick_data.tricks
+		WHERE status = 'pending' AND deleted_at IS NULL
+		ORDER BY created_at, slug
+		LIMIT $1 OFFSET $2`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query pending tricks: %w", err)
+	}
+
+	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickSubmission])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collect pending tricks: %w", err)
+	}
+	return tricks, total, nil
+}
+
+// FindSubmittedBy returns the newest limit tricks a user created, whatever their status
+func (r *TrickRepository) FindSubmittedBy(ctx context.Context, userID uuid.UUID, limit int) ([]models.TrickSubmission, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+trickSubmissionColumns+`
+		FROM trick_data.tricks
+		WHERE created_by = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC, slug
+		LIMIT $2`,
+		userID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tricks submitted by %s: %w", userID, err)
+	}
+
+	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickSubmission])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect tricks submitted by %s: %w", userID, err)
+	}
+	return tricks, nil
+}
+
+// Review approves or rejects a pending trick (status is models.TrickApproved or TrickRejected)
+// Approval records reviewedBy as approved_by; reason is kept only on a rejection,
+// which also frees the trick's slug (see SUBMISSION REVIEW) - the returned
+// submission carries the new one.
+// updated_at moves to a later second, as in Update, so an approved trick's
+// ETags change and catalog deltas pick it up. Errors: ErrNotFound, ErrNotPending.
+func (r *TrickRepository) Review(ctx context.Context, slug, status string, reason *string, reviewedBy *uuid.UUID) (*models.TrickSubmission, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		trickID int
+		current string
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT id, status FROM trick_data.tricks
+		WHERE slug = $1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		slug,
+	).Scan(&trickID, &current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick %s: %w", slug, err)
+	}
+	if current != models.TrickPending {
+		return nil, ErrNotPending
+	}
+
+	before, err := trickAuditStates(ctx, tx, []string{slug})
+	if err != nil {
+		return nil, err
+	}
+
+	approved := status == models.TrickApproved
+	if approved {
+		reason = nil
+	}
+	rows, err := tx.Query(ctx, `
+		WITH reviewed AS (
+			UPDATE trick_data.tricks SET
+				status = $2,
+				slug = CASE WHEN $3 THEN slug ELSE slug || '--rejected-' || id END,
+				approved_by = CASE WHEN $3 THEN $4::UUID END,
+				rejection_reason = $5,
+				reviewed_at = NOW(),
+				updated_at = GREATEST(NOW(),
+					date_trunc('second', GREATEST(created_at, updated_at)) + INTERVAL '1 second')
+			WHERE id = $1
+			RETURNING *
+		), revision AS (
+			INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by)
+			SELECT id, CASE WHEN $3 THEN ARRAY['status'] ELSE ARRAY['status', 'slug'] END, $4 FROM reviewed
+		)
+		SELECT `+trickSubmissionColumns+` FROM reviewed`,
+		trickID, status, approved, reviewedBy, reason,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review trick %s: %w", slug, err)
+	}
+	reviewed, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.TrickSubmission])
+	if err != nil {
+		return nil, fmt.Errorf("failed to review trick %s: %w", slug, err)
+	}
+
+	action := TrickAuditReject
+	if approved {
+		action = TrickAuditApprove
+	}
+	after, err := trickAuditStates(ctx, tx, []string{reviewed.ID})
+	if err != nil {
+		return nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, action, before, after, reviewedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &reviewed, nil
+}
+
+// =============================================================================
+// MERGING DUPLICATES
+// =============================================================================
+// Folds a duplicate trick ("540" next to "540 kick") into the one to keep, in
+// one transaction: combo positions, videos and aliases move over, the
+// duplicate's name becomes an alias of the kept trick, and the duplicate is
+// soft-deleted like DeleteTrick. Restoring it later doesn't move anything back.
+
+// TrickMerge is the duplicate side of a merge
+// DuplicateName and DuplicateNameSlug are the alias to add - only added if the
+// duplicate still has that name when the merge runs (as TrickUpdate.FormerName).
+type TrickMerge struct {
+	DuplicateSlug     string
+	DuplicateName     string
+	DuplicateNameSlug string
+}
+
+// Merge moves a duplicate's combo positions, videos and aliases to the kept trick and deletes it
+// Both must be live and approved. Returns ErrNotFound if either isn't. A video whose URL the
+// kept trick already has stays with the duplicate, and moved videos lose
+// is_featured if the kept trick already has a featured one.
+func (r *TrickRepository) Merge(ctx context.Context, keepSlug string, merge TrickMerge, purgeAfter time.Duration, changedBy *uuid.UUID) (*models.TrickMergeResponse, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, aliasLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock aliases: %w", err)
+	}
+
+	// Both rows locked in id order, so two merges of the same pair can't deadlock
+	rows, err := tx.Query(ctx, `
+		SELECT id, slug, name FROM trick_data.tricks
+		WHERE slug = ANY($1) AND deleted_at IS NULL AND status = 'approved'
+		ORDER BY id
+		FOR UPDATE`,
+		[]string{keepSlug, merge.DuplicateSlug},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tricks to merge: %w", err)
+	}
+	var (
+		keepID, duplicateID int
+		duplicateName       string
+		id                  int
+		slug, name          string
+	)
+	_, err = pgx.ForEachRow(rows, []any{&id, &slug, &name}, func() error {
+		if slug == keepSlug {
+			keepID = id
+		} else {
+			duplicateID, duplicateName = id, name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tricks to merge: %w", err)
+	}
+	if keepID == 0 || duplicateID == 0 {
+		return nil, ErrNotFound
+	}
+
+	// None of the kept trick's audited columns change - only the duplicate is audited
+	before, err := trickAuditStates(ctx, tx, []string{merge.DuplicateSlug})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &models.TrickMergeResponse{ID: keepSlug, DuplicateID: merge.DuplicateSlug}
+
+	tag, err := tx.Exec(ctx, `UPDATE combo_tricks SET trick_id = $1 WHERE trick_id = $2`, keepID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move combo tricks: %w", err)
+	}
+	merged.ComboTricksMoved = tag.RowsAffected()
+
+	// Every part of the statement sees the videos as they were before the
+	// UPDATE, so the duplicate's count minus the moved ones is what stayed
+	err = tx.QueryRow(ctx, `
+		WITH moved AS (
+			UPDATE trick_data.trick_videos v SET
+				trick_id = $1,
+				updated_at = now(),
+				is_featured = v.is_featured AND NOT EXISTS (
+					SELECT 1 FROM trick_data.trick_videos k WHERE k.trick_id = $1 AND k.is_featured
+				)
+			WHERE v.trick_id = $2 AND NOT EXISTS (
+				SELECT 1 FROM trick_data.trick_videos k WHERE k.trick_id = $1 AND k.video_url = v.video_url
+			)
+			RETURNING 1
+		)
+		SELECT (SELECT COUNT(*) FROM moved),
+			(SELECT COUNT(*) FROM trick_data.trick_videos WHERE trick_id = $2) - (SELECT COUNT(*) FROM moved)`,
+		keepID, duplicateID,
+	).Scan(&merged.VideosMoved, &merged.VideosSkipped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move videos: %w", err)
+	}
+
+	tag, err = tx.Exec(ctx, `UPDATE trick_data.trick_aliases SET trick_id = $1 WHERE trick_id = $2`, keepID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move aliases: %w", err)
+	}
+	merged.AliasesMoved = tag.RowsAffected()
+
+	// The duplicate keeps its name (it could be restored), so this skips the
+	// name check Add makes - the alias unique indexes still apply
+	if merge.DuplicateName != "" && merge.DuplicateName == duplicateName {
+		tag, err = tx.Exec(ctx, `
+			INSERT INTO trick_data.trick_aliases (trick_id, alias, slug)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`,
+			keepID, merge.DuplicateName, merge.DuplicateNameSlug,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add alias %s: %w", merge.DuplicateName, err)
+		}
+		merged.AliasAdded = tag.RowsAffected() > 0
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE trick_data.tricks
+		SET deleted_at = NOW(), purge_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE id = $1
+		RETURNING deleted_at, purge_at`,
+		duplicateID, purgeAfter.Seconds(),
+	).Scan(&merged.DeletedAt, &merged.PurgeAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete trick %s: %w", merge.DuplicateSlug, err)
+	}
+
+	// The kept trick's videos and aliases changed - move its ETag on
+	_, err = tx.Exec(ctx, `
+		UPDATE trick_data.tricks
+		SET updated_at = GREATEST(NOW(), date_trunc('second', GREATEST(created_at, updated_at)) + INTERVAL '1 second')
+		WHERE id = $1`,
+		keepID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to touch trick %s: %w", keepSlug, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO trick_data.trick_revisions (trick_id, changed_fields, changed_by)
+		VALUES ($1, $2, $5), ($3, $4, $5)`,
+		keepID, []string{"videos", "aliases"}, duplicateID, []string{"deleted_at"}, changedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record merge revisions: %w", err)
+	}
+
+	after, err := trickAuditStates(ctx, tx, []string{merge.DuplicateSlug})
+	if err != nil {
+		return nil, err
+	}
+	if err := recordTrickAudit(ctx, tx, TrickAuditDelete, before, after, changedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return merged, nil
 }
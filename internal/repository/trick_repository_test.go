@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestUniqueViolation(t *testing.T) {
+	violation := func(constraint string) error {
+		return &pgconn.PgError{Code: "23505", ConstraintName: constraint}
+	}
+	otherErr := errors.New("connection reset")
+	foreignKey := &pgconn.PgError{Code: "23503", ConstraintName: "tricks_flip_id_fkey"}
+	otherUnique := violation("trick_videos_url_key")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "name index", err: violation("tricks_lower_name"), want: ErrNameTaken},
+		{name: "slug key", err: violation("tricks_slug_key"), want: ErrSlugTaken},
+		{name: "lowercase slug index", err: violation("tricks_lower_slug"), want: ErrSlugTaken},
+		{name: "alias slug", err: violation("trick_aliases_slug"), want: ErrSlugTaken},
+		{name: "wrapped", err: fmt.Errorf("failed to insert trick: %w", violation("tricks_lower_name")), want: ErrNameTaken},
+		{name: "another unique index", err: otherUnique, want: otherUnique},
+		{name: "not a unique violation", err: foreignKey, want: foreignKey},
+		{name: "not a Postgres error", err: otherErr, want: otherErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uniqueViolation(tt.err); got != tt.want {
+				t.Errorf("uniqueViolation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
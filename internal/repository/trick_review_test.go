@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+)
+
+// reviewFixture has one pending submission and one approved trick
+const reviewFixture = `
+	INSERT INTO trick_data.tricks (slug, name, status) VALUES
+		('cork', 'Cork', 'pending'),
+		('gainer', 'Gainer', 'approved');
+`
+
+func TestRejectedTrickFreesNameAndSlug(t *testing.T) {
+	repo := newScratchRepo(t, trickWriteSchema, reviewFixture)
+	ctx := context.Background()
+	admin := uuid.New()
+
+	// While it waits, the submission holds its name
+	if taken, err := repo.ExistsByName(ctx, "CORK", ""); err != nil || !taken {
+		t.Fatalf("ExistsByName(pending) = %v, %v, want taken", taken, err)
+	}
+	pendingCopy := models.Trick{Slug: "cork", Name: "Cork"}
+	if err := repo.Insert(ctx, &pendingCopy, models.TrickPending, &admin); !errors.Is(err, ErrNameTaken) {
+		t.Fatalf("Insert(name of a pending trick) error = %v, want ErrNameTaken", err)
+	}
+
+	rejected, err := repo.Review(ctx, "cork", models.TrickRejected, nil, &admin)
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if rejected.ID == "cork" || rejected.Status != models.TrickRejected {
+		t.Fatalf("rejected submission = %s (%s), want it off the cork slug", rejected.ID, rejected.Status)
+	}
+
+	if taken, err := repo.ExistsByName(ctx, "Cork", ""); err != nil || taken {
+		t.Errorf("ExistsByName(rejected) = %v, %v, want free", taken, err)
+	}
+	if taken, err := repo.ExistsBySlug(ctx, "cork", ""); err != nil || taken {
+		t.Errorf("ExistsBySlug(rejected) = %v, %v, want free", taken, err)
+	}
+
+	// A new submission gets both - the scoped index lets the name through
+	resubmitted := models.Trick{Slug: "cork", Name: "Cork"}
+	if err := repo.Insert(ctx, &resubmitted, models.TrickPending, &admin); err != nil {
+		t.Fatalf("Insert(name of a rejected trick) error = %v", err)
+	}
+	if resubmitted.Slug != "cork" {
+		t.Errorf("resubmitted slug = %s, want cork", resubmitted.Slug)
+	}
+
+	// Approved tricks keep theirs
+	approvedCopy := models.Trick{Slug: "gainer", Name: "gainer"}
+	if err := repo.Insert(ctx, &approvedCopy, models.TrickPending, &admin); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("Insert(name of an approved trick) error = %v, want ErrNameTaken", err)
+	}
+}
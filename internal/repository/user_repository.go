@@ -1,8 +1,23 @@
+// =============================================================================
+// TABLE STRUCTURE (need to create this):
+//
+// CREATE TABLE combo_attempts (
+//     id BIGSERIAL PRIMARY KEY,
+//     user_id UUID NOT NULL,
+//     combo_id BIGINT REFERENCES combos(id) ON DELETE SET NULL,  -- Kept when the combo is deleted
+//     landed BOOLEAN NOT NULL DEFAULT false,
+//     attempted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+// );
+//
+// CREATE INDEX combo_attempts_user_id_attempted_at ON combo_attempts (user_id, attempted_at);
+// =============================================================================
+
 package repository
 
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -14,7 +29,10 @@ import (
 // UserRepositoryInterface defines the contract for user data operations
 type UserRepositoryInterface interface {
 	GetCombosByUserID(ctx context.Context, userID uuid.UUID) ([]models.Combo, error)
-	GetComboTricks(ctx context.Context, comboID int64) ([]models.TrickSimpleResponse, error)
+	GetComboSummariesByUserID(ctx context.Context, userID uuid.UUID) ([]models.ComboSummary, error)
+	GetComboTricks(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error)
+	GetRecentTricks(ctx context.Context, userID uuid.UUID, limit int) ([]models.RecentTrickResponse, error)
+	GetAttemptDays(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.AttemptDay, error)
 	// GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	// GetPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error)
 }
@@ -32,7 +50,7 @@ func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 // GetCombosByUserID retrieves all combos for a specific user
 func (r *UserRepository) GetCombosByUserID(ctx context.Context, userID uuid.UUID) ([]models.Combo, error) {
 	query := `
-		SELECT id, user_id, name, created_at
+		SELECT id, user_id, name, notes, created_at, is_shared, is_featured
 		FROM combos
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -52,12 +70,45 @@ func (r *UserRepository) GetCombosByUserID(ctx context.Context, userID uuid.UUID
 	return combos, nil
 }
 
+// GetComboSummariesByUserID retrieves all combos for a user with aggregate columns
+// One query for the whole list - no per-combo trick loads
+func (r *UserRepository) GetComboSummariesByUserID(ctx context.Context, userID uuid.UUID) ([]models.ComboSummary, error) {
+	// LEFT JOINs keep combos that have no tricks (they get 0 for both aggregates)
+	// NULL difficulties count as 0, matching how the combo generator treats them
+	query := `
+		SELECT
+			c.id, c.user_id, c.name, c.notes, c.created_at, c.is_shared, c.is_featured,
+			COALESCE(SUM(COALESCE(t.difficulty, 0)), 0)::BIGINT AS total_difficulty,
+			COUNT(ct.trick_id) AS trick_count
+		FROM combos c
+		LEFT JOIN combo_tricks ct ON ct.combo_id = c.id
+		LEFT JOIN trick_data.tricks t ON t.id = ct.trick_id
+		WHERE c.user_id = $1
+		GROUP BY c.id
+		ORDER BY c.created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user combo summaries: %w", err)
+	}
+
+	// RowToStructByName fills the embedded Combo fields too
+	summaries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.ComboSummary])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect combo summary rows: %w", err)
+	}
+
+	return summaries, nil
+}
+
 // GetComboTricks retrieves all tricks for a specific combo, ordered by position
-func (r *UserRepository) GetComboTricks(ctx context.Context, comboID int64) ([]models.TrickSimpleResponse, error) {
+func (r *UserRepository) GetComboTricks(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error) {
 	query := `
-		SELECT t.id, t.name
+		SELECT t.slug AS id, t.id AS trick_id, t.slug, t.name, t.difficulty, ct.position, ct.note,
+			t.deleted_at IS NOT NULL AS deleted
 		FROM combo_tricks ct
-		JOIN tricks t ON ct.trick_id = t.id
+		JOIN trick_data.tricks t ON ct.trick_id = t.id
 		WHERE ct.combo_id = $1
 		ORDER BY ct.position ASC
 	`
@@ -67,17 +118,71 @@ func (r *UserRepository) GetComboTricks(ctx context.Context, comboID int64) ([]m
 		return nil, fmt.Errorf("failed to query combo tricks: %w", err)
 	}
 
-	// pgx.CollectRows with RowTo for simple structs without db tags
-	tricks, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[models.TrickSimpleResponse])
+	// pgx.CollectRows with RowToStructByPos for simple structs without db tags
+	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.ComboTrickResponse])
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect trick rows: %w", err)
 	}
 
-	// Convert from []*TrickSimpleResponse to []TrickSimpleResponse
-	result := make([]models.TrickSimpleResponse, len(tricks))
-	for i, t := range tricks {
-		result[i] = *t
+	return tricks, nil
+}
+
+// GetRecentTricks retrieves the tricks a user most recently used in their combos
+// Each trick appears once, most recently used first
+func (r *UserRepository) GetRecentTricks(ctx context.Context, userID uuid.UUID, limit int) ([]models.RecentTrickResponse, error) {
+	// DISTINCT ON keeps one row per trick - the ORDER BY makes it the newest combo's row
+	// The outer query then re-sorts those rows by recency and applies the limit
+	query := `
+		SELECT id, slug, name, difficulty, last_used_at
+		FROM (
+			SELECT DISTINCT ON (t.slug)
+				t.slug AS id, t.slug, t.name, t.difficulty, c.created_at AS last_used_at
+			FROM combos c
+			JOIN combo_tricks ct ON ct.combo_id = c.id
+			JOIN trick_data.tricks t ON t.id = ct.trick_id
+			WHERE c.user_id = $1
+			ORDER BY t.slug, c.created_at DESC
+		) recent
+		ORDER BY last_used_at DESC, name ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent tricks: %w", err)
+	}
+
+	tricks, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.RecentTrickResponse])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect recent trick rows: %w", err)
+	}
+
+	return tricks, nil
+}
+
+// GetAttemptDays counts a user's combo attempts per UTC day in [from, to)
+// Only days with at least one attempt are returned, oldest first.
+func (r *UserRepository) GetAttemptDays(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.AttemptDay, error) {
+	query := `
+		SELECT
+			date_trunc('day', attempted_at AT TIME ZONE 'UTC') AS day,
+			COUNT(*) AS attempts,
+			COUNT(*) FILTER (WHERE landed) AS landed
+		FROM combo_attempts
+		WHERE user_id = $1 AND attempted_at >= $2 AND attempted_at < $3
+		GROUP BY day
+		ORDER BY day
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attempt days: %w", err)
+	}
+
+	days, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AttemptDay])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect attempt day rows: %w", err)
 	}
 
-	return result, nil
+	return days, nil
 }
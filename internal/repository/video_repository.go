@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -10,10 +12,57 @@ import (
 	"tricking-api/internal/models"
 )
 
+// =============================================================================
+// TABLE STRUCTURE (attribution columns - need to create these):
+//
+// ALTER TABLE trick_data.tricks ADD COLUMN attribution TEXT, ADD COLUMN license TEXT;
+// ALTER TABLE trick_data.trick_videos ADD COLUMN attribution TEXT, ADD COLUMN license TEXT;
+//
+// (availability checking)
+// ALTER TABLE trick_data.trick_videos
+//     ADD COLUMN availability TEXT NOT NULL DEFAULT 'unknown',  -- available, unavailable, unknown
+//     ADD COLUMN last_checked_at TIMESTAMPTZ;
+//
+// (featured-video lookups)
+// CREATE INDEX trick_videos_trick_id_featured ON trick_data.trick_videos (trick_id) WHERE is_featured;
+//
+// (video flags on the trick list)
+// CREATE INDEX trick_videos_trick_id ON trick_data.trick_videos (trick_id);
+//
+// (captions and duplicate protection - remove existing duplicates before the index)
+// ALTER TABLE trick_data.trick_videos ADD COLUMN label TEXT;
+// CREATE UNIQUE INDEX trick_videos_trick_id_url ON trick_data.trick_videos (trick_id, video_url);
+//
+// (catalog diff - NULL until the video is first edited; availability checks don't count)
+// ALTER TABLE trick_data.trick_videos ADD COLUMN updated_at TIMESTAMPTZ;
+// CREATE INDEX trick_videos_updated_at ON trick_data.trick_videos (updated_at) WHERE updated_at IS NOT NULL;
+// =============================================================================
+
+// ErrDuplicateVideo is returned when a trick already has a video with the same URL
+var ErrDuplicateVideo = errors.New("trick already has a video with this URL")
+
+// VideoKey identifies a video by trick and URL - the unique key of trick_videos
+type VideoKey struct {
+	TrickID int
+	URL     string
+}
+
 // VideoRepositoryInterface defines the contract for video data operations
 type VideoRepositoryInterface interface {
 	FindByTrickID(ctx context.Context, trickID string) ([]models.TrickVideo, error)
 	GetFeaturedByTrickID(ctx context.Context, trickID string) (*models.TrickVideo, error)
+	FindAll(ctx context.Context) ([]models.TrickVideo, error)
+	UpdateExternalFields(ctx context.Context, video *models.TrickVideo) error
+	GetByID(ctx context.Context, id int64) (*models.TrickVideo, error)
+	FindDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]models.TrickVideo, error)
+	UpdateAvailability(ctx context.Context, id int64, availability string) (time.Time, error)
+	Create(ctx context.Context, trickSlug string, video *models.TrickVideo) error
+	GetTrickSlug(ctx context.Context, trickID int) (string, error)
+	GetFeaturedForTrickIDs(ctx context.Context, trickIDs []string) (map[string]models.TrickVideo, error)
+	FindPerformersByTrickID(ctx context.Context, trickID string, limit int) ([]models.TrickPerformer, int, error)
+	ResolveTrickSlugs(ctx context.Context, slugs []string) (map[string]int, error)
+	FindExistingURLs(ctx context.Context, keys []VideoKey) (map[VideoKey]bool, error)
+	InsertImported(ctx context.Context, videos []models.TrickVideo) ([]error, error)
 }
 
 // VideoRepository implements VideoRepositoryInterface
@@ -27,14 +76,16 @@ func NewVideoRepository(pool *pgxpool.Pool) *VideoRepository {
 }
 
 // FindByTrickID retrieves all videos for a specific trick
+// trickID is the trick's public ID (its slug); videos reference the integer primary key
 func (r *VideoRepository) FindByTrickID(ctx context.Context, trickID string) ([]models.TrickVideo, error) {
 	query := `
 		SELECT 
 			id, trick_id, video_url, thumbnail_url,
 			uploaded_by, performer_user_id, performer_name,
-			is_featured, created_at
+			is_featured, created_at, attribution, license, label,
+			availability, last_checked_at
 		FROM trick_data.trick_videos
-		WHERE trick_id = $1
+		WHERE trick_id = (SELECT id FROM trick_data.tricks WHERE slug = $1)
 		ORDER BY is_featured DESC, created_at DESC
 	`
 	// ORDER BY is_featured DESC puts featured videos first
@@ -54,16 +105,17 @@ func (r *VideoRepository) FindByTrickID(ctx context.Context, trickID string) ([]
 	return videos, nil
 }
 
-// GetFeaturedByTrickID retrieves the featured video for a trick
+// GetFeaturedByTrickID retrieves the featured video for a trick (by slug, like FindByTrickID)
 // Returns nil (not error) if no featured video exists
 func (r *VideoRepository) GetFeaturedByTrickID(ctx context.Context, trickID string) (*models.TrickVideo, error) {
 	query := `
 		SELECT 
 			id, trick_id, video_url, thumbnail_url,
 			uploaded_by, performer_user_id, performer_name,
-			is_featured, created_at
+			is_featured, created_at, attribution, license, label,
+			availability, last_checked_at
 		FROM trick_data.trick_videos
-		WHERE trick_id = $1 AND is_featured = true
+		WHERE trick_id = (SELECT id FROM trick_data.tricks WHERE slug = $1) AND is_featured = true
 		LIMIT 1
 	`
 
@@ -78,6 +130,10 @@ func (r *VideoRepository) GetFeaturedByTrickID(ctx context.Context, trickID stri
 		&video.PerformerName,
 		&video.IsFeatured,
 		&video.CreatedAt,
+		&video.Attribution,
+		&video.License,
+		&video.Availability,
+		&video.LastCheckedAt,
 	)
 
 	if err != nil {
@@ -92,3 +148,470 @@ func (r *VideoRepository) GetFeaturedByTrickID(ctx context.Context, trickID stri
 
 	return &video, nil
 }
+
+// FindAll retrieves every trick video
+// Used by the admin re-sanitization pass
+func (r *VideoRepository) FindAll(ctx context.Context) ([]models.TrickVideo, error) {
+	query := `
+		SELECT 
+			id, trick_id, video_url, thumbnail_url,
+			uploaded_by, performer_user_id, performer_name,
+			is_featured, created_at, attribution, license, label,
+			availability, last_checked_at
+		FROM trick_data.trick_videos
+		ORDER BY id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query videos: %w", err)
+	}
+
+	videos, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickVideo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect video rows: %w", err)
+	}
+
+	return videos, nil
+}
+
+// UpdateExternalFields overwrites the user-submitted columns of a video
+// (URLs and performer name)
+func (r *VideoRepository) UpdateExternalFields(ctx context.Context, video *models.TrickVideo) error {
+	query := `
+		UPDATE trick_data.trick_videos
+		SET video_url = $1, thumbnail_url = $2, performer_name = $3, updated_at = now()
+		WHERE id = $4
+	`
+
+	tag, err := r.pool.Exec(ctx, query, video.VideoURL, video.ThumbnailURL, video.PerformerName, video.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update video %d: %w", video.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a single video
+// Returns ErrNotFound if the video doesn't exist
+func (r *VideoRepository) GetByID(ctx context.Context, id int64) (*models.TrickVideo, error) {
+	query := `
+		SELECT 
+			id, trick_id, video_url, thumbnail_url,
+			uploaded_by, performer_user_id, performer_name,
+			is_featured, created_at, attribution, license, label,
+			availability, last_checked_at
+		FROM trick_data.trick_videos
+		WHERE id = $1
+	`
+
+	rows, err := r.pool.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video %d: %w", id, err)
+	}
+
+	video, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.TrickVideo])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get video %d: %w", id, err)
+	}
+
+	return &video, nil
+}
+
+// FindDueForCheck retrieves videos never checked or last checked before checkedBefore
+// Never-checked videos come first, then the stalest
+func (r *VideoRepository) FindDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]models.TrickVideo, error) {
+	query := `
+		SELECT 
+			id, trick_id, video_url, thumbnail_url,
+			uploaded_by, performer_user_id, performer_name,
+			is_featured, created_at, attribution, license, label,
+			availability, last_checked_at
+		FROM trick_data.trick_videos
+		WHERE last_checked_at IS NULL OR last_checked_at < $1
+		ORDER BY last_checked_at ASC NULLS FIRST, id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, checkedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query videos due for check: %w", err)
+	}
+
+	videos, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrickVideo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect video rows: %w", err)
+	}
+
+	return videos, nil
+}
+
+// UpdateAvailability records a check result and returns the check time
+func (r *VideoRepository) UpdateAvailability(ctx context.Context, id int64, availability string) (time.Time, error) {
+	query := `
+		UPDATE trick_data.trick_videos
+		SET availability = $1, last_checked_at = NOW()
+		WHERE id = $2
+		RETURNING last_checked_at
+	`
+
+	var checkedAt time.Time
+	err := r.pool.QueryRow(ctx, query, availability, id).Scan(&checkedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, ErrNotFound
+		}
+		return time.Time{}, fmt.Errorf("failed to update availability for video %d: %w", id, err)
+	}
+
+	return checkedAt, nil
+}
+
+// Create adds a video to a live trick, filling in video.ID, TrickID, CreatedAt and Availability
+// In the same transaction it:
+//   - unfeatures the trick's other videos when video.IsFeatured is set
+//   - resets the trick's weight_modifier to 1.0 - a trick with a video is no longer stale
+//
+// Returns ErrNotFound if the trick doesn't exist or is deleted, ErrDuplicateVideo
+// if it already has a video with this URL
+func (r *VideoRepository) Create(ctx context.Context, trickSlug string, video *models.TrickVideo) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Resetting the modifier also locks the trick row, so a concurrent delete can't interleave
+	err = tx.QueryRow(ctx,
+		`UPDATE trick_data.tricks SET weight_modifier = 1.0
+		 WHERE slug = $1 AND deleted_at IS NULL
+		 RETURNING id`,
+		trickSlug,
+	).Scan(&video.TrickID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to get trick %s: %w", trickSlug, err)
+	}
+
+	if video.IsFeatured {
+		_, err = tx.Exec(ctx,
+			`UPDATE trick_data.trick_videos SET is_featured = false, updated_at = now() WHERE trick_id = $1 AND is_featured`,
+			video.TrickID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to unfeature videos of trick %s: %w", trickSlug, err)
+		}
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO trick_data.trick_videos
+			(trick_id, video_url, thumbnail_url, uploaded_by, performer_user_id, performer_name,
+			 is_featured, attribution, license, label)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		 ON CONFLICT (trick_id, video_url) DO NOTHING
+		 RETURNING id, created_at, availability`,
+		video.TrickID, video.VideoURL, video.ThumbnailURL, video.UploadedBy, video.PerformerUserID,
+		video.PerformerName, video.IsFeatured, video.Attribution, video.License, video.Label,
+	).Scan(&video.ID, &video.CreatedAt, &video.Availability)
+	if err != nil {
+		// Rolling back also undoes the unfeaturing above
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDuplicateVideo
+		}
+		return fmt.Errorf("failed to insert video: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetFeaturedForTrickIDs returns the featured video of each trick in one query, keyed by slug
+// It picks the same video as the dictionary: the featured one, or - if that is
+// known to be unavailable - the newest playable video instead.
+// Tricks without such a video are absent from the map.
+func (r *VideoRepository) GetFeaturedForTrickIDs(ctx context.Context, trickIDs []string) (map[string]models.TrickVideo, error) {
+	// DISTINCT ON keeps the first row per trick in ORDER BY order: featured first, then newest
+	query := `
+		SELECT DISTINCT ON (v.trick_id)
+			t.slug,
+			v.id, v.trick_id, v.video_url, v.thumbnail_url,
+			v.uploaded_by, v.performer_user_id, v.performer_name,
+			v.is_featured, v.created_at, v.attribution, v.license, v.label,
+			v.availability, v.last_checked_at
+		FROM trick_data.trick_videos v
+		JOIN trick_data.tricks t ON t.id = v.trick_id
+		WHERE t.slug = ANY($1)
+			AND v.availability <> $2
+			AND (v.is_featured OR EXISTS (
+				SELECT 1 FROM trick_data.trick_videos f
+				WHERE f.trick_id = v.trick_id AND f.is_featured AND f.availability = $2
+			))
+		ORDER BY v.trick_id, v.is_featured DESC, v.created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, trickIDs, models.VideoUnavailable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query featured videos: %w", err)
+	}
+	defer rows.Close()
+
+	featured := make(map[string]models.TrickVideo, len(trickIDs))
+	for rows.Next() {
+		var slug string
+		var video models.TrickVideo
+		err := rows.Scan(
+			&slug,
+			&video.ID,
+			&video.TrickID,
+			&video.VideoURL,
+			&video.ThumbnailURL,
+			&video.UploadedBy,
+			&video.PerformerUserID,
+			&video.PerformerName,
+			&video.IsFeatured,
+			&video.CreatedAt,
+			&video.Attribution,
+			&video.License,
+			&video.Label,
+			&video.Availability,
+			&video.LastCheckedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan featured video: %w", err)
+		}
+		featured[slug] = video
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate featured videos: %w", err)
+	}
+
+	return featured, nil
+}
+
+// FindPerformersByTrickID returns the top performers of a trick by video count,
+// plus how many distinct performers there are in total
+// Names are grouped case-insensitively ("Jeremy" and "jeremy " are one performer),
+// shown in their most common spelling. Videos known to be unavailable don't count -
+// only footage the dictionary would actually show.
+func (r *VideoRepository) FindPerformersByTrickID(ctx context.Context, trickID string, limit int) ([]models.TrickPerformer, int, error) {
+	// COUNT(*) OVER () runs after GROUP BY but before LIMIT: the number of performers
+	query := `
+		SELECT
+			mode() WITHIN GROUP (ORDER BY btrim(v.performer_name)) AS name,
+			(array_agg(v.performer_user_id ORDER BY v.created_at DESC)
+				FILTER (WHERE v.performer_user_id IS NOT NULL))[1] AS user_id,
+			COUNT(*) AS video_count,
+			COUNT(*) OVER () AS total
+		FROM trick_data.trick_videos v
+		JOIN trick_data.tricks t ON t.id = v.trick_id
+		WHERE t.slug = $1
+			AND v.availability <> $2
+			AND btrim(v.performer_name) <> ''
+		GROUP BY lower(btrim(v.performer_name))
+		ORDER BY video_count DESC, name
+		LIMIT $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, trickID, models.VideoUnavailable, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query performers: %w", err)
+	}
+	defer rows.Close()
+
+	performers := []models.TrickPerformer{}
+	total := 0
+	for rows.Next() {
+		var performer models.TrickPerformer
+		if err := rows.Scan(&performer.Name, &performer.UserID, &performer.VideoCount, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan performer: %w", err)
+		}
+		performers = append(performers, performer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate performers: %w", err)
+	}
+
+	return performers, total, nil
+}
+
+// GetTrickSlug returns the slug of the trick with the given integer ID
+// Videos reference tricks by integer ID; everything public uses the slug
+func (r *VideoRepository) GetTrickSlug(ctx context.Context, trickID int) (string, error) {
+	var slug string
+	err := r.pool.QueryRow(ctx, `SELECT slug FROM trick_data.tricks WHERE id = $1`, trickID).Scan(&slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get slug of trick %d: %w", trickID, err)
+	}
+	return slug, nil
+}
+
+// ResolveTrickSlugs maps each slug of a live trick to its integer ID, in one query
+// Slugs of missing or deleted tricks are absent from the map.
+func (r *VideoRepository) ResolveTrickSlugs(ctx context.Context, slugs []string) (map[string]int, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT slug, id FROM trick_data.tricks WHERE slug = ANY($1) AND deleted_at IS NULL`,
+		slugs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve trick slugs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int, len(slugs))
+	for rows.Next() {
+		var slug string
+		var id int
+		if err := rows.Scan(&slug, &id); err != nil {
+			return nil, fmt.Errorf("failed to scan trick slug: %w", err)
+		}
+		ids[slug] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trick slugs: %w", err)
+	}
+	return ids, nil
+}
+
+// FindExistingURLs reports which (trick, URL) pairs already have a video, in one query
+// Only pairs that exist are in the map.
+func (r *VideoRepository) FindExistingURLs(ctx context.Context, keys []VideoKey) (map[VideoKey]bool, error) {
+	trickIDs := make([]int, len(keys))
+	urls := make([]string, len(keys))
+	for i, key := range keys {
+		trickIDs[i] = key.TrickID
+		urls[i] = key.URL
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT v.trick_id, v.video_url
+		FROM unnest($1::INTEGER[], $2::TEXT[]) AS k (trick_id, video_url)
+		JOIN trick_data.trick_videos v ON v.trick_id = k.trick_id AND v.video_url = k.video_url`,
+		trickIDs, urls,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query existing video URLs: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[VideoKey]bool)
+	for rows.Next() {
+		var key VideoKey
+		if err := rows.Scan(&key.TrickID, &key.URL); err != nil {
+			return nil, fmt.Errorf("failed to scan existing video URL: %w", err)
+		}
+		existing[key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating existing video URLs: %w", err)
+	}
+	return existing, nil
+}
+
+// InsertImported writes one chunk of imported videos in a single transaction
+// The inserts go out as one pgx.Batch. Returns one error per video, in order:
+// nil (ID, CreatedAt and Availability are filled in), ErrDuplicateVideo if the
+// URL landed on the trick since the caller checked, or ErrNotFound if the trick
+// was deleted since the caller resolved it.
+//
+// Like Create, it resets each trick's weight_modifier (locking the trick rows
+// first) and a featured video unfeatures the trick's other videos - so within
+// the chunk, a later featured video wins.
+func (r *VideoRepository) InsertImported(ctx context.Context, videos []models.TrickVideo) ([]error, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	trickIDs := make([]int, 0, len(videos))
+	for _, video := range videos {
+		trickIDs = append(trickIDs, video.TrickID)
+	}
+	rows, err := tx.Query(ctx,
+		`UPDATE trick_data.tricks SET weight_modifier = 1.0
+		 WHERE id = ANY($1) AND deleted_at IS NULL
+		 RETURNING id`,
+		trickIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock imported tricks: %w", err)
+	}
+	liveIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect imported trick IDs: %w", err)
+	}
+	live := make(map[int]bool, len(liveIDs))
+	for _, id := range liveIDs {
+		live[id] = true
+	}
+
+	// Queued in input order - the batch runs its statements sequentially
+	batch := &pgx.Batch{}
+	for _, video := range videos {
+		if !live[video.TrickID] {
+			continue
+		}
+		if video.IsFeatured {
+			batch.Queue(
+				`UPDATE trick_data.trick_videos SET is_featured = false, updated_at = now() WHERE trick_id = $1 AND is_featured`,
+				video.TrickID,
+			)
+		}
+		batch.Queue(
+			`INSERT INTO trick_data.trick_videos
+				(trick_id, video_url, thumbnail_url, uploaded_by, performer_name,
+				 is_featured, attribution, license, label)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			 ON CONFLICT (trick_id, video_url) DO NOTHING
+			 RETURNING id, created_at, availability`,
+			video.TrickID, video.VideoURL, video.ThumbnailURL, video.UploadedBy, video.PerformerName,
+			video.IsFeatured, video.Attribution, video.License, video.Label,
+		)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	outcomes := make([]error, len(videos))
+	for i := range videos {
+		video := &videos[i]
+		if !live[video.TrickID] {
+			outcomes[i] = ErrNotFound
+			continue
+		}
+		if video.IsFeatured {
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return nil, fmt.Errorf("failed to unfeature videos of trick %d: %w", video.TrickID, err)
+			}
+		}
+
+		err := results.QueryRow().Scan(&video.ID, &video.CreatedAt, &video.Availability)
+		if errors.Is(err, pgx.ErrNoRows) {
+			outcomes[i] = ErrDuplicateVideo
+		} else if err != nil {
+			results.Close()
+			return nil, fmt.Errorf("failed to insert video %s: %w", video.VideoURL, err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("failed to insert videos: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return outcomes, nil
+}
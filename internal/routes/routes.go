@@ -1,86 +1,504 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 
+	"tricking-api/internal/adminui"
 	"tricking-api/internal/config"
 	"tricking-api/internal/handlers"
+	"tricking-api/internal/metrics"
 	"tricking-api/internal/middleware"
 )
 
+// Per-group request timeouts (504 when exceeded)
+// Catalog reads are single queries, generation does weighted selection over
+// the candidate pool, and admin jobs walk the whole catalog.
+// Variables only so tests can shorten them.
+var (
+	catalogTimeout    = 3 * time.Second
+	generationTimeout = 10 * time.Second
+	adminTimeout      = 60 * time.Second
+)
+
+// trickPathsDeprecatedAt is when /trick/... gave way to /tricks/...
+var trickPathsDeprecatedAt = time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+
 func NewRouter(
 	cfg *config.Config,
 	trickHandler *handlers.TrickHandler,
 	comboHandler *handlers.ComboHandler,
 	categoryHandler *handlers.CategoryHandler,
+	stanceHandler *handlers.StanceHandler,
+	flipHandler *handlers.FlipHandler,
 	userHandler *handlers.UserHandler,
+	changelogHandler *handlers.ChangelogHandler,
+	adminHandler *handlers.AdminHandler,
+	healthHandler *handlers.HealthHandler,
+	moderationHandler *handlers.ModerationHandler,
+	publicLinkHandler *handlers.PublicLinkHandler,
+	metaHandler *handlers.MetaHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	apiKeys *middleware.APIKeys,
+	apiVersion string,
 ) *gin.Engine {
 	// CREATE ROUTER
 	router := gin.Default()
 
+	// Trailing slashes are stripped before routing by middleware.StripTrailingSlash
+	// (wrapped around this router in main), so gin's own 301/307 redirect stays off
+	router.RedirectTrailingSlash = false
+
+	// Tag each request with an ID so log lines can be correlated
+	router.Use(middleware.RequestID())
+
+	// Every response carries the API version so clients can spot a newer server
+	router.Use(middleware.APIVersion(apiVersion))
+
+	// Record which internal service is calling (logged + counted, enforced on admin routes)
+	router.Use(middleware.ServiceIdentity(cfg.AllowedServices))
+
 	// API VERSION GROUP
 	// Routes will be:
 	// /api/v1/tricks
 	// /api/v1/combos
 	// /api/v1/categories
 	v1 := router.Group("/api/v1")
-	// All routes require internal API key
+
+	// Public catalog routes share a hard rate limit (429 as soon as it's hit)
+	// Routes registered on v1 further down don't inherit this. Third parties may
+	// call them with an external API key instead, on their own per-key bucket.
+	public := v1.Group("", apiKeys.Accept(), middleware.RateLimit("public", cfg.PublicRateLimit))
+
+	// Catalog reads get the short timeout; combo generation has its own group below
+	catalog := public.Group("", middleware.Timeout(catalogTimeout))
 
 	// V1 ROUTES
 	{
+		// GET /api/v1/tricks?cursor=&limit=&include=featured_video,video_flags&with_counts=true - Trick catalog, cursor-paginated by name
+		// GET /api/v1/tricks?min_difficulty=&max_difficulty=&takeoff_stance_id=&landing_stance_id=&category_ids=
+		//   - Full filtered list (filters are ANDed)
+		// GET /api/v1/tricks?slugs=backflip,cork,raiz - Batch lookup (max 100, order kept, unknown -> "missing")
+		// All three accept ?fields=id,name,difficulty to return only those fields
+		catalog.GET("/tricks", trickHandler.ListTricks)
+
+		// HEAD /api/v1/tricks and /api/v1/tricks/:id - Freshness checks for sync clients
+		// gin has no implicit HEAD, so these run the GET handlers: same ETag,
+		// Last-Modified and 304 logic, and the Timeout buffer sets the GET's
+		// Content-Length. net/http drops the body. Only these two are registered, so a
+		// HEAD for another /tricks/... path is looked up as a trick slug (usually 404).
+		catalog.HEAD("/tricks", trickHandler.ListTricks)
+		catalog.HEAD("/tricks/:id", trickHandler.GetSimpleTrickById)
+
 		// GET /api/v1/tricks - List all tricks (for dropdowns/search)
-		v1.GET("/tricks/simple", trickHandler.GetSimpleTricksList)
+		catalog.GET("/tricks/simple", trickHandler.GetSimpleTricksList)
+
+		// GET /api/v1/tricks/slugs - Slugs + update times only (for sitemap generation)
+		catalog.GET("/tricks/slugs", trickHandler.GetTrickSlugs)
+
+		// GET /api/v1/tricks/daily - Trick of the day: full details, same for everyone, changes at midnight UTC
+		// ?date=2024-06-01 shows another day's pick (for testing)
+		catalog.GET("/tricks/daily", trickHandler.GetDailyTrick)
+
+		// GET /api/v1/tricks/new - Recently added tricks, newest first (?days=7&limit=10)
+		catalog.GET("/tricks/new", trickHandler.GetNewTricks)
+
+		// GET /api/v1/tricks/popular?window=7d&limit=10 - Most viewed tricks in the last 1-30 days
+		catalog.GET("/tricks/popular", trickHandler.GetPopularTricks)
+
+		// GET /api/v1/tricks/stats - Totals, count per flip_id, average difficulty, added in the last 30 days
+		catalog.GET("/tricks/stats", trickHandler.GetTrickStats)
+
+		// GET /api/v1/tricks/difficulty-histogram - Trick count per difficulty (same filters as GET /tricks)
+		catalog.GET("/tricks/difficulty-histogram", trickHandler.GetDifficultyHistogram)
+
+		// GET /api/v1/tricks/graph?relations=prerequisite&format=json|dot - Tricks and their relations as a graph
+		// (same filters as GET /tricks; 413 past TRICK_GRAPH_MAX_NODES tricks)
+		catalog.GET("/tricks/graph", trickHandler.GetTrickGraph)
+
+		// GET /api/v1/tricks/changes?since=1712345678 - Delta sync for offline clients
+		// (changed tricks + deleted_slugs + server_time to send as the next since)
+		catalog.GET("/tricks/changes", trickHandler.GetTrickChanges)
+
+		// GET /api/v1/catalog/snapshot/delta?since_version= - Tricks, categories and stances
+		// changed since a snapshot version (+ the next version; 409 full_required when too old)
+		catalog.GET("/catalog/snapshot/delta", trickHandler.GetCatalogDelta)
+
+		// GET /api/v1/tricks/search?q=&limit= - Ranked full-text search (exact name matches first)
+		catalog.GET("/tricks/search", trickHandler.SearchTricks)
+
+		// GET /api/v1/tricks/autocomplete?prefix= - Type-ahead, max 10 {slug, name} pairs
+		catalog.GET("/tricks/autocomplete", trickHandler.AutocompleteTricks)
+
+		// GET /api/v1/tricks/random?max_difficulty=5&exclude=cork,raiz - One weighted-random trick
+		// (same weighting as combo generation; 422 if nothing matches)
+		catalog.GET("/tricks/random", comboHandler.GetRandomTrick)
 
 		// ======================================================================
 		// TRICK ROUTES
 		// ======================================================================
-		tricks := v1.Group("/trick")
+		// Single tricks live under /tricks like the list. gin matches the static
+		// routes above first, so a trick slugged "random" is only reachable
+		// through the /trick alias below.
+
+		// GET /api/v1/tricks/:id - Get simple trick details
+		// :id is a URL parameter - any value in that position is captured
+		// Example: /api/v1/tricks/sideswipe -> id = "sideswipe"
+		// The slug is the public ID; a numeric legacy ID (/tricks/42) still resolves
+		// Accepts ?fields= like GET /tricks; conditional via ETag or Last-Modified
+		catalog.GET("/tricks/:id", trickHandler.GetSimpleTrickById)
+
+		// GET /api/v1/tricks/:id/dictionary - Get full trick details with videos
+		// Nested resource - the dictionary "belongs to" a specific trick
+		// ?include=featured_video,performers,prerequisites picks the optional sections (default: all)
+		catalog.GET("/tricks/:id/dictionary", trickHandler.GetFullDetailsTrickById)
+
+		// POST /api/v1/tricks/batch-dictionary - Up to 20 dictionaries at once, in request order
+		// (same ?include= for all; unknown slugs get an inline error instead of failing the batch)
+		catalog.POST("/tricks/batch-dictionary", trickHandler.BatchGetDictionaries)
+
+		// GET /api/v1/tricks/:id/prerequisites - Tricks to learn first (direct only)
+		catalog.GET("/tricks/:id/prerequisites", trickHandler.GetPrerequisites)
+
+		// GET /api/v1/tricks/:id/unlocks - Tricks that list this one as a prerequisite
+		catalog.GET("/tricks/:id/unlocks", trickHandler.GetUnlocks)
+
+		// Deprecated: the original /trick paths, kept for existing clients
+		legacyTricks := catalog.Group("/trick", middleware.Deprecated(trickPathsDeprecatedAt))
 		{
+			// GET /api/v1/trick/:id -> /api/v1/tricks/:id
+			legacyTricks.GET("/:id", trickHandler.GetSimpleTrickById)
+			legacyTricks.HEAD("/:id", trickHandler.GetSimpleTrickById)
 
-			// GET /api/v1/tricks/:id - Get simple trick details
-			// :id is a URL parameter - any value in that position is captured
-			// Example: /api/v1/tricks/sideswipe -> id = "sideswipe"
-			tricks.GET("/:id", trickHandler.GetSimpleTrickById)
+			// GET /api/v1/trick/detail/:id -> /api/v1/tricks/:id/dictionary
+			legacyTricks.GET("/detail/:id", trickHandler.GetFullDetailsTrickById)
 
-			// GET /api/v1/tricks/:id/dictionary - Get full trick details with videos
-			// Nested resource - the dictionary "belongs to" a specific trick
-			tricks.GET("/detail/:id", trickHandler.GetFullDetailsTrickById)
+			// GET /api/v1/trick/:id/prerequisites and /unlocks -> /api/v1/tricks/:id/...
+			legacyTricks.GET("/:id/prerequisites", trickHandler.GetPrerequisites)
+			legacyTricks.GET("/:id/unlocks", trickHandler.GetUnlocks)
 		}
 
 		// ======================================================================
 		// COMBO ROUTES
 		// ======================================================================
-		combos := v1.Group("/combos")
+		combos := public.Group("/combos", middleware.Timeout(generationTimeout))
 		{
 			// GET /api/v1/combos/generate - Generate combo with filters
 			// Using GET because this is a read operation (no data created)
-			// Filters are passed as query parameters
+			// Filters are passed as query parameters; ?notation_style=arrows|dashes|numbered
 			combos.GET("/generate", comboHandler.GenerateComboWithFilters)
 
 			// GET /api/v1/combos/generate/simple - Generate combo with size only
 			combos.GET("/generate/simple/:size", comboHandler.GenerateSimpleCombo)
+
+			// POST /api/v1/combos/estimate - Difficulty, grade and stance flow of a hypothetical combo
+			// POST only to carry the ordered list - nothing is stored
+			combos.POST("/estimate", comboHandler.EstimateCombo)
 		}
 
+		// ======================================================================
+		// TAG ROUTES
+		// ======================================================================
+		// GET /api/v1/tags - Every tag with its live-trick count (filter with GET /tricks?tag=)
+		catalog.GET("/tags", trickHandler.ListTags)
+
 		// ======================================================================
 		// CATEGORY ROUTES
 		// ======================================================================
-		categories := v1.Group("/categories")
+		categories := catalog.Group("/categories")
 		{
 			// GET /api/v1/categories - List all categories
 			categories.GET("", categoryHandler.ListCategories)
+
+			// GET /api/v1/categories/:id/tricks?min_difficulty=&max_difficulty= - Tricks in a category
+			categories.GET("/:id/tricks", categoryHandler.ListCategoryTricks)
+		}
+
+		// GET /api/v1/stances - Every stance (names for takeoff_stance_id / landing_stance_id)
+		catalog.GET("/stances", stanceHandler.ListStances)
+
+		// GET /api/v1/flips - Every flip family with its trick count (names for flip_id)
+		catalog.GET("/flips", flipHandler.ListFlips)
+
+		// ======================================================================
+		// CHANGELOG ROUTES
+		// ======================================================================
+		// GET /api/v1/meta/difficulty-bands - Named difficulty ranges (?band= filters, "band" on trick details)
+		catalog.GET("/meta/difficulty-bands", metaHandler.ListDifficultyBands)
+
+		// GET /api/v1/changelog - What changed in each API release
+		catalog.GET("/changelog", changelogHandler.GetChangelog)
+
+		// ======================================================================
+		// PUBLIC LINK ROUTES
+		// ======================================================================
+		// Keyless: the signed link is the credential (see services.PublicLinkService).
+		// Registered before the v1.Use calls below, so no API key or user context -
+		// and rate-limited per client IP rather than through the shared public bucket.
+		publicLinks := v1.Group("/public", middleware.RateLimitByIP("public_link", cfg.PublicLinkRateLimit), middleware.Timeout(catalogTimeout))
+		{
+			// GET /api/v1/public/tricks/:slug?exp=&sig= - Reduced trick preview (401 if the link is bad or expired)
+			publicLinks.GET("/tricks/:slug", publicLinkHandler.GetPublicTrick)
 		}
 
 		// ======================================================================
-		// USER ROUTES (for saved combos) NOT IMPLEMENTED YET
+		// USER ROUTES (for saved combos)
 		// ======================================================================
+		// External API keys only reach the catalog export past this point -
+		// anything else sent with one is a 403
+		v1.Use(apiKeys.Accept("/api/v1/tricks/export"))
 		// Extract user context from BFF headers for all /users routes
-		v1.Use(middleware.ExtractUserContext())
+		v1.Use(middleware.ExtractUserContext(cfg.AdminServices))
 		v1.Use(middleware.InternalAPIKey(cfg.InternalAPIKey))
+		// The BFF fronts real users - queue briefly during spikes instead of failing
+		v1.Use(middleware.RateLimit("internal", cfg.InternalRateLimit))
 		users := v1.Group("/users")
 		{
 			// GET /api/v1/users/:userId/combos - Get user's saved combos
 			// This is a nested resource - combos belong to a user
 			users.GET("/:userId/combos", userHandler.GetUserCombos)
+
+			// POST /api/v1/users/:userId/combos - Save a new combo
+			users.POST("/:userId/combos", userHandler.CreateCombo)
+
+			// POST /api/v1/users/:userId/combos/import - Save up to 50 combos, each on its own (result per combo)
+			users.POST("/:userId/combos/import", userHandler.ImportCombos)
+
+			// PUT /api/v1/users/:userId/combos/:comboId - Replace a saved combo
+			users.PUT("/:userId/combos/:comboId", userHandler.UpdateCombo)
+
+			// PUT /api/v1/users/:userId/combos/:comboId/tricks - Replace only the trick order
+			users.PUT("/:userId/combos/:comboId/tricks", userHandler.ReplaceComboTricks)
+
+			// GET /api/v1/users/:userId/recent-tricks - Tricks from the user's newest combos
+			users.GET("/:userId/recent-tricks", userHandler.GetRecentTricks)
+
+			// GET /api/v1/users/:userId/training-calendar?from=&to= - Combo attempts per day, with streaks
+			users.GET("/:userId/training-calendar", userHandler.GetTrainingCalendar)
+
+			// GET /api/v1/users/:userId/trick-submissions?limit=20 - Tricks the user created, with review status and reason
+			users.GET("/:userId/trick-submissions", trickHandler.GetTrickSubmissions)
+		}
+
+		// Catalog export - behind the API key (unlike the public catalog) and, as a
+		// streaming route, without the Timeout middleware (the handler has its own deadline)
+		exports := v1.Group("/tricks")
+		{
+			// GET /api/v1/tricks/export?format=csv|ndjson|json - Whole catalog as a download (GET /tricks filters apply)
+			exports.GET("/export", trickHandler.ExportTricks)
+		}
+
+		// Full catalog snapshot - public like the delta, but streamed, so it
+		// skips the Timeout middleware too (the handler has its own deadline)
+		snapshots := public.Group("/catalog")
+		{
+			// GET /api/v1/catalog/snapshot - Everything the delta returns without since_version,
+			// streamed a trick at a time (X-Stream-Status trailer: complete|incomplete)
+			snapshots.GET("/snapshot", trickHandler.GetCatalogSnapshot)
+		}
+
+		// Combo reads that span users - authorization is per combo, in the service
+		savedCombos := v1.Group("/combos")
+		{
+			// POST /api/v1/combos/batch-get - Up to 50 combos by ID (for activity feeds)
+			savedCombos.POST("/batch-get", userHandler.BatchGetCombos)
+		}
+
+		// ======================================================================
+		// ADMIN ROUTES
+		// ======================================================================
+		// Same API key + user context as /users, plus the admin role
+		// and a known service-name so audit records can tell callers apart
+		// Bulk jobs (sanitize, diff, calibration) get the long timeout
+		admin := v1.Group("/admin", middleware.Timeout(adminTimeout), middleware.RequireService(), middleware.RequireAdmin())
+		{
+			// POST /api/v1/admin/sanitize - Re-clean existing rows with current rules
+			admin.POST("/sanitize", adminHandler.ResanitizeCatalog)
+
+			// GET /api/v1/admin/catalog/diff?from=&to= - What changed in the catalog (max 90 days)
+			admin.GET("/catalog/diff", adminHandler.GetCatalogDiff)
+
+			// GET /api/v1/admin/attributions - Attributed content grouped by source
+			admin.GET("/attributions", adminHandler.GetAttributions)
+
+			// GET /api/v1/admin/difficulty-calibration - Curated vs community difficulty
+			admin.GET("/difficulty-calibration", adminHandler.GetDifficultyCalibration)
+
+			// POST /api/v1/admin/tricks/:slug/adopt-community-difficulty - Use the community average
+			admin.POST("/tricks/:slug/adopt-community-difficulty", adminHandler.AdoptCommunityDifficulty)
+
+			// PATCH /api/v1/admin/tricks/weights - Set many weights in one transaction ({"weights": {slug: weight}})
+			admin.PATCH("/tricks/weights", adminHandler.SetTrickWeights)
+
+			// POST /api/v1/admin/tricks/:slug/public-link - Mint a signed link to the trick's public preview
+			admin.POST("/tricks/:slug/public-link", publicLinkHandler.CreateLink)
+
+			// POST /api/v1/admin/api-keys - Issue an external API key (the key is only in this response)
+			admin.POST("/api-keys", apiKeyHandler.IssueKey)
+
+			// GET /api/v1/admin/api-keys - Every key's prefix, owner, scopes, tier and revocation
+			admin.GET("/api-keys", apiKeyHandler.ListKeys)
+
+			// DELETE /api/v1/admin/api-keys/:id - Revoke a key
+			admin.DELETE("/api-keys/:id", apiKeyHandler.RevokeKey)
+
+			// POST /api/v1/admin/tricks/:slug/aliases - Add an alternate name (unique across names and aliases)
+			admin.POST("/tricks/:slug/aliases", adminHandler.AddAlias)
+
+			// DELETE /api/v1/admin/tricks/:slug/aliases/:alias - Remove an alias, by its slug
+			admin.DELETE("/tricks/:slug/aliases/:alias", adminHandler.RemoveAlias)
+
+			// GET /api/v1/admin/tricks/:slug/former-names - Names the trick was renamed from (recorded by the import)
+			admin.GET("/tricks/:slug/former-names", adminHandler.GetFormerNames)
+
+			// PUT /api/v1/admin/tricks/:slug/former-names/:alias - Correct a former name's spelling
+			admin.PUT("/tricks/:slug/former-names/:alias", adminHandler.UpdateFormerName)
+
+			// DELETE /api/v1/admin/tricks/:slug/former-names/:alias - Prune a former name
+			admin.DELETE("/tricks/:slug/former-names/:alias", adminHandler.RemoveFormerName)
+
+			// GET /api/v1/admin/tricks/:slug/translations - The trick's translations
+			admin.GET("/tricks/:slug/translations", adminHandler.GetTranslations)
+
+			// PUT /api/v1/admin/tricks/:slug/translations/:locale - Create/replace one ("es", "fr")
+			admin.PUT("/tricks/:slug/translations/:locale", adminHandler.SaveTranslation)
+
+			// DELETE /api/v1/admin/tricks/:slug/translations/:locale - Remove one
+			admin.DELETE("/tricks/:slug/translations/:locale", adminHandler.DeleteTranslation)
+
+			// POST /api/v1/admin/tricks/:slug/videos - Add a video (resets the trick's weight decay)
+			admin.POST("/tricks/:slug/videos", adminHandler.CreateVideo)
+
+			// POST /api/v1/admin/videos/import - Add up to 1000 videos by trick slug (per-row results)
+			admin.POST("/videos/import", adminHandler.ImportVideos)
+
+			// GET /api/v1/admin/stats - Catalog counts (live/deleted tricks, videos, decayed tricks)
+			admin.GET("/stats", adminHandler.GetStats)
+
+			// GET /api/v1/admin/runtime - Version, commit, features, redacted DB info, uptime, goroutines
+			admin.GET("/runtime", adminHandler.GetRuntime)
+
+			// GET /api/v1/admin/tricks/pending-purge - Deleted tricks and their purge dates
+			admin.GET("/tricks/pending-purge", adminHandler.GetPendingPurge)
+
+			// POST /api/v1/admin/tricks/:slug/merge - Fold {"duplicate_slug": "..."} into this trick
+			// (combo positions, videos and aliases move over, its name becomes an alias, it's soft-deleted)
+			admin.POST("/tricks/:slug/merge", adminHandler.MergeTrick)
+
+			// GET /api/v1/admin/tricks/pending?limit=&offset= - Submitted tricks waiting for review, oldest first
+			admin.GET("/tricks/pending", adminHandler.ListPendingTricks)
+
+			// POST /api/v1/admin/tricks/:slug/approve - Add a pending trick to the catalog (409 if already reviewed)
+			admin.POST("/tricks/:slug/approve", adminHandler.ApproveTrick)
+
+			// POST /api/v1/admin/tricks/:slug/reject - Turn one down ({"reason": "..."} optional, shown to the submitter)
+			admin.POST("/tricks/:slug/reject", adminHandler.RejectTrick)
+
+			// POST /api/v1/admin/videos/:id/check - Re-check a video's external link now
+			admin.POST("/videos/:id/check", adminHandler.CheckVideoAvailability)
+
+			// GET /api/v1/admin/consistency - Trick references (stances, flip) that point at nothing
+			// POST /api/v1/admin/consistency?fix=null_out - Same, then clear them (batched, audited)
+			admin.GET("/consistency", adminHandler.CheckConsistency)
+			admin.POST("/consistency", adminHandler.CheckConsistency)
+
+			// GET /api/v1/admin/self-check - Re-check required indexes (refreshes /health/ready)
+			admin.GET("/self-check", adminHandler.SelfCheck)
+
+			// POST /api/v1/admin/drain - Fail /health/ready from now on, like SIGTERM (until restart)
+			admin.POST("/drain", healthHandler.Drain)
+		}
+
+		// Streaming admin routes - same guards, but no Timeout middleware: it buffers
+		// the whole response, which would defeat streaming. Handlers set their own deadline.
+		adminStreams := v1.Group("/admin", middleware.RequireService(), middleware.RequireAdmin())
+		{
+			// POST /api/v1/admin/tricks/import - NDJSON bulk create/overwrite by slug
+			// (Content-Type: application/x-ndjson; one result line per input line, then a summary)
+			adminStreams.POST("/tricks/import", adminHandler.ImportTricks)
+		}
+
+		// ======================================================================
+		// MODERATION ROUTES
+		// ======================================================================
+		// Content curation - moderators and admins
+		moderation := v1.Group("/moderation", middleware.RequireService(), middleware.RequireModerator())
+		{
+			// POST /api/v1/moderation/tricks/:slug/mistakes - Add a common mistake (appended last)
+			moderation.POST("/tricks/:slug/mistakes", moderationHandler.CreateMistake)
+
+			// PUT /api/v1/moderation/tricks/:slug/mistakes/order - Reorder (body lists every mistake ID)
+			moderation.PUT("/tricks/:slug/mistakes/order", moderationHandler.ReorderMistakes)
+
+			// PUT /api/v1/moderation/tricks/:slug/mistakes/:mistakeId - Edit text/severity
+			moderation.PUT("/tricks/:slug/mistakes/:mistakeId", moderationHandler.UpdateMistake)
+
+			// DELETE /api/v1/moderation/tricks/:slug/mistakes/:mistakeId
+			moderation.DELETE("/tricks/:slug/mistakes/:mistakeId", moderationHandler.DeleteMistake)
+
+			// POST /api/v1/moderation/tricks/:slug/prerequisites - {"prerequisite_id": "backflip"}
+			// (422 if it would make a trick its own prerequisite, directly or transitively)
+			moderation.POST("/tricks/:slug/prerequisites", moderationHandler.AddPrerequisite)
+
+			// DELETE /api/v1/moderation/tricks/:slug/prerequisites/:prerequisite
+			moderation.DELETE("/tricks/:slug/prerequisites/:prerequisite", moderationHandler.RemovePrerequisite)
+
+			// POST /api/v1/moderation/tags - Create a tag (normalized to lowercase)
+			moderation.POST("/tags", moderationHandler.CreateTag)
+
+			// DELETE /api/v1/moderation/tags/:tag - Delete a tag from every trick
+			moderation.DELETE("/tags/:tag", moderationHandler.DeleteTag)
+
+			// POST /api/v1/moderation/tricks/:slug/tags - Tag a trick (creates the tag if needed)
+			moderation.POST("/tricks/:slug/tags", moderationHandler.TagTrick)
+
+			// DELETE /api/v1/moderation/tricks/:slug/tags/:tag - Untag a trick
+			moderation.DELETE("/tricks/:slug/tags/:tag", moderationHandler.UntagTrick)
+		}
+
+		// Any user can submit a trick; only an admin's goes live without review
+		trickSubmissions := v1.Group("/tricks", middleware.RequireService())
+		{
+			// POST /api/v1/tricks - Create a trick (slug from the name; 201 + Location, 409 if the name is taken)
+			// (202 with "status": "pending" for non-admins - see /admin/tricks/pending)
+			trickSubmissions.POST("", trickHandler.CreateTrick)
+		}
+
+		// Trick edits live on the trick resource itself - curators only, like moderation
+		trickWrites := v1.Group("/tricks", middleware.RequireService(), middleware.RequireModerator())
+		{
+			// PUT /api/v1/tricks/:id - Replace a trick's fields (slug kept unless regenerate_slug; 404, 409 as above)
+			trickWrites.PUT("/:id", trickHandler.UpdateTrick)
+
+			// PATCH /api/v1/tricks/:id - Change only the fields sent (null clears; {} is a 400)
+			trickWrites.PATCH("/:id", trickHandler.PatchTrick)
+		}
+
+		// Trick deletion, history and batch creation live on the trick resource itself, but are admin-only
+		adminTricks := v1.Group("/tricks", middleware.RequireService(), middleware.RequireAdmin())
+		{
+			// DELETE /api/v1/tricks/:id - Soft delete, purged after the configured window
+			adminTricks.DELETE("/:id", adminHandler.DeleteTrick)
+
+			// POST /api/v1/tricks/:id/restore - Undo a delete before its purge date
+			adminTricks.POST("/:id/restore", adminHandler.RestoreTrick)
+
+			// GET /api/v1/tricks/:id/history?limit=&offset= - Who changed the trick and how, newest first
+			adminTricks.GET("/:id/history", adminHandler.GetTrickHistory)
+
+			// PATCH /api/v1/tricks/:id/weight - Set the curated weight ({"weight": 1-1000})
+			adminTricks.PATCH("/:id/weight", adminHandler.SetTrickWeight)
+
+			// POST /api/v1/tricks/import - Create many tricks from a CSV or JSON array, all or nothing (?dry_run=true)
+			adminTricks.POST("/import", middleware.MaxBodySize(cfg.TrickImportMaxBytes), trickHandler.ImportTrickBatch)
+		}
+
+		// Deprecated: /trick spellings of the admin trick routes
+		legacyAdminTricks := v1.Group("/trick", middleware.Deprecated(trickPathsDeprecatedAt), middleware.RequireService(), middleware.RequireAdmin())
+		{
+			legacyAdminTricks.DELETE("/:id", adminHandler.DeleteTrick)
+			legacyAdminTricks.POST("/:id/restore", adminHandler.RestoreTrick)
 		}
 	}
 
@@ -93,5 +511,29 @@ func NewRouter(
 		})
 	})
 
+	// Readiness - still 200 when degraded, so a missing index doesn't take the API down;
+	// 503 once draining (SIGTERM or POST /admin/drain)
+	router.GET("/health/ready", healthHandler.Ready)
+
+	// ==========================================================================
+	// DEV ADMIN UI
+	// ==========================================================================
+	// A static page for browsing the catalog locally - not registered in
+	// production at all, so it 404s there like any unknown path
+	if !cfg.IsProduction() {
+		router.GET("/admin/ui", adminui.Handler())
+	}
+
+	// ==========================================================================
+	// METRICS ROUTE
+	// ==========================================================================
+	// Prometheus text format - scraped by monitoring, not called by clients
+	router.GET("/metrics", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4")
+		if err := metrics.WriteText(c.Writer); err != nil {
+			c.Status(500)
+		}
+	})
+
 	return router
 }
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/handlers"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/middleware"
+)
+
+// newTestRouter builds the real router around cfg and trickHandler
+// Requests in these tests only reach trick handlers, so the other handlers are empty.
+func newTestRouter(cfg *config.Config, trickHandler *handlers.TrickHandler) *gin.Engine {
+	return newTestRouterWith(cfg, trickHandler, nil, nil)
+}
+
+// newTestRouterWith is newTestRouter with combo and admin handlers too (nil = empty)
+func newTestRouterWith(cfg *config.Config, trickHandler *handlers.TrickHandler, comboHandler *handlers.ComboHandler, adminHandler *handlers.AdminHandler) *gin.Engine {
+	if trickHandler == nil {
+		trickHandler = new(handlers.TrickHandler)
+	}
+	if comboHandler == nil {
+		comboHandler = new(handlers.ComboHandler)
+	}
+	if adminHandler == nil {
+		adminHandler = new(handlers.AdminHandler)
+	}
+	return NewRouter(cfg,
+		trickHandler, comboHandler, new(handlers.CategoryHandler),
+		new(handlers.StanceHandler), new(handlers.FlipHandler), new(handlers.UserHandler),
+		new(handlers.ChangelogHandler), adminHandler, new(handlers.HealthHandler),
+		new(handlers.ModerationHandler), new(handlers.PublicLinkHandler), new(handlers.MetaHandler),
+		new(handlers.APIKeyHandler), middleware.NewAPIKeys(nil, cfg.APIKeyRateLimits), "test",
+	)
+}
+
+// productionConfig loads the config exactly as the server does with ENVIRONMENT=production
+func productionConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	t.Setenv("ENVIRONMENT", "production")
+	t.Setenv("DATABASE_URL", "postgres://localhost/tricking")
+	t.Setenv("INTERNAL_API_KEY", "test-key")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	return cfg
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	if err := messages.Load(); err != nil {
+		log.Fatalf("failed to load messages: %v", err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestAdminUIOnlyOutsideProduction(t *testing.T) {
+	production := productionConfig(t)
+	if !production.IsProduction() {
+		t.Fatal("ENVIRONMENT=production did not load a production config")
+	}
+
+	development := *production
+	development.Environment = "dev"
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		wantStatus int
+	}{
+		{name: "production", cfg: production, wantStatus: http.StatusNotFound},
+		{name: "development", cfg: &development, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(tt.cfg, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ui", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("GET /admin/ui status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
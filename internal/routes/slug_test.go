@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tricking-api/internal/handlers"
+	"tricking-api/internal/middleware"
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+)
+
+// fakeSlugTrickService knows a few lowercase slugs and records what was looked up
+type fakeSlugTrickService struct {
+	services.TrickServiceInterface
+
+	lookups []string
+}
+
+func (s *fakeSlugTrickService) GetSimpleTrickById(ctx context.Context, id string, locale string) (*models.TrickDetailResponse, int64, error) {
+	s.lookups = append(s.lookups, id)
+	switch id {
+	case "backflip", "540-kick":
+		return &models.TrickDetailResponse{ID: id, Name: id}, 1700000000, nil
+	}
+	return nil, 0, services.ErrTrickNotFound
+}
+
+func (s *fakeSlugTrickService) RecordView(id string) {}
+
+func TestTrickPathNormalization(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantLocation string
+		wantLookup   string // slug the service was asked for, "" for none
+	}{
+		{name: "lowercase", path: "/api/v1/tricks/backflip", wantStatus: http.StatusOK, wantLookup: "backflip"},
+		{name: "trailing slash", path: "/api/v1/tricks/backflip/", wantStatus: http.StatusOK, wantLookup: "backflip"},
+		{name: "several trailing slashes", path: "/api/v1/tricks/backflip//", wantStatus: http.StatusOK, wantLookup: "backflip"},
+		{name: "uppercase redirects", path: "/api/v1/tricks/Backflip", wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/tricks/backflip", wantLookup: "backflip"},
+		{
+			name: "uppercase with trailing slash and query", path: "/api/v1/tricks/BACKFLIP/?fields=id",
+			wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/tricks/backflip?fields=id", wantLookup: "backflip",
+		},
+		{name: "unknown uppercase is 404 not redirect", path: "/api/v1/tricks/Nope", wantStatus: http.StatusNotFound, wantLookup: "nope"},
+		{name: "encoded hyphen", path: "/api/v1/tricks/540%2Dkick", wantStatus: http.StatusOK, wantLookup: "540-kick"},
+		{name: "encoded uppercase letter", path: "/api/v1/tricks/Back%66lip", wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/tricks/backflip", wantLookup: "backflip"},
+		{name: "encoded space", path: "/api/v1/tricks/back%20flip", wantStatus: http.StatusBadRequest},
+		{name: "encoded slash", path: "/api/v1/tricks/back%2Fflip", wantStatus: http.StatusNotFound},
+		{name: "encoded trailing slash", path: "/api/v1/tricks/backflip%2F", wantStatus: http.StatusOK, wantLookup: "backflip"},
+		{name: "legacy path", path: "/api/v1/trick/backflip/", wantStatus: http.StatusOK, wantLookup: "backflip"},
+	}
+
+	cfg := productionConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeSlugTrickService{}
+			router := newTestRouter(cfg, handlers.NewTrickHandler(service, nil, nil))
+			// Wrapped the same way main wraps it
+			server := middleware.StripTrailingSlash(router)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d: %s", tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+			if location := w.Header().Get("Location"); location != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", location, tt.wantLocation)
+			}
+
+			got := strings.Join(service.lookups, ",")
+			if got != tt.wantLookup {
+				t.Errorf("looked up %q, want %q", got, tt.wantLookup)
+			}
+		})
+	}
+}
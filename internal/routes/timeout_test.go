@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tricking-api/internal/handlers"
+	"tricking-api/internal/messages"
+	"tricking-api/internal/models"
+	"tricking-api/internal/services"
+)
+
+// slowCall sleeps for delay or until ctx is cancelled, noting which came first
+type slowCall struct {
+	delay     time.Duration
+	cancelled atomic.Bool
+}
+
+func (s *slowCall) wait(ctx context.Context) error {
+	select {
+	case <-time.After(s.delay):
+		return nil
+	case <-ctx.Done():
+		s.cancelled.Store(true)
+		return ctx.Err()
+	}
+}
+
+type slowTrickService struct {
+	services.TrickServiceInterface
+	*slowCall
+}
+
+func (s *slowTrickService) GetSimpleTrickById(ctx context.Context, id string, locale string) (*models.TrickDetailResponse, int64, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	return &models.TrickDetailResponse{ID: id, Name: id}, 1700000000, nil
+}
+
+func (s *slowTrickService) RecordView(id string) {}
+
+type slowComboService struct {
+	services.ComboServiceInterface
+	*slowCall
+}
+
+func (s *slowComboService) GenerateComboWithFilters(ctx context.Context, req models.ComboGenerateRequest) (*models.GeneratedComboResponse, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return &models.GeneratedComboResponse{}, nil
+}
+
+type slowAdminService struct {
+	services.AdminServiceInterface
+	*slowCall
+}
+
+func (s *slowAdminService) GetAttributions(ctx context.Context) ([]models.AttributionSource, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return []models.AttributionSource{}, nil
+}
+
+// shortenTimeouts scales the group timeouts down for the duration of a test
+func shortenTimeouts(t *testing.T, catalog, generation, admin time.Duration) {
+	t.Helper()
+
+	saved := [3]time.Duration{catalogTimeout, generationTimeout, adminTimeout}
+	catalogTimeout, generationTimeout, adminTimeout = catalog, generation, admin
+	t.Cleanup(func() {
+		catalogTimeout, generationTimeout, adminTimeout = saved[0], saved[1], saved[2]
+	})
+}
+
+func TestRouteGroupTimeouts(t *testing.T) {
+	// Same ordering as production: catalog < generation < admin
+	shortenTimeouts(t, 50*time.Millisecond, 300*time.Millisecond, 900*time.Millisecond)
+
+	adminHeaders := map[string]string{
+		"internal-api-key": "test-key",
+		"service-name":     "bff-admin",
+		"user-id":          "6f1c1f7e-2a55-4c43-9d1e-0d6b1c3f8a10",
+		"user-role":        "admin",
+	}
+
+	tests := []struct {
+		name       string
+		group      string
+		path       string
+		headers    map[string]string
+		delay      time.Duration
+		wantStatus int
+	}{
+		{name: "fast catalog read", group: "catalog", path: "/api/v1/tricks/backflip", delay: 0, wantStatus: http.StatusOK},
+		{name: "slow catalog read", group: "catalog", path: "/api/v1/tricks/backflip", delay: 150 * time.Millisecond, wantStatus: http.StatusGatewayTimeout},
+		{name: "legacy catalog path", group: "catalog", path: "/api/v1/trick/backflip", delay: 150 * time.Millisecond, wantStatus: http.StatusGatewayTimeout},
+		{name: "generation past the catalog timeout", group: "generation", path: "/api/v1/combos/generate?size=3", delay: 150 * time.Millisecond, wantStatus: http.StatusOK},
+		{name: "slow generation", group: "generation", path: "/api/v1/combos/generate?size=3", delay: 600 * time.Millisecond, wantStatus: http.StatusGatewayTimeout},
+		{name: "admin past the generation timeout", group: "admin", path: "/api/v1/admin/attributions", headers: adminHeaders, delay: 600 * time.Millisecond, wantStatus: http.StatusOK},
+		{name: "slow admin", group: "admin", path: "/api/v1/admin/attributions", headers: adminHeaders, delay: 2 * time.Second, wantStatus: http.StatusGatewayTimeout},
+	}
+
+	cfg := productionConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			call := &slowCall{delay: tt.delay}
+			var trickHandler *handlers.TrickHandler
+			var comboHandler *handlers.ComboHandler
+			var adminHandler *handlers.AdminHandler
+			switch tt.group {
+			case "catalog":
+				trickHandler = handlers.NewTrickHandler(&slowTrickService{slowCall: call}, nil, nil)
+			case "generation":
+				comboHandler = handlers.NewComboHandler(&slowComboService{slowCall: call}, services.NewDifficultyBands(nil))
+			case "admin":
+				adminHandler = handlers.NewAdminHandler(&slowAdminService{slowCall: call}, nil, nil, nil, nil)
+			}
+			router := newTestRouterWith(cfg, trickHandler, comboHandler, adminHandler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusGatewayTimeout {
+				return
+			}
+
+			// The handler's own error response after the cancellation must not leak out
+			var body struct {
+				Code string `json:"code"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != messages.CodeRequestTimeout {
+				t.Errorf("body = %q, want a single %s envelope", w.Body.String(), messages.CodeRequestTimeout)
+			}
+			if !call.cancelled.Load() {
+				t.Error("the slow service never saw the cancellation")
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tricking-api/internal/handlers"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/services"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeIDTrickRepo serves a few fixture tricks by slug and by legacy integer key
+type fakeIDTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	tricks      map[string]models.Trick // by slug
+	primaryKeys map[int64]string        // legacy integer key -> slug
+	views       map[string]int64
+}
+
+func newFakeIDTrickRepo() *fakeIDTrickRepo {
+	numeric := fixtures.Trick().WithSlug("1080").WithName("1080").WithDifficulty(6).Build()
+	return &fakeIDTrickRepo{
+		tricks: map[string]models.Trick{
+			"backflip": fixtures.CatalogTrick("backflip"),
+			"cork":     fixtures.CatalogTrick("cork"),
+			"aerial":   fixtures.CatalogTrick("aerial"),
+			"1080":     numeric,
+		},
+		primaryKeys: map[int64]string{42: "backflip", 2024: "cork", 1080: "aerial"},
+		views:       map[string]int64{},
+	}
+}
+
+func (r *fakeIDTrickRepo) GetByID(ctx context.Context, id string) (*models.Trick, error) {
+	trick, ok := r.tricks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &trick, nil
+}
+
+func (r *fakeIDTrickRepo) GetLastModifiedByID(ctx context.Context, id string) (int64, error) {
+	if _, ok := r.tricks[id]; !ok {
+		return 0, repository.ErrNotFound
+	}
+	return fixtures.Epoch.Unix(), nil
+}
+
+// ResolveNumericID mirrors the SQL: a slug that is the number wins over the key
+func (r *fakeIDTrickRepo) ResolveNumericID(ctx context.Context, id string, legacyID int64) (string, error) {
+	if _, ok := r.tricks[id]; ok {
+		return id, nil
+	}
+	if slug, ok := r.primaryKeys[legacyID]; ok {
+		return slug, nil
+	}
+	return "", repository.ErrNotFound
+}
+
+func (r *fakeIDTrickRepo) IncrementViewCounts(ctx context.Context, counts map[string]int64) error {
+	for slug, n := range counts {
+		r.views[slug] += n
+	}
+	return nil
+}
+
+// fakeIDAliasRepo knows one alias
+type fakeIDAliasRepo struct {
+	repository.AliasRepositoryInterface
+}
+
+func (r *fakeIDAliasRepo) ResolveSlug(ctx context.Context, aliasSlug string) (string, error) {
+	if aliasSlug == "side-somi" {
+		return "aerial", nil
+	}
+	return "", repository.ErrNotFound
+}
+
+func TestTrickSlugAndNumericIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantID     string // canonical slug in the response and the view count
+	}{
+		{name: "legacy slug path", path: "/api/v1/trick/backflip", wantStatus: http.StatusOK, wantID: "backflip"},
+		{name: "legacy numeric path", path: "/api/v1/trick/42", wantStatus: http.StatusOK, wantID: "backflip"},
+		{name: "slug path", path: "/api/v1/tricks/backflip", wantStatus: http.StatusOK, wantID: "backflip"},
+		{name: "numeric path", path: "/api/v1/tricks/42", wantStatus: http.StatusOK, wantID: "backflip"},
+		{name: "leading zeros", path: "/api/v1/trick/0042", wantStatus: http.StatusOK, wantID: "backflip"},
+		{name: "another key", path: "/api/v1/trick/2024", wantStatus: http.StatusOK, wantID: "cork"},
+		{name: "numeric slug wins over the key", path: "/api/v1/trick/1080", wantStatus: http.StatusOK, wantID: "1080"},
+		{name: "alias", path: "/api/v1/trick/side-somi", wantStatus: http.StatusOK, wantID: "aerial"},
+		{name: "unknown key", path: "/api/v1/trick/99", wantStatus: http.StatusNotFound},
+		{name: "unknown slug", path: "/api/v1/trick/nope", wantStatus: http.StatusNotFound},
+	}
+
+	cfg := productionConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trickRepo := newFakeIDTrickRepo()
+			viewCounter := services.NewViewCounter(trickRepo)
+			service := services.NewTrickService(trickRepo, nil, nil, nil, &fakeIDAliasRepo{}, nil, nil, nil,
+				viewCounter, nil, 7, services.NewDifficultyBands(cfg.DifficultyBands), 0, 0, 0, false)
+			router := newTestRouter(cfg, handlers.NewTrickHandler(service, nil, nil))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d: %s", tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantID == "" {
+				return
+			}
+
+			// No redirect: the body carries the canonical slug
+			var body struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("bad body: %v", err)
+			}
+			if body.ID != tt.wantID {
+				t.Errorf("id = %q, want %q", body.ID, tt.wantID)
+			}
+
+			// Views are only ever counted against slugs
+			if err := viewCounter.Flush(context.Background()); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+			if len(trickRepo.views) != 1 || trickRepo.views[tt.wantID] != 1 {
+				t.Errorf("views = %v, want one for %s", trickRepo.views, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestMixedCaseSlugRedirect(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantLocation string
+	}{
+		{name: "exact slug", path: "/api/v1/tricks/backflip", wantStatus: http.StatusOK},
+		{
+			name: "capitalized", path: "/api/v1/tricks/Backflip",
+			wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/tricks/backflip",
+		},
+		{
+			name: "upper case keeps the query", path: "/api/v1/tricks/BACKFLIP?fields=id,name",
+			wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/tricks/backflip?fields=id,name",
+		},
+		{
+			name: "legacy path", path: "/api/v1/trick/Cork",
+			wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/trick/cork",
+		},
+		{
+			name: "dictionary", path: "/api/v1/tricks/Aerial/dictionary",
+			wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/tricks/aerial/dictionary",
+		},
+		{name: "missing slug is not redirected", path: "/api/v1/tricks/Nope", wantStatus: http.StatusNotFound},
+		{name: "missing dictionary is not redirected", path: "/api/v1/tricks/Nope/dictionary", wantStatus: http.StatusNotFound},
+		{name: "case variant of a different slug", path: "/api/v1/tricks/BACK-FLIP", wantStatus: http.StatusNotFound},
+	}
+
+	cfg := productionConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trickRepo := newFakeIDTrickRepo()
+			service := services.NewTrickService(trickRepo, nil, nil, nil, &fakeIDAliasRepo{}, nil, nil, nil,
+				services.NewViewCounter(trickRepo), nil, 7, services.NewDifficultyBands(cfg.DifficultyBands), 0, 0, 0, false)
+			router := newTestRouter(cfg, handlers.NewTrickHandler(service, nil, nil))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d: %s", tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+		})
+	}
+}
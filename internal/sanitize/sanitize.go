@@ -0,0 +1,121 @@
+// =============================================================================
+// FILE: internal/sanitize/sanitize.go
+// PURPOSE: Pure functions that clean user-submitted data before it is stored
+// =============================================================================
+//
+// Every write path runs its text and URL fields through this package.
+// The functions have no dependencies on the database or HTTP layer so the
+// same rules can be applied to new writes and to re-cleaning existing rows.
+// =============================================================================
+
+package sanitize
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidURL indicates a URL that can't be stored (bad scheme, no host, etc.)
+var ErrInvalidURL = errors.New("invalid URL")
+
+var (
+	// htmlTag matches anything that looks like an HTML/XML tag or comment
+	htmlTag = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^<>]*>`)
+
+	// inlineSpace matches runs of spaces and tabs
+	inlineSpace = regexp.MustCompile(`[ \t]+`)
+
+	// blankLines matches 3+ newlines (with optional spaces) - one blank line is enough
+	blankLines = regexp.MustCompile(`\n[ \t]*(\n[ \t]*){2,}`)
+)
+
+// Text strips HTML tags and control characters and collapses long whitespace runs
+// Markdown punctuation (*, _, #, >, -, etc.) is left untouched
+func Text(s string) string {
+	s = htmlTag.ReplaceAllString(s, "")
+
+	// Normalize Windows/old Mac line endings before dropping control characters
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || isBidiControl(r) || r == unicode.ReplacementChar {
+			return -1 // -1 drops the rune
+		}
+		return r
+	}, s)
+
+	s = inlineSpace.ReplaceAllString(s, " ")
+	s = blankLines.ReplaceAllString(s, "\n\n")
+
+	// Trim trailing spaces on each line so collapsed runs don't leave "foo \n"
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// OptionalText runs Text on a nullable field
+// Returns nil if the input is nil or cleans down to an empty string
+func OptionalText(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	cleaned := Text(*s)
+	if cleaned == "" {
+		return nil
+	}
+	return &cleaned
+}
+
+// URL validates an external URL and returns it in normalized form
+// Only https is accepted unless allowHTTP is true (development only)
+func URL(raw string, allowHTTP bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", ErrInvalidURL
+	}
+
+	// Control characters and whitespace are never valid inside a URL
+	if strings.IndexFunc(raw, func(r rune) bool { return unicode.IsControl(r) || isBidiControl(r) || unicode.IsSpace(r) }) >= 0 {
+		return "", ErrInvalidURL
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !allowHTTP {
+			return "", ErrInvalidURL
+		}
+	default:
+		// Rejects javascript:, data:, file:, relative URLs, etc.
+		return "", ErrInvalidURL
+	}
+
+	if u.Host == "" || u.User != nil {
+		return "", ErrInvalidURL
+	}
+
+	u.Host = strings.ToLower(u.Host)
+
+	return u.String(), nil
+}
+
+// isBidiControl reports whether r is a bidi embedding, override or isolate
+// These reorder the text around them, so "abc\u202Etxt.exe" displays as "abcexe.txt"
+func isBidiControl(r rune) bool {
+	return (r >= '\u202A' && r <= '\u202E') || (r >= '\u2066' && r <= '\u2069')
+}
@@ -0,0 +1,675 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/sanitize"
+)
+
+// MaxCatalogDiffWindow bounds how far apart from and to can be in a catalog diff
+const MaxCatalogDiffWindow = 90 * 24 * time.Hour
+
+// ErrNoDifficultyVotes indicates a trick has no community votes to adopt
+var ErrNoDifficultyVotes = errors.New("trick has no community difficulty votes")
+
+// ErrTrickNotDeleted indicates a restore was attempted on a live trick
+var ErrTrickNotDeleted = errors.New("trick is not deleted")
+
+// ErrPurgeWindowClosed indicates a deleted trick can no longer be restored
+var ErrPurgeWindowClosed = errors.New("trick is past its purge date and can no longer be restored")
+
+// ErrInvalidVideoURL indicates a video or thumbnail URL that can't be stored
+var ErrInvalidVideoURL = errors.New("invalid video or thumbnail URL")
+
+// ErrInvalidDiffWindow indicates a catalog diff window that is reversed or too long
+var ErrInvalidDiffWindow = errors.New("diff window must have from before to and span at most 90 days")
+
+// ErrMergeIntoSelf indicates a merge whose duplicate is the kept trick itself
+var ErrMergeIntoSelf = errors.New("a trick can't be merged into itself")
+
+// ErrTrickNotPending indicates an approve or reject of a trick that was already reviewed
+var ErrTrickNotPending = errors.New("trick is not pending review")
+
+// MaxRejectionReasonLength caps the reason shown to a rejected trick's submitter (in characters)
+const MaxRejectionReasonLength = 500
+
+// ErrInvalidRejectionReason indicates a rejection reason that is too long
+var ErrInvalidRejectionReason = errors.New("rejection reason must be at most 500 characters")
+
+// MaxAliasLength caps one trick alias (in characters)
+const MaxAliasLength = 100
+
+// ErrInvalidAlias indicates an alias that is empty, too long or has no letters or digits
+var ErrInvalidAlias = errors.New("alias must be 1-100 characters with at least one letter or digit")
+
+// ErrAliasTaken indicates an alias that is already a trick name, slug or alias
+var ErrAliasTaken = errors.New("alias is already a trick name or alias")
+
+// ErrAliasNotFound indicates the trick doesn't have that alias
+var ErrAliasNotFound = errors.New("alias not found")
+
+// Curated weight range for the weight endpoints - higher is picked more often
+// by combo generation. (Import accepts the column's whole SMALLINT range.)
+const (
+	MinTrickWeight = 1
+	MaxTrickWeight = 1000
+)
+
+// MaxBulkWeights caps one bulk weight change - room for the whole catalog
+const MaxBulkWeights = 5000
+
+// ErrInvalidTrickWeight indicates a weight outside MinTrickWeight-MaxTrickWeight
+var ErrInvalidTrickWeight = errors.New("weight must be between 1 and 1000")
+
+// ErrTooManyWeights indicates a bulk weight change over MaxBulkWeights tricks
+var ErrTooManyWeights = errors.New("too many tricks in one weight change")
+
+// UnknownTricksError lists the slugs of a bulk change that aren't live tricks
+// errors.Is(err, ErrTrickNotFound) matches it; nothing was changed.
+type UnknownTricksError struct {
+	Slugs []string
+}
+
+func (e *UnknownTricksError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrTrickNotFound, strings.Join(e.Slugs, ", "))
+}
+
+func (e *UnknownTricksError) Is(target error) bool {
+	return target == ErrTrickNotFound
+}
+
+// AdminServiceInterface defines the contract for admin-only maintenance operations
+type AdminServiceInterface interface {
+	ResanitizeCatalog(ctx context.Context) (*models.SanitizeReport, error)
+	GetCatalogDiff(ctx context.Context, from, to time.Time) (*models.CatalogDiffResponse, error)
+	GetAttributions(ctx context.Context) ([]models.AttributionSource, error)
+	GetDifficultyCalibration(ctx context.Context, minVotes int, minDelta float64, limit, offset int) (*models.DifficultyCalibrationPage, error)
+	AdoptCommunityDifficulty(ctx context.Context, id string, changedBy *uuid.UUID) (*models.DifficultyChangeResponse, error)
+	DeleteTrick(ctx context.Context, id string, changedBy *uuid.UUID) (*models.TrickDeletionResponse, error)
+	MergeTrick(ctx context.Context, keepSlug, duplicateSlug string, changedBy *uuid.UUID) (*models.TrickMergeResponse, error)
+	RestoreTrick(ctx context.Context, id string, changedBy *uuid.UUID) error
+	GetTrickHistory(ctx context.Context, id string, limit, offset int) (*models.TrickHistoryPage, error)
+	ListPendingTricks(ctx context.Context, limit, offset int) (*models.TrickSubmissionPage, error)
+	ApproveTrick(ctx context.Context, id string, approvedBy *uuid.UUID) (*models.TrickSubmission, error)
+	RejectTrick(ctx context.Context, id string, reason *string, rejectedBy *uuid.UUID) (*models.TrickSubmission, error)
+	SetTrickWeight(ctx context.Context, id string, weight int, changedBy *uuid.UUID) (*models.TrickWeightResponse, error)
+	SetTrickWeights(ctx context.Context, weights map[string]int, changedBy *uuid.UUID) (*models.TrickWeightsResponse, error)
+	GetPendingPurge(ctx context.Context) ([]models.PendingPurge, error)
+	CreateVideo(ctx context.Context, trickSlug string, req models.VideoCreateRequest, uploadedBy uuid.UUID) (*models.VideoResponse, error)
+	ImportVideos(ctx context.Context, rows []models.VideoImportRow, uploadedBy uuid.UUID, emit VideoImportEmitter) (*models.VideoImportSummary, error)
+	GetStats(ctx context.Context) (*models.AdminStats, error)
+	AddAlias(ctx context.Context, trickSlug, alias string) (*models.TrickAlias, error)
+	RemoveAlias(ctx context.Context, trickSlug, aliasSlug string) error
+	GetFormerNames(ctx context.Context, trickSlug string) ([]models.TrickAlias, error)
+	UpdateFormerName(ctx context.Context, trickSlug, aliasSlug, alias string) (*models.TrickAlias, error)
+	RemoveFormerName(ctx context.Context, trickSlug, aliasSlug string) error
+	GetTranslations(ctx context.Context, trickSlug string) ([]models.TrickTranslation, error)
+	SaveTranslation(ctx context.Context, trickSlug, locale string, req models.TrickTranslationRequest) (*models.TrickTranslation, error)
+	DeleteTranslation(ctx context.Context, trickSlug, locale string) error
+	ImportTricks(ctx context.Context, r io.Reader, changedBy *uuid.UUID, emit ImportEmitter) (*models.TrickImportSummary, error)
+}
+
+// AdminService implements AdminServiceInterface
+type AdminService struct {
+	trickRepo       repository.TrickRepositoryInterface
+	videoRepo       repository.VideoRepositoryInterface
+	catalogRepo     repository.CatalogRepositoryInterface
+	aliasRepo       repository.AliasRepositoryInterface
+	translationRepo repository.TranslationRepositoryInterface
+	categoryRepo    repository.CategoryRepositoryInterface
+
+	// allowHTTP permits plain http URLs (development only)
+	allowHTTP bool
+
+	// suggestCategories lets the bulk import pick categories for tricks without one
+	suggestCategories bool
+
+	// purgeAfter is how long a deleted trick can still be restored
+	purgeAfter time.Duration
+
+	// dictionaryCache is invalidated after every write that shows up in a dictionary
+	dictionaryCache *DictionaryCache
+}
+
+// NewAdminService creates a new AdminService instance
+func NewAdminService(
+	trickRepo repository.TrickRepositoryInterface,
+	videoRepo repository.VideoRepositoryInterface,
+	catalogRepo repository.CatalogRepositoryInterface,
+	aliasRepo repository.AliasRepositoryInterface,
+	translationRepo repository.TranslationRepositoryInterface,
+	categoryRepo repository.CategoryRepositoryInterface,
+	allowHTTP bool,
+	suggestCategories bool,
+	purgeAfter time.Duration,
+	dictionaryCache *DictionaryCache,
+) *AdminService {
+	return &AdminService{
+		trickRepo:         trickRepo,
+		videoRepo:         videoRepo,
+		catalogRepo:       catalogRepo,
+		aliasRepo:         aliasRepo,
+		translationRepo:   translationRepo,
+		categoryRepo:      categoryRepo,
+		allowHTTP:         allowHTTP,
+		suggestCategories: suggestCategories,
+		purgeAfter:        purgeAfter,
+		dictionaryCache:   dictionaryCache,
+	}
+}
+
+// ResanitizeCatalog runs the current sanitization rules over existing tricks and videos
+// Rows are only written when cleaning actually changed something
+func (s *AdminService) ResanitizeCatalog(ctx context.Context) (*models.SanitizeReport, error) {
+	report := &models.SanitizeReport{InvalidVideoIDs: []int64{}, InvalidTrickIDs: []string{}}
+
+	// ==========================================================================
+	// TRICKS - free-text fields
+	// ==========================================================================
+	tricks, err := s.trickRepo.FindTextFields(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tricks for sanitization: %w", err)
+	}
+	report.TricksScanned = len(tricks)
+
+	for _, trick := range tricks {
+		cleaned := trick
+		cleaned.Name = sanitize.Text(trick.Name)
+		if aliasSlug(cleaned.Name) == "" {
+			// Same rule as create/update - a name that cleans to nothing is reported, not saved
+			report.InvalidTrickIDs = append(report.InvalidTrickIDs, trick.ID)
+			continue
+		}
+		cleaned.Description = sanitize.OptionalText(trick.Description)
+		cleaned.ExecutionNotes = sanitize.OptionalText(trick.ExecutionNotes)
+
+		if cleaned.Name == trick.Name &&
+			equalOptional(cleaned.Description, trick.Description) &&
+			equalOptional(cleaned.ExecutionNotes, trick.ExecutionNotes) {
+			continue
+		}
+
+		if err := s.trickRepo.UpdateTextFields(ctx, &cleaned); err != nil {
+			return nil, fmt.Errorf("failed to update sanitized trick %s: %w", trick.ID, err)
+		}
+		report.TricksUpdated++
+	}
+
+	// ==========================================================================
+	// VIDEOS - external URLs and performer name
+	// ==========================================================================
+	videos, err := s.videoRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get videos for sanitization: %w", err)
+	}
+	report.VideosScanned = len(videos)
+
+	for _, video := range videos {
+		videoURL, videoErr := sanitize.URL(video.VideoURL, s.allowHTTP)
+		thumbnailURL, thumbErr := sanitize.URL(video.ThumbnailURL, s.allowHTTP)
+		if videoErr != nil || thumbErr != nil {
+			// A bad URL can't be repaired automatically - report it instead
+			report.InvalidVideoIDs = append(report.InvalidVideoIDs, video.ID)
+			continue
+		}
+
+		cleaned := video
+		cleaned.VideoURL = videoURL
+		cleaned.ThumbnailURL = thumbnailURL
+		cleaned.PerformerName = sanitize.Text(video.PerformerName)
+
+		if cleaned.VideoURL == video.VideoURL &&
+			cleaned.ThumbnailURL == video.ThumbnailURL &&
+			cleaned.PerformerName == video.PerformerName {
+			continue
+		}
+
+		if err := s.videoRepo.UpdateExternalFields(ctx, &cleaned); err != nil {
+			return nil, fmt.Errorf("failed to update sanitized video %d: %w", video.ID, err)
+		}
+		report.VideosUpdated++
+	}
+
+	// Rewrites can touch any trick, so drop every cached dictionary
+	if report.TricksUpdated > 0 || report.VideosUpdated > 0 {
+		s.dictionaryCache.InvalidateAll()
+	}
+
+	return report, nil
+}
+
+// GetCatalogDiff reports tricks, videos and categories that changed in [from, to)
+// The window is capped at MaxCatalogDiffWindow to keep the queries bounded
+func (s *AdminService) GetCatalogDiff(ctx context.Context, from, to time.Time) (*models.CatalogDiffResponse, error) {
+	if !from.Before(to) || to.Sub(from) > MaxCatalogDiffWindow {
+		return nil, ErrInvalidDiffWindow
+	}
+
+	changes, err := s.catalogRepo.FindChanges(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog changes: %w", err)
+	}
+
+	return &models.CatalogDiffResponse{
+		From:    from,
+		To:      to,
+		Changes: changes,
+		Count:   len(changes),
+	}, nil
+}
+
+// GetAttributions lists all attributed content grouped by source
+func (s *AdminService) GetAttributions(ctx context.Context) ([]models.AttributionSource, error) {
+	items, err := s.catalogRepo.FindAttributions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributions: %w", err)
+	}
+
+	// Rows arrive sorted by attribution, so each source is one consecutive run
+	sources := make([]models.AttributionSource, 0)
+	for _, item := range items {
+		if len(sources) == 0 || sources[len(sources)-1].Attribution != item.Attribution {
+			sources = append(sources, models.AttributionSource{Attribution: item.Attribution})
+		}
+		last := &sources[len(sources)-1]
+		last.Items = append(last.Items, item)
+	}
+
+	return sources, nil
+}
+
+// GetDifficultyCalibration lists tricks whose curated and community difficulty disagree
+func (s *AdminService) GetDifficultyCalibration(ctx context.Context, minVotes int, minDelta float64, limit, offset int) (*models.DifficultyCalibrationPage, error) {
+	tricks, total, err := s.trickRepo.FindDifficultyCalibration(ctx, minVotes, minDelta, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get difficulty calibration: %w", err)
+	}
+
+	return &models.DifficultyCalibrationPage{
+		Tricks: tricks,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// AdoptCommunityDifficulty replaces a trick's curated difficulty with the community average
+func (s *AdminService) AdoptCommunityDifficulty(ctx context.Context, id string, changedBy *uuid.UUID) (*models.DifficultyChangeResponse, error) {
+	change, err := s.trickRepo.AdoptCommunityDifficulty(ctx, id, changedBy)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		if errors.Is(err, repository.ErrNoVotes) {
+			return nil, ErrNoDifficultyVotes
+		}
+		return nil, fmt.Errorf("failed to adopt community difficulty: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(id)
+	return change, nil
+}
+
+// DeleteTrick soft-deletes a trick and schedules its permanent purge
+func (s *AdminService) DeleteTrick(ctx context.Context, id string, changedBy *uuid.UUID) (*models.TrickDeletionResponse, error) {
+	deletion, err := s.trickRepo.SoftDelete(ctx, id, s.purgeAfter, changedBy)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		return nil, fmt.Errorf("failed to delete trick: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(id)
+	return deletion, nil
+}
+
+// MergeTrick folds a duplicate trick into keepSlug and soft-deletes the duplicate
+// Combo positions, videos and aliases move to the kept trick, and the
+// duplicate's name becomes one of its aliases (see TrickRepository.Merge).
+func (s *AdminService) MergeTrick(ctx context.Context, keepSlug, duplicateSlug string, changedBy *uuid.UUID) (*models.TrickMergeResponse, error) {
+	duplicateSlug = strings.ToLower(strings.TrimSpace(duplicateSlug))
+	if duplicateSlug == keepSlug {
+		return nil, ErrMergeIntoSelf
+	}
+
+	duplicate, err := s.trickRepo.GetByID(ctx, duplicateSlug)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick: %w", err)
+	}
+
+	merge := repository.TrickMerge{
+		DuplicateSlug:     duplicateSlug,
+		DuplicateName:     duplicate.Name,
+		DuplicateNameSlug: aliasSlug(duplicate.Name),
+	}
+	merged, err := s.trickRepo.Merge(ctx, keepSlug, merge, s.purgeAfter, changedBy)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		return nil, fmt.Errorf("failed to merge trick: %w", err)
+	}
+
+	// Prerequisite lists and other tricks' dictionaries may show either trick
+	s.dictionaryCache.InvalidateAll()
+	return merged, nil
+}
+
+// RestoreTrick undoes a soft delete that hasn't been purged yet
+func (s *AdminService) RestoreTrick(ctx context.Context, id string, changedBy *uuid.UUID) error {
+	err := s.trickRepo.Restore(ctx, id, changedBy)
+	switch {
+	case err == nil:
+		s.dictionaryCache.InvalidateTrick(id)
+		return nil
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrTrickNotFound
+	case errors.Is(err, repository.ErrNotDeleted):
+		return ErrTrickNotDeleted
+	case errors.Is(err, repository.ErrPurgeWindowClosed):
+		return ErrPurgeWindowClosed
+	default:
+		return fmt.Errorf("failed to restore trick: %w", err)
+	}
+}
+
+// GetTrickHistory returns a page of a trick's audit entries, newest first
+// Deleted tricks keep their history until they're purged.
+func (s *AdminService) GetTrickHistory(ctx context.Context, id string, limit, offset int) (*models.TrickHistoryPage, error) {
+	entries, total, err := s.trickRepo.FindHistory(ctx, id, limit, offset)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick history: %w", err)
+	}
+
+	return &models.TrickHistoryPage{
+		ID:      id,
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}, nil
+}
+
+// ListPendingTricks returns a page of the tricks waiting for review, oldest first
+func (s *AdminService) ListPendingTricks(ctx context.Context, limit, offset int) (*models.TrickSubmissionPage, error) {
+	tricks, total, err := s.trickRepo.FindPending(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending tricks: %w", err)
+	}
+
+	return &models.TrickSubmissionPage{
+		Tricks: tricks,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// ApproveTrick adds a pending trick to the catalog
+func (s *AdminService) ApproveTrick(ctx context.Context, id string, approvedBy *uuid.UUID) (*models.TrickSubmission, error) {
+	trick, err := s.reviewTrick(ctx, id, models.TrickApproved, nil, approvedBy)
+	if err != nil {
+		return nil, err
+	}
+	// The trick can now show up in other tricks' dictionaries (prerequisites)
+	s.dictionaryCache.InvalidateAll()
+	return trick, nil
+}
+
+// RejectTrick turns a pending trick down; reason (optional) is shown to its submitter
+// Blank reasons are dropped.
+func (s *AdminService) RejectTrick(ctx context.Context, id string, reason *string, rejectedBy *uuid.UUID) (*models.TrickSubmission, error) {
+	reason = sanitize.OptionalText(reason)
+	if reason != nil && utf8.RuneCountInString(*reason) > MaxRejectionReasonLength {
+		return nil, ErrInvalidRejectionReason
+	}
+	return s.reviewTrick(ctx, id, models.TrickRejected, reason, rejectedBy)
+}
+
+// reviewTrick records an admin's decision on a pending trick
+func (s *AdminService) reviewTrick(ctx context.Context, id, status string, reason *string, reviewedBy *uuid.UUID) (*models.TrickSubmission, error) {
+	trick, err := s.trickRepo.Review(ctx, id, status, reason, reviewedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrTrickNotFound
+		case errors.Is(err, repository.ErrNotPending):
+			return nil, ErrTrickNotPending
+		default:
+			return nil, fmt.Errorf("failed to review trick: %w", err)
+		}
+	}
+	return trick, nil
+}
+
+// SetTrickWeight sets one trick's curated weight (MinTrickWeight-MaxTrickWeight)
+func (s *AdminService) SetTrickWeight(ctx context.Context, id string, weight int, changedBy *uuid.UUID) (*models.TrickWeightResponse, error) {
+	if weight < MinTrickWeight || weight > MaxTrickWeight {
+		return nil, ErrInvalidTrickWeight
+	}
+
+	changed, missing, err := s.trickRepo.SetWeights(ctx, map[string]int16{id: int16(weight)}, changedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set trick weight: %w", err)
+	}
+	if len(missing) > 0 {
+		return nil, ErrTrickNotFound
+	}
+	if changed > 0 {
+		s.dictionaryCache.InvalidateTrick(id)
+	}
+	return &models.TrickWeightResponse{ID: id, Weight: weight, Changed: changed > 0}, nil
+}
+
+// SetTrickWeights sets the curated weights of many tricks at once, by slug
+// It's all or nothing: one invalid weight or unknown slug and no trick changes.
+func (s *AdminService) SetTrickWeights(ctx context.Context, weights map[string]int, changedBy *uuid.UUID) (*models.TrickWeightsResponse, error) {
+	if len(weights) > MaxBulkWeights {
+		return nil, ErrTooManyWeights
+	}
+
+	values := make(map[string]int16, len(weights))
+	for slug, weight := range weights {
+		if weight < MinTrickWeight || weight > MaxTrickWeight {
+			return nil, ErrInvalidTrickWeight
+		}
+		values[strings.ToLower(slug)] = int16(weight)
+	}
+
+	changed, missing, err := s.trickRepo.SetWeights(ctx, values, changedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set trick weights: %w", err)
+	}
+	if len(missing) > 0 {
+		return nil, &UnknownTricksError{Slugs: missing}
+	}
+	if changed > 0 {
+		s.dictionaryCache.InvalidateAll()
+	}
+	return &models.TrickWeightsResponse{Changed: changed, Unchanged: len(values) - changed}, nil
+}
+
+// AddAlias gives a trick an alternate name that search and trick lookups also accept
+// The alias is sanitized and slugified ("Side Somi" -> "side-somi"); neither may
+// match any trick name, slug or other alias.
+func (s *AdminService) AddAlias(ctx context.Context, trickSlug, alias string) (*models.TrickAlias, error) {
+	alias = sanitize.Text(alias)
+	slug := aliasSlug(alias)
+	if slug == "" || utf8.RuneCountInString(alias) > MaxAliasLength {
+		return nil, ErrInvalidAlias
+	}
+
+	created, err := s.aliasRepo.Add(ctx, trickSlug, alias, slug)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, ErrTrickNotFound
+	case errors.Is(err, repository.ErrAliasTaken):
+		return nil, ErrAliasTaken
+	case err != nil:
+		return nil, fmt.Errorf("failed to add alias: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return created, nil
+}
+
+// RemoveAlias deletes one of a trick's aliases, by alias slug
+func (s *AdminService) RemoveAlias(ctx context.Context, trickSlug, aliasSlug string) error {
+	if err := s.aliasRepo.Remove(ctx, trickSlug, aliasSlug); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAliasNotFound
+		}
+		return fmt.Errorf("failed to remove alias: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return nil
+}
+
+// GetPendingPurge lists deleted tricks and when each will be permanently removed
+func (s *AdminService) GetPendingPurge(ctx context.Context) ([]models.PendingPurge, error) {
+	pending, err := s.trickRepo.FindPendingPurge(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending purges: %w", err)
+	}
+	return pending, nil
+}
+
+// CreateVideo adds a video to a trick
+// URLs are validated and text is sanitized on the way in, like every write path.
+// The trick's weight decay (if any) is reset in the same transaction.
+func (s *AdminService) CreateVideo(ctx context.Context, trickSlug string, req models.VideoCreateRequest, uploadedBy uuid.UUID) (*models.VideoResponse, error) {
+	// YouTube/Vimeo links are stored in one canonical form, so duplicates are caught
+	videoURL, _, err := NormalizeVideoURL(req.VideoURL, s.allowHTTP)
+	if err != nil {
+		return nil, err
+	}
+	thumbnailURL, err := sanitize.URL(req.ThumbnailURL, s.allowHTTP)
+	if err != nil {
+		return nil, ErrInvalidVideoURL
+	}
+	label, err := videoLabel(req.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	video := &models.TrickVideo{
+		VideoURL:        videoURL,
+		ThumbnailURL:    thumbnailURL,
+		UploadedBy:      uploadedBy,
+		PerformerUserID: req.PerformerUserID,
+		PerformerName:   sanitize.Text(req.PerformerName),
+		IsFeatured:      req.IsFeatured,
+		Attribution:     sanitize.OptionalText(req.Attribution),
+		License:         sanitize.OptionalText(req.License),
+		Label:           label,
+	}
+
+	if err := s.videoRepo.Create(ctx, trickSlug, video); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		if errors.Is(err, repository.ErrDuplicateVideo) {
+			return nil, ErrDuplicateVideo
+		}
+		return nil, fmt.Errorf("failed to create video: %w", err)
+	}
+	// Only this trick's dictionaries can show the new video
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+
+	response := video.ToResponse(responseView(ctx))
+	return &response, nil
+}
+
+// GetStats returns catalog counts for the admin dashboard
+func (s *AdminService) GetStats(ctx context.Context) (*models.AdminStats, error) {
+	stats, err := s.catalogRepo.GetStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetFormerNames lists the names a trick was renamed from, most recent first
+func (s *AdminService) GetFormerNames(ctx context.Context, trickSlug string) ([]models.TrickAlias, error) {
+	names, err := s.aliasRepo.FindFormerNames(ctx, trickSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get former names: %w", err)
+	}
+	return names, nil
+}
+
+// UpdateFormerName corrects the spelling of a former name, addressed by its alias slug
+// The new spelling is cleaned and checked like AddAlias; the slug follows it.
+func (s *AdminService) UpdateFormerName(ctx context.Context, trickSlug, formerSlug, alias string) (*models.TrickAlias, error) {
+	alias = sanitize.Text(alias)
+	slug := aliasSlug(alias)
+	if slug == "" || utf8.RuneCountInString(alias) > MaxAliasLength {
+		return nil, ErrInvalidAlias
+	}
+
+	updated, err := s.aliasRepo.UpdateFormerName(ctx, trickSlug, formerSlug, alias, slug)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, ErrAliasNotFound
+	case errors.Is(err, repository.ErrAliasTaken):
+		return nil, ErrAliasTaken
+	case err != nil:
+		return nil, fmt.Errorf("failed to update former name: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return updated, nil
+}
+
+// RemoveFormerName prunes one of a trick's former names, by alias slug
+// Regular aliases are left alone - RemoveAlias deletes those.
+func (s *AdminService) RemoveFormerName(ctx context.Context, trickSlug, aliasSlug string) error {
+	if err := s.aliasRepo.RemoveFormerName(ctx, trickSlug, aliasSlug); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAliasNotFound
+		}
+		return fmt.Errorf("failed to remove former name: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return nil
+}
+
+// aliasSlug turns an alias into its URL form: lowercase, runs of anything but
+// a-z and 0-9 collapsed to one "-". Empty if nothing is left.
+func aliasSlug(alias string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(alias) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// equalOptional compares two nullable strings by value
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tricking-api/internal/cache"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeSanitizeTrickRepo serves text fields and records which tricks were rewritten
+type fakeSanitizeTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	tricks  []models.Trick
+	updated map[string]models.Trick
+}
+
+func (r *fakeSanitizeTrickRepo) FindTextFields(ctx context.Context) ([]models.Trick, error) {
+	return r.tricks, nil
+}
+
+func (r *fakeSanitizeTrickRepo) UpdateTextFields(ctx context.Context, trick *models.Trick) error {
+	r.updated[trick.ID] = *trick
+	return nil
+}
+
+// fakeSanitizeVideoRepo has no videos
+type fakeSanitizeVideoRepo struct {
+	repository.VideoRepositoryInterface
+}
+
+func (r *fakeSanitizeVideoRepo) FindAll(ctx context.Context) ([]models.TrickVideo, error) {
+	return nil, nil
+}
+
+func TestResanitizeCatalogTrickNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		trick       models.Trick
+		wantUpdated string // cleaned name that was saved, "" if nothing was saved
+		wantInvalid bool
+	}{
+		{name: "clean name untouched", trick: fixtures.Trick().WithSlug("cork").WithName("Cork").Build()},
+		{name: "dirty name cleaned", trick: fixtures.Trick().WithSlug("b-twist").WithName("<b>B-Twist</b>").Build(), wantUpdated: "B-Twist"},
+		{name: "only tags reported", trick: fixtures.Trick().WithSlug("tags").WithName("<script></script>").Build(), wantInvalid: true},
+		{name: "only controls reported", trick: fixtures.Trick().WithSlug("controls").WithName("\x00\u202E ").Build(), wantInvalid: true},
+		{name: "only punctuation reported", trick: fixtures.Trick().WithSlug("dashes").WithName("--").Build(), wantInvalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trickRepo := &fakeSanitizeTrickRepo{tricks: []models.Trick{tt.trick}, updated: map[string]models.Trick{}}
+			service := NewAdminService(trickRepo, &fakeSanitizeVideoRepo{}, nil, nil, nil, nil, false, false, time.Hour,
+				NewDictionaryCache(cache.NewMemory(), time.Minute))
+
+			report, err := service.ResanitizeCatalog(context.Background())
+			if err != nil {
+				t.Fatalf("ResanitizeCatalog() error = %v", err)
+			}
+
+			saved, ok := trickRepo.updated[tt.trick.ID]
+			switch {
+			case tt.wantUpdated == "" && ok:
+				t.Errorf("saved trick with name %q, want no write", saved.Name)
+			case tt.wantUpdated != "" && !ok:
+				t.Errorf("trick was not saved, want name %q", tt.wantUpdated)
+			case ok && saved.Name != tt.wantUpdated:
+				t.Errorf("saved name = %q, want %q", saved.Name, tt.wantUpdated)
+			}
+
+			if got := len(report.InvalidTrickIDs) == 1 && report.InvalidTrickIDs[0] == tt.trick.ID; got != tt.wantInvalid {
+				t.Errorf("InvalidTrickIDs = %v, want invalid = %v", report.InvalidTrickIDs, tt.wantInvalid)
+			}
+			if report.TricksScanned != 1 {
+				t.Errorf("TricksScanned = %d, want 1", report.TricksScanned)
+			}
+		})
+	}
+}
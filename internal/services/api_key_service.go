@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/cache"
+	"tricking-api/internal/config"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/sanitize"
+)
+
+// =============================================================================
+// EXTERNAL API KEYS
+// =============================================================================
+// Third parties (e.g. a stats site) read the catalog with their own key instead
+// of going through the BFF: "Authorization: ApiKey tk_<43 base64url chars>".
+//
+// - An admin issues a key for an owner, with scopes and a rate tier. The key
+//   is returned once; only its SHA-256 hash is stored.
+// - Every request looks the hash up - through a short cache, so a busy key
+//   doesn't cost a query per request. Revoking clears this instance's cache at
+//   once; other instances stop accepting the key within apiKeyCacheTTL.
+// - Malformed keys are rejected without a lookup. Unknown keys aren't cached
+//   (revoked ones are), so random keys can't fill the cache.
+
+// apiKeyPrefix starts every key, so a leaked one is recognisable (secret scanners)
+const apiKeyPrefix = "tk_"
+
+// apiKeyBytes is the key's entropy - 32 random bytes, 43 base64url characters
+const apiKeyBytes = 32
+
+// apiKeyShownPrefix is how much of a key is stored in the clear (prefix + 8 characters)
+const apiKeyShownPrefix = len(apiKeyPrefix) + 8
+
+// apiKeyCacheTTL bounds how long another instance keeps accepting a revoked key
+const apiKeyCacheTTL = time.Minute
+
+// apiKeyCachePrefix namespaces API key lookups in the cache
+const apiKeyCachePrefix = "api_key:"
+
+// MaxAPIKeyOwnerLength bounds an API key's owner label (in characters)
+const MaxAPIKeyOwnerLength = 100
+
+// APIKeyScopes are the scopes a key can be issued with
+var APIKeyScopes = []string{models.APIKeyScopeCatalogRead}
+
+// API key errors
+var (
+	ErrAPIKeyNotFound     = errors.New("API key not found")
+	ErrInvalidAPIKeyOwner = errors.New("API key owner must be 1-100 characters")
+	ErrInvalidAPIKeyScope = errors.New("unknown API key scope")
+	ErrInvalidAPIKeyTier  = errors.New("unknown API key rate tier")
+)
+
+// APIKeyServiceInterface defines the contract for external API keys
+type APIKeyServiceInterface interface {
+	IssueKey(ctx context.Context, req models.APIKeyCreateRequest, createdBy *uuid.UUID) (*models.IssuedAPIKey, error)
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeKey(ctx context.Context, id int64) (*models.APIKey, error)
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+// APIKeyService implements APIKeyServiceInterface
+type APIKeyService struct {
+	apiKeyRepo repository.APIKeyRepositoryInterface
+	cache      cache.Cache
+
+	// tiers are the rate tiers a key can be issued with (config.APIKeyRateLimits)
+	tiers map[string]config.RateLimitConfig
+}
+
+// NewAPIKeyService creates a new APIKeyService instance
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepositoryInterface, store cache.Cache, tiers map[string]config.RateLimitConfig) *APIKeyService {
+	return &APIKeyService{apiKeyRepo: apiKeyRepo, cache: store, tiers: tiers}
+}
+
+// IssueKey creates a key for req.Owner and returns it with its secret
+// Scopes default to catalog:read and the tier to standard.
+func (s *APIKeyService) IssueKey(ctx context.Context, req models.APIKeyCreateRequest, createdBy *uuid.UUID) (*models.IssuedAPIKey, error) {
+	owner := sanitize.Text(req.Owner)
+	if owner == "" || utf8.RuneCountInString(owner) > MaxAPIKeyOwnerLength {
+		return nil, ErrInvalidAPIKeyOwner
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{models.APIKeyScopeCatalogRead}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(APIKeyScopes, scope) {
+			return nil, ErrInvalidAPIKeyScope
+		}
+	}
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	tier := req.RateTier
+	if tier == "" {
+		tier = config.APIKeyTierStandard
+	}
+	if _, ok := s.tiers[tier]; !ok {
+		return nil, ErrInvalidAPIKeyTier
+	}
+
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	issued := &models.IssuedAPIKey{
+		APIKey: models.APIKey{
+			Prefix:    key[:apiKeyShownPrefix],
+			Owner:     owner,
+			Scopes:    scopes,
+			RateTier:  tier,
+			CreatedBy: createdBy,
+		},
+		Key: key,
+	}
+	hash := sha256.Sum256([]byte(key))
+	if err := s.apiKeyRepo.Insert(ctx, &issued.APIKey, hash[:]); err != nil {
+		return nil, fmt.Errorf("failed to issue API key: %w", err)
+	}
+	return issued, nil
+}
+
+// ListKeys returns every key, newest first - never their secrets
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	keys, err := s.apiKeyRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeKey stops a key from working (see the top of the file for how soon)
+func (s *APIKeyService) RevokeKey(ctx context.Context, id int64) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.Revoke(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	// The cache is keyed by hash, which we don't have - drop every cached key
+	s.cache.DeletePrefix(apiKeyCachePrefix)
+	return key, nil
+}
+
+// Authenticate returns the live key matching key, or nil if there is none
+// (malformed, unknown or revoked). An error means the lookup itself failed.
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) ||
+		base64.RawURLEncoding.DecodedLen(len(key)-len(apiKeyPrefix)) != apiKeyBytes {
+		return nil, nil
+	}
+
+	hash := sha256.Sum256([]byte(key))
+	cacheKey := apiKeyCachePrefix + hex.EncodeToString(hash[:])
+	found, ok := s.cache.Get(cacheKey)
+	if !ok {
+		var err error
+		found, err = s.apiKeyRepo.FindByHash(ctx, hash[:])
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to look up API key: %w", err)
+		}
+		// Revoked keys are cached too - a client still sending one costs no queries
+		s.cache.Set(cacheKey, found, apiKeyCacheTTL)
+	}
+
+	apiKey := found.(*models.APIKey)
+	if apiKey.RevokedAt != nil {
+		return nil, nil
+	}
+	return apiKey, nil
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"tricking-api/internal/cache"
+	"tricking-api/internal/config"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeAPIKeyRepo is the api_keys table: rows by hash, counting lookups
+type fakeAPIKeyRepo struct {
+	repository.APIKeyRepositoryInterface
+
+	keys    map[string]*models.APIKey // hex(hash) -> row
+	hashes  [][]byte                  // Every hash inserted
+	lookups int
+	err     error // FindByHash fails
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{keys: map[string]*models.APIKey{}}
+}
+
+func (r *fakeAPIKeyRepo) Insert(ctx context.Context, key *models.APIKey, hash []byte) error {
+	key.ID = int64(len(r.keys) + 1)
+	key.CreatedAt = fixtures.Epoch
+	row := *key
+	r.keys[hex.EncodeToString(hash)] = &row
+	r.hashes = append(r.hashes, hash)
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) FindByHash(ctx context.Context, hash []byte) (*models.APIKey, error) {
+	r.lookups++
+	if r.err != nil {
+		return nil, r.err
+	}
+	row, ok := r.keys[hex.EncodeToString(hash)]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	key := *row
+	return &key, nil
+}
+
+func (r *fakeAPIKeyRepo) Revoke(ctx context.Context, id int64) (*models.APIKey, error) {
+	for _, row := range r.keys {
+		if row.ID == id {
+			revokedAt := fixtures.Epoch.Add(time.Hour)
+			row.RevokedAt = &revokedAt
+			key := *row
+			return &key, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// apiKeyTiers are the default config's rate tiers
+var apiKeyTiers = map[string]config.RateLimitConfig{
+	config.APIKeyTierStandard: {RequestsPerSecond: 5, Burst: 20},
+	config.APIKeyTierPartner:  {RequestsPerSecond: 25, Burst: 100},
+}
+
+func TestIssueKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        models.APIKeyCreateRequest
+		wantOwner  string
+		wantScopes []string
+		wantTier   string
+		wantErr    error
+	}{
+		{
+			name: "defaults", req: models.APIKeyCreateRequest{Owner: "  Stats Site  "},
+			wantOwner: "Stats Site", wantScopes: []string{models.APIKeyScopeCatalogRead}, wantTier: config.APIKeyTierStandard,
+		},
+		{
+			name: "repeated scope, partner tier",
+			req: models.APIKeyCreateRequest{
+				Owner: "Stats Site", Scopes: []string{models.APIKeyScopeCatalogRead, models.APIKeyScopeCatalogRead},
+				RateTier: config.APIKeyTierPartner,
+			},
+			wantOwner: "Stats Site", wantScopes: []string{models.APIKeyScopeCatalogRead}, wantTier: config.APIKeyTierPartner,
+		},
+		{name: "blank owner", req: models.APIKeyCreateRequest{Owner: "   "}, wantErr: ErrInvalidAPIKeyOwner},
+		{name: "long owner", req: models.APIKeyCreateRequest{Owner: strings.Repeat("ö", 101)}, wantErr: ErrInvalidAPIKeyOwner},
+		{name: "unknown scope", req: models.APIKeyCreateRequest{Owner: "Stats Site", Scopes: []string{"catalog:write"}}, wantErr: ErrInvalidAPIKeyScope},
+		{name: "unknown tier", req: models.APIKeyCreateRequest{Owner: "Stats Site", RateTier: "unlimited"}, wantErr: ErrInvalidAPIKeyTier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAPIKeyRepo()
+			service := NewAPIKeyService(repo, cache.NewMemory(), apiKeyTiers)
+			createdBy := fixtures.OwnerID
+
+			issued, err := service.IssueKey(context.Background(), tt.req, &createdBy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IssueKey() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(repo.keys) != 0 {
+					t.Errorf("rejected key was stored: %v", repo.keys)
+				}
+				return
+			}
+
+			if issued.Owner != tt.wantOwner || !reflect.DeepEqual(issued.Scopes, tt.wantScopes) || issued.RateTier != tt.wantTier {
+				t.Errorf("issued %q %v %q, want %q %v %q", issued.Owner, issued.Scopes, issued.RateTier, tt.wantOwner, tt.wantScopes, tt.wantTier)
+			}
+			if !strings.HasPrefix(issued.Key, "tk_") || len(issued.Key) != len("tk_")+43 || issued.Prefix != issued.Key[:11] {
+				t.Errorf("key %q with prefix %q, want tk_ and 43 characters, first 11 shown", issued.Key, issued.Prefix)
+			}
+
+			// Only the SHA-256 of the key is stored - never the key
+			hash := sha256.Sum256([]byte(issued.Key))
+			if len(repo.hashes) != 1 || !bytes.Equal(repo.hashes[0], hash[:]) {
+				t.Errorf("stored hash %x, want %x", repo.hashes, hash)
+			}
+			for _, row := range repo.keys {
+				if strings.Contains(row.Prefix+row.Owner, issued.Key) {
+					t.Errorf("stored row %+v contains the key", row)
+				}
+			}
+		})
+	}
+}
+
+func TestIssueKeyIsRandom(t *testing.T) {
+	service := NewAPIKeyService(newFakeAPIKeyRepo(), cache.NewMemory(), apiKeyTiers)
+
+	seen := map[string]bool{}
+	for range 20 {
+		issued, err := service.IssueKey(context.Background(), models.APIKeyCreateRequest{Owner: "Stats Site"}, nil)
+		if err != nil {
+			t.Fatalf("IssueKey() error = %v", err)
+		}
+		if seen[issued.Key] {
+			t.Fatalf("key %s issued twice", issued.Key)
+		}
+		seen[issued.Key] = true
+	}
+}
+
+func TestAuthenticateCachesLookups(t *testing.T) {
+	repo := newFakeAPIKeyRepo()
+	store := cache.NewMemory()
+	service := NewAPIKeyService(repo, store, apiKeyTiers)
+	ctx := context.Background()
+
+	issued, err := service.IssueKey(ctx, models.APIKeyCreateRequest{Owner: "Stats Site"}, nil)
+	if err != nil {
+		t.Fatalf("IssueKey() error = %v", err)
+	}
+
+	// A busy key costs one lookup
+	for range 3 {
+		key, err := service.Authenticate(ctx, issued.Key)
+		if err != nil || key == nil || key.ID != issued.ID || key.Owner != "Stats Site" {
+			t.Fatalf("Authenticate(issued) = %+v, %v", key, err)
+		}
+	}
+	if repo.lookups != 1 {
+		t.Errorf("%d lookups for 3 requests, want 1", repo.lookups)
+	}
+
+	// Malformed keys never reach the repository; unknown ones do, every time,
+	// without being cached - random keys can't fill the cache
+	repo.lookups = 0
+	unknown := "tk_" + strings.Repeat("A", 43)
+	for _, key := range []string{"", "tk_short", "xx_" + strings.Repeat("A", 43), issued.Key + "A", unknown, unknown} {
+		if got, err := service.Authenticate(ctx, key); got != nil || err != nil {
+			t.Errorf("Authenticate(%q) = %+v, %v; want nil, nil", key, got, err)
+		}
+	}
+	if repo.lookups != 2 {
+		t.Errorf("%d lookups for two unknown keys, want 2", repo.lookups)
+	}
+	if store.Len() != 1 {
+		t.Errorf("cache holds %d entries, want only the issued key", store.Len())
+	}
+
+	// A failed lookup is an error, not a bad key
+	repo.err = errors.New("db down")
+	if key, err := service.Authenticate(ctx, "tk_"+strings.Repeat("B", 43)); key != nil || !errors.Is(err, repo.err) {
+		t.Errorf("Authenticate() with the database down = %+v, %v", key, err)
+	}
+}
+
+func TestRevokeKey(t *testing.T) {
+	repo := newFakeAPIKeyRepo()
+	local, other := cache.NewMemory(), cache.NewMemory()
+	service := NewAPIKeyService(repo, local, apiKeyTiers)
+	otherInstance := NewAPIKeyService(repo, other, apiKeyTiers)
+	ctx := context.Background()
+
+	issued, err := service.IssueKey(ctx, models.APIKeyCreateRequest{Owner: "Stats Site"}, nil)
+	if err != nil {
+		t.Fatalf("IssueKey() error = %v", err)
+	}
+	for _, instance := range []*APIKeyService{service, otherInstance} {
+		if key, _ := instance.Authenticate(ctx, issued.Key); key == nil {
+			t.Fatal("issued key rejected")
+		}
+	}
+
+	revoked, err := service.RevokeKey(ctx, issued.ID)
+	if err != nil || revoked.RevokedAt == nil {
+		t.Fatalf("RevokeKey() = %+v, %v", revoked, err)
+	}
+
+	// This instance stops accepting the key at once, even though it was cached
+	repo.lookups = 0
+	for range 3 {
+		if key, err := service.Authenticate(ctx, issued.Key); key != nil || err != nil {
+			t.Fatalf("Authenticate(revoked) = %+v, %v; want nil, nil", key, err)
+		}
+	}
+	// ...and remembers it's revoked, so a client still sending it costs one lookup
+	if repo.lookups != 1 {
+		t.Errorf("%d lookups for a revoked key, want 1", repo.lookups)
+	}
+	// Another instance keeps its cached copy until apiKeyCacheTTL runs out
+	if key, _ := otherInstance.Authenticate(ctx, issued.Key); key == nil {
+		t.Error("other instance dropped the key before its cache TTL")
+	}
+
+	if _, err := service.RevokeKey(ctx, 999); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("RevokeKey(unknown) error = %v, want %v", err, ErrAPIKeyNotFound)
+	}
+}
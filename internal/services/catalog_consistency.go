@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+)
+
+// consistencyFixBatchSize bounds how many tricks one null_out statement changes
+const consistencyFixBatchSize = 100
+
+// ErrUnknownFixMode indicates a ?fix= value the consistency check doesn't offer
+var ErrUnknownFixMode = errors.New("unknown consistency fix mode")
+
+// CatalogConsistencyInterface defines the contract for trick reference checks
+type CatalogConsistencyInterface interface {
+	Check(ctx context.Context, fix string, changedBy *uuid.UUID) (*models.ConsistencyReport, error)
+}
+
+// CatalogConsistency finds trick references (stances, flip) that point at nothing
+//
+// Historical rows predate some foreign keys, so a deleted stance or category can
+// leave tricks pointing at it. Readers already cope - the dictionary only shows
+// the raw IDs - but the data is still wrong. The scheduled run only reports;
+// clearing is an explicit admin action (fix=null_out).
+type CatalogConsistency struct {
+	trickRepo repository.TrickRepositoryInterface
+
+	// dictionaryCache is invalidated for every trick a fix changes
+	dictionaryCache *DictionaryCache
+}
+
+// NewCatalogConsistency creates a new CatalogConsistency instance
+func NewCatalogConsistency(trickRepo repository.TrickRepositoryInterface, dictionaryCache *DictionaryCache) *CatalogConsistency {
+	return &CatalogConsistency{
+		trickRepo:       trickRepo,
+		dictionaryCache: dictionaryCache,
+	}
+}
+
+// Check reports every broken reference, and with fix = models.ConsistencyFixNullOut clears them
+// fix = "" only reports. Clearing runs in batches per field, each trick getting a
+// trick_revisions entry attributed to changedBy. On error, batches already cleared stay cleared.
+func (s *CatalogConsistency) Check(ctx context.Context, fix string, changedBy *uuid.UUID) (*models.ConsistencyReport, error) {
+	if fix != "" && fix != models.ConsistencyFixNullOut {
+		return nil, ErrUnknownFixMode
+	}
+
+	broken, err := s.trickRepo.FindBrokenReferences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check trick references: %w", err)
+	}
+
+	report := &models.ConsistencyReport{
+		CheckedAt:        time.Now().UTC(),
+		BrokenReferences: broken,
+		Fix:              fix,
+	}
+	if fix == "" || len(broken) == 0 {
+		return report, nil
+	}
+
+	for _, ref := range repository.TrickReferences {
+		for {
+			slugs, err := s.trickRepo.ClearBrokenReferences(ctx, ref.Field, consistencyFixBatchSize, changedBy)
+			report.Cleared += len(slugs)
+			for _, slug := range slugs {
+				s.dictionaryCache.InvalidateTrick(slug)
+			}
+			if err != nil {
+				return report, fmt.Errorf("failed to clear broken trick references: %w", err)
+			}
+			// A short batch means nothing else is broken in this field
+			if len(slugs) < consistencyFixBatchSize {
+				break
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Run checks on an interval until ctx is cancelled, logging what it finds
+// Report-only: dangling references are cleared by an admin, never automatically.
+func (s *CatalogConsistency) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			report, err := s.Check(ctx, "", nil)
+			if err != nil {
+				log.Printf("Warning: %v", err)
+				continue
+			}
+			for _, ref := range report.BrokenReferences {
+				log.Printf("Warning: trick %s has %s = %d, which doesn't exist", ref.TrickID, ref.Field, ref.ReferencedID)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
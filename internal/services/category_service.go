@@ -2,25 +2,34 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"tricking-api/internal/models"
 	"tricking-api/internal/repository"
 )
 
+// ErrCategoryNotFound indicates the requested category doesn't exist
+var ErrCategoryNotFound = errors.New("category not found")
+
 // CategoryServiceInterface defines the contract for category operations
 type CategoryServiceInterface interface {
 	GetAllCategories(ctx context.Context) ([]models.CategoryResponse, error)
+	GetTricksForCategory(ctx context.Context, categoryID int, minDifficulty, maxDifficulty *int64) ([]models.TrickSimpleResponse, error)
 }
 
 // CategoryService implements CategoryServiceInterface
 type CategoryService struct {
 	categoryRepo repository.CategoryRepositoryInterface
+	trickRepo    repository.TrickRepositoryInterface
 }
 
 // NewCategoryService creates a new CategoryService instance
-func NewCategoryService(categoryRepo repository.CategoryRepositoryInterface) *CategoryService {
-	return &CategoryService{categoryRepo: categoryRepo}
+func NewCategoryService(categoryRepo repository.CategoryRepositoryInterface, trickRepo repository.TrickRepositoryInterface) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		trickRepo:    trickRepo,
+	}
 }
 
 // GetAllCategories retrieves all categories for the UI dropdown
@@ -38,3 +47,35 @@ func (s *CategoryService) GetAllCategories(ctx context.Context) ([]models.Catego
 
 	return responses, nil
 }
+
+// GetTricksForCategory lists the live tricks in a category (tricks.flip_id), ordered by name
+// Difficulty bounds are optional and inclusive, as on GET /tricks.
+// Returns ErrCategoryNotFound for an unknown category - an existing but empty one is just [].
+func (s *CategoryService) GetTricksForCategory(ctx context.Context, categoryID int, minDifficulty, maxDifficulty *int64) ([]models.TrickSimpleResponse, error) {
+	if minDifficulty != nil && maxDifficulty != nil && *minDifficulty > *maxDifficulty {
+		return nil, ErrInvalidDifficultyRange
+	}
+
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	tricks, err := s.trickRepo.FindByFilters(ctx, repository.TrickFilters{
+		MinDifficulty: minDifficulty,
+		MaxDifficulty: maxDifficulty,
+		CategoryIDs:   []int{categoryID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category tricks: %w", err)
+	}
+	sortTricksByName(tricks)
+
+	responses := make([]models.TrickSimpleResponse, 0, len(tricks))
+	for _, trick := range tricks {
+		responses = append(responses, models.TrickSimpleResponse{ID: trick.ID, Name: trick.Name})
+	}
+	return responses, nil
+}
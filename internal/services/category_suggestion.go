@@ -0,0 +1,92 @@
+package services
+
+import (
+	"strings"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// CATEGORY SUGGESTIONS
+// =============================================================================
+// Tricks often arrive without a category (flip_id). When the category_suggestions
+// feature is on, the bulk import and POST /tricks ask SuggestCategory for one: a
+// confident suggestion fills the empty flip_id, a weaker one is only reported
+// back (on the import's result line, or in the 201 response) for the client to
+// confirm. A category given in the request is never replaced, and neither is
+// one the trick already has.
+//
+// The rules only look at the trick's name - the import carries no rotation or
+// axis, and stances are bare IDs. Categories are matched by name (case-insensitive),
+// since their IDs differ between databases; a rule whose category doesn't
+// exist is skipped.
+
+// AutoAssignConfidence is the confidence at which a suggestion is applied, not just reported
+const AutoAssignConfidence = 0.8
+
+// ambiguousPenalty scales a suggestion's confidence when another category matched too
+const ambiguousPenalty = 0.5
+
+// categoryRule maps name keywords to a category
+// A name matches when it contains any keyword (lowercased, as a substring -
+// "backfull" contains "full"). Confidence is how sure a match makes us.
+type categoryRule struct {
+	Category   string
+	Keywords   []string
+	Confidence float64
+}
+
+// categoryRules is checked in order; the most confident match wins (ties go to the earlier rule)
+// Keep the most specific words in the most confident rules.
+var categoryRules = []categoryRule{
+	{Category: "kicks", Keywords: []string{"kick", "hook", "crescent", "tornado", "roundhouse", "swipe"}, Confidence: 0.9},
+	{Category: "twists", Keywords: []string{"twist", "cork", "full", "screw"}, Confidence: 0.85},
+	{Category: "flips", Keywords: []string{"flip", "tuck", "pike", "layout", "gainer", "arabian", "webster", "somi"}, Confidence: 0.8},
+	{Category: "transitions", Keywords: []string{"step", "swing", "skip", "vanish", "wrap"}, Confidence: 0.6},
+}
+
+// SuggestCategory picks a category for a trick name, or returns nil
+// categories is every category (see CategoryRepository.FindAll). When names of
+// two different categories match, the winner's confidence is halved, so an
+// ambiguous name ("Cork Kick") is reported but never auto-assigned.
+func SuggestCategory(name string, categories []models.Category) *models.CategorySuggestion {
+	return suggestCategory(categoryRules, name, categories)
+}
+
+// suggestCategory is SuggestCategory over any rule table
+func suggestCategory(rules []categoryRule, name string, categories []models.Category) *models.CategorySuggestion {
+	byName := make(map[string]models.Category, len(categories))
+	for _, category := range categories {
+		byName[strings.ToLower(category.Name)] = category
+	}
+
+	lowerName := strings.ToLower(name)
+	var best *models.CategorySuggestion
+	matched := 0
+	for _, rule := range rules {
+		category, ok := byName[rule.Category]
+		if !ok || !containsAny(lowerName, rule.Keywords) {
+			continue
+		}
+
+		matched++
+		if best == nil || rule.Confidence > best.Confidence {
+			best = &models.CategorySuggestion{ID: category.ID, Name: category.Name, Confidence: rule.Confidence}
+		}
+	}
+
+	if best != nil && matched > 1 {
+		best.Confidence *= ambiguousPenalty
+	}
+	return best
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
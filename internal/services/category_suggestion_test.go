@@ -0,0 +1,162 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// categoryNamed returns the ID of the fixture category called name
+func categoryNamed(t *testing.T, name string) int {
+	t.Helper()
+	for _, category := range fixtures.CatalogCategories() {
+		if strings.EqualFold(category.Name, name) {
+			return category.ID
+		}
+	}
+	t.Fatalf("no fixture category %q", name)
+	return 0
+}
+
+func TestSuggestCategoryKeywords(t *testing.T) {
+	categories := fixtures.CatalogCategories()
+
+	// Every keyword on its own picks its rule's category, at the rule's confidence
+	for _, rule := range categoryRules {
+		for _, keyword := range rule.Keywords {
+			t.Run(rule.Category+"/"+keyword, func(t *testing.T) {
+				got := SuggestCategory("Double "+strings.ToUpper(keyword[:1])+keyword[1:], categories)
+				id := categoryNamed(t, rule.Category)
+				if got == nil || got.ID != id || got.Confidence != rule.Confidence {
+					t.Errorf("SuggestCategory(%q) = %+v, want category %d at %v", keyword, got, id, rule.Confidence)
+				}
+			})
+		}
+	}
+}
+
+func TestSuggestCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		trickName  string
+		categories []models.Category // nil means the fixture categories
+		want       *models.CategorySuggestion
+		wantApply  bool // Confident enough to fill flip_id
+	}{
+		{
+			name: "confident", trickName: "Tornado Kick",
+			want:      &models.CategorySuggestion{ID: fixtures.CategoryKicks, Name: "Kicks", Confidence: 0.9},
+			wantApply: true,
+		},
+		{
+			name: "keyword inside a word", trickName: "Backfull",
+			want:      &models.CategorySuggestion{ID: fixtures.CategoryTwists, Name: "Twists", Confidence: 0.85},
+			wantApply: true,
+		},
+		{
+			name: "any case", trickName: "WEBSTER",
+			want:      &models.CategorySuggestion{ID: fixtures.CategoryFlips, Name: "Flips", Confidence: 0.8},
+			wantApply: true,
+		},
+		{
+			name: "below the threshold", trickName: "Skip Swing",
+			want: &models.CategorySuggestion{ID: fixtures.CategoryTransitions, Name: "Transitions", Confidence: 0.6},
+		},
+		{
+			// Kicks (0.9) beats twists (0.85), but two categories matched
+			name: "ambiguous", trickName: "Cork Kick",
+			want: &models.CategorySuggestion{ID: fixtures.CategoryKicks, Name: "Kicks", Confidence: 0.9 * ambiguousPenalty},
+		},
+		{
+			name: "ambiguous, otherwise confident", trickName: "Gainer Flash Kick",
+			want: &models.CategorySuggestion{ID: fixtures.CategoryKicks, Name: "Kicks", Confidence: 0.9 * ambiguousPenalty},
+		},
+		{name: "no keyword", trickName: "Raiz"},
+		{
+			// No kicks category: the kick rule is skipped, so only twists matched
+			name: "missing category skipped", trickName: "Cork Kick",
+			categories: []models.Category{{ID: 7, Name: "Twists"}, {ID: 8, Name: "Flips"}},
+			want:       &models.CategorySuggestion{ID: 7, Name: "Twists", Confidence: 0.85},
+			wantApply:  true,
+		},
+		{
+			name: "only rule's category missing", trickName: "Tornado Kick",
+			categories: []models.Category{{ID: 7, Name: "Twists"}},
+		},
+		{name: "no categories", trickName: "Tornado Kick", categories: []models.Category{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories := tt.categories
+			if categories == nil {
+				categories = fixtures.CatalogCategories()
+			}
+
+			got := SuggestCategory(tt.trickName, categories)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Fatalf("SuggestCategory(%q) = %+v, want nil", tt.trickName, *got)
+			case tt.want == nil:
+				return
+			case got == nil || *got != *tt.want:
+				t.Fatalf("SuggestCategory(%q) = %+v, want %+v", tt.trickName, got, *tt.want)
+			}
+			if apply := got.Confidence >= AutoAssignConfidence; apply != tt.wantApply {
+				t.Errorf("confidence %v applies = %v, want %v", got.Confidence, apply, tt.wantApply)
+			}
+		})
+	}
+}
+
+func TestSuggestCategoryTieGoesToEarlierRule(t *testing.T) {
+	rules := []categoryRule{
+		{Category: "flips", Keywords: []string{"gainer"}, Confidence: 0.7},
+		{Category: "twists", Keywords: []string{"switch"}, Confidence: 0.7},
+	}
+	got := suggestCategory(rules, "Gainer Switch", fixtures.CatalogCategories())
+	want := models.CategorySuggestion{ID: fixtures.CategoryFlips, Name: "Flips", Confidence: 0.7 * ambiguousPenalty}
+	if got == nil || *got != want {
+		t.Errorf("suggestCategory() = %+v, want %+v", got, want)
+	}
+}
+
+func TestImportCategoryKeepsFlipID(t *testing.T) {
+	service := &AdminService{suggestCategories: true}
+	categories := fixtures.CatalogCategories()
+
+	tests := []struct {
+		name           string
+		flipID         *int
+		wantSuggestion bool
+		wantFlipID     *int
+	}{
+		// "Tornado Kick" would confidently be a kick - a given category still wins
+		{name: "flip_id given", flipID: fixtures.Ptr(fixtures.CategoryTwists), wantFlipID: fixtures.Ptr(fixtures.CategoryTwists)},
+		{name: "no flip_id", wantSuggestion: true, wantFlipID: fixtures.Ptr(fixtures.CategoryKicks)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trick := repository.ImportedTrick{}
+			trick.Name, trick.FlipID = "Tornado Kick", tt.flipID
+
+			suggestion, err := service.importCategory(&trick, categories)
+			if err != nil {
+				t.Fatalf("importCategory() error = %v", err)
+			}
+			if (suggestion != nil) != tt.wantSuggestion {
+				t.Errorf("suggestion = %+v, want one: %v", suggestion, tt.wantSuggestion)
+			}
+			if trick.FlipID == nil || *trick.FlipID != *tt.wantFlipID {
+				t.Errorf("flip_id = %v, want %d", trick.FlipID, *tt.wantFlipID)
+			}
+			if trick.SuggestedFlip != tt.wantSuggestion {
+				t.Errorf("SuggestedFlip = %v, want %v", trick.SuggestedFlip, tt.wantSuggestion)
+			}
+		})
+	}
+}
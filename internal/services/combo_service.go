@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"time"
 
+	"tricking-api/internal/comboalg"
 	"tricking-api/internal/models"
 	"tricking-api/internal/repository"
 )
@@ -15,27 +17,79 @@ import (
 var (
 	ErrInsufficientTricks = errors.New("not enough tricks available for requested combo size")
 	ErrInvalidComboSize   = errors.New("combo size must be at least 1")
+	ErrNoMatchingTrick    = errors.New("no trick matches the filters")
 )
 
+// Generation failure reasons - used as the metrics label, so keep the set small and fixed
+const (
+	ReasonInsufficientCandidates = "insufficient_candidates"
+	ReasonQuotaUnsatisfiable     = "quota_unsatisfiable"
+	ReasonBudgetUnsatisfiable    = "budget_unsatisfiable"
+	ReasonInvalidSize            = "invalid_size"
+	ReasonRepoError              = "repo_error"
+)
+
+// GenerationError is returned by combo generation with a machine-readable reason
+// It wraps the underlying error, so errors.Is(err, ErrInsufficientTricks) still works
+type GenerationError struct {
+	Reason string
+	Err    error
+}
+
+func (e *GenerationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *GenerationError) Unwrap() error {
+	return e.Err
+}
+
+// GenerationFailureReason extracts the reason code from a generation error
+// Errors that didn't come from generation are reported as repo_error
+func GenerationFailureReason(err error) string {
+	var genErr *GenerationError
+	if errors.As(err, &genErr) {
+		return genErr.Reason
+	}
+	return ReasonRepoError
+}
+
+// generationError wraps err with a reason code
+func generationError(reason string, err error) error {
+	return &GenerationError{Reason: reason, Err: err}
+}
+
 type ComboServiceInterface interface {
 	GenerateComboWithFilters(ctx context.Context, req models.ComboGenerateRequest) (*models.GeneratedComboResponse, error)
-	GenerateSimpleCombo(ctx context.Context, size int) (*models.GeneratedComboResponse, error)
+	GenerateSimpleCombo(ctx context.Context, size int, notationStyle string) (*models.GeneratedComboResponse, error)
+	PickRandomTrick(ctx context.Context, filter models.RandomTrickFilter) (*models.TrickDetailResponse, error)
+	EstimateCombo(ctx context.Context, ids []string) (*models.ComboEstimateResponse, error)
 }
 
+// ComboService fetches candidates and hands selection to the comboalg package
 type ComboService struct {
-	trickRepo repository.TrickRepositoryInterface
-	rng       *rand.Rand // Random number generator for combo generation
+	trickRepo  repository.TrickRepositoryInterface
+	stanceRepo repository.StanceRepositoryInterface
+
+	// bands names the random trick's difficulty band
+	bands *DifficultyBands
 }
 
 // NewComboService creates a new ComboService instance
-func NewComboService(trickRepo repository.TrickRepositoryInterface) *ComboService {
+func NewComboService(trickRepo repository.TrickRepositoryInterface, stanceRepo repository.StanceRepositoryInterface, bands *DifficultyBands) *ComboService {
 	return &ComboService{
-		trickRepo: trickRepo,
-		// Create a seeded random generator
-		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+		trickRepo:  trickRepo,
+		stanceRepo: stanceRepo,
+		bands:      bands,
 	}
 }
 
+// newSource seeds a random source for one generation
+// Sources aren't safe for concurrent use, so requests never share one.
+func newSource() rand.Source {
+	return rand.NewSource(time.Now().UnixNano())
+}
+
 // GenerateComboWithFilters creates a new combo based on filters
 // This is the "complicated" version with all filter options
 func (s *ComboService) GenerateComboWithFilters(ctx context.Context, req models.ComboGenerateRequest) (*models.GeneratedComboResponse, error) {
@@ -43,236 +97,210 @@ func (s *ComboService) GenerateComboWithFilters(ctx context.Context, req models.
 	// VALIDATION
 	// ==========================================================================
 	if req.Size < 3 {
-		return nil, ErrInvalidComboSize
+		return nil, generationError(ReasonInvalidSize, ErrInvalidComboSize)
 	}
 
+	// Everything below works from the normalized constraints, so the response's
+	// applied_constraints is exactly what was used
+	applied := NormalizeComboConstraints(req)
+
 	// ==========================================================================
 	// FETCH CANDIDATE TRICKS
 	// ==========================================================================
 	// First, get all tricks that match the filters
 	filters := repository.TrickFilters{
-		MaxDifficulty:   req.MaxDifficulty,
-		CategoryIDs:     req.ExcludeCategoryIDs,
-		ExcludeTrickIDs: req.ExcludeTrickIDs,
+		MaxDifficulty:   applied.MaxDifficulty,
+		CategoryIDs:     applied.CategoryIDs,
+		ExcludeTrickIDs: applied.ExcludeTrickIDs,
 	}
 
 	candidateTricks, err := s.trickRepo.FindByFilters(ctx, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch tricks for combo generation: %w", err)
+		return nil, generationError(ReasonRepoError, fmt.Errorf("failed to fetch tricks for combo generation: %w", err))
 	}
 
 	// Check if we have enough tricks
-	if len(candidateTricks) < req.Size {
-		return nil, fmt.Errorf("%w: need %d tricks, only %d available",
-			ErrInsufficientTricks, req.Size, len(candidateTricks))
+	if len(candidateTricks) < applied.Size {
+		return nil, generationError(ReasonInsufficientCandidates, fmt.Errorf("%w: need %d tricks, only %d available",
+			ErrInsufficientTricks, applied.Size, len(candidateTricks)))
 	}
 
 	// ==========================================================================
 	// COMBO GENERATION ALGORITHM
 	// ==========================================================================
-	// This is where the business logic lives!
-	//
-	// Algorithm options you might implement:
-	// 1. Random selection (simple)
-	// 2. Weighted random (higher weight = more likely)
-	// 3. Flow-based (consider landing_stance -> takeoff_stance compatibility)
-	// 4. Difficulty progression (start easy, build up)
-	// 5. Variety enforcement (no duplicate trick types in a row)
+	// The algorithms themselves live in internal/comboalg (pure functions,
+	// also used by the BFF's offline preview). This service only gathers
+	// their inputs.
 
-	selectedTricks := s.selectTricksWeighted(candidateTricks, req.Size)
+	if !applied.BalanceLegs {
+		selection := comboalg.Select(candidateTricks, comboalg.Options{Count: applied.Size}, newSource())
+		return s.buildComboResponse(selection.Tricks, applied), nil
+	}
+
+	// balance_legs needs to know which leg each landing stance is on
+	stances, err := s.stanceRepo.FindAll(ctx)
+	if err != nil {
+		return nil, generationError(ReasonRepoError, fmt.Errorf("failed to fetch stances for combo generation: %w", err))
+	}
+	legs := make(map[int]string, len(stances))
+	for _, stance := range stances {
+		legs[stance.ID] = stance.Leg
+	}
+
+	selection := comboalg.Select(candidateTricks, comboalg.Options{
+		Count:    applied.Size,
+		Strategy: comboalg.Strategy(applied.Strategy),
+		Legs:     legs,
+	}, newSource())
 
 	// ==========================================================================
 	// BUILD RESPONSE
 	// ==========================================================================
-	return s.buildComboResponse(selectedTricks), nil
+	response := s.buildComboResponse(selection.Tricks, applied)
+	response.RelaxedPositions = selection.RelaxedPositions
+	return response, nil
 }
 
 // GenerateSimpleCombo creates a combo based only on size (no filters)
 // This is the "simple" version
-func (s *ComboService) GenerateSimpleCombo(ctx context.Context, size int) (*models.GeneratedComboResponse, error) {
+func (s *ComboService) GenerateSimpleCombo(ctx context.Context, size int, notationStyle string) (*models.GeneratedComboResponse, error) {
 	if size < 3 {
-		return nil, ErrInvalidComboSize
+		return nil, generationError(ReasonInvalidSize, ErrInvalidComboSize)
 	}
 
 	// Get all tricks (no filters)
 	allTricks, err := s.trickRepo.FindAll(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch tricks: %w", err)
+		return nil, generationError(ReasonRepoError, fmt.Errorf("failed to fetch tricks: %w", err))
 	}
 
 	if len(allTricks) < size {
-		return nil, fmt.Errorf("%w: need %d tricks, only %d available",
-			ErrInsufficientTricks, size, len(allTricks))
+		return nil, generationError(ReasonInsufficientCandidates, fmt.Errorf("%w: need %d tricks, only %d available",
+			ErrInsufficientTricks, size, len(allTricks)))
 	}
-	selectedTricks := s.selectTricksWeighted(allTricks, size)
-	return s.buildComboResponse(selectedTricks), nil
+	selection := comboalg.Select(allTricks, comboalg.Options{Count: size}, newSource())
+	return s.buildComboResponse(selection.Tricks, NormalizeComboConstraints(models.ComboGenerateRequest{Size: size, NotationStyle: notationStyle})), nil
 }
 
-// =============================================================================
-// PRIVATE HELPER METHODS
-// =============================================================================
-
-// selectTricksWeighted selects n tricks using weighted random selection
-// Tricks with higher weight are more likely to be selected
-func (s *ComboService) selectTricksWeighted(candidates []models.Trick, count int) []models.Trick {
-
-	// Make a copy to avoid modifying the original slice
-	available := make([]models.Trick, len(candidates))
-	copy(available, candidates)
-
-	selected := make([]models.Trick, 0, count)
-
-	for i := 0; i < count && len(available) > 0; i++ {
-		// Calculate total weight
-		totalWeight := int64(0)
-		for _, trick := range available {
-			// Ensure minimum weight of 1 to prevent tricks from being impossible to select
-			weight := int64(trick.Weight)
-			if weight < 1 {
-				weight = 1
-			}
-			totalWeight += weight
-		}
-
-		// Pick random point in weight space
-		target := s.rng.Int63n(totalWeight)
-
-		// Find the trick at that point
-		cumulative := int64(0)
-		selectedIdx := 0
-		for idx, trick := range available {
-			weight := int64(trick.Weight)
-			if weight < 1 {
-				weight = 1
-			}
-			cumulative += weight
-			if cumulative > target {
-				selectedIdx = idx
-				break
-			}
-		}
+// PickRandomTrick draws one trick matching the filters, for warm-up drills
+// The draw is weighted exactly like combo generation (weight * decay modifier).
+func (s *ComboService) PickRandomTrick(ctx context.Context, filter models.RandomTrickFilter) (*models.TrickDetailResponse, error) {
+	if filter.MinDifficulty != nil && filter.MaxDifficulty != nil && *filter.MinDifficulty > *filter.MaxDifficulty {
+		return nil, ErrInvalidDifficultyRange
+	}
 
-		// Add to selected and remove from available
-		selected = append(selected, available[selectedIdx])
-		// Remove by swapping with last element and shrinking slice
-		available[selectedIdx] = available[len(available)-1]
-		available = available[:len(available)-1]
+	candidates, err := s.trickRepo.FindByFilters(ctx, repository.TrickFilters{
+		MinDifficulty:   filter.MinDifficulty,
+		MaxDifficulty:   filter.MaxDifficulty,
+		TakeoffStanceID: filter.TakeoffStanceID,
+		LandingStanceID: filter.LandingStanceID,
+		ExcludeSlugs:    filter.Exclude,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tricks for random pick: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoMatchingTrick
 	}
 
-	return selected
+	trick := comboalg.Pick(candidates, newSource())
+	response := trick.ToDetailResponse(responseView(ctx))
+	response.Band = s.bands.Of(trick.Difficulty)
+	return &response, nil
 }
 
-// buildComboResponse creates the API response from selected tricks
-func (s *ComboService) buildComboResponse(tricks []models.Trick) *models.GeneratedComboResponse {
-	// Convert to simple responses
-	trickResponses := make([]models.TrickSimpleResponse, 0, len(tricks))
+// EstimateCombo scores tricks in the order given, without generating or saving anything
+// ids are slugs or legacy numeric IDs, resolved in one query. Unknown ones are
+// dropped before scoring, so transitions are between the tricks that remain.
+func (s *ComboService) EstimateCombo(ctx context.Context, ids []string) (*models.ComboEstimateResponse, error) {
+	found, err := s.trickRepo.GetByIdentifiers(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tricks for combo estimate: %w", err)
+	}
 
-	for _, trick := range tricks {
-		trickResponses = append(trickResponses, trick.ToSimpleResponse())
+	tricks := make([]models.Trick, 0, len(ids))
+	unknown := 0
+	for _, id := range ids {
+		trick, ok := found[id]
+		if !ok {
+			unknown++
+			continue
+		}
+		tricks = append(tricks, trick)
 	}
 
-	return &models.GeneratedComboResponse{
-		Tricks: trickResponses,
+	score := comboalg.ScoreCombo(tricks)
+	response := &models.ComboEstimateResponse{
+		TotalDifficulty: score.TotalDifficulty,
+		Grade:           score.Grade,
+		Transitions:     make([]models.ComboTransition, 0, len(score.Transitions)),
+		UnknownCount:    unknown,
+	}
+	for _, t := range score.Transitions {
+		response.Transitions = append(response.Transitions, models.ComboTransition{
+			From:          t.From,
+			To:            t.To,
+			Compatibility: t.Compatibility,
+		})
 	}
+	return response, nil
 }
 
 // =============================================================================
-// ALTERNATIVE SELECTION ALGORITHMS (for reference)
+// PRIVATE HELPER METHODS
 // =============================================================================
 
-// selectTricksWithFlow considers stance compatibility for smoother combos
-// This is more complex but creates more realistic combos
-func (s *ComboService) selectTricksWithFlow(candidates []models.Trick, count int) []models.Trick {
-	if len(candidates) == 0 || count == 0 {
-		return []models.Trick{}
+// NormalizeComboConstraints turns a generation request into the constraints it applies
+// Every generation path builds its filters from the result and echoes it back,
+// so what clients are shown can't drift from what was used:
+//   - ID lists are copied, sorted and never nil (handlers/params already de-duplicates)
+//   - balance_legs selects the balanced strategy, otherwise weighted
+//   - trick_ids is accepted for compatibility but doesn't affect selection - it's listed as ignored
+//   - no notation_style means arrows
+func NormalizeComboConstraints(req models.ComboGenerateRequest) models.AppliedComboConstraints {
+	applied := models.AppliedComboConstraints{
+		Size:            req.Size,
+		MaxDifficulty:   req.MaxDifficulty,
+		CategoryIDs:     sortedIDs(req.ExcludeCategoryIDs),
+		ExcludeTrickIDs: sortedIDs(req.ExcludeTrickIDs),
+		BalanceLegs:     req.BalanceLegs,
+		Strategy:        string(comboalg.StrategyWeighted),
+		NotationStyle:   req.NotationStyle,
+		IgnoredParams:   []string{},
 	}
-
-	selected := make([]models.Trick, 0, count)
-	available := make([]models.Trick, len(candidates))
-	copy(available, candidates)
-
-	// Pick first trick randomly (weighted)
-	first := s.pickWeightedRandom(available)
-	selected = append(selected, first)
-	available = s.removeTrick(available, first.ID)
-
-	// For subsequent tricks, prefer those where takeoff_stance matches previous landing_stance
-	for i := 1; i < count && len(available) > 0; i++ {
-		lastTrick := selected[i-1]
-
-		// Find tricks with compatible stances
-		compatible := s.filterCompatibleTricks(available, lastTrick.LandingStanceID)
-
-		var nextTrick models.Trick
-		if len(compatible) > 0 {
-			// Pick from compatible tricks
-			nextTrick = s.pickWeightedRandom(compatible)
-		} else {
-			// Fallback to any trick if no compatible ones
-			nextTrick = s.pickWeightedRandom(available)
-		}
-
-		selected = append(selected, nextTrick)
-		available = s.removeTrick(available, nextTrick.ID)
+	if applied.NotationStyle == "" {
+		applied.NotationStyle = string(comboalg.NotationArrows)
 	}
-
-	return selected
-}
-
-// pickWeightedRandom picks a single trick using weighted random selection
-func (s *ComboService) pickWeightedRandom(tricks []models.Trick) models.Trick {
-	if len(tricks) == 1 {
-		return tricks[0]
+	if req.BalanceLegs {
+		applied.Strategy = string(comboalg.StrategyBalanced)
 	}
-
-	totalWeight := int64(0)
-	for _, t := range tricks {
-		w := int64(t.Weight)
-		if w < 1 {
-			w = 1
-		}
-		totalWeight += w
-	}
-
-	target := s.rng.Int63n(totalWeight)
-	cumulative := int64(0)
-
-	for _, t := range tricks {
-		w := int64(t.Weight)
-		if w < 1 {
-			w = 1
-		}
-		cumulative += w
-		if cumulative > target {
-			return t
-		}
+	if len(req.TrickIDs) > 0 {
+		applied.IgnoredParams = append(applied.IgnoredParams, "trick_ids")
 	}
+	return applied
+}
 
-	return tricks[len(tricks)-1] // Fallback
+// sortedIDs returns a sorted copy of ids (empty, not nil, for none)
+func sortedIDs(ids []int) []int {
+	sorted := append([]int{}, ids...)
+	sort.Ints(sorted)
+	return sorted
 }
 
-// filterCompatibleTricks returns tricks where takeoff matches the given landing stance
-func (s *ComboService) filterCompatibleTricks(tricks []models.Trick, landingStanceID *int) []models.Trick {
-	if landingStanceID == nil {
-		return tricks // No landing stance = any trick works
-	}
+// buildComboResponse creates the API response from selected tricks
+func (s *ComboService) buildComboResponse(tricks []models.Trick, applied models.AppliedComboConstraints) *models.GeneratedComboResponse {
+	// Convert to simple responses
+	trickResponses := make([]models.TrickSimpleResponse, 0, len(tricks))
+	names := make([]string, 0, len(tricks))
 
-	compatible := make([]models.Trick, 0)
-	for _, t := range tricks {
-		// Trick is compatible if it has no takeoff requirement OR matches
-		if t.TakeoffStanceID == nil || *t.TakeoffStanceID == *landingStanceID {
-			compatible = append(compatible, t)
-		}
+	for _, trick := range tricks {
+		trickResponses = append(trickResponses, trick.ToSimpleResponse())
+		names = append(names, trick.Name)
 	}
-	return compatible
-}
 
-// removeTrick removes a trick from a slice by ID
-func (s *ComboService) removeTrick(tricks []models.Trick, id string) []models.Trick {
-	for i, t := range tricks {
-		if t.ID == id {
-			return append(tricks[:i], tricks[i+1:]...)
-		}
+	return &models.GeneratedComboResponse{
+		Tricks:             trickResponses,
+		Notation:           comboalg.FormatNotation(names, comboalg.NotationStyle(applied.NotationStyle)),
+		AppliedConstraints: applied,
 	}
-	return tricks
 }
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeComboTrickRepo serves candidate tricks for combo generation
+type fakeComboTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	tricks  []models.Trick
+	err     error
+	filters []repository.TrickFilters // Every FindByFilters call
+}
+
+func (r *fakeComboTrickRepo) FindAll(ctx context.Context) ([]models.Trick, error) {
+	return r.tricks, r.err
+}
+
+func (r *fakeComboTrickRepo) FindByFilters(ctx context.Context, filters repository.TrickFilters) ([]models.Trick, error) {
+	r.filters = append(r.filters, filters)
+	return r.tricks, r.err
+}
+
+// fakeComboStanceRepo serves the fixture stances for leg balancing
+type fakeComboStanceRepo struct {
+	repository.StanceRepositoryInterface
+
+	err error
+}
+
+func (r *fakeComboStanceRepo) FindAll(ctx context.Context) ([]models.Stance, error) {
+	return fixtures.CatalogStances(), r.err
+}
+
+func TestGenerationFailureReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "generation error", err: generationError(ReasonInvalidSize, ErrInvalidComboSize), want: ReasonInvalidSize},
+		{name: "wrapped generation error", err: fmt.Errorf("handler: %w", generationError(ReasonInsufficientCandidates, ErrInsufficientTricks)), want: ReasonInsufficientCandidates},
+		{name: "plain sentinel", err: ErrInsufficientTricks, want: ReasonRepoError},
+		{name: "unrelated error", err: errors.New("boom"), want: ReasonRepoError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GenerationFailureReason(tt.err); got != tt.want {
+				t.Errorf("GenerationFailureReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateComboFailureReasons(t *testing.T) {
+	repoErr := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		simple     bool
+		size       int
+		balance    bool
+		tricks     []models.Trick
+		trickErr   error
+		stanceErr  error
+		wantReason string
+		wantIs     error
+	}{
+		{name: "filtered size too small", size: 2, tricks: fixtures.CatalogTricks(), wantReason: ReasonInvalidSize, wantIs: ErrInvalidComboSize},
+		{name: "filtered repo error", size: 3, trickErr: repoErr, wantReason: ReasonRepoError, wantIs: repoErr},
+		{name: "filtered too few candidates", size: 5, tricks: fixtures.CatalogTricks()[:4], wantReason: ReasonInsufficientCandidates, wantIs: ErrInsufficientTricks},
+		{name: "filtered stance repo error", size: 3, balance: true, tricks: fixtures.CatalogTricks(), stanceErr: repoErr, wantReason: ReasonRepoError, wantIs: repoErr},
+		{name: "simple size too small", simple: true, size: 1, tricks: fixtures.CatalogTricks(), wantReason: ReasonInvalidSize, wantIs: ErrInvalidComboSize},
+		{name: "simple repo error", simple: true, size: 3, trickErr: repoErr, wantReason: ReasonRepoError, wantIs: repoErr},
+		{name: "simple too few candidates", simple: true, size: 3, tricks: fixtures.CatalogTricks()[:2], wantReason: ReasonInsufficientCandidates, wantIs: ErrInsufficientTricks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewComboService(&fakeComboTrickRepo{tricks: tt.tricks, err: tt.trickErr}, &fakeComboStanceRepo{err: tt.stanceErr}, nil)
+
+			var err error
+			if tt.simple {
+				_, err = service.GenerateSimpleCombo(context.Background(), tt.size, "")
+			} else {
+				_, err = service.GenerateComboWithFilters(context.Background(), models.ComboGenerateRequest{Size: tt.size, BalanceLegs: tt.balance})
+			}
+
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := GenerationFailureReason(err); got != tt.wantReason {
+				t.Errorf("reason = %q, want %q", got, tt.wantReason)
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("error %v does not wrap %v", err, tt.wantIs)
+			}
+		})
+	}
+}
+
+func TestGenerateComboFromCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		balance bool
+	}{
+		{name: "plain", size: 5},
+		{name: "balanced legs", size: 5, balance: true},
+		{name: "whole catalog", size: len(fixtures.CatalogTricks())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewComboService(&fakeComboTrickRepo{tricks: fixtures.CatalogTricks()}, &fakeComboStanceRepo{}, nil)
+
+			combo, err := service.GenerateComboWithFilters(context.Background(), models.ComboGenerateRequest{Size: tt.size, BalanceLegs: tt.balance})
+			if err != nil {
+				t.Fatalf("GenerateComboWithFilters() error = %v", err)
+			}
+			if len(combo.Tricks) != tt.size {
+				t.Fatalf("got %d tricks, want %d", len(combo.Tricks), tt.size)
+			}
+
+			seen := map[string]bool{}
+			for _, trick := range combo.Tricks {
+				if seen[trick.ID] {
+					t.Errorf("trick %s picked twice", trick.ID)
+				}
+				seen[trick.ID] = true
+			}
+		})
+	}
+}
+
+func TestNormalizeComboConstraints(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.ComboGenerateRequest
+		want models.AppliedComboConstraints
+	}{
+		{
+			name: "defaults",
+			req:  models.ComboGenerateRequest{Size: 3},
+			want: models.AppliedComboConstraints{
+				Size: 3, CategoryIDs: []int{}, ExcludeTrickIDs: []int{},
+				Strategy: "weighted", NotationStyle: "arrows", IgnoredParams: []string{},
+			},
+		},
+		{
+			name: "every filter",
+			req: models.ComboGenerateRequest{
+				Size: 5, MaxDifficulty: fixtures.Ptr(int64(7)),
+				ExcludeCategoryIDs: []int{9, 2, 5}, ExcludeTrickIDs: []int{30, 4, 12},
+				NotationStyle: "numbered",
+			},
+			want: models.AppliedComboConstraints{
+				Size: 5, MaxDifficulty: fixtures.Ptr(int64(7)),
+				CategoryIDs: []int{2, 5, 9}, ExcludeTrickIDs: []int{4, 12, 30},
+				Strategy: "weighted", NotationStyle: "numbered", IgnoredParams: []string{},
+			},
+		},
+		{
+			name: "balance_legs selects the balanced strategy",
+			req:  models.ComboGenerateRequest{Size: 4, BalanceLegs: true, NotationStyle: "dashes"},
+			want: models.AppliedComboConstraints{
+				Size: 4, CategoryIDs: []int{}, ExcludeTrickIDs: []int{},
+				BalanceLegs: true, Strategy: "balanced", NotationStyle: "dashes", IgnoredParams: []string{},
+			},
+		},
+		{
+			name: "trick_ids is reported as ignored",
+			req:  models.ComboGenerateRequest{Size: 3, TrickIDs: []int{1, 2}},
+			want: models.AppliedComboConstraints{
+				Size: 3, CategoryIDs: []int{}, ExcludeTrickIDs: []int{},
+				Strategy: "weighted", NotationStyle: "arrows", IgnoredParams: []string{"trick_ids"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories := append([]int(nil), tt.req.ExcludeCategoryIDs...)
+			excluded := append([]int(nil), tt.req.ExcludeTrickIDs...)
+
+			got := NormalizeComboConstraints(tt.req)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeComboConstraints() = %+v, want %+v", got, tt.want)
+			}
+			// Sorting works on copies - the caller's slices are untouched
+			if !reflect.DeepEqual(tt.req.ExcludeCategoryIDs, categories) || !reflect.DeepEqual(tt.req.ExcludeTrickIDs, excluded) {
+				t.Errorf("request ID lists modified: %v, %v", tt.req.ExcludeCategoryIDs, tt.req.ExcludeTrickIDs)
+			}
+		})
+	}
+}
+
+func TestGenerateComboEchoesAppliedConstraints(t *testing.T) {
+	tests := []struct {
+		name   string
+		simple bool
+		req    models.ComboGenerateRequest
+	}{
+		{name: "filtered", req: models.ComboGenerateRequest{
+			Size: 4, MaxDifficulty: fixtures.Ptr(int64(6)), ExcludeCategoryIDs: []int{3, 1}, ExcludeTrickIDs: []int{8, 2},
+			TrickIDs: []int{5}, NotationStyle: "dashes",
+		}},
+		{name: "filtered balanced", req: models.ComboGenerateRequest{Size: 3, BalanceLegs: true}},
+		{name: "simple", simple: true, req: models.ComboGenerateRequest{Size: 5, NotationStyle: "numbered"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeComboTrickRepo{tricks: fixtures.CatalogTricks()}
+			service := NewComboService(repo, &fakeComboStanceRepo{}, nil)
+
+			var combo *models.GeneratedComboResponse
+			var err error
+			if tt.simple {
+				combo, err = service.GenerateSimpleCombo(context.Background(), tt.req.Size, tt.req.NotationStyle)
+			} else {
+				combo, err = service.GenerateComboWithFilters(context.Background(), tt.req)
+			}
+			if err != nil {
+				t.Fatalf("generate error = %v", err)
+			}
+
+			want := NormalizeComboConstraints(tt.req)
+			if !reflect.DeepEqual(combo.AppliedConstraints, want) {
+				t.Errorf("applied_constraints = %+v, want %+v", combo.AppliedConstraints, want)
+			}
+			// What's echoed is what the repository was asked for
+			if !tt.simple {
+				if len(repo.filters) != 1 {
+					t.Fatalf("FindByFilters called %d times, want 1", len(repo.filters))
+				}
+				filters := repo.filters[0]
+				if !reflect.DeepEqual(filters.MaxDifficulty, want.MaxDifficulty) ||
+					!reflect.DeepEqual(filters.CategoryIDs, want.CategoryIDs) ||
+					!reflect.DeepEqual(filters.ExcludeTrickIDs, want.ExcludeTrickIDs) {
+					t.Errorf("filters = %+v, applied = %+v", filters, want)
+				}
+			}
+		})
+	}
+}
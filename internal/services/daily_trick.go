@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// TRICK OF THE DAY
+// =============================================================================
+// One featured trick per UTC day, the same for every client and every API
+// instance, with nothing stored: the date is hashed (FNV-1a over "2006-01-02")
+// and taken modulo the number of live tricks, ordered by slug.
+//
+// Adding or deleting a trick changes the count, so it can change the pick for
+// days that haven't been shown yet - but never mid-day for a catalog that
+// didn't change, and never from one API instance to the next.
+
+// DailyTrickSlug picks the trick of the day for date from slugs (sorted, live tricks)
+// Only the UTC calendar date of date matters. Returns "" for an empty catalog.
+func DailyTrickSlug(date time.Time, slugs []string) string {
+	if len(slugs) == 0 {
+		return ""
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(date.UTC().Format(time.DateOnly)))
+	return slugs[hash.Sum64()%uint64(len(slugs))]
+}
+
+// GetDailyTrick returns the full details of the trick of the day for date
+// Every dictionary section is included (featured video and all), and the
+// dictionary cache is shared with GET /tricks/:id/dictionary.
+// Returns ErrTrickNotFound when there are no live tricks.
+func (s *TrickService) GetDailyTrick(ctx context.Context, date time.Time, locale string) (*models.TrickDictionaryResponse, error) {
+	rows, err := s.trickRepo.FindSlugs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trick slugs: %w", err)
+	}
+
+	// FindSlugs is ordered by slug - the order the pick is defined over
+	slugs := make([]string, len(rows))
+	for i, row := range rows {
+		slugs[i] = row.Slug
+	}
+
+	slug := DailyTrickSlug(date, slugs)
+	if slug == "" {
+		return nil, ErrTrickNotFound
+	}
+	return s.GetTrickDictionary(ctx, slug, dictionaryIncludes, locale)
+}
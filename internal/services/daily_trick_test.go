@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// catalogSlugs is the fixture catalog's slugs in FindSlugs order
+func catalogSlugs() []string {
+	var slugs []string
+	for _, trick := range fixtures.CatalogTricks() {
+		slugs = append(slugs, trick.Slug)
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+func TestDailyTrickSlug(t *testing.T) {
+	small := []string{"aerial", "backflip", "butterfly-kick", "cork", "raiz"}
+	grown := append(catalogSlugs(), "zz-new-trick")
+
+	tests := []struct {
+		date  string
+		slugs []string
+		want  string
+	}{
+		{date: "2024-06-01", slugs: small, want: "cork"},
+		{date: "2024-06-02", slugs: small, want: "butterfly-kick"},
+		{date: "2024-01-01", slugs: catalogSlugs(), want: "skip-hook"},
+		{date: "2024-02-29", slugs: catalogSlugs(), want: "butterfly-kick"},
+		{date: "2024-06-01", slugs: catalogSlugs(), want: "gainer"},
+		{date: "2024-06-02", slugs: catalogSlugs(), want: "full"},
+		{date: "2024-12-31", slugs: catalogSlugs(), want: "cheat-720"},
+		{date: "2025-01-01", slugs: catalogSlugs(), want: "raiz"},
+		// A new trick changes the count, and with it the pick
+		{date: "2024-06-01", slugs: grown, want: "snapuswipe"},
+		{date: "2024-06-01", slugs: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		date, err := time.Parse(time.DateOnly, tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := DailyTrickSlug(date, tt.slugs); got != tt.want {
+			t.Errorf("DailyTrickSlug(%s, %d slugs) = %q, want %q", tt.date, len(tt.slugs), got, tt.want)
+		}
+	}
+}
+
+func TestDailyTrickSlugSameAllUTCDay(t *testing.T) {
+	slugs := catalogSlugs()
+	want := DailyTrickSlug(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), slugs)
+
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	tests := []struct {
+		name string
+		at   time.Time
+	}{
+		{name: "last second of the day", at: time.Date(2024, time.June, 1, 23, 59, 59, 0, time.UTC)},
+		{name: "midday", at: time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)},
+		// 08:00 on June 2nd in Tokyo is still June 1st in UTC
+		{name: "other zone", at: time.Date(2024, time.June, 2, 8, 0, 0, 0, tokyo)},
+	}
+
+	for _, tt := range tests {
+		if got := DailyTrickSlug(tt.at, slugs); got != want {
+			t.Errorf("%s: DailyTrickSlug(%s) = %q, want %q", tt.name, tt.at, got, want)
+		}
+	}
+
+	if next := DailyTrickSlug(time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC), slugs); next == want {
+		t.Errorf("pick didn't change at midnight UTC: %q", next)
+	}
+}
+
+// fakeDailyTrickRepo lists slugs only
+type fakeDailyTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	slugs []models.TrickSlugResponse
+	err   error
+}
+
+func (r *fakeDailyTrickRepo) FindSlugs(ctx context.Context) ([]models.TrickSlugResponse, error) {
+	return r.slugs, r.err
+}
+
+func TestGetDailyTrickWithoutTricks(t *testing.T) {
+	repoErr := errors.New("db down")
+
+	tests := []struct {
+		name    string
+		repo    *fakeDailyTrickRepo
+		wantErr error
+	}{
+		{name: "empty catalog", repo: &fakeDailyTrickRepo{}, wantErr: ErrTrickNotFound},
+		{name: "repository error", repo: &fakeDailyTrickRepo{err: repoErr}, wantErr: repoErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTrickService(tt.repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, 7, NewDifficultyBands(nil), 0, 0, 0, false)
+
+			if _, err := service.GetDailyTrick(context.Background(), fixtures.Epoch, ""); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetDailyTrick() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"tricking-api/internal/cache"
+	"tricking-api/internal/metrics"
+	"tricking-api/internal/models"
+)
+
+// dictionaryCacheRequests counts dictionary cache lookups by result (hit, miss)
+// Hit rate = hit / (hit + miss)
+var dictionaryCacheRequests = metrics.NewCounterVec(
+	"dictionary_cache_requests_total",
+	"Trick dictionary cache lookups, labelled by result",
+	"result",
+)
+
+// dictionaryKeyPrefix namespaces dictionary entries in the shared cache
+const dictionaryKeyPrefix = "dictionary:"
+
+// DictionaryCache caches assembled trick dictionaries
+//
+// KEY: dictionary:<slug>:<sorted include set>:<locale>
+// Every entry for a trick shares the "dictionary:<slug>:" prefix, so a write to
+// that trick (or its videos) invalidates all of its variants and nothing else.
+//
+// Includes that vary per user must never be cached - when one is added,
+// GetTrickDictionary has to skip the cache for requests that use it.
+type DictionaryCache struct {
+	store cache.Cache
+	ttl   time.Duration
+}
+
+// NewDictionaryCache creates a new DictionaryCache instance
+// A ttl of zero disables caching (every lookup is a miss, nothing is stored)
+func NewDictionaryCache(store cache.Cache, ttl time.Duration) *DictionaryCache {
+	return &DictionaryCache{store: store, ttl: ttl}
+}
+
+// dictionaryCacheKey builds the key for one dictionary variant
+// includes must already be sorted (ParseDictionaryIncludes does that)
+func dictionaryCacheKey(slug string, includes []string, locale string) string {
+	return dictionaryTrickPrefix(slug) + strings.Join(includes, ",") + ":" + locale
+}
+
+// dictionaryTrickPrefix is the prefix shared by every cached variant of one trick
+func dictionaryTrickPrefix(slug string) string {
+	return dictionaryKeyPrefix + slug + ":"
+}
+
+// get returns a cached dictionary - callers must not modify it
+func (d *DictionaryCache) get(key string) (*models.TrickDictionaryResponse, bool) {
+	if d == nil || d.ttl <= 0 {
+		return nil, false
+	}
+
+	if value, ok := d.store.Get(key); ok {
+		if dictionary, ok := value.(*models.TrickDictionaryResponse); ok {
+			dictionaryCacheRequests.Inc("hit")
+			return dictionary, true
+		}
+	}
+	dictionaryCacheRequests.Inc("miss")
+	return nil, false
+}
+
+// set stores a dictionary under key
+func (d *DictionaryCache) set(key string, dictionary *models.TrickDictionaryResponse) {
+	if d == nil || d.ttl <= 0 {
+		return
+	}
+	d.store.Set(key, dictionary, d.ttl)
+}
+
+// InvalidateTrick drops every cached variant of one trick
+// Call after any write that changes what the trick's dictionary shows
+func (d *DictionaryCache) InvalidateTrick(slug string) {
+	if d == nil {
+		return
+	}
+	d.store.DeletePrefix(dictionaryTrickPrefix(slug))
+}
+
+// InvalidateAll drops every cached dictionary (for bulk rewrites)
+func (d *DictionaryCache) InvalidateAll() {
+	if d == nil {
+		return
+	}
+	d.store.DeletePrefix(dictionaryKeyPrefix)
+}
+
+// Len returns how many entries the underlying store holds (for GET /admin/runtime)
+func (d *DictionaryCache) Len() int {
+	if d == nil {
+		return 0
+	}
+	return d.store.Len()
+}
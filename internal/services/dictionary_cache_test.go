@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tricking-api/internal/cache"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeUploadVideoRepo accepts videos for the tricks it knows
+type fakeUploadVideoRepo struct {
+	repository.VideoRepositoryInterface
+
+	known map[string]bool
+}
+
+func (r *fakeUploadVideoRepo) Create(ctx context.Context, trickSlug string, video *models.TrickVideo) error {
+	if !r.known[trickSlug] {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func TestCreateVideoInvalidatesOnlyThatTrick(t *testing.T) {
+	includeSets := [][]string{nil, {"videos"}, {"mistakes", "prerequisites", "videos"}}
+	locales := []string{"en", "es"}
+	slugs := []string{"cork", "cork-2", "corkscrew"}
+
+	tests := []struct {
+		name       string
+		slug       string // Video uploaded for
+		wantErr    error
+		wantMissed map[string]bool
+	}{
+		// "cork-2" and "corkscrew" start with "cork" - the ":" ending the prefix keeps them
+		{name: "upload for cork", slug: "cork", wantMissed: map[string]bool{"cork": true}},
+		{name: "upload for cork-2", slug: "cork-2", wantMissed: map[string]bool{"cork-2": true}},
+		{name: "unknown trick", slug: "cor", wantErr: ErrTrickNotFound, wantMissed: map[string]bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dictionaries := NewDictionaryCache(cache.NewMemory(), time.Minute)
+			for _, slug := range slugs {
+				for _, includes := range includeSets {
+					for _, locale := range locales {
+						dictionaries.set(dictionaryCacheKey(slug, includes, locale), &models.TrickDictionaryResponse{})
+					}
+				}
+			}
+
+			videoRepo := &fakeUploadVideoRepo{known: map[string]bool{"cork": true, "cork-2": true, "corkscrew": true}}
+			service := NewAdminService(nil, videoRepo, nil, nil, nil, nil, false, false, time.Hour, dictionaries)
+			req := models.VideoCreateRequest{
+				VideoURL:      "https://youtu.be/dQw4w9WgXcQ",
+				ThumbnailURL:  "https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg",
+				PerformerName: "Someone",
+			}
+			if _, err := service.CreateVideo(context.Background(), tt.slug, req, fixtures.OwnerID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateVideo() error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, slug := range slugs {
+				for _, includes := range includeSets {
+					for _, locale := range locales {
+						_, hit := dictionaries.get(dictionaryCacheKey(slug, includes, locale))
+						if hit == tt.wantMissed[slug] {
+							t.Errorf("%s %v %s: hit = %v, want %v", slug, includes, locale, hit, !tt.wantMissed[slug])
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"tricking-api/internal/config"
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// DIFFICULTY BANDS
+// =============================================================================
+// Bands ("beginner", "advanced", ...) are names for difficulty ranges, defined
+// in config (DIFFICULTY_BANDS) rather than code, so their boundaries can move
+// without a release. config.Load has already checked that they don't overlap
+// or leave gaps. A band is only ever a shorthand: filters translate it to its
+// range, and trick responses derive it from the difficulty.
+
+// DifficultyBands looks up the configured bands
+type DifficultyBands struct {
+	bands []models.DifficultyBand // Lowest first
+}
+
+// NewDifficultyBands creates a DifficultyBands from validated config (lowest band first)
+func NewDifficultyBands(bands []config.DifficultyBand) *DifficultyBands {
+	converted := make([]models.DifficultyBand, len(bands))
+	for i, band := range bands {
+		converted[i] = models.DifficultyBand{Name: band.Name, MinDifficulty: band.Min, MaxDifficulty: band.Max}
+	}
+	return &DifficultyBands{bands: converted}
+}
+
+// All returns every band, lowest first
+func (b *DifficultyBands) All() []models.DifficultyBand {
+	return b.bands
+}
+
+// Names returns every band name, lowest first
+func (b *DifficultyBands) Names() []string {
+	names := make([]string, len(b.bands))
+	for i, band := range b.bands {
+		names[i] = band.Name
+	}
+	return names
+}
+
+// Find returns the band with the given name
+func (b *DifficultyBands) Find(name string) (models.DifficultyBand, bool) {
+	for _, band := range b.bands {
+		if band.Name == name {
+			return band, true
+		}
+	}
+	return models.DifficultyBand{}, false
+}
+
+// Of returns the name of the band a difficulty falls in
+// nil for an unrated trick or a difficulty outside every band.
+func (b *DifficultyBands) Of(difficulty *int64) *string {
+	if difficulty == nil {
+		return nil
+	}
+	for _, band := range b.bands {
+		if *difficulty >= band.MinDifficulty && *difficulty <= band.MaxDifficulty {
+			name := band.Name
+			return &name
+		}
+	}
+	return nil
+}
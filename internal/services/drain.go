@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"tricking-api/internal/models"
+)
+
+// DrainServiceInterface defines the contract for taking this instance out of rotation
+type DrainServiceInterface interface {
+	Drain(reason string) *models.DrainStatus
+	Status() *models.DrainStatus
+}
+
+// DrainService tracks whether this instance is draining
+// Once it is, /health/ready answers 503 so the load balancer stops sending new
+// traffic; requests that still arrive are served as usual. main starts the
+// drain on SIGTERM, admins with POST /admin/drain. There is no way back short
+// of a restart - a draining instance is on its way out.
+type DrainService struct {
+	mu     sync.Mutex
+	status models.DrainStatus
+}
+
+// NewDrainService creates a new DrainService instance (not draining)
+func NewDrainService() *DrainService {
+	return &DrainService{}
+}
+
+// Drain starts draining and returns the status; draining again keeps the first start and reason
+func (s *DrainService) Drain(reason string) *models.DrainStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.status.Draining {
+		since := time.Now().UTC()
+		s.status = models.DrainStatus{Draining: true, Since: &since, Reason: reason}
+		log.Printf("Draining (%s): /health/ready now reports 503", reason)
+	}
+	status := s.status
+	return &status
+}
+
+// Status returns whether the instance is draining, since when and why
+func (s *DrainService) Status() *models.DrainStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	return &status
+}
+
+// Shutdowner is what the shutdown sequence stops - the *http.Server in main
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// Shutdown takes the instance out of rotation, then stops it, in this order:
+//  1. readiness fails (Drain with reason)
+//  2. delay passes - whatever still arrives is served; a value on skip cuts it short
+//  3. srv.Shutdown waits up to timeout for in-flight requests
+//  4. stopWorkers stops the background jobs
+//
+// A failed srv.Shutdown is returned before the workers are stopped.
+func (s *DrainService) Shutdown(srv Shutdowner, reason string, delay, timeout time.Duration, skip <-chan os.Signal, stopWorkers func()) error {
+	// The load balancer keeps sending traffic for a few seconds after SIGTERM,
+	// and it must still be served until it stops
+	s.Drain(reason)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-skip:
+	}
+
+	log.Println("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	// No more requests are coming in
+	stopWorkers()
+	return nil
+}
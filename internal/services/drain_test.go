@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// shutdownSteps records the shutdown sequence as it runs
+type shutdownSteps struct {
+	mu    sync.Mutex
+	start time.Time
+	steps []string
+	at    map[string]time.Duration // Since start
+}
+
+func newShutdownSteps() *shutdownSteps {
+	return &shutdownSteps{start: time.Now(), at: map[string]time.Duration{}}
+}
+
+func (s *shutdownSteps) record(step string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, step)
+	s.at[step] = time.Since(s.start)
+}
+
+func (s *shutdownSteps) list() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.steps...)
+}
+
+// fakeShutdowner is the server: it records the shutdown, whether readiness
+// was already failing then, and how long it was given
+type fakeShutdowner struct {
+	steps *shutdownSteps
+	drain *DrainService
+	err   error
+
+	drainingAtShutdown bool
+	deadline           time.Duration
+}
+
+func (f *fakeShutdowner) Shutdown(ctx context.Context) error {
+	f.steps.record("server")
+	f.drainingAtShutdown = f.drain.Status().Draining
+	if deadline, ok := ctx.Deadline(); ok {
+		f.deadline = time.Until(deadline)
+	}
+	return f.err
+}
+
+func TestShutdown(t *testing.T) {
+	const timeout = 30 * time.Second
+	shutdownErr := errors.New("context deadline exceeded")
+	signalled := make(chan os.Signal, 1)
+	signalled <- syscall.SIGTERM
+
+	tests := []struct {
+		name      string
+		delay     time.Duration
+		skip      chan os.Signal
+		err       error
+		wantSteps []string
+		wantWait  time.Duration // Minimum time before the server shut down
+		wantFast  bool          // Server shut down long before delay
+	}{
+		{name: "waits out the delay", delay: 50 * time.Millisecond, wantSteps: []string{"server", "workers"}, wantWait: 50 * time.Millisecond},
+		{name: "no delay", delay: 0, wantSteps: []string{"server", "workers"}},
+		{name: "second signal skips the delay", delay: time.Hour, skip: signalled, wantSteps: []string{"server", "workers"}, wantFast: true},
+		{name: "failed shutdown leaves the workers", delay: 0, err: shutdownErr, wantSteps: []string{"server"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drain := NewDrainService()
+			steps := newShutdownSteps()
+			srv := &fakeShutdowner{steps: steps, drain: drain, err: tt.err}
+
+			err := drain.Shutdown(srv, "terminated", tt.delay, timeout, tt.skip, func() { steps.record("workers") })
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Shutdown() error = %v, want %v", err, tt.err)
+			}
+
+			if got := steps.list(); !reflect.DeepEqual(got, tt.wantSteps) {
+				t.Errorf("steps = %v, want %v", got, tt.wantSteps)
+			}
+			if !srv.drainingAtShutdown {
+				t.Error("readiness still passing when the server shut down")
+			}
+			if status := drain.Status(); status.Reason != "terminated" {
+				t.Errorf("drain reason = %q, want the signal", status.Reason)
+			}
+			if waited := steps.at["server"]; waited < tt.wantWait || (tt.wantFast && waited > time.Second) {
+				t.Errorf("server shut down after %v, want at least %v", waited, tt.wantWait)
+			}
+			if srv.deadline <= timeout-time.Second || srv.deadline > timeout {
+				t.Errorf("shutdown deadline in %v, want %v", srv.deadline, timeout)
+			}
+		})
+	}
+}
+
+func TestShutdownDrainsFirst(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	drain := NewDrainService()
+	steps := newShutdownSteps()
+	srv := &fakeShutdowner{steps: steps, drain: drain}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- drain.Shutdown(srv, "terminated", delay, time.Second, nil, func() { steps.record("workers") })
+	}()
+
+	// Readiness fails right away, while the server is still up for the delay
+	for !drain.Status().Draining {
+		if time.Since(steps.start) > delay/2 {
+			t.Fatal("readiness still passing halfway through the drain delay")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := steps.list(); len(got) != 0 {
+		t.Errorf("steps %v ran before the drain delay", got)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := steps.list(); !reflect.DeepEqual(got, []string{"server", "workers"}) {
+		t.Errorf("steps = %v, want server then workers", got)
+	}
+}
+
+func TestDrainKeepsFirstStart(t *testing.T) {
+	drain := NewDrainService()
+	if drain.Status().Draining {
+		t.Fatal("new instance is draining")
+	}
+
+	first := drain.Drain("admin")
+	again := drain.Drain("terminated")
+	if !again.Draining || again.Reason != "admin" || !again.Since.Equal(*first.Since) {
+		t.Errorf("second drain = %+v, want the first's start and reason %+v", again, first)
+	}
+}
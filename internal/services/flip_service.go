@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+)
+
+// FlipServiceInterface defines the contract for flip operations
+type FlipServiceInterface interface {
+	GetAllFlips(ctx context.Context) ([]models.FlipResponse, error)
+}
+
+// FlipService implements FlipServiceInterface
+type FlipService struct {
+	flipRepo repository.FlipRepositoryInterface
+}
+
+// NewFlipService creates a new FlipService instance
+func NewFlipService(flipRepo repository.FlipRepositoryInterface) *FlipService {
+	return &FlipService{flipRepo: flipRepo}
+}
+
+// GetAllFlips retrieves every flip family (what a trick's flip_id points at)
+func (s *FlipService) GetAllFlips(ctx context.Context) ([]models.FlipResponse, error) {
+	flips, err := s.flipRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flips: %w", err)
+	}
+
+	responses := make([]models.FlipResponse, 0, len(flips))
+	for _, flip := range flips {
+		responses = append(responses, flip.ToResponse())
+	}
+
+	return responses, nil
+}
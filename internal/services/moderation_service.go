@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/sanitize"
+)
+
+// MaxMistakeTextLength caps one common-mistake entry (in characters)
+const MaxMistakeTextLength = 500
+
+// ErrInvalidMistakeText indicates mistake text that is empty or too long
+var ErrInvalidMistakeText = errors.New("mistake text must be 1-500 characters")
+
+// ErrInvalidMistakeSeverity indicates a severity outside the fixed set
+var ErrInvalidMistakeSeverity = errors.New("mistake severity must be minor, major or critical")
+
+// ErrMistakeNotFound indicates the mistake doesn't exist on that trick
+var ErrMistakeNotFound = errors.New("mistake not found")
+
+// ErrMistakeOrderMismatch indicates a reorder that doesn't list exactly the trick's mistakes
+var ErrMistakeOrderMismatch = errors.New("mistake order must list every mistake of the trick exactly once")
+
+// ErrUnknownPrerequisite indicates a prerequisite that isn't a live trick
+var ErrUnknownPrerequisite = errors.New("prerequisite trick not found")
+
+// ErrPrerequisiteCycle indicates a prerequisite that would make a trick its own (transitive) prerequisite
+var ErrPrerequisiteCycle = errors.New("prerequisite would create a cycle")
+
+// ErrPrerequisiteNotFound indicates the trick doesn't have that prerequisite
+var ErrPrerequisiteNotFound = errors.New("prerequisite not found")
+
+// MaxTagLength caps a tag name (in characters)
+const MaxTagLength = 50
+
+// ErrInvalidTag indicates a tag name that is empty, too long or contains a comma
+var ErrInvalidTag = errors.New("tag must be 1-50 characters without commas")
+
+// ErrTagNotFound indicates the tag doesn't exist, or the trick doesn't carry it
+var ErrTagNotFound = errors.New("tag not found")
+
+// mistakeSeverities is the fixed severity enum - validated here, not by the database
+var mistakeSeverities = map[string]bool{
+	models.MistakeSeverityMinor:    true,
+	models.MistakeSeverityMajor:    true,
+	models.MistakeSeverityCritical: true,
+}
+
+// ModerationServiceInterface defines the contract for moderator content curation
+type ModerationServiceInterface interface {
+	CreateMistake(ctx context.Context, trickSlug string, req models.MistakeRequest) (*models.TrickMistake, error)
+	UpdateMistake(ctx context.Context, trickSlug string, id int64, req models.MistakeRequest) (*models.TrickMistake, error)
+	DeleteMistake(ctx context.Context, trickSlug string, id int64) error
+	ReorderMistakes(ctx context.Context, trickSlug string, ids []int64) ([]models.TrickMistake, error)
+	AddPrerequisite(ctx context.Context, trickSlug, prerequisiteSlug string) (bool, error)
+	RemovePrerequisite(ctx context.Context, trickSlug, prerequisiteSlug string) error
+	CreateTag(ctx context.Context, name string) (string, bool, error)
+	DeleteTag(ctx context.Context, name string) error
+	TagTrick(ctx context.Context, trickSlug, name string) (string, bool, error)
+	UntagTrick(ctx context.Context, trickSlug, name string) error
+}
+
+// ModerationService implements ModerationServiceInterface
+type ModerationService struct {
+	mistakeRepo repository.MistakeRepositoryInterface
+	prereqRepo  repository.PrerequisiteRepositoryInterface
+	tagRepo     repository.TagRepositoryInterface
+
+	// dictionaryCache is invalidated after every write - mistakes,
+	// prerequisites and tags are part of the dictionary
+	dictionaryCache *DictionaryCache
+}
+
+// NewModerationService creates a new ModerationService instance
+func NewModerationService(
+	mistakeRepo repository.MistakeRepositoryInterface,
+	prereqRepo repository.PrerequisiteRepositoryInterface,
+	tagRepo repository.TagRepositoryInterface,
+	dictionaryCache *DictionaryCache,
+) *ModerationService {
+	return &ModerationService{
+		mistakeRepo:     mistakeRepo,
+		prereqRepo:      prereqRepo,
+		tagRepo:         tagRepo,
+		dictionaryCache: dictionaryCache,
+	}
+}
+
+// CreateMistake adds a common mistake at the end of a trick's list
+func (s *ModerationService) CreateMistake(ctx context.Context, trickSlug string, req models.MistakeRequest) (*models.TrickMistake, error) {
+	mistake, err := cleanMistakeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mistakeRepo.Create(ctx, trickSlug, mistake); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		return nil, fmt.Errorf("failed to create mistake: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return mistake, nil
+}
+
+// UpdateMistake replaces the text and severity of one of a trick's mistakes
+func (s *ModerationService) UpdateMistake(ctx context.Context, trickSlug string, id int64, req models.MistakeRequest) (*models.TrickMistake, error) {
+	mistake, err := cleanMistakeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	mistake.ID = id
+
+	if err := s.mistakeRepo.Update(ctx, trickSlug, mistake); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrMistakeNotFound
+		}
+		return nil, fmt.Errorf("failed to update mistake: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return mistake, nil
+}
+
+// DeleteMistake removes one of a trick's mistakes
+func (s *ModerationService) DeleteMistake(ctx context.Context, trickSlug string, id int64) error {
+	if err := s.mistakeRepo.Delete(ctx, trickSlug, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrMistakeNotFound
+		}
+		return fmt.Errorf("failed to delete mistake: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return nil
+}
+
+// ReorderMistakes sets the display order of a trick's mistakes and returns them in it
+func (s *ModerationService) ReorderMistakes(ctx context.Context, trickSlug string, ids []int64) ([]models.TrickMistake, error) {
+	err := s.mistakeRepo.Reorder(ctx, trickSlug, ids)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, ErrTrickNotFound
+	case errors.Is(err, repository.ErrMistakeOrderMismatch):
+		return nil, ErrMistakeOrderMismatch
+	case err != nil:
+		return nil, fmt.Errorf("failed to reorder mistakes: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+
+	mistakes, err := s.mistakeRepo.FindByTrickSlug(ctx, trickSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reordered mistakes: %w", err)
+	}
+	return mistakes, nil
+}
+
+// =============================================================================
+// PRIVATE HELPER METHODS
+// =============================================================================
+
+// cleanMistakeRequest sanitizes the text and checks it and the severity
+func cleanMistakeRequest(req models.MistakeRequest) (*models.TrickMistake, error) {
+	text := sanitize.Text(req.Text)
+	if text == "" || utf8.RuneCountInString(text) > MaxMistakeTextLength {
+		return nil, ErrInvalidMistakeText
+	}
+	if !mistakeSeverities[req.Severity] {
+		return nil, ErrInvalidMistakeSeverity
+	}
+	return &models.TrickMistake{Text: text, Severity: req.Severity}, nil
+}
+
+// AddPrerequisite records that prerequisiteSlug should be learned before trickSlug
+// Returns false if the link already existed. A link that would make any trick
+// its own prerequisite, directly or through other tricks, is ErrPrerequisiteCycle.
+func (s *ModerationService) AddPrerequisite(ctx context.Context, trickSlug, prerequisiteSlug string) (bool, error) {
+	created, err := s.prereqRepo.Add(ctx, trickSlug, prerequisiteSlug)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return false, ErrTrickNotFound
+	case errors.Is(err, repository.ErrUnknownPrerequisite):
+		return false, ErrUnknownPrerequisite
+	case errors.Is(err, repository.ErrPrerequisiteCycle):
+		return false, ErrPrerequisiteCycle
+	case err != nil:
+		return false, fmt.Errorf("failed to add prerequisite: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return created, nil
+}
+
+// RemovePrerequisite deletes one of a trick's prerequisites
+func (s *ModerationService) RemovePrerequisite(ctx context.Context, trickSlug, prerequisiteSlug string) error {
+	if err := s.prereqRepo.Remove(ctx, trickSlug, prerequisiteSlug); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPrerequisiteNotFound
+		}
+		return fmt.Errorf("failed to remove prerequisite: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return nil
+}
+
+// NormalizeTag turns a tag as typed into its stored form
+// Sanitized, lowercased and with whitespace runs collapsed, so "Ground  Move"
+// and "ground move" are the same tag. Commas are rejected - ?tag= lists use them.
+func NormalizeTag(raw string) (string, error) {
+	name := strings.Join(strings.Fields(strings.ToLower(sanitize.Text(raw))), " ")
+	if name == "" || utf8.RuneCountInString(name) > MaxTagLength || strings.Contains(name, ",") {
+		return "", ErrInvalidTag
+	}
+	return name, nil
+}
+
+// CreateTag adds a tag without putting it on any trick
+// Returns the normalized name and false if the tag already existed.
+func (s *ModerationService) CreateTag(ctx context.Context, name string) (string, bool, error) {
+	name, err := NormalizeTag(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	created, err := s.tagRepo.Create(ctx, name)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return name, created, nil
+}
+
+// DeleteTag removes a tag from every trick, then deletes it
+func (s *ModerationService) DeleteTag(ctx context.Context, name string) error {
+	name, err := NormalizeTag(name)
+	if err != nil {
+		return ErrTagNotFound
+	}
+
+	slugs, err := s.tagRepo.Delete(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	for _, slug := range slugs {
+		s.dictionaryCache.InvalidateTrick(slug)
+	}
+	return nil
+}
+
+// TagTrick puts a tag on a trick, creating the tag if it's new
+// Returns the normalized name and false if the trick already had it.
+func (s *ModerationService) TagTrick(ctx context.Context, trickSlug, name string) (string, bool, error) {
+	name, err := NormalizeTag(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	created, err := s.tagRepo.AddToTrick(ctx, trickSlug, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", false, ErrTrickNotFound
+		}
+		return "", false, fmt.Errorf("failed to tag trick: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return name, created, nil
+}
+
+// UntagTrick takes a tag off a trick
+func (s *ModerationService) UntagTrick(ctx context.Context, trickSlug, name string) error {
+	name, err := NormalizeTag(name)
+	if err != nil {
+		return ErrTagNotFound
+	}
+
+	if err := s.tagRepo.RemoveFromTrick(ctx, trickSlug, name); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("failed to untag trick: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+)
+
+// =============================================================================
+// PUBLIC TRICK LINKS
+// =============================================================================
+// Social media previews can't send the internal API key, so an admin mints a
+// link instead: /public/tricks/<slug>?exp=<unix seconds>&sig=<signature>
+//
+// sig = base64url(HMAC-SHA256(secret, "<slug>\n<exp>"))
+//
+// Changing the slug or pushing exp back breaks the signature, so a link only
+// ever opens the one trick it was minted for, and only until it expires.
+// Rotating PUBLIC_LINK_SECRET revokes every link at once.
+
+// Public link lifetimes
+const (
+	DefaultPublicLinkTTL = 7 * 24 * time.Hour
+	MinPublicLinkTTL     = time.Minute
+	MaxPublicLinkTTL     = 90 * 24 * time.Hour
+)
+
+// ErrPublicLinksDisabled indicates no signing secret is configured
+var ErrPublicLinksDisabled = errors.New("public links are not configured")
+
+// ErrInvalidLinkTTL indicates a requested lifetime outside MinPublicLinkTTL-MaxPublicLinkTTL
+var ErrInvalidLinkTTL = errors.New("link lifetime out of range")
+
+// ErrInvalidLinkSignature indicates a link whose signature doesn't match its slug and expiry
+var ErrInvalidLinkSignature = errors.New("invalid link signature")
+
+// ErrLinkExpired indicates a correctly signed link past its expiry
+var ErrLinkExpired = errors.New("link has expired")
+
+// PublicLinkServiceInterface defines the contract for signed public trick links
+type PublicLinkServiceInterface interface {
+	CreateLink(ctx context.Context, slug string, ttl time.Duration) (*models.PublicTrickLink, error)
+	GetPublicTrick(ctx context.Context, slug, sig, exp string) (*models.PublicTrickPreview, time.Time, error)
+}
+
+// PublicLinkService implements PublicLinkServiceInterface
+type PublicLinkService struct {
+	trickRepo repository.TrickRepositoryInterface
+	videoRepo repository.VideoRepositoryInterface
+
+	// secret is the HMAC key - empty means the feature is off
+	secret []byte
+}
+
+// NewPublicLinkService creates a new PublicLinkService instance
+// An empty secret disables links: minting fails and every link is rejected.
+func NewPublicLinkService(trickRepo repository.TrickRepositoryInterface, videoRepo repository.VideoRepositoryInterface, secret string) *PublicLinkService {
+	return &PublicLinkService{
+		trickRepo: trickRepo,
+		videoRepo: videoRepo,
+		secret:    []byte(secret),
+	}
+}
+
+// CreateLink signs a link to one live trick, valid for ttl
+func (s *PublicLinkService) CreateLink(ctx context.Context, slug string, ttl time.Duration) (*models.PublicTrickLink, error) {
+	if len(s.secret) == 0 {
+		return nil, ErrPublicLinksDisabled
+	}
+	if ttl < MinPublicLinkTTL || ttl > MaxPublicLinkTTL {
+		return nil, ErrInvalidLinkTTL
+	}
+
+	// Only link tricks that exist - a link to a 404 is no use in a preview
+	if _, err := s.trickRepo.GetByID(ctx, slug); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		return nil, fmt.Errorf("failed to get trick: %w", err)
+	}
+
+	expiresAt := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	sig := s.sign(slug, exp)
+
+	query := url.Values{"exp": {exp}, "sig": {sig}}
+	return &models.PublicTrickLink{
+		URL:       "/api/v1/public/tricks/" + url.PathEscape(slug) + "?" + query.Encode(),
+		Slug:      slug,
+		Sig:       sig,
+		Exp:       expiresAt.Unix(),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetPublicTrick checks a link's signature and expiry, then loads the trick's preview
+// Also returns when the link expires, so the handler can keep caches from outliving it.
+func (s *PublicLinkService) GetPublicTrick(ctx context.Context, slug, sig, exp string) (*models.PublicTrickPreview, time.Time, error) {
+	expiresAt, err := s.verify(slug, sig, exp)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	trick, err := s.trickRepo.GetByID(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, time.Time{}, ErrTrickNotFound
+		}
+		return nil, time.Time{}, fmt.Errorf("failed to get trick: %w", err)
+	}
+
+	preview := &models.PublicTrickPreview{
+		ID:          slug,
+		Name:        trick.Name,
+		Description: trick.Description,
+		Difficulty:  trick.Difficulty,
+	}
+
+	// Same pick as the gallery grid: featured, or the newest playable video if that's dead
+	featured, err := s.videoRepo.GetFeaturedForTrickIDs(ctx, []string{slug})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get featured video: %w", err)
+	}
+	if video, ok := featured[slug]; ok && video.ThumbnailURL != "" {
+		thumbnail := video.ThumbnailURL
+		preview.ThumbnailURL = &thumbnail
+	}
+
+	return preview, expiresAt, nil
+}
+
+// verify checks sig against slug and exp, then the expiry itself
+// The signature is checked first, so a forged link never learns whether it has expired.
+func (s *PublicLinkService) verify(slug, sig, exp string) (time.Time, error) {
+	if len(s.secret) == 0 {
+		return time.Time{}, ErrInvalidLinkSignature
+	}
+
+	// hmac.Equal compares in constant time - no timing hints about the right signature
+	if !hmac.Equal([]byte(sig), []byte(s.sign(slug, exp))) {
+		return time.Time{}, ErrInvalidLinkSignature
+	}
+
+	seconds, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidLinkSignature
+	}
+	expiresAt := time.Unix(seconds, 0).UTC()
+	if !time.Now().Before(expiresAt) {
+		return time.Time{}, ErrLinkExpired
+	}
+	return expiresAt, nil
+}
+
+// sign computes the signature of one slug/expiry pair
+func (s *PublicLinkService) sign(slug, exp string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(slug + "\n" + exp))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
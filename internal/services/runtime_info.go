@@ -0,0 +1,86 @@
+package services
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/models"
+)
+
+// RuntimeInfoServiceInterface defines the contract for describing the running instance
+type RuntimeInfoServiceInterface interface {
+	Info() models.RuntimeInfo
+	Status() models.RuntimeStatus
+}
+
+// RuntimeInfoService answers "which build and config is this pod running?"
+// The static part is fixed at startup; Status adds live process numbers.
+type RuntimeInfoService struct {
+	info models.RuntimeInfo
+
+	// dictionaryCache is only read for its size
+	dictionaryCache *DictionaryCache
+}
+
+// NewRuntimeInfoService creates a new RuntimeInfoService instance
+// database must already be redacted (database.Describe does that).
+// Secrets in cfg are never read - only the feature switches they imply.
+func NewRuntimeInfoService(cfg *config.Config, version string, database models.DatabaseInfo, dictionaryCache *DictionaryCache) *RuntimeInfoService {
+	return &RuntimeInfoService{
+		info: models.RuntimeInfo{
+			Version:     version,
+			GitCommit:   gitCommit(),
+			GoVersion:   runtime.Version(),
+			Environment: cfg.Environment,
+			Features:    cfg.EnabledFeatures(),
+			Database:    database,
+			StartedAt:   time.Now().UTC(),
+		},
+		dictionaryCache: dictionaryCache,
+	}
+}
+
+// Info returns what was fixed at startup (the startup log line)
+func (s *RuntimeInfoService) Info() models.RuntimeInfo {
+	return s.info
+}
+
+// Status returns Info plus uptime, scheduler and cache numbers
+func (s *RuntimeInfoService) Status() models.RuntimeStatus {
+	return models.RuntimeStatus{
+		RuntimeInfo:   s.info,
+		UptimeSeconds: int64(time.Since(s.info.StartedAt).Seconds()),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		Goroutines:    runtime.NumGoroutine(),
+		CacheEntries:  s.dictionaryCache.Len(),
+	}
+}
+
+// gitCommit reads the VCS revision Go stamps into binaries built from a checkout
+// "-dirty" marks uncommitted changes; "unknown" if the build carries no VCS info
+// (e.g. go run, or a build outside the repository).
+func gitCommit() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	revision, modified := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "unknown"
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision
+}
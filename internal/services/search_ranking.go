@@ -0,0 +1,156 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// SEARCH RANKING
+// =============================================================================
+// Search results are ranked in tiers, best first:
+//   1. exact       - name equals the query ("cork" -> "Cork")
+//   2. prefix      - name starts with the query ("cork" -> "Corkscrew")
+//   3. alias       - an alternate name contains the query
+//   4. former_name - a name the trick was renamed from contains the query
+//   5. word        - a later word in the name starts with the query ("cork" -> "Double Cork")
+//   6. name        - the query appears anywhere else in the name
+//   7. description - only the description or execution notes contain the query
+//   8. text        - full-text match only (stemmed words, e.g. "flips" -> "flip")
+//   9. fuzzy       - only close to the name or an alias, i.e. a typo ("gainner" -> "Gainer")
+// Within a tier, higher weight wins, then name alphabetically - except fuzzy
+// matches, which go by similarity score first.
+//
+// Fuzzy matching needs pg_trgm; without it (or with a candidate below the
+// threshold) a candidate that matched no substring is a full-text match.
+
+// Match types, in rank order
+const (
+	matchExact       = "exact"
+	matchPrefix      = "prefix"
+	matchAlias       = "alias"
+	matchFormerName  = "former_name"
+	matchWord        = "word"
+	matchName        = "name"
+	matchDescription = "description"
+	matchText        = "text"
+	matchFuzzy       = "fuzzy"
+)
+
+// matchRank orders match types - lower is better
+var matchRank = map[string]int{
+	matchExact:       0,
+	matchPrefix:      1,
+	matchAlias:       2,
+	matchFormerName:  3,
+	matchWord:        4,
+	matchName:        5,
+	matchDescription: 6,
+	matchText:        7,
+	matchFuzzy:       8,
+}
+
+// rankedTrick pairs a candidate trick with how it matched
+type rankedTrick struct {
+	trick     models.Trick
+	matchType string
+}
+
+// rankSearchResults scores candidates against the query and returns them best first
+// aliases maps trick ID to alternate names. Candidates already matched in the database,
+// so any that don't match a substring here came from full-text or fuzzy search -
+// fuzzy when their similarity reaches minSimilarity (0 when fuzzy search is off).
+func rankSearchResults(query string, candidates []models.Trick, aliases map[string][]string, minSimilarity float64) []models.TrickSearchResult {
+	q := strings.ToLower(strings.TrimSpace(query))
+
+	ranked := make([]rankedTrick, 0, len(candidates))
+	for _, trick := range candidates {
+		ranked = append(ranked, rankedTrick{trick: trick, matchType: classifyMatch(q, trick, aliases[trick.ID], minSimilarity)})
+	}
+
+	// SliceStable + full tie-breaking keeps the order deterministic
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if matchRank[a.matchType] != matchRank[b.matchType] {
+			return matchRank[a.matchType] < matchRank[b.matchType]
+		}
+		if a.matchType == matchFuzzy && *a.trick.Similarity != *b.trick.Similarity {
+			return *a.trick.Similarity > *b.trick.Similarity
+		}
+		if a.trick.Weight != b.trick.Weight {
+			return a.trick.Weight > b.trick.Weight
+		}
+		return strings.ToLower(a.trick.Name) < strings.ToLower(b.trick.Name)
+	})
+
+	results := make([]models.TrickSearchResult, 0, len(ranked))
+	for _, r := range ranked {
+		results = append(results, models.TrickSearchResult{
+			ID:         r.trick.ID,
+			Name:       r.trick.Name,
+			Difficulty: r.trick.Difficulty,
+			MatchType:  r.matchType,
+			Score:      r.trick.Similarity,
+		})
+	}
+	return results
+}
+
+// classifyMatch returns the best match type for a trick
+// q must already be lowercased and trimmed. Former names come from trick.FormerNames.
+func classifyMatch(q string, trick models.Trick, aliases []string, minSimilarity float64) string {
+	name := strings.ToLower(trick.Name)
+
+	switch {
+	case name == q:
+		return matchExact
+	case strings.HasPrefix(name, q):
+		return matchPrefix
+	}
+
+	for _, alias := range aliases {
+		if strings.Contains(strings.ToLower(alias), q) {
+			return matchAlias
+		}
+	}
+	for _, former := range trick.FormerNames {
+		if strings.Contains(strings.ToLower(former), q) {
+			return matchFormerName
+		}
+	}
+
+	if hasWordPrefix(name, q) {
+		return matchWord
+	}
+	if strings.Contains(name, q) {
+		return matchName
+	}
+	if containsFold(trick.Description, q) || containsFold(trick.ExecutionNotes, q) {
+		return matchDescription
+	}
+	if minSimilarity > 0 && trick.Similarity != nil && *trick.Similarity >= minSimilarity {
+		return matchFuzzy
+	}
+	return matchText
+}
+
+// containsFold reports whether an optional field contains q (already lowercased)
+func containsFold(field *string, q string) bool {
+	return field != nil && strings.Contains(strings.ToLower(*field), q)
+}
+
+// hasWordPrefix reports whether any word in s (after the first) starts with q
+// A word starts after any non-letter/digit (space, hyphen, etc.)
+func hasWordPrefix(s, q string) bool {
+	prev := rune(0)
+	for i, r := range s {
+		if i > 0 && !unicode.IsLetter(prev) && !unicode.IsDigit(prev) && strings.HasPrefix(s[i:], q) {
+			return true
+		}
+		prev = r
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// searchCatalog is the fixture catalog plus the extra text the ranking looks at
+// Each trick keeps its catalog weight, so tie-breaks match a real database.
+func searchCatalog() []models.Trick {
+	tricks := fixtures.CatalogTricks()
+	for i := range tricks {
+		switch tricks[i].Slug {
+		case "full":
+			tricks[i].Description = fixtures.Ptr("Like a cork but from a punch")
+		case "arabian":
+			tricks[i].ExecutionNotes = fixtures.Ptr("Set high, then corkscrew out")
+		case "720-kick":
+			tricks[i].FormerNames = []string{"Corked 720"}
+		case "gumbi":
+			tricks[i].Similarity = fixtures.Ptr(0.45)
+		case "gainer":
+			tricks[i].Similarity = fixtures.Ptr(0.62)
+		}
+	}
+	return tricks
+}
+
+// searchAliases are the alternate names the ranking sees, by trick ID
+var searchAliases = map[string][]string{
+	"btwist-round": {"Corked B-Twist"},
+	"double-full":  {"Double Cork Full"},
+}
+
+func TestRankSearchResultsRegression(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		candidates    []string // slugs the database returned, in database order
+		minSimilarity float64
+		want          []string // slug:match_type, best first
+	}{
+		{
+			name:  "cork across every tier",
+			query: "cork",
+			candidates: []string{
+				"triple-cork", "full", "corkscrew", "720-kick", "double-full", "cork-double-kick",
+				"arabian", "btwist-round", "double-cork", "cork", "gumbi", "gainer",
+			},
+			minSimilarity: 0.3,
+			want: []string{
+				"cork:exact",
+				"corkscrew:prefix", "cork-double-kick:prefix",
+				"btwist-round:alias", "double-full:alias",
+				"720-kick:former_name",
+				"double-cork:word", "triple-cork:word",
+				"full:description", "arabian:description",
+				"gainer:fuzzy", "gumbi:fuzzy",
+			},
+		},
+		{
+			name:          "fuzzy disabled falls back to text",
+			query:         "cork",
+			candidates:    []string{"gumbi", "gainer", "cork"},
+			minSimilarity: 0,
+			want:          []string{"cork:exact", "gainer:text", "gumbi:text"},
+		},
+		{
+			name:          "below the similarity threshold is text",
+			query:         "cork",
+			candidates:    []string{"gumbi", "gainer"},
+			minSimilarity: 0.5,
+			want:          []string{"gumbi:text", "gainer:fuzzy"}, // Full-text hits outrank fuzzy ones
+		},
+		{
+			name:       "query is trimmed and case-insensitive",
+			query:      "  DOUBLE  ",
+			candidates: []string{"double-leg", "double-cork", "double-full", "double-btwist"},
+			// All prefix matches: weight 5 first, then the weight-3 tricks by name
+			want: []string{"double-leg:prefix", "double-btwist:prefix", "double-cork:prefix", "double-full:prefix"},
+		},
+		{
+			name:       "word match after a hyphen",
+			query:      "twist",
+			candidates: []string{"double-btwist", "butterfly-twist"},
+			want:       []string{"butterfly-twist:word", "double-btwist:word"},
+		},
+		{
+			name:       "substring inside a word is a name match",
+			query:      "ebs",
+			candidates: []string{"webster"},
+			want:       []string{"webster:name"},
+		},
+		{
+			name:       "digits start words",
+			query:      "720",
+			candidates: []string{"cheat-720", "720-kick"},
+			want:       []string{"720-kick:prefix", "cheat-720:word"},
+		},
+		{
+			name:       "no candidates",
+			query:      "cork",
+			candidates: nil,
+			want:       []string{},
+		},
+	}
+
+	catalog := map[string]models.Trick{}
+	for _, trick := range searchCatalog() {
+		catalog[trick.Slug] = trick
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := make([]models.Trick, 0, len(tt.candidates))
+			for _, slug := range tt.candidates {
+				trick, ok := catalog[slug]
+				if !ok {
+					t.Fatalf("no search fixture %q", slug)
+				}
+				candidates = append(candidates, trick)
+			}
+
+			results := rankSearchResults(tt.query, candidates, searchAliases, tt.minSimilarity)
+
+			got := make([]string, 0, len(results))
+			for _, r := range results {
+				got = append(got, r.ID+":"+r.MatchType)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ranking changed\n got: %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankSearchResultsIsDeterministic(t *testing.T) {
+	forward := searchCatalog()
+	backward := make([]models.Trick, len(forward))
+	for i, trick := range forward {
+		backward[len(forward)-1-i] = trick
+	}
+
+	a := rankSearchResults("o", forward, searchAliases, 0.3)
+	b := rankSearchResults("o", backward, searchAliases, 0.3)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("ranking depends on candidate order")
+	}
+}
+
+func TestHasWordPrefix(t *testing.T) {
+	tests := []struct {
+		s, q string
+		want bool
+	}{
+		{"double cork", "cork", true},
+		{"b-twist round", "twist", true},
+		{"cork", "cork", false}, // The first word is a prefix match, not a word match
+		{"corkscrew", "screw", false},
+		{"cheat 720", "72", true},
+		{"540 kick", "kick", true},
+		{"gainer/switch", "switch", true},
+		{"", "cork", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s+"/"+tt.q, func(t *testing.T) {
+			if got := hasWordPrefix(tt.s, tt.q); got != tt.want {
+				t.Errorf("hasWordPrefix(%q, %q) = %v, want %v", tt.s, tt.q, got, tt.want)
+			}
+		})
+	}
+}
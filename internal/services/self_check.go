@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"tricking-api/internal/database"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+)
+
+// SelfCheckServiceInterface defines the contract for schema self-checks
+type SelfCheckServiceInterface interface {
+	Run(ctx context.Context) *models.SelfCheckReport
+	LastReport() *models.SelfCheckReport
+}
+
+// SelfCheckService verifies that the indexes in database.RequiredIndexes exist
+// It runs once at startup and again whenever an admin asks; the latest report
+// backs the degraded flag on /health/ready.
+type SelfCheckService struct {
+	schemaRepo repository.SchemaRepositoryInterface
+	required   []database.RequiredIndex
+
+	mu   sync.Mutex
+	last *models.SelfCheckReport
+}
+
+// NewSelfCheckService creates a new SelfCheckService instance
+func NewSelfCheckService(schemaRepo repository.SchemaRepositoryInterface, required []database.RequiredIndex) *SelfCheckService {
+	return &SelfCheckService{
+		schemaRepo: schemaRepo,
+		required:   required,
+	}
+}
+
+// Run checks the schema now, logs a warning per missing index and stores the report
+// A failed lookup is reported as degraded rather than returned - the API can still serve
+func (s *SelfCheckService) Run(ctx context.Context) *models.SelfCheckReport {
+	report := &models.SelfCheckReport{
+		CheckedAt:      time.Now().UTC(),
+		MissingIndexes: make([]models.MissingIndex, 0),
+	}
+
+	missing, err := s.findMissing(ctx)
+	if err != nil {
+		log.Printf("Warning: schema self-check failed: %v", err)
+		report.Degraded = true
+		report.Error = "could not read indexes"
+	} else {
+		for _, index := range missing {
+			log.Printf("Warning: missing index %s.%s on %s - create it with: %s",
+				index.Schema, index.Name, index.Table, index.Definition)
+			report.MissingIndexes = append(report.MissingIndexes, models.MissingIndex{
+				Table:      index.Schema + "." + index.Table,
+				Name:       index.Name,
+				Definition: index.Definition,
+			})
+		}
+		report.Degraded = len(missing) > 0
+	}
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+	return report
+}
+
+// LastReport returns the most recent report, or nil if no check has run yet
+func (s *SelfCheckService) LastReport() *models.SelfCheckReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// findMissing returns the required indexes that aren't in the database
+func (s *SelfCheckService) findMissing(ctx context.Context) ([]database.RequiredIndex, error) {
+	// Only ask about the schemas we care about
+	schemas := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, index := range s.required {
+		if !seen[index.Schema] {
+			seen[index.Schema] = true
+			schemas = append(schemas, index.Schema)
+		}
+	}
+
+	found, err := s.schemaRepo.FindIndexNames(ctx, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	missing := make([]database.RequiredIndex, 0)
+	for _, index := range s.required {
+		if !found[index.Schema+"."+index.Name] {
+			missing = append(missing, index)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+)
+
+// ErrStanceNotFound indicates the requested stance doesn't exist
+var ErrStanceNotFound = errors.New("stance not found")
+
+// StanceServiceInterface defines the contract for stance operations
+type StanceServiceInterface interface {
+	GetAllStances(ctx context.Context) ([]models.StanceResponse, error)
+	GetStance(ctx context.Context, id int) (*models.StanceResponse, error)
+	ExpandStances(ctx context.Context, trick *models.TrickDetailResponse) error
+}
+
+// StanceService implements StanceServiceInterface
+// Stances are reference data - the repository caches them, so this stays thin
+type StanceService struct {
+	stanceRepo repository.StanceRepositoryInterface
+}
+
+// NewStanceService creates a new StanceService instance
+func NewStanceService(stanceRepo repository.StanceRepositoryInterface) *StanceService {
+	return &StanceService{stanceRepo: stanceRepo}
+}
+
+// GetAllStances retrieves every stance, by ID, for resolving takeoff/landing stance IDs
+func (s *StanceService) GetAllStances(ctx context.Context) ([]models.StanceResponse, error) {
+	stances, err := s.stanceRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stances: %w", err)
+	}
+
+	responses := make([]models.StanceResponse, 0, len(stances))
+	for _, stance := range stances {
+		responses = append(responses, stance.ToResponse())
+	}
+	return responses, nil
+}
+
+// GetStance retrieves one stance by ID
+func (s *StanceService) GetStance(ctx context.Context, id int) (*models.StanceResponse, error) {
+	stance, err := s.stanceRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrStanceNotFound
+		}
+		return nil, fmt.Errorf("failed to get stance: %w", err)
+	}
+
+	response := stance.ToResponse()
+	return &response, nil
+}
+
+// ExpandStances fills in a trick's TakeoffStance/LandingStance from its stance IDs
+// An ID with no stance (a broken reference) is left unexpanded rather than failing.
+func (s *StanceService) ExpandStances(ctx context.Context, trick *models.TrickDetailResponse) error {
+	if trick.TakeoffStanceID == nil && trick.LandingStanceID == nil {
+		return nil
+	}
+
+	stances, err := s.stanceRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get stances: %w", err)
+	}
+	byID := make(map[int]models.StanceResponse, len(stances))
+	for _, stance := range stances {
+		byID[stance.ID] = stance.ToResponse()
+	}
+
+	if trick.TakeoffStanceID != nil {
+		if stance, ok := byID[*trick.TakeoffStanceID]; ok {
+			trick.TakeoffStance = &stance
+		}
+	}
+	if trick.LandingStanceID != nil {
+		if stance, ok := byID[*trick.LandingStanceID]; ok {
+			trick.LandingStance = &stance
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// BATCH TRICK CREATION (CSV / JSON array)
+// =============================================================================
+// For seeding an environment: every row is a new trick, shaped like the body
+// of POST /tricks and slugged from its name the same way. Unlike the NDJSON
+// import (trick_import.go) nothing is upserted and it's all or nothing:
+//
+//  1. Validate each row on its own (bad rows are reported, not fatal)
+//  2. Insert every valid row in ONE transaction - names are checked against
+//     the catalog and the rows before them, stance/flip IDs must exist
+//  3. Commit only if no row failed and this isn't a dry run
+//
+// A dry run goes through step 2 too and rolls back, so it reports the same
+// slugs and conflicts a real run would. The upload size is capped by the route
+// (TRICK_IMPORT_MAX_BYTES), which bounds the row count too.
+
+// Batch row errors - reported on the row's result
+var (
+	ErrInvalidTrickBatchRow = errors.New("row has a value of the wrong type")
+	ErrInvalidTrickRotation = errors.New("rotation must be between 0 and 1800")
+)
+
+// ErrInvalidTrickBatch indicates an upload that can't be read as rows at all
+// (not a JSON array, a broken CSV, unknown CSV columns, or no rows). A read
+// error is wrapped too, so errors.As still finds an *http.MaxBytesError.
+var ErrInvalidTrickBatch = errors.New("upload is not a CSV with a header row or a JSON array of tricks")
+
+// Rotation range for batch rows (matches TrickCreateRequest's binding)
+const (
+	MinTrickRotation = 0
+	MaxTrickRotation = 1800
+)
+
+// trickBatchColumns are the CSV header names - the JSON keys of TrickCreateRequest
+var trickBatchColumns = []string{
+	"name", "difficulty", "description", "execution_notes",
+	"takeoff_stance_id", "landing_stance_id", "flip_id", "rotation",
+}
+
+// TrickBatchRow is one row of an upload, numbered from 1 by its position
+// Err is set when the row couldn't be decoded into a request.
+type TrickBatchRow struct {
+	Request models.TrickCreateRequest
+	Err     error
+}
+
+// TrickBatchEmitter receives one result per row, in row order
+// rowErr is the row's error (nil for valid rows); the caller turns it into
+// the result's Code/Error and collects the results.
+type TrickBatchEmitter func(result models.TrickBatchResult, rowErr error)
+
+// ParseTrickBatchJSON reads a JSON array of TrickCreateRequest objects
+// An element that isn't a valid request is a row error, not a failed upload.
+func ParseTrickBatchJSON(r io.Reader) ([]TrickBatchRow, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elements); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTrickBatch, err)
+	}
+	if len(elements) == 0 {
+		return nil, ErrInvalidTrickBatch
+	}
+
+	rows := make([]TrickBatchRow, len(elements))
+	for i, element := range elements {
+		if err := json.Unmarshal(element, &rows[i].Request); err != nil {
+			rows[i].Err = ErrInvalidTrickBatchRow
+		}
+	}
+	return rows, nil
+}
+
+// ParseTrickBatchCSV reads a CSV whose header row names the columns
+// Columns are trickBatchColumns in any order ("name" is required); an empty
+// cell leaves an optional field unset.
+func ParseTrickBatchCSV(r io.Reader) ([]TrickBatchRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTrickBatch, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(trickBatchColumns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidTrickBatch, name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("%w: no name column", ErrInvalidTrickBatch)
+	}
+
+	rows := []TrickBatchRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// A row with the wrong number of cells is that row's problem; anything
+		// else (a stray quote) leaves the rest of the file unreadable
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTrickBatch, err)
+		}
+
+		var row TrickBatchRow
+		if err != nil {
+			row.Err = ErrInvalidTrickBatchRow
+		} else {
+			row.Request, row.Err = trickBatchRecord(record, columns)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", ErrInvalidTrickBatch)
+	}
+	return rows, nil
+}
+
+// trickBatchRecord turns one CSV record into a request
+func trickBatchRecord(record []string, columns map[string]int) (models.TrickCreateRequest, error) {
+	cell := func(name string) *string {
+		i, ok := columns[name]
+		if !ok || strings.TrimSpace(record[i]) == "" {
+			return nil
+		}
+		value := record[i]
+		return &value
+	}
+	number := func(name string) (*int, error) {
+		raw := cell(name)
+		if raw == nil {
+			return nil, nil
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(*raw))
+		if err != nil {
+			return nil, ErrInvalidTrickBatchRow
+		}
+		return &value, nil
+	}
+
+	var req models.TrickCreateRequest
+	if name := cell("name"); name != nil {
+		req.Name = *name
+	}
+	req.Description = cell("description")
+	req.ExecutionNotes = cell("execution_notes")
+
+	difficulty, err := number("difficulty")
+	if err != nil {
+		return req, err
+	}
+	if difficulty != nil {
+		req.Difficulty = int64(*difficulty)
+	}
+	for _, field := range []struct {
+		name  string
+		value **int
+	}{
+		{"takeoff_stance_id", &req.TakeoffStanceID},
+		{"landing_stance_id", &req.LandingStanceID},
+		{"flip_id", &req.FlipID},
+		{"rotation", &req.Rotation},
+	} {
+		if *field.value, err = number(field.name); err != nil {
+			return req, err
+		}
+	}
+	return req, nil
+}
+
+// CreateTrickBatch creates a trick per row, all in one transaction (see the top of the file)
+// With dryRun nothing is written. Returns the summary; results go through emit.
+func (s *TrickService) CreateTrickBatch(ctx context.Context, rows []TrickBatchRow, createdBy *uuid.UUID, dryRun bool, emit TrickBatchEmitter) (*models.TrickBatchSummary, error) {
+	summary := &models.TrickBatchSummary{DryRun: dryRun, Rows: len(rows)}
+
+	errs := make([]error, len(rows))
+	tricks := make([]*models.Trick, 0, len(rows))
+	indexes := make([]int, 0, len(rows)) // Row index of each trick
+	for i, row := range rows {
+		if row.Err != nil {
+			errs[i] = row.Err
+			continue
+		}
+		trick, err := trickFromBatchRequest(row.Request)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		trick.CreatedBy = createdBy
+		tricks = append(tricks, &trick)
+		indexes = append(indexes, i)
+	}
+
+	valid := len(tricks) == len(rows)
+	if len(tricks) > 0 {
+		insertErrs, err := s.trickRepo.InsertBatch(ctx, tricks, createdBy, valid && !dryRun)
+		if err != nil {
+			return summary, fmt.Errorf("failed to create tricks: %w", err)
+		}
+		for j, err := range insertErrs {
+			if err != nil {
+				errs[indexes[j]] = trickWriteError(err, tricks[j], "failed to create trick")
+				valid = false
+			}
+		}
+	}
+	written := valid && !dryRun
+	summary.Written = written
+
+	slugs := make(map[int]string, len(tricks))
+	for j, trick := range tricks {
+		slugs[indexes[j]] = trick.Slug
+	}
+	for i := range rows {
+		result := models.TrickBatchResult{Row: i + 1, Slug: slugs[i]}
+		switch {
+		case errs[i] != nil:
+			result.Status = models.ImportInvalid
+			result.Slug = ""
+			summary.Invalid++
+		case written:
+			result.Status = models.ImportCreated
+			summary.Created++
+		default:
+			result.Status = models.ImportValid
+			summary.Valid++
+		}
+		emit(result, errs[i])
+	}
+	return summary, nil
+}
+
+// trickFromBatchRequest applies TrickCreateRequest's rules to a row - its
+// binding tags only run on a request body - then trickFromRequest's
+func trickFromBatchRequest(req models.TrickCreateRequest) (models.Trick, error) {
+	trick, err := trickFromRequest(req)
+	if err != nil {
+		return trick, err
+	}
+	if req.Difficulty < MinImportDifficulty || req.Difficulty > MaxImportDifficulty {
+		return trick, ErrInvalidImportDifficulty
+	}
+	for _, id := range []*int{req.TakeoffStanceID, req.LandingStanceID, req.FlipID} {
+		if id != nil && *id < 1 {
+			return trick, ErrUnknownTrickReference
+		}
+	}
+	if req.Rotation != nil && (*req.Rotation < MinTrickRotation || *req.Rotation > MaxTrickRotation) {
+		return trick, ErrInvalidTrickRotation
+	}
+	trick.Slug = aliasSlug(trick.Name)
+	return trick, nil
+}
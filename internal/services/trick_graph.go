@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// TRICK GRAPH
+// =============================================================================
+// Tricks as nodes and their relations as typed edges, for visualization. One
+// query reads the nodes, then one per relation reads the edges between them.
+//
+// The graph is capped at graphMaxNodes (TRICK_GRAPH_MAX_NODES): past it the
+// caller has to narrow the graph with the GET /tricks filters (a category or a
+// difficulty band) rather than get a truncated one.
+
+// Trick graph errors
+var (
+	ErrUnknownTrickRelation = errors.New("unknown trick relation")
+	ErrTrickGraphTooLarge   = errors.New("trick graph has too many nodes")
+)
+
+// TrickGraphTooLargeError is a graph request matching more than Max tricks
+// It matches ErrTrickGraphTooLarge.
+type TrickGraphTooLargeError struct {
+	Max int
+}
+
+func (e *TrickGraphTooLargeError) Error() string {
+	return fmt.Sprintf("trick graph has more than %d nodes", e.Max)
+}
+
+func (e *TrickGraphTooLargeError) Is(target error) bool {
+	return target == ErrTrickGraphTooLarge
+}
+
+// TrickRelations are the relation types GET /tricks/graph can draw, in edge order
+var TrickRelations = []string{models.TrickRelationPrerequisite}
+
+// trickGraphEdges reads one relation's edges between the given slugs
+type trickGraphEdges func(ctx context.Context, slugs []string) ([]models.TrickGraphEdge, error)
+
+// GetTrickGraph returns the tricks matching filter and the relations between them
+// relations are TrickRelations entries (none means all of them). Returns
+// a *TrickGraphTooLargeError if more than graphMaxNodes tricks match.
+func (s *TrickService) GetTrickGraph(ctx context.Context, filter models.TrickListFilter, relations []string) (*models.TrickGraph, error) {
+	if len(relations) == 0 {
+		relations = TrickRelations
+	}
+	for _, relation := range relations {
+		if !slices.Contains(TrickRelations, relation) {
+			return nil, ErrUnknownTrickRelation
+		}
+	}
+	filters, err := trickListFilters(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := s.trickRepo.FindGraphNodes(ctx, filters, s.graphMaxNodes+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trick graph: %w", err)
+	}
+	if len(nodes) > s.graphMaxNodes {
+		return nil, &TrickGraphTooLargeError{Max: s.graphMaxNodes}
+	}
+
+	slugs := make([]string, len(nodes))
+	for i, node := range nodes {
+		slugs[i] = node.Slug
+	}
+	// A new relation type only needs its table's reader here
+	readers := map[string]trickGraphEdges{
+		models.TrickRelationPrerequisite: s.prereqRepo.FindGraphEdges,
+	}
+
+	graph := &models.TrickGraph{Nodes: nodes, Edges: []models.TrickGraphEdge{}}
+	for _, relation := range TrickRelations {
+		if !slices.Contains(relations, relation) {
+			continue
+		}
+		graph.Relations = append(graph.Relations, relation)
+		if len(slugs) == 0 {
+			continue
+		}
+		edges, err := readers[relation](ctx, slugs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s edges: %w", relation, err)
+		}
+		graph.Edges = append(graph.Edges, edges...)
+	}
+	return graph, nil
+}
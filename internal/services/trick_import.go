@@ -0,0 +1,380 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/sanitize"
+)
+
+// =============================================================================
+// BULK TRICK IMPORT (NDJSON)
+// =============================================================================
+// One JSON object per line, read as a stream: a line is parsed, validated and
+// queued, and every importBatchSize queued tricks are written in one transaction.
+// Results go back through emit as soon as their batch is written, so memory
+// stays flat however big the upload is - at most one batch and one line.
+// emit blocking (a slow client) also stops reading, which is our back-pressure.
+
+// Bulk import limits
+const (
+	// MaxImportLineBytes is the longest accepted input line (longer lines are rejected, not truncated)
+	MaxImportLineBytes = 16 << 10
+
+	// importBatchSize is how many tricks go into one upsert transaction
+	importBatchSize = 500
+
+	// Difficulty scale for imported tricks
+	MinImportDifficulty = 1
+	MaxImportDifficulty = 10
+
+	// MaxImportNameLength bounds an imported trick's name (in characters)
+	MaxImportNameLength = 100
+
+	// Weight range for imported tricks - weight is a SMALLINT column (models.Trick.Weight is int16)
+	MinImportWeight = 1
+	MaxImportWeight = math.MaxInt16
+)
+
+// Per-line import errors - reported on the line's result, the import carries on
+var (
+	ErrImportLineTooLong       = errors.New("import line is too long")
+	ErrInvalidImportLine       = errors.New("import line is not a JSON object")
+	ErrInvalidImportSlug       = errors.New("slug must be lowercase letters, digits and single hyphens")
+	ErrInvalidImportName       = errors.New("trick name must be 1-100 characters")
+	ErrInvalidImportDifficulty = errors.New("difficulty must be between 1 and 10")
+	ErrInvalidImportWeight     = errors.New("weight must be a whole number between 1 and 32767")
+	ErrImportedTrickDeleted    = errors.New("slug belongs to a deleted trick")
+	ErrUnknownImportCategory   = errors.New("flip_id is not a category")
+)
+
+// importSlug is the shape of a trick slug ("double-cork", "b-twist")
+var importSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ImportEmitter receives one result per input line, in input order
+// lineErr is the per-line error (nil for created/updated lines).
+// Returning an error (e.g. the client went away) stops the import.
+type ImportEmitter func(result models.TrickImportResult, lineErr error) error
+
+// importLine is an input line waiting for its batch to be written
+type importLine struct {
+	result models.TrickImportResult
+	err    error
+	trick  int // Index into the batch's tricks, -1 for lines that failed validation
+}
+
+// ImportTricks streams an NDJSON trick import from r
+// The summary is always returned - with an error, it covers what was
+// written before the import stopped (earlier batches stay committed).
+func (s *AdminService) ImportTricks(ctx context.Context, r io.Reader, changedBy *uuid.UUID, emit ImportEmitter) (*models.TrickImportSummary, error) {
+	summary := &models.TrickImportSummary{}
+
+	// Categories are few - load them once to check flip_id and make suggestions
+	categories, err := s.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	reader := bufio.NewReader(r)
+	buf := make([]byte, 0, MaxImportLineBytes)
+
+	pending := make([]importLine, 0, importBatchSize)
+	tricks := make([]repository.ImportedTrick, 0, importBatchSize)
+	names := make(map[string]string, importBatchSize) // Lowercased name -> slug, for the queued tricks
+
+	// flush writes the queued tricks and emits every pending line in order
+	flush := func() error {
+		if err := s.markRenames(ctx, tricks); err != nil {
+			return err
+		}
+		outcomes, err := s.trickRepo.UpsertImported(ctx, tricks, changedBy)
+		if err != nil {
+			return fmt.Errorf("failed to import tricks: %w", err)
+		}
+
+		for _, line := range pending {
+			if line.trick >= 0 {
+				outcome := outcomes[line.trick]
+				line.result.Status = outcome.Status
+				// A suggestion sent along only fills an empty flip_id - check it stuck
+				if suggestion := line.result.SuggestedCategory; suggestion != nil && tricks[line.trick].SuggestedFlip {
+					suggestion.Applied = outcome.FlipID != nil && *outcome.FlipID == suggestion.ID
+				}
+				if line.result.Status == models.ImportSkipped {
+					line.err = ErrImportedTrickDeleted
+				} else {
+					s.dictionaryCache.InvalidateTrick(line.result.Slug)
+				}
+			}
+			countImportResult(summary, line.result.Status)
+			if err := emit(line.result, line.err); err != nil {
+				return err
+			}
+		}
+
+		pending = pending[:0]
+		tricks = tricks[:0]
+		clear(names)
+		return nil
+	}
+
+	lineNumber := 0
+	for {
+		line, tooLong, err := readImportLine(reader, buf)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return summary, fmt.Errorf("failed to read import: %w", err)
+		}
+		lineNumber++
+
+		// Blank lines (e.g. a trailing newline) aren't records - no result for them
+		if !tooLong && len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		summary.Lines++
+
+		entry := importLine{result: models.TrickImportResult{Line: lineNumber}, trick: -1}
+		if tooLong {
+			entry.err = ErrImportLineTooLong
+		} else {
+			var trick models.Trick
+			trick, entry.err = parseImportLine(line)
+			entry.result.Slug = trick.Slug
+			imported := repository.ImportedTrick{Trick: trick}
+			if entry.err == nil {
+				entry.err, err = s.checkImportUnique(ctx, &trick, names)
+				if err != nil {
+					return summary, err
+				}
+			}
+			if entry.err == nil {
+				entry.result.SuggestedCategory, entry.err = s.importCategory(&imported, categories)
+			}
+			if entry.err == nil {
+				entry.trick = len(tricks)
+				tricks = append(tricks, imported)
+			}
+		}
+		if entry.err != nil {
+			entry.result.Status = models.ImportInvalid
+		}
+		pending = append(pending, entry)
+
+		if len(tricks) == importBatchSize || len(pending) == cap(pending) {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		if err := flush(); err != nil {
+			return summary, err
+		}
+	}
+	summary.Done = true
+	return summary, nil
+}
+
+// readImportLine reads the next line into buf (reused between calls), without its line ending
+// A line longer than MaxImportLineBytes is read to its end but not kept - tooLong
+// is set instead. Returns io.EOF once the input is exhausted.
+func readImportLine(r *bufio.Reader, buf []byte) (line []byte, tooLong bool, err error) {
+	buf = buf[:0]
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !tooLong {
+			if len(buf)+len(chunk) > MaxImportLineBytes+2 { // +2 for "\r\n"
+				tooLong = true
+				buf = buf[:0]
+			} else {
+				buf = append(buf, chunk...)
+			}
+		}
+
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case errors.Is(err, io.EOF):
+			// The last line may have no trailing newline
+			if len(buf) == 0 && !tooLong {
+				return nil, false, io.EOF
+			}
+		case err != nil:
+			return nil, false, err
+		}
+
+		line = bytes.TrimRight(buf, "\r\n")
+		if len(line) > MaxImportLineBytes {
+			return nil, true, nil
+		}
+		return line, tooLong, nil
+	}
+}
+
+// parseImportLine decodes and validates one line, sanitizing text like every write path
+// The returned trick carries the slug even when validation fails (for the result line).
+func parseImportLine(line []byte) (models.Trick, error) {
+	var record models.TrickImportRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return models.Trick{}, ErrInvalidImportLine
+	}
+
+	trick := models.Trick{Slug: record.Slug}
+	if !importSlug.MatchString(record.Slug) {
+		return trick, ErrInvalidImportSlug
+	}
+
+	name := sanitize.Text(record.Name)
+	if name == "" || utf8.RuneCountInString(name) > MaxImportNameLength {
+		return trick, ErrInvalidImportName
+	}
+	if record.Difficulty != nil && (*record.Difficulty < MinImportDifficulty || *record.Difficulty > MaxImportDifficulty) {
+		return trick, ErrInvalidImportDifficulty
+	}
+	weight, err := importWeight(record.Weight)
+	if err != nil {
+		return trick, err
+	}
+
+	trick.Name = name
+	trick.Description = sanitize.OptionalText(record.Description)
+	trick.Difficulty = record.Difficulty
+	trick.ExecutionNotes = sanitize.OptionalText(record.ExecutionNotes)
+	trick.TakeoffStanceID = record.TakeoffStanceID
+	trick.LandingStanceID = record.LandingStanceID
+	trick.Attribution = sanitize.OptionalText(record.Attribution)
+	trick.License = sanitize.OptionalText(record.License)
+	trick.Weight = weight
+	trick.FlipID = record.FlipID
+	return trick, nil
+}
+
+// checkImportUnique checks a line's name and slug like any other trick write
+// The slug only clashes with another trick's or an alias's - the line's own
+// trick is the one it updates. Names queued in the unwritten batch count too.
+// Returns the line's error (a *DuplicateTrickError), or err when the lookup
+// failed - that stops the import.
+func (s *AdminService) checkImportUnique(ctx context.Context, trick *models.Trick, names map[string]string) (lineErr, err error) {
+	key := strings.ToLower(trick.Name)
+	if slug, ok := names[key]; ok && slug != trick.Slug {
+		return &DuplicateTrickError{Field: "name", Value: trick.Name}, nil
+	}
+
+	err = checkTrickUnique(ctx, s.trickRepo, trick.Name, trick.Slug, trick.Slug)
+	var duplicate *DuplicateTrickError
+	switch {
+	case errors.As(err, &duplicate):
+		return duplicate, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to check imported trick %s: %w", trick.Slug, err)
+	}
+	names[key] = trick.Slug
+	return nil, nil
+}
+
+// markRenames sets FormerName on every trick of a batch that renames an existing one
+// UpsertImported keeps the current name as a former-name alias, so searches for
+// it still find the trick. A change of case only ("Backflip" -> "BackFlip") isn't
+// a rename. A slug twice in one batch is renamed from its previous line's name.
+func (s *AdminService) markRenames(ctx context.Context, tricks []repository.ImportedTrick) error {
+	slugs := make([]string, len(tricks))
+	for i, trick := range tricks {
+		slugs[i] = trick.Slug
+	}
+	existing, err := s.trickRepo.GetBySlugs(ctx, slugs)
+	if err != nil {
+		return fmt.Errorf("failed to get imported tricks: %w", err)
+	}
+
+	names := make(map[string]string, len(existing))
+	for _, trick := range existing {
+		names[trick.Slug] = trick.Name
+	}
+	for i := range tricks {
+		trick := &tricks[i]
+		current, ok := names[trick.Slug]
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(current, trick.Name) {
+			if slug := aliasSlug(current); slug != "" {
+				trick.FormerName, trick.FormerNameSlug = current, slug
+			}
+		}
+		names[trick.Slug] = trick.Name
+	}
+	return nil
+}
+
+// importCategory checks a line's flip_id, or - without one - suggests a category
+// A confident suggestion (see AutoAssignConfidence) is sent along as the trick's
+// flip_id, marked SuggestedFlip so it can't replace a category the trick has.
+// Returns the suggestion to report, nil when there is none or suggestions are off.
+func (s *AdminService) importCategory(trick *repository.ImportedTrick, categories []models.Category) (*models.CategorySuggestion, error) {
+	if trick.FlipID != nil {
+		known := slices.ContainsFunc(categories, func(category models.Category) bool {
+			return category.ID == *trick.FlipID
+		})
+		if !known {
+			return nil, ErrUnknownImportCategory
+		}
+		return nil, nil
+	}
+
+	if !s.suggestCategories {
+		return nil, nil
+	}
+	suggestion := SuggestCategory(trick.Name, categories)
+	if suggestion != nil && suggestion.Confidence >= AutoAssignConfidence {
+		flipID := suggestion.ID
+		trick.FlipID = &flipID
+		trick.SuggestedFlip = true
+	}
+	return suggestion, nil
+}
+
+// importWeight range-checks an imported weight; 0 means none was given
+// ParseInt with bitSize 16 rejects anything that wouldn't fit the column,
+// as well as fractions ("2.5") and exponents ("1e3").
+func importWeight(raw *json.Number) (int16, error) {
+	if raw == nil {
+		return 0, nil
+	}
+	weight, err := strconv.ParseInt(raw.String(), 10, 16)
+	if err != nil || weight < MinImportWeight || weight > MaxImportWeight {
+		return 0, ErrInvalidImportWeight
+	}
+	return int16(weight), nil
+}
+
+// countImportResult adds one line's outcome to the summary
+func countImportResult(summary *models.TrickImportSummary, status string) {
+	switch status {
+	case models.ImportCreated:
+		summary.Created++
+	case models.ImportUpdated:
+		summary.Updated++
+	case models.ImportSkipped:
+		summary.Skipped++
+	default:
+		summary.Invalid++
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/testutil/fakes"
+)
+
+// syntheticImportLine is line n of a generated import - every invalidEvery-th line has a bad slug
+func syntheticImportLine(n, invalidEvery int) string {
+	slug := fmt.Sprintf("synthetic-trick-%d", n)
+	if invalidEvery > 0 && n%invalidEvery == 0 {
+		slug = "Not A Slug"
+	}
+	return fmt.Sprintf(`{"slug":%q,"name":"Synthetic Trick %d","difficulty":%d,"description":"Generated for the import test"}`+"\n",
+		slug, n, n%10+1)
+}
+
+// writeSyntheticImport writes lines generated lines to w, counting them in written, then closes w
+func writeSyntheticImport(w *io.PipeWriter, lines, invalidEvery int, written *atomic.Int64) {
+	for n := 1; n <= lines; n++ {
+		if _, err := io.WriteString(w, syntheticImportLine(n, invalidEvery)); err != nil {
+			w.CloseWithError(err)
+			return
+		}
+		written.Add(1)
+	}
+	w.Close()
+}
+
+func newImportService(trickRepo *fakes.ImportTrickRepo) *AdminService {
+	return NewAdminService(trickRepo, nil, nil, nil, nil, &fakes.CategoryRepo{}, false, false, time.Hour, nil)
+}
+
+// heapInUse is the live heap after a full collection
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func TestImportTricksStreamsWithFlatMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("imports 100k lines")
+	}
+
+	const (
+		lines        = 100_000
+		invalidEvery = 1_000
+		sampleEvery  = 10_000
+		// Live heap may not grow by more than this between the first and last samples.
+		// A buffered import of 100k lines (~10MB of input) would blow well past it.
+		maxHeapGrowth = 2 << 20
+	)
+
+	reader, writer := io.Pipe()
+	var written atomic.Int64
+	go writeSyntheticImport(writer, lines, invalidEvery, &written)
+
+	trickRepo := &fakes.ImportTrickRepo{}
+	var emitted int
+	var baseline, peak uint64
+	var maxAhead int64
+	emit := func(result models.TrickImportResult, lineErr error) error {
+		emitted++
+		if result.Line != emitted {
+			return fmt.Errorf("result for line %d arrived as result %d", result.Line, emitted)
+		}
+		if wantInvalid := result.Line%invalidEvery == 0; wantInvalid != (lineErr != nil) {
+			return fmt.Errorf("line %d: error = %v, want invalid = %v", result.Line, lineErr, wantInvalid)
+		}
+
+		// Back-pressure: the writer can't get further ahead than one batch plus the read buffer
+		maxAhead = max(maxAhead, written.Load()-int64(emitted))
+
+		if emitted%sampleEvery == 0 {
+			heap := heapInUse()
+			if baseline == 0 {
+				baseline = heap
+			}
+			peak = max(peak, heap)
+		}
+		return nil
+	}
+
+	summary, err := newImportService(trickRepo).ImportTricks(context.Background(), reader, nil, emit)
+	if err != nil {
+		t.Fatalf("ImportTricks() error = %v", err)
+	}
+
+	wantInvalid := lines / invalidEvery
+	if !summary.Done || summary.Lines != lines || summary.Created != lines-wantInvalid || summary.Invalid != wantInvalid {
+		t.Errorf("summary = %+v, want %d lines, %d created, %d invalid", *summary, lines, lines-wantInvalid, wantInvalid)
+	}
+	if emitted != lines {
+		t.Errorf("emitted %d results, want %d", emitted, lines)
+	}
+	if trickRepo.Imported != lines-wantInvalid || trickRepo.MaxBatch > importBatchSize {
+		t.Errorf("imported %d tricks in batches of up to %d, want %d in batches of up to %d",
+			trickRepo.Imported, trickRepo.MaxBatch, lines-wantInvalid, importBatchSize)
+	}
+	if maxAhead > 2*importBatchSize {
+		t.Errorf("writer got %d lines ahead of the results, want at most %d", maxAhead, 2*importBatchSize)
+	}
+	if growth := int64(peak) - int64(baseline); growth > maxHeapGrowth {
+		t.Errorf("live heap grew by %d bytes over the import (from %d to %d), want at most %d",
+			growth, baseline, peak, maxHeapGrowth)
+	}
+}
+
+func TestImportTricksLineLimits(t *testing.T) {
+	long := `{"slug":"long-one","name":"` + strings.Repeat("x", MaxImportLineBytes) + `"}`
+
+	tests := []struct {
+		name        string
+		input       string
+		wantLines   int
+		wantInvalid int
+		wantErrs    []error // per result line, nil for imported lines
+	}{
+		{name: "trailing newline adds no line", input: syntheticImportLine(1, 0), wantLines: 1, wantErrs: []error{nil}},
+		{name: "no trailing newline", input: strings.TrimSuffix(syntheticImportLine(1, 0), "\n"), wantLines: 1, wantErrs: []error{nil}},
+		{name: "crlf", input: strings.ReplaceAll(syntheticImportLine(1, 0), "\n", "\r\n"), wantLines: 1, wantErrs: []error{nil}},
+		{name: "blank lines skipped", input: "\n" + syntheticImportLine(1, 0) + "  \n", wantLines: 1, wantErrs: []error{nil}},
+		{
+			name:      "long line rejected and the next one read",
+			input:     long + "\n" + syntheticImportLine(2, 0),
+			wantLines: 2, wantInvalid: 1, wantErrs: []error{ErrImportLineTooLong, nil},
+		},
+		{name: "not json", input: "slug=backflip\n", wantLines: 1, wantInvalid: 1, wantErrs: []error{ErrInvalidImportLine}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			emit := func(result models.TrickImportResult, lineErr error) error {
+				errs = append(errs, lineErr)
+				return nil
+			}
+
+			summary, err := newImportService(&fakes.ImportTrickRepo{}).ImportTricks(context.Background(), strings.NewReader(tt.input), nil, emit)
+			if err != nil {
+				t.Fatalf("ImportTricks() error = %v", err)
+			}
+			if summary.Lines != tt.wantLines || summary.Invalid != tt.wantInvalid {
+				t.Errorf("summary = %+v, want %d lines, %d invalid", *summary, tt.wantLines, tt.wantInvalid)
+			}
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("got %d results, want %d", len(errs), len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				if errs[i] != want {
+					t.Errorf("result %d error = %v, want %v", i+1, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseImportLineWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		weight  string // Raw JSON value, empty to leave the field out
+		want    int16
+		wantErr error
+	}{
+		{name: "absent", want: 0},
+		{name: "null", weight: "null", want: 0},
+		{name: "minimum", weight: "1", want: 1},
+		{name: "maximum", weight: "32767", want: 32767},
+		{name: "zero", weight: "0", wantErr: ErrInvalidImportWeight},
+		{name: "negative", weight: "-1", wantErr: ErrInvalidImportWeight},
+		{name: "one over the column", weight: "32768", wantErr: ErrInvalidImportWeight},
+		{name: "would wrap to 1 as int16", weight: "65537", wantErr: ErrInvalidImportWeight},
+		{name: "huge", weight: "99999999999999999999", wantErr: ErrInvalidImportWeight},
+		{name: "fraction", weight: "2.5", wantErr: ErrInvalidImportWeight},
+		{name: "exponent", weight: "1e3", wantErr: ErrInvalidImportWeight},
+		{name: "string", weight: `"heavy"`, wantErr: ErrInvalidImportLine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := `{"slug":"backflip","name":"Backflip"`
+			if tt.weight != "" {
+				line += `,"weight":` + tt.weight
+			}
+			line += "}"
+
+			trick, err := parseImportLine([]byte(line))
+			if err != tt.wantErr {
+				t.Fatalf("parseImportLine(%s) error = %v, want %v", line, err, tt.wantErr)
+			}
+			if err == nil && trick.Weight != tt.want {
+				t.Errorf("weight = %d, want %d", trick.Weight, tt.want)
+			}
+		})
+	}
+
+	// The error names the field and the allowed range
+	for _, want := range []string{"weight", fmt.Sprint(MinImportWeight), fmt.Sprint(MaxImportWeight)} {
+		if !strings.Contains(ErrInvalidImportWeight.Error(), want) {
+			t.Errorf("ErrInvalidImportWeight = %q, missing %q", ErrInvalidImportWeight, want)
+		}
+	}
+}
+
+func BenchmarkImportTricks(b *testing.B) {
+	const lines = 10_000
+
+	var input strings.Builder
+	for n := 1; n <= lines; n++ {
+		input.WriteString(syntheticImportLine(n, 0))
+	}
+	data := input.String()
+	emit := func(result models.TrickImportResult, lineErr error) error { return nil }
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		service := newImportService(&fakes.ImportTrickRepo{})
+		if _, err := service.ImportTricks(context.Background(), strings.NewReader(data), nil, emit); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
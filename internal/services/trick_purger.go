@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"tricking-api/internal/repository"
+)
+
+// trickPurgeBatchSize bounds how many tricks one purge transaction removes
+// Small batches keep locks short when a large delete comes due at once
+const trickPurgeBatchSize = 100
+
+// TrickPurger permanently removes soft-deleted tricks once their purge date passes
+//
+// IDEMPOTENCY:
+// Each batch only selects tricks that are still past their purge date, so a
+// crashed or repeated run just picks up whatever is left.
+type TrickPurger struct {
+	trickRepo repository.TrickRepositoryInterface
+}
+
+// NewTrickPurger creates a new TrickPurger instance
+func NewTrickPurger(trickRepo repository.TrickRepositoryInterface) *TrickPurger {
+	return &TrickPurger{trickRepo: trickRepo}
+}
+
+// PurgeExpired removes every trick past its purge date, one batch at a time
+// Returns how many tricks were purged
+func (p *TrickPurger) PurgeExpired(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		purged, err := p.trickRepo.PurgeExpired(ctx, trickPurgeBatchSize)
+		total += purged
+		if err != nil {
+			return total, fmt.Errorf("failed to purge expired tricks: %w", err)
+		}
+		// A short batch means nothing else is due
+		if purged < trickPurgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// Run purges on an interval until ctx is cancelled
+func (p *TrickPurger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			purged, err := p.PurgeExpired(ctx)
+			if err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("Purged %d deleted tricks", purged)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
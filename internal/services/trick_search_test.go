@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// trigrams splits s into pg_trgm's trigrams: lowercased words of letters and
+// digits, each padded with two spaces in front and one behind
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// trigramSimilarity is pg_trgm's similarity(): shared trigrams over all distinct ones
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	shared := 0
+	for trigram := range ta {
+		if tb[trigram] {
+			shared++
+		}
+	}
+	if union := len(ta) + len(tb) - shared; union > 0 {
+		return float64(shared) / float64(union)
+	}
+	return 0
+}
+
+// fakeSearchTrickRepo stands in for the search query over the fixture catalog:
+// substring matches on the name, plus - with minSimilarity > 0 - names with a
+// word whose trigram similarity to the query reaches it (word_similarity's
+// "best-matching stretch", one word at a time)
+type fakeSearchTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	minSimilarity float64 // As passed by the service
+}
+
+func (r *fakeSearchTrickRepo) Search(ctx context.Context, query string, limit int, minSimilarity float64) ([]models.Trick, error) {
+	r.minSimilarity = minSimilarity
+	q := strings.ToLower(query)
+
+	var matches []models.Trick
+	for _, trick := range fixtures.CatalogTricks() {
+		substring := strings.Contains(strings.ToLower(trick.Name), q)
+		if minSimilarity == 0 {
+			if substring {
+				matches = append(matches, trick)
+			}
+			continue
+		}
+
+		best := trigramSimilarity(query, trick.Name)
+		for _, word := range strings.Fields(trick.Name) {
+			best = max(best, trigramSimilarity(query, word))
+		}
+		if substring || best >= minSimilarity {
+			trick.Similarity = fixtures.Ptr(best)
+			matches = append(matches, trick)
+		}
+	}
+	return matches, nil
+}
+
+func TestSearchTricksToleratesTypos(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		minSimilarity float64 // 0 = pg_trgm not installed
+		wantTop       string  // Best result's slug, "" for no results
+		wantMatch     string
+	}{
+		{name: "doubled letter", query: "gainner", minSimilarity: 0.3, wantTop: "gainer", wantMatch: matchFuzzy},
+		{name: "wrong vowel", query: "webstir", minSimilarity: 0.3, wantTop: "webster", wantMatch: matchFuzzy},
+		{name: "dropped letter", query: "backflp", minSimilarity: 0.3, wantTop: "backflip", wantMatch: matchFuzzy},
+		{name: "exact match still wins", query: "cork", minSimilarity: 0.3, wantTop: "cork", wantMatch: matchExact},
+		{name: "nothing close", query: "xyzzy", minSimilarity: 0.3},
+		{name: "typo without pg_trgm", query: "gainner"},
+		{name: "exact match without pg_trgm", query: "webster", wantTop: "webster", wantMatch: matchExact},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSearchTrickRepo{}
+			service := NewTrickService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, 7, NewDifficultyBands(nil), tt.minSimilarity, 0, 0, false)
+
+			results, err := service.SearchTricks(context.Background(), tt.query, 10)
+			if err != nil {
+				t.Fatalf("SearchTricks(%q) error = %v", tt.query, err)
+			}
+			if repo.minSimilarity != tt.minSimilarity {
+				t.Errorf("repository got threshold %v, want %v", repo.minSimilarity, tt.minSimilarity)
+			}
+
+			if tt.wantTop == "" {
+				if len(results) != 0 {
+					t.Errorf("SearchTricks(%q) = %+v, want no results", tt.query, results)
+				}
+				return
+			}
+			if len(results) == 0 {
+				t.Fatalf("SearchTricks(%q) found nothing, want %s", tt.query, tt.wantTop)
+			}
+
+			top := results[0]
+			if top.ID != tt.wantTop || top.MatchType != tt.wantMatch {
+				t.Errorf("top result = %s:%s, want %s:%s", top.ID, top.MatchType, tt.wantTop, tt.wantMatch)
+			}
+			// Scores are there for a "did you mean" hint only when fuzzy search is on
+			switch {
+			case tt.minSimilarity == 0 && top.Score != nil:
+				t.Errorf("score = %v without pg_trgm, want none", *top.Score)
+			case tt.minSimilarity > 0 && (top.Score == nil || *top.Score < tt.minSimilarity || *top.Score > 1):
+				t.Errorf("score = %v, want between %v and 1", top.Score, tt.minSimilarity)
+			}
+		})
+	}
+}
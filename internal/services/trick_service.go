@@ -2,11 +2,22 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
 
 	"tricking-api/internal/models"
 	"tricking-api/internal/repository"
+	"tricking-api/internal/viewer"
 )
 
 // =============================================================================
@@ -18,17 +29,92 @@ import (
 // This allows us to change repository implementation without changing handlers
 var ErrTrickNotFound = errors.New("trick not found")
 
+// ErrInvalidCursor indicates a pagination cursor that wasn't issued by us
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// MaxBatchSlugs caps how many tricks one batch lookup may request
+const MaxBatchSlugs = 100
+
+// ErrTooManySlugs indicates a batch lookup above MaxBatchSlugs
+var ErrTooManySlugs = errors.New("too many slugs in batch lookup")
+
+// ErrUnknownInclude indicates an include= value the endpoint doesn't offer
+var ErrUnknownInclude = errors.New("unknown include")
+
+// Optional sections, selected with ?include=a,b (video_flags is list-only)
+// In the dictionary, featured_video also brings the full videos list (same query)
+const (
+	IncludeFeaturedVideo = "featured_video"
+	IncludePerformers    = "performers"
+	IncludePrerequisites = "prerequisites"
+	IncludeVideoFlags    = "video_flags"
+)
+
+// dictionaryIncludes is every include the dictionary offers - also the default set
+// Kept sorted, like the sets parseIncludes returns (they're part of the cache key)
+var dictionaryIncludes = []string{IncludeFeaturedVideo, IncludePerformers, IncludePrerequisites}
+
+// maxDictionaryPerformers caps the dictionary's performer list (performer_total has the full count)
+const maxDictionaryPerformers = 10
+
+// trickListIncludes is every include GET /tricks offers - none by default
+var trickListIncludes = []string{IncludeFeaturedVideo, IncludeVideoFlags}
+
+// includeAliases maps accepted alternative spellings to their include name
+var includeAliases = map[string]string{
+	"videos": IncludeFeaturedVideo,
+}
+
+// MaxNewTrickDays caps the ?days= window of GET /tricks/new
+const MaxNewTrickDays = 90
+
+// MaxPopularWindowDays caps the ?window= of GET /tricks/popular (daily view buckets kept)
+const MaxPopularWindowDays = repository.ViewDayRetentionDays
+
+// ErrInvalidPopularWindow indicates a ?window= that isn't 1d-30d
+var ErrInvalidPopularWindow = errors.New("invalid popular window")
+
+// ErrInvalidDifficultyRange indicates min_difficulty > max_difficulty
+var ErrInvalidDifficultyRange = errors.New("min difficulty is greater than max difficulty")
+
 // =============================================================================
 // SERVICE INTERFACE
 // =============================================================================
 
 // TrickServiceInterface defines the contract for trick business operations
 type TrickServiceInterface interface {
-	GetSimpleTrickById(ctx context.Context, id string) (*models.TrickDetailResponse, error)
-	GetFullDetailsTrickById(ctx context.Context, id string) (*models.TrickFullDetailsResponse, error)
+	GetSimpleTrickById(ctx context.Context, id string, locale string) (*models.TrickDetailResponse, int64, error)
+	GetTrickDictionary(ctx context.Context, id string, includes []string, locale string) (*models.TrickDictionaryResponse, error)
+	GetTrickDictionaries(ctx context.Context, ids []string, includes []string, locale string) []DictionaryResult
 	GetSimpleTricksList(ctx context.Context) ([]models.TrickSimpleResponse, error)
+	ListTricks(ctx context.Context, cursor string, limit int, includes []string, withCounts bool) (*models.TrickPage, error)
+	GetTricksList(ctx context.Context, filter models.TrickListFilter) ([]models.Trick, error)
+	ExportTricks(ctx context.Context, filter models.TrickListFilter, fn func(models.TrickExportRow) error) error
+	GetTricksBySlugs(ctx context.Context, slugs []string) (*models.TrickBatchResponse, error)
+	GetTrickSlugs(ctx context.Context) ([]models.TrickSlugResponse, error)
+	GetDailyTrick(ctx context.Context, date time.Time, locale string) (*models.TrickDictionaryResponse, error)
+	GetTrickStats(ctx context.Context) (*models.TrickStatsResponse, error)
+	GetDifficultyHistogram(ctx context.Context, filter models.TrickListFilter) ([]models.DifficultyBucket, error)
+	GetTrickGraph(ctx context.Context, filter models.TrickListFilter, relations []string) (*models.TrickGraph, error)
+	GetTags(ctx context.Context) ([]models.TagResponse, error)
+	NewTricksSince(days int) time.Time
+	GetNewTricks(ctx context.Context, since time.Time, limit int) ([]models.NewTrickResponse, error)
+	GetPopularTricks(ctx context.Context, days, limit int) ([]models.PopularTrickResponse, time.Time, error)
+	GetPrerequisites(ctx context.Context, id string) ([]models.TrickSimpleResponse, error)
+	GetUnlocks(ctx context.Context, id string) ([]models.TrickSimpleResponse, error)
+	SearchTricks(ctx context.Context, query string, limit int) ([]models.TrickSearchResult, error)
+	AutocompleteTricks(ctx context.Context, prefix string) ([]models.TrickAutocompleteResponse, error)
 	GetLastModified(ctx context.Context) (int64, error)
 	GetLastModifiedByID(ctx context.Context, id string) (int64, error)
+	GetTrickChanges(ctx context.Context, since int64) (*models.TrickChangesResponse, error)
+	GetCatalogDelta(ctx context.Context, sinceVersion string) (*models.CatalogDeltaResponse, error)
+	StreamCatalogSnapshot(ctx context.Context, start func(*models.CatalogDeltaResponse) error, fn func(models.TrickDetailResponse) error) error
+	RecordView(id string)
+	CreateTrick(ctx context.Context, req models.TrickCreateRequest, createdBy *uuid.UUID) (*models.TrickDetailResponse, error)
+	GetTrickSubmissions(ctx context.Context, userID uuid.UUID, limit int) ([]models.TrickSubmission, error)
+	UpdateTrick(ctx context.Context, id string, req models.TrickUpdateRequest, changedBy *uuid.UUID) (*models.TrickDetailResponse, int64, error)
+	PatchTrick(ctx context.Context, id string, req models.TrickPatchRequest, changedBy *uuid.UUID) (*models.TrickDetailResponse, int64, error)
+	CreateTrickBatch(ctx context.Context, rows []TrickBatchRow, createdBy *uuid.UUID, dryRun bool, emit TrickBatchEmitter) (*models.TrickBatchSummary, error)
 }
 
 // =============================================================================
@@ -38,43 +124,273 @@ type TrickServiceInterface interface {
 // TrickService implements TrickServiceInterface
 type TrickService struct {
 	// Services can depend on multiple repositories
-	trickRepo repository.TrickRepositoryInterface
-	videoRepo repository.VideoRepositoryInterface
+	trickRepo       repository.TrickRepositoryInterface
+	videoRepo       repository.VideoRepositoryInterface
+	mistakeRepo     repository.MistakeRepositoryInterface
+	prereqRepo      repository.PrerequisiteRepositoryInterface
+	aliasRepo       repository.AliasRepositoryInterface
+	tagRepo         repository.TagRepositoryInterface
+	translationRepo repository.TranslationRepositoryInterface
+	categoryRepo    repository.CategoryRepositoryInterface
+
+	// viewCounter batches trick views in memory between flushes
+	viewCounter *ViewCounter
+
+	// dictionaryCache holds assembled dictionaries (invalidated by admin and moderator writes)
+	dictionaryCache *DictionaryCache
+
+	// newTrickDays is the window, in days, in which a trick counts as new (is_new, GET /tricks/new)
+	newTrickDays int
+
+	// bands names each trick's difficulty band in detail responses
+	bands *DifficultyBands
+
+	// searchSimilarity is the trigram similarity at which search tolerates typos
+	// 0 when pg_trgm isn't installed - search is then substring/full-text only
+	searchSimilarity float64
+
+	// tombstoneRetention is how long a deleted trick stays in the table before it
+	// is purged - the oldest snapshot version a catalog delta can still serve
+	tombstoneRetention time.Duration
+
+	// graphMaxNodes caps GetTrickGraph (see trick_graph.go)
+	graphMaxNodes int
+
+	// suggestCategories makes CreateTrick suggest a category when none is given
+	suggestCategories bool
+
+	// stats is the last GetTrickStats result, reused until trickStatsTTL passes
+	statsMu sync.Mutex
+	stats   *models.TrickStatsResponse
 }
 
 // NewTrickService creates a new TrickService instance
 // Accepts interfaces, not concrete types - this enables mocking for tests
-func NewTrickService(trickRepo repository.TrickRepositoryInterface, videoRepo repository.VideoRepositoryInterface) *TrickService {
+func NewTrickService(
+	trickRepo repository.TrickRepositoryInterface,
+	videoRepo repository.VideoRepositoryInterface,
+	mistakeRepo repository.MistakeRepositoryInterface,
+	prereqRepo repository.PrerequisiteRepositoryInterface,
+	aliasRepo repository.AliasRepositoryInterface,
+	tagRepo repository.TagRepositoryInterface,
+	translationRepo repository.TranslationRepositoryInterface,
+	categoryRepo repository.CategoryRepositoryInterface,
+	viewCounter *ViewCounter,
+	dictionaryCache *DictionaryCache,
+	newTrickDays int,
+	bands *DifficultyBands,
+	searchSimilarity float64,
+	tombstoneRetention time.Duration,
+	graphMaxNodes int,
+	suggestCategories bool,
+) *TrickService {
 	return &TrickService{
-		trickRepo: trickRepo,
-		videoRepo: videoRepo,
+		trickRepo:       trickRepo,
+		videoRepo:       videoRepo,
+		mistakeRepo:     mistakeRepo,
+		prereqRepo:      prereqRepo,
+		aliasRepo:       aliasRepo,
+		tagRepo:         tagRepo,
+		translationRepo: translationRepo,
+		categoryRepo:    categoryRepo,
+		viewCounter:     viewCounter,
+		dictionaryCache: dictionaryCache,
+		newTrickDays:    newTrickDays,
+		bands:           bands,
+
+		searchSimilarity:   searchSimilarity,
+		tombstoneRetention: tombstoneRetention,
+		graphMaxNodes:      graphMaxNodes,
+		suggestCategories:  suggestCategories,
 	}
 }
 
+// detailResponse is Trick.ToDetailResponse plus the fields derived from config (band)
+func (s *TrickService) detailResponse(trick *models.Trick, view models.ResponseView) models.TrickDetailResponse {
+	response := trick.ToDetailResponse(view)
+	response.Band = s.bands.Of(trick.Difficulty)
+	return response
+}
+
+// responseView is the view for the caller's role (set by middleware.ExtractUserContext)
+// Public catalog routes run without a role, so their callers get PublicView.
+func responseView(ctx context.Context) models.ResponseView {
+	return models.ViewForRole(viewer.Role(ctx))
+}
+
 // GetSimpleTrickById retrieves basic trick details without videos
-// "simple" endpoint
-func (s *TrickService) GetSimpleTrickById(ctx context.Context, id string) (*models.TrickDetailResponse, error) {
+// "simple" endpoint. Also returns the trick's last-modified Unix time (for the
+// ETag), read from the same row so the handler needs no second query.
+// locale picks a translation of the texts (see translate) - "" for none.
+func (s *TrickService) GetSimpleTrickById(ctx context.Context, id string, locale string) (*models.TrickDetailResponse, int64, error) {
 	// Fetch trick from repository
-	trick, err := s.trickRepo.GetByID(ctx, id)
-	if err != nil {
-		// Convert repository errors to service errors
-		// This abstracts the data layer from the handler layer
-		if errors.Is(err, repository.ErrNotFound) {
-			return nil, ErrTrickNotFound
+	trick, err := withTrickID(ctx, s, id, func(slug string) (*models.Trick, error) {
+		trick, err := s.trickRepo.GetByID(ctx, slug)
+		if err != nil {
+			// Convert repository errors to service errors
+			// This abstracts the data layer from the handler layer
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrTrickNotFound
+			}
+			// Wrap unexpected errors with context
+			return nil, fmt.Errorf("failed to get trick: %w", err)
 		}
-		// Wrap unexpected errors with context
-		return nil, fmt.Errorf("failed to get trick: %w", err)
+		return trick, nil
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Convert model to response DTO
 	// The handler doesn't need to know about this transformation
-	response := trick.ToDetailResponse()
-	return &response, nil
+	response := s.detailResponse(trick, responseView(ctx))
+	// flip_name came from the same row; the handler drops it unless ?expand=flip
+	response.FlipName = trick.FlipName
+	if err := s.translate(ctx, trick.Slug, locale, &response); err != nil {
+		return nil, 0, err
+	}
+	return &response, trickLastModified(trick), nil
 }
 
-// GetFullDetailsTrickById retrieves full trick details WITH videos
-func (s *TrickService) GetFullDetailsTrickById(ctx context.Context, id string) (*models.TrickFullDetailsResponse, error) {
+// resolveTrickID turns a public trick identifier into the trick's slug
+// Slugs are THE public ID and pass straight through. Purely numeric
+// identifiers are resolved once (see TrickRepository.ResolveNumericID) so that
+// everything downstream - queries, cache keys, view counts - only sees slugs.
+func (s *TrickService) resolveTrickID(ctx context.Context, id string) (string, error) {
+	legacyID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return id, nil
+	}
+
+	slug, err := s.trickRepo.ResolveNumericID(ctx, id, legacyID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrTrickNotFound
+		}
+		return "", fmt.Errorf("failed to resolve trick ID: %w", err)
+	}
+	return slug, nil
+}
+
+// withTrickID runs load with the slug of a public trick identifier, falling back to aliases
+// An identifier that isn't a trick is tried once more as an alias slug, so
+// /tricks/side-somi serves the canonical trick. The response carries the
+// canonical ID - no redirect, clients keep the URL they asked for.
+func withTrickID[T any](ctx context.Context, s *TrickService, id string, load func(slug string) (T, error)) (T, error) {
+	var zero T
+
+	slug, err := s.resolveTrickID(ctx, id)
+	if err == nil {
+		result, err := load(slug)
+		if !errors.Is(err, ErrTrickNotFound) {
+			return result, err
+		}
+	} else if !errors.Is(err, ErrTrickNotFound) {
+		return zero, err
+	}
+
+	canonical, err := s.aliasRepo.ResolveSlug(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return zero, ErrTrickNotFound
+		}
+		return zero, fmt.Errorf("failed to resolve trick alias: %w", err)
+	}
+	return load(canonical)
+}
+
+// trickLastModified is GREATEST(created_at, updated_at) as Unix seconds
+// (GetLastModifiedByID computes the same thing in SQL). A NULL updated_at
+// falls back to created_at.
+func trickLastModified(trick *models.Trick) int64 {
+	var lastModified int64
+	if trick.CreatedAt != nil {
+		lastModified = trick.CreatedAt.Unix()
+	}
+	if trick.UpdatedAt != nil && trick.UpdatedAt.Unix() > lastModified {
+		lastModified = trick.UpdatedAt.Unix()
+	}
+	return lastModified
+}
+
+// GetTrickDictionary retrieves full trick details, with the sections in includes
+// includes comes from ParseDictionaryIncludes. Results are cached per
+// (trick, include set, locale); the returned value may be shared - don't modify it.
+func (s *TrickService) GetTrickDictionary(ctx context.Context, id string, includes []string, locale string) (*models.TrickDictionaryResponse, error) {
+	return withTrickID(ctx, s, id, func(slug string) (*models.TrickDictionaryResponse, error) {
+		cacheKey := dictionaryCacheKey(slug, includes, locale)
+		if cached, ok := s.dictionaryCache.get(cacheKey); ok {
+			return cached, nil
+		}
+
+		response, err := s.buildTrickDictionary(ctx, slug, includes, locale)
+		if err != nil {
+			return nil, err
+		}
+
+		s.dictionaryCache.set(cacheKey, response)
+		return response, nil
+	})
+}
+
+// batchDictionaryWorkers bounds how many dictionaries one batch fetch assembles at once
+// A cache miss runs several queries, so this also bounds the pool connections one batch holds
+const batchDictionaryWorkers = 4
+
+// DictionaryResult is one entry of GetTrickDictionaries - the dictionary, or why there is none
+type DictionaryResult struct {
+	Dictionary *models.TrickDictionaryResponse
+	Err        error // ErrTrickNotFound, or a wrapped database error
+}
+
+// GetTrickDictionaries is GetTrickDictionary for several tricks, one result per id in order
+// Every id goes through the same per-trick cache; up to batchDictionaryWorkers
+// ids are looked up at a time, so misses are assembled concurrently. A repeated
+// id is looked up once and its result repeated. One id failing doesn't affect
+// the others - the caller decides what a partial batch means.
+func (s *TrickService) GetTrickDictionaries(ctx context.Context, ids []string, includes []string, locale string) []DictionaryResult {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found := make([]DictionaryResult, len(unique))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(batchDictionaryWorkers, len(unique)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				dictionary, err := s.GetTrickDictionary(ctx, unique[i], includes, locale)
+				found[i] = DictionaryResult{Dictionary: dictionary, Err: err}
+			}
+		}()
+	}
+	for i := range unique {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	byID := make(map[string]DictionaryResult, len(unique))
+	for i, id := range unique {
+		byID[id] = found[i]
+	}
+	results := make([]DictionaryResult, len(ids))
+	for i, id := range ids {
+		results[i] = byID[id]
+	}
+	return results
+}
 
+// buildTrickDictionary assembles a dictionary from the database (the cache-miss path)
+// locale is the content language - its translation (if any) is part of the cached dictionary.
+func (s *TrickService) buildTrickDictionary(ctx context.Context, id string, includes []string, locale string) (*models.TrickDictionaryResponse, error) {
 	// Step 1: Get the trick
 	trick, err := s.trickRepo.GetByID(ctx, id)
 	if err != nil {
@@ -84,6 +400,45 @@ func (s *TrickService) GetFullDetailsTrickById(ctx context.Context, id string) (
 		return nil, fmt.Errorf("failed to get trick: %w", err)
 	}
 
+	// Common mistakes are part of every dictionary (and of its completeness)
+	mistakes, err := s.mistakeRepo.FindByTrickSlug(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mistakes for trick: %w", err)
+	}
+
+	response := &models.TrickDictionaryResponse{
+		// Dictionaries are cached and shared between callers - never the staff view
+		TrickDetailResponse: s.detailResponse(trick, models.PublicView),
+		CommonMistakes:      mistakes,
+		Completeness:        dictionaryCompleteness(trick, mistakes),
+	}
+	if err := s.translate(ctx, id, locale, &response.TrickDetailResponse); err != nil {
+		return nil, err
+	}
+
+	// Who has footage of this trick - one GROUP BY over its videos
+	if hasInclude(includes, IncludePerformers) {
+		performers, total, err := s.videoRepo.FindPerformersByTrickID(ctx, id, maxDictionaryPerformers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get performers for trick: %w", err)
+		}
+		response.Performers = performers
+		response.PerformerTotal = &total
+	}
+
+	// Direct prerequisites only - the full chain is a walk through /prerequisites
+	if hasInclude(includes, IncludePrerequisites) {
+		prerequisites, err := s.prereqRepo.FindPrerequisites(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prerequisites for trick: %w", err)
+		}
+		response.Prerequisites = prerequisites
+	}
+
+	if !hasInclude(includes, IncludeFeaturedVideo) {
+		return response, nil
+	}
+
 	// Step 2: Get all videos for this trick
 	videos, err := s.videoRepo.FindByTrickID(ctx, id)
 	if err != nil {
@@ -93,40 +448,482 @@ func (s *TrickService) GetFullDetailsTrickById(ctx context.Context, id string) (
 		return nil, fmt.Errorf("failed to get videos for trick: %w", err)
 	}
 
-	// Step 3: Convert videos to response DTOs
-	videoResponses := make([]models.VideoResponse, 0, len(videos))
+	// Step 3: List the playable videos and pick the featured one
+	// Videos come featured first, then newest. A featured video that is known to be
+	// down is skipped, and the next playable video takes its place.
 	var featuredVideo *models.VideoResponse
+	featuredDown := false
+	playable := make([]models.VideoResponse, 0, len(videos))
 
 	for _, video := range videos {
-		vr := video.ToResponse()
-		videoResponses = append(videoResponses, vr)
-
-		// Track the featured video for convenience
-		if video.IsFeatured {
+		if video.Availability == models.VideoUnavailable {
+			featuredDown = featuredDown || video.IsFeatured
+			continue
+		}
+		playable = append(playable, video.ToResponse(models.PublicView))
+		if featuredVideo == nil && (video.IsFeatured || featuredDown) {
+			vr := playable[len(playable)-1]
 			featuredVideo = &vr
-			break
 		}
 	}
 
-	// Step 4: Build the combined response
-	response := &models.TrickFullDetailsResponse{
-		TrickDetailResponse: trick.ToDetailResponse(),
-		FeaturedVideo:       featuredVideo,
+	response.FeaturedVideo = featuredVideo
+	response.Videos = playable
+	videoCount := len(playable)
+	response.VideoCount = &videoCount
+	return response, nil
+}
+
+// dictionaryCompleteness scores how much of a dictionary entry is filled in (0-100)
+// Each section counts equally. Videos are left out on purpose: whether they're
+// loaded depends on ?include=, and the score must not.
+func dictionaryCompleteness(trick *models.Trick, mistakes []models.TrickMistake) int {
+	sections := []bool{
+		trick.Description != nil && *trick.Description != "",
+		trick.Difficulty != nil,
+		trick.ExecutionNotes != nil && *trick.ExecutionNotes != "",
+		trick.TakeoffStanceID != nil,
+		trick.LandingStanceID != nil,
+		len(mistakes) > 0,
 	}
 
-	return response, nil
+	filled := 0
+	for _, ok := range sections {
+		if ok {
+			filled++
+		}
+	}
+	return filled * 100 / len(sections)
 }
 
 // GetSimpleTricksList retrieves a minimal list for dropdown menus
 func (s *TrickService) GetSimpleTricksList(ctx context.Context) ([]models.TrickSimpleResponse, error) {
 	// Call repository method
-	tricks, err := s.trickRepo.FindSimpleList(ctx)
+	tricks, err := s.trickRepo.FindSimpleList(ctx, s.NewTricksSince(0))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tricks list: %w", err)
 	}
 	return tricks, nil
 }
 
+// ListTricks returns one page of the trick catalog, ordered by name
+// cursor is empty for the first page, otherwise the NextCursor of the previous page
+// includes comes from ParseTrickListIncludes; featured videos are loaded for the whole page in one query,
+// video_flags come with the page itself, and so do the video counts when withCounts is set.
+func (s *TrickService) ListTricks(ctx context.Context, cursor string, limit int, includes []string, withCounts bool) (*models.TrickPage, error) {
+	var after *repository.TrickPageKey
+	if cursor != "" {
+		key, err := decodeTrickCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = key
+	}
+
+	// Fetch one extra row - if it comes back, there is another page
+	tricks, err := s.trickRepo.FindPage(ctx, after, limit+1, hasInclude(includes, IncludeVideoFlags), withCounts, s.NewTricksSince(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trick page: %w", err)
+	}
+
+	page := &models.TrickPage{Tricks: tricks}
+	if len(tricks) > limit {
+		page.Tricks = tricks[:limit]
+		last := page.Tricks[limit-1]
+		next := encodeTrickCursor(repository.TrickPageKey{Name: last.Name, Slug: last.ID})
+		page.NextCursor = &next
+	}
+
+	if hasInclude(includes, IncludeFeaturedVideo) && len(page.Tricks) > 0 {
+		slugs := make([]string, len(page.Tricks))
+		for i, trick := range page.Tricks {
+			slugs[i] = trick.ID
+		}
+
+		featured, err := s.videoRepo.GetFeaturedForTrickIDs(ctx, slugs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get featured videos for trick page: %w", err)
+		}
+		for i := range page.Tricks {
+			if video, ok := featured[page.Tricks[i].ID]; ok {
+				response := video.ToResponse(responseView(ctx))
+				page.Tricks[i].FeaturedVideo = &response
+			}
+		}
+	}
+	return page, nil
+}
+
+// GetTricksList returns every live trick matching the filter
+// Difficulty bounds are inclusive; tricks without a difficulty are left out whenever a bound is set.
+// A filter that matches nothing (e.g. an unknown stance ID) is an empty list, not an error.
+func (s *TrickService) GetTricksList(ctx context.Context, filter models.TrickListFilter) ([]models.Trick, error) {
+	filters, err := trickListFilters(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tricks, err := s.trickRepo.FindByFilters(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get filtered tricks: %w", err)
+	}
+
+	sortTricksByName(tricks)
+	return tricks, nil
+}
+
+// GetDifficultyHistogram counts the tricks matching filter at each difficulty
+// Same filter rules as GetTricksList, so the counts match what the list would show.
+func (s *TrickService) GetDifficultyHistogram(ctx context.Context, filter models.TrickListFilter) ([]models.DifficultyBucket, error) {
+	filters, err := trickListFilters(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := s.trickRepo.DifficultyHistogram(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get difficulty histogram: %w", err)
+	}
+	return buckets, nil
+}
+
+// trickListFilters checks a list filter and converts it for the repository
+// Returns ErrInvalidDifficultyRange if min_difficulty > max_difficulty.
+func trickListFilters(filter models.TrickListFilter) (repository.TrickFilters, error) {
+	if filter.MinDifficulty != nil && filter.MaxDifficulty != nil && *filter.MinDifficulty > *filter.MaxDifficulty {
+		return repository.TrickFilters{}, ErrInvalidDifficultyRange
+	}
+
+	return repository.TrickFilters{
+		MinDifficulty:   filter.MinDifficulty,
+		MaxDifficulty:   filter.MaxDifficulty,
+		TakeoffStanceID: filter.TakeoffStanceID,
+		LandingStanceID: filter.LandingStanceID,
+		CategoryIDs:     filter.CategoryIDs,
+		Tags:            filter.Tags,
+	}, nil
+}
+
+// ExportTricks passes every live trick matching the filter to fn, by name, as it is read
+// Same filter rules as GetTricksList. An error from fn (e.g. the client went away)
+// stops the export and is returned wrapped.
+func (s *TrickService) ExportTricks(ctx context.Context, filter models.TrickListFilter, fn func(models.TrickExportRow) error) error {
+	filters, err := trickListFilters(filter)
+	if err != nil {
+		return err
+	}
+
+	err = s.trickRepo.StreamExport(ctx, filters, fn)
+	if err != nil {
+		return fmt.Errorf("failed to export tricks: %w", err)
+	}
+	return nil
+}
+
+// GetTricksBySlugs looks up several tricks in one query
+// Results keep the requested order; repeated slugs are returned once, and
+// slugs that don't exist are listed in Missing instead of failing the batch.
+func (s *TrickService) GetTricksBySlugs(ctx context.Context, slugs []string) (*models.TrickBatchResponse, error) {
+	unique := make([]string, 0, len(slugs))
+	seen := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		if !seen[slug] {
+			seen[slug] = true
+			unique = append(unique, slug)
+		}
+	}
+	if len(unique) > MaxBatchSlugs {
+		return nil, ErrTooManySlugs
+	}
+
+	tricks, err := s.trickRepo.GetBySlugs(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tricks by slugs: %w", err)
+	}
+
+	bySlug := make(map[string]models.Trick, len(tricks))
+	for _, trick := range tricks {
+		bySlug[trick.ID] = trick
+	}
+
+	response := &models.TrickBatchResponse{
+		Tricks:  make([]models.TrickDetailResponse, 0, len(tricks)),
+		Missing: make([]string, 0),
+	}
+	for _, slug := range unique {
+		trick, ok := bySlug[slug]
+		if !ok {
+			response.Missing = append(response.Missing, slug)
+			continue
+		}
+		response.Tricks = append(response.Tricks, s.detailResponse(&trick, responseView(ctx)))
+	}
+	response.Count = len(response.Tricks)
+
+	return response, nil
+}
+
+// ParseDictionaryIncludes turns an include= value ("a,b") into a sorted, de-duplicated set
+// Empty means every include. Returns ErrUnknownInclude for names we don't offer.
+func ParseDictionaryIncludes(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return dictionaryIncludes, nil
+	}
+	return parseIncludes(raw, dictionaryIncludes)
+}
+
+// ParseTrickListIncludes is ParseDictionaryIncludes for GET /tricks
+// Empty means no includes - the list stays as small as it always was.
+func ParseTrickListIncludes(raw string) ([]string, error) {
+	return parseIncludes(raw, trickListIncludes)
+}
+
+// parseIncludes splits raw into a sorted, de-duplicated subset of offered
+// Aliases ("videos") are resolved to their include name first.
+func parseIncludes(raw string, offered []string) ([]string, error) {
+	includes := make([]string, 0, len(offered))
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if alias, ok := includeAliases[name]; ok {
+			name = alias
+		}
+		if name == "" || hasInclude(includes, name) {
+			continue
+		}
+		if !hasInclude(offered, name) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownInclude, name)
+		}
+		includes = append(includes, name)
+	}
+
+	// Sorted so "a,b" and "b,a" share a cache entry
+	sort.Strings(includes)
+	return includes, nil
+}
+
+// hasInclude reports whether name is in includes
+func hasInclude(includes []string, name string) bool {
+	for _, include := range includes {
+		if include == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortTricksByName orders FindByFilters results for display
+// FindByFilters orders for combo generation (weight, then random) - a list wants names
+func sortTricksByName(tricks []models.Trick) {
+	sort.Slice(tricks, func(i, j int) bool {
+		if tricks[i].Name != tricks[j].Name {
+			return tricks[i].Name < tricks[j].Name
+		}
+		return tricks[i].ID < tricks[j].ID
+	})
+}
+
+// trickCursor is the JSON inside an opaque page cursor
+type trickCursor struct {
+	Name string `json:"n"`
+	Slug string `json:"s"`
+}
+
+// encodeTrickCursor turns a page key into an opaque, URL-safe cursor
+func encodeTrickCursor(key repository.TrickPageKey) string {
+	data, _ := json.Marshal(trickCursor{Name: key.Name, Slug: key.Slug})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTrickCursor reverses encodeTrickCursor
+// Returns ErrInvalidCursor for anything that doesn't decode to a full key
+func decodeTrickCursor(cursor string) (*repository.TrickPageKey, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c trickCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Slug == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &repository.TrickPageKey{Name: c.Name, Slug: c.Slug}, nil
+}
+
+// GetPrerequisites returns the tricks to learn before this one (direct prerequisites, by name)
+func (s *TrickService) GetPrerequisites(ctx context.Context, id string) ([]models.TrickSimpleResponse, error) {
+	return withTrickID(ctx, s, id, func(slug string) ([]models.TrickSimpleResponse, error) {
+		tricks, err := s.prereqRepo.FindPrerequisites(ctx, slug)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrTrickNotFound
+			}
+			return nil, fmt.Errorf("failed to get prerequisites: %w", err)
+		}
+		return tricks, nil
+	})
+}
+
+// GetUnlocks returns the tricks that list this one as a direct prerequisite, by name
+func (s *TrickService) GetUnlocks(ctx context.Context, id string) ([]models.TrickSimpleResponse, error) {
+	return withTrickID(ctx, s, id, func(slug string) ([]models.TrickSimpleResponse, error) {
+		tricks, err := s.prereqRepo.FindUnlocks(ctx, slug)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrTrickNotFound
+			}
+			return nil, fmt.Errorf("failed to get unlocked tricks: %w", err)
+		}
+		return tricks, nil
+	})
+}
+
+// Trick stats settings
+const (
+	// trickStatsTTL is how long GetTrickStats reuses a result - apps call it on every start
+	trickStatsTTL = 5 * time.Minute
+
+	// trickStatsAddedWindow is the "recently added" window of the stats
+	trickStatsAddedWindow = 30 * 24 * time.Hour
+)
+
+// GetTrickStats returns catalog-wide counts and averages (GET /tricks/stats)
+// Cached in memory for trickStatsTTL, so writes show up within a few minutes.
+// Callers must not modify the result - it is shared between requests.
+func (s *TrickService) GetTrickStats(ctx context.Context) (*models.TrickStatsResponse, error) {
+	s.statsMu.Lock()
+	cached := s.stats
+	s.statsMu.Unlock()
+	if cached != nil && time.Since(cached.GeneratedAt) < trickStatsTTL {
+		return cached, nil
+	}
+
+	now := time.Now().UTC()
+	stats, err := s.trickRepo.GetStats(ctx, now.Add(-trickStatsAddedWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trick stats: %w", err)
+	}
+	stats.GeneratedAt = now
+
+	s.statsMu.Lock()
+	s.stats = stats
+	s.statsMu.Unlock()
+	return stats, nil
+}
+
+// GetTags returns every tag with its live-trick count, by name
+func (s *TrickService) GetTags(ctx context.Context) ([]models.TagResponse, error) {
+	tags, err := s.tagRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+// NewTricksSince returns the cutoff of a "new" window of days (0 = the configured window)
+// The cutoff is midnight UTC days ago, so what counts as new only changes once a
+// day - list ETags include it (see TrickHandler.listNotModified) and stay valid
+// in between. A trick created today is new for days to days+1 days.
+func (s *TrickService) NewTricksSince(days int) time.Time {
+	if days == 0 {
+		days = s.newTrickDays
+	}
+	return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -days)
+}
+
+// GetNewTricks returns up to limit live tricks created at or after since, newest first
+func (s *TrickService) GetNewTricks(ctx context.Context, since time.Time, limit int) ([]models.NewTrickResponse, error) {
+	tricks, err := s.trickRepo.FindCreatedSince(ctx, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get new tricks: %w", err)
+	}
+	return tricks, nil
+}
+
+// ParsePopularWindow parses a ?window= of whole days ("7d"; a bare "7" is accepted too)
+func ParsePopularWindow(raw string) (int, error) {
+	days, err := strconv.Atoi(strings.TrimSuffix(raw, "d"))
+	if err != nil || days < 1 || days > MaxPopularWindowDays {
+		return 0, ErrInvalidPopularWindow
+	}
+	return days, nil
+}
+
+// GetPopularTricks returns up to limit live tricks with the most views in the last days days
+// Views are bucketed per UTC day, so the window is today plus the days-1 before it;
+// since is where it starts.
+func (s *TrickService) GetPopularTricks(ctx context.Context, days, limit int) ([]models.PopularTrickResponse, time.Time, error) {
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	tricks, err := s.trickRepo.FindPopular(ctx, since, limit)
+	if err != nil {
+		return nil, since, fmt.Errorf("failed to get popular tricks: %w", err)
+	}
+	return tricks, since, nil
+}
+
+// GetTrickSlugs retrieves slugs and update times of all live tricks (for sitemaps)
+func (s *TrickService) GetTrickSlugs(ctx context.Context) ([]models.TrickSlugResponse, error) {
+	slugs, err := s.trickRepo.FindSlugs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trick slugs: %w", err)
+	}
+	return slugs, nil
+}
+
+// searchCandidateLimit bounds how many rows ranking looks at per search
+const searchCandidateLimit = 200
+
+// MaxSearchLimit caps how many results one search can return
+const MaxSearchLimit = 100
+
+// MinSearchQueryLength is the shortest query worth searching for
+const MinSearchQueryLength = 2
+
+// ErrSearchQueryTooShort indicates a search query below MinSearchQueryLength
+var ErrSearchQueryTooShort = errors.New("search query must be at least 2 characters")
+
+// SearchTricks finds tricks by name, alias, description or execution notes, best matches first
+// See search_ranking.go for the ranking tiers. limit is capped at MaxSearchLimit.
+// With fuzzy search on, typos within the similarity threshold match too and
+// every result carries its similarity score.
+func (s *TrickService) SearchTricks(ctx context.Context, query string, limit int) ([]models.TrickSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < MinSearchQueryLength {
+		return nil, ErrSearchQueryTooShort
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	candidates, err := s.trickRepo.Search(ctx, query, searchCandidateLimit, s.searchSimilarity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tricks: %w", err)
+	}
+
+	aliases := make(map[string][]string, len(candidates))
+	for _, trick := range candidates {
+		aliases[trick.ID] = trick.Aliases
+	}
+
+	results := rankSearchResults(query, candidates, aliases, s.searchSimilarity)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// autocompleteLimit is how many suggestions type-ahead returns
+const autocompleteLimit = 10
+
+// AutocompleteTricks suggests tricks whose name starts with prefix
+// Unlike SearchTricks this is prefix-only with no ranking pass, so it stays fast
+func (s *TrickService) AutocompleteTricks(ctx context.Context, prefix string) ([]models.TrickAutocompleteResponse, error) {
+	tricks, err := s.trickRepo.FindByNamePrefix(ctx, prefix, autocompleteLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to autocomplete tricks: %w", err)
+	}
+	return tricks, nil
+}
+
 // GetLastModified returns the latest modification timestamp across all tricks
 // Used for efficient ETag generation on list endpoints
 func (s *TrickService) GetLastModified(ctx context.Context) (int64, error) {
@@ -137,15 +934,151 @@ func (s *TrickService) GetLastModified(ctx context.Context) (int64, error) {
 	return timestamp, nil
 }
 
+// GetTrickChanges returns what changed in the catalog since a Unix timestamp
+// The comparison is inclusive (timestamps are whole seconds), so a client may
+// receive a trick it already has again - harmless for an upsert, unlike a miss.
+func (s *TrickService) GetTrickChanges(ctx context.Context, since int64) (*models.TrickChangesResponse, error) {
+	changes, err := s.trickRepo.FindChangesSince(ctx, time.Unix(since, 0), true, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trick changes: %w", err)
+	}
+
+	response := &models.TrickChangesResponse{
+		Tricks:       make([]models.TrickDetailResponse, 0, len(changes.Tricks)),
+		Count:        len(changes.Tricks),
+		DeletedSlugs: changes.DeletedSlugs,
+		// Rounded down: the next sync's ">= since" then covers the rest of this second
+		ServerTime: changes.ServerTime.Unix(),
+	}
+	for _, trick := range changes.Tricks {
+		response.Tricks = append(response.Tricks, s.detailResponse(&trick, responseView(ctx)))
+	}
+	return response, nil
+}
+
+// ErrFullSnapshotRequired indicates a snapshot version a delta can't be computed from
+// (malformed, from the future, or older than the tombstones go back)
+var ErrFullSnapshotRequired = errors.New("snapshot version is unknown or too old for a delta")
+
+// snapshotVersion is the data behind a catalog snapshot version cursor
+// At is the database clock (Unix seconds) when that snapshot was read.
+type snapshotVersion struct {
+	V  int   `json:"v"`
+	At int64 `json:"at"`
+}
+
+// snapshotVersionFormat is bumped if the meaning of a version changes - older
+// versions then just ask their clients for a full snapshot
+const snapshotVersionFormat = 1
+
+// encodeSnapshotVersion makes the opaque version cursor for a snapshot read at at
+func encodeSnapshotVersion(at time.Time) string {
+	data, _ := json.Marshal(snapshotVersion{V: snapshotVersionFormat, At: at.Unix()})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSnapshotVersion reverses encodeSnapshotVersion
+// Returns ErrFullSnapshotRequired for anything it didn't make.
+func decodeSnapshotVersion(version string) (time.Time, error) {
+	data, err := base64.RawURLEncoding.DecodeString(version)
+	if err != nil {
+		return time.Time{}, ErrFullSnapshotRequired
+	}
+
+	var v snapshotVersion
+	if err := json.Unmarshal(data, &v); err != nil || v.V != snapshotVersionFormat || v.At <= 0 {
+		return time.Time{}, ErrFullSnapshotRequired
+	}
+	return time.Unix(v.At, 0), nil
+}
+
+// GetCatalogDelta returns the tricks, categories and stances changed since a
+// snapshot version, plus the version to send next time
+// An empty sinceVersion returns the full catalog (the "full snapshot"). A version
+// older than the tombstone retention is ErrFullSnapshotRequired: deletions from
+// before then may have been purged, and the client would never hear about them.
+func (s *TrickService) GetCatalogDelta(ctx context.Context, sinceVersion string) (*models.CatalogDeltaResponse, error) {
+	since := time.Unix(0, 0)
+	if sinceVersion != "" {
+		var err error
+		since, err = decodeSnapshotVersion(sinceVersion)
+		if err != nil {
+			return nil, err
+		}
+		// A minute of slack for clock skew between this instance and the database
+		now := time.Now()
+		if since.After(now.Add(time.Minute)) || since.Before(now.Add(-s.tombstoneRetention)) {
+			return nil, ErrFullSnapshotRequired
+		}
+	}
+
+	changes, err := s.trickRepo.FindChangesSince(ctx, since, true, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog delta: %w", err)
+	}
+
+	response := &models.CatalogDeltaResponse{
+		// Rounded down like GetTrickChanges: the next ">= since" covers the rest of this second
+		Version:      encodeSnapshotVersion(changes.ServerTime),
+		Full:         sinceVersion == "",
+		Tricks:       make([]models.TrickDetailResponse, 0, len(changes.Tricks)),
+		DeletedSlugs: changes.DeletedSlugs,
+		Categories:   changes.Categories,
+		Stances:      changes.Stances,
+	}
+	for _, trick := range changes.Tricks {
+		response.Tricks = append(response.Tricks, s.detailResponse(&trick, models.PublicView))
+	}
+	return response, nil
+}
+
+// StreamCatalogSnapshot is the full snapshot of GetCatalogDelta, a trick at a time
+// start gets the response without its tricks (version, deleted slugs, categories,
+// stances) before any trick is read; fn then gets each trick, by name. The version
+// is read first, so a trick that changes mid-stream also comes in the next delta.
+func (s *TrickService) StreamCatalogSnapshot(ctx context.Context, start func(*models.CatalogDeltaResponse) error, fn func(models.TrickDetailResponse) error) error {
+	changes, err := s.trickRepo.FindChangesSince(ctx, time.Unix(0, 0), false, true)
+	if err != nil {
+		return fmt.Errorf("failed to get catalog snapshot: %w", err)
+	}
+
+	err = start(&models.CatalogDeltaResponse{
+		Version:      encodeSnapshotVersion(changes.ServerTime),
+		Full:         true,
+		DeletedSlugs: changes.DeletedSlugs,
+		Categories:   changes.Categories,
+		Stances:      changes.Stances,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.trickRepo.ForEachTrick(ctx, func(trick *models.Trick) error {
+		return fn(s.detailResponse(trick, models.PublicView))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream catalog snapshot: %w", err)
+	}
+	return nil
+}
+
 // GetLastModifiedByID returns the modification timestamp for a specific trick
 // Used for efficient ETag generation on individual trick endpoints
 func (s *TrickService) GetLastModifiedByID(ctx context.Context, id string) (int64, error) {
-	timestamp, err := s.trickRepo.GetLastModifiedByID(ctx, id)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return 0, ErrTrickNotFound
+	return withTrickID(ctx, s, id, func(slug string) (int64, error) {
+		timestamp, err := s.trickRepo.GetLastModifiedByID(ctx, slug)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return 0, ErrTrickNotFound
+			}
+			return 0, fmt.Errorf("failed to get last modified timestamp for trick: %w", err)
 		}
-		return 0, fmt.Errorf("failed to get last modified timestamp for trick: %w", err)
-	}
-	return timestamp, nil
+		return timestamp, nil
+	})
+}
+
+// RecordView counts one view of a trick
+// Views are only held in memory here - the ViewCounter flushes them in the background
+func (s *TrickService) RecordView(id string) {
+	s.viewCounter.Record(id)
 }
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+	"tricking-api/internal/viewer"
+)
+
+// fakePageTrickRepo is an in-memory catalog that pages the way FindPage does:
+// ordered by (name, slug), starting strictly after the given key
+type fakePageTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	tricks []models.TrickSimpleResponse
+}
+
+func newFakePageTrickRepo() *fakePageTrickRepo {
+	repo := &fakePageTrickRepo{}
+	for _, trick := range fixtures.CatalogTricks() {
+		repo.insert(trick.Slug, trick.Name)
+	}
+	return repo
+}
+
+func (r *fakePageTrickRepo) insert(slug, name string) {
+	r.tricks = append(r.tricks, models.TrickSimpleResponse{ID: slug, Name: name})
+	sort.Slice(r.tricks, func(i, j int) bool {
+		if r.tricks[i].Name != r.tricks[j].Name {
+			return r.tricks[i].Name < r.tricks[j].Name
+		}
+		return r.tricks[i].ID < r.tricks[j].ID
+	})
+}
+
+func (r *fakePageTrickRepo) FindPage(ctx context.Context, after *repository.TrickPageKey, limit int, withVideoFlags, withVideoCounts bool, newSince time.Time) ([]models.TrickSimpleResponse, error) {
+	page := []models.TrickSimpleResponse{}
+	for _, trick := range r.tricks {
+		if after != nil && (trick.Name < after.Name || (trick.Name == after.Name && trick.ID <= after.Slug)) {
+			continue
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, trick)
+	}
+	return page, nil
+}
+
+func newPageTrickService(repo repository.TrickRepositoryInterface) *TrickService {
+	return NewTrickService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, 7, nil, 0, time.Hour, 0, false)
+}
+
+func TestListTricksPagesWithoutGapsOrDuplicates(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		// insert runs once, after the first page has been read
+		insertSlug, insertName string
+		wantInserted           bool // whether the inserted trick must show up in a later page
+	}{
+		{name: "no inserts", limit: 7},
+		{name: "page size one", limit: 1},
+		{name: "single page", limit: 100},
+		{name: "insert before the cursor", limit: 5, insertSlug: "aaa-kick", insertName: "AAA Kick"},
+		{name: "insert after the cursor", limit: 5, insertSlug: "zzz-flip", insertName: "Zzz Flip", wantInserted: true},
+		{name: "insert with the cursor's name", limit: 5, insertSlug: "zz-btwist-round", insertName: "B-Twist Round", wantInserted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakePageTrickRepo()
+			service := newPageTrickService(repo)
+			original := len(repo.tricks)
+
+			seen := map[string]int{}
+			cursor, pages := "", 0
+			for {
+				page, err := service.ListTricks(context.Background(), cursor, tt.limit, nil, false)
+				if err != nil {
+					t.Fatalf("ListTricks() error = %v", err)
+				}
+				pages++
+				if len(page.Tricks) > tt.limit {
+					t.Fatalf("page has %d tricks, limit %d", len(page.Tricks), tt.limit)
+				}
+				for _, trick := range page.Tricks {
+					seen[trick.ID]++
+				}
+
+				if pages == 1 && tt.insertSlug != "" {
+					repo.insert(tt.insertSlug, tt.insertName)
+				}
+
+				if page.NextCursor == nil {
+					break
+				}
+				if len(page.Tricks) != tt.limit {
+					t.Fatalf("short page (%d tricks) has a next cursor", len(page.Tricks))
+				}
+				cursor = *page.NextCursor
+				if pages > 100 {
+					t.Fatal("pagination never ended")
+				}
+			}
+
+			for _, trick := range fixtures.CatalogTricks() {
+				if seen[trick.Slug] != 1 {
+					t.Errorf("trick %s seen %d times, want once", trick.Slug, seen[trick.Slug])
+				}
+			}
+			if tt.insertSlug != "" && (seen[tt.insertSlug] == 1) != tt.wantInserted {
+				t.Errorf("inserted trick seen %d times, want inserted = %v", seen[tt.insertSlug], tt.wantInserted)
+			}
+			if total := len(seen); total < original {
+				t.Errorf("saw %d tricks, want at least %d", total, original)
+			}
+		})
+	}
+}
+
+func TestListTricksLastPageHasNoCursor(t *testing.T) {
+	repo := newFakePageTrickRepo()
+	service := newPageTrickService(repo)
+
+	// Exactly one full page: the extra row FindPage is asked for doesn't exist
+	page, err := service.ListTricks(context.Background(), "", len(repo.tricks), nil, false)
+	if err != nil {
+		t.Fatalf("ListTricks() error = %v", err)
+	}
+	if page.NextCursor != nil {
+		t.Errorf("NextCursor = %q, want nil on the last page", *page.NextCursor)
+	}
+	if len(page.Tricks) != len(repo.tricks) {
+		t.Errorf("got %d tricks, want %d", len(page.Tricks), len(repo.tricks))
+	}
+}
+
+func TestDecodeTrickCursor(t *testing.T) {
+	valid := encodeTrickCursor(repository.TrickPageKey{Name: "Double Cork", Slug: "double-cork"})
+
+	tests := []struct {
+		name    string
+		cursor  string
+		want    *repository.TrickPageKey
+		wantErr bool
+	}{
+		{name: "round trip", cursor: valid, want: &repository.TrickPageKey{Name: "Double Cork", Slug: "double-cork"}},
+		{name: "not base64", cursor: "!!!", wantErr: true},
+		{name: "padded base64", cursor: valid + "==", wantErr: true},
+		{name: "not JSON", cursor: base64.RawURLEncoding.EncodeToString([]byte("cork")), wantErr: true},
+		{name: "missing slug", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"n":"Cork"}`)), wantErr: true},
+		{name: "JSON array", cursor: base64.RawURLEncoding.EncodeToString([]byte(`["Cork","cork"]`)), wantErr: true},
+		{name: "empty name is allowed", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"n":"","s":"x"}`)), want: &repository.TrickPageKey{Slug: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeTrickCursor(tt.cursor)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Errorf("decodeTrickCursor() = %v, %v; want ErrInvalidCursor", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeTrickCursor() error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("decodeTrickCursor() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestListTricksInvalidCursor(t *testing.T) {
+	service := newPageTrickService(newFakePageTrickRepo())
+	if _, err := service.ListTricks(context.Background(), "not-a-cursor!", 10, nil, false); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ListTricks() error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestTrickDetailCreatorByRole(t *testing.T) {
+	creator := fixtures.OwnerID
+	trick := fixtures.Trick().WithSlug("cork").WithName("Cork").Build()
+	trick.CreatedBy = &creator
+
+	tests := []struct {
+		role        string
+		wantCreator bool
+	}{
+		{role: "admin", wantCreator: true},
+		{role: "moderator", wantCreator: true},
+		{role: "user"},
+		{role: ""}, // Public catalog routes have no role
+	}
+
+	for _, tt := range tests {
+		t.Run("role "+tt.role, func(t *testing.T) {
+			service := NewTrickService(&fakeTranslationTrickRepo{trick: trick}, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, 7, NewDifficultyBands(nil), 0, 0, 0, false)
+
+			ctx := context.Background()
+			if tt.role != "" {
+				ctx = viewer.WithRole(ctx, tt.role)
+			}
+			detail, _, err := service.GetSimpleTrickById(ctx, "cork", "")
+			if err != nil {
+				t.Fatalf("GetSimpleTrickById() error = %v", err)
+			}
+
+			switch {
+			case tt.wantCreator && (detail.CreatedBy == nil || *detail.CreatedBy != creator):
+				t.Errorf("created_by = %v, want %s", detail.CreatedBy, creator)
+			case !tt.wantCreator && detail.CreatedBy != nil:
+				t.Errorf("created_by = %s, want it hidden", detail.CreatedBy)
+			}
+		})
+	}
+}
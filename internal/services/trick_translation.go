@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/sanitize"
+)
+
+// =============================================================================
+// TRICK TRANSLATIONS
+// =============================================================================
+// Tricks are written in English (BaseLocale). A translation swaps in
+// another language's name, description and execution notes on trick details and
+// dictionaries - field by field, so a partly translated trick shows English for
+// the rest. Locales are primary language tags only ("es", not "es-MX"), matching
+// how Accept-Language is read for error messages.
+
+// BaseLocale is the language of the tricks table itself - it has no translations
+const BaseLocale = "en"
+
+// MaxTranslationNameLength caps a translated trick name (in characters)
+const MaxTranslationNameLength = 100
+
+// Translation errors
+var (
+	ErrInvalidLocale          = errors.New("locale must be a two-letter language code other than en")
+	ErrInvalidTranslationName = errors.New("translated name must be 1-100 characters")
+	ErrEmptyTranslation       = errors.New("translation has no name, description or execution notes")
+	ErrTranslationNotFound    = errors.New("translation not found")
+)
+
+// translationLocale is the shape of a locale: an ISO 639-1 language code
+var translationLocale = regexp.MustCompile(`^[a-z]{2}$`)
+
+// ValidContentLocale reports whether locale can be asked for (?locale=)
+// BaseLocale is accepted here - it just means "untranslated".
+func ValidContentLocale(locale string) bool {
+	return translationLocale.MatchString(locale)
+}
+
+// translate applies the trick's translation into locale, if it has one
+// BaseLocale (or none) needs no lookup: the trick's own fields are English.
+func (s *TrickService) translate(ctx context.Context, slug, locale string, detail *models.TrickDetailResponse) error {
+	if locale == "" || locale == BaseLocale {
+		return nil
+	}
+
+	translation, err := s.translationRepo.Find(ctx, slug, locale)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get translation: %w", err)
+	}
+	translation.Apply(detail)
+	return nil
+}
+
+// GetTranslations lists a trick's translations (admin view)
+func (s *AdminService) GetTranslations(ctx context.Context, trickSlug string) ([]models.TrickTranslation, error) {
+	translations, err := s.translationRepo.FindByTrickSlug(ctx, trickSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translations: %w", err)
+	}
+	return translations, nil
+}
+
+// SaveTranslation creates or replaces a trick's translation into locale
+// Text is sanitized like every write path; empty fields count as untranslated.
+func (s *AdminService) SaveTranslation(ctx context.Context, trickSlug, locale string, req models.TrickTranslationRequest) (*models.TrickTranslation, error) {
+	if !translationLocale.MatchString(locale) || locale == BaseLocale {
+		return nil, ErrInvalidLocale
+	}
+
+	translation := &models.TrickTranslation{
+		Locale:         locale,
+		Name:           sanitize.OptionalText(req.Name),
+		Description:    sanitize.OptionalText(req.Description),
+		ExecutionNotes: sanitize.OptionalText(req.ExecutionNotes),
+	}
+	if translation.Name != nil && utf8.RuneCountInString(*translation.Name) > MaxTranslationNameLength {
+		return nil, ErrInvalidTranslationName
+	}
+	if translation.Name == nil && translation.Description == nil && translation.ExecutionNotes == nil {
+		return nil, ErrEmptyTranslation
+	}
+
+	if err := s.translationRepo.Upsert(ctx, trickSlug, translation); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrickNotFound
+		}
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return translation, nil
+}
+
+// DeleteTranslation removes a trick's translation into locale
+func (s *AdminService) DeleteTranslation(ctx context.Context, trickSlug, locale string) error {
+	if err := s.translationRepo.Delete(ctx, trickSlug, locale); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTranslationNotFound
+		}
+		return fmt.Errorf("failed to delete translation: %w", err)
+	}
+	s.dictionaryCache.InvalidateTrick(trickSlug)
+	return nil
+}
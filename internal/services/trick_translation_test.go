@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeTranslationTrickRepo serves one trick by slug
+type fakeTranslationTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	trick models.Trick
+}
+
+func (r *fakeTranslationTrickRepo) GetByID(ctx context.Context, slug string) (*models.Trick, error) {
+	if slug != r.trick.Slug {
+		return nil, repository.ErrNotFound
+	}
+	trick := r.trick
+	return &trick, nil
+}
+
+// fakeTranslationRepo stores translations by locale, the way the table does:
+// a nil field is a NULL column
+type fakeTranslationRepo struct {
+	repository.TranslationRepositoryInterface
+
+	slug         string
+	translations map[string]models.TrickTranslation
+	err          error
+	finds        int
+}
+
+func (r *fakeTranslationRepo) Find(ctx context.Context, trickSlug, locale string) (*models.TrickTranslation, error) {
+	r.finds++
+	if r.err != nil {
+		return nil, r.err
+	}
+	translation, ok := r.translations[locale]
+	if !ok || trickSlug != r.slug {
+		return nil, repository.ErrNotFound
+	}
+	return &translation, nil
+}
+
+func (r *fakeTranslationRepo) Upsert(ctx context.Context, trickSlug string, translation *models.TrickTranslation) error {
+	if trickSlug != r.slug {
+		return repository.ErrNotFound
+	}
+	translation.UpdatedAt = fixtures.Epoch
+	r.translations[translation.Locale] = *translation
+	return nil
+}
+
+func translationFixtures() (models.Trick, *fakeTranslationRepo) {
+	trick := fixtures.Trick().WithSlug("backflip").WithName("Backflip").
+		WithDescription("A backward flip", "Jump up, not back").Build()
+	repo := &fakeTranslationRepo{slug: "backflip", translations: map[string]models.TrickTranslation{
+		"es": {
+			Locale: "es", Name: fixtures.Ptr("Mortal atrás"),
+			Description: fixtures.Ptr("Un mortal hacia atrás"), ExecutionNotes: fixtures.Ptr("Salta hacia arriba"),
+		},
+		"fr": {Locale: "fr", Name: fixtures.Ptr("Salto arrière")}, // Name only
+	}}
+	return trick, repo
+}
+
+func TestGetTrickTranslationFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		locale      string
+		repoErr     error
+		wantLocale  string
+		wantName    string
+		wantDesc    string
+		wantNotes   string
+		wantLookups int
+		wantErr     bool
+	}{
+		{
+			name: "no locale", wantName: "Backflip", wantDesc: "A backward flip", wantNotes: "Jump up, not back",
+		},
+		{
+			name: "base locale needs no lookup", locale: BaseLocale,
+			wantName: "Backflip", wantDesc: "A backward flip", wantNotes: "Jump up, not back",
+		},
+		{
+			name: "full translation", locale: "es", wantLocale: "es", wantLookups: 1,
+			wantName: "Mortal atrás", wantDesc: "Un mortal hacia atrás", wantNotes: "Salta hacia arriba",
+		},
+		{
+			name: "partial translation falls back per field", locale: "fr", wantLocale: "fr", wantLookups: 1,
+			wantName: "Salto arrière", wantDesc: "A backward flip", wantNotes: "Jump up, not back",
+		},
+		{
+			name: "untranslated locale", locale: "de", wantLookups: 1,
+			wantName: "Backflip", wantDesc: "A backward flip", wantNotes: "Jump up, not back",
+		},
+		{
+			name: "repository error", locale: "es", repoErr: errors.New("db down"), wantLookups: 1, wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trick, translations := translationFixtures()
+			translations.err = tt.repoErr
+			service := NewTrickService(&fakeTranslationTrickRepo{trick: trick}, nil, nil, nil, nil, nil, translations, nil,
+				nil, nil, 7, NewDifficultyBands(nil), 0, 0, 0, false)
+
+			detail, _, err := service.GetSimpleTrickById(context.Background(), "backflip", tt.locale)
+			if translations.finds != tt.wantLookups {
+				t.Errorf("translation lookups = %d, want %d", translations.finds, tt.wantLookups)
+			}
+			if tt.wantErr {
+				if err == nil || !errors.Is(err, tt.repoErr) {
+					t.Fatalf("error = %v, want %v", err, tt.repoErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSimpleTrickById() error = %v", err)
+			}
+
+			if detail.Locale != tt.wantLocale || detail.Name != tt.wantName ||
+				detail.Description == nil || *detail.Description != tt.wantDesc ||
+				detail.ExecutionNotes == nil || *detail.ExecutionNotes != tt.wantNotes {
+				t.Errorf("got locale %q, %q / %v / %v; want %q, %q / %q / %q",
+					detail.Locale, detail.Name, deref(detail.Description), deref(detail.ExecutionNotes),
+					tt.wantLocale, tt.wantName, tt.wantDesc, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestSaveTranslation(t *testing.T) {
+	tests := []struct {
+		name    string
+		slug    string
+		locale  string
+		req     models.TrickTranslationRequest
+		want    models.TrickTranslation
+		wantErr error
+	}{
+		{
+			name: "partial translation keeps the rest NULL", slug: "backflip", locale: "it",
+			req:  models.TrickTranslationRequest{Name: fixtures.Ptr("  Salto all'indietro  "), Description: fixtures.Ptr("")},
+			want: models.TrickTranslation{Locale: "it", Name: fixtures.Ptr("Salto all'indietro"), UpdatedAt: fixtures.Epoch},
+		},
+		{
+			name: "replaces an earlier translation", slug: "backflip", locale: "fr",
+			req: models.TrickTranslationRequest{Description: fixtures.Ptr("Un salto arrière")},
+			want: models.TrickTranslation{
+				Locale: "fr", Description: fixtures.Ptr("Un salto arrière"), UpdatedAt: fixtures.Epoch,
+			},
+		},
+		{
+			name: "nothing translated", slug: "backflip", locale: "it",
+			req: models.TrickTranslationRequest{Name: fixtures.Ptr(" ")}, wantErr: ErrEmptyTranslation,
+		},
+		{
+			name: "base locale", slug: "backflip", locale: BaseLocale,
+			req: models.TrickTranslationRequest{Name: fixtures.Ptr("Backflip")}, wantErr: ErrInvalidLocale,
+		},
+		{
+			name: "region tag", slug: "backflip", locale: "es-mx",
+			req: models.TrickTranslationRequest{Name: fixtures.Ptr("Mortal")}, wantErr: ErrInvalidLocale,
+		},
+		{
+			name: "unknown trick", slug: "no-such-trick", locale: "es",
+			req: models.TrickTranslationRequest{Name: fixtures.Ptr("Mortal")}, wantErr: ErrTrickNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, translations := translationFixtures()
+			service := NewAdminService(nil, nil, nil, nil, translations, nil, false, false, 0, nil)
+
+			got, err := service.SaveTranslation(context.Background(), tt.slug, tt.locale, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SaveTranslation() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			stored := translations.translations[tt.locale]
+			for _, translation := range []models.TrickTranslation{*got, stored} {
+				if translation.Locale != tt.want.Locale || deref(translation.Name) != deref(tt.want.Name) ||
+					deref(translation.Description) != deref(tt.want.Description) ||
+					deref(translation.ExecutionNotes) != deref(tt.want.ExecutionNotes) {
+					t.Errorf("translation = %+v, want %+v", translation, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// deref is *s, or "<nil>" to tell NULL apart from empty
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
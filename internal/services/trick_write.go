@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/sanitize"
+	"tricking-api/internal/viewer"
+)
+
+// =============================================================================
+// TRICK WRITES
+// =============================================================================
+// Creating and editing tricks through the API. Text is sanitized like every
+// other write path, and names follow the import's rules (1-MaxImportNameLength
+// characters).
+//
+// Names and slugs are unique across tricks and aliases, in any case. Writes are
+// checked up front (checkTrickUnique), again by the repository under the alias
+// lock, and finally by the unique indexes - whichever catches a duplicate, it
+// comes back as a *DuplicateTrickError.
+
+// Trick write errors
+var (
+	ErrInvalidTrickName      = errors.New("trick name must be 1-100 characters with at least one letter or digit")
+	ErrTrickNameTaken        = errors.New("trick name is already a trick name or alias")
+	ErrDuplicateTrick        = errors.New("trick name or slug is already taken")
+	ErrUnknownTrickReference = errors.New("stance or flip ID does not exist")
+	ErrEmptyTrickPatch       = errors.New("trick patch sets no fields")
+)
+
+// DuplicateTrickError is a trick write whose name or slug is already taken
+// It matches ErrDuplicateTrick, and a name clash also ErrTrickNameTaken.
+type DuplicateTrickError struct {
+	Field string // "name" or "slug"
+	Value string
+}
+
+func (e *DuplicateTrickError) Error() string {
+	return fmt.Sprintf("trick %s %q is already taken", e.Field, e.Value)
+}
+
+func (e *DuplicateTrickError) Is(target error) bool {
+	return target == ErrDuplicateTrick || (target == ErrTrickNameTaken && e.Field == "name")
+}
+
+// checkTrickUnique checks a write's name and slug against every other trick and alias
+// exceptSlug is the trick being written ("" for a new one) - its own name, slug
+// and former names don't count. An empty name or slug isn't checked.
+func checkTrickUnique(ctx context.Context, repo repository.TrickRepositoryInterface, name, slug, exceptSlug string) error {
+	if name != "" {
+		taken, err := repo.ExistsByName(ctx, name, exceptSlug)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return &DuplicateTrickError{Field: "name", Value: name}
+		}
+	}
+	if slug != "" {
+		taken, err := repo.ExistsBySlug(ctx, slug, exceptSlug)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return &DuplicateTrickError{Field: "slug", Value: slug}
+		}
+	}
+	return nil
+}
+
+// CreateTrick adds a trick to the catalog, slugged from its name
+// A taken slug gets a numeric suffix ("cork-2"); a taken name is a *DuplicateTrickError.
+// createdBy is recorded as the trick's creator and on its first revision.
+// Only an admin's trick goes straight into the catalog; anyone else's waits
+// as pending for an admin to approve it (response.Status says so).
+func (s *TrickService) CreateTrick(ctx context.Context, req models.TrickCreateRequest, createdBy *uuid.UUID) (*models.TrickDetailResponse, error) {
+	trick, err := trickFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	trick.Slug = aliasSlug(trick.Name)
+	trick.CreatedBy = createdBy
+
+	if err := checkTrickUnique(ctx, s.trickRepo, trick.Name, "", ""); err != nil {
+		return nil, trickWriteError(err, &trick, "failed to check trick name")
+	}
+	suggestion, err := s.suggestTrickCategory(ctx, &trick)
+	if err != nil {
+		return nil, err
+	}
+	status := models.TrickPending
+	if viewer.Role(ctx) == "admin" {
+		status = models.TrickApproved
+	}
+	if err := s.trickRepo.Insert(ctx, &trick, status, createdBy); err != nil {
+		return nil, trickWriteError(err, &trick, "failed to create trick")
+	}
+
+	response := s.detailResponse(&trick, responseView(ctx))
+	if status == models.TrickPending {
+		response.Status = status
+	}
+	response.SuggestedCategory = suggestion
+	return &response, nil
+}
+
+// suggestTrickCategory suggests a category for a new trick without a flip_id
+// (see SuggestCategory), filling it in when confident. Returns nil when
+// suggestions are off, the trick has a category, or no rule matched.
+func (s *TrickService) suggestTrickCategory(ctx context.Context, trick *models.Trick) (*models.CategorySuggestion, error) {
+	if !s.suggestCategories || trick.FlipID != nil {
+		return nil, nil
+	}
+	categories, err := s.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	suggestion := SuggestCategory(trick.Name, categories)
+	if suggestion != nil && suggestion.Confidence >= AutoAssignConfidence {
+		flipID := suggestion.ID
+		trick.FlipID = &flipID
+		suggestion.Applied = true
+	}
+	return suggestion, nil
+}
+
+// GetTrickSubmissions returns the tricks a user created, newest first, with their review status
+// A rejected trick carries the reviewer's reason, if they gave one.
+func (s *TrickService) GetTrickSubmissions(ctx context.Context, userID uuid.UUID, limit int) ([]models.TrickSubmission, error) {
+	tricks, err := s.trickRepo.FindSubmittedBy(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trick submissions: %w", err)
+	}
+	return tricks, nil
+}
+
+// UpdateTrick replaces a trick's editable fields with req (see models.TrickUpdateRequest)
+// A rename keeps the old name as a former name, so it still resolves and
+// searches. Also returns the trick's new last-modified time (for the ETag).
+func (s *TrickService) UpdateTrick(ctx context.Context, id string, req models.TrickUpdateRequest, changedBy *uuid.UUID) (*models.TrickDetailResponse, int64, error) {
+	trick, err := trickFromRequest(req.TrickCreateRequest)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	updated, err := withTrickID(ctx, s, id, func(slug string) (*models.Trick, error) {
+		current, err := s.trickRepo.GetByID(ctx, slug)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrTrickNotFound
+			}
+			return nil, fmt.Errorf("failed to get trick: %w", err)
+		}
+
+		if err := checkTrickUnique(ctx, s.trickRepo, trick.Name, "", current.Slug); err != nil {
+			return nil, trickWriteError(err, &trick, "failed to check trick name")
+		}
+
+		update := repository.TrickUpdate{Trick: trick, ChangedFields: changedTrickFields(current, &trick)}
+		if !strings.EqualFold(current.Name, trick.Name) {
+			update.FormerName, update.FormerNameSlug = current.Name, aliasSlug(current.Name)
+		}
+		if req.RegenerateSlug {
+			update.SlugBase = aliasSlug(trick.Name)
+		}
+
+		updated, err := s.trickRepo.Update(ctx, current.Slug, update, changedBy)
+		if err != nil {
+			return nil, trickWriteError(err, &update.Trick, "failed to update trick")
+		}
+
+		// Other tricks' dictionaries show this one's name (prerequisites) - a rename reaches them all
+		if current.Name != updated.Name || current.Slug != updated.Slug {
+			s.dictionaryCache.InvalidateAll()
+		} else {
+			s.dictionaryCache.InvalidateTrick(current.Slug)
+		}
+		return updated, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	response := s.detailResponse(updated, responseView(ctx))
+	return &response, trickLastModified(updated), nil
+}
+
+// PatchTrick changes only the fields req sets (see models.TrickPatchRequest)
+// The values must already be in range (req.Validate); names are checked and
+// renames keep the old name as in UpdateTrick. A patch never moves the slug.
+func (s *TrickService) PatchTrick(ctx context.Context, id string, req models.TrickPatchRequest, changedBy *uuid.UUID) (*models.TrickDetailResponse, int64, error) {
+	if req.Empty() {
+		return nil, 0, ErrEmptyTrickPatch
+	}
+
+	updated, err := withTrickID(ctx, s, id, func(slug string) (*models.Trick, error) {
+		current, err := s.trickRepo.GetByID(ctx, slug)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrTrickNotFound
+			}
+			return nil, fmt.Errorf("failed to get trick: %w", err)
+		}
+
+		patch := repository.TrickPatch{Trick: *current}
+		if req.Name.Set {
+			name := sanitize.Text(*req.Name.Value)
+			if aliasSlug(name) == "" || utf8.RuneCountInString(name) > MaxImportNameLength {
+				return nil, ErrInvalidTrickName
+			}
+			if err := checkTrickUnique(ctx, s.trickRepo, name, "", current.Slug); err != nil {
+				return nil, trickWriteError(err, &patch.Trick, "failed to check trick name")
+			}
+			patch.Name = name
+			patch.Columns = append(patch.Columns, "name")
+			if !strings.EqualFold(current.Name, name) {
+				patch.FormerName, patch.FormerNameSlug = current.Name, aliasSlug(current.Name)
+			}
+		}
+		if req.Difficulty.Set {
+			patch.Difficulty = req.Difficulty.Value
+			patch.Columns = append(patch.Columns, "difficulty")
+		}
+		if req.Description.Set {
+			patch.Description = sanitize.OptionalText(req.Description.Value)
+			patch.Columns = append(patch.Columns, "description")
+		}
+		if req.ExecutionNotes.Set {
+			patch.ExecutionNotes = sanitize.OptionalText(req.ExecutionNotes.Value)
+			patch.Columns = append(patch.Columns, "execution_notes")
+		}
+		if req.TakeoffStanceID.Set {
+			patch.TakeoffStanceID = req.TakeoffStanceID.Value
+			patch.Columns = append(patch.Columns, "takeoff_stance_id")
+		}
+		if req.LandingStanceID.Set {
+			patch.LandingStanceID = req.LandingStanceID.Value
+			patch.Columns = append(patch.Columns, "landing_stance_id")
+		}
+		if req.FlipID.Set {
+			patch.FlipID = req.FlipID.Value
+			patch.Columns = append(patch.Columns, "flip_id")
+		}
+		if req.Rotation.Set {
+			patch.Rotation = req.Rotation.Value
+			patch.Columns = append(patch.Columns, "rotation")
+		}
+		if req.Attribution.Set {
+			patch.Attribution = sanitize.OptionalText(req.Attribution.Value)
+			patch.Columns = append(patch.Columns, "attribution")
+		}
+		if req.License.Set {
+			patch.License = sanitize.OptionalText(req.License.Value)
+			patch.Columns = append(patch.Columns, "license")
+		}
+		patch.ChangedFields = changedTrickFields(current, &patch.Trick)
+
+		updated, err := s.trickRepo.Patch(ctx, current.Slug, patch, changedBy)
+		if err != nil {
+			return nil, trickWriteError(err, &patch.Trick, "failed to patch trick")
+		}
+
+		if current.Name != updated.Name {
+			s.dictionaryCache.InvalidateAll()
+		} else {
+			s.dictionaryCache.InvalidateTrick(current.Slug)
+		}
+		return updated, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	response := s.detailResponse(updated, responseView(ctx))
+	return &response, trickLastModified(updated), nil
+}
+
+// trickFromRequest validates and sanitizes the fields a create or update sets
+func trickFromRequest(req models.TrickCreateRequest) (models.Trick, error) {
+	name := sanitize.Text(req.Name)
+	if aliasSlug(name) == "" || utf8.RuneCountInString(name) > MaxImportNameLength {
+		return models.Trick{}, ErrInvalidTrickName
+	}
+
+	difficulty := req.Difficulty
+	return models.Trick{
+		Name:            name,
+		Description:     sanitize.OptionalText(req.Description),
+		Difficulty:      &difficulty,
+		ExecutionNotes:  sanitize.OptionalText(req.ExecutionNotes),
+		TakeoffStanceID: req.TakeoffStanceID,
+		LandingStanceID: req.LandingStanceID,
+		FlipID:          req.FlipID,
+		Rotation:        req.Rotation,
+		Attribution:     sanitize.OptionalText(req.Attribution),
+		License:         sanitize.OptionalText(req.License),
+	}, nil
+}
+
+// trickWriteError maps the repository errors of Insert, Update and Patch to service errors
+// trick is what was being written - its name or slug goes on a *DuplicateTrickError.
+func trickWriteError(err error, trick *models.Trick, action string) error {
+	var duplicate *DuplicateTrickError
+	switch {
+	case errors.As(err, &duplicate):
+		return duplicate
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrTrickNotFound
+	case errors.Is(err, repository.ErrNameTaken):
+		return &DuplicateTrickError{Field: "name", Value: trick.Name}
+	case errors.Is(err, repository.ErrSlugTaken):
+		return &DuplicateTrickError{Field: "slug", Value: trick.Slug}
+	case errors.Is(err, repository.ErrUnknownReference):
+		return ErrUnknownTrickReference
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// changedTrickFields lists the columns an update changes, for the revision
+func changedTrickFields(current, next *models.Trick) []string {
+	fields := []string{}
+	for _, field := range []struct {
+		name  string
+		equal bool
+	}{
+		{"name", current.Name == next.Name},
+		{"description", equalOptional(current.Description, next.Description)},
+		{"difficulty", equalPtr(current.Difficulty, next.Difficulty)},
+		{"execution_notes", equalOptional(current.ExecutionNotes, next.ExecutionNotes)},
+		{"takeoff_stance_id", equalPtr(current.TakeoffStanceID, next.TakeoffStanceID)},
+		{"landing_stance_id", equalPtr(current.LandingStanceID, next.LandingStanceID)},
+		{"flip_id", equalPtr(current.FlipID, next.FlipID)},
+		{"rotation", equalPtr(current.Rotation, next.Rotation)},
+		{"attribution", equalOptional(current.Attribution, next.Attribution)},
+		{"license", equalOptional(current.License, next.License)},
+	} {
+		if !field.equal {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+// equalPtr compares two nullable values by value
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
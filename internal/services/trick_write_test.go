@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/cache"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fakes"
+	"tricking-api/internal/testutil/fixtures"
+	"tricking-api/internal/viewer"
+)
+
+// fakeCreateTrickRepo takes any name and keeps the last trick inserted
+type fakeCreateTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	inserted *models.Trick
+}
+
+func (r *fakeCreateTrickRepo) ExistsByName(ctx context.Context, name, exceptSlug string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeCreateTrickRepo) Insert(ctx context.Context, trick *models.Trick, status string, changedBy *uuid.UUID) error {
+	trick.ID = trick.Slug
+	inserted := *trick
+	r.inserted = &inserted
+	return nil
+}
+
+// fakeUpdateTrickRepo holds one attributed trick and keeps the last update
+type fakeUpdateTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	update *repository.TrickUpdate
+}
+
+func (r *fakeUpdateTrickRepo) GetByID(ctx context.Context, slug string) (*models.Trick, error) {
+	trick := fixtures.Trick().WithSlug("cork").WithName("Cork").WithDifficulty(5).
+		WithAttribution("Loopkicks", "CC BY 4.0").Build()
+	return &trick, nil
+}
+
+func (r *fakeUpdateTrickRepo) ExistsByName(ctx context.Context, name, exceptSlug string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeUpdateTrickRepo) Update(ctx context.Context, slug string, update repository.TrickUpdate, changedBy *uuid.UUID) (*models.Trick, error) {
+	r.update = &update
+	trick := update.Trick
+	trick.ID, trick.Slug = slug, slug
+	return &trick, nil
+}
+
+func TestCreateTrickAttribution(t *testing.T) {
+	repo := &fakeCreateTrickRepo{}
+	service := NewTrickService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, 7, NewDifficultyBands(nil), 0, 0, 0, false)
+
+	req := models.TrickCreateRequest{
+		Name:        "Cork",
+		Difficulty:  5,
+		Attribution: fixtures.Ptr("  Tricking Bible "),
+		License:     fixtures.Ptr("CC BY 4.0"),
+	}
+	detail, err := service.CreateTrick(viewer.WithRole(context.Background(), "admin"), req, &fixtures.OwnerID)
+	if err != nil {
+		t.Fatalf("CreateTrick() error = %v", err)
+	}
+
+	inserted := repo.inserted
+	if inserted.Attribution == nil || *inserted.Attribution != "Tricking Bible" || inserted.License == nil || *inserted.License != "CC BY 4.0" {
+		t.Errorf("inserted attribution, license = %v, %v, want Tricking Bible, CC BY 4.0", inserted.Attribution, inserted.License)
+	}
+	if detail.Attribution == nil || detail.License == nil {
+		t.Errorf("response attribution, license = %v, %v, want both set", detail.Attribution, detail.License)
+	}
+}
+
+func TestCreateTrickSuggestsCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		suggestions bool
+		trickName   string
+		flipID      *int
+		want        *models.CategorySuggestion
+		wantFlipID  *int
+	}{
+		{name: "suggestions off", trickName: "Tornado Kick"},
+		{
+			name: "confident", suggestions: true, trickName: "Tornado Kick",
+			want:       &models.CategorySuggestion{ID: fixtures.CategoryKicks, Name: "Kicks", Confidence: 0.9, Applied: true},
+			wantFlipID: fixtures.Ptr(fixtures.CategoryKicks),
+		},
+		{
+			name: "not confident enough", suggestions: true, trickName: "Skip Vanish",
+			want: &models.CategorySuggestion{ID: fixtures.CategoryTransitions, Name: "Transitions", Confidence: 0.6},
+		},
+		{
+			name: "ambiguous", suggestions: true, trickName: "Cork Kick",
+			want: &models.CategorySuggestion{ID: fixtures.CategoryKicks, Name: "Kicks", Confidence: 0.45},
+		},
+		{name: "no rule matches", suggestions: true, trickName: "Raiz"},
+		{
+			name: "flip_id given", suggestions: true, trickName: "Tornado Kick",
+			flipID: fixtures.Ptr(fixtures.CategoryTwists), wantFlipID: fixtures.Ptr(fixtures.CategoryTwists),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCreateTrickRepo{}
+			service := NewTrickService(repo, nil, nil, nil, nil, nil, nil, &fakes.CategoryRepo{}, nil, nil, 7,
+				NewDifficultyBands(nil), 0, 0, 0, tt.suggestions)
+
+			req := models.TrickCreateRequest{Name: tt.trickName, Difficulty: 5, FlipID: tt.flipID}
+			detail, err := service.CreateTrick(context.Background(), req, &fixtures.OwnerID)
+			if err != nil {
+				t.Fatalf("CreateTrick() error = %v", err)
+			}
+
+			switch got := detail.SuggestedCategory; {
+			case tt.want == nil && got != nil:
+				t.Errorf("suggested_category = %+v, want none", *got)
+			case tt.want != nil && (got == nil || *got != *tt.want):
+				t.Errorf("suggested_category = %+v, want %+v", got, *tt.want)
+			}
+			switch got := repo.inserted.FlipID; {
+			case tt.wantFlipID == nil && got != nil:
+				t.Errorf("inserted flip_id = %d, want none", *got)
+			case tt.wantFlipID != nil && (got == nil || *got != *tt.wantFlipID):
+				t.Errorf("inserted flip_id = %v, want %d", got, *tt.wantFlipID)
+			}
+		})
+	}
+}
+
+func TestUpdateTrickAttribution(t *testing.T) {
+	tests := []struct {
+		name            string
+		req             models.TrickCreateRequest
+		wantAttribution *string
+		wantLicense     *string
+		wantChanged     []string
+	}{
+		{
+			name:            "unchanged",
+			req:             models.TrickCreateRequest{Name: "Cork", Difficulty: 5, Attribution: fixtures.Ptr("Loopkicks"), License: fixtures.Ptr("CC BY 4.0")},
+			wantAttribution: fixtures.Ptr("Loopkicks"),
+			wantLicense:     fixtures.Ptr("CC BY 4.0"),
+			wantChanged:     []string{},
+		},
+		{
+			name:            "changed",
+			req:             models.TrickCreateRequest{Name: "Cork", Difficulty: 5, Attribution: fixtures.Ptr("Tricking Bible"), License: fixtures.Ptr("CC BY 4.0")},
+			wantAttribution: fixtures.Ptr("Tricking Bible"),
+			wantLicense:     fixtures.Ptr("CC BY 4.0"),
+			wantChanged:     []string{"attribution"},
+		},
+		{
+			// PUT is the trick's full state: leaving them out clears them
+			name:        "left out",
+			req:         models.TrickCreateRequest{Name: "Cork", Difficulty: 5},
+			wantChanged: []string{"attribution", "license"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUpdateTrickRepo{}
+			service := NewTrickService(repo, nil, nil, nil, nil, nil, nil, nil, nil,
+				NewDictionaryCache(cache.NewMemory(), time.Minute), 7, NewDifficultyBands(nil), 0, 0, 0, false)
+
+			if _, _, err := service.UpdateTrick(context.Background(), "cork", models.TrickUpdateRequest{TrickCreateRequest: tt.req}, &fixtures.OwnerID); err != nil {
+				t.Fatalf("UpdateTrick() error = %v", err)
+			}
+
+			update := repo.update
+			if !equalOptional(update.Attribution, tt.wantAttribution) || !equalOptional(update.License, tt.wantLicense) {
+				t.Errorf("attribution, license = %v, %v, want %v, %v", update.Attribution, update.License, tt.wantAttribution, tt.wantLicense)
+			}
+			if !reflect.DeepEqual(update.ChangedFields, tt.wantChanged) {
+				t.Errorf("changed fields = %v, want %v", update.ChangedFields, tt.wantChanged)
+			}
+		})
+	}
+}
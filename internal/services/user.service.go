@@ -2,60 +2,550 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
+	"tricking-api/internal/config"
 	"tricking-api/internal/models"
 	"tricking-api/internal/repository"
+	"tricking-api/internal/sanitize"
 )
 
+// Length caps for combo text, counted in characters after sanitization
+const (
+	MaxComboNameLength      = 100
+	MaxComboNotesLength     = 2000
+	MaxComboTrickNoteLength = 280
+)
+
+// MaxTrainingCalendarDays caps a training calendar's range - one year, leap years included
+const MaxTrainingCalendarDays = 366
+
+// ErrInvalidCalendarRange indicates a training calendar range that is reversed or too long
+var ErrInvalidCalendarRange = errors.New("calendar range must have from on or before to and span at most 366 days")
+
+// CUSTOM ERRORS
+var (
+	ErrComboNotFound     = errors.New("combo not found")
+	ErrInvalidComboName  = errors.New("combo name must be 1-100 characters")
+	ErrComboNoteTooLong  = errors.New("combo notes must be at most 2000 characters and position notes at most 280")
+	ErrUnknownComboTrick = errors.New("combo references a trick that does not exist")
+	ErrComboLimitReached = errors.New("saved combo limit reached")
+)
+
+// ComboLimitError is ErrComboLimitReached with the numbers behind it
+// errors.Is(err, ErrComboLimitReached) matches it; errors.As gets Count and Limit
+type ComboLimitError struct {
+	Count int
+	Limit int
+}
+
+func (e *ComboLimitError) Error() string {
+	return fmt.Sprintf("%v: %d of %d combos saved", ErrComboLimitReached, e.Count, e.Limit)
+}
+
+func (e *ComboLimitError) Is(target error) bool {
+	return target == ErrComboLimitReached
+}
+
+// DuplicateComboTricksError lists tricks that appear more than once in a combo request
+// Use errors.As to get at the details for a structured response
+type DuplicateComboTricksError struct {
+	Duplicates []models.DuplicateComboTrick
+}
+
+func (e *DuplicateComboTricksError) Error() string {
+	return fmt.Sprintf("combo repeats %d trick(s) - set allow_duplicates to keep them", len(e.Duplicates))
+}
+
 // UserServiceInterface defines the contract for user operations
 type UserServiceInterface interface {
 	GetUserCombos(ctx context.Context, userID uuid.UUID) ([]models.ComboResponse, error)
+	GetUserComboSummaries(ctx context.Context, userID uuid.UUID) ([]models.ComboSummaryResponse, error)
+	GetRecentTricks(ctx context.Context, userID uuid.UUID, limit int) ([]models.RecentTrickResponse, error)
+	CreateCombo(ctx context.Context, userID uuid.UUID, role string, req models.ComboSaveRequest) (*models.ComboResponse, error)
+	ImportCombos(ctx context.Context, userID uuid.UUID, role string, reqs []models.ComboSaveRequest, emit ComboImportEmitter) (*models.ComboImportSummary, error)
+	UpdateCombo(ctx context.Context, userID uuid.UUID, comboID int64, req models.ComboSaveRequest) (*models.ComboResponse, error)
+	ReplaceComboTricks(ctx context.Context, userID uuid.UUID, comboID int64, req models.ComboTricksReplaceRequest) (*models.ComboResponse, error)
+	BatchGetCombos(ctx context.Context, ids []int64, viewer ComboViewer) (*models.ComboBatchResponse, error)
+	GetTrainingCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.TrainingCalendarResponse, error)
 	// Add more user-related methods as needed:
 	// GetProfile(ctx context.Context, userID uuid.UUID) (*models.UserProfile, error)
 	// UpdatePreferences(ctx context.Context, userID uuid.UUID, prefs models.UserPreferences) error
 }
 
+// ComboViewer identifies who is reading combos, for per-combo authorization
+// UserID is nil when the BFF calls without a user (it then only sees public combos)
+type ComboViewer struct {
+	UserID  *uuid.UUID
+	IsAdmin bool
+}
+
+// canView reports whether the viewer may read a combo:
+// admins see everything, everyone else their own combos plus shared/featured ones
+func (v ComboViewer) canView(combo models.Combo) bool {
+	if v.IsAdmin || combo.IsShared || combo.IsFeatured {
+		return true
+	}
+	return v.UserID != nil && *v.UserID == combo.UserID
+}
+
 // UserService implements UserServiceInterface
 type UserService struct {
-	userRepo repository.UserRepositoryInterface
+	userRepo    repository.UserRepositoryInterface
+	comboRepo   repository.ComboRepositoryInterface
+	comboLimits config.ComboLimitConfig
 }
 
 // NewUserService creates a new UserService instance
-func NewUserService(userRepo repository.UserRepositoryInterface) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepositoryInterface, comboRepo repository.ComboRepositoryInterface, comboLimits config.ComboLimitConfig) *UserService {
+	return &UserService{
+		userRepo:    userRepo,
+		comboRepo:   comboRepo,
+		comboLimits: comboLimits,
+	}
 }
 
-// GetUserCombos retrieves all saved combos for a user with their tricks
+// GetUserCombos retrieves all saved combos for a user, each with its tricks
 func (s *UserService) GetUserCombos(ctx context.Context, userID uuid.UUID) ([]models.ComboResponse, error) {
-	// Get the user's combos
-	combos, err := s.userRepo.GetCombosByUserID(ctx, userID)
+	summaries, err := s.GetUserComboSummaries(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user combos: %w", err)
+		return nil, err
 	}
 
-	// Build response with tricks for each combo
-	responses := make([]models.ComboResponse, 0, len(combos))
+	responses := make([]models.ComboResponse, 0, len(summaries))
 
-	for _, combo := range combos {
+	for _, summary := range summaries {
 		// Get tricks for this combo
-		tricks, err := s.userRepo.GetComboTricks(ctx, combo.ID)
+		tricks, err := s.userRepo.GetComboTricks(ctx, summary.ID)
 		if err != nil {
 			// Log error but continue - don't fail the whole request for one bad combo
 			// In production, use a proper logger
-			fmt.Printf("Warning: failed to get tricks for combo %d: %v\n", combo.ID, err)
-			tricks = []models.TrickSimpleResponse{} // Empty slice instead of nil
+			fmt.Printf("Warning: failed to get tricks for combo %d: %v\n", summary.ID, err)
 		}
+		if tricks == nil {
+			tricks = []models.ComboTrickResponse{} // Empty slice instead of nil
+		}
+
+		responses = append(responses, models.ComboResponse{ComboSummaryResponse: summary, Tricks: tricks})
+	}
 
-		responses = append(responses, models.ComboResponse{
-			ID:        combo.ID,
-			Name:      combo.Name,
-			Tricks:    tricks,
-			CreatedAt: combo.CreatedAt,
+	return responses, nil
+}
+
+// GetUserComboSummaries retrieves a user's saved combos with totals only
+// Tricks are not loaded - total difficulty and trick count come from one query
+func (s *UserService) GetUserComboSummaries(ctx context.Context, userID uuid.UUID) ([]models.ComboSummaryResponse, error) {
+	// Get the user's combos with total difficulty and trick count
+	combos, err := s.userRepo.GetComboSummariesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user combos: %w", err)
+	}
+
+	responses := make([]models.ComboSummaryResponse, 0, len(combos))
+	for _, combo := range combos {
+		responses = append(responses, models.ComboSummaryResponse{
+			ID:              combo.ID,
+			Name:            combo.Name,
+			Notes:           combo.Notes,
+			TrickCount:      combo.TrickCount,
+			TotalDifficulty: combo.TotalDifficulty,
+			CreatedAt:       combo.CreatedAt,
 		})
 	}
 
 	return responses, nil
 }
+
+// GetRecentTricks retrieves the tricks a user has most recently used in saved combos
+// Usage is derived from combo_tricks, so saving a combo is all it takes to "track" it
+func (s *UserService) GetRecentTricks(ctx context.Context, userID uuid.UUID, limit int) ([]models.RecentTrickResponse, error) {
+	tricks, err := s.userRepo.GetRecentTricks(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent tricks: %w", err)
+	}
+	return tricks, nil
+}
+
+// GetTrainingCalendar builds a user's day-by-day attempt calendar for [from, to]
+// from and to are dates - both are truncated to their UTC day and both are included.
+// The database only returns days with attempts; the gaps are filled with zeros here
+// so clients can render the grid as is.
+func (s *UserService) GetTrainingCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.TrainingCalendarResponse, error) {
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour)
+	if to.Before(from) || to.Sub(from) >= MaxTrainingCalendarDays*24*time.Hour {
+		return nil, ErrInvalidCalendarRange
+	}
+
+	attemptDays, err := s.userRepo.GetAttemptDays(ctx, userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get training calendar: %w", err)
+	}
+	byDate := make(map[string]models.AttemptDay, len(attemptDays))
+	for _, day := range attemptDays {
+		byDate[day.Day.Format(time.DateOnly)] = day
+	}
+
+	calendar := &models.TrainingCalendarResponse{
+		From: from.Format(time.DateOnly),
+		To:   to.Format(time.DateOnly),
+		Days: make([]models.TrainingDay, 0, int(to.Sub(from).Hours()/24)+1),
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		counts := byDate[date]
+		calendar.Days = append(calendar.Days, models.TrainingDay{Date: date, Attempts: counts.Attempts, Landed: counts.Landed})
+		calendar.TotalAttempts += counts.Attempts
+		calendar.TotalLanded += counts.Landed
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	calendar.CurrentStreak, calendar.LongestStreak = trainingStreaks(calendar.Days, to.Equal(today))
+	return calendar, nil
+}
+
+// trainingStreaks returns the current and longest runs of consecutive days with attempts
+// The current run ends on the last day - or the day before when the last day is
+// today, so a streak isn't shown as broken before the user has had a chance to train.
+func trainingStreaks(days []models.TrainingDay, lastIsToday bool) (current, longest int) {
+	run := 0
+	for _, day := range days {
+		if day.Attempts == 0 {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+
+	current = run
+	if current == 0 && lastIsToday && len(days) > 1 {
+		for i := len(days) - 2; i >= 0 && days[i].Attempts > 0; i-- {
+			current++
+		}
+	}
+	return current, longest
+}
+
+// CreateCombo saves a new combo for a user
+// role is the caller's role - it decides the saved-combo cap (see checkComboLimit)
+func (s *UserService) CreateCombo(ctx context.Context, userID uuid.UUID, role string, req models.ComboSaveRequest) (*models.ComboResponse, error) {
+	name, notes, input, err := cleanComboRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	tricks, err := s.resolveComboTricks(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkComboLimit(ctx, userID, role, 1); err != nil {
+		return nil, err
+	}
+
+	combo, err := s.comboRepo.Create(ctx, userID, name, notes, tricks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create combo: %w", err)
+	}
+
+	return s.buildSavedComboResponse(ctx, combo)
+}
+
+// ComboImportEmitter receives one result per combo of an import, in order
+// rowErr is the combo's error (nil for saved combos); the caller turns it
+// into the result's Code/Error and collects the results.
+type ComboImportEmitter func(result models.ComboImportResult, rowErr error)
+
+// ImportCombos saves several combos for a user, in request order, each on its own
+// An invalid combo is reported and skipped. Once the user's cap is reached the
+// rest are reported as over the limit - the combos saved before it stay saved.
+// Any other error stops the import; combos saved until then stay saved too.
+// Returns the summary; results go through emit.
+func (s *UserService) ImportCombos(ctx context.Context, userID uuid.UUID, role string, reqs []models.ComboSaveRequest, emit ComboImportEmitter) (*models.ComboImportSummary, error) {
+	summary := &models.ComboImportSummary{Rows: len(reqs)}
+
+	for i, req := range reqs {
+		result := models.ComboImportResult{Row: i + 1}
+		combo, err := s.CreateCombo(ctx, userID, role, req)
+		switch {
+		case err == nil:
+			result.Status = models.ImportCreated
+			result.Combo = combo
+			summary.Created++
+		case errors.Is(err, ErrComboLimitReached):
+			result.Status = models.ImportOverLimit
+			summary.OverLimit++
+		case isComboRequestError(err):
+			result.Status = models.ImportInvalid
+			summary.Invalid++
+		default:
+			return summary, err
+		}
+		emit(result, err)
+	}
+	return summary, nil
+}
+
+// isComboRequestError reports whether err is about the combo sent rather than a failure to save it
+func isComboRequestError(err error) bool {
+	var duplicateErr *DuplicateComboTricksError
+	return errors.As(err, &duplicateErr) ||
+		errors.Is(err, ErrInvalidComboName) ||
+		errors.Is(err, ErrComboNoteTooLong) ||
+		errors.Is(err, ErrUnknownComboTrick)
+}
+
+// checkComboLimit returns a *ComboLimitError if adding combos would take the
+// user past their role's cap. Every path that creates combos goes through here.
+//
+// The count is read before the insert, so two saves racing at the cap can both
+// get through - the cap is a plan limit, not an invariant, so one over is fine.
+func (s *UserService) checkComboLimit(ctx context.Context, userID uuid.UUID, role string, adding int) error {
+	limit := s.comboLimits.For(role)
+	if limit == 0 {
+		return nil
+	}
+
+	count, err := s.comboRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check combo limit: %w", err)
+	}
+	if count+adding > limit {
+		return &ComboLimitError{Count: count, Limit: limit}
+	}
+	return nil
+}
+
+// UpdateCombo replaces the name, notes and tricks of one of the user's combos
+func (s *UserService) UpdateCombo(ctx context.Context, userID uuid.UUID, comboID int64, req models.ComboSaveRequest) (*models.ComboResponse, error) {
+	name, notes, input, err := cleanComboRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	tricks, err := s.resolveComboTricks(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	combo, err := s.comboRepo.Update(ctx, userID, comboID, name, notes, tricks)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrComboNotFound
+		}
+		return nil, fmt.Errorf("failed to update combo: %w", err)
+	}
+
+	return s.buildSavedComboResponse(ctx, combo)
+}
+
+// ReplaceComboTricks replaces only the trick list of one of the user's combos
+// Positions are assigned from array order, so gaps can't happen
+func (s *UserService) ReplaceComboTricks(ctx context.Context, userID uuid.UUID, comboID int64, req models.ComboTricksReplaceRequest) (*models.ComboResponse, error) {
+	input, err := cleanComboTricks(req.Tricks, req.AllowDuplicates)
+	if err != nil {
+		return nil, err
+	}
+	tricks, err := s.resolveComboTricks(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	combo, err := s.comboRepo.ReplaceTricks(ctx, userID, comboID, tricks)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrComboNotFound
+		}
+		return nil, fmt.Errorf("failed to replace combo tricks: %w", err)
+	}
+
+	return s.buildSavedComboResponse(ctx, combo)
+}
+
+// BatchGetCombos loads several combos (from any users) in two queries
+// Combos the viewer may not see are reported as missing, same as ones that don't
+// exist, so the response never reveals whether a private combo ID is real.
+func (s *UserService) BatchGetCombos(ctx context.Context, ids []int64, viewer ComboViewer) (*models.ComboBatchResponse, error) {
+	// Callers may repeat IDs (feeds often do) - fetch each once, answer in request order
+	uniqueIDs := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+
+	combos, err := s.comboRepo.GetByIDs(ctx, uniqueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get combos: %w", err)
+	}
+
+	// Authorization happens here, per combo, before any tricks are loaded
+	visible := make(map[int64]models.ComboSummary, len(combos))
+	visibleIDs := make([]int64, 0, len(combos))
+	for _, combo := range combos {
+		if viewer.canView(combo.Combo) {
+			visible[combo.ID] = combo
+			visibleIDs = append(visibleIDs, combo.ID)
+		}
+	}
+
+	tricksByCombo := map[int64][]models.ComboTrickResponse{}
+	if len(visibleIDs) > 0 {
+		if tricksByCombo, err = s.comboRepo.GetTricksForCombos(ctx, visibleIDs); err != nil {
+			return nil, fmt.Errorf("failed to get combo tricks: %w", err)
+		}
+	}
+
+	response := &models.ComboBatchResponse{
+		Combos:     make([]models.ComboResponse, 0, len(visibleIDs)),
+		MissingIDs: make([]int64, 0),
+	}
+	for _, id := range uniqueIDs {
+		combo, ok := visible[id]
+		if !ok {
+			response.MissingIDs = append(response.MissingIDs, id)
+			continue
+		}
+
+		tricks := tricksByCombo[id]
+		if tricks == nil {
+			tricks = []models.ComboTrickResponse{}
+		}
+		ownerID := combo.UserID
+		response.Combos = append(response.Combos, models.ComboResponse{
+			ComboSummaryResponse: models.ComboSummaryResponse{
+				ID:              combo.ID,
+				OwnerID:         &ownerID,
+				Name:            combo.Name,
+				Notes:           combo.Notes,
+				TrickCount:      combo.TrickCount,
+				TotalDifficulty: combo.TotalDifficulty,
+				CreatedAt:       combo.CreatedAt,
+			},
+			Tricks: tricks,
+		})
+	}
+
+	return response, nil
+}
+
+// =============================================================================
+// PRIVATE HELPER METHODS
+// =============================================================================
+
+// cleanComboRequest sanitizes combo text and enforces the length caps
+func cleanComboRequest(req models.ComboSaveRequest) (string, *string, []models.ComboTrickInput, error) {
+	name := sanitize.Text(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > MaxComboNameLength {
+		return "", nil, nil, ErrInvalidComboName
+	}
+
+	notes := sanitize.OptionalText(req.Notes)
+	if notes != nil && utf8.RuneCountInString(*notes) > MaxComboNotesLength {
+		return "", nil, nil, ErrComboNoteTooLong
+	}
+
+	tricks, err := cleanComboTricks(req.Tricks, req.AllowDuplicates)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return name, notes, tricks, nil
+}
+
+// cleanComboTricks sanitizes position notes and rejects accidental duplicates
+// Repeating a trick is legitimate, so allowDuplicates lets callers opt in explicitly
+func cleanComboTricks(input []models.ComboTrickInput, allowDuplicates bool) ([]models.ComboTrickInput, error) {
+	tricks := make([]models.ComboTrickInput, len(input))
+	positionsBySlug := make(map[string][]int, len(input))
+	order := make([]string, 0, len(input)) // First-seen order keeps the error output stable
+
+	for i, trick := range input {
+		note := sanitize.OptionalText(trick.Note)
+		if note != nil && utf8.RuneCountInString(*note) > MaxComboTrickNoteLength {
+			return nil, ErrComboNoteTooLong
+		}
+		tricks[i] = models.ComboTrickInput{TrickID: trick.TrickID, Note: note}
+
+		if _, seen := positionsBySlug[trick.TrickID]; !seen {
+			order = append(order, trick.TrickID)
+		}
+		positionsBySlug[trick.TrickID] = append(positionsBySlug[trick.TrickID], i+1)
+	}
+
+	if !allowDuplicates {
+		duplicates := make([]models.DuplicateComboTrick, 0)
+		for _, slug := range order {
+			if positions := positionsBySlug[slug]; len(positions) > 1 {
+				duplicates = append(duplicates, models.DuplicateComboTrick{TrickID: slug, Positions: positions})
+			}
+		}
+		if len(duplicates) > 0 {
+			return nil, &DuplicateComboTricksError{Duplicates: duplicates}
+		}
+	}
+
+	return tricks, nil
+}
+
+// resolveComboTricks turns a combo's trick slugs (or legacy numeric IDs) into
+// the integer IDs combo_tricks stores - one batched lookup, before the write's
+// transaction, so an unknown trick fails the save before anything is written
+func (s *UserService) resolveComboTricks(ctx context.Context, input []models.ComboTrickInput) ([]repository.ComboTrick, error) {
+	idents := make([]string, len(input))
+	for i, trick := range input {
+		idents[i] = trick.TrickID
+	}
+	ids, err := s.comboRepo.ResolveTrickIDs(ctx, idents)
+	if err != nil {
+		return nil, err
+	}
+
+	tricks := make([]repository.ComboTrick, len(input))
+	for i, trick := range input {
+		id, ok := ids[trick.TrickID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownComboTrick, trick.TrickID)
+		}
+		tricks[i] = repository.ComboTrick{TrickID: id, Note: trick.Note}
+	}
+	return tricks, nil
+}
+
+// buildSavedComboResponse loads a saved combo's tricks and builds the response
+func (s *UserService) buildSavedComboResponse(ctx context.Context, combo *models.Combo) (*models.ComboResponse, error) {
+	tricks, err := s.comboRepo.GetTricksForCombo(ctx, combo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get combo tricks: %w", err)
+	}
+
+	// NULL difficulties count as 0, same as the combos list query
+	totalDifficulty := int64(0)
+	for _, trick := range tricks {
+		if trick.Difficulty != nil {
+			totalDifficulty += *trick.Difficulty
+		}
+	}
+
+	if tricks == nil {
+		tricks = []models.ComboTrickResponse{}
+	}
+
+	return &models.ComboResponse{
+		ComboSummaryResponse: models.ComboSummaryResponse{
+			ID:              combo.ID,
+			Name:            combo.Name,
+			Notes:           combo.Notes,
+			TrickCount:      int64(len(tricks)),
+			TotalDifficulty: totalDifficulty,
+			CreatedAt:       combo.CreatedAt,
+		},
+		Tricks: tricks,
+	}, nil
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// fakeLimitComboRepo saves combos in memory against the fixture catalog
+type fakeLimitComboRepo struct {
+	repository.ComboRepositoryInterface
+
+	saved     int
+	failAfter int // Create fails once this many combos are saved (0 = never)
+}
+
+func (r *fakeLimitComboRepo) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	return r.saved, nil
+}
+
+func (r *fakeLimitComboRepo) ResolveTrickIDs(ctx context.Context, ids []string) (map[string]int, error) {
+	known := make(map[string]int)
+	for i, trick := range fixtures.CatalogTricks() {
+		known[trick.Slug] = i + 1
+	}
+	resolved := make(map[string]int, len(ids))
+	for _, id := range ids {
+		if n, ok := known[id]; ok {
+			resolved[id] = n
+		}
+	}
+	return resolved, nil
+}
+
+func (r *fakeLimitComboRepo) Create(ctx context.Context, userID uuid.UUID, name string, notes *string, tricks []repository.ComboTrick) (*models.Combo, error) {
+	if r.failAfter > 0 && r.saved >= r.failAfter {
+		return nil, errors.New("db down")
+	}
+	r.saved++
+	combo := fixtures.Combo().WithID(int64(r.saved)).WithName(name).OwnedBy(userID).Build()
+	return &combo, nil
+}
+
+func (r *fakeLimitComboRepo) GetTricksForCombo(ctx context.Context, comboID int64) ([]models.ComboTrickResponse, error) {
+	return nil, nil
+}
+
+// testComboLimits mirrors the config defaults: 50 for everyone, admin and pro unlimited
+var testComboLimits = config.ComboLimitConfig{
+	Default: 50,
+	ByRole:  map[string]int{"admin": 0, "pro": 0, "moderator": 200},
+}
+
+func comboRequest(name string, tricks ...string) models.ComboSaveRequest {
+	req := models.ComboSaveRequest{Name: name}
+	for _, slug := range tricks {
+		req.Tricks = append(req.Tricks, models.ComboTrickInput{TrickID: slug})
+	}
+	return req
+}
+
+func TestCreateComboLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		role      string
+		saved     int
+		wantLimit int // 0 = the save goes through
+	}{
+		{name: "well under", role: "user", saved: 10},
+		{name: "one below the cap", role: "user", saved: 49},
+		{name: "at the cap", role: "user", saved: 50, wantLimit: 50},
+		{name: "already over", role: "user", saved: 51, wantLimit: 50},
+		{name: "no role gets the default", role: "", saved: 50, wantLimit: 50},
+		{name: "pro bypasses", role: "pro", saved: 500},
+		{name: "admin bypasses", role: "admin", saved: 500},
+		{name: "role override below", role: "moderator", saved: 199},
+		{name: "role override at", role: "moderator", saved: 200, wantLimit: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLimitComboRepo{saved: tt.saved}
+			service := NewUserService(nil, repo, testComboLimits)
+
+			_, err := service.CreateCombo(context.Background(), uuid.New(), tt.role, comboRequest("Cork Chain", "cork", "gainer"))
+
+			if tt.wantLimit == 0 {
+				if err != nil {
+					t.Fatalf("CreateCombo() error = %v, want nil", err)
+				}
+				if repo.saved != tt.saved+1 {
+					t.Errorf("saved = %d, want %d", repo.saved, tt.saved+1)
+				}
+				return
+			}
+
+			var limitErr *ComboLimitError
+			if !errors.As(err, &limitErr) || !errors.Is(err, ErrComboLimitReached) {
+				t.Fatalf("CreateCombo() error = %v, want a *ComboLimitError", err)
+			}
+			if limitErr.Count != tt.saved || limitErr.Limit != tt.wantLimit {
+				t.Errorf("error = %d of %d, want %d of %d", limitErr.Count, limitErr.Limit, tt.saved, tt.wantLimit)
+			}
+			if repo.saved != tt.saved {
+				t.Errorf("a combo was saved past the cap (%d saved)", repo.saved)
+			}
+		})
+	}
+}
+
+func TestImportCombosPartialSuccess(t *testing.T) {
+	valid := comboRequest("Kick Chain", "540-kick", "butterfly-kick")
+	unknownTrick := comboRequest("Mystery", "540-kick", "not-a-trick")
+	duplicate := comboRequest("Twice", "cork", "cork")
+
+	limits := config.ComboLimitConfig{Default: 5, ByRole: map[string]int{"pro": 0}}
+
+	tests := []struct {
+		name       string
+		role       string
+		saved      int
+		failAfter  int
+		reqs       []models.ComboSaveRequest
+		wantStatus []string
+		wantSaved  int
+		wantErr    bool
+	}{
+		{
+			name: "room for all", saved: 0,
+			reqs:       []models.ComboSaveRequest{valid, valid, valid},
+			wantStatus: []string{models.ImportCreated, models.ImportCreated, models.ImportCreated},
+			wantSaved:  3,
+		},
+		{
+			name: "exactly fills the cap", saved: 2,
+			reqs:       []models.ComboSaveRequest{valid, valid, valid},
+			wantStatus: []string{models.ImportCreated, models.ImportCreated, models.ImportCreated},
+			wantSaved:  5,
+		},
+		{
+			name: "cap hit mid-batch", saved: 3,
+			reqs:       []models.ComboSaveRequest{valid, valid, valid, valid},
+			wantStatus: []string{models.ImportCreated, models.ImportCreated, models.ImportOverLimit, models.ImportOverLimit},
+			wantSaved:  5,
+		},
+		{
+			name: "invalid combos don't use up the cap", saved: 3,
+			reqs:       []models.ComboSaveRequest{valid, unknownTrick, duplicate, valid, valid},
+			wantStatus: []string{models.ImportCreated, models.ImportInvalid, models.ImportInvalid, models.ImportCreated, models.ImportOverLimit},
+			wantSaved:  5,
+		},
+		{
+			name: "already at the cap", saved: 5,
+			reqs:       []models.ComboSaveRequest{valid, unknownTrick},
+			wantStatus: []string{models.ImportOverLimit, models.ImportInvalid},
+			wantSaved:  5,
+		},
+		{
+			name: "pro has no cap", role: "pro", saved: 5,
+			reqs:       []models.ComboSaveRequest{valid, valid},
+			wantStatus: []string{models.ImportCreated, models.ImportCreated},
+			wantSaved:  7,
+		},
+		{
+			name: "save failure stops the import and keeps earlier combos", saved: 0, failAfter: 1,
+			reqs:       []models.ComboSaveRequest{valid, valid, valid},
+			wantStatus: []string{models.ImportCreated},
+			wantSaved:  1, wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLimitComboRepo{saved: tt.saved, failAfter: tt.failAfter}
+			service := NewUserService(nil, repo, limits)
+
+			var statuses []string
+			emit := func(result models.ComboImportResult, rowErr error) {
+				if result.Row != len(statuses)+1 {
+					t.Errorf("result for row %d arrived as result %d", result.Row, len(statuses)+1)
+				}
+				if (result.Status == models.ImportCreated) != (rowErr == nil) {
+					t.Errorf("row %d: status %s with error %v", result.Row, result.Status, rowErr)
+				}
+				if result.Status == models.ImportOverLimit && !errors.Is(rowErr, ErrComboLimitReached) {
+					t.Errorf("row %d: over the limit with error %v", result.Row, rowErr)
+				}
+				if (result.Combo != nil) != (result.Status == models.ImportCreated) {
+					t.Errorf("row %d: status %s with combo %v", result.Row, result.Status, result.Combo)
+				}
+				statuses = append(statuses, result.Status)
+			}
+
+			summary, err := service.ImportCombos(context.Background(), uuid.New(), tt.role, tt.reqs, emit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImportCombos() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(statuses) != len(tt.wantStatus) {
+				t.Fatalf("statuses = %v, want %v", statuses, tt.wantStatus)
+			}
+			counts := map[string]int{}
+			for i, status := range statuses {
+				if status != tt.wantStatus[i] {
+					t.Errorf("row %d status = %s, want %s", i+1, status, tt.wantStatus[i])
+				}
+				counts[status]++
+			}
+			if summary.Rows != len(tt.reqs) || summary.Created != counts[models.ImportCreated] ||
+				summary.Invalid != counts[models.ImportInvalid] || summary.OverLimit != counts[models.ImportOverLimit] {
+				t.Errorf("summary = %+v, want counts %v", *summary, counts)
+			}
+			if repo.saved != tt.wantSaved {
+				t.Errorf("saved = %d, want %d", repo.saved, tt.wantSaved)
+			}
+		})
+	}
+}
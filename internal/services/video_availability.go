@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+)
+
+// =============================================================================
+// VIDEO AVAILABILITY
+// =============================================================================
+// Videos link to external hosts (mostly YouTube), and those links die when a
+// video is removed or made private. The checker records an availability status
+// per video so the dictionary page can skip dead links.
+
+// ErrVideoNotFound indicates the requested video doesn't exist
+var ErrVideoNotFound = errors.New("video not found")
+
+// ErrAvailabilityCheckFailed indicates the external host couldn't give a definite answer
+var ErrAvailabilityCheckFailed = errors.New("video availability check failed")
+
+// videoRecheckAfter is how old a check can get before the background job repeats it
+const videoRecheckAfter = 24 * time.Hour
+
+// videoCheckBatchSize bounds how many videos one background run checks
+const videoCheckBatchSize = 50
+
+// AvailabilityChecker asks an external host whether a video still plays
+// Returns models.VideoAvailable or models.VideoUnavailable; an error means "don't know"
+// Swap in a fake implementation to test without network access.
+type AvailabilityChecker interface {
+	Check(ctx context.Context, videoURL string) (string, error)
+}
+
+// HTTPAvailabilityChecker checks videos over HTTP
+// YouTube links go through the oEmbed endpoint, which answers 404/401/403 for
+// removed or private videos; anything else gets a HEAD request.
+type HTTPAvailabilityChecker struct {
+	client *http.Client
+}
+
+// NewHTTPAvailabilityChecker creates a new HTTPAvailabilityChecker instance
+func NewHTTPAvailabilityChecker(client *http.Client) *HTTPAvailabilityChecker {
+	return &HTTPAvailabilityChecker{client: client}
+}
+
+// Check implements AvailabilityChecker
+func (c *HTTPAvailabilityChecker) Check(ctx context.Context, videoURL string) (string, error) {
+	method, checkURL := http.MethodHead, videoURL
+	if isYouTubeURL(videoURL) {
+		method = http.MethodGet
+		checkURL = "https://www.youtube.com/oembed?format=json&url=" + url.QueryEscape(videoURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, checkURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrAvailabilityCheckFailed, err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrAvailabilityCheckFailed, err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return models.VideoAvailable, nil
+	case resp.StatusCode == http.StatusNotFound,
+		resp.StatusCode == http.StatusGone,
+		resp.StatusCode == http.StatusUnauthorized,
+		resp.StatusCode == http.StatusForbidden:
+		return models.VideoUnavailable, nil
+	default:
+		// 429s and 5xx say nothing about the video itself
+		return "", fmt.Errorf("%w: %s answered %d", ErrAvailabilityCheckFailed, req.URL.Host, resp.StatusCode)
+	}
+}
+
+// isYouTubeURL reports whether a video URL is hosted on YouTube
+func isYouTubeURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	return host == "youtube.com" || host == "m.youtube.com" || host == "youtu.be"
+}
+
+// VideoAvailabilityServiceInterface defines the contract for video availability checks
+type VideoAvailabilityServiceInterface interface {
+	CheckVideo(ctx context.Context, id int64) (*models.VideoAvailabilityResponse, error)
+}
+
+// VideoAvailabilityService checks and records video availability
+type VideoAvailabilityService struct {
+	videoRepo repository.VideoRepositoryInterface
+	checker   AvailabilityChecker
+
+	// dictionaryCache is invalidated when a video's availability flips,
+	// since that can change which video a dictionary features
+	dictionaryCache *DictionaryCache
+}
+
+// NewVideoAvailabilityService creates a new VideoAvailabilityService instance
+func NewVideoAvailabilityService(videoRepo repository.VideoRepositoryInterface, checker AvailabilityChecker, dictionaryCache *DictionaryCache) *VideoAvailabilityService {
+	return &VideoAvailabilityService{
+		videoRepo:       videoRepo,
+		checker:         checker,
+		dictionaryCache: dictionaryCache,
+	}
+}
+
+// CheckVideo checks one video right now and records the result
+// The caller's ctx bounds how long the external check may take
+func (s *VideoAvailabilityService) CheckVideo(ctx context.Context, id int64) (*models.VideoAvailabilityResponse, error) {
+	video, err := s.videoRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVideoNotFound
+		}
+		return nil, fmt.Errorf("failed to get video: %w", err)
+	}
+
+	availability, err := s.checker.Check(ctx, video.VideoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	checkedAt, err := s.recordAvailability(ctx, video, availability)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.VideoAvailabilityResponse{
+		ID:            id,
+		Availability:  availability,
+		LastCheckedAt: checkedAt,
+	}, nil
+}
+
+// CheckDue checks one batch of videos whose last check is missing or stale
+// Inconclusive checks are skipped and stay due, so they are retried next run
+func (s *VideoAvailabilityService) CheckDue(ctx context.Context) (int, error) {
+	videos, err := s.videoRepo.FindDueForCheck(ctx, time.Now().Add(-videoRecheckAfter), videoCheckBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get videos due for check: %w", err)
+	}
+
+	checked := 0
+	for _, video := range videos {
+		availability, err := s.checker.Check(ctx, video.VideoURL)
+		if err != nil {
+			log.Printf("Warning: availability check for video %d: %v", video.ID, err)
+			continue
+		}
+		if _, err := s.recordAvailability(ctx, &video, availability); err != nil {
+			return checked, err
+		}
+		checked++
+	}
+	return checked, nil
+}
+
+// recordAvailability stores a check result and, if the availability changed,
+// invalidates the cached dictionaries of the video's trick
+func (s *VideoAvailabilityService) recordAvailability(ctx context.Context, video *models.TrickVideo, availability string) (time.Time, error) {
+	checkedAt, err := s.videoRepo.UpdateAvailability(ctx, video.ID, availability)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to record video availability: %w", err)
+	}
+
+	if availability != video.Availability {
+		slug, err := s.videoRepo.GetTrickSlug(ctx, video.TrickID)
+		if err != nil {
+			// The result is stored - a stale dictionary only lives until its TTL
+			log.Printf("Warning: could not invalidate dictionary for video %d: %v", video.ID, err)
+		} else {
+			s.dictionaryCache.InvalidateTrick(slug)
+		}
+	}
+
+	return checkedAt, nil
+}
+
+// Run checks due videos on an interval until ctx is cancelled
+func (s *VideoAvailabilityService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.CheckDue(ctx); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
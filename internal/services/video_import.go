@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/sanitize"
+)
+
+// =============================================================================
+// BULK VIDEO IMPORT
+// =============================================================================
+// Rows map a trick slug to a video link (typically a spreadsheet export).
+// Unlike the trick import this is a plain JSON body - a few hundred rows fit
+// comfortably in memory, and every row must be seen before any is written:
+//
+//  1. Validate and normalize each row (bad rows are reported, not fatal)
+//  2. Resolve every slug in one query, then find already-stored URLs in one more
+//  3. Decide the featured video per trick: the LAST featured row wins, earlier
+//     ones are added unfeatured and report which row demoted them
+//  4. Insert in chunks of videoImportChunkSize, one transaction per chunk
+//
+// A failed chunk stops the import; earlier chunks stay committed and their
+// rows are reported as created.
+
+// Bulk video import limits
+const (
+	// MaxVideoImportRows bounds one request (matches VideoImportRequest's binding)
+	MaxVideoImportRows = 1000
+
+	// videoImportChunkSize is how many videos go into one insert transaction
+	videoImportChunkSize = 200
+
+	// MaxVideoLabelLength bounds a video's label (in characters)
+	MaxVideoLabelLength = 100
+)
+
+// Per-row video errors - reported on the row's result (CreateVideo uses them too)
+var (
+	ErrInvalidVideoLabel    = errors.New("video label must be 1-100 characters")
+	ErrInvalidPerformerName = errors.New("performer name is required")
+	ErrThumbnailRequired    = errors.New("thumbnail URL is required for this video host")
+	ErrDuplicateVideo       = errors.New("trick already has a video with this URL")
+)
+
+// youTubeID is the shape of a YouTube video ID
+var youTubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// vimeoID is the shape of a Vimeo video ID
+var vimeoID = regexp.MustCompile(`^[0-9]+$`)
+
+// VideoImportEmitter receives one result per row, in row order
+// rowErr is the row's error (nil for created rows); the caller turns it into
+// the result's Code/Error and collects the results.
+type VideoImportEmitter func(result models.VideoImportResult, rowErr error)
+
+// videoImportRow is a row that passed validation, waiting to be written
+type videoImportRow struct {
+	result models.VideoImportResult
+	err    error
+	video  models.TrickVideo
+	valid  bool
+}
+
+// ImportVideos adds the videos in rows to their tricks
+// The summary is always returned - with an error, it covers the chunks
+// written before the import stopped.
+func (s *AdminService) ImportVideos(ctx context.Context, rows []models.VideoImportRow, uploadedBy uuid.UUID, emit VideoImportEmitter) (*models.VideoImportSummary, error) {
+	summary := &models.VideoImportSummary{Rows: len(rows)}
+	entries := make([]videoImportRow, len(rows))
+
+	// Step 1: validate and normalize
+	slugs := make([]string, 0, len(rows))
+	for i, row := range rows {
+		entry := &entries[i]
+		entry.result = models.VideoImportResult{Row: i + 1, TrickSlug: strings.ToLower(strings.TrimSpace(row.TrickSlug))}
+		entry.video, entry.err = parseVideoImportRow(row, s.allowHTTP)
+		entry.video.UploadedBy = uploadedBy
+		entry.result.VideoURL = entry.video.VideoURL
+		if entry.err == nil {
+			entry.valid = true
+			slugs = append(slugs, entry.result.TrickSlug)
+		}
+	}
+
+	// Step 2: slugs -> trick IDs, then drop duplicates (stored, or earlier in this import)
+	trickIDs, err := s.videoRepo.ResolveTrickSlugs(ctx, slugs)
+	if err != nil {
+		return summary, fmt.Errorf("failed to resolve tricks: %w", err)
+	}
+	keys := make([]repository.VideoKey, 0, len(slugs))
+	for i := range entries {
+		entry := &entries[i]
+		if !entry.valid {
+			continue
+		}
+		id, ok := trickIDs[entry.result.TrickSlug]
+		if !ok {
+			entry.valid, entry.err = false, ErrTrickNotFound
+			continue
+		}
+		entry.video.TrickID = id
+		keys = append(keys, repository.VideoKey{TrickID: id, URL: entry.video.VideoURL})
+	}
+
+	existing, err := s.videoRepo.FindExistingURLs(ctx, keys)
+	if err != nil {
+		return summary, fmt.Errorf("failed to check existing videos: %w", err)
+	}
+	for i := range entries {
+		entry := &entries[i]
+		if !entry.valid {
+			continue
+		}
+		key := repository.VideoKey{TrickID: entry.video.TrickID, URL: entry.video.VideoURL}
+		if existing[key] {
+			entry.valid, entry.err = false, ErrDuplicateVideo
+			continue
+		}
+		existing[key] = true
+	}
+
+	// Step 3: one featured video per trick - walking backwards, the first featured row seen wins
+	featuredRow := make(map[int]int)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := &entries[i]
+		if !entry.valid || !entry.video.IsFeatured {
+			continue
+		}
+		if winner, ok := featuredRow[entry.video.TrickID]; ok {
+			entry.video.IsFeatured = false
+			entry.result.DemotedBy = &winner
+			summary.Demoted++
+			continue
+		}
+		featuredRow[entry.video.TrickID] = entry.result.Row
+	}
+
+	// Step 4: write valid rows in chunks; results go out in row order
+	emitted := 0
+	flush := func(upTo int, chunk []*videoImportRow) error {
+		if len(chunk) > 0 {
+			videos := make([]models.TrickVideo, len(chunk))
+			for i, entry := range chunk {
+				videos[i] = entry.video
+			}
+			outcomes, err := s.videoRepo.InsertImported(ctx, videos)
+			if err != nil {
+				return fmt.Errorf("failed to import videos: %w", err)
+			}
+			for i, entry := range chunk {
+				entry.video = videos[i]
+				switch {
+				case errors.Is(outcomes[i], repository.ErrNotFound):
+					entry.err = ErrTrickNotFound
+				case errors.Is(outcomes[i], repository.ErrDuplicateVideo):
+					entry.err = ErrDuplicateVideo
+				default:
+					entry.result.VideoID = &entry.video.ID
+					entry.result.IsFeatured = entry.video.IsFeatured
+					s.dictionaryCache.InvalidateTrick(entry.result.TrickSlug)
+				}
+			}
+		}
+
+		for ; emitted < upTo; emitted++ {
+			entry := &entries[emitted]
+			entry.result.Status = videoImportStatus(entry.err)
+			countVideoImportResult(summary, entry.result)
+			emit(entry.result, entry.err)
+		}
+		return nil
+	}
+
+	chunk := make([]*videoImportRow, 0, videoImportChunkSize)
+	for i := range entries {
+		if entries[i].valid {
+			chunk = append(chunk, &entries[i])
+		}
+		if len(chunk) == videoImportChunkSize {
+			if err := flush(i+1, chunk); err != nil {
+				return summary, err
+			}
+			chunk = chunk[:0]
+		}
+	}
+	if err := flush(len(entries), chunk); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// parseVideoImportRow validates one row, sanitizing text and normalizing URLs like CreateVideo
+// The returned video carries its normalized URL whenever that much was valid.
+func parseVideoImportRow(row models.VideoImportRow, allowHTTP bool) (models.TrickVideo, error) {
+	var video models.TrickVideo
+	if !importSlug.MatchString(strings.ToLower(strings.TrimSpace(row.TrickSlug))) {
+		return video, ErrTrickNotFound
+	}
+
+	videoURL, thumbnailURL, err := NormalizeVideoURL(row.VideoURL, allowHTTP)
+	if err != nil {
+		return video, err
+	}
+	video.VideoURL = videoURL
+
+	if strings.TrimSpace(row.ThumbnailURL) != "" {
+		if thumbnailURL, err = sanitize.URL(row.ThumbnailURL, allowHTTP); err != nil {
+			return video, ErrInvalidVideoURL
+		}
+	}
+	if thumbnailURL == "" {
+		return video, ErrThumbnailRequired
+	}
+	video.ThumbnailURL = thumbnailURL
+
+	video.PerformerName = sanitize.Text(row.PerformerName)
+	if video.PerformerName == "" {
+		return video, ErrInvalidPerformerName
+	}
+	if video.Label, err = videoLabel(row.Label); err != nil {
+		return video, err
+	}
+
+	video.IsFeatured = row.IsFeatured
+	video.Attribution = sanitize.OptionalText(row.Attribution)
+	video.License = sanitize.OptionalText(row.License)
+	return video, nil
+}
+
+// videoLabel sanitizes an optional label and checks its length
+func videoLabel(raw *string) (*string, error) {
+	label := sanitize.OptionalText(raw)
+	if raw != nil && (label == nil || utf8.RuneCountInString(*label) > MaxVideoLabelLength) {
+		return nil, ErrInvalidVideoLabel
+	}
+	return label, nil
+}
+
+// NormalizeVideoURL validates a video URL and rewrites known hosts to one canonical form
+// so the same video can't be stored twice under different URLs:
+//
+//	youtu.be/ID, m.youtube.com/watch?v=ID, youtube.com/shorts/ID, /embed/ID, /live/ID
+//	  -> https://www.youtube.com/watch?v=ID  (thumbnail: i.ytimg.com/vi/ID/hqdefault.jpg)
+//	player.vimeo.com/video/ID -> https://vimeo.com/ID
+//
+// thumbnailURL is empty unless it can be derived from the URL. Other hosts only
+// get sanitize.URL's normalization.
+func NormalizeVideoURL(raw string, allowHTTP bool) (videoURL, thumbnailURL string, err error) {
+	cleaned, err := sanitize.URL(raw, allowHTTP)
+	if err != nil {
+		return "", "", ErrInvalidVideoURL
+	}
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return "", "", ErrInvalidVideoURL
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch host {
+	case "youtube.com", "m.youtube.com", "youtu.be":
+		id := ""
+		switch {
+		case host == "youtu.be":
+			id = segments[0]
+		case len(segments) == 1 && segments[0] == "watch":
+			id = u.Query().Get("v")
+		case len(segments) == 2 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live"):
+			id = segments[1]
+		}
+		if !youTubeID.MatchString(id) {
+			return "", "", ErrInvalidVideoURL
+		}
+		return "https://www.youtube.com/watch?v=" + id, "https://i.ytimg.com/vi/" + id + "/hqdefault.jpg", nil
+
+	case "vimeo.com", "player.vimeo.com":
+		id := segments[len(segments)-1]
+		if host == "player.vimeo.com" && (len(segments) != 2 || segments[0] != "video") {
+			id = ""
+		}
+		if vimeoID.MatchString(id) {
+			return "https://vimeo.com/" + id, "", nil
+		}
+	}
+	return cleaned, "", nil
+}
+
+// videoImportStatus is the result status for a row's error
+func videoImportStatus(err error) string {
+	switch {
+	case err == nil:
+		return models.ImportCreated
+	case errors.Is(err, ErrDuplicateVideo):
+		return models.ImportSkipped
+	default:
+		return models.ImportInvalid
+	}
+}
+
+// countVideoImportResult adds one row's outcome to the summary counts
+func countVideoImportResult(summary *models.VideoImportSummary, result models.VideoImportResult) {
+	switch result.Status {
+	case models.ImportCreated:
+		summary.Created++
+	case models.ImportSkipped:
+		summary.Skipped++
+	default:
+		summary.Invalid++
+	}
+}
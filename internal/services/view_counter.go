@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	"tricking-api/internal/repository"
+)
+
+// viewCounterShards is how many independent locks the counter spreads views over
+// More shards = less lock contention when many requests record views at once
+const viewCounterShards = 32
+
+// viewShard holds pending view counts for the slugs that hash to it
+type viewShard struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// ViewCounter accumulates trick views in memory and flushes them in batches
+//
+// CONCURRENCY:
+//   - Record is called on every request, so it only locks one shard
+//   - Flush sends RELATIVE increments (view_count = view_count + n), so several
+//     API instances can flush to the same database without losing views
+type ViewCounter struct {
+	trickRepo repository.TrickRepositoryInterface
+	shards    [viewCounterShards]viewShard
+}
+
+// NewViewCounter creates a new ViewCounter instance
+func NewViewCounter(trickRepo repository.TrickRepositoryInterface) *ViewCounter {
+	vc := &ViewCounter{trickRepo: trickRepo}
+	for i := range vc.shards {
+		vc.shards[i].counts = make(map[string]int64)
+	}
+	return vc
+}
+
+// Record adds one view for a trick
+func (vc *ViewCounter) Record(trickID string) {
+	shard := vc.shardFor(trickID)
+	shard.mu.Lock()
+	shard.counts[trickID]++
+	shard.mu.Unlock()
+}
+
+// Flush writes all pending views to the database
+// If the write fails, the views are put back so the next flush retries them
+func (vc *ViewCounter) Flush(ctx context.Context) error {
+	pending := vc.drain()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := vc.trickRepo.IncrementViewCounts(ctx, pending); err != nil {
+		vc.restore(pending)
+		return err
+	}
+	return nil
+}
+
+// Run flushes on an interval until ctx is cancelled, then flushes one last time
+func (vc *ViewCounter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := vc.Flush(ctx); err != nil {
+				log.Printf("Warning: failed to flush trick views: %v", err)
+			}
+		case <-ctx.Done():
+			// ctx is already cancelled, so give the final flush its own deadline
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := vc.Flush(flushCtx); err != nil {
+				log.Printf("Warning: failed to flush trick views on shutdown: %v", err)
+			}
+			cancel()
+			return
+		}
+	}
+}
+
+// =============================================================================
+// PRIVATE HELPER METHODS
+// =============================================================================
+
+// shardFor picks the shard a trick's views are stored in
+func (vc *ViewCounter) shardFor(trickID string) *viewShard {
+	h := fnv.New32a()
+	h.Write([]byte(trickID))
+	return &vc.shards[h.Sum32()%viewCounterShards]
+}
+
+// drain swaps out every shard's map and merges them into one
+func (vc *ViewCounter) drain() map[string]int64 {
+	pending := make(map[string]int64)
+	for i := range vc.shards {
+		shard := &vc.shards[i]
+		shard.mu.Lock()
+		counts := shard.counts
+		shard.counts = make(map[string]int64)
+		shard.mu.Unlock()
+
+		for id, n := range counts {
+			pending[id] += n
+		}
+	}
+	return pending
+}
+
+// restore adds counts back after a failed flush
+func (vc *ViewCounter) restore(counts map[string]int64) {
+	for id, n := range counts {
+		shard := vc.shardFor(id)
+		shard.mu.Lock()
+		shard.counts[id] += n
+		shard.mu.Unlock()
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"tricking-api/internal/repository"
+)
+
+// fakeViewRepo records flushed view counts and can be told to fail flushes
+type fakeViewRepo struct {
+	repository.TrickRepositoryInterface
+
+	mu       sync.Mutex
+	totals   map[string]int64
+	calls    int
+	failEach int // every failEach-th flush fails; 0 never fails
+}
+
+func (r *fakeViewRepo) IncrementViewCounts(ctx context.Context, counts map[string]int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+	if r.failEach > 0 && r.calls%r.failEach == 0 {
+		return errors.New("flush failed")
+	}
+	for id, n := range counts {
+		r.totals[id] += n
+	}
+	return nil
+}
+
+func (r *fakeViewRepo) stopFailing() {
+	r.mu.Lock()
+	r.failEach = 0
+	r.mu.Unlock()
+}
+
+func TestViewCounterConcurrentFlush(t *testing.T) {
+	tests := []struct {
+		name       string
+		goroutines int
+		views      int
+		tricks     int
+		failEach   int
+	}{
+		{name: "single trick", goroutines: 32, views: 500, tricks: 1},
+		{name: "many tricks", goroutines: 32, views: 500, tricks: 50},
+		{name: "every other flush fails", goroutines: 32, views: 500, tricks: 50, failEach: 2},
+		{name: "every flush fails until the end", goroutines: 16, views: 200, tricks: 10, failEach: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeViewRepo{totals: make(map[string]int64), failEach: tt.failEach}
+			vc := NewViewCounter(repo)
+			ctx := context.Background()
+
+			// Flush continuously while the recorders run, failing as configured
+			stop := make(chan struct{})
+			flusherDone := make(chan struct{})
+			go func() {
+				defer close(flusherDone)
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						vc.Flush(ctx)
+					}
+				}
+			}()
+
+			var wg sync.WaitGroup
+			for g := 0; g < tt.goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < tt.views; i++ {
+						vc.Record(fmt.Sprintf("trick-%d", (g+i)%tt.tricks))
+					}
+				}(g)
+			}
+			wg.Wait()
+			close(stop)
+			<-flusherDone
+
+			// Final flush succeeds and must pick up anything restored after a failure
+			repo.stopFailing()
+			if err := vc.Flush(ctx); err != nil {
+				t.Fatalf("final Flush() error = %v", err)
+			}
+
+			var total int64
+			for _, n := range repo.totals {
+				total += n
+			}
+			if want := int64(tt.goroutines * tt.views); total != want {
+				t.Errorf("flushed %d views, want %d", total, want)
+			}
+			if len(repo.totals) != tt.tricks {
+				t.Errorf("flushed %d tricks, want %d", len(repo.totals), tt.tricks)
+			}
+
+			// Nothing may be left behind to be flushed twice
+			before := repo.calls
+			if err := vc.Flush(ctx); err != nil {
+				t.Fatalf("empty Flush() error = %v", err)
+			}
+			if repo.calls != before {
+				t.Errorf("empty Flush() called the repository")
+			}
+		})
+	}
+}
+
+func TestViewCounterFailedFlushRestores(t *testing.T) {
+	repo := &fakeViewRepo{totals: make(map[string]int64), failEach: 1}
+	vc := NewViewCounter(repo)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		vc.Record("backflip")
+	}
+	vc.Record("cork")
+
+	if err := vc.Flush(ctx); err == nil {
+		t.Fatal("Flush() error = nil, want failure")
+	}
+	vc.Record("backflip")
+
+	repo.stopFailing()
+	if err := vc.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := map[string]int64{"backflip": 4, "cork": 1}
+	for id, n := range want {
+		if repo.totals[id] != n {
+			t.Errorf("totals[%q] = %d, want %d", id, repo.totals[id], n)
+		}
+	}
+}
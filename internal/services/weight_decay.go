@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"tricking-api/internal/config"
+	"tricking-api/internal/repository"
+)
+
+// TrickWeightDecayer lowers the combo-generation weight of stale tricks
+//
+// A trick is stale when it is older than the configured window, has no videos,
+// and hasn't been viewed within the window. Stale tricks get the configured
+// weight_modifier; everything else is set back to 1.0. The curated weight
+// column is never changed, and adding a video resets the modifier immediately
+// (see VideoRepository.Create) rather than waiting for the next run.
+type TrickWeightDecayer struct {
+	trickRepo repository.TrickRepositoryInterface
+	rule      config.WeightDecayConfig
+}
+
+// NewTrickWeightDecayer creates a new TrickWeightDecayer instance
+func NewTrickWeightDecayer(trickRepo repository.TrickRepositoryInterface, rule config.WeightDecayConfig) *TrickWeightDecayer {
+	return &TrickWeightDecayer{
+		trickRepo: trickRepo,
+		rule:      rule,
+	}
+}
+
+// Apply recomputes every trick's modifier now
+// Returns how many tricks changed (newly decayed or recovered)
+func (d *TrickWeightDecayer) Apply(ctx context.Context) (int64, error) {
+	changed, err := d.trickRepo.ApplyWeightDecay(ctx, time.Now().Add(-d.rule.After), d.rule.Modifier)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply trick weight decay: %w", err)
+	}
+	return changed, nil
+}
+
+// Run applies the decay rule on an interval until ctx is cancelled
+func (d *TrickWeightDecayer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := d.Apply(ctx)
+			if err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			if changed > 0 {
+				log.Printf("Updated weight modifier on %d tricks", changed)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
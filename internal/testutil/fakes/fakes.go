@@ -0,0 +1,76 @@
+// =============================================================================
+// FILE: internal/testutil/fakes/fakes.go
+// PURPOSE: In-memory repositories shared by the service and handler tests
+// =============================================================================
+//
+// Each fake embeds the repository interface it stands in for and implements
+// only the methods its tests reach - anything else panics on the nil
+// interface, which is how a test finds out it hit the database.
+//
+// Most fakes stay next to the one test that needs them; a fake moves here
+// once two packages would otherwise carry their own copy.
+// =============================================================================
+
+package fakes
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+	"tricking-api/internal/repository"
+	"tricking-api/internal/testutil/fixtures"
+)
+
+// ImportTrickRepo accepts every imported trick as new
+// Names and slugs in Taken (lowercase) belong to other tricks or aliases.
+// Weights, when not nil, records each written trick's weight by slug; the
+// counts are kept either way (a large import only needs those).
+type ImportTrickRepo struct {
+	repository.TrickRepositoryInterface
+
+	Taken   map[string]bool
+	Weights map[string]int16
+
+	Batches  int
+	Imported int
+	MaxBatch int
+}
+
+func (r *ImportTrickRepo) ExistsByName(ctx context.Context, name, exceptSlug string) (bool, error) {
+	return r.Taken[strings.ToLower(name)], nil
+}
+
+func (r *ImportTrickRepo) ExistsBySlug(ctx context.Context, slug, exceptSlug string) (bool, error) {
+	return r.Taken[slug], nil
+}
+
+func (r *ImportTrickRepo) GetBySlugs(ctx context.Context, slugs []string) ([]models.Trick, error) {
+	return nil, nil
+}
+
+func (r *ImportTrickRepo) UpsertImported(ctx context.Context, tricks []repository.ImportedTrick, changedBy *uuid.UUID) ([]repository.ImportOutcome, error) {
+	r.Batches++
+	r.Imported += len(tricks)
+	r.MaxBatch = max(r.MaxBatch, len(tricks))
+
+	outcomes := make([]repository.ImportOutcome, len(tricks))
+	for i, trick := range tricks {
+		if r.Weights != nil {
+			r.Weights[trick.Slug] = trick.Weight
+		}
+		outcomes[i] = repository.ImportOutcome{Status: models.ImportCreated, FlipID: trick.FlipID}
+	}
+	return outcomes, nil
+}
+
+// CategoryRepo serves the fixture categories (fixtures.CatalogCategories)
+type CategoryRepo struct {
+	repository.CategoryRepositoryInterface
+}
+
+func (r *CategoryRepo) FindAll(ctx context.Context) ([]models.Category, error) {
+	return fixtures.CatalogCategories(), nil
+}
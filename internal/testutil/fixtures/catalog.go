@@ -0,0 +1,139 @@
+package fixtures
+
+import (
+	"tricking-api/internal/models"
+)
+
+// =============================================================================
+// CATALOG
+// =============================================================================
+// A small but realistic catalog: the stances and categories tricks point at,
+// and ~30 tricks with the difficulties, stances and weights a real database
+// would hold. Landings chain into takeoffs the way they do on the mat, so
+// comboalg's flow and balanced strategies have real choices to make.
+//
+// Every call returns fresh slices - a test may modify its copy freely.
+
+// Stance IDs used by CatalogStances and CatalogTricks
+const (
+	StanceComplete = 1 // Lands on the left leg
+	StanceHyper    = 2 // Lands on the right leg
+	StanceMega     = 3 // Lands on the left leg, swinging through
+	StanceSemi     = 4 // Lands on the right leg, swinging through
+	StanceTwoFeet  = 5 // Lands on both feet
+	StancePunch    = 6 // Takes off from both feet
+)
+
+// Category IDs used by CatalogCategories and CatalogTricks (tricks.flip_id)
+const (
+	CategoryKicks       = 1
+	CategoryFlips       = 2
+	CategoryTwists      = 3
+	CategoryTransitions = 4
+)
+
+// CatalogStances returns the stances the catalog tricks use
+func CatalogStances() []models.Stance {
+	return []models.Stance{
+		{ID: StanceComplete, Name: "Complete", Leg: models.LegLeft},
+		{ID: StanceHyper, Name: "Hyper", Leg: models.LegRight},
+		{ID: StanceMega, Name: "Mega", Leg: models.LegLeft},
+		{ID: StanceSemi, Name: "Semi", Leg: models.LegRight},
+		{ID: StanceTwoFeet, Name: "Two Feet", Leg: models.LegBoth},
+		{ID: StancePunch, Name: "Punch", Leg: models.LegBoth},
+	}
+}
+
+// CatalogLegs maps each catalog stance to its leg, the shape comboalg.Options.Legs takes
+func CatalogLegs() map[int]string {
+	legs := make(map[int]string)
+	for _, stance := range CatalogStances() {
+		legs[stance.ID] = stance.Leg
+	}
+	return legs
+}
+
+// CatalogCategories returns the categories the catalog tricks use
+func CatalogCategories() []models.Category {
+	return []models.Category{
+		{ID: CategoryKicks, Name: "Kicks"},
+		{ID: CategoryFlips, Name: "Flips"},
+		{ID: CategoryTwists, Name: "Twists"},
+		{ID: CategoryTransitions, Name: "Transitions"},
+	}
+}
+
+// catalogEntry is one row of catalogTable
+type catalogEntry struct {
+	slug, name        string
+	difficulty        int64
+	takeoff, landing  int
+	category, degrees int
+	weight            int16
+}
+
+// catalogTable is ordered by difficulty
+var catalogTable = []catalogEntry{
+	{"tornado-kick", "Tornado Kick", 1, StanceComplete, StanceComplete, CategoryKicks, 360, 8},
+	{"skip-hook", "Skip Hook", 1, StanceComplete, StanceHyper, CategoryKicks, 0, 6},
+	{"pop-360", "Pop 360", 1, StancePunch, StanceTwoFeet, CategoryTwists, 360, 5},
+	{"vanish", "Vanish", 1, StanceComplete, StanceMega, CategoryTransitions, 180, 4},
+	{"swing-through", "Swing Through", 1, StanceHyper, StanceSemi, CategoryTransitions, 0, 4},
+	{"backflip", "Backflip", 2, StancePunch, StanceTwoFeet, CategoryFlips, 0, 8},
+	{"frontflip", "Frontflip", 2, StancePunch, StanceTwoFeet, CategoryFlips, 0, 6},
+	{"aerial", "Aerial", 2, StanceComplete, StanceComplete, CategoryFlips, 0, 7},
+	{"540-kick", "540 Kick", 2, StanceComplete, StanceHyper, CategoryKicks, 540, 9},
+	{"butterfly-kick", "Butterfly Kick", 2, StanceComplete, StanceComplete, CategoryKicks, 0, 7},
+	{"webster", "Webster", 3, StanceComplete, StanceComplete, CategoryFlips, 0, 5},
+	{"gainer", "Gainer", 3, StanceComplete, StanceComplete, CategoryFlips, 0, 6},
+	{"raiz", "Raiz", 3, StanceComplete, StanceHyper, CategoryKicks, 0, 4},
+	{"720-kick", "720 Kick", 3, StanceHyper, StanceComplete, CategoryKicks, 720, 5},
+	{"butterfly-twist", "Butterfly Twist", 4, StanceComplete, StanceComplete, CategoryTwists, 360, 7},
+	{"cork", "Cork", 4, StanceComplete, StanceComplete, CategoryTwists, 360, 8},
+	{"btwist-round", "B-Twist Round", 4, StanceComplete, StanceHyper, CategoryTwists, 360, 3},
+	{"full", "Full", 4, StancePunch, StanceTwoFeet, CategoryTwists, 360, 5},
+	{"arabian", "Arabian", 4, StancePunch, StanceTwoFeet, CategoryFlips, 180, 4},
+	{"double-leg", "Double Leg", 5, StanceComplete, StanceHyper, CategoryKicks, 540, 5},
+	{"gainer-switch", "Gainer Switch", 5, StanceComplete, StanceHyper, CategoryFlips, 0, 5},
+	{"cheat-720", "Cheat 720", 5, StanceComplete, StanceComplete, CategoryKicks, 720, 4},
+	{"snapuswipe", "Snapuswipe", 5, StancePunch, StanceHyper, CategoryKicks, 180, 3},
+	{"corkscrew", "Corkscrew", 6, StanceComplete, StanceHyper, CategoryTwists, 360, 5},
+	{"gumbi", "Gumbi", 6, StanceHyper, StanceSemi, CategoryKicks, 540, 3},
+	{"double-full", "Double Full", 7, StancePunch, StanceTwoFeet, CategoryTwists, 720, 3},
+	{"double-btwist", "Double B-Twist", 7, StanceComplete, StanceComplete, CategoryTwists, 720, 3},
+	{"cork-double-kick", "Cork Double Kick", 8, StanceComplete, StanceHyper, CategoryKicks, 360, 2},
+	{"double-cork", "Double Cork", 8, StanceComplete, StanceComplete, CategoryTwists, 720, 3},
+	{"triple-cork", "Triple Cork", 10, StanceComplete, StanceComplete, CategoryTwists, 1080, 1},
+}
+
+// CatalogTricks returns the catalog tricks, easiest first
+// Every trick has a difficulty, both stances, a category and a weight; rotation
+// is left NULL for tricks that don't rotate. Each slug is also the trick's ID.
+func CatalogTricks() []models.Trick {
+	tricks := make([]models.Trick, len(catalogTable))
+	for i, entry := range catalogTable {
+		builder := Trick().
+			WithSlug(entry.slug).
+			WithName(entry.name).
+			WithDifficulty(entry.difficulty).
+			WithStances(entry.takeoff, entry.landing).
+			WithFlip(entry.category).
+			WithWeight(entry.weight, 1)
+		if entry.degrees > 0 {
+			builder = builder.WithRotation(entry.degrees)
+		}
+		tricks[i] = builder.Build()
+	}
+	return tricks
+}
+
+// CatalogTrick returns the catalog trick with the given slug
+// It panics on an unknown slug, so a typo fails the test that made it.
+func CatalogTrick(slug string) models.Trick {
+	for _, trick := range CatalogTricks() {
+		if trick.Slug == slug {
+			return trick
+		}
+	}
+	panic("fixtures: no catalog trick " + slug)
+}
@@ -0,0 +1,313 @@
+// =============================================================================
+// FILE: internal/testutil/fixtures/fixtures.go
+// PURPOSE: Deterministic model builders for tests
+// =============================================================================
+//
+// Building a models.Trick by hand means a pointer helper for every nullable
+// field. The builders here start from a valid, fully deterministic value (no
+// clock, no randomness, no counters) and each With... call sets one field:
+//
+//	trick := fixtures.Trick().WithDifficulty(5).WithStances(1, 2).Build()
+//	video := fixtures.Video().ForTrick(7).Featured().Build()
+//	combo := fixtures.Combo().WithName("Warmup").Shared().Build()
+//
+// Builders are values, so a partly configured one can be reused as a template:
+// each With... returns a copy and never changes the builder it was called on.
+// Catalog (catalog.go) is a canned set of realistic tricks and stances.
+// =============================================================================
+
+package fixtures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"tricking-api/internal/models"
+)
+
+// Epoch is the timestamp every fixture is created at, unless overridden
+var Epoch = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// OwnerID is the user every fixture combo and video belongs to, unless overridden
+var OwnerID = uuid.MustParse("00000000-0000-4000-8000-000000000001")
+
+// Ptr returns a pointer to v - for the nullable model fields
+// fixtures.Ptr(int64(5)), fixtures.Ptr("notes"), fixtures.Ptr(fixtures.Epoch)
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// =============================================================================
+// TRICKS
+// =============================================================================
+
+// TrickBuilder builds a models.Trick
+type TrickBuilder struct {
+	trick models.Trick
+}
+
+// Trick starts a trick with slug "test-trick", weight 1 and no difficulty or stances
+func Trick() TrickBuilder {
+	return TrickBuilder{trick: models.Trick{
+		ID:             "test-trick",
+		Slug:           "test-trick",
+		Name:           "Test Trick",
+		Weight:         1,
+		WeightModifier: 1,
+		CreatedAt:      Ptr(Epoch),
+		UpdatedAt:      Ptr(Epoch),
+	}}
+}
+
+// WithSlug sets the slug, which is also the trick's public ID
+func (b TrickBuilder) WithSlug(slug string) TrickBuilder {
+	b.trick.ID, b.trick.Slug = slug, slug
+	return b
+}
+
+// WithName sets the display name
+func (b TrickBuilder) WithName(name string) TrickBuilder {
+	b.trick.Name = name
+	return b
+}
+
+// WithDifficulty sets the difficulty rating
+func (b TrickBuilder) WithDifficulty(difficulty int64) TrickBuilder {
+	b.trick.Difficulty = Ptr(difficulty)
+	return b
+}
+
+// WithStances sets the takeoff and landing stance IDs
+func (b TrickBuilder) WithStances(takeoff, landing int) TrickBuilder {
+	b.trick.TakeoffStanceID, b.trick.LandingStanceID = Ptr(takeoff), Ptr(landing)
+	return b
+}
+
+// WithFlip sets the flip family
+func (b TrickBuilder) WithFlip(flipID int) TrickBuilder {
+	b.trick.FlipID = Ptr(flipID)
+	return b
+}
+
+// WithRotation sets the degrees of rotation
+func (b TrickBuilder) WithRotation(degrees int) TrickBuilder {
+	b.trick.Rotation = Ptr(degrees)
+	return b
+}
+
+// WithWeight sets the combo selection weight and its decay modifier
+func (b TrickBuilder) WithWeight(weight int16, modifier float64) TrickBuilder {
+	b.trick.Weight, b.trick.WeightModifier = weight, modifier
+	return b
+}
+
+// WithDescription sets the description and execution notes ("" leaves one NULL)
+func (b TrickBuilder) WithDescription(description, executionNotes string) TrickBuilder {
+	b.trick.Description, b.trick.ExecutionNotes = optional(description), optional(executionNotes)
+	return b
+}
+
+// WithAttribution sets the attribution and license ("" leaves one NULL)
+func (b TrickBuilder) WithAttribution(attribution, license string) TrickBuilder {
+	b.trick.Attribution, b.trick.License = optional(attribution), optional(license)
+	return b
+}
+
+// WithAliases sets the alternate names
+func (b TrickBuilder) WithAliases(aliases ...string) TrickBuilder {
+	b.trick.Aliases = aliases
+	return b
+}
+
+// WithTags sets the tags
+func (b TrickBuilder) WithTags(tags ...string) TrickBuilder {
+	b.trick.Tags = tags
+	return b
+}
+
+// CreatedAt sets both timestamps
+func (b TrickBuilder) CreatedAt(at time.Time) TrickBuilder {
+	b.trick.CreatedAt, b.trick.UpdatedAt = Ptr(at), Ptr(at)
+	return b
+}
+
+// Build returns the trick
+// Slices are copied, so changing the result never changes the builder.
+func (b TrickBuilder) Build() models.Trick {
+	trick := b.trick
+	trick.Aliases = clone(trick.Aliases)
+	trick.Tags = clone(trick.Tags)
+	return trick
+}
+
+// =============================================================================
+// VIDEOS
+// =============================================================================
+
+// VideoBuilder builds a models.TrickVideo
+type VideoBuilder struct {
+	video models.TrickVideo
+}
+
+// Video starts an unfeatured video with ID 1 for trick 1, availability unknown
+func Video() VideoBuilder {
+	return VideoBuilder{video: models.TrickVideo{
+		ID:            1,
+		TrickID:       1,
+		VideoURL:      "https://videos.example.com/1.mp4",
+		ThumbnailURL:  "https://videos.example.com/1.jpg",
+		UploadedBy:    OwnerID,
+		PerformerName: "Test Performer",
+		CreatedAt:     Epoch,
+		Availability:  models.VideoAvailabilityUnknown,
+	}}
+}
+
+// WithID sets the video ID
+func (b VideoBuilder) WithID(id int64) VideoBuilder {
+	b.video.ID = id
+	return b
+}
+
+// ForTrick sets the internal ID of the trick the video shows
+func (b VideoBuilder) ForTrick(trickID int) VideoBuilder {
+	b.video.TrickID = trickID
+	return b
+}
+
+// WithURL sets the video and thumbnail URLs
+func (b VideoBuilder) WithURL(videoURL, thumbnailURL string) VideoBuilder {
+	b.video.VideoURL, b.video.ThumbnailURL = videoURL, thumbnailURL
+	return b
+}
+
+// Featured marks the video as its trick's featured video
+func (b VideoBuilder) Featured() VideoBuilder {
+	b.video.IsFeatured = true
+	return b
+}
+
+// WithAvailability sets the availability and when it was checked
+func (b VideoBuilder) WithAvailability(availability string, checkedAt time.Time) VideoBuilder {
+	b.video.Availability, b.video.LastCheckedAt = availability, Ptr(checkedAt)
+	return b
+}
+
+// WithLabel sets the caption
+func (b VideoBuilder) WithLabel(label string) VideoBuilder {
+	b.video.Label = Ptr(label)
+	return b
+}
+
+// Build returns the video
+func (b VideoBuilder) Build() models.TrickVideo {
+	return b.video
+}
+
+// =============================================================================
+// COMBOS
+// =============================================================================
+
+// ComboBuilder builds a models.Combo and, optionally, its trick positions
+type ComboBuilder struct {
+	combo    models.Combo
+	trickIDs []int
+	notes    map[int]string
+}
+
+// Combo starts a private combo with ID 1 owned by OwnerID, with no tricks
+func Combo() ComboBuilder {
+	return ComboBuilder{combo: models.Combo{
+		ID:        1,
+		UserID:    OwnerID,
+		Name:      "Test Combo",
+		CreatedAt: Epoch,
+	}}
+}
+
+// WithID sets the combo ID
+func (b ComboBuilder) WithID(id int64) ComboBuilder {
+	b.combo.ID = id
+	return b
+}
+
+// WithName sets the combo name
+func (b ComboBuilder) WithName(name string) ComboBuilder {
+	b.combo.Name = name
+	return b
+}
+
+// WithNotes sets the notes on the whole combo
+func (b ComboBuilder) WithNotes(notes string) ComboBuilder {
+	b.combo.Notes = Ptr(notes)
+	return b
+}
+
+// OwnedBy sets the owner
+func (b ComboBuilder) OwnedBy(userID uuid.UUID) ComboBuilder {
+	b.combo.UserID = userID
+	return b
+}
+
+// Shared makes the combo visible to other users
+func (b ComboBuilder) Shared() ComboBuilder {
+	b.combo.IsShared = true
+	return b
+}
+
+// Featured marks the combo as picked by an admin
+func (b ComboBuilder) Featured() ComboBuilder {
+	b.combo.IsFeatured = true
+	return b
+}
+
+// WithTricks sets the internal trick IDs, in combo order
+func (b ComboBuilder) WithTricks(trickIDs ...int) ComboBuilder {
+	b.trickIDs = clone(trickIDs)
+	return b
+}
+
+// WithNote sets the note on a position (1-indexed)
+func (b ComboBuilder) WithNote(position int, note string) ComboBuilder {
+	notes := make(map[int]string, len(b.notes)+1)
+	for p, n := range b.notes {
+		notes[p] = n
+	}
+	notes[position] = note
+	b.notes = notes
+	return b
+}
+
+// Build returns the combo
+func (b ComboBuilder) Build() models.Combo {
+	return b.combo
+}
+
+// BuildTricks returns the combo's positions, numbered from 1
+func (b ComboBuilder) BuildTricks() []models.ComboTrick {
+	positions := make([]models.ComboTrick, len(b.trickIDs))
+	for i, trickID := range b.trickIDs {
+		positions[i] = models.ComboTrick{ComboID: b.combo.ID, TrickID: trickID, Position: i + 1}
+		if note, ok := b.notes[i+1]; ok {
+			positions[i].Note = Ptr(note)
+		}
+	}
+	return positions
+}
+
+// optional returns nil for "" - how the nullable text columns come back empty
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// clone copies a slice, keeping nil as nil
+func clone[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append([]T(nil), s...)
+}
@@ -0,0 +1,126 @@
+package fixtures
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCatalogIsConsistent(t *testing.T) {
+	stances := map[int]bool{}
+	for _, stance := range CatalogStances() {
+		stances[stance.ID] = true
+	}
+	categories := map[int]bool{}
+	for _, category := range CatalogCategories() {
+		categories[category.ID] = true
+	}
+
+	tricks := CatalogTricks()
+	if len(tricks) < 25 {
+		t.Fatalf("catalog has %d tricks, want ~30", len(tricks))
+	}
+
+	slugs := map[string]bool{}
+	var lastDifficulty int64
+	for _, trick := range tricks {
+		switch {
+		case slugs[trick.Slug]:
+			t.Errorf("slug %s used twice", trick.Slug)
+		case trick.ID != trick.Slug:
+			t.Errorf("trick %s has ID %q", trick.Slug, trick.ID)
+		case trick.Difficulty == nil || *trick.Difficulty < lastDifficulty:
+			t.Errorf("trick %s breaks the easiest-first order", trick.Slug)
+		case trick.TakeoffStanceID == nil || !stances[*trick.TakeoffStanceID] ||
+			trick.LandingStanceID == nil || !stances[*trick.LandingStanceID]:
+			t.Errorf("trick %s has a stance outside CatalogStances", trick.Slug)
+		case trick.FlipID == nil || !categories[*trick.FlipID]:
+			t.Errorf("trick %s has a category outside CatalogCategories", trick.Slug)
+		case trick.Weight < 1:
+			t.Errorf("trick %s has weight %d", trick.Slug, trick.Weight)
+		}
+		slugs[trick.Slug] = true
+		if trick.Difficulty != nil {
+			lastDifficulty = *trick.Difficulty
+		}
+	}
+
+	if legs := CatalogLegs(); len(legs) != len(stances) {
+		t.Errorf("CatalogLegs() covers %d stances, want %d", len(legs), len(stances))
+	}
+}
+
+func TestCatalogIsFreshEveryCall(t *testing.T) {
+	first := CatalogTricks()
+	*first[0].Difficulty = 99
+	first[1].Name = "Changed"
+
+	if !reflect.DeepEqual(CatalogTricks(), CatalogTricks()) {
+		t.Fatal("CatalogTricks() differs between calls")
+	}
+	second := CatalogTricks()
+	if *second[0].Difficulty == 99 || second[1].Name == "Changed" {
+		t.Error("changing one call's catalog changed the next")
+	}
+}
+
+func TestCatalogTrickPanicsOnUnknownSlug(t *testing.T) {
+	if got := CatalogTrick("cork"); got.Slug != "cork" {
+		t.Errorf("CatalogTrick(cork) = %s", got.Slug)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("CatalogTrick(typo) didn't panic")
+		}
+	}()
+	CatalogTrick("crok")
+}
+
+func TestBuildersAreTemplates(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(t *testing.T)
+	}{
+		{
+			name: "trick",
+			check: func(t *testing.T) {
+				base := Trick().WithAliases("Alias")
+				hard := base.WithDifficulty(9).Build()
+				plain := base.Build()
+				if plain.Difficulty != nil || hard.Difficulty == nil {
+					t.Errorf("WithDifficulty on a copy changed the template: %v / %v", plain.Difficulty, hard.Difficulty)
+				}
+				plain.Aliases[0] = "Changed"
+				if base.Build().Aliases[0] != "Alias" {
+					t.Error("changing a built trick's aliases changed the builder")
+				}
+			},
+		},
+		{
+			name: "video",
+			check: func(t *testing.T) {
+				base := Video().ForTrick(7)
+				featured := base.Featured().Build()
+				if base.Build().IsFeatured || !featured.IsFeatured || featured.TrickID != 7 {
+					t.Errorf("Featured on a copy changed the template: %+v", base.Build())
+				}
+			},
+		},
+		{
+			name: "combo",
+			check: func(t *testing.T) {
+				base := Combo().WithName("Warmup")
+				if base.WithID(5).Build().ID == base.Build().ID {
+					t.Error("WithID on a copy changed the template")
+				}
+				if !reflect.DeepEqual(Combo().Build(), Combo().Build()) {
+					t.Error("Combo() is not deterministic")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.check)
+	}
+}
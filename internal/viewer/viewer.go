@@ -0,0 +1,28 @@
+// =============================================================================
+// FILE: internal/viewer/viewer.go
+// PURPOSE: Carry the caller's role from the HTTP layer to the services
+// =============================================================================
+//
+// middleware.ExtractUserContext stores the BFF-supplied role here, on the
+// request context, so services can decide what a response may show without
+// depending on gin (see models.ResponseView). Routes that don't run that
+// middleware - the public catalog - have no role: callers there are anonymous.
+// =============================================================================
+
+package viewer
+
+import "context"
+
+// roleKey is the context key for the caller's role (unexported so only this package sets it)
+type roleKey struct{}
+
+// WithRole returns a copy of ctx carrying the caller's role
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// Role returns the caller's role, "" if none was set
+func Role(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey{}).(string)
+	return role
+}